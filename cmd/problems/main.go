@@ -0,0 +1,158 @@
+// Command problems imports problem packages into the library of a company
+// and exports library problems as packages.
+//
+//	problems import -company <companyID> <dir|zip>...
+//	problems export -company <companyID> -problem <bankProblemID> [-o file.zip]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/joho/godotenv"
+	"github.com/kw3a/spotted-server/internal/server/blobstore"
+	"github.com/kw3a/spotted-server/internal/server/problempkg"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/kw3a/spotted-server/internal/server/storage"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage:")
+	fmt.Fprintln(os.Stderr, "  problems import -company <companyID> <dir|zip>...")
+	fmt.Fprintln(os.Stderr, "  problems export -company <companyID> -problem <bankProblemID> [-o file.zip]")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	if err := godotenv.Load(".env"); err != nil {
+		log.Println("Error loading.env file. Running without env variables")
+	}
+	dbURL := os.Getenv("DATABASE_URL")
+	if dbURL == "" {
+		log.Fatal("DATABASE_URL environment variable is not set")
+	}
+	var err error
+	switch os.Args[1] {
+	case "import":
+		err = runImport(dbURL, os.Args[2:])
+	case "export":
+		err = runExport(dbURL, os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runImport(dbURL string, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	companyID := fs.String("company", "", "company that owns the library")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := shared.ValidateUUID(*companyID); err != nil || fs.NArg() == 0 {
+		usage()
+	}
+	problems := []shared.Problem{}
+	for _, path := range fs.Args() {
+		read, err := readPackage(path)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		problems = append(problems, read...)
+	}
+	mysql, err := openStorage(dbURL)
+	if err != nil {
+		return err
+	}
+	ids, err := mysql.InsertBankProblems(context.Background(), *companyID, problems)
+	if err != nil {
+		return err
+	}
+	for i, id := range ids {
+		fmt.Printf("%s\t%s\n", id, problems[i].Title)
+	}
+	return nil
+}
+
+// openStorage also opens the blob store that keeps the large test data
+func openStorage(dbURL string) (*storage.MysqlStorage, error) {
+	mysql, err := storage.NewMysqlStorage(dbURL)
+	if err != nil {
+		return nil, err
+	}
+	blobs, err := blobstore.NewFSFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	mysql.Blobs = blobs
+	return mysql, nil
+}
+
+func readPackage(path string) ([]shared.Problem, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return problempkg.Read(os.DirFS(path))
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		return nil, fmt.Errorf("expected a directory or a .zip file")
+	}
+	if info.Size() > problempkg.MaxPackageSize {
+		return nil, fmt.Errorf("package larger than %d MB", problempkg.MaxPackageSize>>20)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return problempkg.ReadZip(data)
+}
+
+func runExport(dbURL string, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	companyID := fs.String("company", "", "company that owns the library")
+	bankProblemID := fs.String("problem", "", "library problem to export")
+	output := fs.String("o", "", "output file, defaults to <name>.zip")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if shared.ValidateUUID(*companyID) != nil || shared.ValidateUUID(*bankProblemID) != nil {
+		usage()
+	}
+	mysql, err := openStorage(dbURL)
+	if err != nil {
+		return err
+	}
+	problem, err := mysql.SelectCompanyBankProblem(context.Background(), *companyID, *bankProblemID)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		*output = problempkg.ShortName(problem.Title) + ".zip"
+	}
+	f, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := problempkg.Write(f, problem); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Println(*output)
+	return nil
+}
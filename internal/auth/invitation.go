@@ -0,0 +1,41 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+)
+
+var errInvitationToken = errors.New("invitation token is not valid")
+
+// Keeps invitation signatures apart from any other use of the secret
+const invitationPurpose = "invitation:"
+
+func (j *JWTAuth) invitationMAC(code string) []byte {
+	mac := hmac.New(sha256.New, []byte(j.secret))
+	mac.Write([]byte(invitationPurpose + code))
+	return mac.Sum(nil)
+}
+
+// SignInvitation returns a token for invitation links, it carries the code
+// so the candidate does not have to type it
+func (j *JWTAuth) SignInvitation(code string) string {
+	return code + "." + base64.RawURLEncoding.EncodeToString(j.invitationMAC(code))
+}
+
+func (j *JWTAuth) VerifyInvitation(token string) (string, error) {
+	code, signature, found := strings.Cut(token, ".")
+	if !found || code == "" {
+		return "", errInvitationToken
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return "", errInvitationToken
+	}
+	if !hmac.Equal(decoded, j.invitationMAC(code)) {
+		return "", errInvitationToken
+	}
+	return code, nil
+}
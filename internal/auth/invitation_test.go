@@ -0,0 +1,33 @@
+package auth
+
+import "testing"
+
+func TestVerifyInvitation(t *testing.T) {
+	j := NewJWTAuth("secret")
+	token := j.SignInvitation("ABCD2345")
+	code, err := j.VerifyInvitation(token)
+	if err != nil {
+		t.Errorf("VerifyInvitation() failed: %v", err)
+	}
+	if code != "ABCD2345" {
+		t.Errorf("expected ABCD2345, got %v", code)
+	}
+}
+
+func TestVerifyInvitationInvalid(t *testing.T) {
+	j := NewJWTAuth("secret")
+	token := j.SignInvitation("ABCD2345")
+	other := NewJWTAuth("other").SignInvitation("ABCD2345")
+	for _, bad := range []string{
+		"",
+		"ABCD2345",
+		"ABCD2345.",
+		"ABCD2345.%%%",
+		"WXYZ2345" + token[len("ABCD2345"):],
+		other,
+	} {
+		if _, err := j.VerifyInvitation(bad); err == nil {
+			t.Errorf("expected error for %q, got nil", bad)
+		}
+	}
+}
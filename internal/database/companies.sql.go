@@ -55,18 +55,25 @@ func (q *Queries) GetCompanies(ctx context.Context, arg GetCompaniesParams) ([]C
 const getCompaniesByQuery = `-- name: GetCompaniesByQuery :many
 SELECT company.id, company.name, company.description, company.website, company.created_at, company.updated_at, company.image_url, company.user_id
 FROM company
-WHERE company.name LIKE CONCAT('%', ?, '%')
+JOIN company_document ON company_document.company_id = company.id
+WHERE MATCH(company_document.body) AGAINST(? IN BOOLEAN MODE)
+ORDER BY MATCH(company_document.body) AGAINST(? IN BOOLEAN MODE) DESC, company.name
 LIMIT ? OFFSET ?
 `
 
 type GetCompaniesByQueryParams struct {
-	CONCAT interface{}
+	Query  string
 	Limit  int32
 	Offset int32
 }
 
 func (q *Queries) GetCompaniesByQuery(ctx context.Context, arg GetCompaniesByQueryParams) ([]Company, error) {
-	rows, err := q.db.QueryContext(ctx, getCompaniesByQuery, arg.CONCAT, arg.Limit, arg.Offset)
+	rows, err := q.db.QueryContext(ctx, getCompaniesByQuery,
+		arg.Query,
+		arg.Query,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -100,7 +107,8 @@ func (q *Queries) GetCompaniesByQuery(ctx context.Context, arg GetCompaniesByQue
 const getCompaniesByUser = `-- name: GetCompaniesByUser :many
 SELECT company.id, company.name, company.description, company.website, company.created_at, company.updated_at, company.image_url, company.user_id
 FROM company
-WHERE company.user_id = ?
+JOIN company_member ON company_member.company_id = company.id
+WHERE company_member.user_id = ?
 LIMIT ? OFFSET ?
 `
 
@@ -145,12 +153,15 @@ func (q *Queries) GetCompaniesByUser(ctx context.Context, arg GetCompaniesByUser
 const getCompaniesByUserAndQuery = `-- name: GetCompaniesByUserAndQuery :many
 SELECT company.id, company.name, company.description, company.website, company.created_at, company.updated_at, company.image_url, company.user_id
 FROM company
-WHERE company.name LIKE CONCAT('%', ?, '%') AND company.user_id = ?
+JOIN company_member ON company_member.company_id = company.id
+JOIN company_document ON company_document.company_id = company.id
+WHERE MATCH(company_document.body) AGAINST(? IN BOOLEAN MODE) AND company_member.user_id = ?
+ORDER BY MATCH(company_document.body) AGAINST(? IN BOOLEAN MODE) DESC, company.name
 LIMIT ? OFFSET ?
 `
 
 type GetCompaniesByUserAndQueryParams struct {
-	CONCAT interface{}
+	Query  string
 	UserID string
 	Limit  int32
 	Offset int32
@@ -158,8 +169,9 @@ type GetCompaniesByUserAndQueryParams struct {
 
 func (q *Queries) GetCompaniesByUserAndQuery(ctx context.Context, arg GetCompaniesByUserAndQueryParams) ([]Company, error) {
 	rows, err := q.db.QueryContext(ctx, getCompaniesByUserAndQuery,
-		arg.CONCAT,
+		arg.Query,
 		arg.UserID,
+		arg.Query,
 		arg.Limit,
 		arg.Offset,
 	)
@@ -245,7 +257,8 @@ func (q *Queries) InsertCompany(ctx context.Context, arg InsertCompanyParams) er
 const selectCompany = `-- name: SelectCompany :one
 SELECT company.id, company.name, company.description, company.website, company.created_at, company.updated_at, company.image_url, company.user_id
 FROM company
-WHERE company.id = ? AND company.user_id = ?
+JOIN company_member ON company_member.company_id = company.id
+WHERE company.id = ? AND company_member.user_id = ? AND company_member.role IN ("owner", "admin")
 `
 
 type SelectCompanyParams struct {
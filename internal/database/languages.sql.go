@@ -44,6 +44,16 @@ func (q *Queries) AllLanguages(ctx context.Context) ([]Language, error) {
 	return items, nil
 }
 
+const deleteLanguageQuizzes = `-- name: DeleteLanguageQuizzes :exec
+DELETE FROM language_quiz
+WHERE quiz_id = ?
+`
+
+func (q *Queries) DeleteLanguageQuizzes(ctx context.Context, quizID string) error {
+	_, err := q.db.ExecContext(ctx, deleteLanguageQuizzes, quizID)
+	return err
+}
+
 const insertLanguageQuiz = `-- name: InsertLanguageQuiz :exec
 INSERT INTO language_quiz
 (id, quiz_id, language_id)
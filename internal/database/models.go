@@ -9,6 +9,13 @@ import (
 	"time"
 )
 
+type BankProblem struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CompanyID string
+}
+
 type Company struct {
 	ID          string
 	Name        string
@@ -20,6 +27,40 @@ type Company struct {
 	UserID      string
 }
 
+type CompanyDocument struct {
+	CompanyID string
+	Body      string
+}
+
+type CompanyInvitation struct {
+	ID         string
+	CreatedAt  time.Time
+	Invitee    string
+	Role       string
+	DeclinedAt sql.NullTime
+	InvitedBy  string
+	CompanyID  string
+}
+
+type CompanyMember struct {
+	CompanyID string
+	UserID    string
+	Role      string
+	CreatedAt time.Time
+}
+
+type CompanyPipeline struct {
+	CompanyID  string
+	ShareStage bool
+}
+
+type CompanyStage struct {
+	CompanyID string
+	Name      string
+	Label     string
+	Position  int32
+}
+
 type Education struct {
 	ID          string
 	CreatedAt   time.Time
@@ -51,6 +92,14 @@ type Experience struct {
 	UserID    string
 }
 
+type IdentityReveal struct {
+	ParticipationID string
+	CreatedAt       time.Time
+	Stage           string
+	Reason          string
+	UserID          string
+}
+
 type Jwt struct {
 	RefreshToken string
 	CreatedAt    time.Time
@@ -81,6 +130,25 @@ type Link struct {
 	UserID    string
 }
 
+type Notification struct {
+	ID            string
+	CreatedAt     time.Time
+	Category      string
+	Title         string
+	Link          string
+	InApp         bool
+	DigestPending bool
+	ReadAt        sql.NullTime
+	UserID        string
+}
+
+type NotificationPreference struct {
+	UserID   string
+	Category string
+	InApp    bool
+	Email    string
+}
+
 type Offer struct {
 	ID           string
 	CreatedAt    time.Time
@@ -89,38 +157,247 @@ type Offer struct {
 	About        string
 	Requirements string
 	Benefits     string
-	Status       int32
+	Status       string
 	MinWage      int32
 	MaxWage      int32
 	CompanyID    string
+	Location     string
+	Modality     string
+	Seniority    string
+	Contract     string
+	Currency     string
+	WagePeriod   string
+}
+
+type OfferBlindReview struct {
+	OfferID     string
+	RevealStage string
+}
+
+type OfferDocument struct {
+	OfferID string
+	Title   string
+	Body    string
+}
+
+type OfferTag struct {
+	OfferID    string
+	LanguageID int32
 }
 
 type Participation struct {
+	ID         string
+	CreatedAt  sql.NullTime
+	UpdatedAt  sql.NullTime
+	ExpiresAt  time.Time
+	UserID     string
+	QuizID     string
+	FinishedAt sql.NullTime
+	EndReason  string
+	PausedAt   sql.NullTime
+}
+
+type ParticipationAdjustment struct {
+	ID              string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Action          string
+	Minutes         int32
+	Reason          string
+	ParticipationID string
+	UserID          string
+}
+
+type ParticipationNote struct {
+	ID              string
+	CreatedAt       time.Time
+	Body            string
+	ParticipationID string
+	UserID          string
+}
+
+type ParticipationProblem struct {
+	ParticipationID string
+	ProblemID       string
+	Position        int32
+}
+
+type ParticipationReview struct {
+	ParticipationID string
+	UpdatedAt       time.Time
+	Stage           string
+	Rating          int32
+	WithdrawnAt     sql.NullTime
+}
+
+type ParticipationStageHistory struct {
+	ID              string
+	CreatedAt       time.Time
+	FromStage       string
+	ToStage         string
+	ParticipationID string
+	UserID          string
+}
+
+type ParticipationTag struct {
+	ParticipationID string
+	Tag             string
+}
+
+type Problem struct {
+	ID            string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Description   string
+	Title         string
+	MemoryLimit   int32
+	TimeLimit     int32
+	BankProblemID string
+	Version       int32
+	Signature     string
+	Difficulty    int32
+	Tag           string
+}
+
+type ProblemChecker struct {
 	ID        string
-	CreatedAt sql.NullTime
-	UpdatedAt sql.NullTime
-	ExpiresAt time.Time
-	UserID    string
+	CreatedAt time.Time
+	Path      string
+	Src       string
+	ProblemID string
+}
+
+type ProblemDatabase struct {
+	ProblemID string
+	CreatedAt time.Time
+	SchemaSql string
+	SeedSql   string
+	Ordered   bool
+}
+
+type ProctoringEvent struct {
+	ID              string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Kind            string
+	Detail          string
+	ParticipationID string
+}
+
+type ProctoringRule struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Kind      string
+	Threshold int32
 	QuizID    string
 }
 
-type Problem struct {
-	ID          string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Description string
-	Title       string
-	MemoryLimit int32
-	TimeLimit   int32
-	QuizID      string
+type Question struct {
+	ProblemID string
+	CreatedAt time.Time
+	Kind      string
+	Points    int32
+	Rubric    string
+	Tolerance float64
+}
+
+type QuestionAnswer struct {
+	ID        string
+	ProblemID string
+	Position  int32
+	Value     string
+}
+
+type QuestionOption struct {
+	ID        string
+	ProblemID string
+	Position  int32
+	Label     string
+	Correct   bool
+}
+
+type QuestionResponse struct {
+	ID              string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	ParticipationID string
+	ProblemID       string
+	Answer          string
+	Score           sql.NullInt32
+	Feedback        string
 }
 
 type Quiz struct {
+	ID         string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Duration   int32
+	OfferID    string
+	OpensAt    sql.NullTime
+	ClosesAt   sql.NullTime
+	InviteOnly bool
+	Shuffle    bool
+}
+
+type QuizInvitation struct {
 	ID        string
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	Duration  int32
-	OfferID   string
+	Email     string
+	Code      string
+	UsedAt    sql.NullTime
+	UserID    sql.NullString
+	QuizID    string
+}
+
+type QuizProblem struct {
+	QuizID    string
+	ProblemID string
+	Position  int32
+}
+
+type QuizPool struct {
+	ID         string
+	QuizID     string
+	Position   int32
+	Tag        string
+	Difficulty int32
+	Draw       int32
+}
+
+type ReferenceResult struct {
+	CreatedAt           time.Time
+	Status              string
+	Time                int32
+	Memory              int32
+	ReferenceSolutionID string
+	TestCaseID          string
+}
+
+type ReferenceSolution struct {
+	ID         string
+	CreatedAt  time.Time
+	Src        string
+	Expected   string
+	LanguageID int32
+	ProblemID  string
+}
+
+type Rejudge struct {
+	ID        string
+	CreatedAt time.Time
+	Scope     string
+	TargetID  string
+	QuizID    string
+	UserID    string
+}
+
+type RejudgeSubmission struct {
+	AcceptedBefore int32
+	Error          string
+	RejudgeID      string
+	SubmissionID   string
 }
 
 type Skill struct {
@@ -158,15 +435,25 @@ type Submission struct {
 	ProblemID         string
 	ParticipationID   string
 	LanguageID        int32
+	RejudgedProblemID sql.NullString
+}
+
+type SubmissionJudgedEvent struct {
+	SubmissionID string
+	CreatedAt    time.Time
 }
 
 type TestCase struct {
-	ID        string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Input     string
-	Output    string
-	ProblemID string
+	ID         string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Input      string
+	Output     string
+	ProblemID  string
+	InputHash  string
+	OutputHash string
+	InputSize  int32
+	OutputSize int32
 }
 
 type TestCaseResult struct {
@@ -193,3 +480,26 @@ type User struct {
 	ImageUrl    string
 	Number      string
 }
+
+type Webhook struct {
+	ID        string
+	CreatedAt time.Time
+	Url       string
+	Secret    string
+	Events    string
+	CompanyID string
+}
+
+type WebhookDelivery struct {
+	ID            string
+	CreatedAt     time.Time
+	Event         string
+	Payload       string
+	Status        string
+	Attempts      int32
+	NextAttemptAt time.Time
+	ResponseCode  int32
+	LastError     string
+	DeliveredAt   sql.NullTime
+	WebhookID     string
+}
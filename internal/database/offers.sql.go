@@ -10,25 +10,8 @@ import (
 	"time"
 )
 
-const archiveOffer = `-- name: ArchiveOffer :exec
-UPDATE offer
-JOIN company ON offer.company_id = company.id
-SET offer.status = -1
-WHERE offer.id = ? AND company.user_id = ?
-`
-
-type ArchiveOfferParams struct {
-	ID     string
-	UserID string
-}
-
-func (q *Queries) ArchiveOffer(ctx context.Context, arg ArchiveOfferParams) error {
-	_, err := q.db.ExecContext(ctx, archiveOffer, arg.ID, arg.UserID)
-	return err
-}
-
 const getOffer = `-- name: GetOffer :one
-SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id, company.name as company_name, company.image_url as company_image_url
+SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id, offer.location, offer.modality, offer.seniority, offer.contract, offer.currency, offer.wage_period, company.name as company_name, company.image_url as company_image_url
 FROM offer
 JOIN company ON offer.company_id = company.id
 WHERE offer.id = ?
@@ -43,10 +26,16 @@ type GetOfferRow struct {
 	About           string
 	Requirements    string
 	Benefits        string
-	Status          int32
+	Status          string
 	MinWage         int32
 	MaxWage         int32
 	CompanyID       string
+	Location        string
+	Modality        string
+	Seniority       string
+	Contract        string
+	Currency        string
+	WagePeriod      string
 	CompanyName     string
 	CompanyImageUrl string
 }
@@ -66,6 +55,12 @@ func (q *Queries) GetOffer(ctx context.Context, id string) (GetOfferRow, error)
 		&i.MinWage,
 		&i.MaxWage,
 		&i.CompanyID,
+		&i.Location,
+		&i.Modality,
+		&i.Seniority,
+		&i.Contract,
+		&i.Currency,
+		&i.WagePeriod,
 		&i.CompanyName,
 		&i.CompanyImageUrl,
 	)
@@ -73,7 +68,7 @@ func (q *Queries) GetOffer(ctx context.Context, id string) (GetOfferRow, error)
 }
 
 const getOfferByQuiz = `-- name: GetOfferByQuiz :one
-SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id
+SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id, offer.location, offer.modality, offer.seniority, offer.contract, offer.currency, offer.wage_period
 FROM offer
 JOIN quiz ON offer.id = quiz.offer_id
 WHERE quiz.id = ?
@@ -95,15 +90,23 @@ func (q *Queries) GetOfferByQuiz(ctx context.Context, id string) (Offer, error)
 		&i.MinWage,
 		&i.MaxWage,
 		&i.CompanyID,
+		&i.Location,
+		&i.Modality,
+		&i.Seniority,
+		&i.Contract,
+		&i.Currency,
+		&i.WagePeriod,
 	)
 	return i, err
 }
 
 const getOfferByUser = `-- name: GetOfferByUser :one
-SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id, company.name as company_name, company.image_url as company_image_url
+SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id, offer.location, offer.modality, offer.seniority, offer.contract, offer.currency, offer.wage_period, company.name as company_name, company.image_url as company_image_url,
+  company_member.role AS member_role
 FROM offer
 JOIN company ON offer.company_id = company.id
-JOIN user ON company.user_id = user.id
+JOIN company_member ON company_member.company_id = company.id
+JOIN user ON company_member.user_id = user.id
 WHERE offer.id = ? AND user.id = ?
 LIMIT 1
 `
@@ -121,12 +124,19 @@ type GetOfferByUserRow struct {
 	About           string
 	Requirements    string
 	Benefits        string
-	Status          int32
+	Status          string
 	MinWage         int32
 	MaxWage         int32
 	CompanyID       string
+	Location        string
+	Modality        string
+	Seniority       string
+	Contract        string
+	Currency        string
+	WagePeriod      string
 	CompanyName     string
 	CompanyImageUrl string
+	MemberRole      string
 }
 
 func (q *Queries) GetOfferByUser(ctx context.Context, arg GetOfferByUserParams) (GetOfferByUserRow, error) {
@@ -144,84 +154,38 @@ func (q *Queries) GetOfferByUser(ctx context.Context, arg GetOfferByUserParams)
 		&i.MinWage,
 		&i.MaxWage,
 		&i.CompanyID,
+		&i.Location,
+		&i.Modality,
+		&i.Seniority,
+		&i.Contract,
+		&i.Currency,
+		&i.WagePeriod,
 		&i.CompanyName,
 		&i.CompanyImageUrl,
+		&i.MemberRole,
 	)
 	return i, err
 }
 
-const getOffers = `-- name: GetOffers :many
-SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id, company.name as company_name, company.image_url as company_image_url
+const getOfferStatusByQuiz = `-- name: GetOfferStatusByQuiz :one
+SELECT offer.status
 FROM offer
-JOIN company ON offer.company_id= company.id
-WHERE offer.status = 1
-ORDER BY offer.created_at DESC
-LIMIT ? OFFSET ?
+JOIN quiz ON offer.id = quiz.offer_id
+WHERE quiz.id = ?
 `
 
-type GetOffersParams struct {
-	Limit  int32
-	Offset int32
-}
-
-type GetOffersRow struct {
-	ID              string
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
-	Title           string
-	About           string
-	Requirements    string
-	Benefits        string
-	Status          int32
-	MinWage         int32
-	MaxWage         int32
-	CompanyID       string
-	CompanyName     string
-	CompanyImageUrl string
-}
-
-func (q *Queries) GetOffers(ctx context.Context, arg GetOffersParams) ([]GetOffersRow, error) {
-	rows, err := q.db.QueryContext(ctx, getOffers, arg.Limit, arg.Offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetOffersRow
-	for rows.Next() {
-		var i GetOffersRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Title,
-			&i.About,
-			&i.Requirements,
-			&i.Benefits,
-			&i.Status,
-			&i.MinWage,
-			&i.MaxWage,
-			&i.CompanyID,
-			&i.CompanyName,
-			&i.CompanyImageUrl,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+func (q *Queries) GetOfferStatusByQuiz(ctx context.Context, id string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getOfferStatusByQuiz, id)
+	var status string
+	err := row.Scan(&status)
+	return status, err
 }
 
 const getOffersByCompany = `-- name: GetOffersByCompany :many
-SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id, company.name as company_name, company.image_url as company_image_url
+SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id, offer.location, offer.modality, offer.seniority, offer.contract, offer.currency, offer.wage_period, company.name as company_name, company.image_url as company_image_url
 FROM offer
 JOIN company ON offer.company_id = company.id
-WHERE company.id = ? 
+WHERE company.id = ? AND offer.status != "draft"
 ORDER BY offer.created_at DESC
 LIMIT ? OFFSET ?
 `
@@ -240,10 +204,16 @@ type GetOffersByCompanyRow struct {
 	About           string
 	Requirements    string
 	Benefits        string
-	Status          int32
+	Status          string
 	MinWage         int32
 	MaxWage         int32
 	CompanyID       string
+	Location        string
+	Modality        string
+	Seniority       string
+	Contract        string
+	Currency        string
+	WagePeriod      string
 	CompanyName     string
 	CompanyImageUrl string
 }
@@ -269,74 +239,12 @@ func (q *Queries) GetOffersByCompany(ctx context.Context, arg GetOffersByCompany
 			&i.MinWage,
 			&i.MaxWage,
 			&i.CompanyID,
-			&i.CompanyName,
-			&i.CompanyImageUrl,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
-}
-
-const getOffersByQuery = `-- name: GetOffersByQuery :many
-SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id, company.name as company_name, company.image_url as company_image_url
-FROM offer
-JOIN company ON offer.company_id = company.id
-WHERE offer.title LIKE CONCAT('%', ?, '%') AND offer.status = 1
-ORDER BY offer.created_at DESC
-LIMIT ? OFFSET ?
-`
-
-type GetOffersByQueryParams struct {
-	CONCAT interface{}
-	Limit  int32
-	Offset int32
-}
-
-type GetOffersByQueryRow struct {
-	ID              string
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
-	Title           string
-	About           string
-	Requirements    string
-	Benefits        string
-	Status          int32
-	MinWage         int32
-	MaxWage         int32
-	CompanyID       string
-	CompanyName     string
-	CompanyImageUrl string
-}
-
-func (q *Queries) GetOffersByQuery(ctx context.Context, arg GetOffersByQueryParams) ([]GetOffersByQueryRow, error) {
-	rows, err := q.db.QueryContext(ctx, getOffersByQuery, arg.CONCAT, arg.Limit, arg.Offset)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetOffersByQueryRow
-	for rows.Next() {
-		var i GetOffersByQueryRow
-		if err := rows.Scan(
-			&i.ID,
-			&i.CreatedAt,
-			&i.UpdatedAt,
-			&i.Title,
-			&i.About,
-			&i.Requirements,
-			&i.Benefits,
-			&i.Status,
-			&i.MinWage,
-			&i.MaxWage,
-			&i.CompanyID,
+			&i.Location,
+			&i.Modality,
+			&i.Seniority,
+			&i.Contract,
+			&i.Currency,
+			&i.WagePeriod,
 			&i.CompanyName,
 			&i.CompanyImageUrl,
 		); err != nil {
@@ -354,10 +262,12 @@ func (q *Queries) GetOffersByQuery(ctx context.Context, arg GetOffersByQueryPara
 }
 
 const getOffersByUser = `-- name: GetOffersByUser :many
-SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id, company.name as company_name, company.image_url as company_image_url
+SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id, offer.location, offer.modality, offer.seniority, offer.contract, offer.currency, offer.wage_period, company.name as company_name, company.image_url as company_image_url,
+  company_member.role AS member_role
 FROM offer
 JOIN company ON offer.company_id = company.id
-JOIN user ON company.user_id = user.id
+JOIN company_member ON company_member.company_id = company.id
+JOIN user ON company_member.user_id = user.id
 WHERE user.id = ? 
 ORDER BY offer.created_at DESC
 LIMIT ? OFFSET ?
@@ -377,12 +287,19 @@ type GetOffersByUserRow struct {
 	About           string
 	Requirements    string
 	Benefits        string
-	Status          int32
+	Status          string
 	MinWage         int32
 	MaxWage         int32
 	CompanyID       string
+	Location        string
+	Modality        string
+	Seniority       string
+	Contract        string
+	Currency        string
+	WagePeriod      string
 	CompanyName     string
 	CompanyImageUrl string
+	MemberRole      string
 }
 
 func (q *Queries) GetOffersByUser(ctx context.Context, arg GetOffersByUserParams) ([]GetOffersByUserRow, error) {
@@ -406,8 +323,15 @@ func (q *Queries) GetOffersByUser(ctx context.Context, arg GetOffersByUserParams
 			&i.MinWage,
 			&i.MaxWage,
 			&i.CompanyID,
+			&i.Location,
+			&i.Modality,
+			&i.Seniority,
+			&i.Contract,
+			&i.Currency,
+			&i.WagePeriod,
 			&i.CompanyName,
 			&i.CompanyImageUrl,
+			&i.MemberRole,
 		); err != nil {
 			return nil, err
 		}
@@ -423,7 +347,7 @@ func (q *Queries) GetOffersByUser(ctx context.Context, arg GetOffersByUserParams
 }
 
 const getParticipatedOffers = `-- name: GetParticipatedOffers :many
-SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id, company.name as company_name, company.image_url as company_image_url
+SELECT offer.id, offer.created_at, offer.updated_at, offer.title, offer.about, offer.requirements, offer.benefits, offer.status, offer.min_wage, offer.max_wage, offer.company_id, offer.location, offer.modality, offer.seniority, offer.contract, offer.currency, offer.wage_period, company.name as company_name, company.image_url as company_image_url
 FROM offer
 JOIN company ON offer.company_id = company.id
 JOIN quiz ON offer.id = quiz.offer_id
@@ -447,10 +371,16 @@ type GetParticipatedOffersRow struct {
 	About           string
 	Requirements    string
 	Benefits        string
-	Status          int32
+	Status          string
 	MinWage         int32
 	MaxWage         int32
 	CompanyID       string
+	Location        string
+	Modality        string
+	Seniority       string
+	Contract        string
+	Currency        string
+	WagePeriod      string
 	CompanyName     string
 	CompanyImageUrl string
 }
@@ -476,6 +406,12 @@ func (q *Queries) GetParticipatedOffers(ctx context.Context, arg GetParticipated
 			&i.MinWage,
 			&i.MaxWage,
 			&i.CompanyID,
+			&i.Location,
+			&i.Modality,
+			&i.Seniority,
+			&i.Contract,
+			&i.Currency,
+			&i.WagePeriod,
 			&i.CompanyName,
 			&i.CompanyImageUrl,
 		); err != nil {
@@ -494,8 +430,9 @@ func (q *Queries) GetParticipatedOffers(ctx context.Context, arg GetParticipated
 
 const insertOffer = `-- name: InsertOffer :exec
 INSERT INTO offer
-(id, title, about, requirements, benefits, min_wage, max_wage, company_id)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+(id, title, about, requirements, benefits, min_wage, max_wage, company_id, status,
+location, modality, seniority, contract, currency, wage_period)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type InsertOfferParams struct {
@@ -507,6 +444,13 @@ type InsertOfferParams struct {
 	MinWage      int32
 	MaxWage      int32
 	CompanyID    string
+	Status       string
+	Location     string
+	Modality     string
+	Seniority    string
+	Contract     string
+	Currency     string
+	WagePeriod   string
 }
 
 func (q *Queries) InsertOffer(ctx context.Context, arg InsertOfferParams) error {
@@ -519,6 +463,97 @@ func (q *Queries) InsertOffer(ctx context.Context, arg InsertOfferParams) error
 		arg.MinWage,
 		arg.MaxWage,
 		arg.CompanyID,
+		arg.Status,
+		arg.Location,
+		arg.Modality,
+		arg.Seniority,
+		arg.Contract,
+		arg.Currency,
+		arg.WagePeriod,
 	)
 	return err
 }
+
+const selectOfferStatusByUser = `-- name: SelectOfferStatusByUser :one
+SELECT offer.status, offer.company_id
+FROM offer
+JOIN company ON offer.company_id = company.id
+JOIN company_member ON company_member.company_id = company.id
+WHERE offer.id = ? AND company_member.user_id = ? AND company_member.role IN ("owner", "admin", "recruiter")
+FOR UPDATE
+`
+
+type SelectOfferStatusByUserParams struct {
+	ID     string
+	UserID string
+}
+
+type SelectOfferStatusByUserRow struct {
+	Status    string
+	CompanyID string
+}
+
+func (q *Queries) SelectOfferStatusByUser(ctx context.Context, arg SelectOfferStatusByUserParams) (SelectOfferStatusByUserRow, error) {
+	row := q.db.QueryRowContext(ctx, selectOfferStatusByUser, arg.ID, arg.UserID)
+	var i SelectOfferStatusByUserRow
+	err := row.Scan(&i.Status, &i.CompanyID)
+	return i, err
+}
+
+const updateOffer = `-- name: UpdateOffer :exec
+UPDATE offer
+SET title = ?, about = ?, requirements = ?, benefits = ?, min_wage = ?, max_wage = ?,
+    location = ?, modality = ?, seniority = ?, contract = ?, currency = ?, wage_period = ?
+WHERE id = ?
+`
+
+type UpdateOfferParams struct {
+	Title        string
+	About        string
+	Requirements string
+	Benefits     string
+	MinWage      int32
+	MaxWage      int32
+	Location     string
+	Modality     string
+	Seniority    string
+	Contract     string
+	Currency     string
+	WagePeriod   string
+	ID           string
+}
+
+func (q *Queries) UpdateOffer(ctx context.Context, arg UpdateOfferParams) error {
+	_, err := q.db.ExecContext(ctx, updateOffer,
+		arg.Title,
+		arg.About,
+		arg.Requirements,
+		arg.Benefits,
+		arg.MinWage,
+		arg.MaxWage,
+		arg.Location,
+		arg.Modality,
+		arg.Seniority,
+		arg.Contract,
+		arg.Currency,
+		arg.WagePeriod,
+		arg.ID,
+	)
+	return err
+}
+
+const updateOfferStatus = `-- name: UpdateOfferStatus :exec
+UPDATE offer
+SET status = ?
+WHERE id = ?
+`
+
+type UpdateOfferStatusParams struct {
+	Status string
+	ID     string
+}
+
+func (q *Queries) UpdateOfferStatus(ctx context.Context, arg UpdateOfferStatusParams) error {
+	_, err := q.db.ExecContext(ctx, updateOfferStatus, arg.Status, arg.ID)
+	return err
+}
@@ -11,20 +11,88 @@ import (
 	"time"
 )
 
-const endParticipation = `-- name: EndParticipation :exec
+const countParticipations = `-- name: CountParticipations :one
+SELECT COUNT(*) AS count
+FROM participation
+WHERE participation.quiz_id = ?
+`
+
+func (q *Queries) CountParticipations(ctx context.Context, quizID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countParticipations, quizID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const endParticipation = `-- name: EndParticipation :execrows
 UPDATE participation
-SET expires_at = ?
-WHERE participation.user_id = ? AND participation.quiz_id = ?
+SET expires_at = ?, finished_at = ?, end_reason = ?
+WHERE participation.user_id = ? AND participation.quiz_id = ? AND participation.finished_at IS NULL
 `
 
 type EndParticipationParams struct {
-	ExpiresAt time.Time
-	UserID    string
-	QuizID    string
+	ExpiresAt  time.Time
+	FinishedAt sql.NullTime
+	EndReason  string
+	UserID     string
+	QuizID     string
+}
+
+func (q *Queries) EndParticipation(ctx context.Context, arg EndParticipationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, endParticipation,
+		arg.ExpiresAt,
+		arg.FinishedAt,
+		arg.EndReason,
+		arg.UserID,
+		arg.QuizID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const finishExpiredParticipation = `-- name: FinishExpiredParticipation :execrows
+UPDATE participation
+SET finished_at = ?, end_reason = ?
+WHERE participation.id = ? AND participation.finished_at IS NULL
+  AND participation.expires_at <= ? AND participation.paused_at IS NULL
+`
+
+type FinishExpiredParticipationParams struct {
+	FinishedAt sql.NullTime
+	EndReason  string
+	ID         string
+	ExpiresAt  time.Time
 }
 
-func (q *Queries) EndParticipation(ctx context.Context, arg EndParticipationParams) error {
-	_, err := q.db.ExecContext(ctx, endParticipation, arg.ExpiresAt, arg.UserID, arg.QuizID)
+func (q *Queries) FinishExpiredParticipation(ctx context.Context, arg FinishExpiredParticipationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, finishExpiredParticipation,
+		arg.FinishedAt,
+		arg.EndReason,
+		arg.ID,
+		arg.ExpiresAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const finishParticipation = `-- name: FinishParticipation :exec
+UPDATE participation
+SET finished_at = ?, end_reason = ?
+WHERE participation.id = ? AND participation.finished_at IS NULL
+`
+
+type FinishParticipationParams struct {
+	FinishedAt sql.NullTime
+	EndReason  string
+	ID         string
+}
+
+func (q *Queries) FinishParticipation(ctx context.Context, arg FinishParticipationParams) error {
+	_, err := q.db.ExecContext(ctx, finishParticipation, arg.FinishedAt, arg.EndReason, arg.ID)
 	return err
 }
 
@@ -45,7 +113,7 @@ func (q *Queries) Participate(ctx context.Context, arg ParticipateParams) error
 }
 
 const participationStatus = `-- name: ParticipationStatus :one
-SELECT participation.id, participation.created_at, participation.updated_at, participation.expires_at, participation.user_id, participation.quiz_id
+SELECT participation.id, participation.created_at, participation.updated_at, participation.expires_at, participation.user_id, participation.quiz_id, participation.finished_at, participation.end_reason, participation.paused_at
 FROM participation
 WHERE participation.user_id = ? AND participation.quiz_id = ?
 `
@@ -65,13 +133,17 @@ func (q *Queries) ParticipationStatus(ctx context.Context, arg ParticipationStat
 		&i.ExpiresAt,
 		&i.UserID,
 		&i.QuizID,
+		&i.FinishedAt,
+		&i.EndReason,
+		&i.PausedAt,
 	)
 	return i, err
 }
 
 const selectApplications = `-- name: SelectApplications :many
 SELECT user.id, user.created_at, user.updated_at, user.nick, user.password, user.name, user.email, user.description, user.image_url, user.number, participation.id as participation_id, participation.created_at as participation_created_at, 
-  participation.expires_at as participation_expires_at
+  participation.expires_at as participation_expires_at, participation.end_reason as participation_end_reason,
+  participation.paused_at as participation_paused_at
 FROM user
 JOIN participation ON user.id = participation.user_id
 WHERE participation.quiz_id = ?
@@ -92,6 +164,8 @@ type SelectApplicationsRow struct {
 	ParticipationID        string
 	ParticipationCreatedAt sql.NullTime
 	ParticipationExpiresAt time.Time
+	ParticipationEndReason string
+	ParticipationPausedAt  sql.NullTime
 }
 
 func (q *Queries) SelectApplications(ctx context.Context, quizID string) ([]SelectApplicationsRow, error) {
@@ -117,6 +191,8 @@ func (q *Queries) SelectApplications(ctx context.Context, quizID string) ([]Sele
 			&i.ParticipationID,
 			&i.ParticipationCreatedAt,
 			&i.ParticipationExpiresAt,
+			&i.ParticipationEndReason,
+			&i.ParticipationPausedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -130,3 +206,99 @@ func (q *Queries) SelectApplications(ctx context.Context, quizID string) ([]Sele
 	}
 	return items, nil
 }
+
+const selectExpiredParticipations = `-- name: SelectExpiredParticipations :many
+SELECT participation.id, participation.expires_at, COUNT(submission.id) AS submissions
+FROM participation
+LEFT JOIN submission ON submission.participation_id = participation.id
+WHERE participation.finished_at IS NULL AND participation.paused_at IS NULL
+  AND participation.expires_at <= ?
+GROUP BY participation.id
+`
+
+type SelectExpiredParticipationsRow struct {
+	ID          string
+	ExpiresAt   time.Time
+	Submissions int64
+}
+
+func (q *Queries) SelectExpiredParticipations(ctx context.Context, expiresAt time.Time) ([]SelectExpiredParticipationsRow, error) {
+	rows, err := q.db.QueryContext(ctx, selectExpiredParticipations, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SelectExpiredParticipationsRow
+	for rows.Next() {
+		var i SelectExpiredParticipationsRow
+		if err := rows.Scan(&i.ID, &i.ExpiresAt, &i.Submissions); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectParticipationByRecruiter = `-- name: SelectParticipationByRecruiter :one
+SELECT participation.id, participation.created_at, participation.updated_at, participation.expires_at, participation.user_id, participation.quiz_id, participation.finished_at, participation.end_reason, participation.paused_at
+FROM participation
+JOIN quiz ON participation.quiz_id = quiz.id
+JOIN offer ON quiz.offer_id = offer.id
+JOIN company ON offer.company_id = company.id
+JOIN company_member ON company_member.company_id = company.id
+WHERE participation.id = ? AND company_member.user_id = ? AND company_member.role IN ("owner", "admin", "recruiter")
+FOR UPDATE
+`
+
+type SelectParticipationByRecruiterParams struct {
+	ID     string
+	UserID string
+}
+
+func (q *Queries) SelectParticipationByRecruiter(ctx context.Context, arg SelectParticipationByRecruiterParams) (Participation, error) {
+	row := q.db.QueryRowContext(ctx, selectParticipationByRecruiter, arg.ID, arg.UserID)
+	var i Participation
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ExpiresAt,
+		&i.UserID,
+		&i.QuizID,
+		&i.FinishedAt,
+		&i.EndReason,
+		&i.PausedAt,
+	)
+	return i, err
+}
+
+const updateParticipationTiming = `-- name: UpdateParticipationTiming :exec
+UPDATE participation
+SET expires_at = ?, paused_at = ?, finished_at = ?, end_reason = ?
+WHERE participation.id = ?
+`
+
+type UpdateParticipationTimingParams struct {
+	ExpiresAt  time.Time
+	PausedAt   sql.NullTime
+	FinishedAt sql.NullTime
+	EndReason  string
+	ID         string
+}
+
+func (q *Queries) UpdateParticipationTiming(ctx context.Context, arg UpdateParticipationTimingParams) error {
+	_, err := q.db.ExecContext(ctx, updateParticipationTiming,
+		arg.ExpiresAt,
+		arg.PausedAt,
+		arg.FinishedAt,
+		arg.EndReason,
+		arg.ID,
+	)
+	return err
+}
@@ -11,33 +11,42 @@ import (
 
 const insertProblem = `-- name: InsertProblem :exec
 INSERT INTO problem
-(id, quiz_id, title, description, memory_limit, time_limit)
-VALUES (?, ?, ?, ?, ?, ?)
+(id, bank_problem_id, version, title, description, memory_limit, time_limit, signature, difficulty, tag)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type InsertProblemParams struct {
-	ID          string
-	QuizID      string
-	Title       string
-	Description string
-	MemoryLimit int32
-	TimeLimit   int32
+	ID            string
+	BankProblemID string
+	Version       int32
+	Title         string
+	Description   string
+	MemoryLimit   int32
+	TimeLimit     int32
+	Signature     string
+	Difficulty    int32
+	Tag           string
 }
 
 func (q *Queries) InsertProblem(ctx context.Context, arg InsertProblemParams) error {
 	_, err := q.db.ExecContext(ctx, insertProblem,
 		arg.ID,
-		arg.QuizID,
+		arg.BankProblemID,
+		arg.Version,
 		arg.Title,
 		arg.Description,
 		arg.MemoryLimit,
 		arg.TimeLimit,
+		arg.Signature,
+		arg.Difficulty,
+		arg.Tag,
 	)
 	return err
 }
 
 const selectProblem = `-- name: SelectProblem :one
-SELECT problem.id, problem.created_at, problem.updated_at, problem.description, problem.title, problem.memory_limit, problem.time_limit, problem.quiz_id
+
+SELECT problem.id, problem.created_at, problem.updated_at, problem.description, problem.title, problem.memory_limit, problem.time_limit, problem.bank_problem_id, problem.version, problem.signature, problem.difficulty, problem.tag
 FROM problem
 WHERE problem.id = ?
 `
@@ -53,21 +62,25 @@ func (q *Queries) SelectProblem(ctx context.Context, id string) (Problem, error)
 		&i.Title,
 		&i.MemoryLimit,
 		&i.TimeLimit,
-		&i.QuizID,
+		&i.BankProblemID,
+		&i.Version,
+		&i.Signature,
+		&i.Difficulty,
+		&i.Tag,
 	)
 	return i, err
 }
 
 const selectProblemIDs = `-- name: SelectProblemIDs :many
-
 SELECT problem.id
 FROM problem
-INNER JOIN quiz ON problem.quiz_id = quiz.id
-WHERE quiz.id = ?
+INNER JOIN quiz_problem ON problem.id = quiz_problem.problem_id
+WHERE quiz_problem.quiz_id = ?
+ORDER BY quiz_problem.position
 `
 
-func (q *Queries) SelectProblemIDs(ctx context.Context, id string) ([]string, error) {
-	rows, err := q.db.QueryContext(ctx, selectProblemIDs, id)
+func (q *Queries) SelectProblemIDs(ctx context.Context, quizID string) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, selectProblemIDs, quizID)
 	if err != nil {
 		return nil, err
 	}
@@ -90,14 +103,15 @@ func (q *Queries) SelectProblemIDs(ctx context.Context, id string) ([]string, er
 }
 
 const selectProblems = `-- name: SelectProblems :many
-SELECT problem.id, problem.created_at, problem.updated_at, problem.description, problem.title, problem.memory_limit, problem.time_limit, problem.quiz_id
+SELECT problem.id, problem.created_at, problem.updated_at, problem.description, problem.title, problem.memory_limit, problem.time_limit, problem.bank_problem_id, problem.version, problem.signature, problem.difficulty, problem.tag
 FROM problem
-INNER JOIN quiz ON problem.quiz_id = quiz.id
-WHERE quiz.id = ?
+INNER JOIN quiz_problem ON problem.id = quiz_problem.problem_id
+WHERE quiz_problem.quiz_id = ?
+ORDER BY quiz_problem.position
 `
 
-func (q *Queries) SelectProblems(ctx context.Context, id string) ([]Problem, error) {
-	rows, err := q.db.QueryContext(ctx, selectProblems, id)
+func (q *Queries) SelectProblems(ctx context.Context, quizID string) ([]Problem, error) {
+	rows, err := q.db.QueryContext(ctx, selectProblems, quizID)
 	if err != nil {
 		return nil, err
 	}
@@ -113,7 +127,11 @@ func (q *Queries) SelectProblems(ctx context.Context, id string) ([]Problem, err
 			&i.Title,
 			&i.MemoryLimit,
 			&i.TimeLimit,
-			&i.QuizID,
+			&i.BankProblemID,
+			&i.Version,
+			&i.Signature,
+			&i.Difficulty,
+			&i.Tag,
 		); err != nil {
 			return nil, err
 		}
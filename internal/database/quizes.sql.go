@@ -7,10 +7,34 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
+const getQuiz = `-- name: GetQuiz :one
+SELECT quiz.id, quiz.created_at, quiz.updated_at, quiz.duration, quiz.offer_id, quiz.opens_at, quiz.closes_at, quiz.invite_only, quiz.shuffle
+FROM quiz
+WHERE quiz.id = ?
+`
+
+func (q *Queries) GetQuiz(ctx context.Context, id string) (Quiz, error) {
+	row := q.db.QueryRowContext(ctx, getQuiz, id)
+	var i Quiz
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Duration,
+		&i.OfferID,
+		&i.OpensAt,
+		&i.ClosesAt,
+		&i.InviteOnly,
+		&i.Shuffle,
+	)
+	return i, err
+}
+
 const getQuizByOffer = `-- name: GetQuizByOffer :one
-SELECT quiz.id, quiz.created_at, quiz.updated_at, quiz.duration, quiz.offer_id
+SELECT quiz.id, quiz.created_at, quiz.updated_at, quiz.duration, quiz.offer_id, quiz.opens_at, quiz.closes_at, quiz.invite_only, quiz.shuffle
 FROM quiz
 WHERE quiz.offer_id = ?
 `
@@ -24,22 +48,95 @@ func (q *Queries) GetQuizByOffer(ctx context.Context, offerID string) (Quiz, err
 		&i.UpdatedAt,
 		&i.Duration,
 		&i.OfferID,
+		&i.OpensAt,
+		&i.ClosesAt,
+		&i.InviteOnly,
+		&i.Shuffle,
 	)
 	return i, err
 }
 
 const insertQuiz = `-- name: InsertQuiz :exec
-INSERT INTO quiz (id, duration, offer_id)
-VALUES (?, ?, ?)
+INSERT INTO quiz (id, duration, offer_id, shuffle)
+VALUES (?, ?, ?, ?)
 `
 
 type InsertQuizParams struct {
 	ID       string
 	Duration int32
 	OfferID  string
+	Shuffle  bool
 }
 
 func (q *Queries) InsertQuiz(ctx context.Context, arg InsertQuizParams) error {
-	_, err := q.db.ExecContext(ctx, insertQuiz, arg.ID, arg.Duration, arg.OfferID)
+	_, err := q.db.ExecContext(ctx, insertQuiz,
+		arg.ID,
+		arg.Duration,
+		arg.OfferID,
+		arg.Shuffle,
+	)
+	return err
+}
+
+const lockQuizByOffer = `-- name: LockQuizByOffer :one
+SELECT quiz.id, quiz.created_at, quiz.updated_at, quiz.duration, quiz.offer_id, quiz.opens_at, quiz.closes_at, quiz.invite_only, quiz.shuffle
+FROM quiz
+WHERE quiz.offer_id = ?
+FOR UPDATE
+`
+
+func (q *Queries) LockQuizByOffer(ctx context.Context, offerID string) (Quiz, error) {
+	row := q.db.QueryRowContext(ctx, lockQuizByOffer, offerID)
+	var i Quiz
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Duration,
+		&i.OfferID,
+		&i.OpensAt,
+		&i.ClosesAt,
+		&i.InviteOnly,
+		&i.Shuffle,
+	)
+	return i, err
+}
+
+const updateQuizAccess = `-- name: UpdateQuizAccess :exec
+UPDATE quiz
+SET opens_at = ?, closes_at = ?, invite_only = ?
+WHERE quiz.id = ?
+`
+
+type UpdateQuizAccessParams struct {
+	OpensAt    sql.NullTime
+	ClosesAt   sql.NullTime
+	InviteOnly bool
+	ID         string
+}
+
+func (q *Queries) UpdateQuizAccess(ctx context.Context, arg UpdateQuizAccessParams) error {
+	_, err := q.db.ExecContext(ctx, updateQuizAccess,
+		arg.OpensAt,
+		arg.ClosesAt,
+		arg.InviteOnly,
+		arg.ID,
+	)
+	return err
+}
+
+const updateQuizDuration = `-- name: UpdateQuizDuration :exec
+UPDATE quiz
+SET duration = ?
+WHERE quiz.id = ?
+`
+
+type UpdateQuizDurationParams struct {
+	Duration int32
+	ID       string
+}
+
+func (q *Queries) UpdateQuizDuration(ctx context.Context, arg UpdateQuizDurationParams) error {
+	_, err := q.db.ExecContext(ctx, updateQuizDuration, arg.Duration, arg.ID)
 	return err
 }
@@ -15,19 +15,21 @@ const batchBestSubmissionsFromParticipation = `-- name: BatchBestSubmissionsFrom
 SELECT id, created_at, updated_at, src, accepted_test_cases, problem_id, participation_id, language_id, title, display_name, total_test_cases, rk
 FROM (
     SELECT 
-        s.id, s.created_at, s.updated_at, s.src, s.accepted_test_cases, s.problem_id, s.participation_id, s.language_id, 
+        s.id, s.created_at, s.updated_at, s.src, s.accepted_test_cases, s.problem_id, s.participation_id, s.language_id, s.rejudged_problem_id, 
         problem.title, 
         language.display_name, 
         COUNT(DISTINCT test_case.id) AS total_test_cases,
         ROW_NUMBER() OVER (
-            PARTITION BY s.participation_id, s.problem_id 
+            PARTITION BY s.participation_id, COALESCE(s.rejudged_problem_id, s.problem_id) 
             ORDER BY s.accepted_test_cases DESC, s.created_at ASC
         ) AS rk
     FROM submission s
     JOIN language ON s.language_id = language.id
-    JOIN problem ON s.problem_id = problem.id
+    JOIN problem ON COALESCE(s.rejudged_problem_id, s.problem_id) = problem.id
+    JOIN participation ON s.participation_id = participation.id
     LEFT JOIN test_case ON problem.id = test_case.problem_id
     WHERE s.participation_id IN (/*SLICE:participation_ids*/?)
+      AND s.created_at <= participation.expires_at
     GROUP BY s.id  
 ) ranked
 WHERE ranked.rk = 1
@@ -42,6 +44,7 @@ type BatchBestSubmissionsFromParticipationRow struct {
 	ProblemID         string
 	ParticipationID   string
 	LanguageID        int32
+	RejudgedProblemID sql.NullString
 	Title             string
 	DisplayName       string
 	TotalTestCases    int64
@@ -76,6 +79,7 @@ func (q *Queries) BatchBestSubmissionsFromParticipation(ctx context.Context, par
 			&i.ProblemID,
 			&i.ParticipationID,
 			&i.LanguageID,
+			&i.RejudgedProblemID,
 			&i.Title,
 			&i.DisplayName,
 			&i.TotalTestCases,
@@ -95,11 +99,15 @@ func (q *Queries) BatchBestSubmissionsFromParticipation(ctx context.Context, par
 }
 
 const bestSubmission = `-- name: BestSubmission :one
-SELECT submission.id, submission.created_at, submission.updated_at, submission.src, submission.accepted_test_cases, submission.problem_id, submission.participation_id, submission.language_id, language.display_name as language
+SELECT submission.id, submission.created_at, submission.updated_at, submission.src, submission.accepted_test_cases, submission.problem_id, submission.participation_id, submission.language_id, submission.rejudged_problem_id, language.display_name as language
 FROM submission
 JOIN participation ON submission.participation_id = participation.id
 JOIN language ON submission.language_id = language.id
-WHERE submission.problem_id = ? and participation.user_id = ?
+WHERE COALESCE(submission.rejudged_problem_id, submission.problem_id) = ? and participation.user_id = ?
+  AND submission.created_at <= participation.expires_at
+  -- problem versions can be shared by several quizzes, only the quiz being
+  -- solved counts
+  AND participation.quiz_id = ?
 ORDER BY submission.accepted_test_cases DESC, submission.created_at ASC
 LIMIT 1
 `
@@ -107,6 +115,7 @@ LIMIT 1
 type BestSubmissionParams struct {
 	ProblemID string
 	UserID    string
+	QuizID    string
 }
 
 type BestSubmissionRow struct {
@@ -118,11 +127,12 @@ type BestSubmissionRow struct {
 	ProblemID         string
 	ParticipationID   string
 	LanguageID        int32
+	RejudgedProblemID sql.NullString
 	Language          string
 }
 
 func (q *Queries) BestSubmission(ctx context.Context, arg BestSubmissionParams) (BestSubmissionRow, error) {
-	row := q.db.QueryRowContext(ctx, bestSubmission, arg.ProblemID, arg.UserID)
+	row := q.db.QueryRowContext(ctx, bestSubmission, arg.ProblemID, arg.UserID, arg.QuizID)
 	var i BestSubmissionRow
 	err := row.Scan(
 		&i.ID,
@@ -133,6 +143,7 @@ func (q *Queries) BestSubmission(ctx context.Context, arg BestSubmissionParams)
 		&i.ProblemID,
 		&i.ParticipationID,
 		&i.LanguageID,
+		&i.RejudgedProblemID,
 		&i.Language,
 	)
 	return i, err
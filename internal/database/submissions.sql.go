@@ -38,7 +38,10 @@ const lastSubmission = `-- name: LastSubmission :one
 SELECT submission.src
 FROM submission
 JOIN participation ON submission.participation_id = participation.id
-WHERE submission.problem_id = ? and submission.language_id = ? and participation.user_id = ?
+WHERE COALESCE(submission.rejudged_problem_id, submission.problem_id) = ? and submission.language_id = ? and participation.user_id = ?
+  -- problem versions can be shared by several quizzes, only the quiz being
+  -- solved counts
+  AND participation.quiz_id = ?
 ORDER BY submission.created_at DESC
 LIMIT 1
 `
@@ -47,10 +50,16 @@ type LastSubmissionParams struct {
 	ProblemID  string
 	LanguageID int32
 	UserID     string
+	QuizID     string
 }
 
 func (q *Queries) LastSubmission(ctx context.Context, arg LastSubmissionParams) (string, error) {
-	row := q.db.QueryRowContext(ctx, lastSubmission, arg.ProblemID, arg.LanguageID, arg.UserID)
+	row := q.db.QueryRowContext(ctx, lastSubmission,
+		arg.ProblemID,
+		arg.LanguageID,
+		arg.UserID,
+		arg.QuizID,
+	)
 	var src string
 	err := row.Scan(&src)
 	return src, err
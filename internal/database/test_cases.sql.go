@@ -11,7 +11,7 @@ import (
 )
 
 const batchTestCases = `-- name: BatchTestCases :many
-SELECT test_case.id, test_case.created_at, test_case.updated_at, test_case.input, test_case.output, test_case.problem_id
+SELECT test_case.id, test_case.created_at, test_case.updated_at, test_case.input, test_case.output, test_case.problem_id, test_case.input_hash, test_case.output_hash, test_case.input_size, test_case.output_size
 FROM test_case
 WHERE problem_id IN (/*SLICE:problem_ids*/?)
 `
@@ -42,6 +42,10 @@ func (q *Queries) BatchTestCases(ctx context.Context, problemIds []string) ([]Te
 			&i.Input,
 			&i.Output,
 			&i.ProblemID,
+			&i.InputHash,
+			&i.OutputHash,
+			&i.InputSize,
+			&i.OutputSize,
 		); err != nil {
 			return nil, err
 		}
@@ -57,7 +61,8 @@ func (q *Queries) BatchTestCases(ctx context.Context, problemIds []string) ([]Te
 }
 
 const getTestCases = `-- name: GetTestCases :many
-SELECT problem.time_limit, problem.memory_limit, test_case.id, test_case.input, test_case.output
+SELECT problem.time_limit, problem.memory_limit, test_case.id, test_case.input, test_case.output,
+  test_case.input_hash, test_case.output_hash
 FROM problem
 JOIN test_case 
 ON problem.id = test_case.problem_id
@@ -70,6 +75,8 @@ type GetTestCasesRow struct {
 	ID          string
 	Input       string
 	Output      string
+	InputHash   string
+	OutputHash  string
 }
 
 func (q *Queries) GetTestCases(ctx context.Context, problemID string) ([]GetTestCasesRow, error) {
@@ -87,6 +94,8 @@ func (q *Queries) GetTestCases(ctx context.Context, problemID string) ([]GetTest
 			&i.ID,
 			&i.Input,
 			&i.Output,
+			&i.InputHash,
+			&i.OutputHash,
 		); err != nil {
 			return nil, err
 		}
@@ -103,15 +112,19 @@ func (q *Queries) GetTestCases(ctx context.Context, problemID string) ([]GetTest
 
 const insertTestCase = `-- name: InsertTestCase :exec
 INSERT INTO test_case
-(id, problem_id, input, output)
-VALUES (?, ?, ?, ?)
+(id, problem_id, input, output, input_hash, output_hash, input_size, output_size)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 type InsertTestCaseParams struct {
-	ID        string
-	ProblemID string
-	Input     string
-	Output    string
+	ID         string
+	ProblemID  string
+	Input      string
+	Output     string
+	InputHash  string
+	OutputHash string
+	InputSize  int32
+	OutputSize int32
 }
 
 func (q *Queries) InsertTestCase(ctx context.Context, arg InsertTestCaseParams) error {
@@ -120,12 +133,16 @@ func (q *Queries) InsertTestCase(ctx context.Context, arg InsertTestCaseParams)
 		arg.ProblemID,
 		arg.Input,
 		arg.Output,
+		arg.InputHash,
+		arg.OutputHash,
+		arg.InputSize,
+		arg.OutputSize,
 	)
 	return err
 }
 
 const selectTestCases = `-- name: SelectTestCases :many
-SELECT id, created_at, updated_at, input, output, problem_id
+SELECT id, created_at, updated_at, input, output, problem_id, input_hash, output_hash, input_size, output_size
 FROM test_case
 WHERE problem_id = ?
 `
@@ -146,6 +163,10 @@ func (q *Queries) SelectTestCases(ctx context.Context, problemID string) ([]Test
 			&i.Input,
 			&i.Output,
 			&i.ProblemID,
+			&i.InputHash,
+			&i.OutputHash,
+			&i.InputSize,
+			&i.OutputSize,
 		); err != nil {
 			return nil, err
 		}
@@ -1,15 +1,23 @@
 package server
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/blobstore"
 	"github.com/kw3a/spotted-server/internal/server/codejudge"
+	"github.com/kw3a/spotted-server/internal/server/mailer"
+	"github.com/kw3a/spotted-server/internal/server/notifications"
+	"github.com/kw3a/spotted-server/internal/server/quizes"
 	"github.com/kw3a/spotted-server/internal/server/storage"
+	"github.com/kw3a/spotted-server/internal/server/testgen"
+	"github.com/kw3a/spotted-server/internal/server/webhooks"
 )
 
 type App struct {
@@ -18,8 +26,15 @@ type App struct {
 	AuthService *auth.AuthService
 	AuthType    *auth.JWTAuth
 	Stream      *codejudge.Stream
+	Deadlines   *quizes.DeadlineBroker
 	Judge       codejudge.Judge0
+	RefJudge    codejudge.Judge0
 	Cld         *cloudinary.Cloudinary
+
+	// Notifications writes and delivers them, NotificationBroker wakes the
+	// streams of the navigation bar
+	Notifications      *notifications.Center
+	NotificationBroker *notifications.Broker
 }
 
 func envVariables() (EnvVariables, error) {
@@ -39,6 +54,10 @@ func envVariables() (EnvVariables, error) {
 	if jwtSecret == "" {
 		return EnvVariables{}, fmt.Errorf("JWT_SECRET environment variable is not set")
 	}
+	pseudonymKey := os.Getenv("PSEUDONYM_SECRET")
+	if pseudonymKey == "" {
+		return EnvVariables{}, fmt.Errorf("PSEUDONYM_SECRET environment variable is not set")
+	}
 	judgeURL := os.Getenv("JUDGE_URL")
 	if judgeURL == "" {
 		return EnvVariables{}, fmt.Errorf("JUDGE_URL environment variable is not set")
@@ -75,6 +94,7 @@ func envVariables() (EnvVariables, error) {
 		port:         port,
 		dbURL:        dbURL,
 		jwtSecret:    jwtSecret,
+		pseudonymKey: pseudonymKey,
 		judgeURL:     judgeURL,
 		judgeHeaders: judgeHeaders,
 		myURL:        myURL,
@@ -92,13 +112,28 @@ func NewApp(envVars EnvVariables) (*App, error) {
 	if err != nil {
 		return nil, err
 	}
+	mysqlStorage.PseudonymKey = []byte(envVars.pseudonymKey)
 	cloudinaryService, err := cloudinary.New()
 	if err != nil {
 		return nil, err
 	}
+	blobs, err := blobstore.NewFSFromEnv()
+	if err != nil {
+		return nil, err
+	}
 	authType := auth.NewJWTAuth(envVars.jwtSecret)
 	authService := &auth.AuthService{}
 	stream := codejudge.NewStream()
+	deadlines := quizes.NewDeadlineBroker()
+	mails, err := mailer.NewLocalFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	notificationBroker := notifications.NewBroker()
+	notificationCenter := notifications.NewCenter(mysqlStorage, notificationBroker, mails, envVars.myURL)
+	go quizes.RunFinalizer(context.Background(), mysqlStorage, deadlines, mysqlStorage, notificationCenter, 5*time.Second)
+	go notifications.RunDigest(context.Background(), mysqlStorage, mails, envVars.myURL)
+	go webhooks.RunDispatcher(context.Background(), mysqlStorage, webhooks.NewClient(), 10*time.Second)
 	callbackPath := "/api/submissions/"
 	callbackURL := envVars.myURL + callbackPath
 	judge := codejudge.NewJudge0(
@@ -106,13 +141,28 @@ func NewApp(envVars EnvVariables) (*App, error) {
 		callbackURL,
 		envVars.judgeHeaders,
 	)
+	referencePath := "/api/references/"
+	referenceJudge := codejudge.NewJudge0(
+		envVars.judgeURL,
+		envVars.myURL+referencePath,
+		envVars.judgeHeaders,
+	)
+	judge.Blobs = blobs
+	referenceJudge.Blobs = blobs
+	mysqlStorage.Blobs = blobs
+	mysqlStorage.Generator = testgen.New(&referenceJudge)
 	return &App{
 		Templ:       templ,
 		Storage:     mysqlStorage,
 		AuthService: authService,
 		AuthType:    authType,
 		Stream:      stream,
+		Deadlines:   deadlines,
 		Judge:       judge,
+		RefJudge:    referenceJudge,
 		Cld:         cloudinaryService,
+
+		Notifications:      notificationCenter,
+		NotificationBroker: notificationBroker,
 	}, nil
 }
@@ -21,6 +21,7 @@ type EnvVariables struct {
 	port         string
 	dbURL        string
 	jwtSecret    string
+	pseudonymKey string
 	judgeURL     string
 	judgeHeaders []codejudge.Judge0Header
 	myURL        string
@@ -68,8 +69,34 @@ func viewRoutes(r *chi.Mux, envVars EnvVariables) {
 		r.Post("/register/companies", app.CompanyRegistrationHandler())
 		r.Get("/companies", app.CompanyListPageHandler())
 		r.Get("/companies/{companyID}", app.CompanyPageHandler())
+		r.Post("/companies/{companyID}/stages", app.CompanyStagesHandler())
+		r.Get("/companies/{companyID}/webhooks", app.CompanyWebhooksPage())
+		r.Post("/companies/{companyID}/webhooks", app.CompanyWebhook())
+		r.Delete("/companies/{companyID}/webhooks/{webhookID}", app.CompanyWebhookDelete())
+		r.Post("/companies/{companyID}/deliveries/{deliveryID}/redeliver", app.WebhookRedelivery())
+		r.Get("/companies/{companyID}/members", app.CompanyTeamPage())
+		r.Post("/companies/{companyID}/invitations", app.CompanyInvite())
+		r.Delete("/companies/{companyID}/invitations/{invitationID}", app.CompanyInvitationDelete())
+		r.Post("/companies/{companyID}/members/{memberID}/role", app.CompanyMemberRole())
+		r.Delete("/companies/{companyID}/members/{memberID}", app.CompanyMemberDelete())
+		r.Post("/companies/{companyID}/members/{memberID}/owner", app.CompanyTransfer())
+		r.Get("/memberships", app.MembershipsPage())
+		r.Post("/memberships/{invitationID}/{answer}", app.MembershipAnswer())
 		r.Get("/register/offers", app.OfferRegistrationPage())
 		r.Post("/register/offers", app.OfferRegistration())
+		r.Post("/markdown/preview", app.MarkdownPreview())
+		r.Post("/attachments", app.Attachment())
+		r.Get("/library", app.Library())
+		r.Get("/library/options", app.BankOptions())
+		r.Get("/library/new", app.BankProblemPage())
+		r.Get("/library/{bankProblemID}", app.BankProblemPage())
+		r.Post("/library", app.BankProblemRegistration())
+		r.Post("/library/{bankProblemID}", app.BankProblemUpdate())
+		r.Post("/library/import", app.BankProblemImport())
+		r.Get("/library/{bankProblemID}/export", app.BankProblemExport())
+		r.Post("/library/{bankProblemID}/solutions", app.ReferenceRegistration())
+		r.Delete("/library/{bankProblemID}/solutions/{solutionID}", app.ReferenceDelete())
+		r.Post("/library/{bankProblemID}/validate", app.ReferenceValidation())
 		r.Patch("/pictures", app.ProfilePicHandler())
 		r.Patch("/email", app.UpdateEmail())
 		r.Patch("/cell", app.UpdateCell())
@@ -90,7 +117,31 @@ func viewRoutes(r *chi.Mux, envVars EnvVariables) {
 		r.Get("/offers/admin", app.OffersAdmin())
 		r.Get("/offers/admin/{offerID}", app.OfferAdmin())
 		r.Patch("/offers/archive/{offerID}", app.OfferArchive())
+		r.Patch("/offers/admin/{offerID}/status", app.OfferStatus())
+		r.Get("/offers/admin/{offerID}/edit", app.OfferEditionPage())
+		r.Post("/offers/admin/{offerID}/edit", app.OfferEdition())
+		r.Post("/offers/admin/{offerID}/proctoring", app.ProctoringRules())
+		r.Post("/offers/admin/{offerID}/blind", app.BlindReview())
+		r.Get("/offers/admin/{offerID}/similarity", app.Similarity())
+		r.Get("/offers/admin/{offerID}/export", app.ApplicantsExport())
+		r.Get("/offers/admin/{offerID}/applicants/{participationID}/dossier", app.ApplicantDossier())
+		r.Get("/offers/admin/{offerID}/access", app.QuizAccess())
+		r.Post("/offers/admin/{offerID}/access", app.QuizAccessUpdate())
+		r.Post("/offers/admin/{offerID}/invitations", app.Invitations())
+		r.Delete("/offers/admin/{offerID}/invitations/{invitationID}", app.InvitationDelete())
+		r.Post("/participations/{participationID}/adjustments", app.AdjustParticipation())
+		r.Post("/participations/{participationID}/review", app.ReviewApplicant())
+		r.Post("/offers/admin/{offerID}/review", app.BulkReview())
+		r.Post("/offers/admin/{offerID}/problems/{problemID}/rejudge", app.Rejudge())
+		r.Post("/participations/{participationID}/rejudge", app.Rejudge())
+		r.Post("/submissions/{submissionID}/rejudge", app.Rejudge())
+		r.Get("/rejudges/{rejudgeID}", app.RejudgeProgress())
+		r.Post("/responses/{responseID}/grade", app.GradeResponse())
 		r.Post("/keystrokes", app.KeystrokeWindowHandler())
+		r.Post("/proctoring", app.ProctoringEventHandler())
+		r.Get("/notifications/stream", app.NotificationStream())
+		r.Post("/notifications/read", app.NotificationsRead())
+		r.Post("/notifications/{notificationID}/read", app.NotificationRead())
 	})
 
 	r.With(authNMiddleware).With(authRMiddleware).Group(func(r chi.Router) {
@@ -99,15 +150,23 @@ func viewRoutes(r *chi.Mux, envVars EnvVariables) {
 		r.Get("/examples", app.ExamplesHandler())
 		r.Get("/source", app.LastSrcHandler())
 		r.Get("/score", app.ScoreHandler())
+		r.Get("/answers", app.QuestionFormHandler())
+		r.Post("/answers", app.AnswerHandler())
 		r.Post("/participate", app.ParticipateHandler())
 		r.Post("/end", app.EndHandler())
+		r.Get("/deadline/{quizID}", app.DeadlineHandler())
+		r.Get("/applications", app.ApplicationsHandler())
+		r.Post("/applications/{participationID}/withdraw", app.WithdrawHandler())
 		r.Get("/keystrokes/report", app.KeystrokeReportHandler())
+		r.Get("/notifications/preferences", app.NotificationPreferencesPage())
+		r.Post("/notifications/preferences", app.NotificationPreferences())
 
 		r.Post("/submissions", app.RunHandler())
 		r.HandleFunc("/results/{submissionID}", app.ResultsHandler())
 	})
 
 	r.Put("/api/submissions/{submissionID}/tc/{testCaseID}", app.CallbackHandler())
+	r.Put("/api/references/{solutionID}/tc/{testCaseID}", app.ReferenceCallback())
 }
 
 func setupCors(r *chi.Mux) {
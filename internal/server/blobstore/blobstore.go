@@ -0,0 +1,167 @@
+package blobstore
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"unicode/utf8"
+)
+
+var (
+	ErrNotFound = errors.New("blob not found")
+	ErrBadHash  = errors.New("invalid blob hash")
+)
+
+var hashFormat = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// FS keeps gzipped blobs on the local filesystem, a blob is named after the
+// SHA-256 of its uncompressed content so the same data is stored once
+type FS struct {
+	root string
+}
+
+func NewFS(root string) (*FS, error) {
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, err
+	}
+	return &FS{root: root}, nil
+}
+
+// NewFSFromEnv keeps the blobs in BLOB_DIR, blobs under the working
+// directory when it is not set
+func NewFSFromEnv() (*FS, error) {
+	root := os.Getenv("BLOB_DIR")
+	if root == "" {
+		root = "blobs"
+	}
+	return NewFS(root)
+}
+
+func Hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func ValidHash(hash string) bool {
+	return hashFormat.MatchString(hash)
+}
+
+func (s *FS) path(hash string) (string, error) {
+	if !ValidHash(hash) {
+		return "", ErrBadHash
+	}
+	return filepath.Join(s.root, hash[:2], hash[2:]+".gz"), nil
+}
+
+// Put stores the data and returns its hash, storing data that is already
+// there does nothing
+func (s *FS) Put(data []byte) (string, error) {
+	hash := Hash(data)
+	path, err := s.path(hash)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return "", err
+	}
+	// the blob is written aside and renamed so readers never see half of it
+	tmp, err := os.CreateTemp(filepath.Dir(path), "tmp-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	zw := gzip.NewWriter(tmp)
+	if _, err := zw.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+type blobReader struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (r blobReader) Close() error {
+	r.Reader.Close()
+	return r.file.Close()
+}
+
+// Open streams the uncompressed content of the blob
+func (s *FS) Open(hash string) (io.ReadCloser, error) {
+	path, err := s.path(hash)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path) // #nosec G304 -- the path is built from a validated hash
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return blobReader{Reader: zr, file: file}, nil
+}
+
+func (s *FS) Read(hash string) ([]byte, error) {
+	r, err := s.Open(hash)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// Preview returns the first n bytes of the blob, cut at a character
+// boundary, and the size of the whole content
+func (s *FS) Preview(hash string, n int) (string, int64, error) {
+	r, err := s.Open(hash)
+	if err != nil {
+		return "", 0, err
+	}
+	defer r.Close()
+	head := make([]byte, n+utf8.UTFMax)
+	read, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", 0, err
+	}
+	rest, err := io.Copy(io.Discard, r)
+	if err != nil {
+		return "", 0, err
+	}
+	return Truncate(string(head[:read]), n), int64(read) + rest, nil
+}
+
+// Truncate cuts the text to at most n bytes without splitting a character
+func Truncate(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	return text[:n]
+}
@@ -0,0 +1,78 @@
+package blobstore
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPutAndRead(t *testing.T) {
+	store, err := NewFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Repeat([]byte("1 2 3 4 5\n"), 100000)
+	hash, err := store.Put(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != Hash(data) {
+		t.Errorf("expected the content hash, got %s", hash)
+	}
+	again, err := store.Put(data)
+	if err != nil || again != hash {
+		t.Errorf("expected the same blob, got %s, %v", again, err)
+	}
+	read, err := store.Read(hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(read, data) {
+		t.Error("expected the stored data back")
+	}
+	path, _ := store.path(hash)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() >= int64(len(data))/10 {
+		t.Errorf("expected compressed data, got %d bytes", info.Size())
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	store, err := NewFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Open(Hash([]byte("missing"))); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected not found, got %v", err)
+	}
+	if _, err := store.Open("../" + strings.Repeat("a", 61)); !errors.Is(err, ErrBadHash) {
+		t.Errorf("expected bad hash, got %v", err)
+	}
+}
+
+func TestPreview(t *testing.T) {
+	store, err := NewFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("ñandú " + strings.Repeat("x", 5000))
+	hash, err := store.Put(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preview, size, err := store.Preview(hash, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview != "ñ" || size != int64(len(data)) {
+		t.Errorf("unexpected preview %q of %d bytes", preview, size)
+	}
+	if Truncate("abc", 5) != "abc" || Truncate("añb", 2) != "a" {
+		t.Error("unexpected truncation")
+	}
+}
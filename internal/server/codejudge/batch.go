@@ -0,0 +1,38 @@
+package codejudge
+
+import (
+	"sync"
+)
+
+type Sender interface {
+	Send(testCases []TestCase, submission Submission) ([]string, error)
+}
+
+// Batch is one submission with the test cases it is judged against
+type Batch struct {
+	TestCases  []TestCase
+	Submission Submission
+}
+
+// SendAll sends every batch keeping at most workers requests in flight, so
+// a large rejudge doesn't flood the judge. The errors keep the order of the
+// batches
+func SendAll(judge Sender, batches []Batch, workers int) []error {
+	if workers < 1 {
+		workers = 1
+	}
+	errs := make([]error, len(batches))
+	sem := make(chan struct{}, workers)
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, batch Batch) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			_, errs[i] = judge.Send(batch.TestCases, batch.Submission)
+		}(i, batch)
+	}
+	wg.Wait()
+	return errs
+}
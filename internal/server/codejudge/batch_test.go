@@ -0,0 +1,55 @@
+package codejudge
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type countingSender struct {
+	mu       sync.Mutex
+	inFlight int
+	max      int
+}
+
+func (s *countingSender) Send(testCases []TestCase, submission Submission) ([]string, error) {
+	s.mu.Lock()
+	s.inFlight++
+	if s.inFlight > s.max {
+		s.max = s.inFlight
+	}
+	s.mu.Unlock()
+	time.Sleep(5 * time.Millisecond)
+	s.mu.Lock()
+	s.inFlight--
+	s.mu.Unlock()
+	if submission.ID == "bad" {
+		return nil, errors.New("error")
+	}
+	return []string{"token"}, nil
+}
+
+func TestSendAllBoundsConcurrency(t *testing.T) {
+	sender := &countingSender{}
+	batches := make([]Batch, 10)
+	batches[3].Submission.ID = "bad"
+	errs := SendAll(sender, batches, 3)
+	if sender.max > 3 {
+		t.Errorf("expected at most 3 requests in flight, got %d", sender.max)
+	}
+	if len(errs) != len(batches) {
+		t.Fatalf("expected %d errors, got %d", len(batches), len(errs))
+	}
+	for i, err := range errs {
+		if (i == 3) != (err != nil) {
+			t.Errorf("batch %d: unexpected error %v", i, err)
+		}
+	}
+}
+
+func TestSendAllEmpty(t *testing.T) {
+	if errs := SendAll(&countingSender{}, nil, 0); len(errs) != 0 {
+		t.Errorf("expected no errors, got %d", len(errs))
+	}
+}
@@ -1,11 +1,14 @@
 package codejudge
 
 import (
+	"bufio"
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 )
@@ -39,12 +42,25 @@ type TestCase struct {
 	MemoryLimit    int32
 	Input          string
 	ExpectedOutput string
+	// Src replaces the source of the submission for this test case, SQL
+	// problems build one script per dataset
+	Src string
+	// InputBlob and OutputBlob replace Input and ExpectedOutput with data
+	// of the blob store, it is streamed to the judge
+	InputBlob  string
+	OutputBlob string
+}
+
+// BlobOpener reads the test data kept outside the database
+type BlobOpener interface {
+	Open(hash string) (io.ReadCloser, error)
 }
 
 type Judge0 struct {
 	CallbackURL string
 	JudgeURL    string
 	Headers     []Judge0Header
+	Blobs       BlobOpener
 }
 
 type Judge0Header struct {
@@ -61,55 +77,147 @@ func NewJudge0(judgeURL, callbackURL string, headers []Judge0Header) Judge0 {
 }
 
 func (j *Judge0) Send(testCases []TestCase, submission Submission) ([]string, error) {
-	body, err := JsonFormat(testCases, submission, j.CallbackURL)
-	if err != nil {
-		return []string{}, err
+	if !hasBlobs(testCases) {
+		body, err := JsonFormat(testCases, submission, j.CallbackURL)
+		if err != nil {
+			return []string{}, err
+		}
+		URL, err := ComposeUrl(j.JudgeURL, "submissions/batch")
+		if err != nil {
+			return []string{}, err
+		}
+		return SendRequest(body, URL, j.Headers)
 	}
 	URL, err := ComposeUrl(j.JudgeURL, "submissions/batch")
 	if err != nil {
 		return []string{}, err
 	}
-	return SendRequest(body, URL, j.Headers)
+	// large test data is encoded while it is sent instead of being held in
+	// memory
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.CloseWithError(StreamFormat(pw, testCases, submission, j.CallbackURL, j.Blobs))
+	}()
+	return sendBody(pr, URL, j.Headers)
+}
+
+func hasBlobs(testCases []TestCase) bool {
+	for _, tc := range testCases {
+		if tc.InputBlob != "" || tc.OutputBlob != "" {
+			return true
+		}
+	}
+	return false
 }
 
 func JsonFormat(dbTestCases []TestCase, submission Submission, callbackURL string) ([]byte, error) {
+	var body bytes.Buffer
+	if err := StreamFormat(&body, dbTestCases, submission, callbackURL, nil); err != nil {
+		return []byte{}, err
+	}
+	return body.Bytes(), nil
+}
+
+// judge0Head holds the fields of a Judge0TC that are not test data
+type judge0Head struct {
+	Src          string  `json:"source_code"`
+	LanguageID   int32   `json:"language_id"`
+	Memory_limit int32   `json:"memory_limit"`
+	Time_limit   float64 `json:"cpu_time_limit"`
+	CallbackURL  string  `json:"callback_url"`
+}
+
+// StreamFormat writes the batch of the submission in the form of a
+// JudgeSubmission, the data of the blobs is read and encoded as it is written
+func StreamFormat(w io.Writer, dbTestCases []TestCase, submission Submission, callbackURL string, blobs BlobOpener) error {
 	if len(dbTestCases) == 0 {
-		return nil, errors.New("empty database test cases")
-	}
-	judgeTCs := []Judge0TC{}
-	for _, dbTestCase := range dbTestCases {
-		current := Judge0TC{
-			Src:            encode(submission.Src),
-			LanguageID:     submission.LanguageID,
-			Memory_limit:   dbTestCase.MemoryLimit,
-			Time_limit:     dbTestCase.TimeLimit,
-			Input:          encode(dbTestCase.Input),
-			ExpectedOutput: encode(dbTestCase.ExpectedOutput),
-			CallbackURL:    callbackURL + submission.ID + "/tc/" + dbTestCase.ID,
+		return errors.New("empty database test cases")
+	}
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`{"submissions":[`)
+	for i, dbTestCase := range dbTestCases {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		src := submission.Src
+		if dbTestCase.Src != "" {
+			src = dbTestCase.Src
 		}
-		judgeTCs = append(judgeTCs, current)
+		head, err := json.Marshal(judge0Head{
+			Src:          encode(src),
+			LanguageID:   submission.LanguageID,
+			Memory_limit: dbTestCase.MemoryLimit,
+			Time_limit:   dbTestCase.TimeLimit,
+			CallbackURL:  callbackURL + submission.ID + "/tc/" + dbTestCase.ID,
+		})
+		if err != nil {
+			return err
+		}
+		bw.Write(head[:len(head)-1])
+		bw.WriteString(`,"stdin":`)
+		if err := writeData(bw, dbTestCase.Input, dbTestCase.InputBlob, blobs); err != nil {
+			return err
+		}
+		bw.WriteString(`,"expected_output":`)
+		if err := writeData(bw, dbTestCase.ExpectedOutput, dbTestCase.OutputBlob, blobs); err != nil {
+			return err
+		}
+		bw.WriteByte('}')
 	}
-	judgeSubmission := JudgeSubmission{
-		TestsCases: judgeTCs,
+	bw.WriteString("]}")
+	return bw.Flush()
+}
+
+// writeData writes the text, or the blob when hash is set, as a base64 JSON
+// string
+func writeData(w io.Writer, text string, hash string, blobs BlobOpener) error {
+	io.WriteString(w, `"`)
+	enc := base64.NewEncoder(base64.StdEncoding, w)
+	if hash == "" {
+		io.WriteString(enc, text)
+	} else {
+		if blobs == nil {
+			return errors.New("test data in a blob without a blob store")
+		}
+		r, err := blobs.Open(hash)
+		if err != nil {
+			return fmt.Errorf("open test data %s: %w", hash, err)
+		}
+		_, err = io.Copy(enc, r)
+		r.Close()
+		if err != nil {
+			return err
+		}
 	}
-	jsonJudgeTC, err := json.Marshal(judgeSubmission)
-	if err != nil {
-		return []byte{}, err
+	if err := enc.Close(); err != nil {
+		return err
 	}
-	return jsonJudgeTC, nil
+	_, err := io.WriteString(w, `"`)
+	return err
 }
 
 func SendRequest(body []byte, URL url.URL, headers []Judge0Header) ([]string, error) {
-	bodyReader := bytes.NewReader(body)
-	req, err := http.NewRequest("POST", URL.String(), bodyReader)
+	return sendBody(bytes.NewReader(body), URL, headers)
+}
+
+func newRequest(ctx context.Context, body io.Reader, URL url.URL, headers []Judge0Header) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", URL.String(), body)
 	if err != nil {
-		return []string{}, errors.New("Post submission failed: " + err.Error())
+		return nil, errors.New("Post submission failed: " + err.Error())
 	}
 	req.Header.Set("Content-Type", "application/json")
 	for _, header := range headers {
 		req.Header.Set(header.Name, header.Value)
 	}
+	return req, nil
+}
 
+func sendBody(body io.Reader, URL url.URL, headers []Judge0Header) ([]string, error) {
+	req, err := newRequest(context.Background(), body, URL, headers)
+	if err != nil {
+		return []string{}, err
+	}
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return []string{}, errors.New("Post submission failed: " + err.Error())
@@ -1,11 +1,16 @@
 package codejudge
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/google/uuid"
@@ -163,6 +168,25 @@ func TestJsonFormat(t *testing.T) {
 	}
 }
 
+func TestJsonFormatTestCaseSrc(t *testing.T) {
+	submission := getSubmission()
+	dbTestCases := []TestCase{{ID: "a"}, {ID: "b", Src: "SELECT 1;"}}
+	body, err := JsonFormat(dbTestCases, submission, "http://localhost/callback/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var judgeSubmission JudgeSubmission
+	if err := json.Unmarshal(body, &judgeSubmission); err != nil {
+		t.Fatal(err)
+	}
+	if judgeSubmission.TestsCases[0].Src != encode(submission.Src) {
+		t.Error("expected the source of the submission")
+	}
+	if judgeSubmission.TestsCases[1].Src != encode("SELECT 1;") {
+		t.Error("expected the source of the test case")
+	}
+}
+
 func TestComposeUrlEmpty(t *testing.T) {
 	_, err := ComposeUrl("", "submissions/batch")
 	if err == nil {
@@ -359,3 +383,104 @@ func TestSendRapidAPI(t *testing.T) {
 		t.Errorf(`Expected 2 tokens, got %d`, len(tokens))
 	}
 }
+
+type blobsMock map[string]string
+
+func (b blobsMock) Open(hash string) (io.ReadCloser, error) {
+	data, ok := b[hash]
+	if !ok {
+		return nil, errors.New("blob not found")
+	}
+	return io.NopCloser(strings.NewReader(data)), nil
+}
+
+func TestStreamFormatBlobs(t *testing.T) {
+	submission := getSubmission()
+	big := strings.Repeat("1 2 3\n", 10000)
+	dbTestCases := []TestCase{{ID: "a", Input: "1", ExpectedOutput: "2"}, {ID: "b", InputBlob: "in", OutputBlob: "out"}}
+	var body strings.Builder
+	err := StreamFormat(&body, dbTestCases, submission, "http://localhost/callback/", blobsMock{"in": big, "out": "6"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var judgeSubmission JudgeSubmission
+	if err := json.Unmarshal([]byte(body.String()), &judgeSubmission); err != nil {
+		t.Fatal(err)
+	}
+	if judgeSubmission.TestsCases[0].Input != encode("1") || judgeSubmission.TestsCases[0].ExpectedOutput != encode("2") {
+		t.Error("expected the inline test data")
+	}
+	if judgeSubmission.TestsCases[1].Input != encode(big) || judgeSubmission.TestsCases[1].ExpectedOutput != encode("6") {
+		t.Error("expected the test data of the blobs")
+	}
+	if judgeSubmission.TestsCases[1].CallbackURL != "http://localhost/callback/"+submission.ID+"/tc/b" {
+		t.Errorf("unexpected callback %s", judgeSubmission.TestsCases[1].CallbackURL)
+	}
+}
+
+func TestStreamFormatMissingBlob(t *testing.T) {
+	dbTestCases := []TestCase{{ID: "a", InputBlob: "missing"}}
+	if err := StreamFormat(io.Discard, dbTestCases, getSubmission(), "", blobsMock{}); err == nil {
+		t.Error("expected error, got nil")
+	}
+	if _, err := JsonFormat(dbTestCases, getSubmission(), ""); err == nil {
+		t.Error("expected error without blob store, got nil")
+	}
+}
+
+func TestSendBlobs(t *testing.T) {
+	judge := getJudgeXAuth()
+	judge.Blobs = blobsMock{"in": strings.Repeat("9", 100000)}
+	testServer := testServer()
+	defer testServer.Close()
+	judge.JudgeURL = testServer.URL
+	dbTestCases := getTestCases()
+	dbTestCases[1].InputBlob = "in"
+	tokens, err := judge.Send(dbTestCases, getSubmission())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens) != 2 {
+		t.Errorf("Expected 2 tokens, got %d", len(tokens))
+	}
+}
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("wait") != "true" || r.URL.Path != "/submissions" {
+			RespondWithError(w, 400, "expected a synchronous submission")
+			return
+		}
+		params := runRequest{}
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			RespondWithError(w, 400, "can't unmarshal params")
+			return
+		}
+		stdin, _ := base64.StdEncoding.DecodeString(params.Input)
+		if string(stdin) == "fail" {
+			RespondWithJSON(w, http.StatusCreated, map[string]any{
+				"stderr": encode("boom"),
+				"status": map[string]any{"id": 11, "description": "Runtime Error (NZEC)"},
+			})
+			return
+		}
+		RespondWithJSON(w, http.StatusCreated, map[string]any{
+			"stdout": encode(string(stdin) + "!"),
+			"status": map[string]any{"id": 3, "description": "Accepted"},
+		})
+	}))
+	defer server.Close()
+	judge := getJudgeXAuth()
+	judge.JudgeURL = server.URL
+	out, err := judge.Run(context.Background(), "print(input())", 71, "42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "42!" {
+		t.Errorf("unexpected output %q", out)
+	}
+	_, err = judge.Run(context.Background(), "print(input())", 71, "fail")
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected the runtime error, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package codejudge
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// runTimeLimit is the cpu time limit in seconds of the programs run by Run
+const runTimeLimit = 10
+
+const statusAccepted = 3
+
+type runRequest struct {
+	Src        string  `json:"source_code"`
+	LanguageID int32   `json:"language_id"`
+	Input      string  `json:"stdin"`
+	TimeLimit  float64 `json:"cpu_time_limit"`
+}
+
+type runResult struct {
+	Stdout        string `json:"stdout"`
+	Stderr        string `json:"stderr"`
+	CompileOutput string `json:"compile_output"`
+	Status        struct {
+		ID          int    `json:"id"`
+		Description string `json:"description"`
+	} `json:"status"`
+}
+
+// Run executes the program with the input and waits for its output, it is
+// used for programs of the recruiters like test generators, not submissions
+func (j *Judge0) Run(ctx context.Context, src string, languageID int32, stdin string) (string, error) {
+	URL, err := ComposeUrl(j.JudgeURL, "submissions")
+	if err != nil {
+		return "", err
+	}
+	q := URL.Query()
+	q.Set("wait", "true")
+	URL.RawQuery = q.Encode()
+	body, err := json.Marshal(runRequest{
+		Src:        encode(src),
+		LanguageID: languageID,
+		Input:      encode(stdin),
+		TimeLimit:  runTimeLimit,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := newRequest(ctx, bytes.NewReader(body), URL, j.Headers)
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("run failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+	result := runResult{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", err
+	}
+	if result.Status.ID != statusAccepted {
+		detail := result.CompileOutput
+		if detail == "" {
+			detail = result.Stderr
+		}
+		decoded, _ := base64.StdEncoding.DecodeString(detail)
+		return "", fmt.Errorf("%s: %s", result.Status.Description, decoded)
+	}
+	stdout, err := base64.StdEncoding.DecodeString(result.Stdout)
+	if err != nil {
+		return "", err
+	}
+	return string(stdout), nil
+}
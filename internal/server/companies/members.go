@@ -0,0 +1,432 @@
+package companies
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const errNotMember = "you are not a member of this company"
+
+// RoleOption is an option of the role selects of the team
+type RoleOption struct {
+	Name  string
+	Label string
+}
+
+// TeamData is the team section of the company as seen by a member with
+// Role, Invitee and InviteRole keep the invitation form values when it is
+// rejected
+type TeamData struct {
+	CompanyID   string
+	UserID      string
+	Role        string
+	Members     []shared.Member
+	Invitations []shared.CompanyInvitation
+	Roles       []RoleOption
+	Invitee     string
+	InviteRole  string
+	Alert       shared.Alert
+}
+
+func (d TeamData) CanManage() bool {
+	return shared.CanManage(d.Role)
+}
+
+func (d TeamData) IsOwner() bool {
+	return d.Role == shared.RoleOwner
+}
+
+type TeamPageData struct {
+	User    auth.AuthUser
+	Company shared.Company
+	Team    TeamData
+}
+
+type TeamStorage interface {
+	GetCompanyByID(ctx context.Context, companyID string) (shared.Company, error)
+	SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error)
+	SelectCompanyMembers(ctx context.Context, companyID string) ([]shared.Member, error)
+	SelectCompanyInvitations(ctx context.Context, companyID string) ([]shared.CompanyInvitation, error)
+	InviteMember(ctx context.Context, userID string, invitation shared.CompanyInvitation) error
+	DeleteMemberInvitation(ctx context.Context, userID, companyID, invitationID string) error
+	UpdateMemberRole(ctx context.Context, userID, companyID, memberID, role string) error
+	DeleteMember(ctx context.Context, userID, companyID, memberID string) error
+	TransferCompany(ctx context.Context, userID, companyID, memberID string) error
+}
+
+type InviteInput struct {
+	CompanyID string
+	Invitee   string
+	Role      string
+}
+
+type InvitationDeleteInput struct {
+	CompanyID    string
+	InvitationID string
+}
+
+type MemberInput struct {
+	CompanyID string
+	MemberID  string
+	// Role is only sent by the role change
+	Role string
+}
+
+func roleOptions() []RoleOption {
+	options := []RoleOption{}
+	for _, role := range shared.MemberRoles {
+		options = append(options, RoleOption{Name: role, Label: shared.MemberRoleLabels[role]})
+	}
+	return options
+}
+
+// GetInviteInput returns the form values along the error so the admin
+// doesn't lose them
+func GetInviteInput(r *http.Request) (InviteInput, error) {
+	companyID := chi.URLParam(r, "companyID")
+	if err := shared.ValidateUUID(companyID); err != nil {
+		return InviteInput{}, err
+	}
+	input := InviteInput{
+		CompanyID: companyID,
+		Invitee:   strings.TrimSpace(r.FormValue("invitee")),
+		Role:      r.FormValue("role"),
+	}
+	invitee, err := shared.ValidateInvitee(input.Invitee)
+	if err != nil {
+		return input, err
+	}
+	input.Invitee = invitee
+	if err := shared.ValidateMemberRole(input.Role); err != nil {
+		return input, err
+	}
+	return input, nil
+}
+
+func GetInvitationDeleteInput(r *http.Request) (InvitationDeleteInput, error) {
+	companyID := chi.URLParam(r, "companyID")
+	if err := shared.ValidateUUID(companyID); err != nil {
+		return InvitationDeleteInput{}, err
+	}
+	invitationID := chi.URLParam(r, "invitationID")
+	if err := shared.ValidateUUID(invitationID); err != nil {
+		return InvitationDeleteInput{}, err
+	}
+	return InvitationDeleteInput{CompanyID: companyID, InvitationID: invitationID}, nil
+}
+
+func GetMemberInput(r *http.Request) (MemberInput, error) {
+	companyID := chi.URLParam(r, "companyID")
+	if err := shared.ValidateUUID(companyID); err != nil {
+		return MemberInput{}, err
+	}
+	memberID := chi.URLParam(r, "memberID")
+	if err := shared.ValidateUUID(memberID); err != nil {
+		return MemberInput{}, err
+	}
+	return MemberInput{CompanyID: companyID, MemberID: memberID}, nil
+}
+
+func GetMemberRoleInput(r *http.Request) (MemberInput, error) {
+	input, err := GetMemberInput(r)
+	if err != nil {
+		return MemberInput{}, err
+	}
+	input.Role = r.FormValue("role")
+	if err := shared.ValidateMemberRole(input.Role); err != nil {
+		return MemberInput{}, err
+	}
+	return input, nil
+}
+
+// selectTeamData only answers to the members of the company
+func selectTeamData(ctx context.Context, storage TeamStorage, companyID, userID string) (TeamData, error) {
+	role, err := storage.SelectCompanyRole(ctx, companyID, userID)
+	if err != nil {
+		return TeamData{}, err
+	}
+	if role == "" {
+		return TeamData{}, shared.ErrNotMember
+	}
+	members, err := storage.SelectCompanyMembers(ctx, companyID)
+	if err != nil {
+		return TeamData{}, err
+	}
+	data := TeamData{
+		CompanyID: companyID,
+		UserID:    userID,
+		Role:      role,
+		Members:   members,
+		Roles:     roleOptions(),
+	}
+	if data.CanManage() {
+		data.Invitations, err = storage.SelectCompanyInvitations(ctx, companyID)
+		if err != nil {
+			return TeamData{}, err
+		}
+	}
+	return data, nil
+}
+
+func renderTeam(
+	w http.ResponseWriter,
+	r *http.Request,
+	storage TeamStorage,
+	templ shared.TemplatesRepo,
+	companyID string,
+	userID string,
+	alert shared.Alert,
+) {
+	data, err := selectTeamData(r.Context(), storage, companyID, userID)
+	if err == shared.ErrNotMember {
+		http.Error(w, errNotMember, http.StatusUnauthorized)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data.Alert = alert
+	if err := templ.Render(w, "team", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// teamAlert shows the team rules to the member, any other error is
+// internal
+func teamAlert(
+	w http.ResponseWriter,
+	r *http.Request,
+	storage TeamStorage,
+	templ shared.TemplatesRepo,
+	companyID string,
+	userID string,
+	err error,
+) {
+	if !shared.IsMemberError(err) {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	renderTeam(w, r, storage, templ, companyID, userID, shared.Alert{Ok: false, Msg: err.Error()})
+}
+
+func CreateTeamPageHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage TeamStorage,
+	inputFn companyPageInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		company, err := storage.GetCompanyByID(r.Context(), input.CompanyID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, err := selectTeamData(r.Context(), storage, company.ID, user.ID)
+		if err == shared.ErrNotMember {
+			http.Error(w, errNotMember, http.StatusUnauthorized)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = templ.Render(w, "teamPage", TeamPageData{
+			User:    user,
+			Company: company,
+			Team:    data,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type inviteInputFn func(r *http.Request) (InviteInput, error)
+
+// CreateInviteHandler invites by nick or email, the invitee is notified if
+// they already have an account
+func CreateInviteHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage TeamStorage,
+	notifier shared.Notifier,
+	inputFn inviteInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, inputErr := inputFn(r)
+		if inputErr != nil && !shared.IsMemberError(inputErr) {
+			http.Error(w, inputErr.Error(), http.StatusBadRequest)
+			return
+		}
+		if inputErr != nil {
+			data, err := selectTeamData(r.Context(), storage, input.CompanyID, user.ID)
+			if err == shared.ErrNotMember {
+				http.Error(w, errNotMember, http.StatusUnauthorized)
+				return
+			}
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			data.Invitee = input.Invitee
+			data.InviteRole = input.Role
+			data.Alert = shared.Alert{Ok: false, Msg: inputErr.Error()}
+			if err := templ.Render(w, "team", data); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		invitation := shared.CompanyInvitation{
+			ID:        uuid.New().String(),
+			CompanyID: input.CompanyID,
+			Invitee:   input.Invitee,
+			Role:      input.Role,
+		}
+		if err := storage.InviteMember(r.Context(), user.ID, invitation); err != nil {
+			teamAlert(w, r, storage, templ, input.CompanyID, user.ID, err)
+			return
+		}
+		shared.Notify(r.Context(), notifier, shared.NotificationEvent{
+			Category:     shared.NotifyTeam,
+			InvitationID: invitation.ID,
+		})
+		renderTeam(w, r, storage, templ, input.CompanyID, user.ID, shared.Alert{Ok: true, Msg: "Invitación enviada"})
+	}
+}
+
+type invitationDeleteInputFn func(r *http.Request) (InvitationDeleteInput, error)
+
+func CreateInvitationDeleteHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage TeamStorage,
+	inputFn invitationDeleteInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = storage.DeleteMemberInvitation(r.Context(), user.ID, input.CompanyID, input.InvitationID)
+		if err != nil {
+			teamAlert(w, r, storage, templ, input.CompanyID, user.ID, err)
+			return
+		}
+		renderTeam(w, r, storage, templ, input.CompanyID, user.ID, shared.Alert{Ok: true, Msg: shared.MsgSaved})
+	}
+}
+
+type memberInputFn func(r *http.Request) (MemberInput, error)
+
+func CreateMemberRoleHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage TeamStorage,
+	inputFn memberInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = storage.UpdateMemberRole(r.Context(), user.ID, input.CompanyID, input.MemberID, input.Role)
+		if err != nil {
+			teamAlert(w, r, storage, templ, input.CompanyID, user.ID, err)
+			return
+		}
+		renderTeam(w, r, storage, templ, input.CompanyID, user.ID, shared.Alert{Ok: true, Msg: shared.MsgSaved})
+	}
+}
+
+// CreateMemberDeleteHandler removes a member, a member that leaves is sent
+// to their memberships since the team is not theirs to see anymore
+func CreateMemberDeleteHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage TeamStorage,
+	inputFn memberInputFn,
+	redirPath string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = storage.DeleteMember(r.Context(), user.ID, input.CompanyID, input.MemberID)
+		if err != nil {
+			teamAlert(w, r, storage, templ, input.CompanyID, user.ID, err)
+			return
+		}
+		if input.MemberID == user.ID {
+			w.Header().Add("HX-Redirect", redirPath)
+			return
+		}
+		renderTeam(w, r, storage, templ, input.CompanyID, user.ID, shared.Alert{Ok: true, Msg: shared.MsgSaved})
+	}
+}
+
+func CreateTransferHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage TeamStorage,
+	inputFn memberInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = storage.TransferCompany(r.Context(), user.ID, input.CompanyID, input.MemberID)
+		if err != nil {
+			teamAlert(w, r, storage, templ, input.CompanyID, user.ID, err)
+			return
+		}
+		renderTeam(w, r, storage, templ, input.CompanyID, user.ID, shared.Alert{Ok: true, Msg: "La empresa fue transferida"})
+	}
+}
@@ -0,0 +1,137 @@
+package companies
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const (
+	answerAccept  = "accept"
+	answerDecline = "decline"
+)
+
+var errAnswer = errors.New("the answer must be accept or decline")
+
+// MembershipsData are the teams of the user and the invitations they didn't
+// answer
+type MembershipsData struct {
+	Invitations []shared.CompanyInvitation
+	Memberships []shared.Membership
+	Alert       shared.Alert
+}
+
+type MembershipsPageData struct {
+	User        auth.AuthUser
+	Memberships MembershipsData
+}
+
+type MembershipsStorage interface {
+	SelectUserInvitations(ctx context.Context, userID string) ([]shared.CompanyInvitation, error)
+	SelectUserMemberships(ctx context.Context, userID string) ([]shared.Membership, error)
+	AnswerInvitation(ctx context.Context, userID, invitationID string, accept bool) error
+}
+
+type AnswerInput struct {
+	InvitationID string
+	Accept       bool
+}
+
+func GetAnswerInput(r *http.Request) (AnswerInput, error) {
+	invitationID := chi.URLParam(r, "invitationID")
+	if err := shared.ValidateUUID(invitationID); err != nil {
+		return AnswerInput{}, err
+	}
+	answer := chi.URLParam(r, "answer")
+	if answer != answerAccept && answer != answerDecline {
+		return AnswerInput{}, errAnswer
+	}
+	return AnswerInput{InvitationID: invitationID, Accept: answer == answerAccept}, nil
+}
+
+func selectMembershipsData(ctx context.Context, storage MembershipsStorage, userID string) (MembershipsData, error) {
+	invitations, err := storage.SelectUserInvitations(ctx, userID)
+	if err != nil {
+		return MembershipsData{}, err
+	}
+	memberships, err := storage.SelectUserMemberships(ctx, userID)
+	if err != nil {
+		return MembershipsData{}, err
+	}
+	return MembershipsData{Invitations: invitations, Memberships: memberships}, nil
+}
+
+func CreateMembershipsPageHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage MembershipsStorage,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, "you must be logged in to see your teams", http.StatusUnauthorized)
+			return
+		}
+		data, err := selectMembershipsData(r.Context(), storage, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = templ.Render(w, "membershipsPage", MembershipsPageData{User: user, Memberships: data})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type answerInputFn func(r *http.Request) (AnswerInput, error)
+
+// CreateAnswerHandler accepts or declines an invitation of the user, an
+// invitation of someone else is not found
+func CreateAnswerHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage MembershipsStorage,
+	inputFn answerInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, "you must be logged in to answer an invitation", http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := storage.AnswerInvitation(r.Context(), user.ID, input.InvitationID, input.Accept); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, err := selectMembershipsData(r.Context(), storage, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data.Alert = shared.Alert{Ok: true, Msg: "Invitación rechazada"}
+		if input.Accept {
+			data.Alert.Msg = "Ahora eres parte del equipo"
+		}
+		if err := templ.Render(w, "memberships", data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -14,6 +14,14 @@ type CompanyPageData struct {
 	Company shared.Company
 	Offers  []shared.Offer
 	NextPage int32
+	// Role is the role of the user in the company, empty for the rest
+	Role string
+	// Stages is only filled for the owner and the admins of the company
+	Stages *CompanyStagesData
+}
+
+func (d CompanyPageData) CanManage() bool {
+	return shared.CanManage(d.Role)
 }
 
 type CompanyPageInput struct {
@@ -23,6 +31,8 @@ type CompanyPageInput struct {
 type CompanyPageStorage interface {
 	GetCompanyByID(ctx context.Context, companyID string) (shared.Company, error)
 	SelectOffers(ctx context.Context, params shared.OfferQueryParams) ([]shared.Offer, error)
+	SelectCompanyPipeline(ctx context.Context, companyID string) (shared.CompanyPipeline, error)
+	SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error)
 }
 
 func GetCompanyPageInput(r *http.Request) (CompanyPageInput, error) {
@@ -71,6 +81,25 @@ func CreateCompanyPageHandler(
 			Offers:  offers,
 			NextPage: shared.PageParam(r) + 1,
 		}
+		if user.ID != "" {
+			data.Role, err = storage.SelectCompanyRole(r.Context(), company.ID, user.ID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		if data.CanManage() {
+			pipeline, err := storage.SelectCompanyPipeline(r.Context(), company.ID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			data.Stages = &CompanyStagesData{
+				CompanyID:  company.ID,
+				Text:       shared.StagesText(pipeline.Stages),
+				ShareStage: pipeline.ShareStage,
+			}
+		}
 		toRender := "companyPage"
 		if shared.PageParam(r) > 1 {
 			toRender = "companyPageList"
@@ -0,0 +1,92 @@
+package companies
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// CompanyStagesData fills the pipeline form of the company page, Text has
+// one stage per line
+type CompanyStagesData struct {
+	CompanyID  string
+	Text       string
+	ShareStage bool
+	Alert      shared.Alert
+}
+
+type StagesInput struct {
+	CompanyID string
+	Text      string
+	Pipeline  shared.CompanyPipeline
+}
+
+type StagesStorage interface {
+	UpdateCompanyPipeline(ctx context.Context, userID, companyID string, pipeline shared.CompanyPipeline) error
+}
+
+// GetStagesInput returns the form text along the error so the recruiter
+// doesn't lose it
+func GetStagesInput(r *http.Request) (StagesInput, error) {
+	companyID := chi.URLParam(r, "companyID")
+	if err := shared.ValidateUUID(companyID); err != nil {
+		return StagesInput{}, err
+	}
+	input := StagesInput{
+		CompanyID: companyID,
+		Text:      r.FormValue("stages"),
+		Pipeline:  shared.CompanyPipeline{ShareStage: r.FormValue("shareStage") == "on"},
+	}
+	stages, err := shared.ParseStages(input.Text)
+	if err != nil {
+		return input, err
+	}
+	input.Pipeline.Stages = stages
+	return input, nil
+}
+
+type stagesInputFn func(r *http.Request) (StagesInput, error)
+
+func CreateStagesHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage StagesStorage,
+	inputFn stagesInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		data := CompanyStagesData{
+			CompanyID:  input.CompanyID,
+			Text:       input.Text,
+			ShareStage: input.Pipeline.ShareStage,
+		}
+		if shared.IsStagesError(err) {
+			data.Alert = shared.Alert{Ok: false, Msg: err.Error()}
+			if err := templ.Render(w, "companyStages", data); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = storage.UpdateCompanyPipeline(r.Context(), user.ID, input.CompanyID, input.Pipeline)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data.Text = shared.StagesText(input.Pipeline.Stages)
+		data.Alert = shared.Alert{Ok: true, Msg: shared.MsgSaved}
+		if err := templ.Render(w, "companyStages", data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package companies
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// WebhookEventOption is a checkbox of the webhook form
+type WebhookEventOption struct {
+	Name  string
+	Label string
+}
+
+// WebhooksData is the webhooks section of the company, URL and Events keep
+// the form values when they are rejected
+type WebhooksData struct {
+	CompanyID  string
+	Webhooks   []shared.Webhook
+	Deliveries []shared.WebhookDelivery
+	Options    []WebhookEventOption
+	URL        string
+	Events     []string
+	Alert      shared.Alert
+}
+
+type WebhooksPageData struct {
+	User     auth.AuthUser
+	Company  shared.Company
+	Webhooks WebhooksData
+}
+
+type WebhooksStorage interface {
+	GetCompany(ctx context.Context, companyID, userID string) (shared.Company, error)
+	SelectWebhooks(ctx context.Context, companyID string) ([]shared.Webhook, error)
+	SelectWebhookDeliveries(ctx context.Context, companyID string) ([]shared.WebhookDelivery, error)
+	InsertWebhook(ctx context.Context, webhook shared.Webhook) error
+	DeleteWebhook(ctx context.Context, companyID, webhookID string) error
+	RedeliverWebhook(ctx context.Context, companyID, deliveryID string) error
+}
+
+type WebhookInput struct {
+	CompanyID string
+	URL       string
+	Events    []string
+}
+
+type WebhookDeleteInput struct {
+	CompanyID string
+	WebhookID string
+}
+
+type RedeliverInput struct {
+	CompanyID  string
+	DeliveryID string
+}
+
+func webhookOptions() []WebhookEventOption {
+	options := []WebhookEventOption{}
+	for _, event := range shared.WebhookEvents {
+		options = append(options, WebhookEventOption{Name: event, Label: shared.WebhookEventLabels[event]})
+	}
+	return options
+}
+
+// GetWebhookInput returns the form values along the error so the recruiter
+// doesn't lose them
+func GetWebhookInput(r *http.Request) (WebhookInput, error) {
+	companyID := chi.URLParam(r, "companyID")
+	if err := shared.ValidateUUID(companyID); err != nil {
+		return WebhookInput{}, err
+	}
+	if err := r.ParseForm(); err != nil {
+		return WebhookInput{}, err
+	}
+	input := WebhookInput{
+		CompanyID: companyID,
+		URL:       strings.TrimSpace(r.FormValue("url")),
+		Events:    r.Form["events"],
+	}
+	if err := shared.ValidateWebhookURL(input.URL); err != nil {
+		return input, err
+	}
+	events, err := shared.ParseWebhookEvents(input.Events)
+	if err != nil {
+		return input, err
+	}
+	input.Events = events
+	return input, nil
+}
+
+func GetWebhookDeleteInput(r *http.Request) (WebhookDeleteInput, error) {
+	companyID := chi.URLParam(r, "companyID")
+	if err := shared.ValidateUUID(companyID); err != nil {
+		return WebhookDeleteInput{}, err
+	}
+	webhookID := chi.URLParam(r, "webhookID")
+	if err := shared.ValidateUUID(webhookID); err != nil {
+		return WebhookDeleteInput{}, err
+	}
+	return WebhookDeleteInput{CompanyID: companyID, WebhookID: webhookID}, nil
+}
+
+func GetRedeliverInput(r *http.Request) (RedeliverInput, error) {
+	companyID := chi.URLParam(r, "companyID")
+	if err := shared.ValidateUUID(companyID); err != nil {
+		return RedeliverInput{}, err
+	}
+	deliveryID := chi.URLParam(r, "deliveryID")
+	if err := shared.ValidateUUID(deliveryID); err != nil {
+		return RedeliverInput{}, err
+	}
+	return RedeliverInput{CompanyID: companyID, DeliveryID: deliveryID}, nil
+}
+
+func selectWebhooksData(ctx context.Context, storage WebhooksStorage, companyID string) (WebhooksData, error) {
+	webhooks, err := storage.SelectWebhooks(ctx, companyID)
+	if err != nil {
+		return WebhooksData{}, err
+	}
+	deliveries, err := storage.SelectWebhookDeliveries(ctx, companyID)
+	if err != nil {
+		return WebhooksData{}, err
+	}
+	return WebhooksData{
+		CompanyID:  companyID,
+		Webhooks:   webhooks,
+		Deliveries: deliveries,
+		Options:    webhookOptions(),
+	}, nil
+}
+
+func renderWebhooks(
+	w http.ResponseWriter,
+	r *http.Request,
+	storage WebhooksStorage,
+	templ shared.TemplatesRepo,
+	companyID string,
+	alert shared.Alert,
+) {
+	data, err := selectWebhooksData(r.Context(), storage, companyID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data.Alert = alert
+	if err := templ.Render(w, "webhooks", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func CreateWebhooksPageHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage WebhooksStorage,
+	inputFn companyPageInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		company, err := storage.GetCompany(r.Context(), input.CompanyID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, err := selectWebhooksData(r.Context(), storage, company.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = templ.Render(w, "webhooksPage", WebhooksPageData{
+			User:     user,
+			Company:  company,
+			Webhooks: data,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type webhookInputFn func(r *http.Request) (WebhookInput, error)
+
+func CreateWebhookHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage WebhooksStorage,
+	secretFn func() (string, error),
+	inputFn webhookInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, inputErr := inputFn(r)
+		if inputErr != nil && !shared.IsWebhookError(inputErr) {
+			http.Error(w, inputErr.Error(), http.StatusBadRequest)
+			return
+		}
+		company, err := storage.GetCompany(r.Context(), input.CompanyID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if inputErr != nil {
+			data, err := selectWebhooksData(r.Context(), storage, company.ID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			data.URL = input.URL
+			data.Events = input.Events
+			data.Alert = shared.Alert{Ok: false, Msg: inputErr.Error()}
+			if err := templ.Render(w, "webhooks", data); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		secret, err := secretFn()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = storage.InsertWebhook(r.Context(), shared.Webhook{
+			ID:        uuid.New().String(),
+			CompanyID: company.ID,
+			URL:       input.URL,
+			Secret:    secret,
+			Events:    input.Events,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		renderWebhooks(w, r, storage, templ, company.ID, shared.Alert{Ok: true, Msg: shared.MsgSaved})
+	}
+}
+
+type webhookDeleteInputFn func(r *http.Request) (WebhookDeleteInput, error)
+
+func CreateWebhookDeleteHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage WebhooksStorage,
+	inputFn webhookDeleteInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		company, err := storage.GetCompany(r.Context(), input.CompanyID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := storage.DeleteWebhook(r.Context(), company.ID, input.WebhookID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		renderWebhooks(w, r, storage, templ, company.ID, shared.Alert{Ok: true, Msg: shared.MsgSaved})
+	}
+}
+
+type redeliverInputFn func(r *http.Request) (RedeliverInput, error)
+
+func CreateRedeliverHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage WebhooksStorage,
+	inputFn redeliverInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		company, err := storage.GetCompany(r.Context(), input.CompanyID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := storage.RedeliverWebhook(r.Context(), company.ID, input.DeliveryID); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		renderWebhooks(w, r, storage, templ, company.ID, shared.Alert{Ok: true, Msg: "Reenvío programado"})
+	}
+}
@@ -36,6 +36,15 @@ func (i invalidTemplates) Render(w io.Writer, name string, data interface{}) err
 	return errors.New("error")
 }
 
+type templatesMock struct {
+	mock.Mock
+}
+
+func (t *templatesMock) Render(w io.Writer, name string, data interface{}) error {
+	args := t.Called(w, name, data)
+	return args.Error(0)
+}
+
 type authMock struct {
 	mock.Mock
 }
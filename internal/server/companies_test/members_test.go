@@ -0,0 +1,372 @@
+package companiestest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/companies"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type teamStorage struct {
+	mock.Mock
+}
+
+func (s *teamStorage) GetCompanyByID(ctx context.Context, companyID string) (shared.Company, error) {
+	args := s.Called(ctx, companyID)
+	return args.Get(0).(shared.Company), args.Error(1)
+}
+
+func (s *teamStorage) SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error) {
+	args := s.Called(ctx, companyID, userID)
+	return args.String(0), args.Error(1)
+}
+
+func (s *teamStorage) SelectCompanyMembers(ctx context.Context, companyID string) ([]shared.Member, error) {
+	args := s.Called(ctx, companyID)
+	return args.Get(0).([]shared.Member), args.Error(1)
+}
+
+func (s *teamStorage) SelectCompanyInvitations(ctx context.Context, companyID string) ([]shared.CompanyInvitation, error) {
+	args := s.Called(ctx, companyID)
+	return args.Get(0).([]shared.CompanyInvitation), args.Error(1)
+}
+
+func (s *teamStorage) InviteMember(ctx context.Context, userID string, invitation shared.CompanyInvitation) error {
+	args := s.Called(ctx, userID, invitation)
+	return args.Error(0)
+}
+
+func (s *teamStorage) DeleteMemberInvitation(ctx context.Context, userID, companyID, invitationID string) error {
+	args := s.Called(ctx, userID, companyID, invitationID)
+	return args.Error(0)
+}
+
+func (s *teamStorage) UpdateMemberRole(ctx context.Context, userID, companyID, memberID, role string) error {
+	args := s.Called(ctx, userID, companyID, memberID, role)
+	return args.Error(0)
+}
+
+func (s *teamStorage) DeleteMember(ctx context.Context, userID, companyID, memberID string) error {
+	args := s.Called(ctx, userID, companyID, memberID)
+	return args.Error(0)
+}
+
+func (s *teamStorage) TransferCompany(ctx context.Context, userID, companyID, memberID string) error {
+	args := s.Called(ctx, userID, companyID, memberID)
+	return args.Error(0)
+}
+
+type teamNotifier struct {
+	mock.Mock
+}
+
+func (n *teamNotifier) Notify(ctx context.Context, event shared.NotificationEvent) error {
+	args := n.Called(ctx, event)
+	return args.Error(0)
+}
+
+// memberTeamStorage is the storage of a company where the user has role
+func memberTeamStorage(role string) *teamStorage {
+	storage := new(teamStorage)
+	storage.On("SelectCompanyRole", mock.Anything, "company-id", mock.Anything).Return(role, nil)
+	storage.On("SelectCompanyMembers", mock.Anything, "company-id").Return([]shared.Member{}, nil)
+	storage.On("SelectCompanyInvitations", mock.Anything, "company-id").Return([]shared.CompanyInvitation{}, nil)
+	return storage
+}
+
+func inviteInputFn(r *http.Request) (companies.InviteInput, error) {
+	return companies.InviteInput{CompanyID: "company-id", Invitee: "ana", Role: shared.RoleRecruiter}, nil
+}
+
+func memberInputFn(r *http.Request) (companies.MemberInput, error) {
+	return companies.MemberInput{CompanyID: "company-id", MemberID: "member-id", Role: shared.RoleViewer}, nil
+}
+
+func TestGetInviteInput(t *testing.T) {
+	req := webhookFormRequest(url.Values{"invitee": {" ana@mail.com "}, "role": {shared.RoleAdmin}})
+	req = WithUrlParam(req, "companyID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	input, err := companies.GetInviteInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if input.Invitee != "ana@mail.com" || input.Role != shared.RoleAdmin {
+		t.Errorf("unexpected input %+v", input)
+	}
+}
+
+func TestGetInviteInputOwnerRole(t *testing.T) {
+	req := webhookFormRequest(url.Values{"invitee": {"ana"}, "role": {shared.RoleOwner}})
+	req = WithUrlParam(req, "companyID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	input, err := companies.GetInviteInput(req)
+	if err != shared.ErrMemberRole {
+		t.Errorf("expected %v, got %v", shared.ErrMemberRole, err)
+	}
+	if input.Invitee != "ana" || input.CompanyID == "" {
+		t.Errorf("expected the form to be kept, got %+v", input)
+	}
+}
+
+func TestGetMemberRoleInput(t *testing.T) {
+	req := webhookFormRequest(url.Values{"role": {shared.RoleViewer}})
+	req = WithUrlParams(req, Params{
+		"companyID": "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		"memberID":  "not-a-uuid",
+	})
+	if _, err := companies.GetMemberRoleInput(req); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestTeamPageHandlerNotMember(t *testing.T) {
+	storage := new(teamStorage)
+	storage.On("GetCompanyByID", mock.Anything, "company-id").Return(shared.Company{ID: "company-id"}, nil)
+	storage.On("SelectCompanyRole", mock.Anything, "company-id", mock.Anything).Return("", nil)
+	inputFn := func(r *http.Request) (companies.CompanyPageInput, error) {
+		return companies.CompanyPageInput{CompanyID: "company-id"}, nil
+	}
+	handler := companies.CreateTeamPageHandler(&templates{}, authRepo{}, storage, inputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestTeamPageHandlerViewer(t *testing.T) {
+	storage := memberTeamStorage(shared.RoleViewer)
+	storage.On("GetCompanyByID", mock.Anything, "company-id").Return(shared.Company{ID: "company-id"}, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "teamPage", mock.MatchedBy(func(data companies.TeamPageData) bool {
+		return !data.Team.CanManage() && data.Team.Invitations == nil
+	})).Return(nil)
+	inputFn := func(r *http.Request) (companies.CompanyPageInput, error) {
+		return companies.CompanyPageInput{CompanyID: "company-id"}, nil
+	}
+	handler := companies.CreateTeamPageHandler(templ, authRepo{}, storage, inputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+	storage.AssertNotCalled(t, "SelectCompanyInvitations", mock.Anything, mock.Anything)
+}
+
+func TestInviteHandlerBadAuth(t *testing.T) {
+	handler := companies.CreateInviteHandler(&templates{}, invalidAuthRepo{}, new(teamStorage), new(teamNotifier), inviteInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestInviteHandlerBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (companies.InviteInput, error) {
+		return companies.InviteInput{}, fmt.Errorf("error")
+	}
+	handler := companies.CreateInviteHandler(&templates{}, authRepo{}, new(teamStorage), new(teamNotifier), invalidInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestInviteHandlerFormError(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (companies.InviteInput, error) {
+		return companies.InviteInput{CompanyID: "company-id", Invitee: "ana maria"}, shared.ErrInvitee
+	}
+	storage := memberTeamStorage(shared.RoleAdmin)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "team", mock.MatchedBy(func(data companies.TeamData) bool {
+		return data.Invitee == "ana maria" && !data.Alert.Ok && data.Alert.Msg == shared.ErrInvitee.Error()
+	})).Return(nil)
+	handler := companies.CreateInviteHandler(templ, authRepo{}, storage, new(teamNotifier), invalidInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+	storage.AssertNotCalled(t, "InviteMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInviteHandlerNotManager(t *testing.T) {
+	storage := memberTeamStorage(shared.RoleRecruiter)
+	storage.On("InviteMember", mock.Anything, mock.Anything, mock.Anything).Return(shared.ErrNotManager)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "team", mock.MatchedBy(func(data companies.TeamData) bool {
+		return !data.Alert.Ok && data.Alert.Msg == shared.ErrNotManager.Error()
+	})).Return(nil)
+	notifier := new(teamNotifier)
+	handler := companies.CreateInviteHandler(templ, authRepo{}, storage, notifier, inviteInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+	notifier.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+}
+
+func TestInviteHandlerBadStorage(t *testing.T) {
+	storage := memberTeamStorage(shared.RoleAdmin)
+	storage.On("InviteMember", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("error"))
+	handler := companies.CreateInviteHandler(&templates{}, authRepo{}, storage, new(teamNotifier), inviteInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestInviteHandler(t *testing.T) {
+	storage := memberTeamStorage(shared.RoleAdmin)
+	var invitationID string
+	storage.On("InviteMember", mock.Anything, mock.Anything, mock.MatchedBy(func(invitation shared.CompanyInvitation) bool {
+		invitationID = invitation.ID
+		return invitation.ID != "" &&
+			invitation.CompanyID == "company-id" &&
+			invitation.Invitee == "ana" &&
+			invitation.Role == shared.RoleRecruiter
+	})).Return(nil)
+	notifier := new(teamNotifier)
+	notifier.On("Notify", mock.Anything, mock.MatchedBy(func(event shared.NotificationEvent) bool {
+		return event.Category == shared.NotifyTeam && event.InvitationID == invitationID
+	})).Return(nil)
+	handler := companies.CreateInviteHandler(&templates{}, authRepo{}, storage, notifier, inviteInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
+
+func TestInvitationDeleteHandler(t *testing.T) {
+	storage := memberTeamStorage(shared.RoleOwner)
+	storage.On("DeleteMemberInvitation", mock.Anything, mock.Anything, "company-id", "invitation-id").Return(nil)
+	inputFn := func(r *http.Request) (companies.InvitationDeleteInput, error) {
+		return companies.InvitationDeleteInput{CompanyID: "company-id", InvitationID: "invitation-id"}, nil
+	}
+	handler := companies.CreateInvitationDeleteHandler(&templates{}, authRepo{}, storage, inputFn)
+	req, _ := http.NewRequest("DELETE", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+}
+
+func TestMemberRoleHandlerOwnerMember(t *testing.T) {
+	storage := memberTeamStorage(shared.RoleAdmin)
+	storage.On("UpdateMemberRole", mock.Anything, mock.Anything, "company-id", "member-id", shared.RoleViewer).Return(shared.ErrOwnerMember)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "team", mock.MatchedBy(func(data companies.TeamData) bool {
+		return !data.Alert.Ok && data.Alert.Msg == shared.ErrOwnerMember.Error()
+	})).Return(nil)
+	handler := companies.CreateMemberRoleHandler(templ, authRepo{}, storage, memberInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestMemberRoleHandler(t *testing.T) {
+	storage := memberTeamStorage(shared.RoleAdmin)
+	storage.On("UpdateMemberRole", mock.Anything, mock.Anything, "company-id", "member-id", shared.RoleViewer).Return(nil)
+	handler := companies.CreateMemberRoleHandler(&templates{}, authRepo{}, storage, memberInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+}
+
+func TestMemberDeleteHandler(t *testing.T) {
+	storage := memberTeamStorage(shared.RoleAdmin)
+	storage.On("DeleteMember", mock.Anything, mock.Anything, "company-id", "member-id").Return(nil)
+	handler := companies.CreateMemberDeleteHandler(&templates{}, authRepo{}, storage, memberInputFn, "/memberships")
+	req, _ := http.NewRequest("DELETE", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("HX-Redirect") != "" {
+		t.Error("expected the team to be shown again")
+	}
+}
+
+func TestMemberDeleteHandlerLeave(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "member-id"}, nil)
+	storage := new(teamStorage)
+	storage.On("DeleteMember", mock.Anything, "member-id", "company-id", "member-id").Return(nil)
+	handler := companies.CreateMemberDeleteHandler(&templates{}, authz, storage, memberInputFn, "/memberships")
+	req, _ := http.NewRequest("DELETE", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Header().Get("HX-Redirect") != "/memberships" {
+		t.Errorf("expected a redirect to the memberships, got %q", w.Header().Get("HX-Redirect"))
+	}
+	storage.AssertNotCalled(t, "SelectCompanyMembers", mock.Anything, mock.Anything)
+}
+
+func TestTransferHandlerNotOwner(t *testing.T) {
+	storage := memberTeamStorage(shared.RoleAdmin)
+	storage.On("TransferCompany", mock.Anything, mock.Anything, "company-id", "member-id").Return(shared.ErrNotOwner)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "team", mock.MatchedBy(func(data companies.TeamData) bool {
+		return !data.Alert.Ok && data.Alert.Msg == shared.ErrNotOwner.Error()
+	})).Return(nil)
+	handler := companies.CreateTransferHandler(templ, authRepo{}, storage, memberInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestTransferHandler(t *testing.T) {
+	storage := memberTeamStorage(shared.RoleAdmin)
+	storage.On("TransferCompany", mock.Anything, mock.Anything, "company-id", "member-id").Return(nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "team", mock.MatchedBy(func(data companies.TeamData) bool {
+		return data.Alert.Ok && data.Role == shared.RoleAdmin
+	})).Return(nil)
+	handler := companies.CreateTransferHandler(templ, authRepo{}, storage, memberInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+	templ.AssertExpectations(t)
+}
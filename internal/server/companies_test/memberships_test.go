@@ -0,0 +1,127 @@
+package companiestest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/companies"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type membershipsStorage struct {
+	mock.Mock
+}
+
+func (s *membershipsStorage) SelectUserInvitations(ctx context.Context, userID string) ([]shared.CompanyInvitation, error) {
+	args := s.Called(ctx, userID)
+	return args.Get(0).([]shared.CompanyInvitation), args.Error(1)
+}
+
+func (s *membershipsStorage) SelectUserMemberships(ctx context.Context, userID string) ([]shared.Membership, error) {
+	args := s.Called(ctx, userID)
+	return args.Get(0).([]shared.Membership), args.Error(1)
+}
+
+func (s *membershipsStorage) AnswerInvitation(ctx context.Context, userID, invitationID string, accept bool) error {
+	args := s.Called(ctx, userID, invitationID, accept)
+	return args.Error(0)
+}
+
+func userAuth() *authMock {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "user-id", Role: "user"}, nil)
+	return authz
+}
+
+func answerInputFn(r *http.Request) (companies.AnswerInput, error) {
+	return companies.AnswerInput{InvitationID: "invitation-id", Accept: true}, nil
+}
+
+func TestGetAnswerInput(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", nil)
+	req = WithUrlParams(req, Params{
+		"invitationID": "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		"answer":       "decline",
+	})
+	input, err := companies.GetAnswerInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if input.Accept {
+		t.Error("expected the invitation to be declined")
+	}
+	req = WithUrlParams(req, Params{
+		"invitationID": "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		"answer":       "maybe",
+	})
+	if _, err := companies.GetAnswerInput(req); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestMembershipsPageHandlerVisitor(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{Role: auth.NotAuthRole}, nil)
+	handler := companies.CreateMembershipsPageHandler(&templates{}, authz, new(membershipsStorage))
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestMembershipsPageHandler(t *testing.T) {
+	storage := new(membershipsStorage)
+	storage.On("SelectUserInvitations", mock.Anything, "user-id").Return([]shared.CompanyInvitation{{ID: "invitation-id"}}, nil)
+	storage.On("SelectUserMemberships", mock.Anything, "user-id").Return([]shared.Membership{}, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "membershipsPage", mock.MatchedBy(func(data companies.MembershipsPageData) bool {
+		return len(data.Memberships.Invitations) == 1
+	})).Return(nil)
+	handler := companies.CreateMembershipsPageHandler(templ, userAuth(), storage)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestAnswerHandlerBadStorage(t *testing.T) {
+	storage := new(membershipsStorage)
+	storage.On("AnswerInvitation", mock.Anything, "user-id", "invitation-id", true).Return(fmt.Errorf("error"))
+	handler := companies.CreateAnswerHandler(&templates{}, userAuth(), storage, answerInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestAnswerHandler(t *testing.T) {
+	storage := new(membershipsStorage)
+	storage.On("AnswerInvitation", mock.Anything, "user-id", "invitation-id", true).Return(nil)
+	storage.On("SelectUserInvitations", mock.Anything, "user-id").Return([]shared.CompanyInvitation{}, nil)
+	storage.On("SelectUserMemberships", mock.Anything, "user-id").Return([]shared.Membership{{CompanyID: "company-id"}}, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "memberships", mock.MatchedBy(func(data companies.MembershipsData) bool {
+		return data.Alert.Ok && len(data.Memberships) == 1
+	})).Return(nil)
+	handler := companies.CreateAnswerHandler(templ, userAuth(), storage, answerInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+	templ.AssertExpectations(t)
+}
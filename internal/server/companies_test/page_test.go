@@ -7,6 +7,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/kw3a/spotted-server/internal/auth"
 	"github.com/kw3a/spotted-server/internal/server/companies"
 	"github.com/kw3a/spotted-server/internal/server/shared"
 	"github.com/stretchr/testify/mock"
@@ -25,6 +26,16 @@ func (s *pageStorage) SelectOffers(ctx context.Context, params shared.OfferQuery
 	return args.Get(0).([]shared.Offer), args.Error(1)
 }
 
+func (s *pageStorage) SelectCompanyPipeline(ctx context.Context, companyID string) (shared.CompanyPipeline, error) {
+	args := s.Called(ctx, companyID)
+	return args.Get(0).(shared.CompanyPipeline), args.Error(1)
+}
+
+func (s *pageStorage) SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error) {
+	args := s.Called(ctx, companyID, userID)
+	return args.String(0), args.Error(1)
+}
+
 func pageInputFn(r *http.Request) (companies.CompanyPageInput, error) {
 	return companies.CompanyPageInput{}, nil
 }
@@ -104,3 +115,57 @@ func TestPageHandler(t *testing.T) {
 		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
 	}
 }
+
+func TestPageHandlerOwnerStages(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "owner-id"}, nil)
+	storage := new(pageStorage)
+	storage.On("GetCompanyByID", mock.Anything, mock.Anything).Return(shared.Company{ID: "company-id", UserID: "owner-id"}, nil)
+	storage.On("SelectOffers", mock.Anything, mock.Anything).Return([]shared.Offer{}, nil)
+	storage.On("SelectCompanyRole", mock.Anything, "company-id", "owner-id").Return(shared.RoleOwner, nil)
+	storage.On("SelectCompanyPipeline", mock.Anything, "company-id").Return(shared.CompanyPipeline{}, fmt.Errorf("error"))
+	handler := companies.CreateCompanyPageHandler(&templates{}, authz, storage, pageInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestPageHandlerBadStorageRole(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "member-id"}, nil)
+	storage := new(pageStorage)
+	storage.On("GetCompanyByID", mock.Anything, mock.Anything).Return(shared.Company{ID: "company-id"}, nil)
+	storage.On("SelectOffers", mock.Anything, mock.Anything).Return([]shared.Offer{}, nil)
+	storage.On("SelectCompanyRole", mock.Anything, "company-id", "member-id").Return("", fmt.Errorf("error"))
+	handler := companies.CreateCompanyPageHandler(&templates{}, authz, storage, pageInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestPageHandlerViewerWithoutStages(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "member-id"}, nil)
+	storage := new(pageStorage)
+	storage.On("GetCompanyByID", mock.Anything, mock.Anything).Return(shared.Company{ID: "company-id"}, nil)
+	storage.On("SelectOffers", mock.Anything, mock.Anything).Return([]shared.Offer{}, nil)
+	storage.On("SelectCompanyRole", mock.Anything, "company-id", "member-id").Return(shared.RoleViewer, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "companyPage", mock.MatchedBy(func(data companies.CompanyPageData) bool {
+		return data.Role == shared.RoleViewer && data.Stages == nil
+	})).Return(nil)
+	handler := companies.CreateCompanyPageHandler(templ, authz, storage, pageInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
@@ -0,0 +1,141 @@
+package companiestest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/companies"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type stagesStorage struct {
+	mock.Mock
+}
+
+func (s *stagesStorage) UpdateCompanyPipeline(ctx context.Context, userID, companyID string, pipeline shared.CompanyPipeline) error {
+	args := s.Called(ctx, userID, companyID, pipeline)
+	return args.Error(0)
+}
+
+func stagesInputFn(r *http.Request) (companies.StagesInput, error) {
+	return companies.StagesInput{
+		CompanyID: "company-id",
+		Pipeline:  shared.CompanyPipeline{Stages: shared.DefaultStages, ShareStage: true},
+	}, nil
+}
+
+func TestGetStagesInput(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{"stages": {"Nuevo\n\nEntrevista técnica\nContratado"}, "shareStage": {"on"}}
+	req = WithUrlParam(req, "companyID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	input, err := companies.GetStagesInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if len(input.Pipeline.Stages) != 3 || input.Pipeline.Stages[1].Name != "entrevista-tecnica" {
+		t.Errorf("unexpected stages %v", input.Pipeline.Stages)
+	}
+	if !input.Pipeline.ShareStage {
+		t.Error("expected the stage to be shared")
+	}
+}
+
+func TestGetStagesInputDuplicate(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{"stages": {"Nuevo\nnuevo"}}
+	req = WithUrlParam(req, "companyID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	input, err := companies.GetStagesInput(req)
+	if err != shared.ErrStageDuplicate {
+		t.Errorf("expected %v, got %v", shared.ErrStageDuplicate, err)
+	}
+	if input.Text != "Nuevo\nnuevo" {
+		t.Errorf("expected the text to be kept, got %q", input.Text)
+	}
+}
+
+func TestStagesHandlerBadAuth(t *testing.T) {
+	handler := companies.CreateStagesHandler(&templates{}, invalidAuthRepo{}, new(stagesStorage), stagesInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestStagesHandlerBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (companies.StagesInput, error) {
+		return companies.StagesInput{}, fmt.Errorf("error")
+	}
+	handler := companies.CreateStagesHandler(&templates{}, authRepo{}, new(stagesStorage), invalidInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestStagesHandlerFormError(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (companies.StagesInput, error) {
+		return companies.StagesInput{CompanyID: "company-id", Text: "Nuevo"}, shared.ErrStageCount
+	}
+	storage := new(stagesStorage)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "companyStages", companies.CompanyStagesData{
+		CompanyID: "company-id",
+		Text:      "Nuevo",
+		Alert:     shared.Alert{Ok: false, Msg: shared.ErrStageCount.Error()},
+	}).Return(nil)
+	handler := companies.CreateStagesHandler(templ, authRepo{}, storage, invalidInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+	storage.AssertNotCalled(t, "UpdateCompanyPipeline", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestStagesHandlerBadStorage(t *testing.T) {
+	storage := new(stagesStorage)
+	storage.On("UpdateCompanyPipeline", mock.Anything, mock.Anything, "company-id", mock.Anything).Return(fmt.Errorf("error"))
+	handler := companies.CreateStagesHandler(&templates{}, authRepo{}, storage, stagesInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestStagesHandlerBadTemplate(t *testing.T) {
+	storage := new(stagesStorage)
+	storage.On("UpdateCompanyPipeline", mock.Anything, mock.Anything, "company-id", mock.Anything).Return(nil)
+	handler := companies.CreateStagesHandler(&invalidTemplates{}, authRepo{}, storage, stagesInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestStagesHandler(t *testing.T) {
+	storage := new(stagesStorage)
+	storage.On("UpdateCompanyPipeline", mock.Anything, mock.Anything, "company-id",
+		shared.CompanyPipeline{Stages: shared.DefaultStages, ShareStage: true}).Return(nil)
+	handler := companies.CreateStagesHandler(&templates{}, authRepo{}, storage, stagesInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+}
@@ -0,0 +1,278 @@
+package companiestest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/companies"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type webhooksStorage struct {
+	mock.Mock
+}
+
+func (s *webhooksStorage) GetCompany(ctx context.Context, companyID, userID string) (shared.Company, error) {
+	args := s.Called(ctx, companyID, userID)
+	return args.Get(0).(shared.Company), args.Error(1)
+}
+
+func (s *webhooksStorage) SelectWebhooks(ctx context.Context, companyID string) ([]shared.Webhook, error) {
+	args := s.Called(ctx, companyID)
+	return args.Get(0).([]shared.Webhook), args.Error(1)
+}
+
+func (s *webhooksStorage) SelectWebhookDeliveries(ctx context.Context, companyID string) ([]shared.WebhookDelivery, error) {
+	args := s.Called(ctx, companyID)
+	return args.Get(0).([]shared.WebhookDelivery), args.Error(1)
+}
+
+func (s *webhooksStorage) InsertWebhook(ctx context.Context, webhook shared.Webhook) error {
+	args := s.Called(ctx, webhook)
+	return args.Error(0)
+}
+
+func (s *webhooksStorage) DeleteWebhook(ctx context.Context, companyID, webhookID string) error {
+	args := s.Called(ctx, companyID, webhookID)
+	return args.Error(0)
+}
+
+func (s *webhooksStorage) RedeliverWebhook(ctx context.Context, companyID, deliveryID string) error {
+	args := s.Called(ctx, companyID, deliveryID)
+	return args.Error(0)
+}
+
+// ownedWebhooksStorage is the storage of a company the user owns, with no
+// webhooks yet
+func ownedWebhooksStorage() *webhooksStorage {
+	storage := new(webhooksStorage)
+	storage.On("GetCompany", mock.Anything, "company-id", mock.Anything).Return(shared.Company{ID: "company-id"}, nil)
+	storage.On("SelectWebhooks", mock.Anything, "company-id").Return([]shared.Webhook{}, nil)
+	storage.On("SelectWebhookDeliveries", mock.Anything, "company-id").Return([]shared.WebhookDelivery{}, nil)
+	return storage
+}
+
+func webhookInputFn(r *http.Request) (companies.WebhookInput, error) {
+	return companies.WebhookInput{
+		CompanyID: "company-id",
+		URL:       "https://ats.example.com/spotted",
+		Events:    []string{shared.EventParticipationFinished},
+	}, nil
+}
+
+func secretFn() (string, error) {
+	return "secret", nil
+}
+
+func webhookFormRequest(form url.Values) *http.Request {
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestGetWebhookInput(t *testing.T) {
+	req := webhookFormRequest(url.Values{
+		"url":    {" https://ats.example.com/spotted "},
+		"events": {shared.EventOfferArchived, shared.EventParticipationStarted},
+	})
+	req = WithUrlParam(req, "companyID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	input, err := companies.GetWebhookInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if input.URL != "https://ats.example.com/spotted" {
+		t.Errorf("unexpected url %q", input.URL)
+	}
+	if len(input.Events) != 2 || input.Events[0] != shared.EventParticipationStarted {
+		t.Errorf("unexpected events %v", input.Events)
+	}
+}
+
+func TestGetWebhookInputBadURL(t *testing.T) {
+	req := webhookFormRequest(url.Values{
+		"url":    {"http://ats.example.com/spotted"},
+		"events": {shared.EventOfferArchived},
+	})
+	req = WithUrlParam(req, "companyID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	input, err := companies.GetWebhookInput(req)
+	if err != shared.ErrWebhookURL {
+		t.Errorf("expected %v, got %v", shared.ErrWebhookURL, err)
+	}
+	if input.URL != "http://ats.example.com/spotted" || input.CompanyID == "" {
+		t.Errorf("expected the form to be kept, got %+v", input)
+	}
+}
+
+func TestGetRedeliverInput(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", nil)
+	req = WithUrlParams(req, Params{
+		"companyID":  "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		"deliveryID": "not-a-uuid",
+	})
+	if _, err := companies.GetRedeliverInput(req); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestWebhooksPageHandlerForeignCompany(t *testing.T) {
+	storage := new(webhooksStorage)
+	storage.On("GetCompany", mock.Anything, mock.Anything, mock.Anything).Return(shared.Company{}, fmt.Errorf("error"))
+	inputFn := func(r *http.Request) (companies.CompanyPageInput, error) {
+		return companies.CompanyPageInput{CompanyID: "company-id"}, nil
+	}
+	handler := companies.CreateWebhooksPageHandler(&templates{}, authRepo{}, storage, inputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestWebhooksPageHandler(t *testing.T) {
+	storage := ownedWebhooksStorage()
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "webhooksPage", mock.Anything).Return(nil)
+	inputFn := func(r *http.Request) (companies.CompanyPageInput, error) {
+		return companies.CompanyPageInput{CompanyID: "company-id"}, nil
+	}
+	handler := companies.CreateWebhooksPageHandler(templ, authRepo{}, storage, inputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestWebhookHandlerBadAuth(t *testing.T) {
+	handler := companies.CreateWebhookHandler(&templates{}, invalidAuthRepo{}, new(webhooksStorage), secretFn, webhookInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestWebhookHandlerBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (companies.WebhookInput, error) {
+		return companies.WebhookInput{}, fmt.Errorf("error")
+	}
+	handler := companies.CreateWebhookHandler(&templates{}, authRepo{}, new(webhooksStorage), secretFn, invalidInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestWebhookHandlerForeignCompany(t *testing.T) {
+	storage := new(webhooksStorage)
+	storage.On("GetCompany", mock.Anything, "company-id", mock.Anything).Return(shared.Company{}, fmt.Errorf("error"))
+	handler := companies.CreateWebhookHandler(&templates{}, authRepo{}, storage, secretFn, webhookInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	storage.AssertNotCalled(t, "InsertWebhook", mock.Anything, mock.Anything)
+}
+
+func TestWebhookHandlerFormError(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (companies.WebhookInput, error) {
+		return companies.WebhookInput{CompanyID: "company-id", URL: "http://ats.example.com"}, shared.ErrWebhookURL
+	}
+	storage := ownedWebhooksStorage()
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "webhooks", mock.MatchedBy(func(data companies.WebhooksData) bool {
+		return data.URL == "http://ats.example.com" && !data.Alert.Ok && data.Alert.Msg == shared.ErrWebhookURL.Error()
+	})).Return(nil)
+	handler := companies.CreateWebhookHandler(templ, authRepo{}, storage, secretFn, invalidInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+	storage.AssertNotCalled(t, "InsertWebhook", mock.Anything, mock.Anything)
+}
+
+func TestWebhookHandler(t *testing.T) {
+	storage := ownedWebhooksStorage()
+	storage.On("InsertWebhook", mock.Anything, mock.MatchedBy(func(webhook shared.Webhook) bool {
+		return webhook.ID != "" &&
+			webhook.CompanyID == "company-id" &&
+			webhook.URL == "https://ats.example.com/spotted" &&
+			webhook.Secret == "secret" &&
+			webhook.Subscribed(shared.EventParticipationFinished)
+	})).Return(nil)
+	handler := companies.CreateWebhookHandler(&templates{}, authRepo{}, storage, secretFn, webhookInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+}
+
+func TestWebhookDeleteHandler(t *testing.T) {
+	storage := ownedWebhooksStorage()
+	storage.On("DeleteWebhook", mock.Anything, "company-id", "webhook-id").Return(nil)
+	inputFn := func(r *http.Request) (companies.WebhookDeleteInput, error) {
+		return companies.WebhookDeleteInput{CompanyID: "company-id", WebhookID: "webhook-id"}, nil
+	}
+	handler := companies.CreateWebhookDeleteHandler(&templates{}, authRepo{}, storage, inputFn)
+	req, _ := http.NewRequest("DELETE", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+}
+
+func redeliverInputFn(r *http.Request) (companies.RedeliverInput, error) {
+	return companies.RedeliverInput{CompanyID: "company-id", DeliveryID: "delivery-id"}, nil
+}
+
+func TestRedeliverHandlerBadStorage(t *testing.T) {
+	storage := ownedWebhooksStorage()
+	storage.On("RedeliverWebhook", mock.Anything, "company-id", "delivery-id").Return(fmt.Errorf("error"))
+	handler := companies.CreateRedeliverHandler(&templates{}, authRepo{}, storage, redeliverInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestRedeliverHandler(t *testing.T) {
+	storage := ownedWebhooksStorage()
+	storage.On("RedeliverWebhook", mock.Anything, "company-id", "delivery-id").Return(nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "webhooks", mock.MatchedBy(func(data companies.WebhooksData) bool {
+		return data.CompanyID == "company-id" && data.Alert.Ok && len(data.Options) == len(shared.WebhookEvents)
+	})).Return(nil)
+	handler := companies.CreateRedeliverHandler(templ, authRepo{}, storage, redeliverInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+	templ.AssertExpectations(t)
+}
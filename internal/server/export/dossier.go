@@ -0,0 +1,198 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// smdThreshold is where the keystroke report starts flagging a window, the
+// same line the recruiter sees in the page
+const smdThreshold = 1.2
+
+var (
+	green  = Color{56, 142, 60}
+	red    = Color{211, 47, 47}
+	orange = Color{255, 152, 0}
+	blue   = Color{0, 123, 255}
+)
+
+// SMDPoint is a keystroke window compared with the profile window of the
+// candidate, the one with most strokes
+type SMDPoint struct {
+	SMD      float64
+	Strokes  int32
+	Profile  bool
+	Inactive bool
+}
+
+// Dossier is everything the report shows about a single applicant
+type Dossier struct {
+	Offer       shared.Offer
+	Application shared.Application
+	Keystrokes  []SMDPoint
+	GeneratedAt time.Time
+}
+
+func WriteDossier(w io.Writer, dossier Dossier) error {
+	application := dossier.Application
+	d := NewDocument(application.Applicant.Name + " - " + dossier.Offer.Title)
+	d.Text(Margin, d.Y+18, HelveticaBold, 18, Black, application.Applicant.Name)
+	d.Y += 26
+	d.Paragraph(Helvetica, 10, Gray, dossier.Offer.Title+" · "+dossier.Offer.CompanyName)
+	d.Paragraph(Helvetica, 8, Gray, "Generado el "+dossier.GeneratedAt.Format("02/01/2006 15:04"))
+	d.Line(Margin, d.Y+4, Margin+ContentWidth, d.Y+4, 0.5, Light, false)
+	d.Y += 10
+
+	writeProfile(d, application)
+	for _, summary := range application.Summary {
+		writeSummary(d, summary)
+	}
+	writeKeystrokes(d, dossier.Keystrokes)
+	_, err := d.WriteTo(w)
+	return err
+}
+
+func writeProfile(d *Document, application shared.Application) {
+	participation := application.Participation
+	pipeline := application.Pipeline
+	d.Heading("Perfil", 12)
+	d.Field("Correo", application.Applicant.Email)
+	d.Field("Celular", application.Applicant.Cell)
+	d.Field("Descripción", application.Applicant.Description)
+	d.Heading("Postulación", 12)
+	d.Field("Iniciada", participation.CreatedAt.Format("02/01/2006 15:04"))
+	status := "En curso"
+	if participation.EndReason != "" {
+		status = participation.EndReasonLabel()
+	}
+	d.Field("Estado", status)
+	if pipeline.Withdrawn() {
+		d.Field("Retirada", pipeline.WithdrawnAt.Format("02/01/2006 15:04"))
+	}
+	d.Field("Etapa", pipeline.StageLabel())
+	d.Field("Calificación", fmt.Sprintf("%d/%d", pipeline.Rating, shared.MaxStars))
+	d.Field("Etiquetas", strings.Join(pipeline.Tags, ", "))
+	if len(application.Problems) > 0 {
+		d.Field("Puntaje", fmt.Sprintf("%d%% de %d problemas", application.NormalizedScore(), len(application.Problems)))
+	}
+	if len(application.Proctoring) > 0 {
+		counts := make([]string, 0, len(application.Proctoring))
+		for _, count := range application.Proctoring {
+			counts = append(counts, fmt.Sprintf("%s: %d", count.Label, count.Count))
+		}
+		d.Field("Supervisión", strings.Join(counts, ", "))
+	}
+	for _, note := range pipeline.Notes {
+		d.Field("Nota de "+note.RecruiterName, note.Body)
+	}
+}
+
+func writeSummary(d *Document, summary shared.Summary) {
+	score := fmt.Sprintf("%d/%d", summary.Score.AcceptedTestCases, summary.Score.TotalTestCases)
+	if summary.Question != nil && !summary.Response.Graded {
+		score = "Por calificar"
+	}
+	d.Heading(summary.Title+"  ("+score+")", 12)
+	if summary.Question != nil {
+		d.Field("Tipo", summary.Question.Label())
+		d.Field("Respuesta", summary.Response.Answer)
+		if summary.Response.Feedback != "" {
+			d.Field("Comentario", summary.Response.Feedback)
+		}
+		return
+	}
+	if summary.Submission.ID == "" {
+		d.Paragraph(Helvetica, 9, Gray, "Sin envíos")
+		return
+	}
+	d.Paragraph(Helvetica, 8, Gray, summary.Submission.Language)
+	d.Code(summary.Submission.Src, summary.Submission.LanguageID)
+	if len(summary.Results) == 0 {
+		return
+	}
+	rows := make([][]string, 0, len(summary.Results))
+	for i, result := range summary.Results {
+		rows = append(rows, []string{
+			fmt.Sprintf("TC %d", i+1),
+			result.Status,
+			fmt.Sprintf("%d ms", result.Time),
+			fmt.Sprintf("%d KB", result.Memory),
+		})
+	}
+	d.Table([]string{"Caso", "Estado", "Tiempo", "Memoria"}, []float64{70, 245, 100, 100}, rows)
+}
+
+// writeKeystrokes draws the SMD of every window like the report of the
+// page: the profile window in a square, inactive windows in gray and the
+// rest red above the threshold
+func writeKeystrokes(d *Document, points []SMDPoint) {
+	const height, left, bottom = 180.0, 30.0, 20.0
+	d.Heading("Dinámica de pulsaciones", 12)
+	if len(points) < 2 {
+		d.Paragraph(Helvetica, 9, Gray, "No hay suficientes datos para generar un reporte.")
+		return
+	}
+	d.Ensure(height + bottom + 30)
+	top := d.Y
+	width := ContentWidth - left
+	maxY := smdThreshold * 1.5
+	for _, p := range points {
+		maxY = math.Max(maxY, p.SMD*1.1)
+	}
+	toY := func(smd float64) float64 {
+		return top + height*(1-smd/maxY)
+	}
+	step := width / float64(len(points))
+	toX := func(i int) float64 {
+		return Margin + left + step*(float64(i)+0.5)
+	}
+	for i := 0; i <= int(maxY); i++ {
+		y := toY(float64(i))
+		d.Line(Margin+left, y, Margin+ContentWidth, y, 0.3, Light, false)
+		d.Text(Margin+left-12, y+3, Helvetica, 7, Gray, fmt.Sprint(i))
+	}
+	d.Line(Margin+left, toY(smdThreshold), Margin+ContentWidth, toY(smdThreshold), 1, orange, true)
+	d.Text(Margin+left+4, top+9, HelveticaBold, 7, red, "Sospechoso")
+	d.Text(Margin+left+4, top+height-4, HelveticaBold, 7, green, "Legítimo")
+
+	line := [][2]float64{}
+	for i, p := range points {
+		if !p.Inactive {
+			line = append(line, [2]float64{toX(i), toY(p.SMD)})
+		}
+	}
+	d.Polyline(line, 1.2, blue)
+	// labels every few windows so they don't overlap
+	every := int(math.Ceil(float64(len(points)) * 22 / width))
+	for i, p := range points {
+		x, y := toX(i), toY(p.SMD)
+		switch {
+		case p.Profile:
+			d.Rect(x-3.5, y-3.5, 7, 7, green)
+		case p.Inactive:
+			d.Circle(x, toY(0), 3, Color{153, 153, 153})
+		case p.SMD > smdThreshold:
+			d.Circle(x, y, 3, red)
+		default:
+			d.Circle(x, y, 3, green)
+		}
+		if i%every == 0 {
+			d.Text(x-6, top+height+12, Helvetica, 6.5, Gray, fmt.Sprintf("W%d", i+1))
+		}
+	}
+	d.Y = top + height + bottom
+	suspicious := 0
+	for _, p := range points {
+		if !p.Profile && !p.Inactive && p.SMD > smdThreshold {
+			suspicious++
+		}
+	}
+	d.Paragraph(Helvetica, 8, Gray, fmt.Sprintf(
+		"%d ventanas, %d sobre el umbral de %.1f. Las ventanas con menos de 10 teclas se consideran inactivas.",
+		len(points), suspicious, smdThreshold))
+}
@@ -0,0 +1,130 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kw3a/spotted-server/internal/server/similarity"
+)
+
+type SpanKind int
+
+const (
+	PlainSpan SpanKind = iota
+	KeywordSpan
+	StringSpan
+	NumberSpan
+	CommentSpan
+)
+
+// Span is a piece of a source line drawn in the color of its kind
+type Span struct {
+	Text string
+	Kind SpanKind
+}
+
+var spanColors = map[SpanKind]Color{
+	PlainSpan:   {36, 41, 46},
+	KeywordSpan: {215, 58, 73},
+	StringSpan:  {3, 47, 98},
+	NumberSpan:  {0, 92, 197},
+	CommentSpan: {106, 115, 125},
+}
+
+// Highlight splits the source in lines of spans. It reuses the tokenizer of
+// the similarity check, whatever it skips between tokens is whitespace or a
+// comment.
+func Highlight(src string, languageID int32) [][]Span {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	lines := [][]Span{{}}
+	emit := func(text string, kind SpanKind) {
+		for i, part := range strings.Split(text, "\n") {
+			if i > 0 {
+				lines = append(lines, []Span{})
+			}
+			if part != "" {
+				last := len(lines) - 1
+				lines[last] = append(lines[last], Span{Text: part, Kind: kind})
+			}
+		}
+	}
+	gap := func(text string) {
+		if strings.TrimSpace(text) == "" {
+			emit(text, PlainSpan)
+			return
+		}
+		emit(text, CommentSpan)
+	}
+	pos := 0
+	for _, token := range similarity.Tokenize(src, languageID) {
+		gap(src[pos:token.Start])
+		text := src[token.Start:token.End]
+		switch {
+		case token.Value == similarity.StringToken:
+			emit(text, StringSpan)
+		case token.Value == similarity.NumberToken:
+			emit(text, NumberSpan)
+		case similarity.IsKeyword(token.Value):
+			emit(text, KeywordSpan)
+		default:
+			emit(text, PlainSpan)
+		}
+		pos = token.End
+	}
+	gap(src[pos:])
+	for len(lines) > 1 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Code writes the listing in a shaded box with line numbers, lines wider
+// than the page continue below without a number
+func (d *Document) Code(src string, languageID int32) {
+	const size, lineHeight, gutter = 7.5, 9.5, 26.0
+	charWidth := TextWidth(Courier, size, " ")
+	perLine := int((ContentWidth - gutter - 6) / charWidth)
+	for number, line := range Highlight(src, languageID) {
+		rows := wrapSpans(line, perLine)
+		for i, row := range rows {
+			d.Ensure(lineHeight)
+			d.Rect(Margin, d.Y, ContentWidth, lineHeight, Color{246, 248, 250})
+			if i == 0 {
+				d.Text(Margin+2, d.Y+7.5, Courier, size, Gray, fmt.Sprintf("%4d", number+1))
+			}
+			x := Margin + gutter
+			for _, span := range row {
+				d.Text(x, d.Y+7.5, Courier, size, spanColors[span.Kind], span.Text)
+				x += float64(len([]rune(expandTabs(span.Text)))) * charWidth
+			}
+			d.Y += lineHeight
+		}
+	}
+	d.Y += 6
+}
+
+// wrapSpans cuts a line in rows of at most width characters keeping the
+// kind of every piece
+func wrapSpans(line []Span, width int) [][]Span {
+	rows := [][]Span{{}}
+	used := 0
+	for _, span := range line {
+		text := []rune(expandTabs(span.Text))
+		for len(text) > 0 {
+			if used == width {
+				rows = append(rows, []Span{})
+				used = 0
+			}
+			n := min(len(text), width-used)
+			last := len(rows) - 1
+			rows[last] = append(rows[last], Span{Text: string(text[:n]), Kind: span.Kind})
+			text = text[n:]
+			used += n
+		}
+	}
+	return rows
+}
+
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
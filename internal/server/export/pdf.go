@@ -0,0 +1,378 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// A4 in points, positions given to Document are measured from the top left
+// corner and converted to the bottom left origin of PDF when written
+const (
+	PageWidth    = 595.28
+	PageHeight   = 841.89
+	Margin       = 40.0
+	ContentWidth = PageWidth - 2*Margin
+)
+
+// Font is one of the standard PDF fonts, every viewer has them so nothing
+// is embedded
+type Font int
+
+const (
+	Helvetica Font = iota
+	HelveticaBold
+	Courier
+)
+
+var fontNames = [...]string{"Helvetica", "Helvetica-Bold", "Courier"}
+
+type Color struct {
+	R, G, B uint8
+}
+
+var (
+	Black = Color{33, 37, 41}
+	Gray  = Color{108, 117, 125}
+	Light = Color{233, 236, 239}
+)
+
+// Document is a minimal PDF writer for A4 reports: text in the standard
+// fonts, lines and filled shapes, enough for tables, code listings and
+// charts without an external service
+type Document struct {
+	Title string
+	// Y is the top of the next block in the current page
+	Y     float64
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+}
+
+func NewDocument(title string) *Document {
+	d := &Document{Title: title}
+	d.AddPage()
+	return d
+}
+
+func (d *Document) AddPage() {
+	d.page = new(bytes.Buffer)
+	d.pages = append(d.pages, d.page)
+	d.Y = Margin
+}
+
+func (d *Document) Pages() int {
+	return len(d.pages)
+}
+
+// Ensure starts a new page when a block of height doesn't fit below Y
+func (d *Document) Ensure(height float64) {
+	if d.Y+height > PageHeight-Margin {
+		d.AddPage()
+	}
+}
+
+// Text writes s with its baseline at y
+func (d *Document) Text(x, y float64, font Font, size float64, c Color, s string) {
+	fmt.Fprintf(d.page, "BT /F%d %.2f Tf %s rg %.2f %.2f Td (%s) Tj ET\n",
+		font+1, size, rgb(c), x, PageHeight-y, escape(winAnsi(s)))
+}
+
+func (d *Document) Line(x1, y1, x2, y2, width float64, c Color, dashed bool) {
+	if dashed {
+		d.page.WriteString("[4 3] 0 d\n")
+	}
+	fmt.Fprintf(d.page, "%s RG %.2f w %.2f %.2f m %.2f %.2f l S\n",
+		rgb(c), width, x1, PageHeight-y1, x2, PageHeight-y2)
+	if dashed {
+		d.page.WriteString("[] 0 d\n")
+	}
+}
+
+// Polyline joins the points, given as x, y pairs
+func (d *Document) Polyline(points [][2]float64, width float64, c Color) {
+	if len(points) < 2 {
+		return
+	}
+	fmt.Fprintf(d.page, "%s RG %.2f w %.2f %.2f m", rgb(c), width, points[0][0], PageHeight-points[0][1])
+	for _, p := range points[1:] {
+		fmt.Fprintf(d.page, " %.2f %.2f l", p[0], PageHeight-p[1])
+	}
+	d.page.WriteString(" S\n")
+}
+
+func (d *Document) Rect(x, y, w, h float64, fill Color) {
+	fmt.Fprintf(d.page, "%s rg %.2f %.2f %.2f %.2f re f\n", rgb(fill), x, PageHeight-y-h, w, h)
+}
+
+// Circle approximates the circle centered in x, y with four Bézier curves
+func (d *Document) Circle(x, y, r float64, fill Color) {
+	const k = 0.5523
+	y = PageHeight - y
+	fmt.Fprintf(d.page, "%s rg %.2f %.2f m ", rgb(fill), x+r, y)
+	fmt.Fprintf(d.page, "%.2f %.2f %.2f %.2f %.2f %.2f c ", x+r, y+k*r, x+k*r, y+r, x, y+r)
+	fmt.Fprintf(d.page, "%.2f %.2f %.2f %.2f %.2f %.2f c ", x-k*r, y+r, x-r, y+k*r, x-r, y)
+	fmt.Fprintf(d.page, "%.2f %.2f %.2f %.2f %.2f %.2f c ", x-r, y-k*r, x-k*r, y-r, x, y-r)
+	fmt.Fprintf(d.page, "%.2f %.2f %.2f %.2f %.2f %.2f c f\n", x+k*r, y-r, x+r, y-k*r, x+r, y)
+}
+
+// Paragraph writes the text wrapped to the content width, moving Y below it
+func (d *Document) Paragraph(font Font, size float64, c Color, text string) {
+	lineHeight := size * 1.35
+	for _, line := range Wrap(font, size, ContentWidth, text) {
+		d.Ensure(lineHeight)
+		d.Text(Margin, d.Y+size, font, size, c, line)
+		d.Y += lineHeight
+	}
+}
+
+func (d *Document) Heading(text string, size float64) {
+	d.Ensure(size * 3)
+	d.Y += size * 0.6
+	d.Text(Margin, d.Y+size, HelveticaBold, size, Black, text)
+	d.Y += size * 1.5
+}
+
+// Field writes a label and its value in one line, long values wrap below
+// the value column
+func (d *Document) Field(label, value string) {
+	const size, labelWidth = 9.0, 110.0
+	lines := Wrap(Helvetica, size, ContentWidth-labelWidth, value)
+	if len(lines) == 0 {
+		lines = []string{"-"}
+	}
+	for i, line := range lines {
+		d.Ensure(size * 1.4)
+		if i == 0 {
+			d.Text(Margin, d.Y+size, HelveticaBold, size, Gray, label)
+		}
+		d.Text(Margin+labelWidth, d.Y+size, Helvetica, size, Black, line)
+		d.Y += size * 1.4
+	}
+}
+
+// Table writes a header row and the rows with the given column widths, the
+// header is repeated on every page the table spans
+func (d *Document) Table(header []string, widths []float64, rows [][]string) {
+	const size, rowHeight = 8.0, 13.0
+	drawHeader := func() {
+		d.Rect(Margin, d.Y, ContentWidth, rowHeight, Light)
+		x := Margin
+		for i, h := range header {
+			d.Text(x+3, d.Y+9.5, HelveticaBold, size, Black, h)
+			x += widths[i]
+		}
+		d.Y += rowHeight
+	}
+	d.Ensure(rowHeight * 2)
+	drawHeader()
+	for _, row := range rows {
+		if d.Y+rowHeight > PageHeight-Margin {
+			d.AddPage()
+			drawHeader()
+		}
+		x := Margin
+		for i, cell := range row {
+			d.Text(x+3, d.Y+9.5, Helvetica, size, Black, Truncate(Helvetica, size, widths[i]-6, cell))
+			x += widths[i]
+		}
+		d.Line(Margin, d.Y+rowHeight, Margin+ContentWidth, d.Y+rowHeight, 0.3, Light, false)
+		d.Y += rowHeight
+	}
+	d.Y += 4
+}
+
+// WriteTo writes the document with its content streams compressed
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	var out bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// 1 catalog, 2 page tree, 3 info, 4-6 fonts, then a page and its
+	// content for every page
+	const firstPage = 7
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object(fmt.Sprintf("<< /Title (%s) >>", escape(winAnsi(d.Title))))
+	for _, name := range fontNames {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", name))
+	}
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] "+
+			"/Resources << /Font << /F1 4 0 R /F2 5 0 R /F3 6 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, firstPage+2*i+1))
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.Bytes()); err != nil {
+			return 0, err
+		}
+		if err := zw.Close(); err != nil {
+			return 0, err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream",
+			compressed.Len(), compressed.String()))
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n",
+		len(offsets)+1, xref)
+	return out.WriteTo(w)
+}
+
+func rgb(c Color) string {
+	return fmt.Sprintf("%.3f %.3f %.3f", float64(c.R)/255, float64(c.G)/255, float64(c.B)/255)
+}
+
+// winAnsiExtra are the characters of WinAnsiEncoding outside Latin-1
+var winAnsiExtra = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87,
+	'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a, '‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91,
+	'’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97, '˜': 0x98,
+	'™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// winAnsi encodes s for the standard fonts, characters they lack become '?'
+func winAnsi(s string) []byte {
+	res := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '\t':
+			res = append(res, ' ')
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			res = append(res, byte(r))
+		default:
+			if b, ok := winAnsiExtra[r]; ok {
+				res = append(res, b)
+			} else {
+				res = append(res, '?')
+			}
+		}
+	}
+	return res
+}
+
+func escape(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch {
+		case c == '(' || c == ')' || c == '\\':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case c >= 0x80:
+			fmt.Fprintf(&sb, "\\%03o", c)
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// Widths of the printable ASCII characters in thousandths of the font size,
+// from the metrics of the standard fonts
+var (
+	helveticaWidths = [95]int{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+		1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+		333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+		556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+	}
+	helveticaBoldWidths = [95]int{
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+		975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+		667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+		333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+		611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+	}
+)
+
+func TextWidth(font Font, size float64, s string) float64 {
+	total := 0
+	for _, c := range winAnsi(s) {
+		switch {
+		case font == Courier:
+			total += 600
+		case c < 0x20 || c > 0x7e:
+			// accented letters are about as wide as the average lowercase
+			total += 556
+		case font == HelveticaBold:
+			total += helveticaBoldWidths[c-0x20]
+		default:
+			total += helveticaWidths[c-0x20]
+		}
+	}
+	return float64(total) * size / 1000
+}
+
+// Wrap splits text in lines no wider than width, breaking at spaces and
+// inside words longer than a line
+func Wrap(font Font, size, width float64, text string) []string {
+	lines := []string{}
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if TextWidth(font, size, candidate) <= width {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			line = word
+			for TextWidth(font, size, line) > width {
+				head := fitting(font, size, width, line)
+				lines = append(lines, head)
+				line = line[len(head):]
+			}
+		}
+		lines = append(lines, line)
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// Truncate cuts s to width, marking the cut with an ellipsis
+func Truncate(font Font, size, width float64, s string) string {
+	if TextWidth(font, size, s) <= width {
+		return s
+	}
+	return fitting(font, size, width-TextWidth(font, size, "…"), s) + "…"
+}
+
+// fitting is the longest prefix of s within width, at least one character
+// so callers always make progress
+func fitting(font Font, size, width float64, s string) string {
+	end := 0
+	for i, r := range s {
+		next := i + utf8.RuneLen(r)
+		if TextWidth(font, size, s[:next]) > width {
+			break
+		}
+		end = next
+	}
+	if end == 0 {
+		_, n := utf8.DecodeRuneInString(s)
+		return s[:n]
+	}
+	return s[:end]
+}
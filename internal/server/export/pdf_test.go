@@ -0,0 +1,125 @@
+package export
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+func TestWrap(t *testing.T) {
+	lines := Wrap(Helvetica, 10, 60, "uno dos tres cuatro\n\ncinco")
+	for _, line := range lines {
+		if TextWidth(Helvetica, 10, line) > 60 {
+			t.Errorf("line %q is wider than the limit", line)
+		}
+	}
+	if strings.Join(lines, " ") != "uno dos tres cuatro  cinco" {
+		t.Errorf("unexpected lines %q", lines)
+	}
+	long := Wrap(Courier, 10, 30, strings.Repeat("x", 20))
+	if len(long) < 2 || strings.Join(long, "") != strings.Repeat("x", 20) {
+		t.Errorf("expected the word to be split, got %q", long)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate(Helvetica, 10, 500, "corto"); got != "corto" {
+		t.Errorf("expected untouched text, got %q", got)
+	}
+	got := Truncate(Helvetica, 10, 40, "un texto bastante largo")
+	if !strings.HasSuffix(got, "…") || TextWidth(Helvetica, 10, got) > 40 {
+		t.Errorf("unexpected truncation %q", got)
+	}
+}
+
+func TestWinAnsi(t *testing.T) {
+	if got := escape(winAnsi("(ñ) €中")); got != `\(\361\) \200?` {
+		t.Errorf("unexpected encoding %s", got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	lines := Highlight("for i := 0 // loop\n\ts := \"x\"\n", 60)
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	kinds := make(map[string]SpanKind)
+	for _, line := range lines {
+		for _, span := range line {
+			kinds[strings.TrimSpace(span.Text)] = span.Kind
+		}
+	}
+	expected := map[string]SpanKind{
+		"for":     KeywordSpan,
+		"i":       PlainSpan,
+		"0":       NumberSpan,
+		"// loop": CommentSpan,
+		`"x"`:     StringSpan,
+	}
+	for text, kind := range expected {
+		if got, ok := kinds[text]; !ok || got != kind {
+			t.Errorf("%q: expected kind %d, got %d (found %t)", text, kind, got, ok)
+		}
+	}
+}
+
+func TestWrapSpans(t *testing.T) {
+	rows := wrapSpans([]Span{{Text: "abcd", Kind: KeywordSpan}, {Text: "ef", Kind: PlainSpan}}, 3)
+	if len(rows) != 2 {
+		t.Fatalf("expected 2 rows, got %d", len(rows))
+	}
+	if rows[1][0].Text != "d" || rows[1][0].Kind != KeywordSpan || rows[1][1].Text != "ef" {
+		t.Errorf("unexpected rows %v", rows)
+	}
+}
+
+func TestWriteDossier(t *testing.T) {
+	src := strings.Repeat("fmt.Println(\"hola\")\n", 120)
+	dossier := Dossier{
+		Offer: shared.Offer{Title: "Backend (Go)", CompanyName: "Acme"},
+		Application: shared.Application{
+			Applicant:     shared.User{Name: "José Pérez", Email: "jose@example.com"},
+			Participation: shared.Participation{CreatedAt: time.Now(), EndReason: shared.EndReasonManual},
+			Summary: []shared.Summary{{
+				Title:      "Suma",
+				Submission: shared.Submission{ID: "s", Src: src, LanguageID: 60, Language: "Go"},
+				Results:    []shared.TestCaseResult{{Status: "Accepted", Time: 10, Memory: 900}},
+				Score:      shared.Score{AcceptedTestCases: 1, TotalTestCases: 1},
+			}},
+		},
+		Keystrokes: []SMDPoint{
+			{SMD: 0, Strokes: 80, Profile: true},
+			{SMD: 2.5, Strokes: 40},
+			{SMD: 0, Strokes: 3, Inactive: true},
+		},
+		GeneratedAt: time.Now(),
+	}
+	var buf bytes.Buffer
+	if err := WriteDossier(&buf, dossier); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if !strings.HasPrefix(out, "%PDF-1.4") || !strings.HasSuffix(out, "%%EOF\n") {
+		t.Fatal("expected a complete PDF")
+	}
+	count := regexp.MustCompile(`/Count (\d+)`).FindStringSubmatch(out)
+	if count == nil || count[1] == "1" {
+		t.Errorf("expected the listing to take several pages, got %v", count)
+	}
+	if !strings.Contains(out, `/Title (Jos\351 P\351rez - Backend \(Go\))`) {
+		t.Error("expected the title in the document info")
+	}
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(out)
+	if startxref == nil {
+		t.Fatal("expected startxref")
+	}
+	offset, _ := strconv.Atoi(startxref[1])
+	if !strings.HasPrefix(out[offset:], "xref\n") {
+		t.Error("expected startxref to point at the cross reference table")
+	}
+}
@@ -0,0 +1,212 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Sheet is a table exported as CSV or XLSX, cells hold strings, ints or
+// float64 so numbers stay numbers in spreadsheets
+type Sheet struct {
+	Name   string
+	Header []string
+	Rows   [][]any
+}
+
+// WriteCSV starts with a byte order mark, without it spreadsheets guess the
+// encoding and break the accents
+func WriteCSV(w io.Writer, sheet Sheet) error {
+	if _, err := io.WriteString(w, "\ufeff"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(sheet.Header); err != nil {
+		return err
+	}
+	for _, row := range sheet.Rows {
+		record := make([]string, len(row))
+		for i, cell := range row {
+			if s, ok := cell.(string); ok {
+				record[i] = neutralize(s)
+			} else {
+				record[i] = formatNumber(cell)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// neutralize keeps text typed by candidates from running as a formula when
+// the file is opened
+func neutralize(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func formatNumber(cell any) string {
+	switch v := cell.(type) {
+	case int:
+		return strconv.Itoa(v)
+	case int32:
+		return strconv.Itoa(int(v))
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(cell)
+}
+
+const (
+	xlsxContentTypes = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
+<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
+<Default Extension="xml" ContentType="application/xml"/>
+<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>
+<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>
+<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>
+</Types>`
+	xlsxRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>
+</Relationships>`
+	xlsxWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+	// the second cell format makes the header bold
+	xlsxStyles = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">
+<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>
+<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>
+<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>
+<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>
+<cellXfs count="2"><xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/><xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/></cellXfs>
+</styleSheet>`
+	xlsxWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets>
+</workbook>`
+)
+
+// WriteXLSX writes a workbook with a single sheet, strings are inlined so
+// no shared string table is needed
+func WriteXLSX(w io.Writer, sheet Sheet) error {
+	zw := zip.NewWriter(w)
+	var name bytes.Buffer
+	if err := xml.EscapeText(&name, []byte(sheetName(sheet.Name))); err != nil {
+		return err
+	}
+	files := []struct {
+		path string
+		body string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, name.String())},
+	}
+	for _, file := range files {
+		f, err := zw.Create(file.path)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, file.body); err != nil {
+			return err
+		}
+	}
+	f, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	if err := writeWorksheet(f, sheet); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// writeWorksheet freezes the header so it stays visible while scrolling
+func writeWorksheet(w io.Writer, sheet Sheet) error {
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	buf.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	buf.WriteString(`<sheetViews><sheetView workbookViewId="0">`)
+	buf.WriteString(`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`)
+	buf.WriteString(`</sheetView></sheetViews><sheetData>`)
+	header := make([]any, len(sheet.Header))
+	for i, h := range sheet.Header {
+		header[i] = h
+	}
+	writeRow(&buf, 1, header, true)
+	for i, row := range sheet.Rows {
+		writeRow(&buf, i+2, row, false)
+	}
+	buf.WriteString(`</sheetData></worksheet>`)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func writeRow(buf *bytes.Buffer, number int, cells []any, bold bool) {
+	fmt.Fprintf(buf, `<row r="%d">`, number)
+	style := ""
+	if bold {
+		style = ` s="1"`
+	}
+	for i, cell := range cells {
+		ref := columnName(i) + strconv.Itoa(number)
+		if s, ok := cell.(string); ok {
+			fmt.Fprintf(buf, `<c r="%s" t="inlineStr"%s><is><t xml:space="preserve">`, ref, style)
+			xml.EscapeText(buf, []byte(s))
+			buf.WriteString(`</t></is></c>`)
+			continue
+		}
+		if cell == nil {
+			continue
+		}
+		fmt.Fprintf(buf, `<c r="%s"%s><v>%s</v></c>`, ref, style, formatNumber(cell))
+	}
+	buf.WriteString(`</row>`)
+}
+
+// columnName is the spreadsheet name of the zero based column: A, B, ... AA
+func columnName(i int) string {
+	name := ""
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = string(rune('A'+(i-1)%26)) + name
+	}
+	return name
+}
+
+// sheetName drops the characters spreadsheets reject in sheet names and
+// keeps their 31 characters limit
+func sheetName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return -1
+		}
+		return r
+	}, s)
+	if runes := []rune(s); len(runes) > 31 {
+		s = string(runes[:31])
+	}
+	if strings.TrimSpace(s) == "" {
+		return "Hoja1"
+	}
+	return s
+}
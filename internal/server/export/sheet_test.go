@@ -0,0 +1,108 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+)
+
+var testSheet = Sheet{
+	Name:   "Backend [Senior]",
+	Header: []string{"Nombre", "Puntaje", "Nota"},
+	Rows: [][]any{
+		{"José", 85, "=HYPERLINK(\"x\")"},
+		{"Ana <b>", 40.5, nil},
+	},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, testSheet); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "\ufeff") {
+		t.Error("expected a byte order mark")
+	}
+	records, err := csv.NewReader(strings.NewReader(strings.TrimPrefix(buf.String(), "\ufeff"))).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{
+		{"Nombre", "Puntaje", "Nota"},
+		{"José", "85", "'=HYPERLINK(\"x\")"},
+		{"Ana <b>", "40.5", ""},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records, got %d", len(expected), len(records))
+	}
+	for i := range expected {
+		if strings.Join(records[i], "|") != strings.Join(expected[i], "|") {
+			t.Errorf("record %d: expected %v, got %v", i, expected[i], records[i])
+		}
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteXLSX(&buf, testSheet); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(body)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("missing %s", name)
+		}
+	}
+	if !strings.Contains(files["xl/workbook.xml"], `name="Backend Senior"`) {
+		t.Error("expected the sheet name without brackets")
+	}
+	worksheet := files["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" t="inlineStr" s="1">`,
+		`<c r="B2"><v>85</v></c>`,
+		`<c r="B3"><v>40.5</v></c>`,
+		"Ana &lt;b&gt;",
+		`state="frozen"`,
+	} {
+		if !strings.Contains(worksheet, want) {
+			t.Errorf("expected worksheet to contain %s", want)
+		}
+	}
+	if strings.Contains(worksheet, `r="C3"`) {
+		t.Error("expected empty cells to be skipped")
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"}
+	for i, want := range tests {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %s, want %s", i, got, want)
+		}
+	}
+}
+
+func TestSheetName(t *testing.T) {
+	if got := sheetName(" "); got != "Hoja1" {
+		t.Errorf("expected default name, got %q", got)
+	}
+	if got := sheetName(strings.Repeat("ñ", 40)); len([]rune(got)) != 31 {
+		t.Errorf("expected 31 characters, got %d", len([]rune(got)))
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/kw3a/spotted-server/internal/server/companies"
+	"github.com/kw3a/spotted-server/internal/server/webhooks"
 )
 
 func (DI *App) CompanyListPageHandler() http.HandlerFunc {
@@ -42,3 +43,122 @@ func (DI *App) CompanyPageHandler() http.HandlerFunc {
 		companies.GetCompanyPageInput,
 	)
 }
+
+func (DI *App) CompanyStagesHandler() http.HandlerFunc {
+	return companies.CreateStagesHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		companies.GetStagesInput,
+	)
+}
+
+func (DI *App) CompanyWebhooksPage() http.HandlerFunc {
+	return companies.CreateWebhooksPageHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		companies.GetCompanyPageInput,
+	)
+}
+
+func (DI *App) CompanyWebhook() http.HandlerFunc {
+	return companies.CreateWebhookHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		webhooks.NewSecret,
+		companies.GetWebhookInput,
+	)
+}
+
+func (DI *App) CompanyWebhookDelete() http.HandlerFunc {
+	return companies.CreateWebhookDeleteHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		companies.GetWebhookDeleteInput,
+	)
+}
+
+func (DI *App) WebhookRedelivery() http.HandlerFunc {
+	return companies.CreateRedeliverHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		companies.GetRedeliverInput,
+	)
+}
+
+func (DI *App) CompanyTeamPage() http.HandlerFunc {
+	return companies.CreateTeamPageHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		companies.GetCompanyPageInput,
+	)
+}
+
+func (DI *App) CompanyInvite() http.HandlerFunc {
+	return companies.CreateInviteHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		DI.Notifications,
+		companies.GetInviteInput,
+	)
+}
+
+func (DI *App) CompanyInvitationDelete() http.HandlerFunc {
+	return companies.CreateInvitationDeleteHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		companies.GetInvitationDeleteInput,
+	)
+}
+
+func (DI *App) CompanyMemberRole() http.HandlerFunc {
+	return companies.CreateMemberRoleHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		companies.GetMemberRoleInput,
+	)
+}
+
+func (DI *App) CompanyMemberDelete() http.HandlerFunc {
+	return companies.CreateMemberDeleteHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		companies.GetMemberInput,
+		"/memberships",
+	)
+}
+
+func (DI *App) CompanyTransfer() http.HandlerFunc {
+	return companies.CreateTransferHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		companies.GetMemberInput,
+	)
+}
+
+func (DI *App) MembershipsPage() http.HandlerFunc {
+	return companies.CreateMembershipsPageHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+	)
+}
+
+func (DI *App) MembershipAnswer() http.HandlerFunc {
+	return companies.CreateAnswerHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		companies.GetAnswerInput,
+	)
+}
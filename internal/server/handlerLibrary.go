@@ -0,0 +1,109 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/kw3a/spotted-server/internal/server/library"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+func (DI *App) Library() http.HandlerFunc {
+	return library.CreateLibraryHandler(
+		library.GetLibraryInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.Templ,
+		"/register/companies",
+	)
+}
+
+func (DI *App) BankOptions() http.HandlerFunc {
+	return library.CreateBankOptionsHandler(
+		library.GetLibraryInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.Templ,
+	)
+}
+
+func (DI *App) BankProblemPage() http.HandlerFunc {
+	return library.CreateProblemPageHandler(
+		library.GetProblemPageInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.Templ,
+		"/login",
+	)
+}
+
+func (DI *App) BankProblemRegistration() http.HandlerFunc {
+	return library.CreateProblemRegisterHandler(
+		library.GetProblemRegisterInput,
+		DI.AuthService,
+		DI.Storage,
+		"/library/",
+	)
+}
+
+func (DI *App) BankProblemUpdate() http.HandlerFunc {
+	return library.CreateProblemUpdateHandler(
+		library.GetProblemUpdateInput,
+		DI.AuthService,
+		DI.Storage,
+		"/library/",
+	)
+}
+
+func (DI *App) BankProblemImport() http.HandlerFunc {
+	return library.CreateProblemImportHandler(
+		library.GetProblemImportInput,
+		DI.AuthService,
+		DI.Storage,
+		"/library?companyID=",
+	)
+}
+
+func (DI *App) BankProblemExport() http.HandlerFunc {
+	return library.CreateProblemExportHandler(
+		library.GetProblemExportInput,
+		DI.AuthService,
+		DI.Storage,
+	)
+}
+
+func (DI *App) ReferenceRegistration() http.HandlerFunc {
+	return library.CreateReferenceRegisterHandler(
+		library.GetReferenceRegisterInput,
+		DI.AuthService,
+		DI.Storage,
+		&DI.RefJudge,
+		"/library/",
+	)
+}
+
+func (DI *App) ReferenceValidation() http.HandlerFunc {
+	return library.CreateReferenceValidateHandler(
+		library.GetReferenceValidateInput,
+		DI.AuthService,
+		DI.Storage,
+		&DI.RefJudge,
+		"/library/",
+	)
+}
+
+func (DI *App) ReferenceDelete() http.HandlerFunc {
+	return library.CreateReferenceDeleteHandler(
+		library.GetReferenceDeleteInput,
+		DI.AuthService,
+		DI.Storage,
+		"/library/",
+	)
+}
+
+func (DI *App) ReferenceCallback() http.HandlerFunc {
+	return library.CreateReferenceCallbackHandler(
+		DI.Storage,
+		shared.Decode[shared.CallbackJsonInput],
+		library.GetReferenceCallbackInput,
+	)
+}
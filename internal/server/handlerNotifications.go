@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/kw3a/spotted-server/internal/server/notifications"
+)
+
+func (DI *App) NotificationStream() http.HandlerFunc {
+	return notifications.CreateStreamHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		DI.NotificationBroker,
+	)
+}
+
+func (DI *App) NotificationRead() http.HandlerFunc {
+	return notifications.CreateReadHandler(
+		DI.AuthService,
+		DI.Storage,
+		DI.NotificationBroker,
+		notifications.GetReadInput,
+	)
+}
+
+func (DI *App) NotificationsRead() http.HandlerFunc {
+	return notifications.CreateReadAllHandler(
+		DI.AuthService,
+		DI.Storage,
+		DI.NotificationBroker,
+	)
+}
+
+func (DI *App) NotificationPreferencesPage() http.HandlerFunc {
+	return notifications.CreatePreferencesPageHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+	)
+}
+
+func (DI *App) NotificationPreferences() http.HandlerFunc {
+	return notifications.CreatePreferencesHandler(
+		DI.Templ,
+		DI.AuthService,
+		DI.Storage,
+		notifications.GetPreferencesInput,
+	)
+}
@@ -1,9 +1,11 @@
 package server
 
 import (
+	"context"
 	"net/http"
 
 	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
 )
 
 func (DI *App) JobOffersHandler() http.HandlerFunc {
@@ -20,6 +22,7 @@ func (app *App) PreambleHandler() http.HandlerFunc {
 		app.Templ,
 		app.Storage,
 		app.AuthService,
+		app.AuthType,
 		offers.GetPreambleInput,
 	)
 }
@@ -53,9 +56,22 @@ func (DI *App) OfferEditionPage() http.HandlerFunc {
 }
 
 func (DI *App) OfferEdition() http.HandlerFunc {
-	return offers.CreateOfferEdition(
+	return offers.CreateOfferEditHandler(
+		offers.GetOfferEditInput,
+		DI.AuthService,
+		DI.Storage,
+		"/offers/admin/",
+	)
+}
+
+func (DI *App) OfferStatus() http.HandlerFunc {
+	return offers.CreateOfferStatusHandler(
+		offers.GetOfferStatusInput,
+		DI.AuthService,
+		DI.Storage,
 		DI.Storage,
-		offers.GetOfferEditionInput,
+		DI.Notifications,
+		"/offers/admin",
 	)
 }
 
@@ -72,6 +88,8 @@ func (DI *App) OfferArchive() http.HandlerFunc {
 		offers.GetOfferArchiveInput,
 		DI.AuthService,
 		DI.Storage,
+		DI.Storage,
+		DI.Notifications,
 	)
 }
 
@@ -83,3 +101,179 @@ func (DI *App) OfferAdmin() http.HandlerFunc {
 		DI.Templ,
 	)
 }
+
+func (DI *App) ApplicantsExport() http.HandlerFunc {
+	return offers.CreateExportHandler(
+		offers.GetExportInput,
+		DI.AuthService,
+		DI.Storage,
+	)
+}
+
+func (DI *App) ApplicantDossier() http.HandlerFunc {
+	return offers.CreateDossierHandler(
+		offers.GetDossierInput,
+		DI.AuthService,
+		DI.Storage,
+	)
+}
+
+func (DI *App) ProctoringRules() http.HandlerFunc {
+	return offers.CreateProctoringRulesHandler(
+		offers.GetProctoringRulesInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.Templ,
+	)
+}
+
+func (DI *App) BlindReview() http.HandlerFunc {
+	return offers.CreateBlindReviewHandler(
+		offers.GetBlindReviewInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.Templ,
+	)
+}
+
+func (DI *App) Similarity() http.HandlerFunc {
+	return offers.CreateSimilarityHandler(
+		offers.GetSimilarityInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.Templ,
+	)
+}
+
+func (DI *App) AdjustParticipation() http.HandlerFunc {
+	return offers.CreateAdjustParticipationHandler(
+		offers.GetAdjustParticipationInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.Deadlines,
+		DI.Templ,
+	)
+}
+
+func (DI *App) ReviewApplicant() http.HandlerFunc {
+	return offers.CreateReviewApplicantHandler(
+		offers.GetReviewApplicantInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.Templ,
+		DI.Notifications,
+	)
+}
+
+func (DI *App) BulkReview() http.HandlerFunc {
+	return offers.CreateBulkReviewHandler(
+		offers.GetBulkReviewInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.Templ,
+		DI.Notifications,
+		"/offers/admin/",
+	)
+}
+
+func (DI *App) MarkdownPreview() http.HandlerFunc {
+	return offers.CreatePreviewHandler(
+		offers.GetPreviewInput,
+		DI.AuthService,
+		DI.Templ,
+	)
+}
+
+func (DI *App) Attachment() http.HandlerFunc {
+	return offers.CreateAttachmentHandler(
+		offers.GetAttachmentInput,
+		DI.AuthService,
+		&DI.Cld.Upload,
+		DI.Templ,
+	)
+}
+
+func (DI *App) GradeResponse() http.HandlerFunc {
+	return offers.CreateGradeHandler(
+		offers.GetGradeInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.Templ,
+	)
+}
+
+func (DI *App) QuizAccess() http.HandlerFunc {
+	return offers.CreateQuizAccessHandler(
+		offers.GetApplicantsInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.AuthType,
+		DI.Templ,
+	)
+}
+
+func (DI *App) QuizAccessUpdate() http.HandlerFunc {
+	return offers.CreateQuizAccessUpdateHandler(
+		offers.GetQuizAccessInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.AuthType,
+		DI.Templ,
+	)
+}
+
+func (DI *App) Invitations() http.HandlerFunc {
+	return offers.CreateInvitationsHandler(
+		offers.GetInvitationsInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.AuthType,
+		DI.Templ,
+	)
+}
+
+func (DI *App) InvitationDelete() http.HandlerFunc {
+	return offers.CreateInvitationDeleteHandler(
+		offers.GetInvitationDeleteInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.AuthType,
+		DI.Templ,
+	)
+}
+
+// rejudgeDispatcher sends the rejudged submissions in the background, the
+// request only waits for the results to be reset
+type rejudgeDispatcher struct {
+	app *App
+}
+
+func (d rejudgeDispatcher) Dispatch(rejudgeID string, entries []shared.RejudgeEntry) {
+	go offers.SendRejudge(
+		context.Background(),
+		d.app.Storage,
+		&d.app.Judge,
+		rejudgeID,
+		entries,
+		offers.RejudgeWorkers,
+	)
+}
+
+func (DI *App) Rejudge() http.HandlerFunc {
+	return offers.CreateRejudgeHandler(
+		offers.GetRejudgeInput,
+		DI.AuthService,
+		DI.Storage,
+		rejudgeDispatcher{app: DI},
+		DI.Templ,
+	)
+}
+
+func (DI *App) RejudgeProgress() http.HandlerFunc {
+	return offers.CreateRejudgeProgressHandler(
+		offers.GetRejudgeProgressInput,
+		DI.AuthService,
+		DI.Storage,
+		DI.Templ,
+	)
+}
@@ -12,6 +12,7 @@ func (app *App) CallbackHandler() http.HandlerFunc {
 	return quizes.CreateCallbackHandler(
 		app.Storage,
 		app.Stream,
+		app.Storage,
 		shared.Decode[shared.CallbackJsonInput],
 		quizes.GetCallbackURLParamsInput,
 	)
@@ -57,6 +58,14 @@ func (app *App) ScoreHandler() http.HandlerFunc {
 	)
 }
 
+func (DI *App) QuestionFormHandler() http.HandlerFunc {
+	return quizes.CreateQuestionFormHandler(DI.Templ, DI.Storage, DI.AuthService, quizes.GetAnswerInput)
+}
+
+func (DI *App) AnswerHandler() http.HandlerFunc {
+	return quizes.CreateAnswerHandler(DI.Templ, DI.Storage, DI.AuthService, quizes.GetAnswerInput)
+}
+
 func (DI *App) LastSrcHandler() http.HandlerFunc {
 	return quizes.CreateSourceHandler(
 		DI.Storage,
@@ -70,11 +79,23 @@ func (app *App) ProblemsHandler() http.HandlerFunc {
 }
 
 func (DI *App) ParticipateHandler() http.HandlerFunc {
-	return quizes.CreateParticipateHandler(DI.Storage, DI.AuthService, quizes.GetParticipateInput)
+	return quizes.CreateParticipateHandler(DI.Templ, DI.Storage, DI.AuthService, DI.Storage, DI.Notifications, quizes.GetParticipateInput)
 }
 
 func (DI *App) EndHandler() http.HandlerFunc {
-	return quizes.CreateEndHandler(DI.Storage, DI.AuthService, quizes.GetEndInput)
+	return quizes.CreateEndHandler(DI.Storage, DI.AuthService, DI.Storage, DI.Notifications, quizes.GetEndInput)
+}
+
+func (DI *App) ApplicationsHandler() http.HandlerFunc {
+	return quizes.CreateApplicationsHandler(DI.Templ, DI.Storage, DI.AuthService, quizes.GetApplicationsInput)
+}
+
+func (DI *App) WithdrawHandler() http.HandlerFunc {
+	return quizes.CreateWithdrawHandler(DI.Templ, DI.Storage, DI.AuthService, DI.Deadlines, quizes.GetWithdrawInput)
+}
+
+func (DI *App) DeadlineHandler() http.HandlerFunc {
+	return quizes.CreateDeadlineHandler(DI.Storage, DI.AuthService, DI.Deadlines, quizes.GetDeadlineInput)
 }
 
 func (app *App) ExamplesHandler() http.HandlerFunc {
@@ -85,6 +106,10 @@ func (app *App) KeystrokeWindowHandler() http.HandlerFunc {
 	return quizes.CreateKeyStrokeWindowHandler(app.Storage, app.AuthService, quizes.GetStrokeWindowInput)
 }
 
+func (app *App) ProctoringEventHandler() http.HandlerFunc {
+	return quizes.CreateProctoringEventHandler(app.Storage, app.AuthService, quizes.GetProctoringEventInput)
+}
+
 func (app *App) KeystrokeReportHandler() http.HandlerFunc {
 	return quizes.CreateKeyStrokeReportHandler(app.Templ, app.Storage, quizes.GetKeyStrokeReportInput)
 }
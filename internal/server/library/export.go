@@ -0,0 +1,75 @@
+package library
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/problempkg"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+type ProblemExportStorage interface {
+	SelectBankProblem(ctx context.Context, bankProblemID string, userID string) (shared.Problem, error)
+	ReadTestData(ctx context.Context, hash string) (string, error)
+}
+
+type ProblemExportInput struct {
+	BankProblemID string
+}
+
+func GetProblemExportInput(r *http.Request) (ProblemExportInput, error) {
+	bankProblemID := chi.URLParam(r, "bankProblemID")
+	if err := shared.ValidateUUID(bankProblemID); err != nil {
+		return ProblemExportInput{}, err
+	}
+	return ProblemExportInput{BankProblemID: bankProblemID}, nil
+}
+
+type problemExportInputFn func(r *http.Request) (ProblemExportInput, error)
+
+// CreateProblemExportHandler downloads the latest version of a library
+// problem as a zipped problem package
+func CreateProblemExportHandler(
+	inputFn problemExportInputFn,
+	authz shared.AuthRep,
+	storage ProblemExportStorage,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authz.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		problem, err := storage.SelectBankProblem(r.Context(), input.BankProblemID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := shared.FillTestData(r.Context(), storage, &problem); err != nil {
+			http.Error(w, fmt.Sprintf("storage error: %s", err.Error()), http.StatusInternalServerError)
+			return
+		}
+		var buf bytes.Buffer
+		if err := problempkg.Write(&buf, problem); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		filename := fmt.Sprintf("%s-v%d.zip", problempkg.ShortName(problem.Title), problem.Version)
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		_, _ = w.Write(buf.Bytes())
+	}
+}
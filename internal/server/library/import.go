@@ -0,0 +1,93 @@
+package library
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/problempkg"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+type ProblemImportInput struct {
+	CompanyID string
+	Problems  []shared.Problem
+}
+
+// GetProblemImportInput reads the zipped problem package uploaded as the
+// "package" field, it may contain several problems
+func GetProblemImportInput(r *http.Request) (ProblemImportInput, error) {
+	r.Body = http.MaxBytesReader(nil, r.Body, problempkg.MaxPackageSize+(1<<20))
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		return ProblemImportInput{}, fmt.Errorf("el paquete no puede superar los %d MB", problempkg.MaxPackageSize>>20)
+	}
+	companyID := r.FormValue("companyID")
+	if err := shared.ValidateUUID(companyID); err != nil {
+		return ProblemImportInput{}, err
+	}
+	file, _, err := r.FormFile("package")
+	if err != nil {
+		return ProblemImportInput{}, fmt.Errorf("selecciona un paquete .zip")
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, problempkg.MaxPackageSize+1))
+	if err != nil {
+		return ProblemImportInput{}, err
+	}
+	if len(data) > problempkg.MaxPackageSize {
+		return ProblemImportInput{}, fmt.Errorf("el paquete no puede superar los %d MB", problempkg.MaxPackageSize>>20)
+	}
+	problems, err := problempkg.ReadZip(data)
+	if err != nil {
+		return ProblemImportInput{}, err
+	}
+	return ProblemImportInput{CompanyID: companyID, Problems: problems}, nil
+}
+
+type problemImportInputFn func(r *http.Request) (ProblemImportInput, error)
+
+type ProblemImportStorage interface {
+	SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error)
+	InsertBankProblems(ctx context.Context, companyID string, problems []shared.Problem) ([]string, error)
+}
+
+func CreateProblemImportHandler(
+	inputFn problemImportInputFn,
+	authz shared.AuthRep,
+	storage ProblemImportStorage,
+	redirPath string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authz.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		role, err := storage.SelectCompanyRole(r.Context(), input.CompanyID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !shared.CanEdit(role) {
+			http.Error(w, errNotEditor, http.StatusUnauthorized)
+			return
+		}
+		if _, err := storage.InsertBankProblems(r.Context(), input.CompanyID, input.Problems); err != nil {
+			http.Error(w, fmt.Sprintf("storage error: %s", err.Error()), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("HX-Redirect", redirPath+input.CompanyID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,89 @@
+package library
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+type LibraryStorage interface {
+	GetCompanies(ctx context.Context, params shared.CompanyQueryParams) ([]shared.Company, error)
+	SelectBankProblems(ctx context.Context, companyID string) ([]shared.BankProblem, error)
+}
+
+type LibraryData struct {
+	User      auth.AuthUser
+	Companies []shared.Company
+	Company   shared.Company
+	Problems  []shared.BankProblem
+}
+
+type LibraryInput struct {
+	CompanyID string
+}
+
+func GetLibraryInput(r *http.Request) (LibraryInput, error) {
+	companyID := r.URL.Query().Get("companyID")
+	if companyID == "" {
+		return LibraryInput{}, nil
+	}
+	if err := shared.ValidateUUID(companyID); err != nil {
+		return LibraryInput{}, err
+	}
+	return LibraryInput{CompanyID: companyID}, nil
+}
+
+type libraryInputFn func(r *http.Request) (LibraryInput, error)
+
+func CreateLibraryHandler(
+	inputFn libraryInputFn,
+	authz shared.AuthRep,
+	storage LibraryStorage,
+	templ shared.TemplatesRepo,
+	redirection string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authz.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Redirect(w, r, redirection, http.StatusSeeOther)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		params := shared.CompanyQueryParams{UserID: user.ID, Page: 1}
+		companies, err := storage.GetCompanies(r.Context(), params)
+		if err != nil || len(companies) == 0 {
+			http.Redirect(w, r, redirection, http.StatusSeeOther)
+			return
+		}
+		company := companies[0]
+		for _, c := range companies {
+			if c.ID == input.CompanyID {
+				company = c
+			}
+		}
+		problems, err := storage.SelectBankProblems(r.Context(), company.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data := LibraryData{
+			User:      user,
+			Companies: companies,
+			Company:   company,
+			Problems:  problems,
+		}
+		if err := templ.Render(w, "library", data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
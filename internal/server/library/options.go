@@ -0,0 +1,61 @@
+package library
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+type BankOptionsStorage interface {
+	SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error)
+	SelectBankProblems(ctx context.Context, companyID string) ([]shared.BankProblem, error)
+}
+
+type BankOptionsData struct {
+	Problems []shared.BankProblem
+}
+
+// CreateBankOptionsHandler lists the library of the selected company so the
+// offer registration form can reuse its problems
+func CreateBankOptionsHandler(
+	inputFn libraryInputFn,
+	authz shared.AuthRep,
+	storage BankOptionsStorage,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authz.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil || input.CompanyID == "" {
+			http.Error(w, "invalid company", http.StatusBadRequest)
+			return
+		}
+		role, err := storage.SelectCompanyRole(r.Context(), input.CompanyID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !shared.CanEdit(role) {
+			http.Error(w, errNotEditor, http.StatusUnauthorized)
+			return
+		}
+		problems, err := storage.SelectBankProblems(r.Context(), input.CompanyID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := templ.Render(w, "bankOptions", BankOptionsData{Problems: problems}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package library
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const (
+	errNotAuthorized = "you must be authenticated to use this function"
+	errNotEditor     = "your role in this company can't edit its library"
+)
+
+type ProblemPageStorage interface {
+	SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error)
+	SelectBankProblem(ctx context.Context, bankProblemID string, userID string) (shared.Problem, error)
+	SelectProblemVersions(ctx context.Context, bankProblemID string) ([]shared.ProblemVersion, error)
+	SelectReferenceSolutions(ctx context.Context, problemID string) ([]shared.ReferenceSolution, error)
+	GetLanguages(ctx context.Context) ([]shared.Language, error)
+}
+
+type ProblemPageData struct {
+	User      auth.AuthUser
+	CompanyID string
+	Problem   shared.Problem
+	Versions  []shared.ProblemVersion
+	Solutions []shared.ReferenceSolution
+	Languages []shared.Language
+}
+
+// ReferenceStatus is empty when the problem has no reference solutions
+func (d ProblemPageData) ReferenceStatus() string {
+	return shared.ReferenceStatus(d.Solutions, d.Problem.TimeLimit)
+}
+
+func (d ProblemPageData) ReferenceStatusLabel() string {
+	return shared.ValidationLabels[d.ReferenceStatus()]
+}
+
+func (d ProblemPageData) SuggestedTimeLimit() int32 {
+	return shared.SuggestTimeLimit(d.Solutions)
+}
+
+type ProblemPageInput struct {
+	CompanyID     string
+	BankProblemID string
+}
+
+// GetProblemPageInput reads the problem to edit from the url, or the
+// company that will own a new problem from the query
+func GetProblemPageInput(r *http.Request) (ProblemPageInput, error) {
+	bankProblemID := chi.URLParam(r, "bankProblemID")
+	if bankProblemID != "" {
+		if err := shared.ValidateUUID(bankProblemID); err != nil {
+			return ProblemPageInput{}, err
+		}
+		return ProblemPageInput{BankProblemID: bankProblemID}, nil
+	}
+	companyID := r.URL.Query().Get("companyID")
+	if err := shared.ValidateUUID(companyID); err != nil {
+		return ProblemPageInput{}, err
+	}
+	return ProblemPageInput{CompanyID: companyID}, nil
+}
+
+type problemPageInputFn func(r *http.Request) (ProblemPageInput, error)
+
+func CreateProblemPageHandler(
+	inputFn problemPageInputFn,
+	authz shared.AuthRep,
+	storage ProblemPageStorage,
+	templ shared.TemplatesRepo,
+	redirection string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authz.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Redirect(w, r, redirection, http.StatusSeeOther)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data := ProblemPageData{User: user, CompanyID: input.CompanyID}
+		if input.BankProblemID == "" {
+			role, err := storage.SelectCompanyRole(r.Context(), input.CompanyID, user.ID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if !shared.CanEdit(role) {
+				http.Error(w, errNotEditor, http.StatusUnauthorized)
+				return
+			}
+		} else {
+			problem, err := storage.SelectBankProblem(r.Context(), input.BankProblemID, user.ID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			versions, err := storage.SelectProblemVersions(r.Context(), input.BankProblemID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			solutions, err := storage.SelectReferenceSolutions(r.Context(), problem.ID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			languages, err := storage.GetLanguages(r.Context())
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			data.Problem = problem
+			data.Versions = versions
+			data.Solutions = solutions
+			data.Languages = languages
+		}
+		if err := templ.Render(w, "bankProblemPage", data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/codejudge"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/kw3a/spotted-server/internal/server/signature"
+	"github.com/kw3a/spotted-server/internal/server/sqljudge"
+)
+
+// MaxReferenceSrc is the size limit in bytes of a reference solution
+const MaxReferenceSrc = 65535
+
+type JudgeService interface {
+	Send(testCases []codejudge.TestCase, submission codejudge.Submission) ([]string, error)
+}
+
+type ReferenceInput struct {
+	BankProblemID string
+	SolutionID    string
+	Solution      shared.ReferenceSolution
+}
+
+func getBankProblemID(r *http.Request) (string, error) {
+	bankProblemID := chi.URLParam(r, "bankProblemID")
+	if err := shared.ValidateUUID(bankProblemID); err != nil {
+		return "", err
+	}
+	return bankProblemID, nil
+}
+
+func GetReferenceRegisterInput(r *http.Request) (ReferenceInput, error) {
+	bankProblemID, err := getBankProblemID(r)
+	if err != nil {
+		return ReferenceInput{}, err
+	}
+	languageID, err := shared.ValidateLanguageID(r.FormValue("languageID"))
+	if err != nil {
+		return ReferenceInput{}, err
+	}
+	expected := r.FormValue("expected")
+	if _, ok := shared.ReferenceExpectedLabels[expected]; !ok {
+		return ReferenceInput{}, fmt.Errorf("tipo de solución inválido")
+	}
+	src := r.FormValue("src")
+	if strings.TrimSpace(src) == "" {
+		return ReferenceInput{}, fmt.Errorf("el código de la solución está vacío")
+	}
+	if len(src) > MaxReferenceSrc {
+		return ReferenceInput{}, fmt.Errorf("el código de la solución no puede superar %d bytes", MaxReferenceSrc)
+	}
+	return ReferenceInput{
+		BankProblemID: bankProblemID,
+		Solution: shared.ReferenceSolution{
+			LanguageID: languageID,
+			Expected:   expected,
+			Src:        src,
+		},
+	}, nil
+}
+
+func GetReferenceValidateInput(r *http.Request) (ReferenceInput, error) {
+	bankProblemID, err := getBankProblemID(r)
+	if err != nil {
+		return ReferenceInput{}, err
+	}
+	return ReferenceInput{BankProblemID: bankProblemID}, nil
+}
+
+func GetReferenceDeleteInput(r *http.Request) (ReferenceInput, error) {
+	bankProblemID, err := getBankProblemID(r)
+	if err != nil {
+		return ReferenceInput{}, err
+	}
+	solutionID := chi.URLParam(r, "solutionID")
+	if err := shared.ValidateUUID(solutionID); err != nil {
+		return ReferenceInput{}, err
+	}
+	return ReferenceInput{BankProblemID: bankProblemID, SolutionID: solutionID}, nil
+}
+
+type referenceInputFn func(r *http.Request) (ReferenceInput, error)
+
+type TestCaseStorage interface {
+	GetTestCases(ctx context.Context, problemID string) ([]codejudge.TestCase, error)
+	SelectProblem(ctx context.Context, problemID string) (shared.Problem, error)
+}
+
+// judgeReferences sends the solutions of one problem version to the judge,
+// with a generous time limit so their runtimes can suggest the real one
+func judgeReferences(ctx context.Context, storage TestCaseStorage, judge JudgeService, solutions []shared.ReferenceSolution) error {
+	if len(solutions) == 0 {
+		return nil
+	}
+	testCases, err := storage.GetTestCases(ctx, solutions[0].ProblemID)
+	if err != nil {
+		return err
+	}
+	for i := range testCases {
+		testCases[i].TimeLimit = float64(shared.ReferenceTimeLimit) / 1000
+	}
+	problem, err := storage.SelectProblem(ctx, solutions[0].ProblemID)
+	if err != nil {
+		return err
+	}
+	for _, solution := range solutions {
+		src, err := signature.Prepare(problem.Signature, solution.LanguageID, solution.Src)
+		if err != nil {
+			return err
+		}
+		judged, submission, err := sqljudge.Prepare(problem.Database, testCases, codejudge.Submission{
+			ID:         solution.ID,
+			Src:        src,
+			LanguageID: solution.LanguageID,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err = judge.Send(judged, submission); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type ReferenceRegisterStorage interface {
+	TestCaseStorage
+	InsertReferenceSolution(ctx context.Context, bankProblemID string, userID string, solution shared.ReferenceSolution) (shared.ReferenceSolution, error)
+}
+
+// CreateReferenceRegisterHandler attaches a solution to the latest version
+// of the problem and judges it right away
+func CreateReferenceRegisterHandler(
+	inputFn referenceInputFn,
+	authz shared.AuthRep,
+	storage ReferenceRegisterStorage,
+	judge JudgeService,
+	redirPath string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authz.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		solution, err := storage.InsertReferenceSolution(r.Context(), input.BankProblemID, user.ID, input.Solution)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				http.Error(w, "problem not found", http.StatusBadRequest)
+				return
+			}
+			http.Error(w, fmt.Sprintf("storage error: %s", err.Error()), http.StatusInternalServerError)
+			return
+		}
+		if err := judgeReferences(r.Context(), storage, judge, []shared.ReferenceSolution{solution}); err != nil {
+			http.Error(w, fmt.Sprintf("judge error: %s", err.Error()), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("HX-Redirect", redirPath+input.BankProblemID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+type ReferenceValidateStorage interface {
+	TestCaseStorage
+	ResetReferenceResults(ctx context.Context, bankProblemID string, userID string) ([]shared.ReferenceSolution, error)
+}
+
+// CreateReferenceValidateHandler judges again every solution of the latest
+// version, it is needed after saving a new version of the problem
+func CreateReferenceValidateHandler(
+	inputFn referenceInputFn,
+	authz shared.AuthRep,
+	storage ReferenceValidateStorage,
+	judge JudgeService,
+	redirPath string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authz.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		solutions, err := storage.ResetReferenceResults(r.Context(), input.BankProblemID, user.ID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				http.Error(w, "problem not found", http.StatusBadRequest)
+				return
+			}
+			http.Error(w, fmt.Sprintf("storage error: %s", err.Error()), http.StatusInternalServerError)
+			return
+		}
+		if err := judgeReferences(r.Context(), storage, judge, solutions); err != nil {
+			http.Error(w, fmt.Sprintf("judge error: %s", err.Error()), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("HX-Redirect", redirPath+input.BankProblemID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+type ReferenceDeleteStorage interface {
+	DeleteReferenceSolution(ctx context.Context, solutionID, bankProblemID, userID string) error
+}
+
+func CreateReferenceDeleteHandler(
+	inputFn referenceInputFn,
+	authz shared.AuthRep,
+	storage ReferenceDeleteStorage,
+	redirPath string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authz.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := storage.DeleteReferenceSolution(r.Context(), input.SolutionID, input.BankProblemID, user.ID); err != nil {
+			http.Error(w, fmt.Sprintf("storage error: %s", err.Error()), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("HX-Redirect", redirPath+input.BankProblemID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+type ReferenceCallbackInput struct {
+	SolutionID string
+	TestCaseID string
+}
+
+func GetReferenceCallbackInput(r *http.Request) (ReferenceCallbackInput, error) {
+	solutionID := chi.URLParam(r, "solutionID")
+	if err := shared.ValidateUUID(solutionID); err != nil {
+		return ReferenceCallbackInput{}, errors.New("invalid solution ID")
+	}
+	testCaseID := chi.URLParam(r, "testCaseID")
+	if err := shared.ValidateUUID(testCaseID); err != nil {
+		return ReferenceCallbackInput{}, errors.New("invalid tc ID")
+	}
+	return ReferenceCallbackInput{SolutionID: solutionID, TestCaseID: testCaseID}, nil
+}
+
+type ReferenceCallbackStorage interface {
+	UpdateReferenceResult(ctx context.Context, input shared.CallbackJsonInput, solutionID, testCaseID string) error
+}
+
+type referenceCallbackInputFn func(r *http.Request) (ReferenceCallbackInput, error)
+type decoderFn func(r *http.Request) (shared.CallbackJsonInput, error)
+
+// CreateReferenceCallbackHandler records the verdict the judge reports for
+// one test case of a reference solution
+func CreateReferenceCallbackHandler(
+	storage ReferenceCallbackStorage,
+	decoder decoderFn,
+	inputFn referenceCallbackInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		urlParams, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		decoded, err := decoder(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		err = storage.UpdateReferenceResult(r.Context(), decoded, urlParams.SolutionID, urlParams.TestCaseID)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+}
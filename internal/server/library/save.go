@@ -0,0 +1,158 @@
+package library
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+type BankProblemJ struct {
+	CompanyID string            `json:"companyID"`
+	Problems  []offers.ProblemJ `json:"problems"`
+}
+
+type ProblemSaveInput struct {
+	CompanyID     string
+	BankProblemID string
+	Problem       shared.Problem
+}
+
+func decodeProblem(r *http.Request) (BankProblemJ, shared.Problem, error) {
+	jsonInput, err := shared.Decode[BankProblemJ](r)
+	if err != nil {
+		return BankProblemJ{}, shared.Problem{}, err
+	}
+	if len(jsonInput.Problems) != 1 {
+		return BankProblemJ{}, shared.Problem{}, fmt.Errorf("debe enviarse exactamente un problema")
+	}
+	problem, err := offers.ValidateProblem(jsonInput.Problems[0])
+	if err != nil {
+		return BankProblemJ{}, shared.Problem{}, err
+	}
+	return jsonInput, problem, nil
+}
+
+func GetProblemRegisterInput(r *http.Request) (ProblemSaveInput, error) {
+	jsonInput, problem, err := decodeProblem(r)
+	if err != nil {
+		return ProblemSaveInput{}, err
+	}
+	if err := shared.ValidateUUID(jsonInput.CompanyID); err != nil {
+		return ProblemSaveInput{}, err
+	}
+	return ProblemSaveInput{CompanyID: jsonInput.CompanyID, Problem: problem}, nil
+}
+
+func GetProblemUpdateInput(r *http.Request) (ProblemSaveInput, error) {
+	bankProblemID := chi.URLParam(r, "bankProblemID")
+	if err := shared.ValidateUUID(bankProblemID); err != nil {
+		return ProblemSaveInput{}, err
+	}
+	_, problem, err := decodeProblem(r)
+	if err != nil {
+		return ProblemSaveInput{}, err
+	}
+	return ProblemSaveInput{BankProblemID: bankProblemID, Problem: problem}, nil
+}
+
+type problemSaveInputFn func(r *http.Request) (ProblemSaveInput, error)
+
+type ProblemRegisterStorage interface {
+	SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error)
+	InsertBankProblem(ctx context.Context, companyID string, problem shared.Problem) (string, error)
+}
+
+func CreateProblemRegisterHandler(
+	inputFn problemSaveInputFn,
+	authz shared.AuthRep,
+	storage ProblemRegisterStorage,
+	redirPath string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authz.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		role, err := storage.SelectCompanyRole(r.Context(), input.CompanyID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !shared.CanEdit(role) {
+			http.Error(w, errNotEditor, http.StatusUnauthorized)
+			return
+		}
+		bankProblemID, err := storage.InsertBankProblem(r.Context(), input.CompanyID, input.Problem)
+		if err != nil {
+			if errors.Is(err, shared.ErrGenerator) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, fmt.Sprintf("storage error: %s", err.Error()), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("HX-Redirect", redirPath+bankProblemID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+type ProblemUpdateStorage interface {
+	UpdateBankProblem(ctx context.Context, bankProblemID string, userID string, problem shared.Problem) (int32, error)
+}
+
+// CreateProblemUpdateHandler saves the edit as a new version of the problem,
+// quizzes already using it keep the version they were published with
+func CreateProblemUpdateHandler(
+	inputFn problemSaveInputFn,
+	authz shared.AuthRep,
+	storage ProblemUpdateStorage,
+	redirPath string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authz.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err := storage.UpdateBankProblem(r.Context(), input.BankProblemID, user.ID, input.Problem); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				http.Error(w, "problem not found", http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, shared.ErrGenerator) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, fmt.Sprintf("storage error: %s", err.Error()), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("HX-Redirect", redirPath+input.BankProblemID)
+		w.WriteHeader(http.StatusOK)
+	}
+}
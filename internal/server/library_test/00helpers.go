@@ -0,0 +1,52 @@
+package librarytest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/stretchr/testify/mock"
+)
+
+type authRepo struct{}
+
+func (a authRepo) GetUser(r *http.Request) (userID auth.AuthUser, err error) {
+	return auth.AuthUser{ID: "1", Role: auth.AuthRole}, nil
+}
+
+type invalidAuthRepo struct{}
+
+func (i invalidAuthRepo) GetUser(r *http.Request) (userID auth.AuthUser, err error) {
+	return auth.AuthUser{}, errors.New("error")
+}
+
+type visitorAuthRepo struct{}
+
+func (v visitorAuthRepo) GetUser(r *http.Request) (userID auth.AuthUser, err error) {
+	return auth.AuthUser{Role: auth.NotAuthRole}, nil
+}
+
+type templates struct{}
+
+func (t *templates) Render(w io.Writer, name string, data interface{}) error {
+	return nil
+}
+
+type templatesMock struct {
+	mock.Mock
+}
+
+func (t *templatesMock) Render(w io.Writer, name string, data interface{}) error {
+	args := t.Called(w, name, data)
+	return args.Error(0)
+}
+
+func WithUrlParam(r *http.Request, key, value string) *http.Request {
+	chiCtx := chi.NewRouteContext()
+	req := r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add(key, value)
+	return req
+}
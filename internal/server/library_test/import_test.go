@@ -0,0 +1,192 @@
+package librarytest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/library"
+	"github.com/kw3a/spotted-server/internal/server/problempkg"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type importStorage struct {
+	mock.Mock
+}
+
+func (s *importStorage) SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error) {
+	args := s.Called(ctx, companyID, userID)
+	return args.String(0), args.Error(1)
+}
+func (s *importStorage) InsertBankProblems(ctx context.Context, companyID string, problems []shared.Problem) ([]string, error) {
+	args := s.Called(ctx, companyID, problems)
+	return args.Get(0).([]string), args.Error(1)
+}
+func (s *importStorage) SelectBankProblem(ctx context.Context, bankProblemID string, userID string) (shared.Problem, error) {
+	args := s.Called(ctx, bankProblemID, userID)
+	return args.Get(0).(shared.Problem), args.Error(1)
+}
+
+func (s *importStorage) ReadTestData(ctx context.Context, hash string) (string, error) {
+	args := s.Called(ctx, hash)
+	return args.String(0), args.Error(1)
+}
+
+func importInputFn(r *http.Request) (library.ProblemImportInput, error) {
+	return library.ProblemImportInput{CompanyID: "c", Problems: []shared.Problem{{Title: "a"}, {Title: "b"}}}, nil
+}
+
+func TestProblemImportHandlerBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (library.ProblemImportInput, error) {
+		return library.ProblemImportInput{}, fmt.Errorf("error")
+	}
+	storage := new(importStorage)
+	handler := library.CreateProblemImportHandler(invalidInputFn, authRepo{}, storage, "/library?companyID=")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestProblemImportHandlerNotOwner(t *testing.T) {
+	storage := new(importStorage)
+	storage.On("SelectCompanyRole", mock.Anything, "c", mock.Anything).Return(shared.RoleViewer, nil)
+	handler := library.CreateProblemImportHandler(importInputFn, authRepo{}, storage, "/library?companyID=")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestProblemImportHandlerBadStorage(t *testing.T) {
+	storage := new(importStorage)
+	storage.On("SelectCompanyRole", mock.Anything, "c", mock.Anything).Return(shared.RoleOwner, nil)
+	storage.On("InsertBankProblems", mock.Anything, "c", mock.Anything).Return([]string{}, fmt.Errorf("error"))
+	handler := library.CreateProblemImportHandler(importInputFn, authRepo{}, storage, "/library?companyID=")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestProblemImportHandler(t *testing.T) {
+	storage := new(importStorage)
+	storage.On("SelectCompanyRole", mock.Anything, "c", mock.Anything).Return(shared.RoleOwner, nil)
+	storage.On("InsertBankProblems", mock.Anything, "c", mock.MatchedBy(func(problems []shared.Problem) bool {
+		return len(problems) == 2
+	})).Return([]string{"b1", "b2"}, nil)
+	handler := library.CreateProblemImportHandler(importInputFn, authRepo{}, storage, "/library?companyID=")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if redirect := w.Header().Get("HX-Redirect"); redirect != "/library?companyID=c" {
+		t.Errorf("unexpected redirect %s", redirect)
+	}
+}
+
+func TestGetProblemImportInput(t *testing.T) {
+	var pkg bytes.Buffer
+	problem := shared.Problem{
+		Title:       "Echo",
+		Description: "Print the input",
+		TestCases:   []shared.TestCase{{Input: "a", Output: "a"}},
+	}
+	if err := problempkg.Write(&pkg, problem); err != nil {
+		t.Fatal(err)
+	}
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("companyID", "0b6f3e44-52c4-4c07-9d8b-4b1f2f0a6c11")
+	part, _ := mw.CreateFormFile("package", "echo.zip")
+	_, _ = part.Write(pkg.Bytes())
+	mw.Close()
+	req, _ := http.NewRequest("POST", "/library/import", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	input, err := library.GetProblemImportInput(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(input.Problems) != 1 || input.Problems[0].Title != "Echo" {
+		t.Errorf("unexpected problems %+v", input.Problems)
+	}
+}
+
+func TestProblemExportHandlerNotFound(t *testing.T) {
+	inputFn := func(r *http.Request) (library.ProblemExportInput, error) {
+		return library.ProblemExportInput{BankProblemID: "b"}, nil
+	}
+	storage := new(importStorage)
+	storage.On("SelectBankProblem", mock.Anything, "b", "1").Return(shared.Problem{}, fmt.Errorf("error"))
+	handler := library.CreateProblemExportHandler(inputFn, authRepo{}, storage)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestProblemExportHandler(t *testing.T) {
+	inputFn := func(r *http.Request) (library.ProblemExportInput, error) {
+		return library.ProblemExportInput{BankProblemID: "b"}, nil
+	}
+	storage := new(importStorage)
+	storage.On("SelectBankProblem", mock.Anything, "b", "1").Return(shared.Problem{
+		Title:       "Two Sum",
+		Description: "Add two numbers",
+		Version:     3,
+		TestCases:   []shared.TestCase{{Input: "1 2", Output: "3"}},
+	}, nil)
+	handler := library.CreateProblemExportHandler(inputFn, authRepo{}, storage)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if cd := w.Header().Get("Content-Disposition"); cd != `attachment; filename="two-sum-v3.zip"` {
+		t.Errorf("unexpected Content-Disposition %s", cd)
+	}
+	problems, err := problempkg.ReadZip(w.Body.Bytes())
+	if err != nil || len(problems) != 1 || problems[0].Title != "Two Sum" {
+		t.Errorf("expected a readable package, got %v %v", problems, err)
+	}
+}
+
+func TestProblemExportHandlerBlobData(t *testing.T) {
+	inputFn := func(r *http.Request) (library.ProblemExportInput, error) {
+		return library.ProblemExportInput{BankProblemID: "b"}, nil
+	}
+	storage := new(importStorage)
+	storage.On("SelectBankProblem", mock.Anything, "b", "1").Return(shared.Problem{
+		Title:       "Two Sum",
+		Description: "Add two numbers",
+		TestCases:   []shared.TestCase{{Input: "1 1 1", Output: "3", InputHash: "h"}},
+	}, nil)
+	storage.On("ReadTestData", mock.Anything, "h").Return("1 1 1 1", nil)
+	handler := library.CreateProblemExportHandler(inputFn, authRepo{}, storage)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	problems, err := problempkg.ReadZip(w.Body.Bytes())
+	if err != nil || len(problems) != 1 || problems[0].TestCases[0].Input != "1 1 1 1" {
+		t.Errorf("expected the whole test data in the package, got %v %v", problems, err)
+	}
+}
@@ -0,0 +1,150 @@
+package librarytest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/library"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type libraryStorage struct {
+	mock.Mock
+}
+
+func (s *libraryStorage) GetCompanies(ctx context.Context, params shared.CompanyQueryParams) ([]shared.Company, error) {
+	args := s.Called(ctx, params)
+	return args.Get(0).([]shared.Company), args.Error(1)
+}
+func (s *libraryStorage) SelectBankProblems(ctx context.Context, companyID string) ([]shared.BankProblem, error) {
+	args := s.Called(ctx, companyID)
+	return args.Get(0).([]shared.BankProblem), args.Error(1)
+}
+func (s *libraryStorage) SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error) {
+	args := s.Called(ctx, companyID, userID)
+	return args.String(0), args.Error(1)
+}
+
+func libraryInputFn(r *http.Request) (library.LibraryInput, error) {
+	return library.LibraryInput{CompanyID: "c2"}, nil
+}
+
+func TestLibraryHandlerBadAuth(t *testing.T) {
+	storage := new(libraryStorage)
+	handler := library.CreateLibraryHandler(libraryInputFn, invalidAuthRepo{}, storage, &templates{}, "/red")
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestLibraryHandlerVisitor(t *testing.T) {
+	storage := new(libraryStorage)
+	handler := library.CreateLibraryHandler(libraryInputFn, visitorAuthRepo{}, storage, &templates{}, "/red")
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusSeeOther {
+		t.Errorf("expected status %d, got %d", http.StatusSeeOther, w.Code)
+	}
+}
+
+func TestLibraryHandlerNoCompanies(t *testing.T) {
+	storage := new(libraryStorage)
+	storage.On("GetCompanies", mock.Anything, mock.Anything).Return([]shared.Company{}, nil)
+	handler := library.CreateLibraryHandler(libraryInputFn, authRepo{}, storage, &templates{}, "/red")
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusSeeOther {
+		t.Errorf("expected status %d, got %d", http.StatusSeeOther, w.Code)
+	}
+}
+
+func TestLibraryHandlerBadStorageSelectBankProblems(t *testing.T) {
+	storage := new(libraryStorage)
+	storage.On("GetCompanies", mock.Anything, mock.Anything).Return([]shared.Company{{ID: "c1"}}, nil)
+	storage.On("SelectBankProblems", mock.Anything, mock.Anything).Return([]shared.BankProblem{}, fmt.Errorf("error"))
+	handler := library.CreateLibraryHandler(libraryInputFn, authRepo{}, storage, &templates{}, "/red")
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestLibraryHandlerSelectedCompany(t *testing.T) {
+	storage := new(libraryStorage)
+	storage.On("GetCompanies", mock.Anything, mock.Anything).Return([]shared.Company{{ID: "c1"}, {ID: "c2"}}, nil)
+	storage.On("SelectBankProblems", mock.Anything, "c2").Return([]shared.BankProblem{{ID: "b"}}, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "library", mock.MatchedBy(func(data library.LibraryData) bool {
+		return data.Company.ID == "c2" && len(data.Problems) == 1
+	})).Return(nil)
+	handler := library.CreateLibraryHandler(libraryInputFn, authRepo{}, storage, templ, "/red")
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestBankOptionsHandlerNotOwner(t *testing.T) {
+	storage := new(libraryStorage)
+	storage.On("SelectCompanyRole", mock.Anything, mock.Anything, mock.Anything).Return(shared.RoleViewer, nil)
+	handler := library.CreateBankOptionsHandler(libraryInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestBankOptionsHandlerMissingCompany(t *testing.T) {
+	inputFn := func(r *http.Request) (library.LibraryInput, error) {
+		return library.LibraryInput{}, nil
+	}
+	storage := new(libraryStorage)
+	handler := library.CreateBankOptionsHandler(inputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestBankOptionsHandler(t *testing.T) {
+	storage := new(libraryStorage)
+	storage.On("SelectCompanyRole", mock.Anything, mock.Anything, mock.Anything).Return(shared.RoleOwner, nil)
+	storage.On("SelectBankProblems", mock.Anything, "c2").Return([]shared.BankProblem{{ID: "b"}}, nil)
+	handler := library.CreateBankOptionsHandler(libraryInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestGetLibraryInput(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/library?companyID=bad", nil)
+	if _, err := library.GetLibraryInput(req); err == nil {
+		t.Error("expected error for invalid company")
+	}
+	req, _ = http.NewRequest("GET", "/library", nil)
+	input, err := library.GetLibraryInput(req)
+	if err != nil || input.CompanyID != "" {
+		t.Errorf("expected empty input, got %v %v", input, err)
+	}
+}
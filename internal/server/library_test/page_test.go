@@ -0,0 +1,142 @@
+package librarytest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/library"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type pageStorage struct {
+	mock.Mock
+}
+
+func (s *pageStorage) SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error) {
+	args := s.Called(ctx, companyID, userID)
+	return args.String(0), args.Error(1)
+}
+func (s *pageStorage) SelectBankProblem(ctx context.Context, bankProblemID string, userID string) (shared.Problem, error) {
+	args := s.Called(ctx, bankProblemID, userID)
+	return args.Get(0).(shared.Problem), args.Error(1)
+}
+func (s *pageStorage) SelectProblemVersions(ctx context.Context, bankProblemID string) ([]shared.ProblemVersion, error) {
+	args := s.Called(ctx, bankProblemID)
+	return args.Get(0).([]shared.ProblemVersion), args.Error(1)
+}
+
+func (s *pageStorage) SelectReferenceSolutions(ctx context.Context, problemID string) ([]shared.ReferenceSolution, error) {
+	args := s.Called(ctx, problemID)
+	return args.Get(0).([]shared.ReferenceSolution), args.Error(1)
+}
+func (s *pageStorage) GetLanguages(ctx context.Context) ([]shared.Language, error) {
+	args := s.Called(ctx)
+	return args.Get(0).([]shared.Language), args.Error(1)
+}
+
+func newPageInputFn(r *http.Request) (library.ProblemPageInput, error) {
+	return library.ProblemPageInput{CompanyID: "c"}, nil
+}
+
+func editPageInputFn(r *http.Request) (library.ProblemPageInput, error) {
+	return library.ProblemPageInput{BankProblemID: "b"}, nil
+}
+
+func TestProblemPageHandlerVisitor(t *testing.T) {
+	storage := new(pageStorage)
+	handler := library.CreateProblemPageHandler(newPageInputFn, visitorAuthRepo{}, storage, &templates{}, "/login")
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusSeeOther {
+		t.Errorf("expected status %d, got %d", http.StatusSeeOther, w.Code)
+	}
+}
+
+func TestProblemPageHandlerBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (library.ProblemPageInput, error) {
+		return library.ProblemPageInput{}, fmt.Errorf("error")
+	}
+	storage := new(pageStorage)
+	handler := library.CreateProblemPageHandler(invalidInputFn, authRepo{}, storage, &templates{}, "/login")
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestProblemPageHandlerNewNotOwner(t *testing.T) {
+	storage := new(pageStorage)
+	storage.On("SelectCompanyRole", mock.Anything, "c", mock.Anything).Return(shared.RoleViewer, nil)
+	handler := library.CreateProblemPageHandler(newPageInputFn, authRepo{}, storage, &templates{}, "/login")
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestProblemPageHandlerNew(t *testing.T) {
+	storage := new(pageStorage)
+	storage.On("SelectCompanyRole", mock.Anything, "c", mock.Anything).Return(shared.RoleOwner, nil)
+	handler := library.CreateProblemPageHandler(newPageInputFn, authRepo{}, storage, &templates{}, "/login")
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestProblemPageHandlerEditNotFound(t *testing.T) {
+	storage := new(pageStorage)
+	storage.On("SelectBankProblem", mock.Anything, "b", "1").Return(shared.Problem{}, fmt.Errorf("error"))
+	handler := library.CreateProblemPageHandler(editPageInputFn, authRepo{}, storage, &templates{}, "/login")
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestProblemPageHandlerEdit(t *testing.T) {
+	storage := new(pageStorage)
+	storage.On("SelectBankProblem", mock.Anything, "b", "1").Return(shared.Problem{ID: "p", BankProblemID: "b", Version: 2}, nil)
+	storage.On("SelectProblemVersions", mock.Anything, "b").Return([]shared.ProblemVersion{{Version: 2}, {Version: 1}}, nil)
+	storage.On("SelectReferenceSolutions", mock.Anything, "p").Return([]shared.ReferenceSolution{{ID: "s"}}, nil)
+	storage.On("GetLanguages", mock.Anything).Return([]shared.Language{{ID: 71}}, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "bankProblemPage", mock.MatchedBy(func(data library.ProblemPageData) bool {
+		return data.Problem.Version == 2 && len(data.Versions) == 2 && len(data.Solutions) == 1 && len(data.Languages) == 1
+	})).Return(nil)
+	handler := library.CreateProblemPageHandler(editPageInputFn, authRepo{}, storage, templ, "/login")
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestProblemPageHandlerEditBadStorageReferences(t *testing.T) {
+	storage := new(pageStorage)
+	storage.On("SelectBankProblem", mock.Anything, "b", "1").Return(shared.Problem{ID: "p", BankProblemID: "b"}, nil)
+	storage.On("SelectProblemVersions", mock.Anything, "b").Return([]shared.ProblemVersion{}, nil)
+	storage.On("SelectReferenceSolutions", mock.Anything, "p").Return([]shared.ReferenceSolution{}, fmt.Errorf("error"))
+	handler := library.CreateProblemPageHandler(editPageInputFn, authRepo{}, storage, &templates{}, "/login")
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
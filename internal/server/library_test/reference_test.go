@@ -0,0 +1,232 @@
+package librarytest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/codejudge"
+	"github.com/kw3a/spotted-server/internal/server/library"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type referenceStorage struct {
+	mock.Mock
+}
+
+func (s *referenceStorage) GetTestCases(ctx context.Context, problemID string) ([]codejudge.TestCase, error) {
+	args := s.Called(ctx, problemID)
+	return args.Get(0).([]codejudge.TestCase), args.Error(1)
+}
+func (s *referenceStorage) SelectProblem(ctx context.Context, problemID string) (shared.Problem, error) {
+	args := s.Called(ctx, problemID)
+	return args.Get(0).(shared.Problem), args.Error(1)
+}
+func (s *referenceStorage) InsertReferenceSolution(ctx context.Context, bankProblemID string, userID string, solution shared.ReferenceSolution) (shared.ReferenceSolution, error) {
+	args := s.Called(ctx, bankProblemID, userID, solution)
+	return args.Get(0).(shared.ReferenceSolution), args.Error(1)
+}
+func (s *referenceStorage) ResetReferenceResults(ctx context.Context, bankProblemID string, userID string) ([]shared.ReferenceSolution, error) {
+	args := s.Called(ctx, bankProblemID, userID)
+	return args.Get(0).([]shared.ReferenceSolution), args.Error(1)
+}
+func (s *referenceStorage) DeleteReferenceSolution(ctx context.Context, solutionID, bankProblemID, userID string) error {
+	args := s.Called(ctx, solutionID, bankProblemID, userID)
+	return args.Error(0)
+}
+func (s *referenceStorage) UpdateReferenceResult(ctx context.Context, input shared.CallbackJsonInput, solutionID, testCaseID string) error {
+	args := s.Called(ctx, input, solutionID, testCaseID)
+	return args.Error(0)
+}
+
+type judgeMock struct {
+	mock.Mock
+}
+
+func (j *judgeMock) Send(testCases []codejudge.TestCase, submission codejudge.Submission) ([]string, error) {
+	args := j.Called(testCases, submission)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func referenceInputFn(r *http.Request) (library.ReferenceInput, error) {
+	return library.ReferenceInput{
+		BankProblemID: "b",
+		SolutionID:    "s",
+		Solution:      shared.ReferenceSolution{LanguageID: 71, Expected: shared.ReferenceAccepted, Src: "print(1)"},
+	}, nil
+}
+
+func TestReferenceRegisterHandlerVisitor(t *testing.T) {
+	storage := new(referenceStorage)
+	handler := library.CreateReferenceRegisterHandler(referenceInputFn, visitorAuthRepo{}, storage, new(judgeMock), "/library/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestReferenceRegisterHandlerNotFound(t *testing.T) {
+	storage := new(referenceStorage)
+	storage.On("InsertReferenceSolution", mock.Anything, "b", "1", mock.Anything).Return(shared.ReferenceSolution{}, sql.ErrNoRows)
+	handler := library.CreateReferenceRegisterHandler(referenceInputFn, authRepo{}, storage, new(judgeMock), "/library/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestReferenceRegisterHandlerBadJudge(t *testing.T) {
+	storage := new(referenceStorage)
+	storage.On("InsertReferenceSolution", mock.Anything, "b", "1", mock.Anything).Return(shared.ReferenceSolution{ID: "s", ProblemID: "p"}, nil)
+	storage.On("GetTestCases", mock.Anything, "p").Return([]codejudge.TestCase{{ID: "tc"}}, nil)
+	storage.On("SelectProblem", mock.Anything, "p").Return(shared.Problem{}, nil)
+	judge := new(judgeMock)
+	judge.On("Send", mock.Anything, mock.Anything).Return([]string{}, fmt.Errorf("error"))
+	handler := library.CreateReferenceRegisterHandler(referenceInputFn, authRepo{}, storage, judge, "/library/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestReferenceRegisterHandler(t *testing.T) {
+	storage := new(referenceStorage)
+	storage.On("InsertReferenceSolution", mock.Anything, "b", "1", mock.Anything).Return(shared.ReferenceSolution{ID: "s", ProblemID: "p", Src: "print(1)", LanguageID: 71}, nil)
+	storage.On("GetTestCases", mock.Anything, "p").Return([]codejudge.TestCase{{ID: "tc", TimeLimit: 1}}, nil)
+	storage.On("SelectProblem", mock.Anything, "p").Return(shared.Problem{}, nil)
+	judge := new(judgeMock)
+	judge.On("Send", mock.MatchedBy(func(testCases []codejudge.TestCase) bool {
+		return len(testCases) == 1 && testCases[0].TimeLimit == float64(shared.ReferenceTimeLimit)/1000
+	}), codejudge.Submission{ID: "s", Src: "print(1)", LanguageID: 71}).Return([]string{"token"}, nil)
+	handler := library.CreateReferenceRegisterHandler(referenceInputFn, authRepo{}, storage, judge, "/library/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if got := w.Header().Get("HX-Redirect"); got != "/library/b" {
+		t.Errorf("expected redirect to /library/b, got %s", got)
+	}
+	judge.AssertExpectations(t)
+}
+
+func TestReferenceValidateHandler(t *testing.T) {
+	storage := new(referenceStorage)
+	storage.On("ResetReferenceResults", mock.Anything, "b", "1").Return([]shared.ReferenceSolution{
+		{ID: "s1", ProblemID: "p"},
+		{ID: "s2", ProblemID: "p"},
+	}, nil)
+	storage.On("GetTestCases", mock.Anything, "p").Return([]codejudge.TestCase{{ID: "tc"}}, nil)
+	storage.On("SelectProblem", mock.Anything, "p").Return(shared.Problem{}, nil)
+	judge := new(judgeMock)
+	judge.On("Send", mock.Anything, mock.Anything).Return([]string{"token"}, nil)
+	handler := library.CreateReferenceValidateHandler(referenceInputFn, authRepo{}, storage, judge, "/library/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	judge.AssertNumberOfCalls(t, "Send", 2)
+}
+
+func TestReferenceValidateHandlerWithoutSolutions(t *testing.T) {
+	storage := new(referenceStorage)
+	storage.On("ResetReferenceResults", mock.Anything, "b", "1").Return([]shared.ReferenceSolution{}, nil)
+	judge := new(judgeMock)
+	handler := library.CreateReferenceValidateHandler(referenceInputFn, authRepo{}, storage, judge, "/library/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	judge.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestReferenceDeleteHandlerBadStorage(t *testing.T) {
+	storage := new(referenceStorage)
+	storage.On("DeleteReferenceSolution", mock.Anything, "s", "b", "1").Return(fmt.Errorf("error"))
+	handler := library.CreateReferenceDeleteHandler(referenceInputFn, authRepo{}, storage, "/library/")
+	req, _ := http.NewRequest("DELETE", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestReferenceDeleteHandler(t *testing.T) {
+	storage := new(referenceStorage)
+	storage.On("DeleteReferenceSolution", mock.Anything, "s", "b", "1").Return(nil)
+	handler := library.CreateReferenceDeleteHandler(referenceInputFn, authRepo{}, storage, "/library/")
+	req, _ := http.NewRequest("DELETE", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestReferenceCallbackHandler(t *testing.T) {
+	log.SetOutput(io.Discard)
+	storage := new(referenceStorage)
+	storage.On("UpdateReferenceResult", mock.Anything, mock.Anything, "s", "tc").Return(nil)
+	inputFn := func(r *http.Request) (library.ReferenceCallbackInput, error) {
+		return library.ReferenceCallbackInput{SolutionID: "s", TestCaseID: "tc"}, nil
+	}
+	handler := library.CreateReferenceCallbackHandler(storage, shared.Decode[shared.CallbackJsonInput], inputFn)
+	body := strings.NewReader(`{"time":"0.012","memory":3000,"status":{"id":3,"description":"Accepted"}}`)
+	req, _ := http.NewRequest("PUT", "/", body)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+}
+
+func TestGetReferenceRegisterInput(t *testing.T) {
+	id := "0b7e1b8e-8d2c-4f5c-9e2a-3c1d2e4f5a6b"
+	cases := []struct {
+		form  url.Values
+		valid bool
+	}{
+		{url.Values{"languageID": {"71"}, "expected": {"accepted"}, "src": {"print(1)"}}, true},
+		{url.Values{"languageID": {"71"}, "expected": {"wrong"}, "src": {"print(2)"}}, true},
+		{url.Values{"languageID": {"71"}, "expected": {"maybe"}, "src": {"print(1)"}}, false},
+		{url.Values{"languageID": {"x"}, "expected": {"accepted"}, "src": {"print(1)"}}, false},
+		{url.Values{"languageID": {"71"}, "expected": {"accepted"}, "src": {"  "}}, false},
+		{url.Values{"languageID": {"71"}, "expected": {"accepted"}, "src": {strings.Repeat("a", library.MaxReferenceSrc+1)}}, false},
+	}
+	for i, c := range cases {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(c.form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req = WithUrlParam(req, "bankProblemID", id)
+		input, err := library.GetReferenceRegisterInput(req)
+		if c.valid && err != nil {
+			t.Errorf("case %d: unexpected error %v", i, err)
+		}
+		if !c.valid && err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+		if c.valid && input.BankProblemID != id {
+			t.Errorf("case %d: expected bank problem %s, got %s", i, id, input.BankProblemID)
+		}
+	}
+}
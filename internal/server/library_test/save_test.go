@@ -0,0 +1,132 @@
+package librarytest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/library"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type saveStorage struct {
+	mock.Mock
+}
+
+func (s *saveStorage) SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error) {
+	args := s.Called(ctx, companyID, userID)
+	return args.String(0), args.Error(1)
+}
+func (s *saveStorage) InsertBankProblem(ctx context.Context, companyID string, problem shared.Problem) (string, error) {
+	args := s.Called(ctx, companyID, problem)
+	return args.String(0), args.Error(1)
+}
+func (s *saveStorage) UpdateBankProblem(ctx context.Context, bankProblemID string, userID string, problem shared.Problem) (int32, error) {
+	args := s.Called(ctx, bankProblemID, userID, problem)
+	return args.Get(0).(int32), args.Error(1)
+}
+
+func saveInputFn(r *http.Request) (library.ProblemSaveInput, error) {
+	return library.ProblemSaveInput{CompanyID: "c", BankProblemID: "b"}, nil
+}
+
+func TestProblemRegisterHandlerBadAuth(t *testing.T) {
+	storage := new(saveStorage)
+	handler := library.CreateProblemRegisterHandler(saveInputFn, invalidAuthRepo{}, storage, "/library/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestProblemRegisterHandlerNotOwner(t *testing.T) {
+	storage := new(saveStorage)
+	storage.On("SelectCompanyRole", mock.Anything, "c", mock.Anything).Return(shared.RoleViewer, nil)
+	handler := library.CreateProblemRegisterHandler(saveInputFn, authRepo{}, storage, "/library/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestProblemRegisterHandlerBadStorage(t *testing.T) {
+	storage := new(saveStorage)
+	storage.On("SelectCompanyRole", mock.Anything, "c", mock.Anything).Return(shared.RoleOwner, nil)
+	storage.On("InsertBankProblem", mock.Anything, "c", mock.Anything).Return("", fmt.Errorf("error"))
+	handler := library.CreateProblemRegisterHandler(saveInputFn, authRepo{}, storage, "/library/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestProblemRegisterHandler(t *testing.T) {
+	storage := new(saveStorage)
+	storage.On("SelectCompanyRole", mock.Anything, "c", mock.Anything).Return(shared.RoleOwner, nil)
+	storage.On("InsertBankProblem", mock.Anything, "c", mock.Anything).Return("new", nil)
+	handler := library.CreateProblemRegisterHandler(saveInputFn, authRepo{}, storage, "/library/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if redirect := w.Header().Get("HX-Redirect"); redirect != "/library/new" {
+		t.Errorf("expected redirect to /library/new, got %s", redirect)
+	}
+}
+
+func TestProblemUpdateHandlerNotFound(t *testing.T) {
+	storage := new(saveStorage)
+	storage.On("UpdateBankProblem", mock.Anything, "b", "1", mock.Anything).Return(int32(0), sql.ErrNoRows)
+	handler := library.CreateProblemUpdateHandler(saveInputFn, authRepo{}, storage, "/library/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestProblemUpdateHandler(t *testing.T) {
+	storage := new(saveStorage)
+	storage.On("UpdateBankProblem", mock.Anything, "b", "1", mock.Anything).Return(int32(3), nil)
+	handler := library.CreateProblemUpdateHandler(saveInputFn, authRepo{}, storage, "/library/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if redirect := w.Header().Get("HX-Redirect"); redirect != "/library/b" {
+		t.Errorf("expected redirect to /library/b, got %s", redirect)
+	}
+}
+
+func TestGetProblemRegisterInput(t *testing.T) {
+	body := `{"companyID":"0b6f3e44-52c4-4c07-9d8b-4b1f2f0a6c11","problems":[{"title":"Suma","description":"Suma dos numeros","time_limit":"1000","test_cases":[{"input":"1 2","output":"3"}],"examples":[{"input":"2 2","output":"4"}]}]}`
+	req, _ := http.NewRequest("POST", "/library", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	input, err := library.GetProblemRegisterInput(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if input.Problem.Title != "Suma" || len(input.Problem.TestCases) != 1 {
+		t.Errorf("unexpected problem %+v", input.Problem)
+	}
+	req, _ = http.NewRequest("POST", "/library", strings.NewReader(`{"companyID":"bad","problems":[]}`))
+	if _, err := library.GetProblemRegisterInput(req); err == nil {
+		t.Error("expected error without problems")
+	}
+}
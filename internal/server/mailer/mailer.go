@@ -0,0 +1,58 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// Local logs every email and, with a directory, also keeps it there as an
+// .eml file a mail client can open. It stands in for a real provider
+// during development.
+type Local struct {
+	dir string
+}
+
+func NewLocal(dir string) (*Local, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o750); err != nil {
+			return nil, err
+		}
+	}
+	return &Local{dir: dir}, nil
+}
+
+// NewLocalFromEnv keeps the emails in MAIL_DIR, they are only logged when
+// it is not set
+func NewLocalFromEnv() (*Local, error) {
+	return NewLocal(os.Getenv("MAIL_DIR"))
+}
+
+func (l *Local) Send(ctx context.Context, email shared.Email) error {
+	if strings.ContainsAny(email.To+email.Subject, "\r\n") {
+		return fmt.Errorf("invalid email header")
+	}
+	log.Printf("mail: to %s: %s", email.To, email.Subject)
+	if l.dir == "" {
+		return nil
+	}
+	now := time.Now()
+	file, err := os.CreateTemp(l.dir, now.Format("20060102-150405")+"-*.eml")
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	_, err = fmt.Fprintf(file,
+		"To: %s\r\nSubject: %s\r\nDate: %s\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n%s\r\n",
+		email.To,
+		email.Subject,
+		now.Format(time.RFC1123Z),
+		email.Body,
+	)
+	return err
+}
@@ -0,0 +1,41 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+func TestLocalSend(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mails")
+	local, err := NewLocal(dir)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	err = local.Send(context.Background(), shared.Email{To: "ana@mail.com", Subject: "Hola", Body: "Cuerpo"})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("expected 1 email, got %d", len(files))
+	}
+	content, _ := os.ReadFile(files[0])
+	for _, expected := range []string{"To: ana@mail.com\r\n", "Subject: Hola\r\n", "\r\n\r\nCuerpo"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("expected %q in %q", expected, content)
+		}
+	}
+}
+
+func TestLocalSendHeaderInjection(t *testing.T) {
+	local, _ := NewLocal("")
+	err := local.Send(context.Background(), shared.Email{To: "ana@mail.com\r\nBcc: luis@mail.com", Subject: "Hola"})
+	if err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,258 @@
+package markdown
+
+import (
+	"html"
+	"html/template"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MaxLength bounds the Markdown of a statement, it matches the longest
+// description the forms accept
+const MaxLength = 5000
+
+var (
+	orderedItem   = regexp.MustCompile(`^\d{1,9}[.)] `)
+	fenceLanguage = regexp.MustCompile(`^[a-zA-Z0-9+#-]{1,20}$`)
+)
+
+// Render turns the Markdown of problems and offers into HTML. Every piece of
+// the source is escaped before it is written, so the only tags on the page are
+// the ones written here; links and images only take https URLs. Math between
+// $ or $$ is kept as text for KaTeX to typeset in the browser
+func Render(src string) template.HTML {
+	lines := strings.Split(strings.ReplaceAll(src, "\r\n", "\n"), "\n")
+	var b strings.Builder
+	renderBlocks(&b, lines)
+	// #nosec G203 -- every text and attribute above went through html.EscapeString
+	return template.HTML(b.String())
+}
+
+func renderBlocks(b *strings.Builder, lines []string) {
+	for i := 0; i < len(lines); {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			i++
+		case strings.HasPrefix(trimmed, "```"):
+			i = renderFence(b, lines, i)
+		case trimmed == "$$" || (strings.HasPrefix(trimmed, "$$") && strings.HasSuffix(trimmed, "$$") && len(trimmed) > 4):
+			i = renderMathBlock(b, lines, i)
+		case trimmed == "---" || trimmed == "***":
+			b.WriteString("<hr>")
+			i++
+		case heading(trimmed) > 0:
+			level := heading(trimmed)
+			// statements live inside pages that already have their own titles
+			tag := "h" + strconv.Itoa(min(level+2, 6))
+			b.WriteString("<" + tag + ">")
+			b.WriteString(inline(strings.TrimSpace(trimmed[level:])))
+			b.WriteString("</" + tag + ">")
+			i++
+		case strings.HasPrefix(trimmed, ">"):
+			quoted := []string{}
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				q := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(q, " "))
+			}
+			b.WriteString("<blockquote>")
+			renderBlocks(b, quoted)
+			b.WriteString("</blockquote>")
+		case listItem(trimmed) != "":
+			i = renderList(b, lines, i)
+		default:
+			paragraph := []string{}
+			for ; i < len(lines) && !startsBlock(lines[i]); i++ {
+				paragraph = append(paragraph, inline(strings.TrimSpace(lines[i])))
+			}
+			b.WriteString("<p>")
+			// the descriptions were plain text before, their line breaks are kept
+			b.WriteString(strings.Join(paragraph, "<br>"))
+			b.WriteString("</p>")
+		}
+	}
+}
+
+func startsBlock(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "" || strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "$$") ||
+		trimmed == "---" || trimmed == "***" || heading(trimmed) > 0 ||
+		strings.HasPrefix(trimmed, ">") || listItem(trimmed) != ""
+}
+
+func heading(line string) int {
+	level := 0
+	for level < len(line) && line[level] == '#' {
+		level++
+	}
+	if level == 0 || level > 6 || level >= len(line) || line[level] != ' ' {
+		return 0
+	}
+	return level
+}
+
+// listItem returns the list the line belongs to, ul or ol
+func listItem(line string) string {
+	if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") || strings.HasPrefix(line, "+ ") {
+		return "ul"
+	}
+	if orderedItem.MatchString(line) {
+		return "ol"
+	}
+	return ""
+}
+
+func renderList(b *strings.Builder, lines []string, i int) int {
+	kind := listItem(strings.TrimSpace(lines[i]))
+	b.WriteString("<" + kind + ">")
+	for i < len(lines) {
+		trimmed := strings.TrimSpace(lines[i])
+		if listItem(trimmed) != kind {
+			break
+		}
+		item := []string{inline(strings.TrimSpace(trimmed[strings.IndexByte(trimmed, ' '):]))}
+		i++
+		// indented lines continue the item
+		for i < len(lines) && strings.HasPrefix(lines[i], "  ") && !startsBlock(lines[i]) {
+			item = append(item, inline(strings.TrimSpace(lines[i])))
+			i++
+		}
+		b.WriteString("<li>" + strings.Join(item, "<br>") + "</li>")
+	}
+	b.WriteString("</" + kind + ">")
+	return i
+}
+
+func renderFence(b *strings.Builder, lines []string, i int) int {
+	language := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i]), "```"))
+	code := []string{}
+	for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+		code = append(code, lines[i])
+	}
+	b.WriteString("<pre><code")
+	if fenceLanguage.MatchString(language) {
+		b.WriteString(` class="language-` + html.EscapeString(strings.ToLower(language)) + `"`)
+	}
+	b.WriteString(">" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>")
+	return i + 1
+}
+
+func renderMathBlock(b *strings.Builder, lines []string, i int) int {
+	trimmed := strings.TrimSpace(lines[i])
+	math := []string{}
+	if trimmed != "$$" {
+		math = append(math, strings.TrimSuffix(strings.TrimPrefix(trimmed, "$$"), "$$"))
+		i++
+	} else {
+		for i++; i < len(lines) && strings.TrimSpace(lines[i]) != "$$"; i++ {
+			math = append(math, lines[i])
+		}
+		i++
+	}
+	b.WriteString(`<div class="math">\[` + html.EscapeString(strings.Join(math, "\n")) + `\]</div>`)
+	return i
+}
+
+func inline(text string) string {
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		c := text[i]
+		rest := text[i:]
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte("\\`*_[]()!$#>-+.", text[i+1]) >= 0:
+			b.WriteString(html.EscapeString(text[i+1 : i+2]))
+			i += 2
+			continue
+		case c == '`':
+			if end := strings.IndexByte(text[i+1:], '`'); end > 0 {
+				b.WriteString("<code>" + html.EscapeString(text[i+1:i+1+end]) + "</code>")
+				i += end + 2
+				continue
+			}
+		case c == '$':
+			if end := mathEnd(text[i+1:]); end > 0 {
+				b.WriteString(`<span class="math">\(` + html.EscapeString(text[i+1:i+1+end]) + `\)</span>`)
+				i += end + 2
+				continue
+			}
+		case strings.HasPrefix(rest, "!["):
+			if label, target, n, ok := link(text[i+1:]); ok {
+				if src, ok := safeURL(target); ok {
+					b.WriteString(`<img src="` + html.EscapeString(src) + `" alt="` + html.EscapeString(label) + `" loading="lazy">`)
+					i += n + 1
+					continue
+				}
+			}
+		case c == '[':
+			if label, target, n, ok := link(rest); ok {
+				if href, ok := safeURL(target); ok {
+					b.WriteString(`<a href="` + html.EscapeString(href) + `" target="_blank" rel="noopener noreferrer nofollow">`)
+					b.WriteString(inline(label) + "</a>")
+					i += n
+					continue
+				}
+			}
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			if end := strings.Index(text[i+2:], rest[:2]); end > 0 {
+				b.WriteString("<strong>" + inline(text[i+2:i+2+end]) + "</strong>")
+				i += end + 4
+				continue
+			}
+		case c == '*' || c == '_':
+			if end := strings.IndexByte(text[i+1:], c); end > 0 && text[i+1] != ' ' && wordBoundary(text, i) {
+				b.WriteString("<em>" + inline(text[i+1:i+1+end]) + "</em>")
+				i += end + 2
+				continue
+			}
+		}
+		b.WriteString(html.EscapeString(text[i : i+1]))
+		i++
+	}
+	return b.String()
+}
+
+// wordBoundary keeps snake_case names from turning into emphasis
+func wordBoundary(text string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	p := text[i-1]
+	return !(p >= 'a' && p <= 'z' || p >= 'A' && p <= 'Z' || p >= '0' && p <= '9')
+}
+
+// mathEnd finds the $ that closes inline math, math can't start or end with
+// a space so prices like $5 and $10 stay text
+func mathEnd(text string) int {
+	if text == "" || text[0] == ' ' || text[0] == '$' {
+		return -1
+	}
+	end := strings.IndexByte(text, '$')
+	if end <= 0 || text[end-1] == ' ' {
+		return -1
+	}
+	return end
+}
+
+// link parses [label](target) at the start of text and returns its length
+func link(text string) (label string, target string, n int, ok bool) {
+	closing := strings.Index(text, "](")
+	if !strings.HasPrefix(text, "[") || closing < 0 {
+		return "", "", 0, false
+	}
+	end := strings.IndexByte(text[closing+2:], ')')
+	if end < 0 {
+		return "", "", 0, false
+	}
+	return text[1:closing], strings.TrimSpace(text[closing+2 : closing+2+end]), closing + 3 + end, true
+}
+
+func safeURL(raw string) (string, bool) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return "", false
+	}
+	return u.String(), true
+}
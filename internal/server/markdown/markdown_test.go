@@ -0,0 +1,50 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	cases := map[string]string{
+		"Hola **mundo** y *tú*":        "<p>Hola <strong>mundo</strong> y <em>tú</em></p>",
+		"línea uno\nlínea dos":         "<p>línea uno<br>línea dos</p>",
+		"# Entrada":                    "<h3>Entrada</h3>",
+		"- a\n- b\n\n1. c":             "<ul><li>a</li><li>b</li></ul><ol><li>c</li></ol>",
+		"usa `a < b` aquí":             "<p>usa <code>a &lt; b</code> aquí</p>",
+		"```go\nif a < b {}\n```":      `<pre><code class="language-go">if a &lt; b {}</code></pre>`,
+		"> cita":                       "<blockquote><p>cita</p></blockquote>",
+		"sea $n \\le 10^5$":            `<p>sea <span class="math">\(n \le 10^5\)</span></p>`,
+		"$$\n\\sum_{i=1}^n a_i\n$$":    `<div class="math">\[\sum_{i=1}^n a_i\]</div>`,
+		"cuesta $5 o $10":              "<p>cuesta $5 o $10</p>",
+		"la variable max_value_x":      "<p>la variable max_value_x</p>",
+		"[datos](https://x.io/a.zip)":  `<p><a href="https://x.io/a.zip" target="_blank" rel="noopener noreferrer nofollow">datos</a></p>`,
+		"![grafo](https://x.io/g.png)": `<p><img src="https://x.io/g.png" alt="grafo" loading="lazy"></p>`,
+		"\\*literal\\*":                "<p>*literal*</p>",
+		"---":                          "<hr>",
+	}
+	for src, expected := range cases {
+		if got := string(Render(src)); got != expected {
+			t.Errorf("%q: expected %q, got %q", src, expected, got)
+		}
+	}
+}
+
+func TestRenderSanitizes(t *testing.T) {
+	cases := []string{
+		"<script>alert(1)</script>",
+		"<img src=x onerror=alert(1)>",
+		"[x](javascript:alert(1))",
+		"![x](http://insecure.io/a.png)",
+		"[x](https://a.io/\" onmouseover=\"alert(1))",
+		"```\"><script>\n</script>\n```",
+		"$</span><script>$",
+	}
+	for _, src := range cases {
+		got := string(Render(src))
+		if strings.Contains(got, "<script") || strings.Contains(got, `href="javascript`) ||
+			strings.Contains(got, "<img src=x") || strings.Contains(got, `src="http://`) || strings.Contains(got, `" onmouseover`) {
+			t.Errorf("%q: unsafe output %q", src, got)
+		}
+	}
+}
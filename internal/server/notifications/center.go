@@ -0,0 +1,176 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// the digest goes out every day at this hour of the server
+const digestHour = 8
+
+// Broker tells the open pages of a user that their notifications changed,
+// a pending signal is enough since the stream reads them again
+type Broker struct {
+	listeners map[string][]chan struct{}
+	mu        sync.Mutex
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		listeners: make(map[string][]chan struct{}),
+	}
+}
+
+func (b *Broker) Subscribe(userID string) chan struct{} {
+	ch := make(chan struct{}, 1)
+	b.mu.Lock()
+	b.listeners[userID] = append(b.listeners[userID], ch)
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *Broker) Unsubscribe(userID string, ch chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	listeners := b.listeners[userID]
+	for i, l := range listeners {
+		if l == ch {
+			listeners = append(listeners[:i], listeners[i+1:]...)
+			break
+		}
+	}
+	if len(listeners) == 0 {
+		delete(b.listeners, userID)
+		return
+	}
+	b.listeners[userID] = listeners
+}
+
+func (b *Broker) Publish(userID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.listeners[userID] {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+type CenterStorage interface {
+	InsertNotifications(ctx context.Context, event shared.NotificationEvent) ([]shared.Notification, error)
+}
+
+type Publisher interface {
+	Publish(userID string)
+}
+
+// Center writes the notifications of an event and delivers each one the
+// way its recipient prefers
+type Center struct {
+	storage   CenterStorage
+	publisher Publisher
+	mailer    shared.Mailer
+	baseURL   string
+}
+
+func NewCenter(storage CenterStorage, publisher Publisher, mailer shared.Mailer, baseURL string) *Center {
+	return &Center{
+		storage:   storage,
+		publisher: publisher,
+		mailer:    mailer,
+		baseURL:   baseURL,
+	}
+}
+
+func (c *Center) Notify(ctx context.Context, event shared.NotificationEvent) error {
+	notifications, err := c.storage.InsertNotifications(ctx, event)
+	if err != nil {
+		return err
+	}
+	for _, notification := range notifications {
+		if notification.Preference.InApp {
+			c.publisher.Publish(notification.UserID)
+		}
+		if notification.Preference.Email != shared.EmailInstant || notification.Email == "" {
+			continue
+		}
+		err := c.mailer.Send(ctx, shared.Email{
+			To:      notification.Email,
+			Subject: notification.Title,
+			Body:    notification.Title + "\n\n" + c.baseURL + notification.Link,
+		})
+		if err != nil {
+			log.Println("notifications:", err)
+		}
+	}
+	return nil
+}
+
+type DigestStorage interface {
+	SelectDigests(ctx context.Context) ([]shared.Digest, error)
+	ClearDigest(ctx context.Context, digest shared.Digest) error
+}
+
+// NextDigest is the first digest hour after now
+func NextDigest(now time.Time) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), digestHour, 0, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+// RunDigest sends the daily digests at the digest hour until the context
+// is cancelled
+func RunDigest(ctx context.Context, storage DigestStorage, mailer shared.Mailer, baseURL string) {
+	for {
+		timer := time.NewTimer(time.Until(NextDigest(time.Now())))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			SendDigests(ctx, storage, mailer, baseURL)
+		}
+	}
+}
+
+// SendDigests keeps a digest that could not be sent for the next day
+func SendDigests(ctx context.Context, storage DigestStorage, mailer shared.Mailer, baseURL string) {
+	digests, err := storage.SelectDigests(ctx)
+	if err != nil {
+		log.Println("digest:", err)
+		return
+	}
+	for _, digest := range digests {
+		if digest.Email != "" {
+			if err := mailer.Send(ctx, DigestEmail(digest, baseURL)); err != nil {
+				log.Println("digest:", err)
+				continue
+			}
+		}
+		if err := storage.ClearDigest(ctx, digest); err != nil {
+			log.Println("digest:", err)
+		}
+	}
+}
+
+func DigestEmail(digest shared.Digest, baseURL string) shared.Email {
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hola %s, esto pasó desde el último resumen:\n\n", digest.Name)
+	for _, notification := range digest.Notifications {
+		fmt.Fprintf(&body, "- %s\n  %s%s\n", notification.Title, baseURL, notification.Link)
+	}
+	return shared.Email{
+		To:      digest.Email,
+		Subject: fmt.Sprintf("Tu resumen diario: %d notificaciones", len(digest.Notifications)),
+		Body:    body.String(),
+	}
+}
@@ -0,0 +1,312 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const (
+	errNotAuthorized = "you must be authenticated to use this function"
+	badgeEvent       = "badge"
+	listEvent        = "notifications"
+)
+
+// NotificationsData is the badge and the dropdown of the navigation bar
+type NotificationsData struct {
+	Unread        int
+	Notifications []shared.Notification
+}
+
+type EmailModeOption struct {
+	Name  string
+	Label string
+}
+
+type PreferencesData struct {
+	Preferences []shared.NotificationPreference
+	Modes       []EmailModeOption
+	Alert       shared.Alert
+}
+
+type PreferencesPageData struct {
+	User        auth.AuthUser
+	Preferences PreferencesData
+}
+
+type StreamStorage interface {
+	SelectNotifications(ctx context.Context, userID string) ([]shared.Notification, error)
+	CountUnreadNotifications(ctx context.Context, userID string) (int, error)
+}
+
+type Subscriber interface {
+	Subscribe(userID string) chan struct{}
+	Unsubscribe(userID string, ch chan struct{})
+}
+
+type ReadStorage interface {
+	ReadNotification(ctx context.Context, userID, notificationID string) (shared.Notification, error)
+	ReadNotifications(ctx context.Context, userID string) error
+}
+
+type PreferencesStorage interface {
+	SelectNotificationPreferences(ctx context.Context, userID string) ([]shared.NotificationPreference, error)
+	UpdateNotificationPreferences(ctx context.Context, userID string, preferences []shared.NotificationPreference) error
+}
+
+func emailModeOptions() []EmailModeOption {
+	options := []EmailModeOption{}
+	for _, mode := range shared.EmailModes {
+		options = append(options, EmailModeOption{Name: mode, Label: shared.EmailModeLabels[mode]})
+	}
+	return options
+}
+
+// FormatSSEvent prefixes every line of the rendered html, a blank line
+// would end the event early
+func FormatSSEvent(event, html string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "event: %s\n", event)
+	for _, line := range strings.Split(strings.TrimSpace(html), "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func renderEvents(ctx context.Context, templ shared.TemplatesRepo, storage StreamStorage, userID string) (string, error) {
+	notifications, err := storage.SelectNotifications(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	unread, err := storage.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	data := NotificationsData{Unread: unread, Notifications: notifications}
+	var badge, list bytes.Buffer
+	if err := templ.Render(&badge, "notificationBadge", data); err != nil {
+		return "", err
+	}
+	if err := templ.Render(&list, "notificationList", data); err != nil {
+		return "", err
+	}
+	return FormatSSEvent(badgeEvent, badge.String()) + FormatSSEvent(listEvent, list.String()), nil
+}
+
+// CreateStreamHandler pushes the badge and the dropdown when the page
+// connects and every time the notifications of the user change
+func CreateStreamHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage StreamStorage,
+	subscriber Subscriber,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "sse is not suppported", http.StatusInternalServerError)
+			return
+		}
+		listener := subscriber.Subscribe(user.ID)
+		defer subscriber.Unsubscribe(user.ID, listener)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		for {
+			events, err := renderEvents(r.Context(), templ, storage, user.ID)
+			if err != nil {
+				log.Println("notifications:", err)
+				return
+			}
+			if _, err := fmt.Fprint(w, events); err != nil {
+				log.Println("notifications:", err)
+				return
+			}
+			flusher.Flush()
+			select {
+			case <-r.Context().Done():
+				return
+			case <-listener:
+			}
+		}
+	}
+}
+
+type ReadInput struct {
+	NotificationID string
+}
+
+func GetReadInput(r *http.Request) (ReadInput, error) {
+	notificationID := chi.URLParam(r, "notificationID")
+	if err := shared.ValidateUUID(notificationID); err != nil {
+		return ReadInput{}, err
+	}
+	return ReadInput{NotificationID: notificationID}, nil
+}
+
+type readInputFn func(r *http.Request) (ReadInput, error)
+
+// CreateReadHandler marks the notification as read and sends the user to
+// what it is about
+func CreateReadHandler(
+	authService shared.AuthRep,
+	storage ReadStorage,
+	publisher Publisher,
+	inputFn readInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		notification, err := storage.ReadNotification(r.Context(), user.ID, input.NotificationID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		publisher.Publish(user.ID)
+		w.Header().Add("HX-Redirect", notification.Link)
+	}
+}
+
+// CreateReadAllHandler answers without content, the stream refreshes the
+// badge and the dropdown
+func CreateReadAllHandler(
+	authService shared.AuthRep,
+	storage ReadStorage,
+	publisher Publisher,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if err := storage.ReadNotifications(r.Context(), user.ID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		publisher.Publish(user.ID)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func CreatePreferencesPageHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage PreferencesStorage,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		preferences, err := storage.SelectNotificationPreferences(r.Context(), user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = templ.Render(w, "notificationPreferencesPage", PreferencesPageData{
+			User: user,
+			Preferences: PreferencesData{
+				Preferences: preferences,
+				Modes:       emailModeOptions(),
+			},
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type PreferencesInput struct {
+	Preferences []shared.NotificationPreference
+}
+
+// GetPreferencesInput reads a checkbox and an email mode per category, an
+// unchecked checkbox is not sent
+func GetPreferencesInput(r *http.Request) (PreferencesInput, error) {
+	if err := r.ParseForm(); err != nil {
+		return PreferencesInput{}, err
+	}
+	preferences := []shared.NotificationPreference{}
+	for _, category := range shared.NotificationCategories {
+		email := r.FormValue("email-" + category)
+		if !slices.Contains(shared.EmailModes, email) {
+			return PreferencesInput{}, shared.ErrNotificationPreference
+		}
+		preferences = append(preferences, shared.NotificationPreference{
+			Category: category,
+			InApp:    r.FormValue("inApp-"+category) == "on",
+			Email:    email,
+		})
+	}
+	return PreferencesInput{Preferences: preferences}, nil
+}
+
+type preferencesInputFn func(r *http.Request) (PreferencesInput, error)
+
+func CreatePreferencesHandler(
+	templ shared.TemplatesRepo,
+	authService shared.AuthRep,
+	storage PreferencesStorage,
+	inputFn preferencesInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := storage.UpdateNotificationPreferences(r.Context(), user.ID, input.Preferences); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = templ.Render(w, "notificationPreferences", PreferencesData{
+			Preferences: input.Preferences,
+			Modes:       emailModeOptions(),
+			Alert:       shared.Alert{Ok: true, Msg: shared.MsgSaved},
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package notificationstest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type authRepo struct{}
+
+func (a authRepo) GetUser(r *http.Request) (userID auth.AuthUser, err error) {
+	return auth.AuthUser{ID: "user-id"}, nil
+}
+
+type invalidAuthRepo struct{}
+
+func (i invalidAuthRepo) GetUser(r *http.Request) (userID auth.AuthUser, err error) {
+	return auth.AuthUser{}, errors.New("error")
+}
+
+type visitorAuthRepo struct{}
+
+func (v visitorAuthRepo) GetUser(r *http.Request) (userID auth.AuthUser, err error) {
+	return auth.AuthUser{Role: auth.NotAuthRole}, nil
+}
+
+type templates struct{}
+
+func (t *templates) Render(w io.Writer, name string, data interface{}) error {
+	return nil
+}
+
+type invalidTemplates struct{}
+
+func (i invalidTemplates) Render(w io.Writer, name string, data interface{}) error {
+	return errors.New("error")
+}
+
+type templatesMock struct {
+	mock.Mock
+}
+
+func (t *templatesMock) Render(w io.Writer, name string, data interface{}) error {
+	args := t.Called(w, name, data)
+	return args.Error(0)
+}
+
+type publisher struct {
+	mock.Mock
+}
+
+func (p *publisher) Publish(userID string) {
+	p.Called(userID)
+}
+
+type mailer struct {
+	mock.Mock
+}
+
+func (m *mailer) Send(ctx context.Context, email shared.Email) error {
+	args := m.Called(ctx, email)
+	return args.Error(0)
+}
+
+func WithUrlParam(r *http.Request, key, value string) *http.Request {
+	chiCtx := chi.NewRouteContext()
+	req := r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, chiCtx))
+	chiCtx.URLParams.Add(key, value)
+	return req
+}
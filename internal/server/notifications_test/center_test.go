@@ -0,0 +1,149 @@
+package notificationstest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/server/notifications"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type centerStorage struct {
+	mock.Mock
+}
+
+func (c *centerStorage) InsertNotifications(ctx context.Context, event shared.NotificationEvent) ([]shared.Notification, error) {
+	args := c.Called(ctx, event)
+	return args.Get(0).([]shared.Notification), args.Error(1)
+}
+
+type digestStorage struct {
+	mock.Mock
+}
+
+func (d *digestStorage) SelectDigests(ctx context.Context) ([]shared.Digest, error) {
+	args := d.Called(ctx)
+	return args.Get(0).([]shared.Digest), args.Error(1)
+}
+
+func (d *digestStorage) ClearDigest(ctx context.Context, digest shared.Digest) error {
+	args := d.Called(ctx, digest)
+	return args.Error(0)
+}
+
+func TestBroker(t *testing.T) {
+	broker := notifications.NewBroker()
+	listener := broker.Subscribe("user-id")
+	broker.Publish("user-id")
+	broker.Publish("user-id")
+	select {
+	case <-listener:
+	default:
+		t.Fatal("expected a signal")
+	}
+	select {
+	case <-listener:
+		t.Fatal("expected the signals to be merged")
+	default:
+	}
+	broker.Unsubscribe("user-id", listener)
+	broker.Publish("user-id")
+	select {
+	case <-listener:
+		t.Fatal("expected no signal after unsubscribing")
+	default:
+	}
+}
+
+func TestNotifyBadStorage(t *testing.T) {
+	storage := new(centerStorage)
+	storage.On("InsertNotifications", mock.Anything, mock.Anything).Return([]shared.Notification{}, errors.New("error"))
+	center := notifications.NewCenter(storage, new(publisher), new(mailer), "https://spotted.dev")
+	if err := center.Notify(context.Background(), shared.NotificationEvent{}); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestNotify(t *testing.T) {
+	event := shared.NotificationEvent{Category: shared.NotifyArchived, OfferID: "offer-id"}
+	storage := new(centerStorage)
+	storage.On("InsertNotifications", mock.Anything, event).Return([]shared.Notification{
+		{UserID: "inbox", Title: "A", Link: "/a", Email: "inbox@mail.com",
+			Preference: shared.NotificationPreference{InApp: true, Email: shared.EmailOff}},
+		{UserID: "instant", Title: "B", Link: "/b", Email: "instant@mail.com",
+			Preference: shared.NotificationPreference{InApp: false, Email: shared.EmailInstant}},
+		{UserID: "digest", Title: "C", Link: "/c", Email: "digest@mail.com",
+			Preference: shared.NotificationPreference{InApp: false, Email: shared.EmailDigest}},
+	}, nil)
+	publisher := new(publisher)
+	publisher.On("Publish", "inbox").Return()
+	mailer := new(mailer)
+	mailer.On("Send", mock.Anything, shared.Email{
+		To:      "instant@mail.com",
+		Subject: "B",
+		Body:    "B\n\nhttps://spotted.dev/b",
+	}).Return(errors.New("error"))
+	center := notifications.NewCenter(storage, publisher, mailer, "https://spotted.dev")
+	if err := center.Notify(context.Background(), event); err != nil {
+		t.Errorf("expected a failed email to be ignored, got %v", err)
+	}
+	publisher.AssertExpectations(t)
+	publisher.AssertNumberOfCalls(t, "Publish", 1)
+	mailer.AssertExpectations(t)
+	mailer.AssertNumberOfCalls(t, "Send", 1)
+}
+
+func TestNextDigest(t *testing.T) {
+	cases := map[time.Time]time.Time{
+		time.Date(2024, 5, 1, 7, 59, 0, 0, time.UTC):  time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC):   time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 31, 20, 0, 0, 0, time.UTC): time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC),
+	}
+	for now, expected := range cases {
+		if next := notifications.NextDigest(now); !next.Equal(expected) {
+			t.Errorf("%v: expected %v, got %v", now, expected, next)
+		}
+	}
+}
+
+func TestSendDigests(t *testing.T) {
+	sent := shared.Digest{UserID: "ana", Name: "Ana", Email: "ana@mail.com",
+		Notifications: []shared.Notification{{Title: "A", Link: "/a"}, {Title: "B", Link: "/b"}}}
+	failed := shared.Digest{UserID: "luis", Name: "Luis", Email: "luis@mail.com",
+		Notifications: []shared.Notification{{Title: "C", Link: "/c"}}}
+	noEmail := shared.Digest{UserID: "eva", Notifications: []shared.Notification{{Title: "D", Link: "/d"}}}
+	storage := new(digestStorage)
+	storage.On("SelectDigests", mock.Anything).Return([]shared.Digest{sent, failed, noEmail}, nil)
+	storage.On("ClearDigest", mock.Anything, sent).Return(nil)
+	storage.On("ClearDigest", mock.Anything, noEmail).Return(nil)
+	mailer := new(mailer)
+	mailer.On("Send", mock.Anything, notifications.DigestEmail(sent, "https://spotted.dev")).Return(nil)
+	mailer.On("Send", mock.Anything, notifications.DigestEmail(failed, "https://spotted.dev")).Return(errors.New("error"))
+	notifications.SendDigests(context.Background(), storage, mailer, "https://spotted.dev")
+	storage.AssertExpectations(t)
+	storage.AssertNotCalled(t, "ClearDigest", mock.Anything, failed)
+	mailer.AssertExpectations(t)
+}
+
+func TestDigestEmail(t *testing.T) {
+	email := notifications.DigestEmail(shared.Digest{
+		Name:          "Ana",
+		Email:         "ana@mail.com",
+		Notifications: []shared.Notification{{Title: "A", Link: "/a"}, {Title: "B", Link: "/b"}},
+	}, "https://spotted.dev")
+	if email.To != "ana@mail.com" {
+		t.Errorf("unexpected recipient %s", email.To)
+	}
+	if !strings.Contains(email.Subject, "2") {
+		t.Errorf("expected the count in the subject, got %s", email.Subject)
+	}
+	for _, expected := range []string{"Ana", "- A\n  https://spotted.dev/a", "- B\n  https://spotted.dev/b"} {
+		if !strings.Contains(email.Body, expected) {
+			t.Errorf("expected %q in %q", expected, email.Body)
+		}
+	}
+}
@@ -0,0 +1,341 @@
+package notificationstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/notifications"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type streamStorage struct {
+	mock.Mock
+}
+
+func (s *streamStorage) SelectNotifications(ctx context.Context, userID string) ([]shared.Notification, error) {
+	args := s.Called(ctx, userID)
+	return args.Get(0).([]shared.Notification), args.Error(1)
+}
+
+func (s *streamStorage) CountUnreadNotifications(ctx context.Context, userID string) (int, error) {
+	args := s.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
+type readStorage struct {
+	mock.Mock
+}
+
+func (s *readStorage) ReadNotification(ctx context.Context, userID, notificationID string) (shared.Notification, error) {
+	args := s.Called(ctx, userID, notificationID)
+	return args.Get(0).(shared.Notification), args.Error(1)
+}
+
+func (s *readStorage) ReadNotifications(ctx context.Context, userID string) error {
+	args := s.Called(ctx, userID)
+	return args.Error(0)
+}
+
+type preferencesStorage struct {
+	mock.Mock
+}
+
+func (s *preferencesStorage) SelectNotificationPreferences(ctx context.Context, userID string) ([]shared.NotificationPreference, error) {
+	args := s.Called(ctx, userID)
+	return args.Get(0).([]shared.NotificationPreference), args.Error(1)
+}
+
+func (s *preferencesStorage) UpdateNotificationPreferences(ctx context.Context, userID string, preferences []shared.NotificationPreference) error {
+	args := s.Called(ctx, userID, preferences)
+	return args.Error(0)
+}
+
+func readInputFn(r *http.Request) (notifications.ReadInput, error) {
+	return notifications.ReadInput{NotificationID: "notification-id"}, nil
+}
+
+func preferencesInputFn(r *http.Request) (notifications.PreferencesInput, error) {
+	return notifications.PreferencesInput{Preferences: []shared.NotificationPreference{
+		{Category: shared.NotifyResults, InApp: true, Email: shared.EmailDigest},
+	}}, nil
+}
+
+func TestFormatSSEvent(t *testing.T) {
+	got := notifications.FormatSSEvent("badge", "<span>\n\n2</span>\n")
+	expected := "event: badge\ndata: <span>\ndata: \ndata: 2</span>\n\n"
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestStreamHandlerBadAuth(t *testing.T) {
+	handler := notifications.CreateStreamHandler(&templates{}, invalidAuthRepo{}, new(streamStorage), notifications.NewBroker())
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestStreamHandlerVisitor(t *testing.T) {
+	handler := notifications.CreateStreamHandler(&templates{}, visitorAuthRepo{}, new(streamStorage), notifications.NewBroker())
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestStreamHandlerBadStorage(t *testing.T) {
+	storage := new(streamStorage)
+	storage.On("SelectNotifications", mock.Anything, "user-id").Return([]shared.Notification{}, errors.New("error"))
+	handler := notifications.CreateStreamHandler(&templates{}, authRepo{}, storage, notifications.NewBroker())
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Body.Len() != 0 {
+		t.Errorf("expected no events, got %q", w.Body.String())
+	}
+}
+
+func TestStreamHandler(t *testing.T) {
+	list := []shared.Notification{{ID: "notification-id", Title: "Hola"}}
+	storage := new(streamStorage)
+	storage.On("SelectNotifications", mock.Anything, "user-id").Return(list, nil)
+	storage.On("CountUnreadNotifications", mock.Anything, "user-id").Return(1, nil)
+	data := notifications.NotificationsData{Unread: 1, Notifications: list}
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "notificationBadge", data).Return(nil)
+	templ.On("Render", mock.Anything, "notificationList", data).Return(nil)
+	broker := notifications.NewBroker()
+	handler := notifications.CreateStreamHandler(templ, authRepo{}, storage, broker)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Header().Get("Content-Type") != "text/event-stream" {
+		t.Errorf("unexpected content type %s", w.Header().Get("Content-Type"))
+	}
+	for _, expected := range []string{"event: badge\n", "event: notifications\n"} {
+		if !strings.Contains(w.Body.String(), expected) {
+			t.Errorf("expected %q in %q", expected, w.Body.String())
+		}
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestGetReadInput(t *testing.T) {
+	id := "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	req, _ := http.NewRequest("POST", "/", nil)
+	req = WithUrlParam(req, "notificationID", id)
+	input, err := notifications.GetReadInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if input.NotificationID != id {
+		t.Errorf("expected %s, got %s", id, input.NotificationID)
+	}
+	req = WithUrlParam(req, "notificationID", "1")
+	if _, err := notifications.GetReadInput(req); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestReadHandlerBadAuth(t *testing.T) {
+	handler := notifications.CreateReadHandler(invalidAuthRepo{}, new(readStorage), new(publisher), readInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestReadHandlerBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (notifications.ReadInput, error) {
+		return notifications.ReadInput{}, errors.New("error")
+	}
+	handler := notifications.CreateReadHandler(authRepo{}, new(readStorage), new(publisher), invalidInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestReadHandlerNotOwned(t *testing.T) {
+	storage := new(readStorage)
+	storage.On("ReadNotification", mock.Anything, "user-id", "notification-id").Return(shared.Notification{}, errors.New("error"))
+	publisher := new(publisher)
+	handler := notifications.CreateReadHandler(authRepo{}, storage, publisher, readInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	publisher.AssertNotCalled(t, "Publish", mock.Anything)
+}
+
+func TestReadHandler(t *testing.T) {
+	storage := new(readStorage)
+	storage.On("ReadNotification", mock.Anything, "user-id", "notification-id").Return(shared.Notification{Link: "/applications"}, nil)
+	publisher := new(publisher)
+	publisher.On("Publish", "user-id").Return()
+	handler := notifications.CreateReadHandler(authRepo{}, storage, publisher, readInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("HX-Redirect") != "/applications" {
+		t.Errorf("unexpected redirect %s", w.Header().Get("HX-Redirect"))
+	}
+	publisher.AssertExpectations(t)
+}
+
+func TestReadAllHandlerBadStorage(t *testing.T) {
+	storage := new(readStorage)
+	storage.On("ReadNotifications", mock.Anything, "user-id").Return(errors.New("error"))
+	handler := notifications.CreateReadAllHandler(authRepo{}, storage, new(publisher))
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestReadAllHandler(t *testing.T) {
+	storage := new(readStorage)
+	storage.On("ReadNotifications", mock.Anything, "user-id").Return(nil)
+	publisher := new(publisher)
+	publisher.On("Publish", "user-id").Return()
+	handler := notifications.CreateReadAllHandler(authRepo{}, storage, publisher)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	publisher.AssertExpectations(t)
+}
+
+func TestPreferencesPageHandlerVisitor(t *testing.T) {
+	handler := notifications.CreatePreferencesPageHandler(&templates{}, visitorAuthRepo{}, new(preferencesStorage))
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestPreferencesPageHandlerBadTemplate(t *testing.T) {
+	storage := new(preferencesStorage)
+	storage.On("SelectNotificationPreferences", mock.Anything, "user-id").Return([]shared.NotificationPreference{}, nil)
+	handler := notifications.CreatePreferencesPageHandler(&invalidTemplates{}, authRepo{}, storage)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestGetPreferencesInput(t *testing.T) {
+	form := url.Values{}
+	for _, category := range shared.NotificationCategories {
+		form.Set("email-"+category, shared.EmailOff)
+	}
+	form.Set("email-"+shared.NotifyStatus, shared.EmailDigest)
+	form.Set("inApp-"+shared.NotifyResults, "on")
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	input, err := notifications.GetPreferencesInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	expected := []shared.NotificationPreference{
+		{Category: shared.NotifyResults, InApp: true, Email: shared.EmailOff},
+		{Category: shared.NotifyApplicant, InApp: false, Email: shared.EmailOff},
+		{Category: shared.NotifyStatus, InApp: false, Email: shared.EmailDigest},
+		{Category: shared.NotifyArchived, InApp: false, Email: shared.EmailOff},
+		{Category: shared.NotifyTeam, InApp: false, Email: shared.EmailOff},
+	}
+	if len(input.Preferences) != len(expected) {
+		t.Fatalf("expected %d preferences, got %d", len(expected), len(input.Preferences))
+	}
+	for i := range expected {
+		if input.Preferences[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], input.Preferences[i])
+		}
+	}
+}
+
+func TestGetPreferencesInputUnknownMode(t *testing.T) {
+	form := url.Values{}
+	for _, category := range shared.NotificationCategories {
+		form.Set("email-"+category, "weekly")
+	}
+	req, _ := http.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if _, err := notifications.GetPreferencesInput(req); err != shared.ErrNotificationPreference {
+		t.Errorf("expected %v, got %v", shared.ErrNotificationPreference, err)
+	}
+}
+
+func TestPreferencesHandlerBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (notifications.PreferencesInput, error) {
+		return notifications.PreferencesInput{}, errors.New("error")
+	}
+	handler := notifications.CreatePreferencesHandler(&templates{}, authRepo{}, new(preferencesStorage), invalidInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestPreferencesHandlerBadStorage(t *testing.T) {
+	storage := new(preferencesStorage)
+	storage.On("UpdateNotificationPreferences", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error"))
+	handler := notifications.CreatePreferencesHandler(&templates{}, authRepo{}, storage, preferencesInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestPreferencesHandler(t *testing.T) {
+	input, _ := preferencesInputFn(nil)
+	storage := new(preferencesStorage)
+	storage.On("UpdateNotificationPreferences", mock.Anything, "user-id", input.Preferences).Return(nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "notificationPreferences", mock.MatchedBy(func(data notifications.PreferencesData) bool {
+		return data.Alert.Ok && len(data.Preferences) == 1 && len(data.Modes) == len(shared.EmailModes)
+	})).Return(nil)
+	handler := notifications.CreatePreferencesHandler(templ, authRepo{}, storage, preferencesInputFn)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+	templ.AssertExpectations(t)
+}
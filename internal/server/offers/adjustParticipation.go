@@ -0,0 +1,117 @@
+package offers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const (
+	maxAdjustmentMinutes = 600
+	maxAdjustmentReason  = 255
+)
+
+type AdjustParticipationStorage interface {
+	AdjustParticipation(ctx context.Context, recruiterID, participationID string, adjustment shared.Adjustment) (shared.Participation, error)
+	SelectAdjustments(ctx context.Context, participationID string) ([]shared.Adjustment, error)
+}
+
+type DeadlineNotifier interface {
+	Publish(participationID, reason string)
+}
+
+type AdjustParticipationInput struct {
+	ParticipationID string
+	Adjustment      shared.Adjustment
+}
+
+func GetAdjustParticipationInput(r *http.Request) (AdjustParticipationInput, error) {
+	participationID := chi.URLParam(r, "participationID")
+	if err := shared.ValidateUUID(participationID); err != nil {
+		return AdjustParticipationInput{}, err
+	}
+	action := r.FormValue("action")
+	if _, ok := shared.AdjustmentLabels[action]; !ok {
+		return AdjustParticipationInput{}, shared.ErrAdjustmentAction
+	}
+	minutes := 0
+	if strMinutes := r.FormValue("minutes"); strMinutes != "" {
+		var err error
+		minutes, err = strconv.Atoi(strMinutes)
+		if err != nil || minutes < 0 || minutes > maxAdjustmentMinutes {
+			return AdjustParticipationInput{}, fmt.Errorf("los minutos deben estar entre 0 y %d", maxAdjustmentMinutes)
+		}
+	}
+	reason := r.FormValue("reason")
+	if len(reason) > maxAdjustmentReason {
+		return AdjustParticipationInput{}, fmt.Errorf("el motivo no puede superar los %d caracteres", maxAdjustmentReason)
+	}
+	return AdjustParticipationInput{
+		ParticipationID: participationID,
+		Adjustment: shared.Adjustment{
+			Action:  action,
+			Minutes: shared.IntToInt32(minutes),
+			Reason:  reason,
+		},
+	}, nil
+}
+
+type adjustParticipationInputFn func(r *http.Request) (AdjustParticipationInput, error)
+
+func CreateAdjustParticipationHandler(
+	inputFn adjustParticipationInputFn,
+	authService shared.AuthRep,
+	storage AdjustParticipationStorage,
+	notifier DeadlineNotifier,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		participation, err := storage.AdjustParticipation(r.Context(), user.ID, input.ParticipationID, input.Adjustment)
+		if shared.IsAdjustmentError(err) {
+			// the controls stay as they are, only the alert is replaced
+			w.Header().Set("HX-Retarget", "#adjust-alert-"+input.ParticipationID)
+			w.Header().Set("HX-Reswap", "innerHTML")
+			if err := templ.Render(w, "adjustmentAlert", shared.Alert{Ok: false, Msg: err.Error()}); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		notifier.Publish(participation.ID, shared.DeadlineAdjusted)
+		adjustments, err := storage.SelectAdjustments(r.Context(), participation.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data := shared.ParticipationControls{
+			Participation: participation,
+			Adjustments:   adjustments,
+			Alert:         shared.Alert{Ok: true, Msg: shared.MsgSaved},
+		}
+		if err := templ.Render(w, "participationControls", data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
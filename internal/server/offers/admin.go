@@ -20,7 +20,7 @@ type OffersAdminStorage interface {
 
 func CreateOffersAdminHandler(
 	authService shared.AuthRep,
-	storage OfferListStorage,
+	storage OffersAdminStorage,
 	templ shared.TemplatesRepo,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -12,6 +12,8 @@ import (
 
 type ApplicantsInput struct {
 	OfferID string
+	// Stage filters the applicants, empty shows all of them
+	Stage string
 }
 
 type ApplicantsData struct {
@@ -22,6 +24,13 @@ type ApplicantsData struct {
 	Problems       []shared.Problem
 	Languages      []shared.Language
 	Applicants     []shared.Application
+	ProctoringData ProctoringRulesData
+	Stages         []shared.Stage
+	StageCounts    []shared.StageCount
+	BulkActions    map[string]string
+	// Stage is the selected filter, kept by the export links
+	Stage       string
+	BlindReview shared.BlindReview
 }
 
 type ApplicantsStorage interface {
@@ -30,6 +39,9 @@ type ApplicantsStorage interface {
 	SelectLanguages(ctx context.Context, quizID string) ([]shared.Language, error)
 	SelectApplications(ctx context.Context, quizID string) ([]shared.Application, error)
 	SelectFullProblems(ctx context.Context, quizID string) ([]shared.Problem, error)
+	SelectProctoringRules(ctx context.Context, quizID string) ([]shared.ProctoringRule, error)
+	SelectOfferStages(ctx context.Context, offerID string) ([]shared.Stage, error)
+	SelectBlindReview(ctx context.Context, offerID string) (shared.BlindReview, error)
 }
 
 func GetApplicantsInput(r *http.Request) (ApplicantsInput, error) {
@@ -37,7 +49,25 @@ func GetApplicantsInput(r *http.Request) (ApplicantsInput, error) {
 	if err := shared.ValidateUUID(offerID); err != nil {
 		return ApplicantsInput{}, err
 	}
-	return ApplicantsInput{OfferID: offerID}, nil
+	stage := r.URL.Query().Get("stage")
+	if stage != "" && stage != shared.StageName(stage) {
+		return ApplicantsInput{}, shared.ErrStage
+	}
+	return ApplicantsInput{OfferID: offerID, Stage: stage}, nil
+}
+
+// filterStage keeps the applicants in stage, all of them when it's empty
+func filterStage(applications []shared.Application, stage string) []shared.Application {
+	if stage == "" {
+		return applications
+	}
+	filtered := []shared.Application{}
+	for _, application := range applications {
+		if application.Pipeline.Current() == stage {
+			filtered = append(filtered, application)
+		}
+	}
+	return filtered
 }
 
 type offerApplInputFn func(r *http.Request) (ApplicantsInput, error)
@@ -84,6 +114,27 @@ func CreateApplicantsHandler(
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		rules, err := storage.SelectProctoringRules(r.Context(), quiz.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		stages, err := storage.SelectOfferStages(r.Context(), input.OfferID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for i := range applications {
+			applications[i].Pipeline.Stages = stages
+		}
+		blind, err := storage.SelectBlindReview(r.Context(), input.OfferID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		blind.Hide(applications)
+		stageCounts := shared.CountStages(stages, applications, input.Stage)
+		applications = filterStage(applications, input.Stage)
 		videoBrokerURL := os.Getenv("VIDEO_BROKER_URL")
 		data := ApplicantsData{
 			VideoBrokerURL: videoBrokerURL,
@@ -93,6 +144,15 @@ func CreateApplicantsHandler(
 			Problems:       problems,
 			Languages:      languages,
 			Applicants:     applications,
+			ProctoringData: ProctoringRulesData{
+				OfferID: offer.ID,
+				Rules:   MergeProctoringRules(rules),
+			},
+			Stages:      stages,
+			StageCounts: stageCounts,
+			BulkActions: shared.BulkReviewActions,
+			Stage:       input.Stage,
+			BlindReview: blind,
 		}
 		if err := templ.Render(w, "offerAdmin", data); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -35,6 +35,8 @@ func CreateArchiveHandler(
 	inputFn offerArchiveInputFn,
 	authService shared.AuthRep,
 	storage OfferArchiveStorage,
+	events shared.WebhookEmitter,
+	notifier shared.Notifier,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		user, err := authService.GetUser(r)
@@ -56,6 +58,14 @@ func CreateArchiveHandler(
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		shared.EmitWebhookEvent(r.Context(), events, shared.WebhookEvent{
+			Type:    shared.EventOfferArchived,
+			OfferID: input.OfferID,
+		})
+		shared.Notify(r.Context(), notifier, shared.NotificationEvent{
+			Category: shared.NotifyArchived,
+			OfferID:  input.OfferID,
+		})
 		w.WriteHeader(http.StatusOK)
 	}
 }
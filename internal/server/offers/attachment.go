@@ -0,0 +1,102 @@
+package offers
+
+import (
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const maxAttachmentSize = 5 << 20
+
+// attachmentTypes are the files a statement can embed or link, the value
+// tells if the file is shown as an image
+var attachmentTypes = map[string]bool{
+	".png":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".gif":  true,
+	".webp": true,
+	".pdf":  false,
+	".txt":  false,
+	".csv":  false,
+	".zip":  false,
+}
+
+type AttachmentInput struct {
+	Name  string
+	URL   string
+	Image bool
+}
+
+// Markdown is the snippet the recruiter pastes in the statement
+func (a AttachmentInput) Markdown() string {
+	name := strings.NewReplacer("[", "", "]", "").Replace(a.Name)
+	if a.Image {
+		return fmt.Sprintf("![%s](%s)", name, a.URL)
+	}
+	return fmt.Sprintf("[%s](%s)", name, a.URL)
+}
+
+func GetAttachmentInput(r *http.Request, cloudinaryService shared.CloudinaryService) (AttachmentInput, error) {
+	r.Body = http.MaxBytesReader(nil, r.Body, maxAttachmentSize+1<<20)
+	if err := r.ParseMultipartForm(maxAttachmentSize); err != nil {
+		return AttachmentInput{}, fmt.Errorf("el archivo no puede superar los %d MB", maxAttachmentSize>>20)
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return AttachmentInput{}, fmt.Errorf("debe seleccionar un archivo")
+	}
+	defer file.Close()
+	if header.Size > maxAttachmentSize {
+		return AttachmentInput{}, fmt.Errorf("el archivo no puede superar los %d MB", maxAttachmentSize>>20)
+	}
+	image, ok := attachmentTypes[strings.ToLower(filepath.Ext(header.Filename))]
+	if !ok {
+		return AttachmentInput{}, fmt.Errorf("solo se aceptan imágenes, PDF, TXT, CSV o ZIP")
+	}
+	resp, err := cloudinaryService.Upload(r.Context(), file, uploader.UploadParams{Folder: "attachments"})
+	if err != nil {
+		return AttachmentInput{}, err
+	}
+	return AttachmentInput{
+		Name:  header.Filename,
+		URL:   resp.SecureURL,
+		Image: image,
+	}, nil
+}
+
+type attachmentInputFn func(r *http.Request, cloudinaryService shared.CloudinaryService) (AttachmentInput, error)
+
+// CreateAttachmentHandler uploads a file of a statement and answers with the
+// Markdown that embeds it
+func CreateAttachmentHandler(
+	inputFn attachmentInputFn,
+	authService shared.AuthRep,
+	cloudinaryService shared.CloudinaryService,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r, cloudinaryService)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := templ.Render(w, "attachmentSnippet", input); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
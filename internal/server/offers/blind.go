@@ -0,0 +1,103 @@
+package offers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+type BlindReviewStorage interface {
+	SelectOfferByUser(ctx context.Context, id string, userID string) (shared.Offer, error)
+	SelectBlindReview(ctx context.Context, offerID string) (shared.BlindReview, error)
+	UpdateBlindReview(ctx context.Context, recruiterID string, review shared.BlindReview) error
+}
+
+type BlindReviewInput struct {
+	OfferID     string
+	Enabled     bool
+	RevealStage string
+}
+
+// GetBlindReviewInput only needs the reveal stage when enabling, it is
+// checked against the pipeline of the company when saved
+func GetBlindReviewInput(r *http.Request) (BlindReviewInput, error) {
+	offerID := chi.URLParam(r, "offerID")
+	if err := shared.ValidateUUID(offerID); err != nil {
+		return BlindReviewInput{}, err
+	}
+	input := BlindReviewInput{
+		OfferID: offerID,
+		Enabled: r.FormValue("enabled") == "on",
+	}
+	if input.Enabled {
+		input.RevealStage = r.FormValue("revealStage")
+		if input.RevealStage == "" {
+			return BlindReviewInput{}, shared.ErrStage
+		}
+	}
+	return input, nil
+}
+
+type blindReviewInputFn func(r *http.Request) (BlindReviewInput, error)
+
+func CreateBlindReviewHandler(
+	inputFn blindReviewInputFn,
+	authService shared.AuthRep,
+	storage BlindReviewStorage,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offer, err := storage.SelectOfferByUser(r.Context(), input.OfferID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if offer.ReadOnly() {
+			http.Error(w, shared.ErrNotEditor.Error(), http.StatusUnauthorized)
+			return
+		}
+		err = storage.UpdateBlindReview(r.Context(), user.ID, shared.BlindReview{
+			OfferID:     offer.ID,
+			Enabled:     input.Enabled,
+			RevealStage: input.RevealStage,
+		})
+		alert := shared.Alert{Ok: true, Msg: shared.MsgSaved}
+		if errors.Is(err, shared.ErrStage) {
+			alert = shared.Alert{Ok: false, Msg: err.Error()}
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		review, err := storage.SelectBlindReview(r.Context(), offer.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		review.Alert = alert
+		// the applicants shown by name change, the page is loaded again
+		if alert.Ok {
+			w.Header().Set("HX-Refresh", "true")
+		}
+		if err := templ.Render(w, "blindReview", review); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
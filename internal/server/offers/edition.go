@@ -2,75 +2,203 @@ package offers
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
-	"strconv"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/auth"
 	"github.com/kw3a/spotted-server/internal/server/shared"
 )
 
-type OfferEdition struct {
-	OfferID     string
-	Languages   []int32
-	Duration    int32
+type OfferEditInput struct {
+	OfferID string
+	Offer   shared.Offer
+	// Quiz is nil when the form leaves the quiz out, quizzes with
+	// participations are locked
+	Quiz           *shared.Quiz
+	BankProblemIDs []string
 }
 
-func GetOfferEditionInput(r *http.Request) (OfferEdition, error) {
-	err := r.ParseForm()
-	if err != nil {
-		return OfferEdition{}, err
-	}
+type OfferEditJ struct {
+	Offer OfferJ   `json:"offer"`
+	Quiz  *QuizJ   `json:"quiz"`
+	Bank  []string `json:"bank"`
+}
+
+func GetOfferEditInput(r *http.Request) (OfferEditInput, error) {
 	offerID := chi.URLParam(r, "offerID")
 	if err := shared.ValidateUUID(offerID); err != nil {
-		return OfferEdition{}, err
+		return OfferEditInput{}, err
+	}
+	jsonInput, err := shared.Decode[OfferEditJ](r)
+	if err != nil {
+		return OfferEditInput{}, err
+	}
+	offer, err := ValidateOffer(jsonInput.Offer)
+	if err != nil {
+		return OfferEditInput{}, err
+	}
+	input := OfferEditInput{
+		OfferID: offerID,
+		Offer:   offer,
+	}
+	if jsonInput.Quiz == nil {
+		return input, nil
+	}
+	// the pools stay as registered, the storage checks them against the
+	// new problems
+	jsonInput.Quiz.Pools = nil
+	quiz, err := ValidateQuiz(*jsonInput.Quiz)
+	if err != nil {
+		return OfferEditInput{}, err
+	}
+	bankProblemIDs, err := ValidateBankProblems(jsonInput.Bank)
+	if err != nil {
+		return OfferEditInput{}, err
 	}
-	languages := r.Form["languages"]
-	if len(languages) == 0 {
-		return OfferEdition{}, fmt.Errorf("languages are empty")
+	if len(bankProblemIDs) < 1 || len(bankProblemIDs) > maxPoolProblems {
+		return OfferEditInput{}, fmt.Errorf("debe haber entre 1 y %d problemas", maxPoolProblems)
 	}
-	fmt.Println(languages)
-	intLanguages := []int32{}
-	for _, lang := range languages {
-		intLang, err := strconv.Atoi(lang)
+	input.Quiz = &quiz
+	input.BankProblemIDs = bankProblemIDs
+	return input, nil
+}
+
+type OfferEditStorage interface {
+	EditOffer(
+		ctx context.Context,
+		offerID, ownerID string,
+		offer shared.Offer,
+		quiz *shared.Quiz,
+		bankProblemIDs []string,
+	) error
+}
+
+// Edits rejected by the offer state or the quiz rules are shown to the owner
+func isEditError(err error) bool {
+	return shared.IsAny(err,
+		sql.ErrNoRows,
+		shared.ErrOfferArchived,
+		shared.ErrQuizLocked,
+		shared.ErrBankProblemNotFound,
+		shared.ErrReferenceValidation,
+		shared.ErrTooManyProblems,
+		shared.ErrPoolTooSmall,
+	)
+}
+
+type offerEditInputFn func(r *http.Request) (OfferEditInput, error)
+
+func CreateOfferEditHandler(
+	inputFn offerEditInputFn,
+	authService shared.AuthRep,
+	storage OfferEditStorage,
+	redirPath string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = storage.EditOffer(
+			r.Context(),
+			input.OfferID,
+			user.ID,
+			input.Offer,
+			input.Quiz,
+			input.BankProblemIDs,
+		)
+		if isEditError(err) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		if err != nil {
-			return OfferEdition{}, err
+			http.Error(w, fmt.Sprintf("storage error: %s", err.Error()), http.StatusInternalServerError)
+			return
 		}
-		intLanguages = append(intLanguages, shared.IntToInt32(intLang))
+		w.Header().Add("HX-Redirect", redirPath+input.OfferID)
+		w.WriteHeader(http.StatusOK)
 	}
-	duration, err := strconv.Atoi(r.FormValue("duration"))
-	if err != nil {
-		return OfferEdition{}, err
+}
+
+type OfferStatusInput struct {
+	OfferID string
+	Status  string
+}
+
+func GetOfferStatusInput(r *http.Request) (OfferStatusInput, error) {
+	offerID := chi.URLParam(r, "offerID")
+	if err := shared.ValidateUUID(offerID); err != nil {
+		return OfferStatusInput{}, err
+	}
+	status := r.FormValue("status")
+	if _, ok := shared.OfferStatusLabels[status]; !ok {
+		return OfferStatusInput{}, fmt.Errorf("estado de oferta inválido")
 	}
-	return OfferEdition{
-		OfferID:     offerID,
-		Languages:   intLanguages,
-		Duration:    shared.IntToInt32(duration),
-	}, nil
+	return OfferStatusInput{OfferID: offerID, Status: status}, nil
 }
 
-type OfferEditionStorage interface {
-	InsertQuiz(ctx context.Context, quizID, offerID string, languages []int32, duration int32) error
+type OfferStatusStorage interface {
+	UpdateOfferStatus(ctx context.Context, offerID, ownerID, status string) error
 }
 
-type offerEditionInputFn func(r *http.Request) (OfferEdition, error)
-func CreateOfferEdition(
-	storage OfferEditionStorage,
-	inputFn offerEditionInputFn,
+type offerStatusInputFn func(r *http.Request) (OfferStatusInput, error)
+
+func CreateOfferStatusHandler(
+	inputFn offerStatusInputFn,
+	authService shared.AuthRep,
+	storage OfferStatusStorage,
+	events shared.WebhookEmitter,
+	notifier shared.Notifier,
+	redirPath string,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
 		input, err := inputFn(r)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		quizID := uuid.New().String()
-		err = storage.InsertQuiz(r.Context(), quizID, input.OfferID, input.Languages, input.Duration)
+		err = storage.UpdateOfferStatus(r.Context(), input.OfferID, user.ID, input.Status)
+		if errors.Is(err, shared.ErrOfferTransition) || errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		if input.Status == shared.OfferArchived {
+			shared.EmitWebhookEvent(r.Context(), events, shared.WebhookEvent{
+				Type:    shared.EventOfferArchived,
+				OfferID: input.OfferID,
+			})
+			shared.Notify(r.Context(), notifier, shared.NotificationEvent{
+				Category: shared.NotifyArchived,
+				OfferID:  input.OfferID,
+			})
+		}
+		w.Header().Add("HX-Redirect", redirPath)
 		w.WriteHeader(http.StatusOK)
 	}
 }
@@ -14,6 +14,18 @@ type OfferEditionPageData struct {
 	Offer     shared.Offer
 	Quiz      shared.Quiz
 	Languages []shared.Language
+	// Selected marks the languages of the quiz
+	Selected map[int32]bool
+	Problems []shared.BankProblem
+	// Pinned maps the library problems of the quiz to the version it uses
+	Pinned map[string]int32
+	// Locked is set once the quiz has participations, only the offer text
+	// and wages can change then
+	Locked bool
+}
+
+func (d OfferEditionPageData) Attributes() OfferAttributesForm {
+	return newOfferAttributesForm(d.Offer, d.Languages)
 }
 
 type OfferEditionPageInput struct {
@@ -24,6 +36,10 @@ type OfferEditionPageStorage interface {
 	SelectOfferByUser(ctx context.Context, ID, userID string) (shared.Offer, error)
 	GetLanguages(ctx context.Context) ([]shared.Language, error)
 	SelectQuizByOffer(ctx context.Context, offerID string) (shared.Quiz, error)
+	SelectLanguages(ctx context.Context, quizID string) ([]shared.Language, error)
+	SelectBankProblems(ctx context.Context, companyID string) ([]shared.BankProblem, error)
+	SelectQuizBankProblems(ctx context.Context, quizID string) (map[string]int32, error)
+	CountParticipations(ctx context.Context, quizID string) (int64, error)
 }
 
 func GetOfferEditionPageInput(r *http.Request) (OfferEditionPageInput, error) {
@@ -37,17 +53,21 @@ func GetOfferEditionPageInput(r *http.Request) (OfferEditionPageInput, error) {
 type offerEditionPageInputFn func(r *http.Request) (OfferEditionPageInput, error)
 
 func CreateOfferEditionPage(
-	auth shared.AuthRep,
+	authService shared.AuthRep,
 	templ shared.TemplatesRepo,
 	storage OfferEditionPageStorage,
 	inputFn offerEditionPageInputFn,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		user, err := auth.GetUser(r)
+		user, err := authService.GetUser(r)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
 		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
 		input, err := inputFn(r)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
@@ -55,7 +75,15 @@ func CreateOfferEditionPage(
 		}
 		offer, err := storage.SelectOfferByUser(r.Context(), input.OfferID, user.ID)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if offer.ReadOnly() {
+			http.Error(w, shared.ErrNotEditor.Error(), http.StatusUnauthorized)
+			return
+		}
+		if !offer.Editable() {
+			http.Error(w, shared.ErrOfferArchived.Error(), http.StatusBadRequest)
 			return
 		}
 		languages, err := storage.GetLanguages(r.Context())
@@ -63,14 +91,44 @@ func CreateOfferEditionPage(
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
+		quiz, err := storage.SelectQuizByOffer(r.Context(), offer.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		quizLanguages, err := storage.SelectLanguages(r.Context(), quiz.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		problems, err := storage.SelectBankProblems(r.Context(), offer.CompanyID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		pinned, err := storage.SelectQuizBankProblems(r.Context(), quiz.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		participations, err := storage.CountParticipations(r.Context(), quiz.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		selected := make(map[int32]bool)
+		for _, language := range quizLanguages {
+			selected[language.ID] = true
+		}
 		data := OfferEditionPageData{
 			User:      user,
 			Offer:     offer,
+			Quiz:      quiz,
 			Languages: languages,
-		}
-		quiz, err := storage.SelectQuizByOffer(r.Context(), offer.ID)
-		if err == nil {
-			data.Quiz = quiz
+			Selected:  selected,
+			Problems:  problems,
+			Pinned:    pinned,
+			Locked:    participations > 0,
 		}
 		if err := templ.Render(w, "offerEdition", data); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
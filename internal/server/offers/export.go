@@ -0,0 +1,281 @@
+package offers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/export"
+	"github.com/kw3a/spotted-server/internal/server/quizes"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+type ExportStorage interface {
+	SelectOfferByUser(ctx context.Context, id string, userID string) (shared.Offer, error)
+	SelectQuizByOffer(ctx context.Context, offerID string) (shared.Quiz, error)
+	SelectApplications(ctx context.Context, quizID string) ([]shared.Application, error)
+	SelectOfferStages(ctx context.Context, offerID string) ([]shared.Stage, error)
+	SelectBlindReview(ctx context.Context, offerID string) (shared.BlindReview, error)
+}
+
+type DossierStorage interface {
+	ExportStorage
+	SelectStrokeWindows(ctx context.Context, participationID string) ([]shared.StrokeWindow, error)
+}
+
+type ExportInput struct {
+	OfferID string
+	Format  string
+	// Stage exports the applicants the recruiter is looking at
+	Stage string
+}
+
+type DossierInput struct {
+	OfferID         string
+	ParticipationID string
+}
+
+func GetExportInput(r *http.Request) (ExportInput, error) {
+	offerID := chi.URLParam(r, "offerID")
+	if err := shared.ValidateUUID(offerID); err != nil {
+		return ExportInput{}, err
+	}
+	format := r.URL.Query().Get("format")
+	if format != shared.ExportCSV && format != shared.ExportXLSX {
+		return ExportInput{}, shared.ErrExportFormat
+	}
+	stage := r.URL.Query().Get("stage")
+	if stage != "" && stage != shared.StageName(stage) {
+		return ExportInput{}, shared.ErrStage
+	}
+	return ExportInput{OfferID: offerID, Format: format, Stage: stage}, nil
+}
+
+func GetDossierInput(r *http.Request) (DossierInput, error) {
+	offerID := chi.URLParam(r, "offerID")
+	if err := shared.ValidateUUID(offerID); err != nil {
+		return DossierInput{}, err
+	}
+	participationID := chi.URLParam(r, "participationID")
+	if err := shared.ValidateUUID(participationID); err != nil {
+		return DossierInput{}, err
+	}
+	return DossierInput{OfferID: offerID, ParticipationID: participationID}, nil
+}
+
+// selectApplicants loads the applicants of an offer owned by the user with
+// the stages of the company, hidden if the offer is blind reviewed
+func selectApplicants(ctx context.Context, storage ExportStorage, offerID, userID string) (shared.Offer, []shared.Application, error) {
+	offer, err := storage.SelectOfferByUser(ctx, offerID, userID)
+	if err != nil {
+		return shared.Offer{}, nil, err
+	}
+	quiz, err := storage.SelectQuizByOffer(ctx, offerID)
+	if err != nil {
+		return shared.Offer{}, nil, err
+	}
+	applications, err := storage.SelectApplications(ctx, quiz.ID)
+	if err != nil {
+		return shared.Offer{}, nil, err
+	}
+	stages, err := storage.SelectOfferStages(ctx, offerID)
+	if err != nil {
+		return shared.Offer{}, nil, err
+	}
+	for i := range applications {
+		applications[i].Pipeline.Stages = stages
+	}
+	blind, err := storage.SelectBlindReview(ctx, offerID)
+	if err != nil {
+		return shared.Offer{}, nil, err
+	}
+	blind.Hide(applications)
+	return offer, applications, nil
+}
+
+func applicationStatus(application shared.Application) string {
+	switch {
+	case application.Pipeline.Withdrawn():
+		return "Retirada"
+	case application.Participation.EndReason != "":
+		return application.Participation.EndReasonLabel()
+	}
+	return "En curso"
+}
+
+// ApplicantSheet is the ranked applicant list with a column per problem or
+// question, holding the percentage solved
+func ApplicantSheet(offer shared.Offer, applications []shared.Application) export.Sheet {
+	ranked := shared.RankApplications(applications)
+	titles := []string{}
+	seen := make(map[string]bool)
+	for _, application := range ranked {
+		for _, summary := range application.Summary {
+			if !seen[summary.Title] {
+				seen[summary.Title] = true
+				titles = append(titles, summary.Title)
+			}
+		}
+	}
+	sheet := export.Sheet{
+		Name: offer.Title,
+		Header: append([]string{
+			"Puesto", "Nombre", "Correo", "Celular", "Etapa", "Calificación",
+			"Etiquetas", "Puntaje (%)", "Estado", "Iniciada",
+		}, titles...),
+	}
+	for i, application := range ranked {
+		row := []any{
+			i + 1,
+			application.Applicant.Name,
+			application.Applicant.Email,
+			application.Applicant.Cell,
+			application.Pipeline.StageLabel(),
+			application.Pipeline.Rating,
+			strings.Join(application.Pipeline.Tags, ", "),
+			application.NormalizedScore(),
+			applicationStatus(application),
+			application.Participation.CreatedAt.Format("2006-01-02 15:04"),
+		}
+		scores := make(map[string]any)
+		for _, summary := range application.Summary {
+			scores[summary.Title] = summaryPercent(summary)
+		}
+		for _, title := range titles {
+			row = append(row, scores[title])
+		}
+		sheet.Rows = append(sheet.Rows, row)
+	}
+	return sheet
+}
+
+// summaryPercent is empty when there is nothing to score yet
+func summaryPercent(summary shared.Summary) any {
+	if summary.Question != nil && !summary.Response.Graded {
+		return "Por calificar"
+	}
+	if summary.Score.TotalTestCases == 0 {
+		return nil
+	}
+	ratio := float64(summary.Score.AcceptedTestCases) / float64(summary.Score.TotalTestCases)
+	return int(math.Round(100 * ratio))
+}
+
+type exportInputFn func(r *http.Request) (ExportInput, error)
+
+func CreateExportHandler(
+	inputFn exportInputFn,
+	authService shared.AuthRep,
+	storage ExportStorage,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offer, applications, err := selectApplicants(r.Context(), storage, input.OfferID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		sheet := ApplicantSheet(offer, filterStage(applications, input.Stage))
+		// the file is built before answering so a failure is not sent as a
+		// truncated download
+		var buf bytes.Buffer
+		contentType := "text/csv; charset=utf-8"
+		if input.Format == shared.ExportXLSX {
+			contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+			err = export.WriteXLSX(&buf, sheet)
+		} else {
+			err = export.WriteCSV(&buf, sheet)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf(`attachment; filename="postulantes-%s.%s"`, offer.ID, input.Format))
+		buf.WriteTo(w)
+	}
+}
+
+type dossierInputFn func(r *http.Request) (DossierInput, error)
+
+func CreateDossierHandler(
+	inputFn dossierInputFn,
+	authService shared.AuthRep,
+	storage DossierStorage,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offer, applications, err := selectApplicants(r.Context(), storage, input.OfferID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		dossier := export.Dossier{Offer: offer, GeneratedAt: time.Now()}
+		found := false
+		for _, application := range applications {
+			if application.Participation.ID == input.ParticipationID {
+				dossier.Application = application
+				found = true
+			}
+		}
+		if !found {
+			http.Error(w, "postulante no encontrado", http.StatusNotFound)
+			return
+		}
+		windows, err := storage.SelectStrokeWindows(r.Context(), input.ParticipationID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		for _, point := range quizes.AnalyzeKeystrokes(windows, input.ParticipationID).Points {
+			dossier.Keystrokes = append(dossier.Keystrokes, export.SMDPoint{
+				SMD:      point.SMD,
+				Strokes:  point.StrokeCount,
+				Profile:  point.IsProfile,
+				Inactive: point.IsInactive,
+			})
+		}
+		var buf bytes.Buffer
+		if err := export.WriteDossier(&buf, dossier); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Header().Set("Content-Disposition",
+			fmt.Sprintf(`inline; filename="postulante-%s.pdf"`, input.ParticipationID))
+		buf.WriteTo(w)
+	}
+}
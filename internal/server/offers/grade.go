@@ -0,0 +1,89 @@
+package offers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const maxGradeFeedback = 1000
+
+type GradeStorage interface {
+	GradeResponse(ctx context.Context, userID, responseID string, score int32, feedback string) error
+}
+
+type GradeInput struct {
+	ResponseID string
+	Score      int32
+	Feedback   string
+}
+
+func GetGradeInput(r *http.Request) (GradeInput, error) {
+	responseID := chi.URLParam(r, "responseID")
+	if err := shared.ValidateUUID(responseID); err != nil {
+		return GradeInput{}, err
+	}
+	score, err := strconv.Atoi(r.FormValue("score"))
+	if err != nil || score < 0 {
+		return GradeInput{}, shared.ErrScoreOutOfRange
+	}
+	feedback := r.FormValue("feedback")
+	if len(feedback) > maxGradeFeedback {
+		return GradeInput{}, fmt.Errorf("la retroalimentación no puede superar los %d caracteres", maxGradeFeedback)
+	}
+	return GradeInput{
+		ResponseID: responseID,
+		Score:      shared.IntToInt32(score),
+		Feedback:   feedback,
+	}, nil
+}
+
+type gradeInputFn func(r *http.Request) (GradeInput, error)
+
+// CreateGradeHandler stores the score of a free text answer, the recruiter
+// sees the result next to the grading form
+func CreateGradeHandler(
+	inputFn gradeInputFn,
+	authService shared.AuthRep,
+	storage GradeStorage,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = storage.GradeResponse(r.Context(), user.ID, input.ResponseID, input.Score, input.Feedback)
+		if errors.Is(err, sql.ErrNoRows) {
+			http.Error(w, "response not found", http.StatusBadRequest)
+			return
+		}
+		alert := shared.Alert{Ok: true, Msg: shared.MsgSaved}
+		if errors.Is(err, shared.ErrNotGradable) || errors.Is(err, shared.ErrScoreOutOfRange) {
+			alert = shared.Alert{Ok: false, Msg: err.Error()}
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := templ.Render(w, "adjustmentAlert", alert); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
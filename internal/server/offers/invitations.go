@@ -0,0 +1,177 @@
+package offers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/profiles"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const maxInvitationsPerRequest = 200
+
+type InvitationsInput struct {
+	OfferID string
+	Emails  []string
+}
+
+type InvitationDeleteInput struct {
+	OfferID      string
+	InvitationID string
+}
+
+// Emails may be separated by commas, semicolons, spaces or new lines as
+// they are usually pasted from a spreadsheet or an email client
+func ParseInvitationEmails(raw string) ([]string, error) {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' ' || r == '\n' || r == '\r' || r == '\t'
+	})
+	seen := make(map[string]bool)
+	emails := []string{}
+	for _, field := range fields {
+		email := strings.ToLower(field)
+		if profiles.EmailValidation(email) != "" {
+			return nil, shared.ErrInvitationBadEmail
+		}
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		emails = append(emails, email)
+	}
+	if len(emails) == 0 {
+		return nil, shared.ErrInvitationNoEmails
+	}
+	if len(emails) > maxInvitationsPerRequest {
+		return nil, shared.ErrInvitationTooMany
+	}
+	return emails, nil
+}
+
+func GetInvitationsInput(r *http.Request) (InvitationsInput, error) {
+	offerID := chi.URLParam(r, "offerID")
+	if err := shared.ValidateUUID(offerID); err != nil {
+		return InvitationsInput{}, err
+	}
+	emails, err := ParseInvitationEmails(r.FormValue("emails"))
+	if err != nil {
+		return InvitationsInput{}, err
+	}
+	return InvitationsInput{
+		OfferID: offerID,
+		Emails:  emails,
+	}, nil
+}
+
+func GetInvitationDeleteInput(r *http.Request) (InvitationDeleteInput, error) {
+	offerID := chi.URLParam(r, "offerID")
+	if err := shared.ValidateUUID(offerID); err != nil {
+		return InvitationDeleteInput{}, err
+	}
+	invitationID := chi.URLParam(r, "invitationID")
+	if err := shared.ValidateUUID(invitationID); err != nil {
+		return InvitationDeleteInput{}, err
+	}
+	return InvitationDeleteInput{
+		OfferID:      offerID,
+		InvitationID: invitationID,
+	}, nil
+}
+
+type invitationsInputFn func(r *http.Request) (InvitationsInput, error)
+
+func CreateInvitationsHandler(
+	inputFn invitationsInputFn,
+	authService shared.AuthRep,
+	storage QuizAccessStorage,
+	signer InvitationSigner,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if shared.IsAccessError(err) {
+			renderAccessAlert(w, templ, err)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offer, err := storage.SelectOfferByUser(r.Context(), input.OfferID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if offer.ReadOnly() {
+			http.Error(w, shared.ErrNotEditor.Error(), http.StatusUnauthorized)
+			return
+		}
+		quiz, err := storage.SelectQuizByOffer(r.Context(), offer.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := storage.InsertInvitations(r.Context(), quiz.ID, input.Emails); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		renderQuizAccess(w, r, storage, signer, templ, offer.ID, quiz, shared.Alert{Ok: true, Msg: shared.MsgSaved})
+	}
+}
+
+type invitationDeleteInputFn func(r *http.Request) (InvitationDeleteInput, error)
+
+func CreateInvitationDeleteHandler(
+	inputFn invitationDeleteInputFn,
+	authService shared.AuthRep,
+	storage QuizAccessStorage,
+	signer InvitationSigner,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offer, err := storage.SelectOfferByUser(r.Context(), input.OfferID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if offer.ReadOnly() {
+			http.Error(w, shared.ErrNotEditor.Error(), http.StatusUnauthorized)
+			return
+		}
+		quiz, err := storage.SelectQuizByOffer(r.Context(), offer.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := storage.DeleteInvitation(r.Context(), quiz.ID, input.InvitationID); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		renderQuizAccess(w, r, storage, signer, templ, offer.ID, quiz, shared.Alert{Ok: true, Msg: shared.MsgSaved})
+	}
+}
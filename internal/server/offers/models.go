@@ -4,23 +4,52 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/server/blobstore"
 	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/kw3a/spotted-server/internal/server/signature"
+	"github.com/kw3a/spotted-server/internal/server/sqljudge"
 )
 
 const availableLanguages = 6
 const memoryLimit = 262144
+const maxProblems = shared.MaxDrawnProblems
+
+const (
+	// maxPoolProblems bounds the problems of a quiz with pools, candidates
+	// still solve at most maxProblems of them
+	maxPoolProblems = 15
+	maxPools        = 5
+	maxTagLength    = 32
+)
+
+const maxLocationLength = 100
+
+const (
+	// maxTestCases counts the generated test cases too
+	maxTestCases      = 30
+	maxTestCaseLength = 65535
+	maxGeneratorSrc   = 65535
+	maxGeneratorSeeds = 20
+	maxSeedLength     = 255
+)
 
 type OfferRegInput struct {
 	Offer    shared.Offer
 	Quiz     shared.Quiz
 	Problems []shared.Problem
+	// BankProblemIDs are library problems reused by the quiz, they are
+	// pinned to their latest version when the offer is registered
+	BankProblemIDs []string
 }
 
 type OfferRegJ struct {
 	Offer    OfferJ     `json:"offer"`
 	Quiz     QuizJ      `json:"quiz"`
 	Problems []ProblemJ `json:"problems"`
+	Bank     []string   `json:"bank"`
 }
 
 type OfferJ struct {
@@ -31,24 +60,63 @@ type OfferJ struct {
 	About        string `json:"about"`
 	Requirements string `json:"requirements"`
 	Benefits     string `json:"benefits"`
+	Location     string `json:"location"`
+	Modality     string `json:"modality"`
+	Seniority    string `json:"seniority"`
+	Contract     string `json:"contract"`
+	Currency     string `json:"currency"`
+	WagePeriod   string `json:"wagePeriod"`
+	// Tags are the ids of the languages of the tech stack
+	Tags []string `json:"tags"`
+	// Draft keeps the offer out of the listings until it is published
+	Draft string `json:"draft"`
 }
 
 type QuizJ struct {
 	Duration  string   `json:"duration"`
 	Languages []string `json:"languages"`
+	Pools     []PoolJ  `json:"pools"`
+	Shuffle   string   `json:"shuffle"`
+}
+
+type PoolJ struct {
+	Tag        string `json:"tag"`
+	Difficulty string `json:"difficulty"`
+	Draw       string `json:"draw"`
 }
 
 type ProblemJ struct {
 	Title       string      `json:"title"`
 	Description string      `json:"description"`
 	TimeLimit   string      `json:"time_limit"`
+	Signature   string      `json:"signature"`
+	SQLSchema   string      `json:"sql_schema"`
+	SQLSeed     string      `json:"sql_seed"`
+	SQLOrder    string      `json:"sql_order"`
+	Kind        string      `json:"kind"`
+	Points      string      `json:"points"`
+	Options     string      `json:"options"`
+	Answers     string      `json:"answers"`
+	Tolerance   string      `json:"tolerance"`
+	Rubric      string      `json:"rubric"`
+	Difficulty  string      `json:"difficulty"`
+	Tag         string      `json:"tag"`
 	TestCases   []TestCaseJ `json:"test_cases"`
 	Examples    []ExampleJ  `json:"examples"`
+	// The generator fields are optional, the seeds are written one per line
+	GeneratorLanguage string `json:"generator_language"`
+	Generator         string `json:"generator"`
+	GeneratorSolution string `json:"generator_solution"`
+	GeneratorSeeds    string `json:"generator_seeds"`
 }
 
+// TestCaseJ names the blobs of large test data sent back by the editor,
+// Input and Output are then only previews
 type TestCaseJ struct {
-	Input  string `json:"input"`
-	Output string `json:"output"`
+	Input      string `json:"input"`
+	Output     string `json:"output"`
+	InputHash  string `json:"input_hash"`
+	OutputHash string `json:"output_hash"`
 }
 
 type ExampleJ struct {
@@ -92,6 +160,14 @@ func ValidateOffer(o OfferJ) (shared.Offer, error) {
 	if minWage < 0 || maxWage < 0 {
 		return shared.Offer{}, fmt.Errorf("el salario no puede ser negativo")
 	}
+	attributes, err := ValidateOfferAttributes(o)
+	if err != nil {
+		return shared.Offer{}, err
+	}
+	status := shared.OfferPublished
+	if o.Draft != "" {
+		status = shared.OfferDraft
+	}
 	return shared.Offer{
 		Title:        o.Title,
 		About:        o.About,
@@ -100,6 +176,75 @@ func ValidateOffer(o OfferJ) (shared.Offer, error) {
 		MinWage:      shared.IntToInt32(minWage),
 		MaxWage:      shared.IntToInt32(maxWage),
 		CompanyID:    o.CompanyID,
+		Status:       status,
+		Location:     attributes.Location,
+		Modality:     attributes.Modality,
+		Seniority:    attributes.Seniority,
+		Contract:     attributes.Contract,
+		Currency:     attributes.Currency,
+		WagePeriod:   attributes.WagePeriod,
+		Tags:         attributes.Tags,
+	}, nil
+}
+
+// ValidateOfferAttributes checks the fields used by the search filters, the
+// modality, currency and wage period fall back to their defaults
+func ValidateOfferAttributes(o OfferJ) (shared.Offer, error) {
+	location := strings.TrimSpace(o.Location)
+	if len(location) > maxLocationLength {
+		return shared.Offer{}, lenError("ubicación", 0, maxLocationLength)
+	}
+	modality := o.Modality
+	if modality == "" {
+		modality = shared.ModalityOnsite
+	}
+	if _, ok := shared.ModalityLabels[modality]; !ok {
+		return shared.Offer{}, fmt.Errorf("modalidad inválida")
+	}
+	if _, ok := shared.SeniorityLabels[o.Seniority]; o.Seniority != "" && !ok {
+		return shared.Offer{}, fmt.Errorf("nivel de experiencia inválido")
+	}
+	if _, ok := shared.ContractLabels[o.Contract]; o.Contract != "" && !ok {
+		return shared.Offer{}, fmt.Errorf("tipo de contrato inválido")
+	}
+	currency := o.Currency
+	if currency == "" {
+		currency = shared.Currencies[0]
+	}
+	if !shared.ValidCurrency(currency) {
+		return shared.Offer{}, fmt.Errorf("moneda inválida")
+	}
+	wagePeriod := o.WagePeriod
+	if wagePeriod == "" {
+		wagePeriod = shared.WageYearly
+	}
+	if _, ok := shared.WagePeriodLabels[wagePeriod]; !ok {
+		return shared.Offer{}, fmt.Errorf("periodo salarial inválido")
+	}
+	if len(o.Tags) > shared.MaxOfferTags {
+		return shared.Offer{}, fmt.Errorf("debe haber como máximo %d tecnologías", shared.MaxOfferTags)
+	}
+	tags := []shared.Language{}
+	seen := make(map[int32]bool)
+	for _, tag := range o.Tags {
+		id, err := strconv.Atoi(tag)
+		if err != nil {
+			return shared.Offer{}, fmt.Errorf("la tecnología debe ser un número")
+		}
+		if seen[shared.IntToInt32(id)] {
+			continue
+		}
+		seen[shared.IntToInt32(id)] = true
+		tags = append(tags, shared.Language{ID: shared.IntToInt32(id)})
+	}
+	return shared.Offer{
+		Location:   location,
+		Modality:   modality,
+		Seniority:  o.Seniority,
+		Contract:   o.Contract,
+		Currency:   currency,
+		WagePeriod: wagePeriod,
+		Tags:       tags,
 	}, nil
 }
 
@@ -122,19 +267,106 @@ func ValidateQuiz(q QuizJ) (shared.Quiz, error) {
 			languages = append(languages, shared.IntToInt32(intLang))
 		}
 	}
+	pools, err := ValidatePools(q.Pools)
+	if err != nil {
+		return shared.Quiz{}, err
+	}
 	return shared.Quiz{
 		Duration:  shared.IntToInt32(duration),
 		Languages: languages,
+		Pools:     pools,
+		Shuffle:   q.Shuffle != "",
 	}, nil
 }
 
+// ValidatePools skips the rows left empty in the form
+func ValidatePools(rows []PoolJ) ([]shared.Pool, error) {
+	pools := []shared.Pool{}
+	drawn := 0
+	for _, row := range rows {
+		if strings.TrimSpace(row.Draw) == "" {
+			continue
+		}
+		draw, err := strconv.Atoi(row.Draw)
+		if err != nil || draw < 1 || draw > maxProblems {
+			return nil, fmt.Errorf("cada grupo debe sortear entre 1 y %d problemas", maxProblems)
+		}
+		tag, err := validateTag(row.Tag)
+		if err != nil {
+			return nil, err
+		}
+		difficulty, err := validateDifficulty(row.Difficulty, true)
+		if err != nil {
+			return nil, err
+		}
+		pools = append(pools, shared.Pool{Tag: tag, Difficulty: difficulty, Draw: shared.IntToInt32(draw)})
+		drawn += draw
+	}
+	if drawn > maxProblems {
+		return nil, shared.ErrTooManyProblems
+	}
+	if len(pools) > maxPools {
+		return nil, fmt.Errorf("no puede haber más de %d grupos", maxPools)
+	}
+	return pools, nil
+}
+
+// validateTag compares tags without case so pools match the problems
+func validateTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if len(tag) > maxTagLength {
+		return "", lenError("tema", 0, maxTagLength)
+	}
+	return tag, nil
+}
+
+func validateDifficulty(value string, allowAny bool) (int32, error) {
+	if value == "" {
+		if allowAny {
+			return shared.DifficultyAny, nil
+		}
+		return shared.DifficultyMedium, nil
+	}
+	difficulty, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("dificultad inválida")
+	}
+	if _, ok := shared.DifficultyLabels[int32(difficulty)]; ok {
+		return int32(difficulty), nil
+	}
+	if allowAny && int32(difficulty) == shared.DifficultyAny {
+		return shared.DifficultyAny, nil
+	}
+	return 0, fmt.Errorf("dificultad inválida")
+}
+
 func ValidateProblem(p ProblemJ) (shared.Problem, error) {
 	if len(p.Title) < 1 || len(p.Title) > 64 {
 		return shared.Problem{}, lenError("título", 1, 64)
 	}
 	if len(p.Description) < 10 || len(p.Description) > 5000 {
-		fmt.Println("title", len(p.Description))
-		return shared.Problem{}, lenError("descripción", 10, 500)
+		return shared.Problem{}, lenError("descripción", 10, 5000)
+	}
+	difficulty, err := validateDifficulty(p.Difficulty, false)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	tag, err := validateTag(p.Tag)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	problem, err := validateProblemContent(p)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	problem.Difficulty = difficulty
+	problem.Tag = tag
+	return problem, nil
+}
+
+func validateProblemContent(p ProblemJ) (shared.Problem, error) {
+	if p.Kind != "" && p.Kind != "code" {
+		return validateQuestion(p)
 	}
 	timeLimit, err := strconv.Atoi(p.TimeLimit)
 	if err != nil {
@@ -142,18 +374,24 @@ func ValidateProblem(p ProblemJ) (shared.Problem, error) {
 	} else if timeLimit < 500 || timeLimit > 2000 {
 		return shared.Problem{}, fmt.Errorf("el límite de tiempo debe estar entre 500 y 2000 ms")
 	}
+	generator, err := validateGenerator(p)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	generated := 0
+	if generator != nil {
+		generated = len(generator.Seeds)
+	}
 	tcs := []shared.TestCase{}
-	if len(p.TestCases) < 1 || len(p.TestCases) > 10 {
-		return shared.Problem{}, fmt.Errorf("debe haber entre 1 y 10 casos de prueba")
+	if len(p.TestCases) < 1 || len(p.TestCases)+generated > maxTestCases {
+		return shared.Problem{}, fmt.Errorf("debe haber entre 1 y %d casos de prueba, contando los generados", maxTestCases)
 	} else {
 		for _, tc := range p.TestCases {
-			if len(tc.Input) < 1 || len(tc.Input) > 1000 {
-				return shared.Problem{}, lenError("entrada", 1, 1000)
-			}
-			if len(tc.Output) < 1 || len(tc.Output) > 1000 {
-				return shared.Problem{}, lenError("salida", 1, 1000)
+			testCase, err := validateTestCase(tc)
+			if err != nil {
+				return shared.Problem{}, err
 			}
-			tcs = append(tcs, shared.TestCase{Input: tc.Input, Output: tc.Output})
+			tcs = append(tcs, testCase)
 		}
 	}
 	exs := []shared.Example{}
@@ -170,16 +408,297 @@ func ValidateProblem(p ProblemJ) (shared.Problem, error) {
 			exs = append(exs, shared.Example{Input: ex.Input, Output: ex.Output})
 		}
 	}
-	return shared.Problem{
+	problem := shared.Problem{
 		Title:       p.Title,
 		Description: p.Description,
 		TimeLimit:   shared.IntToInt32(timeLimit),
 		MemoryLimit: memoryLimit,
 		Examples:    exs,
 		TestCases:   tcs,
+		Generator:   generator,
+	}
+	if generator != nil && (strings.TrimSpace(p.Signature) != "" || strings.TrimSpace(p.SQLSchema) != "") {
+		return shared.Problem{}, fmt.Errorf("el generador solo está disponible para problemas de entrada estándar")
+	}
+	if decl := strings.TrimSpace(p.Signature); decl != "" {
+		if err := applySignature(&problem, decl); err != nil {
+			return shared.Problem{}, err
+		}
+	}
+	if strings.TrimSpace(p.SQLSchema) != "" {
+		if problem.Signature != "" {
+			return shared.Problem{}, fmt.Errorf("un problema SQL no puede tener firma de función")
+		}
+		if err := applyDatabase(&problem, p); err != nil {
+			return shared.Problem{}, err
+		}
+	}
+	return problem, nil
+}
+
+// validateTestCase takes large test data written by hand up to
+// maxTestCaseLength, data that is already in the blob store keeps its hash
+func validateTestCase(tc TestCaseJ) (shared.TestCase, error) {
+	res := shared.TestCase{Input: tc.Input, Output: tc.Output}
+	if tc.InputHash != "" || tc.OutputHash != "" {
+		for _, hash := range []string{tc.InputHash, tc.OutputHash} {
+			if hash != "" && !blobstore.ValidHash(hash) {
+				return shared.TestCase{}, fmt.Errorf("caso de prueba inválido")
+			}
+		}
+		res.InputHash, res.OutputHash = tc.InputHash, tc.OutputHash
+	}
+	if res.InputHash == "" && (len(tc.Input) < 1 || len(tc.Input) > maxTestCaseLength) {
+		return shared.TestCase{}, lenError("entrada", 1, maxTestCaseLength)
+	}
+	if res.OutputHash == "" && (len(tc.Output) < 1 || len(tc.Output) > maxTestCaseLength) {
+		return shared.TestCase{}, lenError("salida", 1, maxTestCaseLength)
+	}
+	return res, nil
+}
+
+// validateGenerator returns nil when the problem has no generator
+func validateGenerator(p ProblemJ) (*shared.Generator, error) {
+	if strings.TrimSpace(p.Generator) == "" {
+		return nil, nil
+	}
+	languageID, err := shared.ValidateLanguageID(p.GeneratorLanguage)
+	if err != nil {
+		return nil, fmt.Errorf("lenguaje del generador inválido")
+	}
+	if len(p.Generator) > maxGeneratorSrc {
+		return nil, lenError("generador", 1, maxGeneratorSrc)
+	}
+	if len(strings.TrimSpace(p.GeneratorSolution)) < 1 || len(p.GeneratorSolution) > maxGeneratorSrc {
+		return nil, lenError("solución del generador", 1, maxGeneratorSrc)
+	}
+	seeds := []string{}
+	for _, line := range strings.Split(p.GeneratorSeeds, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if len(line) > maxSeedLength {
+			return nil, lenError("semilla", 1, maxSeedLength)
+		}
+		seeds = append(seeds, line)
+	}
+	if len(seeds) < 1 || len(seeds) > maxGeneratorSeeds {
+		return nil, fmt.Errorf("debe haber entre 1 y %d semillas", maxGeneratorSeeds)
+	}
+	return &shared.Generator{
+		LanguageID: languageID,
+		Src:        p.Generator,
+		Solution:   p.GeneratorSolution,
+		Seeds:      seeds,
+	}, nil
+}
+
+const (
+	maxQuestionPoints  = 100
+	maxQuestionOptions = 10
+	maxQuestionAnswers = 10
+	maxOptionLength    = 500
+	maxRubricLength    = 2000
+)
+
+// validateQuestion builds a problem answered without code, the options are
+// written one per line and the correct ones start with '*'
+func validateQuestion(p ProblemJ) (shared.Problem, error) {
+	if _, ok := shared.QuestionLabels[p.Kind]; !ok {
+		return shared.Problem{}, fmt.Errorf("tipo de pregunta inválido")
+	}
+	if strings.TrimSpace(p.Signature) != "" || strings.TrimSpace(p.SQLSchema) != "" {
+		return shared.Problem{}, fmt.Errorf("una pregunta no puede tener firma de función ni esquema SQL")
+	}
+	if strings.TrimSpace(p.Generator) != "" {
+		return shared.Problem{}, fmt.Errorf("una pregunta no puede tener generador de casos de prueba")
+	}
+	points, err := strconv.Atoi(p.Points)
+	if err != nil || points < 1 || points > maxQuestionPoints {
+		return shared.Problem{}, fmt.Errorf("el puntaje debe estar entre 1 y %d", maxQuestionPoints)
+	}
+	question := shared.Question{
+		Kind:    p.Kind,
+		Points:  shared.IntToInt32(points),
+		Options: []shared.QuestionOption{},
+		Answers: []string{},
+	}
+	switch {
+	case question.Choice():
+		if err := applyOptions(&question, p.Options); err != nil {
+			return shared.Problem{}, err
+		}
+	case p.Kind == shared.QuestionNumeric || p.Kind == shared.QuestionShort:
+		if err := applyAnswers(&question, p); err != nil {
+			return shared.Problem{}, err
+		}
+	case p.Kind == shared.QuestionText:
+		if len(p.Rubric) > maxRubricLength {
+			return shared.Problem{}, lenError("rúbrica", 0, maxRubricLength)
+		}
+		question.Rubric = strings.TrimSpace(p.Rubric)
+	}
+	return shared.Problem{
+		Title:       p.Title,
+		Description: p.Description,
+		TimeLimit:   1000,
+		MemoryLimit: memoryLimit,
+		Examples:    []shared.Example{},
+		TestCases:   []shared.TestCase{},
+		Question:    &question,
 	}, nil
 }
 
+func applyOptions(question *shared.Question, options string) error {
+	correct := 0
+	for _, line := range strings.Split(options, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		option := shared.QuestionOption{}
+		if label, ok := strings.CutPrefix(line, "*"); ok {
+			option.Correct = true
+			line = strings.TrimSpace(label)
+			correct++
+		}
+		if len(line) < 1 || len(line) > maxOptionLength {
+			return lenError("opción", 1, maxOptionLength)
+		}
+		option.Label = line
+		question.Options = append(question.Options, option)
+	}
+	if len(question.Options) < 2 || len(question.Options) > maxQuestionOptions {
+		return fmt.Errorf("debe haber entre 2 y %d opciones", maxQuestionOptions)
+	}
+	if question.Kind == shared.QuestionSingle && correct != 1 {
+		return fmt.Errorf("una pregunta de opción única debe tener una sola opción correcta")
+	}
+	if correct < 1 {
+		return fmt.Errorf("marca con '*' al menos una opción correcta")
+	}
+	return nil
+}
+
+func applyAnswers(question *shared.Question, p ProblemJ) error {
+	for _, line := range strings.Split(p.Answers, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if len(line) > shared.MaxShortAnswer {
+			return lenError("respuesta", 1, shared.MaxShortAnswer)
+		}
+		if question.Kind == shared.QuestionNumeric {
+			if _, err := shared.ParseNumber(line); err != nil {
+				return err
+			}
+		}
+		question.Answers = append(question.Answers, line)
+	}
+	if len(question.Answers) < 1 || len(question.Answers) > maxQuestionAnswers {
+		return fmt.Errorf("debe haber entre 1 y %d respuestas aceptadas", maxQuestionAnswers)
+	}
+	if question.Kind == shared.QuestionNumeric && strings.TrimSpace(p.Tolerance) != "" {
+		tolerance, err := shared.ParseNumber(p.Tolerance)
+		if err != nil || tolerance < 0 {
+			return fmt.Errorf("la tolerancia debe ser un número positivo")
+		}
+		question.Tolerance = tolerance
+	}
+	return nil
+}
+
+// applyDatabase turns the problem into a SQL problem, the expected results
+// are stored in the form the judge prints them
+func applyDatabase(problem *shared.Problem, p ProblemJ) error {
+	if len(p.SQLSchema) > sqljudge.MaxScriptLength {
+		return lenError("esquema", 1, sqljudge.MaxScriptLength)
+	}
+	if len(p.SQLSeed) > sqljudge.MaxScriptLength {
+		return lenError("datos", 0, sqljudge.MaxScriptLength)
+	}
+	if p.SQLOrder != "ordered" && p.SQLOrder != "unordered" {
+		return fmt.Errorf("orden de filas inválido")
+	}
+	problem.Database = &shared.Database{
+		Schema:  strings.TrimSpace(p.SQLSchema),
+		Seed:    strings.TrimSpace(p.SQLSeed),
+		Ordered: p.SQLOrder == "ordered",
+	}
+	for i, tc := range problem.TestCases {
+		// the scripts are built from the rows, SQL data never goes to the
+		// blob store
+		if tc.Truncated() || len(tc.Input) > shared.InlineTestDataSize || len(tc.Output) > shared.InlineTestDataSize {
+			return lenError("caso de prueba SQL", 1, shared.InlineTestDataSize)
+		}
+		problem.TestCases[i].Output = sqljudge.Result(tc.Output, problem.Database.Ordered)
+	}
+	for i, ex := range problem.Examples {
+		problem.Examples[i].Output = sqljudge.Result(ex.Output, problem.Database.Ordered)
+	}
+	return nil
+}
+
+// applySignature checks that every test case and example encodes the
+// parameters and the result of the function as JSON, and stores them in the
+// exact form the harness reads and prints
+func applySignature(problem *shared.Problem, decl string) error {
+	sig, err := signature.Parse(decl)
+	if err != nil {
+		return err
+	}
+	problem.Signature = sig.String()
+	for i, tc := range problem.TestCases {
+		// large test data was checked when it was first stored
+		if tc.Truncated() {
+			continue
+		}
+		input, output, err := checkCase(sig, tc.Input, tc.Output)
+		if err != nil {
+			return fmt.Errorf("caso de prueba %d: %w", i+1, err)
+		}
+		problem.TestCases[i].Input, problem.TestCases[i].Output = input, output
+	}
+	for i, ex := range problem.Examples {
+		input, output, err := checkCase(sig, ex.Input, ex.Output)
+		if err != nil {
+			return fmt.Errorf("ejemplo %d: %w", i+1, err)
+		}
+		problem.Examples[i].Input, problem.Examples[i].Output = input, output
+	}
+	return nil
+}
+
+func checkCase(sig signature.Signature, input, output string) (string, string, error) {
+	input, err := sig.CheckInput(input)
+	if err != nil {
+		return "", "", err
+	}
+	output, err = sig.CheckOutput(output)
+	if err != nil {
+		return "", "", err
+	}
+	return input, output, nil
+}
+
+func ValidateBankProblems(ids []string) ([]string, error) {
+	res := []string{}
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if err := uuid.Validate(id); err != nil {
+			return nil, fmt.Errorf("problema del banco inválido")
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		res = append(res, id)
+	}
+	return res, nil
+}
+
 func GetOfferRegInput(r *http.Request) (OfferRegInput, error) {
 	jsonInput, err := shared.Decode[OfferRegJ](r)
 	if err != nil {
@@ -202,8 +721,20 @@ func GetOfferRegInput(r *http.Request) (OfferRegInput, error) {
 		}
 		problems = append(problems, problem)
 	}
+	bankProblemIDs, err := ValidateBankProblems(jsonInput.Bank)
+	if err != nil {
+		return OfferRegInput{}, err
+	}
+	total := len(problems) + len(bankProblemIDs)
+	if len(quiz.Pools) == 0 && (total < 1 || total > maxProblems) {
+		return OfferRegInput{}, fmt.Errorf("debe haber entre 1 y %d problemas", maxProblems)
+	}
+	if len(quiz.Pools) > 0 && (total < 1 || total > maxPoolProblems) {
+		return OfferRegInput{}, fmt.Errorf("una prueba con grupos debe tener entre 1 y %d problemas", maxPoolProblems)
+	}
 	input.Offer = offer
 	input.Quiz = quiz
 	input.Problems = problems
+	input.BankProblemIDs = bankProblemIDs
 	return input, nil
 }
@@ -4,16 +4,25 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/kw3a/spotted-server/internal/auth"
 	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/kw3a/spotted-server/internal/server/textsearch"
 )
 
 type OfferListData struct {
-	User     auth.AuthUser
-	Offers   []shared.Offer
-	Search   string
-	NextPage int32
+	User    auth.AuthUser
+	Offers  []shared.Offer
+	Filters shared.OfferFilters
+	// Facets are only counted for the first page, the next ones are
+	// appended to the list. NextURL is empty on the last page
+	Facets      shared.OfferFacets
+	Modalities  map[string]string
+	Seniorities map[string]string
+	Contracts   map[string]string
+	Currencies  []string
+	NextURL     string
 }
 
 const (
@@ -21,25 +30,118 @@ const (
 	errorQueryLimit = "el límite máximo es de 30 caracteres"
 )
 
+const snippetWidth = 180
+
 type OfferListStorage interface {
-	SelectOffers(ctx context.Context, params shared.OfferQueryParams) ([]shared.Offer, error)
+	SearchOffers(ctx context.Context, filters shared.OfferFilters) ([]shared.Offer, *shared.OfferCursor, error)
+	SearchFacets(ctx context.Context, filters shared.OfferFilters) (shared.OfferFacets, error)
 }
 
-func GetListParams(r *http.Request) (shared.OfferQueryParams, error) {
-	params := shared.OfferQueryParams{}
+// GetListParams reads the search filters from the query string, unknown
+// values are rejected instead of ignored so a bad link doesn't look empty
+func GetListParams(r *http.Request) (shared.OfferFilters, error) {
 	q := r.URL.Query()
-	query := q.Get("q")
-	if len(query) > queryUpperLimit {
-		return shared.OfferQueryParams{}, fmt.Errorf(errorQueryLimit)
+	filters := shared.OfferFilters{
+		Query:     q.Get("q"),
+		Modality:  q.Get("modality"),
+		Seniority: q.Get("seniority"),
+		Contract:  q.Get("contract"),
+		CompanyID: q.Get("company"),
+		Currency:  q.Get("currency"),
+		Sort:      q.Get("sort"),
+	}
+	if len(filters.Query) > queryUpperLimit {
+		return shared.OfferFilters{}, fmt.Errorf(errorQueryLimit)
+	}
+	if _, ok := shared.ModalityLabels[filters.Modality]; filters.Modality != "" && !ok {
+		return shared.OfferFilters{}, fmt.Errorf("modalidad inválida")
+	}
+	if _, ok := shared.SeniorityLabels[filters.Seniority]; filters.Seniority != "" && !ok {
+		return shared.OfferFilters{}, fmt.Errorf("nivel de experiencia inválido")
 	}
-	if query != "" {
-		params.Query = query
+	if _, ok := shared.ContractLabels[filters.Contract]; filters.Contract != "" && !ok {
+		return shared.OfferFilters{}, fmt.Errorf("tipo de contrato inválido")
 	}
-	params.Page = shared.PageParam(r)
-	return params, nil
+	if filters.CompanyID != "" {
+		if err := shared.ValidateUUID(filters.CompanyID); err != nil {
+			return shared.OfferFilters{}, fmt.Errorf("empresa inválida")
+		}
+	}
+	if filters.Currency != "" && !shared.ValidCurrency(filters.Currency) {
+		return shared.OfferFilters{}, fmt.Errorf("moneda inválida")
+	}
+	switch filters.Sort {
+	case "":
+		// relevance only makes sense with words to rank
+		filters.Sort = shared.SortDate
+		if len(textsearch.Terms(filters.Query)) > 0 {
+			filters.Sort = shared.SortRelevance
+		}
+	case shared.SortDate, shared.SortWage, shared.SortRelevance:
+	default:
+		return shared.OfferFilters{}, fmt.Errorf("orden inválido")
+	}
+	if after := q.Get("after"); after != "" {
+		cursor, err := shared.ParseOfferCursor(after)
+		if err != nil {
+			return shared.OfferFilters{}, err
+		}
+		filters.After = &cursor
+	}
+	if len(q["tag"]) > shared.MaxOfferTags {
+		return shared.OfferFilters{}, fmt.Errorf("se pueden elegir hasta %d tecnologías", shared.MaxOfferTags)
+	}
+	for _, tag := range q["tag"] {
+		id, err := strconv.Atoi(tag)
+		if err != nil {
+			return shared.OfferFilters{}, fmt.Errorf("la tecnología debe ser un número")
+		}
+		if !filters.HasTag(shared.IntToInt32(id)) {
+			filters.Tags = append(filters.Tags, shared.IntToInt32(id))
+		}
+	}
+	minWage, err := wageParam(q.Get("minWage"))
+	if err != nil {
+		return shared.OfferFilters{}, err
+	}
+	maxWage, err := wageParam(q.Get("maxWage"))
+	if err != nil {
+		return shared.OfferFilters{}, err
+	}
+	if maxWage > 0 && minWage > maxWage {
+		return shared.OfferFilters{}, fmt.Errorf("el salario mínimo supera al máximo")
+	}
+	filters.MinWage = minWage
+	filters.MaxWage = maxWage
+	return filters, nil
+}
+
+func wageParam(value string) (int32, error) {
+	if value == "" {
+		return 0, nil
+	}
+	wage, err := strconv.Atoi(value)
+	if err != nil || wage < 0 {
+		return 0, fmt.Errorf("el salario debe ser un número positivo")
+	}
+	return shared.IntToInt32(wage), nil
 }
 
-type OfferListParamsFn func(r *http.Request) (shared.OfferQueryParams, error)
+// offerSnippet cuts the snippet from the first field that matched, the title
+// is already shown in full
+func offerSnippet(offer shared.Offer, terms []string) []textsearch.Fragment {
+	for _, text := range []string{offer.About, offer.Requirements} {
+		fragments := textsearch.Snippet(text, terms, snippetWidth)
+		for _, fragment := range fragments {
+			if fragment.Match {
+				return fragments
+			}
+		}
+	}
+	return textsearch.Snippet(offer.About, terms, snippetWidth)
+}
+
+type OfferListParamsFn func(r *http.Request) (shared.OfferFilters, error)
 
 func CreateOfferListHandler(
 	paramsFn OfferListParamsFn,
@@ -53,27 +155,44 @@ func CreateOfferListHandler(
 			http.Error(w, err.Error(), http.StatusUnauthorized)
 			return
 		}
-		params, err := paramsFn(r)
+		filters, err := paramsFn(r)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		offers, err := storage.SelectOffers(r.Context(), params)
+		offers, next, err := storage.SearchOffers(r.Context(), filters)
 		if err != nil {
 			http.Error(w, "can't find offers", http.StatusInternalServerError)
 			return
 		}
+		if terms := textsearch.Terms(filters.Query); len(terms) > 0 {
+			for i := range offers {
+				offers[i].Snippet = offerSnippet(offers[i], terms)
+			}
+		}
 		data := OfferListData{
-			User:     user,
-			Offers:   offers,
-			NextPage: params.Page + 1,
+			User:        user,
+			Offers:      offers,
+			Filters:     filters,
+			Modalities:  shared.ModalityLabels,
+			Seniorities: shared.SeniorityLabels,
+			Contracts:   shared.ContractLabels,
+			Currencies:  shared.Currencies,
 		}
-		if params.Query != "" {
-			data.Search = params.Query
+		if next != nil {
+			values := filters.Values()
+			values.Set("after", next.String())
+			data.NextURL = "/?" + values.Encode()
 		}
 		toRender := "offerListPage"
-		if params.Page > 1 {
+		if filters.After != nil {
 			toRender = "offerList"
+		} else {
+			data.Facets, err = storage.SearchFacets(r.Context(), filters)
+			if err != nil {
+				http.Error(w, "can't count offers", http.StatusInternalServerError)
+				return
+			}
 		}
 		if err = templ.Render(w, toRender, data); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -17,6 +17,10 @@ type PreambleData struct {
 	Languages     []shared.Language
 	Participation shared.Participation
 	QuizAlive     bool
+	// Why a new participation cannot start right now, empty when it can
+	AccessMsg      string
+	InvitationCode string
+	InvitationMsg  string
 }
 
 type Result struct {
@@ -25,7 +29,8 @@ type Result struct {
 }
 
 type PreambleInput struct {
-	OfferID string
+	OfferID    string
+	Invitation string
 }
 
 func GetPreambleInput(r *http.Request) (PreambleInput, error) {
@@ -34,7 +39,8 @@ func GetPreambleInput(r *http.Request) (PreambleInput, error) {
 		return PreambleInput{}, err
 	}
 	return PreambleInput{
-		OfferID: quizID,
+		OfferID:    quizID,
+		Invitation: r.URL.Query().Get("invitation"),
 	}, nil
 }
 
@@ -45,9 +51,13 @@ type PreambleStorage interface {
 	SelectLanguages(ctx context.Context, quizID string) ([]shared.Language, error)
 }
 
+type InvitationVerifier interface {
+	VerifyInvitation(token string) (string, error)
+}
+
 type preambleInputFunc = func(r *http.Request) (PreambleInput, error)
 
-func CreateParticipationHandler(templ shared.TemplatesRepo, storage PreambleStorage, authService shared.AuthRep, inputFn preambleInputFunc) http.HandlerFunc {
+func CreateParticipationHandler(templ shared.TemplatesRepo, storage PreambleStorage, authService shared.AuthRep, verifier InvitationVerifier, inputFn preambleInputFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		user, err := authService.GetUser(r)
 		if err != nil {
@@ -68,7 +78,9 @@ func CreateParticipationHandler(templ shared.TemplatesRepo, storage PreambleStor
 			Offer: offer,
 			User:  user,
 		}
-		if offer.Status != 0 {
+		if offer.Status == shared.OfferDraft {
+			data.AccessMsg = shared.ErrOfferNotPublished.Error()
+		} else {
 			quiz, err := storage.SelectQuizByOffer(r.Context(), offer.ID)
 			if err != nil {
 				http.Error(w, err.Error(), http.StatusBadRequest)
@@ -79,12 +91,23 @@ func CreateParticipationHandler(templ shared.TemplatesRepo, storage PreambleStor
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
+			quiz.OfferStatus = offer.Status
 			data.Quiz = quiz
 			data.Languages = languages
+			if err := quiz.Available(time.Now()); err != nil {
+				data.AccessMsg = err.Error()
+			}
+			if input.Invitation != "" {
+				code, err := verifier.VerifyInvitation(input.Invitation)
+				if err != nil {
+					data.InvitationMsg = shared.ErrInvitationInvalid.Error()
+				}
+				data.InvitationCode = code
+			}
 			participation, err := storage.ParticipationStatus(r.Context(), user.ID, quiz.ID)
 			if err == nil {
 				data.Participation = participation
-				if data.Participation.ExpiresAt.After(time.Now()) {
+				if !data.Participation.Finished(time.Now()) {
 					data.QuizAlive = true
 				}
 			}
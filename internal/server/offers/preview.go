@@ -0,0 +1,51 @@
+package offers
+
+import (
+	"net/http"
+
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/markdown"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+type PreviewInput struct {
+	Text string
+}
+
+func GetPreviewInput(r *http.Request) (PreviewInput, error) {
+	text := r.FormValue("text")
+	if len(text) > markdown.MaxLength {
+		return PreviewInput{}, lenError("texto", 0, markdown.MaxLength)
+	}
+	return PreviewInput{Text: text}, nil
+}
+
+type previewInputFn func(r *http.Request) (PreviewInput, error)
+
+// CreatePreviewHandler renders the Markdown of a statement the same way the
+// candidates will see it
+func CreatePreviewHandler(
+	inputFn previewInputFn,
+	authService shared.AuthRep,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := templ.Render(w, "markdownPreview", input.Text); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package offers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const maxProctoringThreshold = 100
+
+type ProctoringRulesStorage interface {
+	SelectOfferByUser(ctx context.Context, id string, userID string) (shared.Offer, error)
+	SelectQuizByOffer(ctx context.Context, offerID string) (shared.Quiz, error)
+	UpsertProctoringRule(ctx context.Context, quizID string, rule shared.ProctoringRule) error
+	SelectProctoringRules(ctx context.Context, quizID string) ([]shared.ProctoringRule, error)
+}
+
+type ProctoringRulesInput struct {
+	OfferID string
+	Rule    shared.ProctoringRule
+}
+
+type ProctoringRulesData struct {
+	OfferID string
+	Rules   []shared.ProctoringRule
+	Alert   shared.Alert
+}
+
+func GetProctoringRulesInput(r *http.Request) (ProctoringRulesInput, error) {
+	offerID := chi.URLParam(r, "offerID")
+	if err := shared.ValidateUUID(offerID); err != nil {
+		return ProctoringRulesInput{}, err
+	}
+	kind := r.FormValue("kind")
+	if err := shared.ValidateProctoringKind(kind); err != nil {
+		return ProctoringRulesInput{}, err
+	}
+	threshold, err := strconv.Atoi(r.FormValue("threshold"))
+	if err != nil {
+		return ProctoringRulesInput{}, fmt.Errorf("el límite debe ser un número")
+	}
+	if threshold < 0 || threshold > maxProctoringThreshold {
+		return ProctoringRulesInput{}, fmt.Errorf("el límite debe estar entre 0 y %d", maxProctoringThreshold)
+	}
+	return ProctoringRulesInput{
+		OfferID: offerID,
+		Rule: shared.ProctoringRule{
+			Kind:      kind,
+			Threshold: shared.IntToInt32(threshold),
+		},
+	}, nil
+}
+
+// Every known kind is listed, kinds without a stored rule keep a zero
+// threshold so the recruiter can enable them from the same form
+func MergeProctoringRules(stored []shared.ProctoringRule) []shared.ProctoringRule {
+	thresholds := make(map[string]int32)
+	for _, rule := range stored {
+		thresholds[rule.Kind] = rule.Threshold
+	}
+	res := []shared.ProctoringRule{}
+	for _, kind := range shared.ProctoringKinds {
+		res = append(res, shared.ProctoringRule{
+			Kind:      kind,
+			Label:     shared.ProctoringLabels[kind],
+			Threshold: thresholds[kind],
+		})
+	}
+	return res
+}
+
+type proctoringRulesInputFn func(r *http.Request) (ProctoringRulesInput, error)
+
+func CreateProctoringRulesHandler(
+	inputFn proctoringRulesInputFn,
+	authService shared.AuthRep,
+	storage ProctoringRulesStorage,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offer, err := storage.SelectOfferByUser(r.Context(), input.OfferID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if offer.ReadOnly() {
+			http.Error(w, shared.ErrNotEditor.Error(), http.StatusUnauthorized)
+			return
+		}
+		quiz, err := storage.SelectQuizByOffer(r.Context(), offer.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := storage.UpsertProctoringRule(r.Context(), quiz.ID, input.Rule); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rules, err := storage.SelectProctoringRules(r.Context(), quiz.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data := ProctoringRulesData{
+			OfferID: offer.ID,
+			Rules:   MergeProctoringRules(rules),
+			Alert:   shared.Alert{Ok: true, Msg: shared.MsgSaved},
+		}
+		if err := templ.Render(w, "proctoringRules", data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package offers
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// Format of datetime-local inputs, interpreted in the server time zone
+const accessTimeLayout = "2006-01-02T15:04"
+
+type InvitationSigner interface {
+	SignInvitation(code string) string
+}
+
+type QuizAccessStorage interface {
+	SelectOfferByUser(ctx context.Context, id string, userID string) (shared.Offer, error)
+	SelectQuizByOffer(ctx context.Context, offerID string) (shared.Quiz, error)
+	UpdateQuizAccess(ctx context.Context, quiz shared.Quiz) error
+	SelectInvitations(ctx context.Context, quizID string) ([]shared.Invitation, error)
+	InsertInvitations(ctx context.Context, quizID string, emails []string) error
+	DeleteInvitation(ctx context.Context, quizID string, invitationID string) error
+}
+
+type InvitationLink struct {
+	Invitation shared.Invitation
+	Link       string
+}
+
+type QuizAccessData struct {
+	OfferID     string
+	Quiz        shared.Quiz
+	Invitations []InvitationLink
+	Alert       shared.Alert
+}
+
+type QuizAccessInput struct {
+	OfferID    string
+	OpensAt    time.Time
+	ClosesAt   time.Time
+	InviteOnly bool
+}
+
+func parseAccessTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.ParseInLocation(accessTimeLayout, value, time.Local)
+}
+
+func GetQuizAccessInput(r *http.Request) (QuizAccessInput, error) {
+	offerID := chi.URLParam(r, "offerID")
+	if err := shared.ValidateUUID(offerID); err != nil {
+		return QuizAccessInput{}, err
+	}
+	opensAt, err := parseAccessTime(r.FormValue("opensAt"))
+	if err != nil {
+		return QuizAccessInput{}, err
+	}
+	closesAt, err := parseAccessTime(r.FormValue("closesAt"))
+	if err != nil {
+		return QuizAccessInput{}, err
+	}
+	if !opensAt.IsZero() && !closesAt.IsZero() && !opensAt.Before(closesAt) {
+		return QuizAccessInput{}, shared.ErrQuizWindow
+	}
+	return QuizAccessInput{
+		OfferID:    offerID,
+		OpensAt:    opensAt,
+		ClosesAt:   closesAt,
+		InviteOnly: r.FormValue("inviteOnly") == "on",
+	}, nil
+}
+
+func invitationLinks(offerID string, invitations []shared.Invitation, signer InvitationSigner) []InvitationLink {
+	baseURL := os.Getenv("MY_URL")
+	res := make([]InvitationLink, len(invitations))
+	for i, invitation := range invitations {
+		token := signer.SignInvitation(invitation.Code)
+		res[i] = InvitationLink{
+			Invitation: invitation,
+			Link:       baseURL + "/preamble/" + offerID + "?invitation=" + url.QueryEscape(token),
+		}
+	}
+	return res
+}
+
+func renderQuizAccess(
+	w http.ResponseWriter,
+	r *http.Request,
+	storage QuizAccessStorage,
+	signer InvitationSigner,
+	templ shared.TemplatesRepo,
+	offerID string,
+	quiz shared.Quiz,
+	alert shared.Alert,
+) {
+	invitations, err := storage.SelectInvitations(r.Context(), quiz.ID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	data := QuizAccessData{
+		OfferID:     offerID,
+		Quiz:        quiz,
+		Invitations: invitationLinks(offerID, invitations, signer),
+		Alert:       alert,
+	}
+	if err := templ.Render(w, "quizAccess", data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// Rule violations only replace the alert so the recruiter keeps the form
+func renderAccessAlert(w http.ResponseWriter, templ shared.TemplatesRepo, err error) {
+	w.Header().Set("HX-Retarget", "#access-alert")
+	w.Header().Set("HX-Reswap", "innerHTML")
+	if err := templ.Render(w, "accessAlert", shared.Alert{Ok: false, Msg: err.Error()}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+type quizAccessPageInputFn func(r *http.Request) (ApplicantsInput, error)
+
+func CreateQuizAccessHandler(
+	inputFn quizAccessPageInputFn,
+	authService shared.AuthRep,
+	storage QuizAccessStorage,
+	signer InvitationSigner,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offer, err := storage.SelectOfferByUser(r.Context(), input.OfferID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		quiz, err := storage.SelectQuizByOffer(r.Context(), offer.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		renderQuizAccess(w, r, storage, signer, templ, offer.ID, quiz, shared.Alert{})
+	}
+}
+
+type quizAccessInputFn func(r *http.Request) (QuizAccessInput, error)
+
+func CreateQuizAccessUpdateHandler(
+	inputFn quizAccessInputFn,
+	authService shared.AuthRep,
+	storage QuizAccessStorage,
+	signer InvitationSigner,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if shared.IsAccessError(err) {
+			renderAccessAlert(w, templ, err)
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offer, err := storage.SelectOfferByUser(r.Context(), input.OfferID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if offer.ReadOnly() {
+			http.Error(w, shared.ErrNotEditor.Error(), http.StatusUnauthorized)
+			return
+		}
+		quiz, err := storage.SelectQuizByOffer(r.Context(), offer.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		quiz.OpensAt = input.OpensAt
+		quiz.ClosesAt = input.ClosesAt
+		quiz.InviteOnly = input.InviteOnly
+		if err := storage.UpdateQuizAccess(r.Context(), quiz); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		renderQuizAccess(w, r, storage, signer, templ, offer.ID, quiz, shared.Alert{Ok: true, Msg: shared.MsgSaved})
+	}
+}
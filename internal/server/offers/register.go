@@ -2,6 +2,7 @@ package offers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -19,8 +20,9 @@ type OfferStorage interface {
 		offerID string, offer shared.Offer,
 		quizID string, quiz shared.Quiz,
 		problems []shared.Problem,
+		bankProblemIDs []string,
 	) error
-	GetCompanyByID(ctx context.Context, companyID string) (shared.Company, error)
+	SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error)
 }
 
 type OfferRegInputFn func(r *http.Request) (OfferRegInput, error)
@@ -43,13 +45,13 @@ func CreateRegisterHandler(
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		company, err := storage.GetCompanyByID(r.Context(), input.Offer.CompanyID)
+		role, err := storage.SelectCompanyRole(r.Context(), input.Offer.CompanyID, user.ID)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("storage error: %s", err.Error()), http.StatusInternalServerError)
 			return
 		}
-		if company.UserID != user.ID {
-			http.Error(w, "you are not the owner of this company", http.StatusUnauthorized)
+		if !shared.CanEdit(role) {
+			http.Error(w, "your role in this company can't publish offers", http.StatusUnauthorized)
 			return
 		}
 		offerID := uuid.New().String()
@@ -61,7 +63,13 @@ func CreateRegisterHandler(
 			quizID,
 			input.Quiz,
 			input.Problems,
+			input.BankProblemIDs,
 		); err != nil {
+			if errors.Is(err, shared.ErrBankProblemNotFound) || errors.Is(err, shared.ErrReferenceValidation) ||
+				errors.Is(err, shared.ErrGenerator) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
 			http.Error(w, fmt.Sprintf("storage error: %s", err.Error()), http.StatusInternalServerError)
 			return
 		}
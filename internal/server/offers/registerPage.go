@@ -20,6 +20,48 @@ type RegisterOfferData struct {
 	Languages []shared.Language
 }
 
+// OfferAttributesForm feeds the structured fields shared by the register
+// and edit forms
+type OfferAttributesForm struct {
+	Offer       shared.Offer
+	Languages   []shared.Language
+	Modalities  map[string]string
+	Seniorities map[string]string
+	Contracts   map[string]string
+	Currencies  []string
+	WagePeriods map[string]string
+}
+
+func newOfferAttributesForm(offer shared.Offer, languages []shared.Language) OfferAttributesForm {
+	return OfferAttributesForm{
+		Offer:       offer,
+		Languages:   languages,
+		Modalities:  shared.ModalityLabels,
+		Seniorities: shared.SeniorityLabels,
+		Contracts:   shared.ContractLabels,
+		Currencies:  shared.Currencies,
+		WagePeriods: shared.WagePeriodLabels,
+	}
+}
+
+func (d RegisterOfferData) Attributes() OfferAttributesForm {
+	offer := shared.Offer{
+		Modality:   shared.ModalityOnsite,
+		Currency:   shared.Currencies[0],
+		WagePeriod: shared.WageYearly,
+	}
+	return newOfferAttributesForm(offer, d.Languages)
+}
+
+// PoolRows indexes the pool inputs of the quiz form
+func (d RegisterOfferData) PoolRows() []int {
+	rows := make([]int, maxPools)
+	for i := range rows {
+		rows[i] = i
+	}
+	return rows
+}
+
 func CreateRegisterPage(
 	authz shared.AuthRep,
 	templ shared.TemplatesRepo,
@@ -0,0 +1,234 @@
+package offers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/codejudge"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/kw3a/spotted-server/internal/server/signature"
+	"github.com/kw3a/spotted-server/internal/server/sqljudge"
+)
+
+// RejudgeWorkers bounds the requests sent to the judge at the same time
+const RejudgeWorkers = 4
+
+const maxRejudgeError = 255
+
+type RejudgeInput struct {
+	OfferID string
+	Request shared.RejudgeRequest
+}
+
+// GetRejudgeInput reads the scope from the route, a single submission, a
+// participation or a problem of the offer
+func GetRejudgeInput(r *http.Request) (RejudgeInput, error) {
+	if submissionID := chi.URLParam(r, "submissionID"); submissionID != "" {
+		if err := shared.ValidateUUID(submissionID); err != nil {
+			return RejudgeInput{}, err
+		}
+		return RejudgeInput{Request: shared.RejudgeRequest{Scope: shared.RejudgeSubmission, TargetID: submissionID}}, nil
+	}
+	if participationID := chi.URLParam(r, "participationID"); participationID != "" {
+		if err := shared.ValidateUUID(participationID); err != nil {
+			return RejudgeInput{}, err
+		}
+		return RejudgeInput{Request: shared.RejudgeRequest{Scope: shared.RejudgeParticipation, TargetID: participationID}}, nil
+	}
+	offerID := chi.URLParam(r, "offerID")
+	if err := shared.ValidateUUID(offerID); err != nil {
+		return RejudgeInput{}, err
+	}
+	problemID := chi.URLParam(r, "problemID")
+	if err := shared.ValidateUUID(problemID); err != nil {
+		return RejudgeInput{}, err
+	}
+	return RejudgeInput{
+		OfferID: offerID,
+		Request: shared.RejudgeRequest{
+			Scope:    shared.RejudgeProblem,
+			TargetID: problemID,
+			Latest:   r.FormValue("latest") == "on",
+		},
+	}, nil
+}
+
+type RejudgeStorage interface {
+	CreateRejudge(ctx context.Context, userID string, offerID string, req shared.RejudgeRequest) (string, []shared.RejudgeEntry, error)
+	SelectRejudge(ctx context.Context, rejudgeID, userID string) (shared.Rejudge, error)
+}
+
+type RejudgeDispatcher interface {
+	Dispatch(rejudgeID string, entries []shared.RejudgeEntry)
+}
+
+type rejudgeInputFn func(r *http.Request) (RejudgeInput, error)
+
+// CreateRejudgeHandler resets the results in the scope and sends the
+// submissions to the judge again, the response shows the progress
+func CreateRejudgeHandler(
+	inputFn rejudgeInputFn,
+	authService shared.AuthRep,
+	storage RejudgeStorage,
+	dispatcher RejudgeDispatcher,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rejudgeID, entries, err := storage.CreateRejudge(r.Context(), user.ID, input.OfferID, input.Request)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) ||
+				errors.Is(err, shared.ErrNothingToRejudge) ||
+				errors.Is(err, shared.ErrReferenceValidation) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		dispatcher.Dispatch(rejudgeID, entries)
+		rejudge, err := storage.SelectRejudge(r.Context(), rejudgeID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := templ.Render(w, "rejudgeProgress", rejudge); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type RejudgeProgressInput struct {
+	RejudgeID string
+}
+
+func GetRejudgeProgressInput(r *http.Request) (RejudgeProgressInput, error) {
+	rejudgeID := chi.URLParam(r, "rejudgeID")
+	if err := shared.ValidateUUID(rejudgeID); err != nil {
+		return RejudgeProgressInput{}, err
+	}
+	return RejudgeProgressInput{RejudgeID: rejudgeID}, nil
+}
+
+type RejudgeProgressStorage interface {
+	SelectRejudge(ctx context.Context, rejudgeID, userID string) (shared.Rejudge, error)
+}
+
+type rejudgeProgressInputFn func(r *http.Request) (RejudgeProgressInput, error)
+
+func CreateRejudgeProgressHandler(
+	inputFn rejudgeProgressInputFn,
+	authService shared.AuthRep,
+	storage RejudgeProgressStorage,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		rejudge, err := storage.SelectRejudge(r.Context(), input.RejudgeID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := templ.Render(w, "rejudgeProgress", rejudge); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type RejudgeSendStorage interface {
+	GetTestCases(ctx context.Context, problemID string) ([]codejudge.TestCase, error)
+	UpdateRejudgeError(ctx context.Context, rejudgeID, submissionID, msg string) error
+}
+
+// SendRejudge sends the submissions of a rejudge with bounded concurrency,
+// the results arrive through the usual judge callback. Failures are stored
+// so the progress doesn't wait for them forever
+func SendRejudge(
+	ctx context.Context,
+	storage RejudgeSendStorage,
+	judge codejudge.Sender,
+	rejudgeID string,
+	entries []shared.RejudgeEntry,
+	workers int,
+) {
+	testCases := make(map[string][]codejudge.TestCase)
+	batches := []codejudge.Batch{}
+	sent := []shared.RejudgeEntry{}
+	for _, entry := range entries {
+		tcs, ok := testCases[entry.ProblemID]
+		if !ok {
+			var err error
+			tcs, err = storage.GetTestCases(ctx, entry.ProblemID)
+			if err != nil {
+				recordRejudgeError(ctx, storage, rejudgeID, entry.SubmissionID, err)
+				continue
+			}
+			testCases[entry.ProblemID] = tcs
+		}
+		src, err := signature.Prepare(entry.Signature, entry.LanguageID, entry.Src)
+		if err != nil {
+			recordRejudgeError(ctx, storage, rejudgeID, entry.SubmissionID, err)
+			continue
+		}
+		judged, submission, err := sqljudge.Prepare(entry.Database, tcs, codejudge.Submission{
+			ID:         entry.SubmissionID,
+			Src:        src,
+			LanguageID: entry.LanguageID,
+		})
+		if err != nil {
+			recordRejudgeError(ctx, storage, rejudgeID, entry.SubmissionID, err)
+			continue
+		}
+		batches = append(batches, codejudge.Batch{
+			TestCases:  judged,
+			Submission: submission,
+		})
+		sent = append(sent, entry)
+	}
+	for i, err := range codejudge.SendAll(judge, batches, workers) {
+		if err != nil {
+			recordRejudgeError(ctx, storage, rejudgeID, sent[i].SubmissionID, err)
+		}
+	}
+}
+
+func recordRejudgeError(ctx context.Context, storage RejudgeSendStorage, rejudgeID, submissionID string, err error) {
+	msg := err.Error()
+	if len(msg) > maxRejudgeError {
+		msg = msg[:maxRejudgeError]
+	}
+	if err := storage.UpdateRejudgeError(ctx, rejudgeID, submissionID, msg); err != nil {
+		log.Printf("error recording rejudge failure: %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+package offers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+type ReviewApplicantsStorage interface {
+	ReviewApplicants(ctx context.Context, recruiterID string, participationIDs []string, review shared.Review) error
+}
+
+type ReviewApplicantStorage interface {
+	ReviewApplicantsStorage
+	SelectPipeline(ctx context.Context, participationID string) (shared.Pipeline, error)
+}
+
+type ReviewApplicantInput struct {
+	ParticipationID string
+	Review          shared.Review
+}
+
+type BulkReviewInput struct {
+	OfferID          string
+	ParticipationIDs []string
+	Review           shared.Review
+}
+
+// getReview reads the action and its value, the stage is checked against
+// the pipeline of the company when it is saved
+func getReview(r *http.Request) (shared.Review, error) {
+	review := shared.Review{Action: r.FormValue("action")}
+	switch review.Action {
+	case shared.ReviewStage:
+		review.Stage = r.FormValue("stage")
+		if review.Stage == "" {
+			return shared.Review{}, shared.ErrStage
+		}
+	case shared.ReviewRating:
+		stars, err := strconv.Atoi(r.FormValue("stars"))
+		if err != nil || stars < 0 || stars > shared.MaxStars {
+			return shared.Review{}, shared.ErrRating
+		}
+		review.Stars = shared.IntToInt32(stars)
+	case shared.ReviewNote:
+		review.Body = strings.TrimSpace(r.FormValue("body"))
+		if review.Body == "" || len([]rune(review.Body)) > shared.MaxNoteLength {
+			return shared.Review{}, shared.ErrNote
+		}
+	case shared.ReviewTag, shared.ReviewUntag:
+		tag, err := shared.ValidateApplicantTag(r.FormValue("tag"))
+		if err != nil {
+			return shared.Review{}, err
+		}
+		review.Tag = tag
+	default:
+		return shared.Review{}, shared.ErrReviewAction
+	}
+	return review, nil
+}
+
+func GetReviewApplicantInput(r *http.Request) (ReviewApplicantInput, error) {
+	participationID := chi.URLParam(r, "participationID")
+	if err := shared.ValidateUUID(participationID); err != nil {
+		return ReviewApplicantInput{}, err
+	}
+	review, err := getReview(r)
+	if err != nil {
+		return ReviewApplicantInput{}, err
+	}
+	return ReviewApplicantInput{ParticipationID: participationID, Review: review}, nil
+}
+
+func GetBulkReviewInput(r *http.Request) (BulkReviewInput, error) {
+	offerID := chi.URLParam(r, "offerID")
+	if err := shared.ValidateUUID(offerID); err != nil {
+		return BulkReviewInput{}, err
+	}
+	// FormValue parses the form the ids are read from
+	action := r.FormValue("action")
+	participationIDs := r.Form["participationID"]
+	if len(participationIDs) == 0 {
+		return BulkReviewInput{}, shared.ErrNoApplicants
+	}
+	if len(participationIDs) > shared.MaxBulkApplicants {
+		return BulkReviewInput{}, shared.ErrManyApplicants
+	}
+	for _, id := range participationIDs {
+		if err := shared.ValidateUUID(id); err != nil {
+			return BulkReviewInput{}, err
+		}
+	}
+	if _, ok := shared.BulkReviewActions[action]; !ok {
+		return BulkReviewInput{}, shared.ErrReviewAction
+	}
+	review, err := getReview(r)
+	if err != nil {
+		return BulkReviewInput{}, err
+	}
+	return BulkReviewInput{
+		OfferID:          offerID,
+		ParticipationIDs: participationIDs,
+		Review:           review,
+	}, nil
+}
+
+// notifyStage tells the applicants moved to another stage, the storage
+// skips the companies that don't share it
+func notifyStage(ctx context.Context, notifier shared.Notifier, participationIDs []string, review shared.Review) {
+	if review.Action != shared.ReviewStage {
+		return
+	}
+	for _, participationID := range participationIDs {
+		shared.Notify(ctx, notifier, shared.NotificationEvent{
+			Category:        shared.NotifyStatus,
+			ParticipationID: participationID,
+			Stage:           review.Stage,
+		})
+	}
+}
+
+type reviewApplicantInputFn func(r *http.Request) (ReviewApplicantInput, error)
+
+func CreateReviewApplicantHandler(
+	inputFn reviewApplicantInputFn,
+	authService shared.AuthRep,
+	storage ReviewApplicantStorage,
+	templ shared.TemplatesRepo,
+	notifier shared.Notifier,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = storage.ReviewApplicants(r.Context(), user.ID, []string{input.ParticipationID}, input.Review)
+		if shared.IsReviewError(err) {
+			w.Header().Set("HX-Retarget", "#review-alert-"+input.ParticipationID)
+			w.Header().Set("HX-Reswap", "innerHTML")
+			if err := templ.Render(w, "reviewAlert", shared.Alert{Ok: false, Msg: err.Error()}); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		notifyStage(r.Context(), notifier, []string{input.ParticipationID}, input.Review)
+		pipeline, err := storage.SelectPipeline(r.Context(), input.ParticipationID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		pipeline.Alert = shared.Alert{Ok: true, Msg: shared.MsgSaved}
+		// moving an applicant to the reveal stage of a blind review shows who
+		// they are, the card is only rendered by the page
+		reveal := pipeline.Reveal
+		if input.Review.Action == shared.ReviewStage && reveal.Reason == shared.RevealReasonStage &&
+			reveal.Stage == input.Review.Stage {
+			w.Header().Set("HX-Refresh", "true")
+		}
+		if err := templ.Render(w, "applicantPipeline", pipeline); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type bulkReviewInputFn func(r *http.Request) (BulkReviewInput, error)
+
+func CreateBulkReviewHandler(
+	inputFn bulkReviewInputFn,
+	authService shared.AuthRep,
+	storage ReviewApplicantsStorage,
+	templ shared.TemplatesRepo,
+	notifier shared.Notifier,
+	redirPath string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		// an empty selection is shown like the rule violations
+		input, err := inputFn(r)
+		if err == nil {
+			err = storage.ReviewApplicants(r.Context(), user.ID, input.ParticipationIDs, input.Review)
+		}
+		if shared.IsReviewError(err) {
+			if err := templ.Render(w, "reviewAlert", shared.Alert{Ok: false, Msg: err.Error()}); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		notifyStage(r.Context(), notifier, input.ParticipationIDs, input.Review)
+		w.Header().Add("HX-Redirect", redirPath+input.OfferID)
+	}
+}
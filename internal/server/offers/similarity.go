@@ -0,0 +1,106 @@
+package offers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/kw3a/spotted-server/internal/server/similarity"
+)
+
+type SimilarityStorage interface {
+	SelectOfferByUser(ctx context.Context, id string, userID string) (shared.Offer, error)
+	SelectQuizByOffer(ctx context.Context, offerID string) (shared.Quiz, error)
+	SelectApplications(ctx context.Context, quizID string) ([]shared.Application, error)
+	SelectPriorSubmissions(ctx context.Context, quizID string) ([]shared.SimilarityEntry, error)
+	SelectBlindReview(ctx context.Context, offerID string) (shared.BlindReview, error)
+}
+
+type SimilarityInput struct {
+	OfferID string
+	Prior   bool
+}
+
+type SimilarityData struct {
+	OfferID string
+	Prior   bool
+	Pairs   []shared.SimilarityPair
+}
+
+func GetSimilarityInput(r *http.Request) (SimilarityInput, error) {
+	offerID := chi.URLParam(r, "offerID")
+	if err := shared.ValidateUUID(offerID); err != nil {
+		return SimilarityInput{}, err
+	}
+	return SimilarityInput{
+		OfferID: offerID,
+		Prior:   r.URL.Query().Get("prior") == "true",
+	}, nil
+}
+
+type similarityInputFn func(r *http.Request) (SimilarityInput, error)
+
+func CreateSimilarityHandler(
+	inputFn similarityInputFn,
+	authService shared.AuthRep,
+	storage SimilarityStorage,
+	templ shared.TemplatesRepo,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if user.Role == auth.NotAuthRole {
+			http.Error(w, errNotAuthorized, http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		offer, err := storage.SelectOfferByUser(r.Context(), input.OfferID, user.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		quiz, err := storage.SelectQuizByOffer(r.Context(), offer.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		applications, err := storage.SelectApplications(r.Context(), quiz.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		prior := []shared.SimilarityEntry{}
+		if input.Prior {
+			prior, err = storage.SelectPriorSubmissions(r.Context(), quiz.ID)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+		}
+		blind, err := storage.SelectBlindReview(r.Context(), offer.ID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// pairs are detected by applicant, they are hidden afterwards
+		pairs := similarity.Detect(similarity.Entries(applications), prior)
+		blind.HidePairs(pairs, applications)
+		data := SimilarityData{
+			OfferID: offer.ID,
+			Prior:   input.Prior,
+			Pairs:   pairs,
+		}
+		if err := templ.Render(w, "similarity", data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -31,6 +32,15 @@ func (i invalidTemplates) Render(w io.Writer, name string, data interface{}) err
 	return errors.New("error")
 }
 
+type templatesMock struct {
+	mock.Mock
+}
+
+func (t *templatesMock) Render(w io.Writer, name string, data interface{}) error {
+	args := t.Called(w, name, data)
+	return args.Error(0)
+}
+
 type authMock struct {
 	mock.Mock
 }
@@ -56,3 +66,21 @@ func WithUrlParams(r *http.Request, params Params) *http.Request {
 	}
 	return req
 }
+
+type webhookEmitter struct {
+	mock.Mock
+}
+
+func (e *webhookEmitter) EmitWebhookEvent(ctx context.Context, event shared.WebhookEvent) error {
+	args := e.Called(ctx, event)
+	return args.Error(0)
+}
+
+type notifier struct {
+	mock.Mock
+}
+
+func (n *notifier) Notify(ctx context.Context, event shared.NotificationEvent) error {
+	args := n.Called(ctx, event)
+	return args.Error(0)
+}
@@ -0,0 +1,193 @@
+package offerstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type adjustStorage struct {
+	mock.Mock
+}
+
+func (s *adjustStorage) AdjustParticipation(ctx context.Context, recruiterID, participationID string, adjustment shared.Adjustment) (shared.Participation, error) {
+	args := s.Called(ctx, recruiterID, participationID, adjustment)
+	return args.Get(0).(shared.Participation), args.Error(1)
+}
+func (s *adjustStorage) SelectAdjustments(ctx context.Context, participationID string) ([]shared.Adjustment, error) {
+	args := s.Called(ctx, participationID)
+	return args.Get(0).([]shared.Adjustment), args.Error(1)
+}
+
+type deadlineNotifier struct {
+	mock.Mock
+}
+
+func (n *deadlineNotifier) Publish(participationID, reason string) {
+	n.Called(participationID, reason)
+}
+
+func adjustInputFn(r *http.Request) (offers.AdjustParticipationInput, error) {
+	return offers.AdjustParticipationInput{
+		ParticipationID: "part-id",
+		Adjustment:      shared.Adjustment{Action: shared.AdjustmentExtend, Minutes: 10},
+	}, nil
+}
+
+func TestGetAdjustParticipationInput(t *testing.T) {
+	participationID := "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{
+		"action":  {shared.AdjustmentExtend},
+		"minutes": {"15"},
+		"reason":  {"corte de luz"},
+	}
+	req = WithUrlParam(req, "participationID", participationID)
+	input, err := offers.GetAdjustParticipationInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if input.Adjustment.Minutes != 15 || input.Adjustment.Reason != "corte de luz" {
+		t.Errorf("unexpected adjustment %v", input.Adjustment)
+	}
+}
+
+func TestGetAdjustParticipationInputBadAction(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{"action": {"skip"}}
+	req = WithUrlParam(req, "participationID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	if _, err := offers.GetAdjustParticipationInput(req); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestGetAdjustParticipationInputBadMinutes(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{"action": {shared.AdjustmentExtend}, "minutes": {"9999"}}
+	req = WithUrlParam(req, "participationID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	if _, err := offers.GetAdjustParticipationInput(req); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestAdjustParticipationBadAuth(t *testing.T) {
+	handler := offers.CreateAdjustParticipationHandler(adjustInputFn, invalidAuthRepo{}, new(adjustStorage), new(deadlineNotifier), &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestAdjustParticipationVisitor(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{Role: auth.NotAuthRole}, nil)
+	handler := offers.CreateAdjustParticipationHandler(adjustInputFn, authz, new(adjustStorage), new(deadlineNotifier), &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestAdjustParticipationBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (offers.AdjustParticipationInput, error) {
+		return offers.AdjustParticipationInput{}, errors.New("error")
+	}
+	handler := offers.CreateAdjustParticipationHandler(invalidInputFn, authRepo{}, new(adjustStorage), new(deadlineNotifier), &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestAdjustParticipationRuleViolation(t *testing.T) {
+	storage := new(adjustStorage)
+	storage.On("AdjustParticipation", mock.Anything, mock.Anything, "part-id", mock.Anything).
+		Return(shared.Participation{}, shared.ErrParticipationFinished)
+	notifier := new(deadlineNotifier)
+	handler := offers.CreateAdjustParticipationHandler(adjustInputFn, authRepo{}, storage, notifier, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("HX-Retarget") != "#adjust-alert-part-id" {
+		t.Errorf("unexpected retarget %s", w.Header().Get("HX-Retarget"))
+	}
+	notifier.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+}
+
+func TestAdjustParticipationBadStorage(t *testing.T) {
+	storage := new(adjustStorage)
+	storage.On("AdjustParticipation", mock.Anything, mock.Anything, "part-id", mock.Anything).
+		Return(shared.Participation{}, errors.New("error"))
+	handler := offers.CreateAdjustParticipationHandler(adjustInputFn, authRepo{}, storage, new(deadlineNotifier), &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestAdjustParticipationBadStorageAdjustments(t *testing.T) {
+	storage := new(adjustStorage)
+	storage.On("AdjustParticipation", mock.Anything, mock.Anything, "part-id", mock.Anything).
+		Return(shared.Participation{ID: "part-id"}, nil)
+	storage.On("SelectAdjustments", mock.Anything, "part-id").Return([]shared.Adjustment{}, errors.New("error"))
+	notifier := new(deadlineNotifier)
+	notifier.On("Publish", "part-id", shared.DeadlineAdjusted).Return()
+	handler := offers.CreateAdjustParticipationHandler(adjustInputFn, authRepo{}, storage, notifier, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestAdjustParticipationBadTemplate(t *testing.T) {
+	storage := new(adjustStorage)
+	storage.On("AdjustParticipation", mock.Anything, mock.Anything, "part-id", mock.Anything).
+		Return(shared.Participation{ID: "part-id"}, nil)
+	storage.On("SelectAdjustments", mock.Anything, "part-id").Return([]shared.Adjustment{}, nil)
+	notifier := new(deadlineNotifier)
+	notifier.On("Publish", "part-id", shared.DeadlineAdjusted).Return()
+	handler := offers.CreateAdjustParticipationHandler(adjustInputFn, authRepo{}, storage, notifier, &invalidTemplates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestAdjustParticipationHandler(t *testing.T) {
+	storage := new(adjustStorage)
+	storage.On("AdjustParticipation", mock.Anything, mock.Anything, "part-id", mock.Anything).
+		Return(shared.Participation{ID: "part-id"}, nil)
+	storage.On("SelectAdjustments", mock.Anything, "part-id").Return([]shared.Adjustment{}, nil)
+	notifier := new(deadlineNotifier)
+	notifier.On("Publish", "part-id", shared.DeadlineAdjusted).Return()
+	handler := offers.CreateAdjustParticipationHandler(adjustInputFn, authRepo{}, storage, notifier, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	notifier.AssertExpectations(t)
+}
@@ -36,6 +36,21 @@ func (s *applicantsStorage) SelectFullProblems(ctx context.Context, quizID strin
 	return args.Get(0).([]shared.Problem), args.Error(1)
 }
 
+func (s *applicantsStorage) SelectProctoringRules(ctx context.Context, quizID string) ([]shared.ProctoringRule, error) {
+	args := s.Called(ctx, quizID)
+	return args.Get(0).([]shared.ProctoringRule), args.Error(1)
+}
+
+func (s *applicantsStorage) SelectOfferStages(ctx context.Context, offerID string) ([]shared.Stage, error) {
+	args := s.Called(ctx, offerID)
+	return args.Get(0).([]shared.Stage), args.Error(1)
+}
+
+func (s *applicantsStorage) SelectBlindReview(ctx context.Context, offerID string) (shared.BlindReview, error) {
+	args := s.Called(ctx, offerID)
+	return args.Get(0).(shared.BlindReview), args.Error(1)
+}
+
 func applicantsInputFn(r *http.Request) (offers.ApplicantsInput, error) {
 	return offers.ApplicantsInput{}, nil
 }
@@ -135,6 +150,24 @@ func TestApplicantsHandlerBadStorageSelectProblems(t *testing.T) {
 	}
 }
 
+func TestApplicantsHandlerBadStorageSelectStages(t *testing.T) {
+	storage := new(applicantsStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
+	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
+	storage.On("SelectApplications", mock.Anything, mock.Anything).Return([]shared.Application{}, nil)
+	storage.On("SelectFullProblems", mock.Anything, mock.Anything).Return([]shared.Problem{}, nil)
+	storage.On("SelectProctoringRules", mock.Anything, mock.Anything).Return([]shared.ProctoringRule{}, nil)
+	storage.On("SelectOfferStages", mock.Anything, mock.Anything).Return([]shared.Stage{}, errors.New("error"))
+	handler := offers.CreateApplicantsHandler(applicantsInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
 func TestApplicantsHandlerBadTemplate(t *testing.T) {
 	storage := new(applicantsStorage)
 	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, nil)
@@ -142,6 +175,9 @@ func TestApplicantsHandlerBadTemplate(t *testing.T) {
 	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
 	storage.On("SelectApplications", mock.Anything, mock.Anything).Return([]shared.Application{}, nil)
 	storage.On("SelectFullProblems", mock.Anything, mock.Anything).Return([]shared.Problem{}, nil)
+	storage.On("SelectProctoringRules", mock.Anything, mock.Anything).Return([]shared.ProctoringRule{}, nil)
+	storage.On("SelectOfferStages", mock.Anything, mock.Anything).Return(shared.DefaultStages, nil)
+	storage.On("SelectBlindReview", mock.Anything, mock.Anything).Return(shared.BlindReview{}, nil)
 	handler := offers.CreateApplicantsHandler(applicantsInputFn, authRepo{}, storage, &invalidTemplates{})
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
@@ -158,6 +194,9 @@ func TestApplicantsHandler(t *testing.T) {
 	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
 	storage.On("SelectApplications", mock.Anything, mock.Anything).Return([]shared.Application{}, nil)
 	storage.On("SelectFullProblems", mock.Anything, mock.Anything).Return([]shared.Problem{}, nil)
+	storage.On("SelectProctoringRules", mock.Anything, mock.Anything).Return([]shared.ProctoringRule{}, nil)
+	storage.On("SelectOfferStages", mock.Anything, mock.Anything).Return(shared.DefaultStages, nil)
+	storage.On("SelectBlindReview", mock.Anything, mock.Anything).Return(shared.BlindReview{}, nil)
 	handler := offers.CreateApplicantsHandler(applicantsInputFn, authRepo{}, storage, &templates{})
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
@@ -166,3 +205,60 @@ func TestApplicantsHandler(t *testing.T) {
 		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
 	}
 }
+
+func TestGetApplicantsInputStage(t *testing.T) {
+	offerID := "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	req, _ := http.NewRequest("GET", "/?stage=interview", nil)
+	req = WithUrlParam(req, "offerID", offerID)
+	input, err := offers.GetApplicantsInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if input.Stage != shared.StageInterview {
+		t.Errorf("unexpected stage %q", input.Stage)
+	}
+	req, _ = http.NewRequest("GET", "/?stage=Entrevista%20final", nil)
+	req = WithUrlParam(req, "offerID", offerID)
+	if _, err := offers.GetApplicantsInput(req); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestApplicantsHandlerStageFilter(t *testing.T) {
+	applications := []shared.Application{
+		{Participation: shared.Participation{ID: "new-id"}},
+		{
+			Participation: shared.Participation{ID: "hired-id"},
+			Pipeline:      shared.Pipeline{Stage: shared.StageHired},
+		},
+	}
+	storage := new(applicantsStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
+	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
+	storage.On("SelectApplications", mock.Anything, mock.Anything).Return(applications, nil)
+	storage.On("SelectFullProblems", mock.Anything, mock.Anything).Return([]shared.Problem{}, nil)
+	storage.On("SelectProctoringRules", mock.Anything, mock.Anything).Return([]shared.ProctoringRule{}, nil)
+	storage.On("SelectOfferStages", mock.Anything, mock.Anything).Return(shared.DefaultStages, nil)
+	storage.On("SelectBlindReview", mock.Anything, mock.Anything).Return(shared.BlindReview{}, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "offerAdmin", mock.MatchedBy(func(data offers.ApplicantsData) bool {
+		if len(data.Applicants) != 1 || data.Applicants[0].Participation.ID != "new-id" {
+			return false
+		}
+		// the counts cover every applicant, not only the filtered ones
+		return data.StageCounts[0].Count == 1 && data.StageCounts[0].Selected &&
+			data.StageCounts[4].Count == 1
+	})).Return(nil)
+	inputFn := func(r *http.Request) (offers.ApplicantsInput, error) {
+		return offers.ApplicantsInput{Stage: shared.StageNew}, nil
+	}
+	handler := offers.CreateApplicantsHandler(inputFn, authRepo{}, storage, templ)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
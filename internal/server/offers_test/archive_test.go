@@ -3,12 +3,16 @@ package offerstest
 import (
 	"context"
 	"errors"
+	"io"
+	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"testing"
 
 	"github.com/kw3a/spotted-server/internal/auth"
 	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -22,12 +26,12 @@ func (s *archiveStorage) ArchiveOffer(ctx context.Context, offerID string, owner
 }
 
 func archiveInputFn(r *http.Request) (offers.OfferArchiveInput, error) {
-	return offers.OfferArchiveInput{}, nil
+	return offers.OfferArchiveInput{OfferID: "offer-id"}, nil
 }
 
 func TestArchiveBadAuth(t *testing.T) {
 	storage := new(archiveStorage)
-	handler := offers.CreateArchiveHandler(archiveInputFn, invalidAuthRepo{}, storage)
+	handler := offers.CreateArchiveHandler(archiveInputFn, invalidAuthRepo{}, storage, new(webhookEmitter), new(notifier))
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -40,7 +44,7 @@ func TestArchiveVisitor(t *testing.T) {
 	authz := new(authMock)
 	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{Role: "visitor"}, nil)
 	storage := new(archiveStorage)
-	handler := offers.CreateArchiveHandler(archiveInputFn, authz, storage)
+	handler := offers.CreateArchiveHandler(archiveInputFn, authz, storage, new(webhookEmitter), new(notifier))
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -54,7 +58,7 @@ func TestArchiveBadInput(t *testing.T) {
 		return offers.OfferArchiveInput{}, errors.New("error")
 	}
 	storage := new(archiveStorage)
-	handler := offers.CreateArchiveHandler(invalidInputFn, authRepo{}, storage)
+	handler := offers.CreateArchiveHandler(invalidInputFn, authRepo{}, storage, new(webhookEmitter), new(notifier))
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -66,7 +70,7 @@ func TestArchiveBadInput(t *testing.T) {
 func TestArchiveBadStorageArchiveOffer(t *testing.T) {
 	storage := new(archiveStorage)
 	storage.On("ArchiveOffer", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error"))
-	handler := offers.CreateArchiveHandler(archiveInputFn, authRepo{}, storage)
+	handler := offers.CreateArchiveHandler(archiveInputFn, authRepo{}, storage, new(webhookEmitter), new(notifier))
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -78,11 +82,41 @@ func TestArchiveBadStorageArchiveOffer(t *testing.T) {
 func TestArchiveHandler(t *testing.T) {
 	storage := new(archiveStorage)
 	storage.On("ArchiveOffer", mock.Anything, mock.Anything, mock.Anything).Return(nil)
-	handler := offers.CreateArchiveHandler(archiveInputFn, authRepo{}, storage)
+	events := new(webhookEmitter)
+	events.On("EmitWebhookEvent", mock.Anything, shared.WebhookEvent{
+		Type:    shared.EventOfferArchived,
+		OfferID: "offer-id",
+	}).Return(nil)
+	notifications := new(notifier)
+	notifications.On("Notify", mock.Anything, shared.NotificationEvent{
+		Category: shared.NotifyArchived,
+		OfferID:  "offer-id",
+	}).Return(nil)
+	handler := offers.CreateArchiveHandler(archiveInputFn, authRepo{}, storage, events, notifications)
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
 	if w.Code != http.StatusOK {
 		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
 	}
+	events.AssertExpectations(t)
+	notifications.AssertExpectations(t)
+}
+
+func TestArchiveHandlerBadWebhooks(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	storage := new(archiveStorage)
+	storage.On("ArchiveOffer", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	events := new(webhookEmitter)
+	events.On("EmitWebhookEvent", mock.Anything, mock.Anything).Return(errors.New("error"))
+	notifications := new(notifier)
+	notifications.On("Notify", mock.Anything, mock.Anything).Return(errors.New("error"))
+	handler := offers.CreateArchiveHandler(archiveInputFn, authRepo{}, storage, events, notifications)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected the offer to be archived anyway, got %d", w.Code)
+	}
 }
@@ -0,0 +1,88 @@
+package offerstest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type cldMock struct {
+	mock.Mock
+}
+
+func (c *cldMock) Upload(ctx context.Context, file interface{}, uploadParams uploader.UploadParams) (*uploader.UploadResult, error) {
+	args := c.Called(ctx, file, uploadParams)
+	return args.Get(0).(*uploader.UploadResult), args.Error(1)
+}
+
+func attachmentRequest(filename string) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile("file", filename)
+	_, _ = part.Write([]byte("contenido"))
+	_ = writer.Close()
+	req, _ := http.NewRequest("POST", "/attachments", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestGetAttachmentInput(t *testing.T) {
+	cld := new(cldMock)
+	cld.On("Upload", mock.Anything, mock.Anything, mock.Anything).Return(&uploader.UploadResult{SecureURL: "https://cdn.io/grafo.png"}, nil)
+	input, err := offers.GetAttachmentInput(attachmentRequest("Grafo.PNG"), cld)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !input.Image || input.Markdown() != "![Grafo.PNG](https://cdn.io/grafo.png)" {
+		t.Errorf("unexpected attachment %+v", input)
+	}
+	input.Image = false
+	if input.Markdown() != "[Grafo.PNG](https://cdn.io/grafo.png)" {
+		t.Errorf("unexpected link %q", input.Markdown())
+	}
+}
+
+func TestGetAttachmentInputBadType(t *testing.T) {
+	cld := new(cldMock)
+	if _, err := offers.GetAttachmentInput(attachmentRequest("virus.exe"), cld); err == nil {
+		t.Error("expected error for an executable")
+	}
+	cld.AssertNotCalled(t, "Upload", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAttachmentHandlerBadInput(t *testing.T) {
+	inputFn := func(r *http.Request, cloudinaryService shared.CloudinaryService) (offers.AttachmentInput, error) {
+		return offers.AttachmentInput{}, errors.New("error")
+	}
+	handler := offers.CreateAttachmentHandler(inputFn, authRepo{}, new(cldMock), &templates{})
+	w := httptest.NewRecorder()
+	handler(w, attachmentRequest("a.png"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestAttachmentHandler(t *testing.T) {
+	attachment := offers.AttachmentInput{Name: "datos.csv", URL: "https://cdn.io/datos.csv"}
+	inputFn := func(r *http.Request, cloudinaryService shared.CloudinaryService) (offers.AttachmentInput, error) {
+		return attachment, nil
+	}
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "attachmentSnippet", attachment).Return(nil)
+	handler := offers.CreateAttachmentHandler(inputFn, authRepo{}, new(cldMock), templ)
+	w := httptest.NewRecorder()
+	handler(w, attachmentRequest("datos.csv"))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
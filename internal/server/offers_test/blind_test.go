@@ -0,0 +1,206 @@
+package offerstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type blindReviewStorage struct {
+	mock.Mock
+}
+
+func (s *blindReviewStorage) SelectOfferByUser(ctx context.Context, id string, userID string) (shared.Offer, error) {
+	args := s.Called(ctx, id, userID)
+	return args.Get(0).(shared.Offer), args.Error(1)
+}
+
+func (s *blindReviewStorage) SelectBlindReview(ctx context.Context, offerID string) (shared.BlindReview, error) {
+	args := s.Called(ctx, offerID)
+	return args.Get(0).(shared.BlindReview), args.Error(1)
+}
+
+func (s *blindReviewStorage) UpdateBlindReview(ctx context.Context, recruiterID string, review shared.BlindReview) error {
+	args := s.Called(ctx, recruiterID, review)
+	return args.Error(0)
+}
+
+func blindReviewInputFn(r *http.Request) (offers.BlindReviewInput, error) {
+	return offers.BlindReviewInput{OfferID: "offer-id", Enabled: true, RevealStage: shared.StageInterview}, nil
+}
+
+func TestGetBlindReviewInput(t *testing.T) {
+	offerID := "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{"enabled": {"on"}, "revealStage": {"interview"}}
+	req = WithUrlParam(req, "offerID", offerID)
+	input, err := offers.GetBlindReviewInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !input.Enabled || input.RevealStage != shared.StageInterview {
+		t.Errorf("unexpected input %v", input)
+	}
+
+	req, _ = http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{"revealStage": {"interview"}}
+	req = WithUrlParam(req, "offerID", offerID)
+	input, err = offers.GetBlindReviewInput(req)
+	if err != nil || input.Enabled || input.RevealStage != "" {
+		t.Errorf("expected blind review disabled, got %v %v", input, err)
+	}
+
+	req, _ = http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{"enabled": {"on"}}
+	req = WithUrlParam(req, "offerID", offerID)
+	if _, err := offers.GetBlindReviewInput(req); !errors.Is(err, shared.ErrStage) {
+		t.Errorf("expected %v, got %v", shared.ErrStage, err)
+	}
+}
+
+func TestBlindReviewBadAuth(t *testing.T) {
+	handler := offers.CreateBlindReviewHandler(blindReviewInputFn, invalidAuthRepo{}, new(blindReviewStorage), &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestBlindReviewBadInput(t *testing.T) {
+	inputFn := func(r *http.Request) (offers.BlindReviewInput, error) {
+		return offers.BlindReviewInput{}, errors.New("error")
+	}
+	handler := offers.CreateBlindReviewHandler(inputFn, authRepo{}, new(blindReviewStorage), &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestBlindReviewForeignOffer(t *testing.T) {
+	storage := new(blindReviewStorage)
+	storage.On("SelectOfferByUser", mock.Anything, "offer-id", mock.Anything).Return(shared.Offer{}, errors.New("error"))
+	handler := offers.CreateBlindReviewHandler(blindReviewInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	storage.AssertNotCalled(t, "UpdateBlindReview", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBlindReviewUnknownStage(t *testing.T) {
+	storage := new(blindReviewStorage)
+	storage.On("SelectOfferByUser", mock.Anything, "offer-id", mock.Anything).Return(shared.Offer{ID: "offer-id", MemberRole: shared.RoleOwner}, nil)
+	storage.On("UpdateBlindReview", mock.Anything, mock.Anything, mock.Anything).Return(shared.ErrStage)
+	storage.On("SelectBlindReview", mock.Anything, "offer-id").Return(shared.BlindReview{OfferID: "offer-id"}, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "blindReview", shared.BlindReview{
+		OfferID: "offer-id",
+		Alert:   shared.Alert{Ok: false, Msg: shared.ErrStage.Error()},
+	}).Return(nil)
+	handler := offers.CreateBlindReviewHandler(blindReviewInputFn, authRepo{}, storage, templ)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("HX-Refresh") != "" {
+		t.Error("expected the form to stay with the error")
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestBlindReviewViewer(t *testing.T) {
+	storage := new(blindReviewStorage)
+	storage.On("SelectOfferByUser", mock.Anything, "offer-id", mock.Anything).Return(shared.Offer{ID: "offer-id", MemberRole: shared.RoleViewer}, nil)
+	handler := offers.CreateBlindReviewHandler(blindReviewInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+	storage.AssertNotCalled(t, "UpdateBlindReview", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBlindReviewBadStorage(t *testing.T) {
+	storage := new(blindReviewStorage)
+	storage.On("SelectOfferByUser", mock.Anything, "offer-id", mock.Anything).Return(shared.Offer{ID: "offer-id", MemberRole: shared.RoleOwner}, nil)
+	storage.On("UpdateBlindReview", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error"))
+	handler := offers.CreateBlindReviewHandler(blindReviewInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestBlindReviewHandler(t *testing.T) {
+	storage := new(blindReviewStorage)
+	storage.On("SelectOfferByUser", mock.Anything, "offer-id", mock.Anything).Return(shared.Offer{ID: "offer-id", MemberRole: shared.RoleOwner}, nil)
+	storage.On("UpdateBlindReview", mock.Anything, mock.Anything, shared.BlindReview{
+		OfferID:     "offer-id",
+		Enabled:     true,
+		RevealStage: shared.StageInterview,
+	}).Return(nil)
+	storage.On("SelectBlindReview", mock.Anything, "offer-id").Return(shared.BlindReview{OfferID: "offer-id", Enabled: true}, nil)
+	handler := offers.CreateBlindReviewHandler(blindReviewInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("HX-Refresh") != "true" {
+		t.Error("expected the page to be loaded again")
+	}
+	storage.AssertExpectations(t)
+}
+
+func TestApplicantsHandlerBlindReview(t *testing.T) {
+	applications := []shared.Application{{
+		Applicant:     shared.User{ID: "user-id", Name: "Ana", ImageURL: "/ana.png"},
+		Participation: shared.Participation{ID: "p1"},
+	}}
+	storage := new(applicantsStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
+	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
+	storage.On("SelectApplications", mock.Anything, mock.Anything).Return(applications, nil)
+	storage.On("SelectFullProblems", mock.Anything, mock.Anything).Return([]shared.Problem{}, nil)
+	storage.On("SelectProctoringRules", mock.Anything, mock.Anything).Return([]shared.ProctoringRule{}, nil)
+	storage.On("SelectOfferStages", mock.Anything, mock.Anything).Return(shared.DefaultStages, nil)
+	storage.On("SelectBlindReview", mock.Anything, mock.Anything).Return(shared.BlindReview{
+		OfferID:     "offer-id",
+		Enabled:     true,
+		RevealStage: shared.StageInterview,
+	}, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "offerAdmin", mock.MatchedBy(func(data offers.ApplicantsData) bool {
+		applicant := data.Applicants[0]
+		return applicant.Anonymous && data.BlindReview.Enabled &&
+			applicant.Applicant == shared.User{Name: shared.Pseudonym(nil, "offer-id", "user-id")}
+	})).Return(nil)
+	handler := offers.CreateApplicantsHandler(applicantsInputFn, authRepo{}, storage, templ)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
@@ -0,0 +1,110 @@
+package offerstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type editionPageStorage struct {
+	mock.Mock
+}
+
+func (s *editionPageStorage) SelectOfferByUser(ctx context.Context, ID, userID string) (shared.Offer, error) {
+	args := s.Called(ctx, ID, userID)
+	return args.Get(0).(shared.Offer), args.Error(1)
+}
+
+func (s *editionPageStorage) GetLanguages(ctx context.Context) ([]shared.Language, error) {
+	args := s.Called(ctx)
+	return args.Get(0).([]shared.Language), args.Error(1)
+}
+
+func (s *editionPageStorage) SelectQuizByOffer(ctx context.Context, offerID string) (shared.Quiz, error) {
+	args := s.Called(ctx, offerID)
+	return args.Get(0).(shared.Quiz), args.Error(1)
+}
+
+func (s *editionPageStorage) SelectLanguages(ctx context.Context, quizID string) ([]shared.Language, error) {
+	args := s.Called(ctx, quizID)
+	return args.Get(0).([]shared.Language), args.Error(1)
+}
+
+func (s *editionPageStorage) SelectBankProblems(ctx context.Context, companyID string) ([]shared.BankProblem, error) {
+	args := s.Called(ctx, companyID)
+	return args.Get(0).([]shared.BankProblem), args.Error(1)
+}
+
+func (s *editionPageStorage) SelectQuizBankProblems(ctx context.Context, quizID string) (map[string]int32, error) {
+	args := s.Called(ctx, quizID)
+	return args.Get(0).(map[string]int32), args.Error(1)
+}
+
+func (s *editionPageStorage) CountParticipations(ctx context.Context, quizID string) (int64, error) {
+	args := s.Called(ctx, quizID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func editionPageInputFn(r *http.Request) (offers.OfferEditionPageInput, error) {
+	return offers.OfferEditionPageInput{OfferID: "offer-id"}, nil
+}
+
+func fullEditionPageStorage(offer shared.Offer, participations int64) *editionPageStorage {
+	storage := new(editionPageStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(offer, nil)
+	storage.On("GetLanguages", mock.Anything).Return([]shared.Language{{ID: 62}, {ID: 71}}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{ID: "quiz-id"}, nil)
+	storage.On("SelectLanguages", mock.Anything, "quiz-id").Return([]shared.Language{{ID: 71}}, nil)
+	storage.On("SelectBankProblems", mock.Anything, mock.Anything).Return([]shared.BankProblem{{ID: "bank-id", Version: 3}}, nil)
+	storage.On("SelectQuizBankProblems", mock.Anything, "quiz-id").Return(map[string]int32{"bank-id": 2}, nil)
+	storage.On("CountParticipations", mock.Anything, "quiz-id").Return(participations, nil)
+	return storage
+}
+
+func TestEditionPageBadStorageSelectOffer(t *testing.T) {
+	storage := new(editionPageStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, errors.New("error"))
+	handler := offers.CreateOfferEditionPage(authRepo{}, &templates{}, storage, editionPageInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestEditionPageArchived(t *testing.T) {
+	storage := new(editionPageStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{Status: shared.OfferArchived, MemberRole: shared.RoleOwner}, nil)
+	handler := offers.CreateOfferEditionPage(authRepo{}, &templates{}, storage, editionPageInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestEditionPage(t *testing.T) {
+	for participations, locked := range map[int64]bool{0: false, 3: true} {
+		storage := fullEditionPageStorage(shared.Offer{ID: "offer-id", Status: shared.OfferPublished, MemberRole: shared.RoleOwner}, participations)
+		templ := new(templatesMock)
+		templ.On("Render", mock.Anything, "offerEdition", mock.MatchedBy(func(data offers.OfferEditionPageData) bool {
+			return data.Locked == locked && data.Selected[71] && !data.Selected[62] && data.Pinned["bank-id"] == 2
+		})).Return(nil)
+		handler := offers.CreateOfferEditionPage(authRepo{}, templ, storage, editionPageInputFn)
+		req, _ := http.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		templ.AssertExpectations(t)
+	}
+}
@@ -2,12 +2,16 @@ package offerstest
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/kw3a/spotted-server/internal/auth"
 	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -15,28 +19,61 @@ type editionStorage struct {
 	mock.Mock
 }
 
-func (s *editionStorage) InsertQuiz(
+func (s *editionStorage) EditOffer(
 	ctx context.Context,
-	quizID string,
-	offerID string,
-	languages []int32,
-	duration int32,
+	offerID, ownerID string,
+	offer shared.Offer,
+	quiz *shared.Quiz,
+	bankProblemIDs []string,
 ) error {
-	args := s.Called(ctx, quizID, offerID, languages, duration)
+	args := s.Called(ctx, offerID, ownerID, offer, quiz, bankProblemIDs)
 	return args.Error(0)
 }
 
-func editionInputFn(r *http.Request) (offers.OfferEdition, error) {
-	return offers.OfferEdition{}, nil
+func (s *editionStorage) UpdateOfferStatus(ctx context.Context, offerID, ownerID, status string) error {
+	args := s.Called(ctx, offerID, ownerID, status)
+	return args.Error(0)
+}
+
+func editionInputFn(r *http.Request) (offers.OfferEditInput, error) {
+	return offers.OfferEditInput{OfferID: "offer-id"}, nil
+}
+
+func statusInputFn(r *http.Request) (offers.OfferStatusInput, error) {
+	return offers.OfferStatusInput{OfferID: "offer-id", Status: shared.OfferClosed}, nil
+}
+
+func TestEditionHandlerBadAuth(t *testing.T) {
+	storage := new(editionStorage)
+	handler := offers.CreateOfferEditHandler(editionInputFn, invalidAuthRepo{}, storage, "/offers/admin/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestEditionHandlerVisitor(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{Role: auth.NotAuthRole}, nil)
+	storage := new(editionStorage)
+	handler := offers.CreateOfferEditHandler(editionInputFn, authz, storage, "/offers/admin/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
 }
 
 func TestEditionHandlerBadInput(t *testing.T) {
-	invalidInputFn := func(r *http.Request) (offers.OfferEdition, error) {
-		return offers.OfferEdition{}, fmt.Errorf("error")
+	invalidInputFn := func(r *http.Request) (offers.OfferEditInput, error) {
+		return offers.OfferEditInput{}, fmt.Errorf("error")
 	}
 	storage := new(editionStorage)
-	handler := offers.CreateOfferEdition(storage, invalidInputFn)
-	req, _ := http.NewRequest("GET", "/", nil)
+	handler := offers.CreateOfferEditHandler(invalidInputFn, authRepo{}, storage, "/offers/admin/")
+	req, _ := http.NewRequest("POST", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
 	if w.Code != http.StatusBadRequest {
@@ -44,18 +81,25 @@ func TestEditionHandlerBadInput(t *testing.T) {
 	}
 }
 
-func TestEditionHandlerBadStorageInsertQuiz(t *testing.T) {
+func TestEditionHandlerRejected(t *testing.T) {
+	for _, rejection := range []error{shared.ErrQuizLocked, shared.ErrOfferArchived, shared.ErrPoolTooSmall} {
+		storage := new(editionStorage)
+		storage.On("EditOffer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(rejection)
+		handler := offers.CreateOfferEditHandler(editionInputFn, authRepo{}, storage, "/offers/admin/")
+		req, _ := http.NewRequest("POST", "/", nil)
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%v: expected status %d, got %d", rejection, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+func TestEditionHandlerBadStorage(t *testing.T) {
 	storage := new(editionStorage)
-	storage.On(
-		"InsertQuiz",
-		mock.Anything,
-		mock.Anything,
-		mock.Anything,
-		mock.Anything,
-		mock.Anything,
-	).Return(fmt.Errorf("error"))
-	handler := offers.CreateOfferEdition(storage, editionInputFn)
-	req, _ := http.NewRequest("GET", "/", nil)
+	storage.On("EditOffer", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error"))
+	handler := offers.CreateOfferEditHandler(editionInputFn, authRepo{}, storage, "/offers/admin/")
+	req, _ := http.NewRequest("POST", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
 	if w.Code != http.StatusInternalServerError {
@@ -65,19 +109,150 @@ func TestEditionHandlerBadStorageInsertQuiz(t *testing.T) {
 
 func TestEditionHandler(t *testing.T) {
 	storage := new(editionStorage)
-	storage.On(
-		"InsertQuiz",
-		mock.Anything,
-		mock.Anything,
-		mock.Anything,
-		mock.Anything,
-		mock.Anything,
-	).Return(nil)
-	handler := offers.CreateOfferEdition(storage, editionInputFn)
-	req, _ := http.NewRequest("GET", "/", nil)
+	storage.On("EditOffer", mock.Anything, "offer-id", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	handler := offers.CreateOfferEditHandler(editionInputFn, authRepo{}, storage, "/offers/admin/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if got := w.Header().Get("HX-Redirect"); got != "/offers/admin/offer-id" {
+		t.Errorf("expected redirect to the offer admin, got %q", got)
+	}
+	storage.AssertExpectations(t)
+}
+
+func editionBody(quiz string) string {
+	long := strings.Repeat("a", 200)
+	offer := fmt.Sprintf(`"offer": {"title": "Desarrollador backend", "minWage": "100", "maxWage": "200",
+		"about": "%s", "requirements": "%s", "benefits": "%s"}`, long, long, long)
+	if quiz == "" {
+		return "{" + offer + "}"
+	}
+	return "{" + offer + ", " + quiz + "}"
+}
+
+func TestGetOfferEditInput(t *testing.T) {
+	offerID := "00000000-0000-0000-0000-000000000001"
+	bankID := "00000000-0000-0000-0000-000000000002"
+	cases := []struct {
+		name      string
+		body      string
+		expectErr bool
+		withQuiz  bool
+	}{
+		{"offer only", editionBody(""), false, false},
+		{"quiz", editionBody(`"quiz": {"duration": "60", "languages": ["62"]}, "bank": ["` + bankID + `"]`), false, true},
+		{"quiz without problems", editionBody(`"quiz": {"duration": "60", "languages": ["62"]}`), true, false},
+		{"bad duration", editionBody(`"quiz": {"duration": "5", "languages": ["62"]}, "bank": ["` + bankID + `"]`), true, false},
+		{"bad bank problem", editionBody(`"quiz": {"duration": "60", "languages": ["62"]}, "bank": ["x"]`), true, false},
+		{"short title", `{"offer": {"title": "corto"}}`, true, false},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest("POST", "/", strings.NewReader(c.body))
+		req = WithUrlParam(req, "offerID", offerID)
+		input, err := offers.GetOfferEditInput(req)
+		if c.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected error", c.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+			continue
+		}
+		if input.OfferID != offerID {
+			t.Errorf("%s: expected offer %s, got %s", c.name, offerID, input.OfferID)
+		}
+		if (input.Quiz != nil) != c.withQuiz {
+			t.Errorf("%s: expected quiz %v, got %v", c.name, c.withQuiz, input.Quiz)
+		}
+	}
+}
+
+func TestOfferStatusHandlerVisitor(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{Role: auth.NotAuthRole}, nil)
+	storage := new(editionStorage)
+	handler := offers.CreateOfferStatusHandler(statusInputFn, authz, storage, new(webhookEmitter), new(notifier), "/offers/admin")
+	req, _ := http.NewRequest("PATCH", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestOfferStatusHandlerTransition(t *testing.T) {
+	storage := new(editionStorage)
+	storage.On("UpdateOfferStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(shared.ErrOfferTransition)
+	handler := offers.CreateOfferStatusHandler(statusInputFn, authRepo{}, storage, new(webhookEmitter), new(notifier), "/offers/admin")
+	req, _ := http.NewRequest("PATCH", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestOfferStatusHandler(t *testing.T) {
+	storage := new(editionStorage)
+	storage.On("UpdateOfferStatus", mock.Anything, "offer-id", mock.Anything, shared.OfferClosed).Return(nil)
+	handler := offers.CreateOfferStatusHandler(statusInputFn, authRepo{}, storage, new(webhookEmitter), new(notifier), "/offers/admin")
+	req, _ := http.NewRequest("PATCH", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
 	if w.Code != http.StatusOK {
 		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
 	}
+	storage.AssertExpectations(t)
+}
+
+func TestOfferStatusHandlerArchived(t *testing.T) {
+	inputFn := func(r *http.Request) (offers.OfferStatusInput, error) {
+		return offers.OfferStatusInput{OfferID: "offer-id", Status: shared.OfferArchived}, nil
+	}
+	storage := new(editionStorage)
+	storage.On("UpdateOfferStatus", mock.Anything, "offer-id", mock.Anything, shared.OfferArchived).Return(nil)
+	events := new(webhookEmitter)
+	events.On("EmitWebhookEvent", mock.Anything, shared.WebhookEvent{
+		Type:    shared.EventOfferArchived,
+		OfferID: "offer-id",
+	}).Return(nil)
+	notifications := new(notifier)
+	notifications.On("Notify", mock.Anything, shared.NotificationEvent{
+		Category: shared.NotifyArchived,
+		OfferID:  "offer-id",
+	}).Return(nil)
+	handler := offers.CreateOfferStatusHandler(inputFn, authRepo{}, storage, events, notifications, "/offers/admin")
+	req, _ := http.NewRequest("PATCH", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	events.AssertExpectations(t)
+	notifications.AssertExpectations(t)
+}
+
+func TestGetOfferStatusInput(t *testing.T) {
+	offerID := "00000000-0000-0000-0000-000000000001"
+	for status, valid := range map[string]bool{
+		shared.OfferPublished: true,
+		shared.OfferArchived:  true,
+		"deleted":             false,
+		"":                    false,
+	} {
+		req, _ := http.NewRequest("PATCH", "/?status="+status, nil)
+		req = WithUrlParam(req, "offerID", offerID)
+		_, err := offers.GetOfferStatusInput(req)
+		if valid && err != nil {
+			t.Errorf("%q: unexpected error %v", status, err)
+		}
+		if !valid && err == nil {
+			t.Errorf("%q: expected error", status)
+		}
+	}
 }
@@ -0,0 +1,240 @@
+package offerstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type exportStorage struct {
+	mock.Mock
+}
+
+func (s *exportStorage) SelectOfferByUser(ctx context.Context, id string, userID string) (shared.Offer, error) {
+	args := s.Called(ctx, id, userID)
+	return args.Get(0).(shared.Offer), args.Error(1)
+}
+
+func (s *exportStorage) SelectQuizByOffer(ctx context.Context, offerID string) (shared.Quiz, error) {
+	args := s.Called(ctx, offerID)
+	return args.Get(0).(shared.Quiz), args.Error(1)
+}
+
+func (s *exportStorage) SelectApplications(ctx context.Context, quizID string) ([]shared.Application, error) {
+	args := s.Called(ctx, quizID)
+	return args.Get(0).([]shared.Application), args.Error(1)
+}
+
+func (s *exportStorage) SelectOfferStages(ctx context.Context, offerID string) ([]shared.Stage, error) {
+	args := s.Called(ctx, offerID)
+	return args.Get(0).([]shared.Stage), args.Error(1)
+}
+
+func (s *exportStorage) SelectBlindReview(ctx context.Context, offerID string) (shared.BlindReview, error) {
+	args := s.Called(ctx, offerID)
+	return args.Get(0).(shared.BlindReview), args.Error(1)
+}
+
+func (s *exportStorage) SelectStrokeWindows(ctx context.Context, participationID string) ([]shared.StrokeWindow, error) {
+	args := s.Called(ctx, participationID)
+	return args.Get(0).([]shared.StrokeWindow), args.Error(1)
+}
+
+const (
+	exportOfferID         = "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	exportParticipationID = "3b241101-e2bb-4255-8caf-4136c566a962"
+)
+
+func exportApplications() []shared.Application {
+	return []shared.Application{
+		{
+			Applicant:     shared.User{Name: "Ana"},
+			Participation: shared.Participation{ID: "p1"},
+			Pipeline:      shared.Pipeline{Stage: shared.StageNew},
+		},
+		{
+			Applicant:     shared.User{Name: "=Beto"},
+			Participation: shared.Participation{ID: exportParticipationID, EndReason: shared.EndReasonManual},
+			Pipeline:      shared.Pipeline{Stage: shared.StageInterview, Rating: 4},
+			Summary: []shared.Summary{{
+				Title: "Suma",
+				Score: shared.Score{AcceptedTestCases: 3, TotalTestCases: 4},
+			}},
+		},
+	}
+}
+
+func newExportStorage() *exportStorage {
+	storage := new(exportStorage)
+	storage.On("SelectOfferByUser", mock.Anything, exportOfferID, mock.Anything).Return(shared.Offer{ID: exportOfferID, Title: "Backend"}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, exportOfferID).Return(shared.Quiz{ID: "q"}, nil)
+	storage.On("SelectApplications", mock.Anything, "q").Return(exportApplications(), nil)
+	storage.On("SelectOfferStages", mock.Anything, exportOfferID).Return(shared.DefaultStages, nil)
+	storage.On("SelectBlindReview", mock.Anything, exportOfferID).Return(shared.BlindReview{}, nil)
+	return storage
+}
+
+func TestGetExportInput(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/?format=xlsx&stage=interview", nil)
+	req = WithUrlParam(req, "offerID", exportOfferID)
+	input, err := offers.GetExportInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if input.Format != shared.ExportXLSX || input.Stage != shared.StageInterview {
+		t.Errorf("unexpected input %v", input)
+	}
+}
+
+func TestGetExportInputBadFormat(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/?format=pdf", nil)
+	req = WithUrlParam(req, "offerID", exportOfferID)
+	if _, err := offers.GetExportInput(req); !errors.Is(err, shared.ErrExportFormat) {
+		t.Errorf("expected %v, got %v", shared.ErrExportFormat, err)
+	}
+}
+
+func TestGetExportInputBadStage(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/?format=csv&stage=Mal%20nombre", nil)
+	req = WithUrlParam(req, "offerID", exportOfferID)
+	if _, err := offers.GetExportInput(req); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestGetDossierInputBadParticipation(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/", nil)
+	req = WithUrlParams(req, Params{"offerID": exportOfferID, "participationID": "x"})
+	if _, err := offers.GetDossierInput(req); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestExportHandlerBadAuth(t *testing.T) {
+	inputFn := func(r *http.Request) (offers.ExportInput, error) {
+		return offers.ExportInput{}, nil
+	}
+	handler := offers.CreateExportHandler(inputFn, invalidAuthRepo{}, new(exportStorage))
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestExportHandlerBadInput(t *testing.T) {
+	inputFn := func(r *http.Request) (offers.ExportInput, error) {
+		return offers.ExportInput{}, shared.ErrExportFormat
+	}
+	handler := offers.CreateExportHandler(inputFn, authRepo{}, new(exportStorage))
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestExportHandlerBadStorage(t *testing.T) {
+	inputFn := func(r *http.Request) (offers.ExportInput, error) {
+		return offers.ExportInput{OfferID: exportOfferID, Format: shared.ExportCSV}, nil
+	}
+	storage := new(exportStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, errors.New("error"))
+	handler := offers.CreateExportHandler(inputFn, authRepo{}, storage)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestExportHandlerCSV(t *testing.T) {
+	inputFn := func(r *http.Request) (offers.ExportInput, error) {
+		return offers.ExportInput{OfferID: exportOfferID, Format: shared.ExportCSV}, nil
+	}
+	handler := offers.CreateExportHandler(inputFn, authRepo{}, newExportStorage())
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if !strings.HasPrefix(w.Header().Get("Content-Type"), "text/csv") {
+		t.Errorf("unexpected content type %s", w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(w.Header().Get("Content-Disposition"), "postulantes-"+exportOfferID+".csv") {
+		t.Errorf("unexpected disposition %s", w.Header().Get("Content-Disposition"))
+	}
+	lines := strings.Split(strings.TrimPrefix(w.Body.String(), "\ufeff"), "\n")
+	if !strings.HasSuffix(lines[0], ",Suma") {
+		t.Errorf("expected a column per problem, got %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "1,'=Beto,") || !strings.HasSuffix(lines[1], ",75") {
+		t.Errorf("expected the rated applicant first, got %s", lines[1])
+	}
+}
+
+func TestExportHandlerStage(t *testing.T) {
+	inputFn := func(r *http.Request) (offers.ExportInput, error) {
+		return offers.ExportInput{OfferID: exportOfferID, Format: shared.ExportXLSX, Stage: shared.StageNew}, nil
+	}
+	handler := offers.CreateExportHandler(inputFn, authRepo{}, newExportStorage())
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if !strings.HasPrefix(w.Body.String(), "PK") {
+		t.Error("expected a zip file")
+	}
+	sheet := offers.ApplicantSheet(shared.Offer{}, exportApplications()[:1])
+	if len(sheet.Rows) != 1 || len(sheet.Header) != 10 {
+		t.Errorf("unexpected sheet %v", sheet)
+	}
+}
+
+func TestDossierHandlerNotFound(t *testing.T) {
+	inputFn := func(r *http.Request) (offers.DossierInput, error) {
+		return offers.DossierInput{OfferID: exportOfferID, ParticipationID: "3b241101-e2bb-4255-8caf-000000000000"}, nil
+	}
+	handler := offers.CreateDossierHandler(inputFn, authRepo{}, newExportStorage())
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestDossierHandler(t *testing.T) {
+	inputFn := func(r *http.Request) (offers.DossierInput, error) {
+		return offers.DossierInput{OfferID: exportOfferID, ParticipationID: exportParticipationID}, nil
+	}
+	storage := newExportStorage()
+	storage.On("SelectStrokeWindows", mock.Anything, exportParticipationID).Return([]shared.StrokeWindow{}, nil)
+	handler := offers.CreateDossierHandler(inputFn, authRepo{}, storage)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("Content-Type") != "application/pdf" {
+		t.Errorf("unexpected content type %s", w.Header().Get("Content-Type"))
+	}
+	if !strings.HasPrefix(w.Body.String(), "%PDF-") {
+		t.Error("expected a PDF document")
+	}
+	storage.AssertExpectations(t)
+}
@@ -0,0 +1,142 @@
+package offerstest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type gradeStorage struct {
+	mock.Mock
+}
+
+func (s *gradeStorage) GradeResponse(ctx context.Context, userID, responseID string, score int32, feedback string) error {
+	args := s.Called(ctx, userID, responseID, score, feedback)
+	return args.Error(0)
+}
+
+func gradeInputFn(r *http.Request) (offers.GradeInput, error) {
+	return offers.GradeInput{ResponseID: "response-id", Score: 7, Feedback: "bien"}, nil
+}
+
+func TestGetGradeInput(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{"score": {"7"}, "feedback": {"bien"}}
+	req = WithUrlParam(req, "responseID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	input, err := offers.GetGradeInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if input.Score != 7 || input.Feedback != "bien" {
+		t.Errorf("unexpected input %v", input)
+	}
+}
+
+func TestGetGradeInputBadScore(t *testing.T) {
+	for _, score := range []string{"", "-1", "siete"} {
+		req, _ := http.NewRequest("POST", "/", nil)
+		req.Form = map[string][]string{"score": {score}}
+		req = WithUrlParam(req, "responseID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+		if _, err := offers.GetGradeInput(req); err == nil {
+			t.Errorf("expected error for score %q", score)
+		}
+	}
+}
+
+func TestGradeBadAuth(t *testing.T) {
+	handler := offers.CreateGradeHandler(gradeInputFn, invalidAuthRepo{}, new(gradeStorage), &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestGradeVisitor(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{Role: auth.NotAuthRole}, nil)
+	handler := offers.CreateGradeHandler(gradeInputFn, authz, new(gradeStorage), &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestGradeBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (offers.GradeInput, error) {
+		return offers.GradeInput{}, errors.New("error")
+	}
+	handler := offers.CreateGradeHandler(invalidInputFn, authRepo{}, new(gradeStorage), &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestGradeNotFound(t *testing.T) {
+	storage := new(gradeStorage)
+	storage.On("GradeResponse", mock.Anything, mock.Anything, "response-id", int32(7), "bien").Return(sql.ErrNoRows)
+	handler := offers.CreateGradeHandler(gradeInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestGradeOutOfRange(t *testing.T) {
+	storage := new(gradeStorage)
+	storage.On("GradeResponse", mock.Anything, mock.Anything, "response-id", int32(7), "bien").Return(shared.ErrScoreOutOfRange)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "adjustmentAlert", shared.Alert{Ok: false, Msg: shared.ErrScoreOutOfRange.Error()}).Return(nil)
+	handler := offers.CreateGradeHandler(gradeInputFn, authRepo{}, storage, templ)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestGradeBadStorage(t *testing.T) {
+	storage := new(gradeStorage)
+	storage.On("GradeResponse", mock.Anything, mock.Anything, "response-id", int32(7), "bien").Return(errors.New("error"))
+	handler := offers.CreateGradeHandler(gradeInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestGradeHandler(t *testing.T) {
+	storage := new(gradeStorage)
+	storage.On("GradeResponse", mock.Anything, mock.Anything, "response-id", int32(7), "bien").Return(nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "adjustmentAlert", shared.Alert{Ok: true, Msg: shared.MsgSaved}).Return(nil)
+	handler := offers.CreateGradeHandler(gradeInputFn, authRepo{}, storage, templ)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+	templ.AssertExpectations(t)
+}
@@ -0,0 +1,162 @@
+package offerstest
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+func invitationsInputFn(r *http.Request) (offers.InvitationsInput, error) {
+	return offers.InvitationsInput{OfferID: "offer", Emails: []string{"ana@mail.com"}}, nil
+}
+
+func invitationDeleteInputFn(r *http.Request) (offers.InvitationDeleteInput, error) {
+	return offers.InvitationDeleteInput{OfferID: "offer", InvitationID: "invitation"}, nil
+}
+
+func TestParseInvitationEmails(t *testing.T) {
+	emails, err := offers.ParseInvitationEmails("Ana@Mail.com, luis@mail.com;\nana@mail.com\tmaria@mail.com")
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	expected := []string{"ana@mail.com", "luis@mail.com", "maria@mail.com"}
+	if len(emails) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, emails)
+	}
+	for i := range expected {
+		if emails[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], emails[i])
+		}
+	}
+}
+
+func TestParseInvitationEmailsInvalid(t *testing.T) {
+	cases := map[string]error{
+		"":                        shared.ErrInvitationNoEmails,
+		" , \n":                   shared.ErrInvitationNoEmails,
+		"ana@mail.com, not-email": shared.ErrInvitationBadEmail,
+	}
+	for raw, expected := range cases {
+		if _, err := offers.ParseInvitationEmails(raw); !errors.Is(err, expected) {
+			t.Errorf("%q: expected %v, got %v", raw, expected, err)
+		}
+	}
+}
+
+func TestGetInvitationDeleteInputBadID(t *testing.T) {
+	req, _ := http.NewRequest("DELETE", "/", nil)
+	req = WithUrlParams(req, Params{
+		"offerID":      "f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		"invitationID": "invalid",
+	})
+	if _, err := offers.GetInvitationDeleteInput(req); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestInvitationsBadEmails(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (offers.InvitationsInput, error) {
+		return offers.InvitationsInput{}, shared.ErrInvitationBadEmail
+	}
+	storage := new(quizAccessStorage)
+	handler := offers.CreateInvitationsHandler(invalidInputFn, authRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("HX-Retarget") != "#access-alert" {
+		t.Errorf("expected the alert to be retargeted, got %q", w.Header().Get("HX-Retarget"))
+	}
+}
+
+func TestInvitationsNotOwner(t *testing.T) {
+	storage := new(quizAccessStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, errors.New("error"))
+	handler := offers.CreateInvitationsHandler(invitationsInputFn, authRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestInvitationsBadStorage(t *testing.T) {
+	storage := new(quizAccessStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{ID: "offer", MemberRole: shared.RoleOwner}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{ID: "quiz"}, nil)
+	storage.On("InsertInvitations", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error"))
+	handler := offers.CreateInvitationsHandler(invitationsInputFn, authRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestInvitationsViewer(t *testing.T) {
+	storage := new(quizAccessStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{ID: "offer", MemberRole: shared.RoleViewer}, nil)
+	handler := offers.CreateInvitationsHandler(invitationsInputFn, authRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+	storage.AssertNotCalled(t, "InsertInvitations", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestInvitations(t *testing.T) {
+	storage := new(quizAccessStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{ID: "offer", MemberRole: shared.RoleOwner}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{ID: "quiz"}, nil)
+	storage.On("InsertInvitations", mock.Anything, "quiz", []string{"ana@mail.com"}).Return(nil)
+	storage.On("SelectInvitations", mock.Anything, "quiz").Return([]shared.Invitation{}, nil)
+	handler := offers.CreateInvitationsHandler(invitationsInputFn, authRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+}
+
+func TestInvitationDeleteBadStorage(t *testing.T) {
+	storage := new(quizAccessStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{ID: "offer", MemberRole: shared.RoleOwner}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{ID: "quiz"}, nil)
+	storage.On("DeleteInvitation", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error"))
+	handler := offers.CreateInvitationDeleteHandler(invitationDeleteInputFn, authRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("DELETE", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestInvitationDelete(t *testing.T) {
+	storage := new(quizAccessStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{ID: "offer", MemberRole: shared.RoleOwner}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{ID: "quiz"}, nil)
+	storage.On("DeleteInvitation", mock.Anything, "quiz", "invitation").Return(nil)
+	storage.On("SelectInvitations", mock.Anything, "quiz").Return([]shared.Invitation{}, nil)
+	handler := offers.CreateInvitationDeleteHandler(invitationDeleteInputFn, authRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("DELETE", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+}
@@ -6,26 +6,51 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/kw3a/spotted-server/internal/server/offers"
 	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
 )
 
 type offerListStorage struct{}
 
-func (j *offerListStorage) SelectOffers(ctx context.Context, params shared.OfferQueryParams) ([]shared.Offer, error) {
-	return []shared.Offer{}, nil
+func (j *offerListStorage) SearchOffers(ctx context.Context, filters shared.OfferFilters) ([]shared.Offer, *shared.OfferCursor, error) {
+	return []shared.Offer{}, nil, nil
+}
+
+func (j *offerListStorage) SearchFacets(ctx context.Context, filters shared.OfferFilters) (shared.OfferFacets, error) {
+	return shared.OfferFacets{}, nil
 }
 
 type invalidOfferListStorage struct{}
 
-func (i *invalidOfferListStorage) SelectOffers(ctx context.Context, params shared.OfferQueryParams) ([]shared.Offer, error) {
-	return nil, errors.New("error")
+func (i *invalidOfferListStorage) SearchOffers(ctx context.Context, filters shared.OfferFilters) ([]shared.Offer, *shared.OfferCursor, error) {
+	return nil, nil, errors.New("error")
+}
+
+func (i *invalidOfferListStorage) SearchFacets(ctx context.Context, filters shared.OfferFilters) (shared.OfferFacets, error) {
+	return shared.OfferFacets{}, errors.New("error")
+}
+
+type offerSearchStorage struct {
+	mock.Mock
+}
+
+func (s *offerSearchStorage) SearchOffers(ctx context.Context, filters shared.OfferFilters) ([]shared.Offer, *shared.OfferCursor, error) {
+	args := s.Called(ctx, filters)
+	return args.Get(0).([]shared.Offer), args.Get(1).(*shared.OfferCursor), args.Error(2)
+}
+
+func (s *offerSearchStorage) SearchFacets(ctx context.Context, filters shared.OfferFilters) (shared.OfferFacets, error) {
+	args := s.Called(ctx, filters)
+	return args.Get(0).(shared.OfferFacets), args.Error(1)
 }
 
-func offerListFn(r *http.Request) (shared.OfferQueryParams, error) {
-	return shared.OfferQueryParams{}, nil
+func offerListFn(r *http.Request) (shared.OfferFilters, error) {
+	return shared.OfferFilters{}, nil
 }
 
 func TestOfferListHandlerBadAuth(t *testing.T) {
@@ -42,8 +67,8 @@ func TestOfferListHandlerBadAuth(t *testing.T) {
 }
 
 func TestOfferListHandlerBadInput(t *testing.T) {
-	inputFn := func(r *http.Request) (shared.OfferQueryParams, error) {
-		return shared.OfferQueryParams{}, fmt.Errorf("input error")
+	inputFn := func(r *http.Request) (shared.OfferFilters, error) {
+		return shared.OfferFilters{}, fmt.Errorf("input error")
 	}
 	handler := offers.CreateOfferListHandler(inputFn, &authRepo{}, &offerListStorage{}, &templates{})
 	if handler == nil {
@@ -95,3 +120,116 @@ func TestOfferListHandler(t *testing.T) {
 		t.Error("expected ok")
 	}
 }
+
+func TestOfferListHandlerBadFacets(t *testing.T) {
+	storage := new(offerSearchStorage)
+	storage.On("SearchOffers", mock.Anything, mock.Anything).Return([]shared.Offer{}, (*shared.OfferCursor)(nil), nil)
+	storage.On("SearchFacets", mock.Anything, mock.Anything).Return(shared.OfferFacets{}, errors.New("error"))
+	handler := offers.CreateOfferListHandler(offerListFn, &authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected: %v, got %v", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestOfferListHandlerNextPage(t *testing.T) {
+	filters := shared.OfferFilters{
+		Modality: shared.ModalityRemote,
+		Tags:     []int32{62},
+		Sort:     shared.SortDate,
+		After:    &shared.OfferCursor{Key: 1700000000, ID: "previous-id"},
+	}
+	inputFn := func(r *http.Request) (shared.OfferFilters, error) {
+		return filters, nil
+	}
+	storage := new(offerSearchStorage)
+	storage.On("SearchOffers", mock.Anything, filters).
+		Return([]shared.Offer{{ID: "offer-id"}}, &shared.OfferCursor{Key: 1600000000, ID: "offer-id"}, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "offerList", mock.MatchedBy(func(data offers.OfferListData) bool {
+		return data.NextURL == "/?after=1.6e%2B09_offer-id&modality=remote&sort=date&tag=62"
+	})).Return(nil)
+	handler := offers.CreateOfferListHandler(inputFn, &authRepo{}, storage, templ)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected: %v, got %v", http.StatusOK, w.Code)
+	}
+	storage.AssertNotCalled(t, "SearchFacets", mock.Anything, mock.Anything)
+	templ.AssertExpectations(t)
+}
+
+func TestOfferListHandlerSnippets(t *testing.T) {
+	inputFn := func(r *http.Request) (shared.OfferFilters, error) {
+		return shared.OfferFilters{Query: "programación", Sort: shared.SortRelevance}, nil
+	}
+	storage := new(offerSearchStorage)
+	storage.On("SearchOffers", mock.Anything, mock.Anything).Return([]shared.Offer{
+		{ID: "offer-id", About: "Sin coincidencias", Requirements: "Experiencia en programacion funcional"},
+	}, (*shared.OfferCursor)(nil), nil)
+	storage.On("SearchFacets", mock.Anything, mock.Anything).Return(shared.OfferFacets{}, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "offerListPage", mock.MatchedBy(func(data offers.OfferListData) bool {
+		snippet := data.Offers[0].Snippet
+		return data.NextURL == "" && len(snippet) == 3 && snippet[1].Match && snippet[1].Text == "programacion"
+	})).Return(nil)
+	handler := offers.CreateOfferListHandler(inputFn, &authRepo{}, storage, templ)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected: %v, got %v", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestGetListParams(t *testing.T) {
+	companyID := "00000000-0000-0000-0000-000000000001"
+	cases := []struct {
+		name      string
+		query     string
+		expectErr bool
+		expected  shared.OfferFilters
+	}{
+		{"empty", "", false, shared.OfferFilters{Sort: shared.SortDate}},
+		{"filters", "q=go&modality=remote&seniority=senior&contract=fulltime&company=" + companyID +
+			"&currency=USD&tag=62&tag=71&tag=62&minWage=100&maxWage=200&sort=wage&after=1.5_" + companyID, false,
+			shared.OfferFilters{Query: "go", Modality: "remote", Seniority: "senior", Contract: "fulltime",
+				CompanyID: companyID, Currency: "USD", Tags: []int32{62, 71}, MinWage: 100, MaxWage: 200,
+				Sort: shared.SortWage, After: &shared.OfferCursor{Key: 1.5, ID: companyID}}},
+		{"relevance by default", "q=desarrollador", false,
+			shared.OfferFilters{Query: "desarrollador", Sort: shared.SortRelevance}},
+		{"only stopwords", "q=de+la", false, shared.OfferFilters{Query: "de la", Sort: shared.SortDate}},
+		{"bad cursor", "after=x", true, shared.OfferFilters{}},
+		{"long query", "q=" + strings.Repeat("a", 31), true, shared.OfferFilters{}},
+		{"bad modality", "modality=space", true, shared.OfferFilters{}},
+		{"bad seniority", "seniority=guru", true, shared.OfferFilters{}},
+		{"bad contract", "contract=forever", true, shared.OfferFilters{}},
+		{"bad company", "company=x", true, shared.OfferFilters{}},
+		{"bad currency", "currency=XXX", true, shared.OfferFilters{}},
+		{"bad sort", "sort=title", true, shared.OfferFilters{}},
+		{"bad tag", "tag=go", true, shared.OfferFilters{}},
+		{"negative wage", "minWage=-1", true, shared.OfferFilters{}},
+		{"inverted wages", "minWage=300&maxWage=200", true, shared.OfferFilters{}},
+	}
+	for _, c := range cases {
+		req, _ := http.NewRequest("GET", "/?"+c.query, nil)
+		filters, err := offers.GetListParams(req)
+		if c.expectErr {
+			if err == nil {
+				t.Errorf("%s: expected error", c.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(filters, c.expected) {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, filters)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/kw3a/spotted-server/internal/server/offers"
 	"github.com/kw3a/spotted-server/internal/server/shared"
@@ -42,7 +43,7 @@ func (s *preambleStorage) SelectLanguages(ctx context.Context, quizID string) ([
 
 func TestPreambleHandlerBadAuth(t *testing.T) {
 	storage := new(preambleStorage)
-	handler := offers.CreateParticipationHandler(&templates{}, storage, invalidAuthRepo{}, preambleInputFn)
+	handler := offers.CreateParticipationHandler(&templates{}, storage, invalidAuthRepo{}, invitationSigner{}, preambleInputFn)
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -56,7 +57,7 @@ func TestPreambleHandlerBadInput(t *testing.T) {
 		return offers.PreambleInput{}, errors.New("error")
 	}
 	storage := new(preambleStorage)
-	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, invalidInputFn)
+	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, invitationSigner{}, invalidInputFn)
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -68,7 +69,7 @@ func TestPreambleHandlerBadInput(t *testing.T) {
 func TestPreambleHandlerBadStorageSelectOffer(t *testing.T) {
 	storage := new(preambleStorage)
 	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{}, errors.New("error"))
-	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, preambleInputFn)
+	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, invitationSigner{}, preambleInputFn)
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -77,10 +78,10 @@ func TestPreambleHandlerBadStorageSelectOffer(t *testing.T) {
 	}
 }
 
-func TestPreambleHandlerOfferStatus0(t *testing.T) {
+func TestPreambleHandlerDraftOffer(t *testing.T) {
 	storage := new(preambleStorage)
-	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: 0}, nil)
-	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, preambleInputFn)
+	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: shared.OfferDraft}, nil)
+	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, invitationSigner{}, preambleInputFn)
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -91,9 +92,9 @@ func TestPreambleHandlerOfferStatus0(t *testing.T) {
 
 func TestPreambleHandlerBadStorageSelectQuiz(t *testing.T) {
 	storage := new(preambleStorage)
-	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: 1}, nil)
+	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: shared.OfferPublished}, nil)
 	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, errors.New("error"))
-	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, preambleInputFn)
+	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, invitationSigner{}, preambleInputFn)
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -104,10 +105,10 @@ func TestPreambleHandlerBadStorageSelectQuiz(t *testing.T) {
 
 func TestPreambleHandlerBadStorageSelectLanguages(t *testing.T) {
 	storage := new(preambleStorage)
-	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: 1}, nil)
+	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: shared.OfferPublished}, nil)
 	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
 	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, errors.New("error"))
-	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, preambleInputFn)
+	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, invitationSigner{}, preambleInputFn)
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -118,11 +119,11 @@ func TestPreambleHandlerBadStorageSelectLanguages(t *testing.T) {
 
 func TestPreambleHandlerBadStorageParticipationStatus(t *testing.T) {
 	storage := new(preambleStorage)
-	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: 1}, nil)
+	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: shared.OfferPublished}, nil)
 	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
 	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(shared.Participation{}, errors.New("error"))
-	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, preambleInputFn)
+	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, invitationSigner{}, preambleInputFn)
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -133,11 +134,11 @@ func TestPreambleHandlerBadStorageParticipationStatus(t *testing.T) {
 
 func TestPreambleHandlerBadTemplate(t *testing.T) {
 	storage := new(preambleStorage)
-	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: 1}, nil)
+	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: shared.OfferPublished}, nil)
 	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
 	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(shared.Participation{}, nil)
-	handler := offers.CreateParticipationHandler(&invalidTemplates{}, storage, authRepo{}, preambleInputFn)
+	handler := offers.CreateParticipationHandler(&invalidTemplates{}, storage, authRepo{}, invitationSigner{}, preambleInputFn)
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -148,11 +149,11 @@ func TestPreambleHandlerBadTemplate(t *testing.T) {
 
 func TestPreambleHandler(t *testing.T) {
 	storage := new(preambleStorage)
-	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: 1}, nil)
+	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: shared.OfferPublished}, nil)
 	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
 	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(shared.Participation{}, nil)
-	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, preambleInputFn)
+	handler := offers.CreateParticipationHandler(&templates{}, storage, authRepo{}, invitationSigner{}, preambleInputFn)
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
 	handler(w, req)
@@ -160,3 +161,72 @@ func TestPreambleHandler(t *testing.T) {
 		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
 	}
 }
+
+func TestPreambleHandlerClosedQuiz(t *testing.T) {
+	storage := new(preambleStorage)
+	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: shared.OfferPublished}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{ClosesAt: time.Now().Add(-time.Hour)}, nil)
+	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(shared.Participation{}, errors.New("error"))
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "preamble", mock.MatchedBy(func(data offers.PreambleData) bool {
+		return data.AccessMsg == shared.ErrQuizClosed.Error()
+	})).Return(nil)
+	handler := offers.CreateParticipationHandler(templ, storage, authRepo{}, invitationSigner{}, preambleInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestPreambleHandlerClosedOffer(t *testing.T) {
+	storage := new(preambleStorage)
+	storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: shared.OfferClosed}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
+	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(shared.Participation{}, errors.New("error"))
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "preamble", mock.MatchedBy(func(data offers.PreambleData) bool {
+		return data.AccessMsg == shared.ErrQuizClosed.Error()
+	})).Return(nil)
+	handler := offers.CreateParticipationHandler(templ, storage, authRepo{}, invitationSigner{}, preambleInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestPreambleHandlerInvitation(t *testing.T) {
+	cases := map[string]offers.PreambleData{
+		"ABCD2345.signature": {InvitationCode: "ABCD2345"},
+		"ABCD2345.forged":    {InvitationMsg: shared.ErrInvitationInvalid.Error()},
+	}
+	for token, expected := range cases {
+		storage := new(preambleStorage)
+		storage.On("SelectOffer", mock.Anything, mock.Anything).Return(shared.Offer{Status: shared.OfferPublished}, nil)
+		storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{InviteOnly: true}, nil)
+		storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
+		storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(shared.Participation{}, errors.New("error"))
+		templ := new(templatesMock)
+		templ.On("Render", mock.Anything, "preamble", mock.MatchedBy(func(data offers.PreambleData) bool {
+			return data.InvitationCode == expected.InvitationCode && data.InvitationMsg == expected.InvitationMsg
+		})).Return(nil)
+		inputFn := func(r *http.Request) (offers.PreambleInput, error) {
+			return offers.PreambleInput{Invitation: token}, nil
+		}
+		handler := offers.CreateParticipationHandler(templ, storage, authRepo{}, invitationSigner{}, inputFn)
+		req, _ := http.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+		}
+		templ.AssertExpectations(t)
+	}
+}
@@ -0,0 +1,50 @@
+package offerstest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/stretchr/testify/mock"
+)
+
+func previewRequest(text string) *http.Request {
+	form := url.Values{"text": {text}}
+	req, _ := http.NewRequest("POST", "/markdown/preview", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req
+}
+
+func TestGetPreviewInput(t *testing.T) {
+	input, err := offers.GetPreviewInput(previewRequest("**hola**"))
+	if err != nil || input.Text != "**hola**" {
+		t.Errorf("unexpected input %+v, %v", input, err)
+	}
+	if _, err := offers.GetPreviewInput(previewRequest(strings.Repeat("a", 5001))); err == nil {
+		t.Error("expected error for a long text")
+	}
+}
+
+func TestPreviewHandlerBadAuth(t *testing.T) {
+	handler := offers.CreatePreviewHandler(offers.GetPreviewInput, invalidAuthRepo{}, &templates{})
+	w := httptest.NewRecorder()
+	handler(w, previewRequest("hola"))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestPreviewHandler(t *testing.T) {
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "markdownPreview", "# hola").Return(nil)
+	handler := offers.CreatePreviewHandler(offers.GetPreviewInput, authRepo{}, templ)
+	w := httptest.NewRecorder()
+	handler(w, previewRequest("# hola"))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
@@ -0,0 +1,165 @@
+package offerstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type proctoringRulesStorage struct {
+	mock.Mock
+}
+
+func (s *proctoringRulesStorage) SelectOfferByUser(ctx context.Context, id string, userID string) (shared.Offer, error) {
+	args := s.Called(ctx, id, userID)
+	return args.Get(0).(shared.Offer), args.Error(1)
+}
+func (s *proctoringRulesStorage) SelectQuizByOffer(ctx context.Context, offerID string) (shared.Quiz, error) {
+	args := s.Called(ctx, offerID)
+	return args.Get(0).(shared.Quiz), args.Error(1)
+}
+func (s *proctoringRulesStorage) UpsertProctoringRule(ctx context.Context, quizID string, rule shared.ProctoringRule) error {
+	args := s.Called(ctx, quizID, rule)
+	return args.Error(0)
+}
+func (s *proctoringRulesStorage) SelectProctoringRules(ctx context.Context, quizID string) ([]shared.ProctoringRule, error) {
+	args := s.Called(ctx, quizID)
+	return args.Get(0).([]shared.ProctoringRule), args.Error(1)
+}
+
+func proctoringRulesInputFn(r *http.Request) (offers.ProctoringRulesInput, error) {
+	return offers.ProctoringRulesInput{}, nil
+}
+
+func TestGetProctoringRulesInput(t *testing.T) {
+	offerID := "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{
+		"kind":      {shared.ProctoringBlur},
+		"threshold": {"5"},
+	}
+	req = WithUrlParam(req, "offerID", offerID)
+	input, err := offers.GetProctoringRulesInput(req)
+	if err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+	if input.Rule.Threshold != 5 || input.Rule.Kind != shared.ProctoringBlur {
+		t.Errorf("unexpected rule %v", input.Rule)
+	}
+}
+
+func TestGetProctoringRulesInputBadThreshold(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{
+		"kind":      {shared.ProctoringBlur},
+		"threshold": {"-1"},
+	}
+	req = WithUrlParam(req, chi.URLParam(req, "offerID"), "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	if _, err := offers.GetProctoringRulesInput(req); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestMergeProctoringRules(t *testing.T) {
+	rules := offers.MergeProctoringRules([]shared.ProctoringRule{
+		{Kind: shared.ProctoringPaste, Threshold: 2},
+	})
+	if len(rules) != len(shared.ProctoringKinds) {
+		t.Fatalf("expected %d rules, got %d", len(shared.ProctoringKinds), len(rules))
+	}
+	for _, rule := range rules {
+		if rule.Kind == shared.ProctoringPaste && rule.Threshold != 2 {
+			t.Errorf("expected threshold 2, got %d", rule.Threshold)
+		}
+		if rule.Kind != shared.ProctoringPaste && rule.Threshold != 0 {
+			t.Errorf("expected threshold 0, got %d", rule.Threshold)
+		}
+	}
+}
+
+func TestProctoringRulesBadAuth(t *testing.T) {
+	storage := new(proctoringRulesStorage)
+	handler := offers.CreateProctoringRulesHandler(proctoringRulesInputFn, invalidAuthRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestProctoringRulesVisitor(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{Role: auth.NotAuthRole}, nil)
+	storage := new(proctoringRulesStorage)
+	handler := offers.CreateProctoringRulesHandler(proctoringRulesInputFn, authz, storage, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestProctoringRulesBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (offers.ProctoringRulesInput, error) {
+		return offers.ProctoringRulesInput{}, errors.New("error")
+	}
+	storage := new(proctoringRulesStorage)
+	handler := offers.CreateProctoringRulesHandler(invalidInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestProctoringRulesNotOwner(t *testing.T) {
+	storage := new(proctoringRulesStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, errors.New("error"))
+	handler := offers.CreateProctoringRulesHandler(proctoringRulesInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestProctoringRulesBadStorageUpsert(t *testing.T) {
+	storage := new(proctoringRulesStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{MemberRole: shared.RoleOwner}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
+	storage.On("UpsertProctoringRule", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error"))
+	handler := offers.CreateProctoringRulesHandler(proctoringRulesInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestProctoringRulesHandler(t *testing.T) {
+	storage := new(proctoringRulesStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{MemberRole: shared.RoleOwner}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
+	storage.On("UpsertProctoringRule", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	storage.On("SelectProctoringRules", mock.Anything, mock.Anything).Return([]shared.ProctoringRule{}, nil)
+	handler := offers.CreateProctoringRulesHandler(proctoringRulesInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
@@ -0,0 +1,257 @@
+package offerstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type invitationSigner struct{}
+
+func (s invitationSigner) SignInvitation(code string) string {
+	return code + ".signature"
+}
+
+func (s invitationSigner) VerifyInvitation(token string) (string, error) {
+	code, found := strings.CutSuffix(token, ".signature")
+	if !found {
+		return "", errors.New("error")
+	}
+	return code, nil
+}
+
+type quizAccessStorage struct {
+	mock.Mock
+}
+
+func (s *quizAccessStorage) SelectOfferByUser(ctx context.Context, id string, userID string) (shared.Offer, error) {
+	args := s.Called(ctx, id, userID)
+	return args.Get(0).(shared.Offer), args.Error(1)
+}
+func (s *quizAccessStorage) SelectQuizByOffer(ctx context.Context, offerID string) (shared.Quiz, error) {
+	args := s.Called(ctx, offerID)
+	return args.Get(0).(shared.Quiz), args.Error(1)
+}
+func (s *quizAccessStorage) UpdateQuizAccess(ctx context.Context, quiz shared.Quiz) error {
+	args := s.Called(ctx, quiz)
+	return args.Error(0)
+}
+func (s *quizAccessStorage) SelectInvitations(ctx context.Context, quizID string) ([]shared.Invitation, error) {
+	args := s.Called(ctx, quizID)
+	return args.Get(0).([]shared.Invitation), args.Error(1)
+}
+func (s *quizAccessStorage) InsertInvitations(ctx context.Context, quizID string, emails []string) error {
+	args := s.Called(ctx, quizID, emails)
+	return args.Error(0)
+}
+func (s *quizAccessStorage) DeleteInvitation(ctx context.Context, quizID string, invitationID string) error {
+	args := s.Called(ctx, quizID, invitationID)
+	return args.Error(0)
+}
+
+func quizAccessPageInputFn(r *http.Request) (offers.ApplicantsInput, error) {
+	return offers.ApplicantsInput{OfferID: "offer"}, nil
+}
+
+func quizAccessInputFn(r *http.Request) (offers.QuizAccessInput, error) {
+	return offers.QuizAccessInput{OfferID: "offer", InviteOnly: true}, nil
+}
+
+func TestGetQuizAccessInput(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{
+		"opensAt":    {"2025-03-01T09:00"},
+		"closesAt":   {"2025-03-02T18:30"},
+		"inviteOnly": {"on"},
+	}
+	req = WithUrlParam(req, "offerID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	input, err := offers.GetQuizAccessInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !input.InviteOnly {
+		t.Error("expected invite only")
+	}
+	expected := time.Date(2025, 3, 1, 9, 0, 0, 0, time.Local)
+	if !input.OpensAt.Equal(expected) {
+		t.Errorf("expected %v, got %v", expected, input.OpensAt)
+	}
+}
+
+func TestGetQuizAccessInputOpenWindow(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{}
+	req = WithUrlParam(req, "offerID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	input, err := offers.GetQuizAccessInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !input.OpensAt.IsZero() || !input.ClosesAt.IsZero() || input.InviteOnly {
+		t.Errorf("expected an open quiz, got %v", input)
+	}
+}
+
+func TestGetQuizAccessInputBadWindow(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{
+		"opensAt":  {"2025-03-02T09:00"},
+		"closesAt": {"2025-03-01T09:00"},
+	}
+	req = WithUrlParam(req, "offerID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	if _, err := offers.GetQuizAccessInput(req); !errors.Is(err, shared.ErrQuizWindow) {
+		t.Errorf("expected %v, got %v", shared.ErrQuizWindow, err)
+	}
+}
+
+func TestGetQuizAccessInputBadTime(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{
+		"opensAt": {"mañana"},
+	}
+	req = WithUrlParam(req, "offerID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+	if _, err := offers.GetQuizAccessInput(req); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestQuizAccessBadAuth(t *testing.T) {
+	storage := new(quizAccessStorage)
+	handler := offers.CreateQuizAccessHandler(quizAccessPageInputFn, invalidAuthRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestQuizAccessVisitor(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{Role: auth.NotAuthRole}, nil)
+	storage := new(quizAccessStorage)
+	handler := offers.CreateQuizAccessHandler(quizAccessPageInputFn, authz, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestQuizAccessNotOwner(t *testing.T) {
+	storage := new(quizAccessStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, errors.New("error"))
+	handler := offers.CreateQuizAccessHandler(quizAccessPageInputFn, authRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestQuizAccessBadStorageInvitations(t *testing.T) {
+	storage := new(quizAccessStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{ID: "offer", MemberRole: shared.RoleOwner}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{ID: "quiz"}, nil)
+	storage.On("SelectInvitations", mock.Anything, "quiz").Return([]shared.Invitation{}, errors.New("error"))
+	handler := offers.CreateQuizAccessHandler(quizAccessPageInputFn, authRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestQuizAccess(t *testing.T) {
+	t.Setenv("MY_URL", "https://spotted.test")
+	storage := new(quizAccessStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{ID: "offer", MemberRole: shared.RoleOwner}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{ID: "quiz"}, nil)
+	storage.On("SelectInvitations", mock.Anything, "quiz").Return([]shared.Invitation{{ID: "1", Code: "ABCD2345"}}, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "quizAccess", mock.MatchedBy(func(data offers.QuizAccessData) bool {
+		return len(data.Invitations) == 1 &&
+			data.Invitations[0].Link == "https://spotted.test/preamble/offer?invitation=ABCD2345.signature"
+	})).Return(nil)
+	handler := offers.CreateQuizAccessHandler(quizAccessPageInputFn, authRepo{}, storage, invitationSigner{}, templ)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestQuizAccessUpdateBadWindow(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (offers.QuizAccessInput, error) {
+		return offers.QuizAccessInput{}, shared.ErrQuizWindow
+	}
+	storage := new(quizAccessStorage)
+	handler := offers.CreateQuizAccessUpdateHandler(invalidInputFn, authRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("HX-Retarget") != "#access-alert" {
+		t.Errorf("expected the alert to be retargeted, got %q", w.Header().Get("HX-Retarget"))
+	}
+	storage.AssertNotCalled(t, "UpdateQuizAccess", mock.Anything, mock.Anything)
+}
+
+func TestQuizAccessUpdateBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (offers.QuizAccessInput, error) {
+		return offers.QuizAccessInput{}, errors.New("error")
+	}
+	storage := new(quizAccessStorage)
+	handler := offers.CreateQuizAccessUpdateHandler(invalidInputFn, authRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestQuizAccessUpdateBadStorage(t *testing.T) {
+	storage := new(quizAccessStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{ID: "offer", MemberRole: shared.RoleOwner}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{ID: "quiz"}, nil)
+	storage.On("UpdateQuizAccess", mock.Anything, mock.Anything).Return(errors.New("error"))
+	handler := offers.CreateQuizAccessUpdateHandler(quizAccessInputFn, authRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestQuizAccessUpdate(t *testing.T) {
+	storage := new(quizAccessStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{ID: "offer", MemberRole: shared.RoleOwner}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{ID: "quiz", Duration: 30}, nil)
+	storage.On("UpdateQuizAccess", mock.Anything, shared.Quiz{ID: "quiz", Duration: 30, InviteOnly: true}).Return(nil)
+	storage.On("SelectInvitations", mock.Anything, "quiz").Return([]shared.Invitation{}, nil)
+	handler := offers.CreateQuizAccessUpdateHandler(quizAccessInputFn, authRepo{}, storage, invitationSigner{}, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+}
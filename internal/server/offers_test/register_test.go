@@ -18,9 +18,9 @@ type registerStorage struct {
 	mock.Mock
 }
 
-func (s *registerStorage) GetCompanyByID(ctx context.Context, companyID string) (shared.Company, error) {
-	args := s.Called(ctx, companyID)
-	return args.Get(0).(shared.Company), args.Error(1)
+func (s *registerStorage) SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error) {
+	args := s.Called(ctx, companyID, userID)
+	return args.String(0), args.Error(1)
 }
 func (s *registerStorage) RegisterOffer(
 	ctx context.Context,
@@ -29,8 +29,9 @@ func (s *registerStorage) RegisterOffer(
 	quizID string,
 	quiz shared.Quiz,
 	problems []shared.Problem,
+	bankProblemIDs []string,
 ) error {
-	args := s.Called(ctx, ctx, offerID, quizID, quiz, problems)
+	args := s.Called(ctx, ctx, offerID, quizID, quiz, problems, bankProblemIDs)
 	return args.Error(0)
 }
 func (s *registerStorage) GetCompanies(ctx context.Context, params shared.CompanyQueryParams) ([]shared.Company, error) {
@@ -73,7 +74,7 @@ func TestRegisterHandlerBadInput(t *testing.T) {
 
 func TestRegisterHandlerBadStorageGetCompany(t *testing.T) {
 	storage := new(registerStorage)
-	storage.On("GetCompanyByID", mock.Anything, mock.Anything).Return(shared.Company{}, fmt.Errorf("error"))
+	storage.On("SelectCompanyRole", mock.Anything, mock.Anything, mock.Anything).Return("", fmt.Errorf("error"))
 	handler := offers.CreateRegisterHandler(&templates{}, &authRepo{}, storage, "", registerInputFn)
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
@@ -87,7 +88,7 @@ func TestRegisterHandlerNotOwner(t *testing.T) {
 	authz := new(authMock)
 	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "1"}, nil)
 	storage := new(registerStorage)
-	storage.On("GetCompanyByID", mock.Anything, mock.Anything).Return(shared.Company{UserID: "2"}, nil)
+	storage.On("SelectCompanyRole", mock.Anything, mock.Anything, mock.Anything).Return(shared.RoleViewer, nil)
 	handler := offers.CreateRegisterHandler(&templates{}, authz, storage, "", registerInputFn)
 	req, _ := http.NewRequest("GET", "/", nil)
 	w := httptest.NewRecorder()
@@ -101,7 +102,7 @@ func TestRegisterHandlerBadStorageRegisterOffer(t *testing.T) {
 	authz := new(authMock)
 	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "1"}, nil)
 	storage := new(registerStorage)
-	storage.On("GetCompanyByID", mock.Anything, mock.Anything).Return(shared.Company{UserID: "1"}, nil)
+	storage.On("SelectCompanyRole", mock.Anything, mock.Anything, mock.Anything).Return(shared.RoleOwner, nil)
 	storage.On(
 		"RegisterOffer",
 		mock.Anything,
@@ -110,6 +111,7 @@ func TestRegisterHandlerBadStorageRegisterOffer(t *testing.T) {
 		mock.Anything,
 		mock.Anything,
 		mock.Anything,
+		mock.Anything,
 	).Return(fmt.Errorf("error"))
 	handler := offers.CreateRegisterHandler(&templates{}, authz, storage, "", registerInputFn)
 	req, _ := http.NewRequest("GET", "/", nil)
@@ -120,12 +122,84 @@ func TestRegisterHandlerBadStorageRegisterOffer(t *testing.T) {
 	}
 }
 
+func TestRegisterHandlerBankProblemNotFound(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "1"}, nil)
+	storage := new(registerStorage)
+	storage.On("SelectCompanyRole", mock.Anything, mock.Anything, mock.Anything).Return(shared.RoleOwner, nil)
+	storage.On(
+		"RegisterOffer",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(shared.ErrBankProblemNotFound)
+	handler := offers.CreateRegisterHandler(&templates{}, authz, storage, "", registerInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestRegisterHandlerReferenceValidation(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "1"}, nil)
+	storage := new(registerStorage)
+	storage.On("SelectCompanyRole", mock.Anything, mock.Anything, mock.Anything).Return(shared.RoleOwner, nil)
+	storage.On(
+		"RegisterOffer",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(fmt.Errorf("%w: Suma", shared.ErrReferenceValidation))
+	handler := offers.CreateRegisterHandler(&templates{}, authz, storage, "", registerInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestRegisterHandlerGenerator(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "1"}, nil)
+	storage := new(registerStorage)
+	storage.On("SelectCompanyRole", mock.Anything, mock.Anything, mock.Anything).Return(shared.RoleOwner, nil)
+	storage.On(
+		"RegisterOffer",
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+		mock.Anything,
+	).Return(fmt.Errorf("%w con la semilla \"1\"", shared.ErrGenerator))
+	handler := offers.CreateRegisterHandler(&templates{}, authz, storage, "", registerInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
 func TestRegisterHandler(t *testing.T) {
 	prefix := "red/"
 	authz := new(authMock)
 	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "1"}, nil)
 	storage := new(registerStorage)
-	storage.On("GetCompanyByID", mock.Anything, mock.Anything).Return(shared.Company{UserID: "1"}, nil)
+	storage.On("SelectCompanyRole", mock.Anything, mock.Anything, mock.Anything).Return(shared.RoleOwner, nil)
 	storage.On(
 		"RegisterOffer",
 		mock.Anything,
@@ -134,6 +208,7 @@ func TestRegisterHandler(t *testing.T) {
 		mock.Anything,
 		mock.Anything,
 		mock.Anything,
+		mock.Anything,
 	).Return(nil)
 	handler := offers.CreateRegisterHandler(&templates{}, authz, storage, prefix, registerInputFn)
 	req, _ := http.NewRequest("GET", "/", nil)
@@ -147,3 +222,344 @@ func TestRegisterHandler(t *testing.T) {
 		t.Errorf("expected prefix %s in %s", prefix, redirectPath)
 	}
 }
+
+func TestValidateBankProblems(t *testing.T) {
+	id := "0b6f3e44-52c4-4c07-9d8b-4b1f2f0a6c11"
+	ids, err := offers.ValidateBankProblems([]string{id, id})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 1 {
+		t.Errorf("expected duplicated ids to be merged, got %v", ids)
+	}
+	if _, err := offers.ValidateBankProblems([]string{"not-an-id"}); err == nil {
+		t.Error("expected error for invalid id")
+	}
+}
+
+func signatureProblem(input, output string) offers.ProblemJ {
+	return offers.ProblemJ{
+		Title:       "Two sum",
+		Description: "Return the indexes of the two numbers",
+		TimeLimit:   "1000",
+		Signature:   " twoSum(nums int[],  target int) int[] ",
+		TestCases:   []offers.TestCaseJ{{Input: input, Output: output}},
+		Examples:    []offers.ExampleJ{{Input: "[3, 3]\n6", Output: "[0, 1]"}},
+	}
+}
+
+func TestValidateProblemSignature(t *testing.T) {
+	problem, err := offers.ValidateProblem(signatureProblem("[2, 7, 11]\n9\n", " [0, 1]\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if problem.Signature != "twoSum(nums int[], target int) int[]" {
+		t.Errorf("unexpected signature %q", problem.Signature)
+	}
+	if problem.TestCases[0].Input != "[2,7,11]\n9" || problem.TestCases[0].Output != "[0,1]" {
+		t.Errorf("expected normalized test case, got %+v", problem.TestCases[0])
+	}
+	if problem.Examples[0].Input != "[3,3]\n6" || problem.Examples[0].Output != "[0,1]" {
+		t.Errorf("expected normalized example, got %+v", problem.Examples[0])
+	}
+}
+
+func TestValidateProblemSignatureBadCase(t *testing.T) {
+	if _, err := offers.ValidateProblem(signatureProblem("[2, 7, 11]", "[0, 1]")); err == nil {
+		t.Error("expected error for a missing parameter")
+	}
+	if _, err := offers.ValidateProblem(signatureProblem("[2, 7, 11]\n9", "0 1")); err == nil {
+		t.Error("expected error for an output that isn't JSON")
+	}
+	bad := signatureProblem("[2, 7, 11]\n9", "[0, 1]")
+	bad.Signature = "twoSum(nums float[]) int"
+	if _, err := offers.ValidateProblem(bad); err == nil {
+		t.Error("expected error for an unknown type")
+	}
+}
+
+func sqlProblem(order string) offers.ProblemJ {
+	return offers.ProblemJ{
+		Title:       "Employees",
+		Description: "List the employees of each department",
+		TimeLimit:   "1000",
+		SQLSchema:   "CREATE TABLE emp(name TEXT, dept TEXT);\n",
+		SQLSeed:     "INSERT INTO emp VALUES ('ana', 'x');",
+		SQLOrder:    order,
+		TestCases:   []offers.TestCaseJ{{Input: "INSERT INTO emp VALUES ('bo', NULL);", Output: "bo|NULL\nana|x  \n"}},
+		Examples:    []offers.ExampleJ{{Input: "SELECT 1;", Output: "ana|x"}},
+	}
+}
+
+func TestValidateProblemDatabase(t *testing.T) {
+	problem, err := offers.ValidateProblem(sqlProblem("unordered"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if problem.Database == nil || problem.Database.Ordered || problem.Database.Schema != "CREATE TABLE emp(name TEXT, dept TEXT);" {
+		t.Fatalf("unexpected database %+v", problem.Database)
+	}
+	if problem.TestCases[0].Output != "ana|x\nbo|NULL" {
+		t.Errorf("expected sorted result, got %q", problem.TestCases[0].Output)
+	}
+	problem, err = offers.ValidateProblem(sqlProblem("ordered"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !problem.Database.Ordered || problem.TestCases[0].Output != "bo|NULL\nana|x" {
+		t.Errorf("expected result in the given order, got %q", problem.TestCases[0].Output)
+	}
+}
+
+func TestValidateProblemDatabaseErrors(t *testing.T) {
+	if _, err := offers.ValidateProblem(sqlProblem("")); err == nil {
+		t.Error("expected error for a missing row order")
+	}
+	bad := sqlProblem("ordered")
+	bad.Signature = "f(a int) int"
+	if _, err := offers.ValidateProblem(bad); err == nil {
+		t.Error("expected error for a SQL problem with a signature")
+	}
+	problem, err := offers.ValidateProblem(signatureProblem("[2, 7, 11]\n9", "[0, 1]"))
+	if err != nil || problem.Database != nil {
+		t.Errorf("expected a problem without database, got %+v, %v", problem.Database, err)
+	}
+}
+
+func questionProblem(kind string) offers.ProblemJ {
+	return offers.ProblemJ{
+		Title:       "Pregunta",
+		Description: "¿Qué complejidad tiene la búsqueda binaria?",
+		Kind:        kind,
+		Points:      "10",
+	}
+}
+
+func TestValidateProblemQuestion(t *testing.T) {
+	p := questionProblem(shared.QuestionSingle)
+	p.Options = "O(n)\n*O(log n)\n\nO(1)\n"
+	problem, err := offers.ValidateProblem(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if problem.Question == nil || len(problem.Question.Options) != 3 || !problem.Question.Options[1].Correct {
+		t.Fatalf("unexpected question %+v", problem.Question)
+	}
+	if problem.Question.Options[1].Label != "O(log n)" || len(problem.TestCases) != 0 {
+		t.Errorf("unexpected problem %+v", problem)
+	}
+	p = questionProblem(shared.QuestionNumeric)
+	p.Answers = "3,14"
+	p.Tolerance = "0.01"
+	problem, err = offers.ValidateProblem(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if problem.Question.Tolerance != 0.01 || problem.Question.Answers[0] != "3,14" {
+		t.Errorf("unexpected question %+v", problem.Question)
+	}
+	p = questionProblem(shared.QuestionText)
+	p.Rubric = "Menciona que divide el rango a la mitad"
+	if problem, err = offers.ValidateProblem(p); err != nil || problem.Question.Rubric != p.Rubric {
+		t.Errorf("unexpected question %+v, %v", problem.Question, err)
+	}
+}
+
+func TestValidateProblemQuestionErrors(t *testing.T) {
+	cases := map[string]offers.ProblemJ{}
+	p := questionProblem("essay")
+	cases["unknown kind"] = p
+	p = questionProblem(shared.QuestionSingle)
+	p.Options = "*a\n*b"
+	cases["two correct options"] = p
+	p = questionProblem(shared.QuestionMultiple)
+	p.Options = "a\nb"
+	cases["no correct option"] = p
+	p = questionProblem(shared.QuestionMultiple)
+	p.Options = "*a"
+	cases["one option"] = p
+	p = questionProblem(shared.QuestionNumeric)
+	p.Answers = "tres"
+	cases["not a number"] = p
+	p = questionProblem(shared.QuestionShort)
+	cases["no answers"] = p
+	p = questionProblem(shared.QuestionText)
+	p.Points = "0"
+	cases["no points"] = p
+	p = questionProblem(shared.QuestionText)
+	p.Signature = "f(a int) int"
+	cases["signature"] = p
+	for name, p := range cases {
+		if _, err := offers.ValidateProblem(p); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestValidatePools(t *testing.T) {
+	pools, err := offers.ValidatePools([]offers.PoolJ{
+		{Tag: " Grafos ", Difficulty: "3", Draw: "1"},
+		{Draw: ""},
+		{Difficulty: "0", Draw: "2"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pools) != 2 {
+		t.Fatalf("expected empty rows to be skipped, got %+v", pools)
+	}
+	if pools[0] != (shared.Pool{Tag: "grafos", Difficulty: shared.DifficultyHard, Draw: 1}) {
+		t.Errorf("unexpected pool %+v", pools[0])
+	}
+	if pools[1] != (shared.Pool{Difficulty: shared.DifficultyAny, Draw: 2}) {
+		t.Errorf("unexpected pool %+v", pools[1])
+	}
+}
+
+func TestValidatePoolsErrors(t *testing.T) {
+	cases := map[string][]offers.PoolJ{
+		"zero draw":          {{Draw: "0"}},
+		"not a number":       {{Draw: "dos"}},
+		"unknown difficulty": {{Draw: "1", Difficulty: "4"}},
+		"too many problems":  {{Draw: "3"}, {Draw: "3"}},
+		"long tag":           {{Draw: "1", Tag: strings.Repeat("a", 33)}},
+	}
+	for name, rows := range cases {
+		if _, err := offers.ValidatePools(rows); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestValidateOfferAttributes(t *testing.T) {
+	offer, err := offers.ValidateOfferAttributes(offers.OfferJ{
+		Location:  " La Paz ",
+		Seniority: "junior",
+		Tags:      []string{"62", "71", "62"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if offer.Location != "La Paz" || offer.Modality != shared.ModalityOnsite ||
+		offer.Currency != "USD" || offer.WagePeriod != shared.WageYearly {
+		t.Errorf("unexpected defaults %+v", offer)
+	}
+	if len(offer.Tags) != 2 || !offer.HasTag(62) || !offer.HasTag(71) {
+		t.Errorf("expected repeated tags to be dropped, got %+v", offer.Tags)
+	}
+}
+
+func TestValidateOfferAttributesErrors(t *testing.T) {
+	cases := map[string]offers.OfferJ{
+		"long location": {Location: strings.Repeat("a", 101)},
+		"modality":      {Modality: "space"},
+		"seniority":     {Seniority: "guru"},
+		"contract":      {Contract: "forever"},
+		"currency":      {Currency: "XXX"},
+		"wage period":   {WagePeriod: "week"},
+		"tag":           {Tags: []string{"go"}},
+		"too many tags": {Tags: strings.Split("1,2,3,4,5,6,7,8,9", ",")},
+	}
+	for name, o := range cases {
+		if _, err := offers.ValidateOfferAttributes(o); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestValidateProblemDifficulty(t *testing.T) {
+	p := questionProblem(shared.QuestionText)
+	problem, err := offers.ValidateProblem(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if problem.Difficulty != shared.DifficultyMedium || problem.Tag != "" {
+		t.Errorf("expected a medium problem without tag, got %d %q", problem.Difficulty, problem.Tag)
+	}
+	p.Difficulty, p.Tag = "1", "Búsqueda"
+	if problem, err = offers.ValidateProblem(p); err != nil || problem.Difficulty != shared.DifficultyEasy || problem.Tag != "búsqueda" {
+		t.Errorf("unexpected problem %d %q, %v", problem.Difficulty, problem.Tag, err)
+	}
+	p.Difficulty = "0"
+	if _, err := offers.ValidateProblem(p); err == nil {
+		t.Error("expected error for a problem without difficulty")
+	}
+}
+
+func generatedProblem(seeds string) offers.ProblemJ {
+	return offers.ProblemJ{
+		Title:             "Suma grande",
+		Description:       "Suma todos los números de la entrada",
+		TimeLimit:         "1000",
+		TestCases:         []offers.TestCaseJ{{Input: strings.Repeat("1 ", 20000), Output: "20000"}},
+		Examples:          []offers.ExampleJ{{Input: "1 2", Output: "3"}},
+		GeneratorLanguage: "71",
+		Generator:         "import sys\nprint(' '.join(['1'] * int(input())))",
+		GeneratorSolution: "print(len(input().split()))",
+		GeneratorSeeds:    seeds,
+	}
+}
+
+func TestValidateProblemGenerator(t *testing.T) {
+	problem, err := offers.ValidateProblem(generatedProblem("10\n\n 100000 \n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if problem.Generator == nil || problem.Generator.LanguageID != 71 {
+		t.Fatalf("unexpected generator %+v", problem.Generator)
+	}
+	if len(problem.Generator.Seeds) != 2 || problem.Generator.Seeds[1] != "100000" {
+		t.Errorf("unexpected seeds %q", problem.Generator.Seeds)
+	}
+	p := generatedProblem("")
+	p.Generator = ""
+	if problem, err := offers.ValidateProblem(p); err != nil || problem.Generator != nil {
+		t.Errorf("expected a problem without generator, got %+v, %v", problem.Generator, err)
+	}
+}
+
+func TestValidateProblemGeneratorErrors(t *testing.T) {
+	cases := map[string]func(p *offers.ProblemJ){
+		"no seeds":      func(p *offers.ProblemJ) { p.GeneratorSeeds = " \n" },
+		"many seeds":    func(p *offers.ProblemJ) { p.GeneratorSeeds = strings.Repeat("1\n", 21) },
+		"no solution":   func(p *offers.ProblemJ) { p.GeneratorSolution = "" },
+		"bad language":  func(p *offers.ProblemJ) { p.GeneratorLanguage = "python" },
+		"signature":     func(p *offers.ProblemJ) { p.Signature = "f(a int) int" },
+		"long seed":     func(p *offers.ProblemJ) { p.GeneratorSeeds = strings.Repeat("1", 256) },
+		"too many test": func(p *offers.ProblemJ) { p.TestCases = make([]offers.TestCaseJ, 25) },
+	}
+	for name, change := range cases {
+		p := generatedProblem("1\n2")
+		change(&p)
+		if _, err := offers.ValidateProblem(p); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
+
+func TestValidateProblemStoredTestData(t *testing.T) {
+	hash := strings.Repeat("ab", 32)
+	p := generatedProblem("")
+	p.Generator = ""
+	p.TestCases = []offers.TestCaseJ{{Input: "1 1 1", Output: "3", InputHash: hash}}
+	problem, err := offers.ValidateProblem(p)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if problem.TestCases[0].InputHash != hash || problem.TestCases[0].OutputHash != "" {
+		t.Errorf("expected the stored input, got %+v", problem.TestCases[0])
+	}
+	p.TestCases[0].InputHash = "../../etc/passwd"
+	if _, err := offers.ValidateProblem(p); err == nil {
+		t.Error("expected error for an invalid hash")
+	}
+	p.TestCases[0] = offers.TestCaseJ{Input: strings.Repeat("1", 65536), Output: "1"}
+	if _, err := offers.ValidateProblem(p); err == nil {
+		t.Error("expected error for a test case longer than the form accepts")
+	}
+	sql := sqlProblem("ordered")
+	sql.TestCases[0].Input = strings.Repeat("1", shared.InlineTestDataSize+1)
+	if _, err := offers.ValidateProblem(sql); err == nil {
+		t.Error("expected error for a large SQL test case")
+	}
+}
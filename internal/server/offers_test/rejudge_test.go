@@ -0,0 +1,219 @@
+package offerstest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/codejudge"
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type rejudgeStorage struct {
+	mock.Mock
+}
+
+func (s *rejudgeStorage) CreateRejudge(ctx context.Context, userID string, offerID string, req shared.RejudgeRequest) (string, []shared.RejudgeEntry, error) {
+	args := s.Called(ctx, userID, offerID, req)
+	return args.String(0), args.Get(1).([]shared.RejudgeEntry), args.Error(2)
+}
+func (s *rejudgeStorage) SelectRejudge(ctx context.Context, rejudgeID, userID string) (shared.Rejudge, error) {
+	args := s.Called(ctx, rejudgeID, userID)
+	return args.Get(0).(shared.Rejudge), args.Error(1)
+}
+func (s *rejudgeStorage) GetTestCases(ctx context.Context, problemID string) ([]codejudge.TestCase, error) {
+	args := s.Called(ctx, problemID)
+	return args.Get(0).([]codejudge.TestCase), args.Error(1)
+}
+func (s *rejudgeStorage) UpdateRejudgeError(ctx context.Context, rejudgeID, submissionID, msg string) error {
+	args := s.Called(ctx, rejudgeID, submissionID, msg)
+	return args.Error(0)
+}
+
+type rejudgeDispatcher struct {
+	mock.Mock
+}
+
+func (d *rejudgeDispatcher) Dispatch(rejudgeID string, entries []shared.RejudgeEntry) {
+	d.Called(rejudgeID, entries)
+}
+
+type judgeSender struct {
+	mock.Mock
+}
+
+func (j *judgeSender) Send(testCases []codejudge.TestCase, submission codejudge.Submission) ([]string, error) {
+	args := j.Called(testCases, submission)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func rejudgeInputFn(r *http.Request) (offers.RejudgeInput, error) {
+	return offers.RejudgeInput{
+		OfferID: "offer-id",
+		Request: shared.RejudgeRequest{Scope: shared.RejudgeProblem, TargetID: "problem-id"},
+	}, nil
+}
+
+func TestGetRejudgeInput(t *testing.T) {
+	id := "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	other := "0b7e1b8e-8d2c-4f5c-9e2a-3c1d2e4f5a6b"
+
+	req, _ := http.NewRequest("POST", "/", nil)
+	req = WithUrlParam(req, "submissionID", id)
+	input, err := offers.GetRejudgeInput(req)
+	if err != nil || input.Request.Scope != shared.RejudgeSubmission || input.Request.TargetID != id {
+		t.Errorf("unexpected submission input %v, %v", input, err)
+	}
+
+	req, _ = http.NewRequest("POST", "/", nil)
+	req = WithUrlParam(req, "participationID", id)
+	input, err = offers.GetRejudgeInput(req)
+	if err != nil || input.Request.Scope != shared.RejudgeParticipation {
+		t.Errorf("unexpected participation input %v, %v", input, err)
+	}
+
+	req, _ = http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{"latest": {"on"}}
+	req = WithUrlParams(req, Params{"offerID": id, "problemID": other})
+	input, err = offers.GetRejudgeInput(req)
+	if err != nil || input.Request.Scope != shared.RejudgeProblem || input.OfferID != id || !input.Request.Latest {
+		t.Errorf("unexpected problem input %v, %v", input, err)
+	}
+
+	req, _ = http.NewRequest("POST", "/", nil)
+	req = WithUrlParams(req, Params{"offerID": id, "problemID": "bad"})
+	if _, err := offers.GetRejudgeInput(req); err == nil {
+		t.Error("expected error for an invalid problem id")
+	}
+}
+
+func TestRejudgeHandlerBadAuth(t *testing.T) {
+	handler := offers.CreateRejudgeHandler(rejudgeInputFn, invalidAuthRepo{}, new(rejudgeStorage), new(rejudgeDispatcher), &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestRejudgeHandlerVisitor(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{Role: auth.NotAuthRole}, nil)
+	handler := offers.CreateRejudgeHandler(rejudgeInputFn, authz, new(rejudgeStorage), new(rejudgeDispatcher), &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestRejudgeHandlerNothingToRejudge(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "1", Role: auth.AuthRole}, nil)
+	storage := new(rejudgeStorage)
+	storage.On("CreateRejudge", mock.Anything, "1", "offer-id", mock.Anything).Return("", []shared.RejudgeEntry{}, shared.ErrNothingToRejudge)
+	dispatcher := new(rejudgeDispatcher)
+	handler := offers.CreateRejudgeHandler(rejudgeInputFn, authz, storage, dispatcher, &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	dispatcher.AssertNotCalled(t, "Dispatch", mock.Anything, mock.Anything)
+}
+
+func TestRejudgeHandlerNotOwner(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "1", Role: auth.AuthRole}, nil)
+	storage := new(rejudgeStorage)
+	storage.On("CreateRejudge", mock.Anything, "1", "offer-id", mock.Anything).Return("", []shared.RejudgeEntry{}, sql.ErrNoRows)
+	handler := offers.CreateRejudgeHandler(rejudgeInputFn, authz, storage, new(rejudgeDispatcher), &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestRejudgeHandlerBadStorage(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "1", Role: auth.AuthRole}, nil)
+	storage := new(rejudgeStorage)
+	storage.On("CreateRejudge", mock.Anything, "1", "offer-id", mock.Anything).Return("", []shared.RejudgeEntry{}, errors.New("error"))
+	handler := offers.CreateRejudgeHandler(rejudgeInputFn, authz, storage, new(rejudgeDispatcher), &templates{})
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestRejudgeHandler(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "1", Role: auth.AuthRole}, nil)
+	entries := []shared.RejudgeEntry{{SubmissionID: "s1"}, {SubmissionID: "s2"}}
+	storage := new(rejudgeStorage)
+	storage.On("CreateRejudge", mock.Anything, "1", "offer-id", shared.RejudgeRequest{Scope: shared.RejudgeProblem, TargetID: "problem-id"}).Return("r", entries, nil)
+	storage.On("SelectRejudge", mock.Anything, "r", "1").Return(shared.Rejudge{ID: "r"}, nil)
+	dispatcher := new(rejudgeDispatcher)
+	dispatcher.On("Dispatch", "r", entries).Return()
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "rejudgeProgress", shared.Rejudge{ID: "r"}).Return(nil)
+	handler := offers.CreateRejudgeHandler(rejudgeInputFn, authz, storage, dispatcher, templ)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	dispatcher.AssertExpectations(t)
+	templ.AssertExpectations(t)
+}
+
+func TestRejudgeProgressHandlerNotFound(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{ID: "1", Role: auth.AuthRole}, nil)
+	storage := new(rejudgeStorage)
+	storage.On("SelectRejudge", mock.Anything, "r", "1").Return(shared.Rejudge{}, sql.ErrNoRows)
+	inputFn := func(r *http.Request) (offers.RejudgeProgressInput, error) {
+		return offers.RejudgeProgressInput{RejudgeID: "r"}, nil
+	}
+	handler := offers.CreateRejudgeProgressHandler(inputFn, authz, storage, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestSendRejudge(t *testing.T) {
+	storage := new(rejudgeStorage)
+	storage.On("GetTestCases", mock.Anything, "p1").Return([]codejudge.TestCase{{ID: "tc1"}}, nil).Once()
+	storage.On("GetTestCases", mock.Anything, "p2").Return([]codejudge.TestCase{}, errors.New("no test cases"))
+	storage.On("UpdateRejudgeError", mock.Anything, "r", mock.Anything, mock.Anything).Return(nil)
+	judge := new(judgeSender)
+	judge.On("Send", mock.Anything, codejudge.Submission{ID: "s1", Src: "a", LanguageID: 71}).Return([]string{"t"}, nil)
+	judge.On("Send", mock.Anything, codejudge.Submission{ID: "s2", Src: "b", LanguageID: 71}).Return([]string{}, errors.New("judge down"))
+	entries := []shared.RejudgeEntry{
+		{SubmissionID: "s1", ProblemID: "p1", Src: "a", LanguageID: 71},
+		{SubmissionID: "s2", ProblemID: "p1", Src: "b", LanguageID: 71},
+		{SubmissionID: "s3", ProblemID: "p2", Src: "c", LanguageID: 71},
+	}
+	offers.SendRejudge(context.Background(), storage, judge, "r", entries, 2)
+	judge.AssertNumberOfCalls(t, "Send", 2)
+	storage.AssertCalled(t, "UpdateRejudgeError", mock.Anything, "r", "s2", "judge down")
+	storage.AssertCalled(t, "UpdateRejudgeError", mock.Anything, "r", "s3", "no test cases")
+	storage.AssertNotCalled(t, "UpdateRejudgeError", mock.Anything, "r", "s1", mock.Anything)
+}
@@ -0,0 +1,345 @@
+package offerstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type reviewStorage struct {
+	mock.Mock
+}
+
+func (s *reviewStorage) ReviewApplicants(ctx context.Context, recruiterID string, participationIDs []string, review shared.Review) error {
+	args := s.Called(ctx, recruiterID, participationIDs, review)
+	return args.Error(0)
+}
+func (s *reviewStorage) SelectPipeline(ctx context.Context, participationID string) (shared.Pipeline, error) {
+	args := s.Called(ctx, participationID)
+	return args.Get(0).(shared.Pipeline), args.Error(1)
+}
+
+func reviewInputFn(r *http.Request) (offers.ReviewApplicantInput, error) {
+	return offers.ReviewApplicantInput{
+		ParticipationID: "part-id",
+		Review:          shared.Review{Action: shared.ReviewStage, Stage: shared.StageInterview},
+	}, nil
+}
+
+func okNotifier() *notifier {
+	n := new(notifier)
+	n.On("Notify", mock.Anything, mock.Anything).Return(nil)
+	return n
+}
+
+func bulkReviewInputFn(r *http.Request) (offers.BulkReviewInput, error) {
+	return offers.BulkReviewInput{
+		OfferID:          "offer-id",
+		ParticipationIDs: []string{"part-id", "other-id"},
+		Review:           shared.Review{Action: shared.ReviewTag, Tag: "backend"},
+	}, nil
+}
+
+func TestGetReviewApplicantInput(t *testing.T) {
+	cases := map[string]struct {
+		form     map[string][]string
+		expected shared.Review
+	}{
+		"stage": {
+			form:     map[string][]string{"action": {"stage"}, "stage": {"interview"}},
+			expected: shared.Review{Action: shared.ReviewStage, Stage: shared.StageInterview},
+		},
+		"rating": {
+			form:     map[string][]string{"action": {"rating"}, "stars": {"4"}},
+			expected: shared.Review{Action: shared.ReviewRating, Stars: 4},
+		},
+		"note": {
+			form:     map[string][]string{"action": {"note"}, "body": {"  Buena comunicación  "}},
+			expected: shared.Review{Action: shared.ReviewNote, Body: "Buena comunicación"},
+		},
+		"tag": {
+			form:     map[string][]string{"action": {"tag"}, "tag": {" Backend "}},
+			expected: shared.Review{Action: shared.ReviewTag, Tag: "backend"},
+		},
+	}
+	for name, c := range cases {
+		req, _ := http.NewRequest("POST", "/", nil)
+		req.Form = c.form
+		req = WithUrlParam(req, "participationID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+		input, err := offers.GetReviewApplicantInput(req)
+		if err != nil {
+			t.Errorf("%s: expected nil, got %v", name, err)
+			continue
+		}
+		if input.Review != c.expected {
+			t.Errorf("%s: expected %v, got %v", name, c.expected, input.Review)
+		}
+	}
+}
+
+func TestGetReviewApplicantInputErrors(t *testing.T) {
+	cases := map[string]map[string][]string{
+		"action": {"action": {"hire"}},
+		"stage":  {"action": {"stage"}},
+		"stars":  {"action": {"rating"}, "stars": {"6"}},
+		"note":   {"action": {"note"}, "body": {"   "}},
+		"tag":    {"action": {"tag"}, "tag": {"una etiqueta demasiado larga para guardarse"}},
+	}
+	for name, form := range cases {
+		req, _ := http.NewRequest("POST", "/", nil)
+		req.Form = form
+		req = WithUrlParam(req, "participationID", "f47ac10b-58cc-4372-a567-0e02b2c3d479")
+		if _, err := offers.GetReviewApplicantInput(req); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
+
+func TestGetBulkReviewInput(t *testing.T) {
+	ids := []string{"f47ac10b-58cc-4372-a567-0e02b2c3d479", "a47ac10b-58cc-4372-a567-0e02b2c3d479"}
+	req, _ := http.NewRequest("POST", "/", nil)
+	req.Form = map[string][]string{"action": {"stage"}, "stage": {"rejected"}, "participationID": ids}
+	req = WithUrlParam(req, "offerID", "b47ac10b-58cc-4372-a567-0e02b2c3d479")
+	input, err := offers.GetBulkReviewInput(req)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if len(input.ParticipationIDs) != 2 || input.Review.Stage != shared.StageRejected {
+		t.Errorf("unexpected input %v", input)
+	}
+}
+
+func TestGetBulkReviewInputErrors(t *testing.T) {
+	id := "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	cases := map[string]map[string][]string{
+		"empty":   {"action": {"stage"}, "stage": {"rejected"}},
+		"invalid": {"action": {"stage"}, "stage": {"rejected"}, "participationID": {"1"}},
+		"note":    {"action": {"note"}, "body": {"nota"}, "participationID": {id}},
+	}
+	for name, form := range cases {
+		req, _ := http.NewRequest("POST", "/", nil)
+		req.Form = form
+		req = WithUrlParam(req, "offerID", "b47ac10b-58cc-4372-a567-0e02b2c3d479")
+		if _, err := offers.GetBulkReviewInput(req); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
+
+func TestReviewApplicantBadAuth(t *testing.T) {
+	handler := offers.CreateReviewApplicantHandler(reviewInputFn, invalidAuthRepo{}, new(reviewStorage), &templates{}, new(notifier))
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestReviewApplicantVisitor(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{Role: auth.NotAuthRole}, nil)
+	handler := offers.CreateReviewApplicantHandler(reviewInputFn, authz, new(reviewStorage), &templates{}, new(notifier))
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestReviewApplicantBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (offers.ReviewApplicantInput, error) {
+		return offers.ReviewApplicantInput{}, errors.New("error")
+	}
+	handler := offers.CreateReviewApplicantHandler(invalidInputFn, authRepo{}, new(reviewStorage), &templates{}, new(notifier))
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestReviewApplicantRuleViolation(t *testing.T) {
+	storage := new(reviewStorage)
+	storage.On("ReviewApplicants", mock.Anything, mock.Anything, []string{"part-id"}, mock.Anything).Return(shared.ErrStage)
+	handler := offers.CreateReviewApplicantHandler(reviewInputFn, authRepo{}, storage, &templates{}, new(notifier))
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("HX-Retarget") != "#review-alert-part-id" {
+		t.Errorf("unexpected retarget %s", w.Header().Get("HX-Retarget"))
+	}
+}
+
+func TestReviewApplicantBadStorage(t *testing.T) {
+	storage := new(reviewStorage)
+	storage.On("ReviewApplicants", mock.Anything, mock.Anything, []string{"part-id"}, mock.Anything).Return(errors.New("error"))
+	handler := offers.CreateReviewApplicantHandler(reviewInputFn, authRepo{}, storage, &templates{}, new(notifier))
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestReviewApplicantBadStoragePipeline(t *testing.T) {
+	storage := new(reviewStorage)
+	storage.On("ReviewApplicants", mock.Anything, mock.Anything, []string{"part-id"}, mock.Anything).Return(nil)
+	storage.On("SelectPipeline", mock.Anything, "part-id").Return(shared.Pipeline{}, errors.New("error"))
+	handler := offers.CreateReviewApplicantHandler(reviewInputFn, authRepo{}, storage, &templates{}, okNotifier())
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestReviewApplicantBadTemplate(t *testing.T) {
+	storage := new(reviewStorage)
+	storage.On("ReviewApplicants", mock.Anything, mock.Anything, []string{"part-id"}, mock.Anything).Return(nil)
+	storage.On("SelectPipeline", mock.Anything, "part-id").Return(shared.Pipeline{}, nil)
+	handler := offers.CreateReviewApplicantHandler(reviewInputFn, authRepo{}, storage, &invalidTemplates{}, okNotifier())
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestReviewApplicantHandler(t *testing.T) {
+	storage := new(reviewStorage)
+	storage.On("ReviewApplicants", mock.Anything, mock.Anything, []string{"part-id"},
+		shared.Review{Action: shared.ReviewStage, Stage: shared.StageInterview}).Return(nil)
+	storage.On("SelectPipeline", mock.Anything, "part-id").Return(shared.Pipeline{ParticipationID: "part-id"}, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "applicantPipeline", shared.Pipeline{
+		ParticipationID: "part-id",
+		Alert:           shared.Alert{Ok: true, Msg: shared.MsgSaved},
+	}).Return(nil)
+	notifier := new(notifier)
+	notifier.On("Notify", mock.Anything, shared.NotificationEvent{
+		Category:        shared.NotifyStatus,
+		ParticipationID: "part-id",
+		Stage:           shared.StageInterview,
+	}).Return(nil)
+	handler := offers.CreateReviewApplicantHandler(reviewInputFn, authRepo{}, storage, templ, notifier)
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+	templ.AssertExpectations(t)
+	notifier.AssertExpectations(t)
+}
+
+func TestReviewApplicantReveal(t *testing.T) {
+	storage := new(reviewStorage)
+	storage.On("ReviewApplicants", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	storage.On("SelectPipeline", mock.Anything, "part-id").Return(shared.Pipeline{
+		ParticipationID: "part-id",
+		Stage:           shared.StageInterview,
+		Reveal: shared.IdentityReveal{
+			Stage:     shared.StageInterview,
+			Reason:    shared.RevealReasonStage,
+			CreatedAt: time.Now(),
+		},
+	}, nil)
+	handler := offers.CreateReviewApplicantHandler(reviewInputFn, authRepo{}, storage, &templates{}, okNotifier())
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Header().Get("HX-Refresh") != "true" {
+		t.Error("expected the page to be loaded again to show the applicant")
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestBulkReviewBadAuth(t *testing.T) {
+	handler := offers.CreateBulkReviewHandler(bulkReviewInputFn, invalidAuthRepo{}, new(reviewStorage), &templates{}, new(notifier), "/offers/admin/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestBulkReviewBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (offers.BulkReviewInput, error) {
+		return offers.BulkReviewInput{}, errors.New("error")
+	}
+	handler := offers.CreateBulkReviewHandler(invalidInputFn, authRepo{}, new(reviewStorage), &templates{}, new(notifier), "/offers/admin/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestBulkReviewEmptySelection(t *testing.T) {
+	emptyInputFn := func(r *http.Request) (offers.BulkReviewInput, error) {
+		return offers.BulkReviewInput{}, shared.ErrNoApplicants
+	}
+	storage := new(reviewStorage)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "reviewAlert", shared.Alert{Ok: false, Msg: shared.ErrNoApplicants.Error()}).Return(nil)
+	handler := offers.CreateBulkReviewHandler(emptyInputFn, authRepo{}, storage, templ, new(notifier), "/offers/admin/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+	storage.AssertNotCalled(t, "ReviewApplicants", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBulkReviewBadStorage(t *testing.T) {
+	storage := new(reviewStorage)
+	storage.On("ReviewApplicants", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error"))
+	handler := offers.CreateBulkReviewHandler(bulkReviewInputFn, authRepo{}, storage, &templates{}, new(notifier), "/offers/admin/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestBulkReviewHandler(t *testing.T) {
+	storage := new(reviewStorage)
+	storage.On("ReviewApplicants", mock.Anything, mock.Anything, []string{"part-id", "other-id"},
+		shared.Review{Action: shared.ReviewTag, Tag: "backend"}).Return(nil)
+	handler := offers.CreateBulkReviewHandler(bulkReviewInputFn, authRepo{}, storage, &templates{}, new(notifier), "/offers/admin/")
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("HX-Redirect") != "/offers/admin/offer-id" {
+		t.Errorf("unexpected redirect %s", w.Header().Get("HX-Redirect"))
+	}
+	storage.AssertExpectations(t)
+}
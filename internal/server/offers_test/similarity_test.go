@@ -0,0 +1,173 @@
+package offerstest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/offers"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type similarityStorage struct {
+	mock.Mock
+}
+
+func (s *similarityStorage) SelectOfferByUser(ctx context.Context, id string, userID string) (shared.Offer, error) {
+	args := s.Called(ctx, id, userID)
+	return args.Get(0).(shared.Offer), args.Error(1)
+}
+func (s *similarityStorage) SelectQuizByOffer(ctx context.Context, offerID string) (shared.Quiz, error) {
+	args := s.Called(ctx, offerID)
+	return args.Get(0).(shared.Quiz), args.Error(1)
+}
+func (s *similarityStorage) SelectApplications(ctx context.Context, quizID string) ([]shared.Application, error) {
+	args := s.Called(ctx, quizID)
+	return args.Get(0).([]shared.Application), args.Error(1)
+}
+func (s *similarityStorage) SelectPriorSubmissions(ctx context.Context, quizID string) ([]shared.SimilarityEntry, error) {
+	args := s.Called(ctx, quizID)
+	return args.Get(0).([]shared.SimilarityEntry), args.Error(1)
+}
+
+func (s *similarityStorage) SelectBlindReview(ctx context.Context, offerID string) (shared.BlindReview, error) {
+	args := s.Called(ctx, offerID)
+	return args.Get(0).(shared.BlindReview), args.Error(1)
+}
+
+func similarityInputFn(r *http.Request) (offers.SimilarityInput, error) {
+	return offers.SimilarityInput{}, nil
+}
+
+func priorSimilarityInputFn(r *http.Request) (offers.SimilarityInput, error) {
+	return offers.SimilarityInput{Prior: true}, nil
+}
+
+func TestGetSimilarityInput(t *testing.T) {
+	offerID := "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	req, _ := http.NewRequest("GET", "/?prior=true", nil)
+	req = WithUrlParam(req, "offerID", offerID)
+	input, err := offers.GetSimilarityInput(req)
+	if err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+	if !input.Prior || input.OfferID != offerID {
+		t.Errorf("unexpected input %v", input)
+	}
+}
+
+func TestSimilarityBadAuth(t *testing.T) {
+	storage := new(similarityStorage)
+	handler := offers.CreateSimilarityHandler(similarityInputFn, invalidAuthRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestSimilarityVisitor(t *testing.T) {
+	authz := new(authMock)
+	authz.On("GetUser", mock.Anything).Return(auth.AuthUser{Role: auth.NotAuthRole}, nil)
+	storage := new(similarityStorage)
+	handler := offers.CreateSimilarityHandler(similarityInputFn, authz, storage, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestSimilarityBadInput(t *testing.T) {
+	invalidInputFn := func(r *http.Request) (offers.SimilarityInput, error) {
+		return offers.SimilarityInput{}, errors.New("error")
+	}
+	storage := new(similarityStorage)
+	handler := offers.CreateSimilarityHandler(invalidInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestSimilarityNotOwner(t *testing.T) {
+	storage := new(similarityStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, errors.New("error"))
+	handler := offers.CreateSimilarityHandler(similarityInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestSimilarityBadStorageApplications(t *testing.T) {
+	storage := new(similarityStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
+	storage.On("SelectApplications", mock.Anything, mock.Anything).Return([]shared.Application{}, errors.New("error"))
+	handler := offers.CreateSimilarityHandler(similarityInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestSimilarityBadStoragePrior(t *testing.T) {
+	storage := new(similarityStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
+	storage.On("SelectApplications", mock.Anything, mock.Anything).Return([]shared.Application{}, nil)
+	storage.On("SelectBlindReview", mock.Anything, mock.Anything).Return(shared.BlindReview{}, nil)
+	storage.On("SelectPriorSubmissions", mock.Anything, mock.Anything).Return([]shared.SimilarityEntry{}, errors.New("error"))
+	handler := offers.CreateSimilarityHandler(priorSimilarityInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestSimilarityBadTemplate(t *testing.T) {
+	storage := new(similarityStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
+	storage.On("SelectApplications", mock.Anything, mock.Anything).Return([]shared.Application{}, nil)
+	storage.On("SelectBlindReview", mock.Anything, mock.Anything).Return(shared.BlindReview{}, nil)
+	handler := offers.CreateSimilarityHandler(similarityInputFn, authRepo{}, storage, &invalidTemplates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestSimilarityHandler(t *testing.T) {
+	storage := new(similarityStorage)
+	storage.On("SelectOfferByUser", mock.Anything, mock.Anything, mock.Anything).Return(shared.Offer{}, nil)
+	storage.On("SelectQuizByOffer", mock.Anything, mock.Anything).Return(shared.Quiz{}, nil)
+	storage.On("SelectApplications", mock.Anything, mock.Anything).Return([]shared.Application{}, nil)
+	storage.On("SelectBlindReview", mock.Anything, mock.Anything).Return(shared.BlindReview{}, nil)
+	storage.On("SelectPriorSubmissions", mock.Anything, mock.Anything).Return([]shared.SimilarityEntry{}, nil)
+	handler := offers.CreateSimilarityHandler(priorSimilarityInputFn, authRepo{}, storage, &templates{})
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertCalled(t, "SelectPriorSubmissions", mock.Anything, mock.Anything)
+}
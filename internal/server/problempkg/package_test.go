@@ -0,0 +1,178 @@
+package problempkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+func file(content string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(content)}
+}
+
+func TestReadKattisPackage(t *testing.T) {
+	fsys := fstest.MapFS{
+		"problem.yaml":                        file("name:\n  en: Two Sum\n  es: Suma de dos\nlimits:\n  memory: 512\nvalidation: custom\n"),
+		".timelimit":                          file("2\n"),
+		"problem_statement/problem.en.tex":    file("Add two numbers"),
+		"data/sample/1.in":                    file("1 2\n"),
+		"data/sample/1.ans":                   file("3\n"),
+		"data/secret/group1/02.in":            file("5 5\n"),
+		"data/secret/group1/02.ans":           file("10\n"),
+		"data/secret/01.in":                   file("2 2\n"),
+		"data/secret/01.ans":                  file("4\n"),
+		"output_validators/checker/check.cpp": file("int main() {}"),
+	}
+	problems, err := Read(fsys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 1 {
+		t.Fatalf("expected 1 problem, got %d", len(problems))
+	}
+	p := problems[0]
+	if p.Title != "Suma de dos" {
+		t.Errorf("expected spanish name, got %q", p.Title)
+	}
+	if p.TimeLimit != 2000 || p.MemoryLimit != 512*1024 {
+		t.Errorf("unexpected limits %d ms %d kb", p.TimeLimit, p.MemoryLimit)
+	}
+	if len(p.Examples) != 1 || len(p.TestCases) != 2 {
+		t.Fatalf("unexpected data %+v %+v", p.Examples, p.TestCases)
+	}
+	if p.TestCases[0].Input != "2 2\n" {
+		t.Errorf("expected lexicographic order, got %q first", p.TestCases[0].Input)
+	}
+	if len(p.Checker) != 1 || p.Checker[0].Path != "output_validators/checker/check.cpp" {
+		t.Errorf("unexpected checker %+v", p.Checker)
+	}
+}
+
+func TestReadSeveralProblems(t *testing.T) {
+	fsys := fstest.MapFS{
+		"hello/problem.yaml":               file("limits:\n  time_limit: 0.5\n"),
+		"hello/statement/problem.md":       file("Say hello"),
+		"hello/data/secret/1.in":           file(""),
+		"hello/data/secret/1.ans":          file("hello\n"),
+		"bye/problem.yaml":                 file("name: Bye\n"),
+		"bye/problem_statement/problem.md": file("Say bye"),
+		"bye/data/secret/1.in":             file(""),
+		"bye/data/secret/1.ans":            file("bye\n"),
+		"__MACOSX/._hello":                 file(""),
+	}
+	problems, err := Read(fsys)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problems, got %d", len(problems))
+	}
+	if problems[1].Title != "hello" || problems[1].TimeLimit != 500 {
+		t.Errorf("expected directory name and yaml limit, got %q %d", problems[1].Title, problems[1].TimeLimit)
+	}
+	if problems[0].MemoryLimit != defaultMemory*1024 || problems[0].TimeLimit != defaultTimeLimit {
+		t.Errorf("expected default limits, got %d %d", problems[0].MemoryLimit, problems[0].TimeLimit)
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	base := func() fstest.MapFS {
+		return fstest.MapFS{
+			"problem.yaml":                 file("name: Echo\n"),
+			"problem_statement/problem.md": file("Echo the input"),
+			"data/secret/1.in":             file("a"),
+			"data/secret/1.ans":            file("a"),
+		}
+	}
+	if _, err := Read(fstest.MapFS{"readme.md": file("x")}); !errors.Is(err, ErrNoProblem) {
+		t.Errorf("expected ErrNoProblem, got %v", err)
+	}
+	noAnswer := base()
+	delete(noAnswer, "data/secret/1.ans")
+	if _, err := Read(noAnswer); err == nil {
+		t.Error("expected error for missing answer")
+	}
+	noStatement := base()
+	delete(noStatement, "problem_statement/problem.md")
+	if _, err := Read(noStatement); !errors.Is(err, ErrNoStatement) {
+		t.Errorf("expected ErrNoStatement, got %v", err)
+	}
+	noChecker := base()
+	noChecker["problem.yaml"] = file("name: Echo\nvalidation: custom\n")
+	if _, err := Read(noChecker); err == nil {
+		t.Error("expected error for custom validation without checker")
+	}
+	big := base()
+	big["data/secret/1.in"] = &fstest.MapFile{Data: make([]byte, MaxDataSize+1)}
+	if _, err := Read(big); err == nil {
+		t.Error("expected error for oversized test data")
+	}
+	huge := base()
+	data := &fstest.MapFile{Data: make([]byte, MaxDataSize)}
+	for i := 0; i < MaxUnpackedSize/MaxDataSize/2+1; i++ {
+		huge[fmt.Sprintf("data/secret/%d.in", i)] = data
+		huge[fmt.Sprintf("data/secret/%d.ans", i)] = data
+	}
+	if _, err := Read(huge); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+	slow := base()
+	slow["problem.yaml"] = file("name: Echo\nlimits:\n  time_limit: 60\n")
+	if _, err := Read(slow); err == nil {
+		t.Error("expected error for time limit out of range")
+	}
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	problem := shared.Problem{
+		Title:       "Two Sum",
+		Description: "Add two numbers",
+		TimeLimit:   1500,
+		MemoryLimit: 262144,
+		Examples:    []shared.Example{{Input: "1 2", Output: "3"}},
+		TestCases:   []shared.TestCase{{Input: "2 2", Output: "4"}, {Input: "5 5", Output: "10"}},
+		Checker:     []shared.CheckerFile{{Path: "output_validators/check/check.py", Src: "print()"}},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, problem); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	problems, err := ReadZip(buf.Bytes())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got := problems[0]
+	if got.Title != problem.Title || got.Description != problem.Description {
+		t.Errorf("unexpected problem %+v", got)
+	}
+	if got.TimeLimit != problem.TimeLimit || got.MemoryLimit != problem.MemoryLimit {
+		t.Errorf("unexpected limits %d %d", got.TimeLimit, got.MemoryLimit)
+	}
+	if len(got.TestCases) != 2 || got.TestCases[1].Output != "10" || len(got.Examples) != 1 {
+		t.Errorf("unexpected data %+v %+v", got.TestCases, got.Examples)
+	}
+	if len(got.Checker) != 1 || got.Checker[0].Src != "print()" {
+		t.Errorf("unexpected checker %+v", got.Checker)
+	}
+	if _, err := ReadZip([]byte("not a zip")); !errors.Is(err, ErrInvalidZip) {
+		t.Errorf("expected ErrInvalidZip, got %v", err)
+	}
+}
+
+func TestShortName(t *testing.T) {
+	cases := map[string]string{
+		"Two Sum":       "two-sum",
+		"  Año 2024!! ": "a-o-2024",
+		"¿?":            "problem",
+		"already-short": "already-short",
+	}
+	for title, expected := range cases {
+		if got := ShortName(title); got != expected {
+			t.Errorf("ShortName(%q) = %q, expected %q", title, got, expected)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+// Package problempkg reads and writes problem packages in the directory
+// layout used by Kattis and the ICPC problem package format: a problem.yaml
+// with the name and limits, a statement, sample and secret test data under
+// data/ and an optional output validator.
+package problempkg
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	MaxPackageSize   = 32 << 20
+	MaxUnpackedSize  = 128 << 20
+	MaxTestCases     = 100
+	MaxExamples      = 10
+	MaxDataSize      = shared.MaxTestDataSize
+	MaxSampleSize    = 65535
+	MaxStatementSize = 65535
+	MaxTitleLen      = 64
+	MaxCheckerSize   = 1 << 20
+
+	defaultTimeLimit = 1000
+	defaultMemory    = 256
+	minTimeLimit     = 100
+	maxTimeLimit     = 10000
+	minMemory        = 16
+	maxMemory        = 1024
+	maxConfigSize    = 64 << 10
+)
+
+var (
+	ErrNoProblem      = errors.New("el paquete no contiene ningún problem.yaml")
+	ErrInvalidZip     = errors.New("el archivo no es un zip válido")
+	ErrNoStatement    = errors.New("el paquete no contiene un enunciado")
+	ErrNoTestCases    = fmt.Errorf("debe haber entre 1 y %d casos en data/secret", MaxTestCases)
+	ErrTooManySamples = fmt.Errorf("no puede haber más de %d ejemplos en data/sample", MaxExamples)
+	ErrTooLarge       = fmt.Errorf("el paquete descomprimido no puede superar los %d MB", MaxUnpackedSize>>20)
+)
+
+// statementDirs are checked in order, the first one is the legacy Kattis
+// layout and the second the current ICPC one
+var statementDirs = []string{"problem_statement", "statement"}
+
+var statementNames = []string{
+	"problem.es.md", "problem.en.md", "problem.md",
+	"problem.es.tex", "problem.en.tex", "problem.tex",
+}
+
+var checkerDirs = []string{"output_validators", "output_validator"}
+
+type config struct {
+	Name       any    `yaml:"name,omitempty"`
+	Validation string `yaml:"validation,omitempty"`
+	Limits     limits `yaml:"limits,omitempty"`
+}
+
+type limits struct {
+	TimeLimit float64 `yaml:"time_limit,omitempty"`
+	Memory    int32   `yaml:"memory,omitempty"`
+}
+
+// ReadZip reads every problem of a zipped package, the problem can be at
+// the root of the archive or each one in its own directory
+func ReadZip(data []byte) ([]shared.Problem, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrInvalidZip
+	}
+	return Read(zr)
+}
+
+// Read reads a single problem package rooted at fsys, or a directory whose
+// subdirectories are problem packages
+func Read(fsys fs.FS) ([]shared.Problem, error) {
+	fsys = &budgetFS{FS: fsys, left: MaxUnpackedSize}
+	if _, err := fs.Stat(fsys, "problem.yaml"); err == nil {
+		problem, err := readProblem(fsys, "")
+		if err != nil {
+			return nil, err
+		}
+		return []shared.Problem{problem}, nil
+	}
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	problems := []shared.Problem{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := fs.Stat(fsys, path.Join(entry.Name(), "problem.yaml")); err != nil {
+			continue
+		}
+		sub, err := fs.Sub(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		problem, err := readProblem(sub, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		problems = append(problems, problem)
+	}
+	if len(problems) == 0 {
+		return nil, ErrNoProblem
+	}
+	return problems, nil
+}
+
+func readProblem(fsys fs.FS, shortName string) (shared.Problem, error) {
+	raw, err := readFile(fsys, "problem.yaml", maxConfigSize)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	cfg := config{}
+	if err := yaml.Unmarshal([]byte(raw), &cfg); err != nil {
+		return shared.Problem{}, fmt.Errorf("problem.yaml inválido: %w", err)
+	}
+	title := problemName(cfg.Name)
+	if title == "" {
+		title = shortName
+	}
+	if len(title) < 1 || len(title) > MaxTitleLen {
+		return shared.Problem{}, fmt.Errorf("el nombre del problema debe tener entre 1 y %d caracteres", MaxTitleLen)
+	}
+	timeLimit, err := problemTimeLimit(fsys, cfg.Limits)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	memory := cfg.Limits.Memory
+	if memory == 0 {
+		memory = defaultMemory
+	}
+	if memory < minMemory || memory > maxMemory {
+		return shared.Problem{}, fmt.Errorf("el límite de memoria debe estar entre %d y %d MiB", minMemory, maxMemory)
+	}
+	statement, err := readStatement(fsys)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	samples, err := readData(fsys, "data/sample", MaxSampleSize)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	if len(samples) > MaxExamples {
+		return shared.Problem{}, ErrTooManySamples
+	}
+	secret, err := readData(fsys, "data/secret", MaxDataSize)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	if len(secret) < 1 || len(secret) > MaxTestCases {
+		return shared.Problem{}, ErrNoTestCases
+	}
+	checker := []shared.CheckerFile{}
+	if strings.HasPrefix(cfg.Validation, "custom") {
+		if checker, err = readChecker(fsys); err != nil {
+			return shared.Problem{}, err
+		}
+	}
+	problem := shared.Problem{
+		Title:       title,
+		Description: statement,
+		TimeLimit:   timeLimit,
+		MemoryLimit: memory * 1024,
+		Checker:     checker,
+	}
+	for _, pair := range samples {
+		problem.Examples = append(problem.Examples, shared.Example{Input: pair[0], Output: pair[1]})
+	}
+	for _, pair := range secret {
+		problem.TestCases = append(problem.TestCases, shared.TestCase{Input: pair[0], Output: pair[1]})
+	}
+	return problem, nil
+}
+
+// problemName accepts both a plain name and a map of names by language
+func problemName(name any) string {
+	switch n := name.(type) {
+	case string:
+		return strings.TrimSpace(n)
+	case map[string]any:
+		for _, lang := range []string{"es", "en"} {
+			if s, ok := n[lang].(string); ok {
+				return strings.TrimSpace(s)
+			}
+		}
+		keys := make([]string, 0, len(n))
+		for k := range n {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if s, ok := n[k].(string); ok {
+				return strings.TrimSpace(s)
+			}
+		}
+	}
+	return ""
+}
+
+// problemTimeLimit reads the limit in seconds from problem.yaml or from
+// the .timelimit file of legacy packages and returns it in milliseconds
+func problemTimeLimit(fsys fs.FS, l limits) (int32, error) {
+	seconds := l.TimeLimit
+	if seconds == 0 {
+		if raw, err := readFile(fsys, ".timelimit", 64); err == nil {
+			seconds, err = strconv.ParseFloat(strings.TrimSpace(raw), 64)
+			if err != nil {
+				return 0, fmt.Errorf("el archivo .timelimit debe contener un número")
+			}
+		}
+	}
+	if seconds == 0 {
+		return defaultTimeLimit, nil
+	}
+	ms := int32(seconds * 1000)
+	if ms < minTimeLimit || ms > maxTimeLimit {
+		return 0, fmt.Errorf("el límite de tiempo debe estar entre %d y %d ms", minTimeLimit, maxTimeLimit)
+	}
+	return ms, nil
+}
+
+func readStatement(fsys fs.FS) (string, error) {
+	for _, dir := range statementDirs {
+		for _, name := range statementNames {
+			statement, err := readFile(fsys, path.Join(dir, name), MaxStatementSize)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+			if strings.TrimSpace(statement) == "" {
+				return "", ErrNoStatement
+			}
+			return statement, nil
+		}
+	}
+	return "", ErrNoStatement
+}
+
+// readData pairs every .in file under dir with its .ans file, in the
+// lexicographic order the judges use, every file has at most limit bytes
+func readData(fsys fs.FS, dir string, limit int) ([][2]string, error) {
+	pairs := [][2]string{}
+	err := fs.WalkDir(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) && name == dir {
+				return fs.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || path.Ext(name) != ".in" {
+			return nil
+		}
+		input, err := readFile(fsys, name, limit)
+		if err != nil {
+			return err
+		}
+		answer, err := readFile(fsys, strings.TrimSuffix(name, ".in")+".ans", limit)
+		if errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("falta la salida esperada de %s", name)
+		}
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, [2]string{input, answer})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+func readChecker(fsys fs.FS) ([]shared.CheckerFile, error) {
+	files := []shared.CheckerFile{}
+	total := 0
+	for _, dir := range checkerDirs {
+		err := fs.WalkDir(fsys, dir, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if errors.Is(err, fs.ErrNotExist) && name == dir {
+					return fs.SkipDir
+				}
+				return err
+			}
+			if d.IsDir() {
+				return nil
+			}
+			src, err := readFile(fsys, name, MaxCheckerSize-total)
+			if err != nil {
+				return err
+			}
+			total += len(src)
+			files = append(files, shared.CheckerFile{Path: name, Src: src})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("la validación es personalizada pero no hay un verificador")
+	}
+	return files, nil
+}
+
+// readFile fails instead of truncating when the file is larger than max,
+// the sizes in zip headers can not be trusted
+func readFile(fsys fs.FS, name string, max int) (string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, int64(max)+1))
+	if err != nil {
+		return "", err
+	}
+	if len(data) > max {
+		return "", fmt.Errorf("el archivo %s supera los %d bytes", name, max)
+	}
+	return string(data), nil
+}
+
+// budgetFS counts the bytes read from every file of the package, each file
+// has its own limit but a package with many of them can still be too large
+type budgetFS struct {
+	fs.FS
+	left int
+}
+
+func (b *budgetFS) Open(name string) (fs.File, error) {
+	f, err := b.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &budgetFile{File: f, budget: b}, nil
+}
+
+func (b *budgetFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(b.FS, name)
+}
+
+func (b *budgetFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(b.FS, name)
+}
+
+type budgetFile struct {
+	fs.File
+	budget *budgetFS
+}
+
+// Read fails as soon as the package goes over the budget
+func (f *budgetFile) Read(p []byte) (int, error) {
+	n, err := f.File.Read(p)
+	f.budget.left -= n
+	if f.budget.left < 0 {
+		return n, ErrTooLarge
+	}
+	return n, err
+}
@@ -0,0 +1,92 @@
+package problempkg
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"gopkg.in/yaml.v3"
+)
+
+// Write stores the problem as a zipped package at the root of the archive,
+// examples go to data/sample and test cases to data/secret
+func Write(w io.Writer, problem shared.Problem) error {
+	zw := zip.NewWriter(w)
+	cfg := config{
+		Name: problem.Title,
+		Limits: limits{
+			TimeLimit: float64(problem.TimeLimit) / 1000,
+			Memory:    problem.MemoryLimit / 1024,
+		},
+	}
+	if len(problem.Checker) > 0 {
+		cfg.Validation = "custom"
+	}
+	raw, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(zw, "problem.yaml", string(raw)); err != nil {
+		return err
+	}
+	if err := writeFile(zw, "problem_statement/problem.md", problem.Description); err != nil {
+		return err
+	}
+	for i, example := range problem.Examples {
+		if err := writePair(zw, "data/sample", i+1, example.Input, example.Output); err != nil {
+			return err
+		}
+	}
+	for i, tc := range problem.TestCases {
+		if err := writePair(zw, "data/secret", i+1, tc.Input, tc.Output); err != nil {
+			return err
+		}
+	}
+	for _, checker := range problem.Checker {
+		if err := writeFile(zw, checker.Path, checker.Src); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writePair(zw *zip.Writer, dir string, n int, input string, output string) error {
+	name := fmt.Sprintf("%s/%03d", dir, n)
+	if err := writeFile(zw, name+".in", input); err != nil {
+		return err
+	}
+	return writeFile(zw, name+".ans", output)
+}
+
+func writeFile(zw *zip.Writer, name string, content string) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(f, content)
+	return err
+}
+
+// ShortName turns a problem title into the lowercase identifier packages
+// use as directory and file name
+func ShortName(title string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(title) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	name := strings.TrimSuffix(b.String(), "-")
+	if name == "" {
+		return "problem"
+	}
+	return name
+}
@@ -0,0 +1,169 @@
+package quizes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+var (
+	errNotQuestion = errors.New("este problema se resuelve con código")
+	errQuestion    = errors.New("esta pregunta no se responde con código")
+)
+
+type QuestionFormData struct {
+	QuizID    string
+	ProblemID string
+	Question  shared.Question
+	Response  shared.QuestionResponse
+	Saved     bool
+}
+
+type AnswerInput struct {
+	QuizID    string
+	ProblemID string
+	Values    []string
+}
+
+func GetAnswerInput(r *http.Request) (AnswerInput, error) {
+	quizID := r.FormValue("quizID")
+	if err := shared.ValidateUUID(quizID); err != nil {
+		return AnswerInput{}, fmt.Errorf("quiz_id: %s", err.Error())
+	}
+	problemID := r.FormValue("problemID")
+	if err := shared.ValidateUUID(problemID); err != nil {
+		return AnswerInput{}, fmt.Errorf("problem_id: %s", err.Error())
+	}
+	return AnswerInput{
+		QuizID:    quizID,
+		ProblemID: problemID,
+		Values:    r.Form["answer"],
+	}, nil
+}
+
+type QuestionFormStorage interface {
+	SelectProblem(ctx context.Context, problemID string) (shared.Problem, error)
+	SelectResponse(ctx context.Context, userID, quizID, problemID string) (shared.QuestionResponse, error)
+}
+
+type answerInputFn func(r *http.Request) (AnswerInput, error)
+
+// CreateQuestionFormHandler renders the answer form of a question with the
+// previous answer of the candidate
+func CreateQuestionFormHandler(
+	templ shared.TemplatesRepo,
+	storage QuestionFormStorage,
+	authService shared.AuthRep,
+	inputFn answerInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		problem, err := storage.SelectProblem(r.Context(), input.ProblemID)
+		if err != nil {
+			http.Error(w, "problem not found", http.StatusBadRequest)
+			return
+		}
+		if problem.Question == nil {
+			http.Error(w, errNotQuestion.Error(), http.StatusBadRequest)
+			return
+		}
+		response, err := storage.SelectResponse(r.Context(), user.ID, input.QuizID, input.ProblemID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = templ.Render(w, "questionForm", QuestionFormData{
+			QuizID:    input.QuizID,
+			ProblemID: input.ProblemID,
+			Question:  *problem.Question,
+			Response:  response,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type AnswerStorage interface {
+	ParticipationStatus(ctx context.Context, userID string, quizID string) (shared.Participation, error)
+	SelectProblem(ctx context.Context, problemID string) (shared.Problem, error)
+	SaveResponse(ctx context.Context, participationID, problemID, answer string, score int32, graded bool) error
+}
+
+// CreateAnswerHandler stores the answer of a question, choice, numeric and
+// short answers are scored right away
+func CreateAnswerHandler(
+	templ shared.TemplatesRepo,
+	storage AnswerStorage,
+	authService shared.AuthRep,
+	inputFn answerInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		participation, err := storage.ParticipationStatus(r.Context(), user.ID, input.QuizID)
+		if err != nil {
+			http.Error(w, "error in getting status:"+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if participation.Finished(time.Now()) {
+			http.Error(w, "your participation is over", http.StatusUnauthorized)
+			return
+		}
+		if participation.Paused() {
+			http.Error(w, "your participation is paused", http.StatusUnauthorized)
+			return
+		}
+		problem, err := storage.SelectProblem(r.Context(), input.ProblemID)
+		if err != nil {
+			http.Error(w, "problem not found", http.StatusBadRequest)
+			return
+		}
+		if problem.Question == nil {
+			http.Error(w, errNotQuestion.Error(), http.StatusBadRequest)
+			return
+		}
+		answer, err := problem.Question.Encode(input.Values)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		score, graded := problem.Question.Grade(answer)
+		err = storage.SaveResponse(r.Context(), participation.ID, input.ProblemID, answer, score, graded)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		err = templ.Render(w, "questionForm", QuestionFormData{
+			QuizID:    input.QuizID,
+			ProblemID: input.ProblemID,
+			Question:  *problem.Question,
+			Response:  shared.QuestionResponse{Answer: answer, Graded: graded, Score: score},
+			Saved:     true,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
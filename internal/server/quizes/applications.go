@@ -0,0 +1,130 @@
+package quizes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/auth"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+type ApplicationsData struct {
+	User         auth.AuthUser
+	Applications []shared.CandidateApplication
+	NextPage     int32
+}
+
+type ApplicationsInput struct {
+	Page int32
+}
+
+func GetApplicationsInput(r *http.Request) (ApplicationsInput, error) {
+	page := shared.PageParam(r)
+	if page < 1 {
+		page = 1
+	}
+	return ApplicationsInput{Page: page}, nil
+}
+
+type ApplicationsStorage interface {
+	SelectCandidateApplications(ctx context.Context, userID string, page int32) ([]shared.CandidateApplication, error)
+}
+
+type applicationsInputFn func(r *http.Request) (ApplicationsInput, error)
+
+func CreateApplicationsHandler(
+	templ shared.TemplatesRepo,
+	storage ApplicationsStorage,
+	authService shared.AuthRep,
+	inputFn applicationsInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		applications, err := storage.SelectCandidateApplications(r.Context(), user.ID, input.Page)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		data := ApplicationsData{User: user, Applications: applications}
+		if len(applications) > 0 {
+			data.NextPage = input.Page + 1
+		}
+		renderName := "applicationsPage"
+		if input.Page > 1 {
+			renderName = "applicationList"
+		}
+		if err := templ.Render(w, renderName, data); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
+
+type WithdrawInput struct {
+	ParticipationID string
+}
+
+func GetWithdrawInput(r *http.Request) (WithdrawInput, error) {
+	participationID := chi.URLParam(r, "participationID")
+	if err := shared.ValidateUUID(participationID); err != nil {
+		return WithdrawInput{}, err
+	}
+	return WithdrawInput{ParticipationID: participationID}, nil
+}
+
+type WithdrawStorage interface {
+	WithdrawApplication(ctx context.Context, userID, participationID string) (shared.CandidateApplication, error)
+}
+
+type withdrawInputFn func(r *http.Request) (WithdrawInput, error)
+
+// CreateWithdrawHandler closes the quiz pages still open for the
+// participation, publishing is a no-op when it already finished
+func CreateWithdrawHandler(
+	templ shared.TemplatesRepo,
+	storage WithdrawStorage,
+	authService shared.AuthRep,
+	publisher DeadlinePublisher,
+	inputFn withdrawInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		application, err := storage.WithdrawApplication(r.Context(), user.ID, input.ParticipationID)
+		if errors.Is(err, shared.ErrAlreadyWithdrawn) {
+			w.Header().Set("HX-Retarget", "#application-alert-"+input.ParticipationID)
+			w.Header().Set("HX-Reswap", "innerHTML")
+			if err := templ.Render(w, "applicationAlert", shared.Alert{Ok: false, Msg: err.Error()}); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		publisher.Publish(input.ParticipationID, shared.EndReasonWithdrawn)
+		application.Alert = shared.Alert{Ok: true, Msg: "Postulación retirada"}
+		if err := templ.Render(w, "applicationStatus", application); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
@@ -39,7 +39,13 @@ type CallbackStorage interface {
 type callbackInputFn func(r *http.Request) (CallbackURLParamsInput, error)
 type decoderFn func(r *http.Request) (shared.CallbackJsonInput, error)
 
-func CreateCallbackHandler(storage CallbackStorage, st StreamService, decoder decoderFn, inputFn callbackInputFn) http.HandlerFunc {
+func CreateCallbackHandler(
+	storage CallbackStorage,
+	st StreamService,
+	events shared.WebhookEmitter,
+	decoder decoderFn,
+	inputFn callbackInputFn,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		urlParams, err := inputFn(r)
 		if err != nil {
@@ -64,6 +70,12 @@ func CreateCallbackHandler(storage CallbackStorage, st StreamService, decoder de
 			return
 		}
 		log.Println("Updated testCase, status: " + decoded.Status.Description)
+		// every callback emits, storage only queues the event once all the
+		// test cases are judged and just for one of them
+		shared.EmitWebhookEvent(r.Context(), events, shared.WebhookEvent{
+			Type:         shared.EventSubmissionJudged,
+			SubmissionID: urlParams.SubmissionID,
+		})
 		err = st.Update(urlParams.SubmissionID, decoded.Token, decoded.Status.Description)
 		if err != nil {
 			log.Printf("error updating stream: %v", err)
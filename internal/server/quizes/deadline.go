@@ -0,0 +1,233 @@
+package quizes
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const (
+	timeOverEvent = "timeover"
+	deadlineEvent = "deadline"
+)
+
+// An event published between reading the participation and subscribing is
+// lost, the page is closed anyway once the finalizer had time to run
+const deadlineGrace = time.Minute
+
+// DeadlineBroker notifies the open quiz pages of a participation when the
+// finalizer closes it
+type DeadlineBroker struct {
+	listeners map[string][]chan string
+	mu        sync.Mutex
+}
+
+func NewDeadlineBroker() *DeadlineBroker {
+	return &DeadlineBroker{
+		listeners: make(map[string][]chan string),
+	}
+}
+
+func (b *DeadlineBroker) Subscribe(participationID string) chan string {
+	ch := make(chan string, 1)
+	b.mu.Lock()
+	b.listeners[participationID] = append(b.listeners[participationID], ch)
+	b.mu.Unlock()
+	return ch
+}
+
+func (b *DeadlineBroker) Unsubscribe(participationID string, ch chan string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	listeners := b.listeners[participationID]
+	for i, l := range listeners {
+		if l == ch {
+			listeners = append(listeners[:i], listeners[i+1:]...)
+			break
+		}
+	}
+	if len(listeners) == 0 {
+		delete(b.listeners, participationID)
+		return
+	}
+	b.listeners[participationID] = listeners
+}
+
+func (b *DeadlineBroker) Publish(participationID, reason string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.listeners[participationID] {
+		select {
+		case ch <- reason:
+		default:
+		}
+	}
+}
+
+type FinalizerStorage interface {
+	FinalizeExpired(ctx context.Context, now time.Time) ([]shared.Participation, error)
+}
+
+type DeadlinePublisher interface {
+	Publish(participationID, reason string)
+}
+
+// RunFinalizer closes expired participations every interval until the
+// context is cancelled
+func RunFinalizer(
+	ctx context.Context,
+	storage FinalizerStorage,
+	publisher DeadlinePublisher,
+	events shared.WebhookEmitter,
+	notifier shared.Notifier,
+	interval time.Duration,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			Finalize(ctx, storage, publisher, events, notifier, time.Now())
+		}
+	}
+}
+
+// Finalize also queues the finished event and the results notification,
+// the applicants who ran out of time never reach the end handler
+func Finalize(
+	ctx context.Context,
+	storage FinalizerStorage,
+	publisher DeadlinePublisher,
+	events shared.WebhookEmitter,
+	notifier shared.Notifier,
+	now time.Time,
+) {
+	finished, err := storage.FinalizeExpired(ctx, now)
+	if err != nil {
+		log.Println("finalizer:", err)
+		return
+	}
+	for _, participation := range finished {
+		publisher.Publish(participation.ID, participation.EndReason)
+		shared.EmitWebhookEvent(ctx, events, shared.WebhookEvent{
+			Type:            shared.EventParticipationFinished,
+			ParticipationID: participation.ID,
+		})
+		shared.Notify(ctx, notifier, shared.NotificationEvent{
+			Category:        shared.NotifyResults,
+			ParticipationID: participation.ID,
+		})
+	}
+}
+
+type DeadlineInput struct {
+	QuizID string
+}
+
+func GetDeadlineInput(r *http.Request) (DeadlineInput, error) {
+	quizID := chi.URLParam(r, "quizID")
+	if err := shared.ValidateUUID(quizID); err != nil {
+		return DeadlineInput{}, err
+	}
+	return DeadlineInput{QuizID: quizID}, nil
+}
+
+type DeadlineStorage interface {
+	ParticipationStatus(ctx context.Context, userID string, quizID string) (shared.Participation, error)
+}
+
+type DeadlineService interface {
+	Subscribe(participationID string) chan string
+	Unsubscribe(participationID string, ch chan string)
+}
+
+func FormatTimeOverEvent() string {
+	data := `<div hx-on::load="showAlertAndRedirect()"></div>`
+	return fmt.Sprintf("event: %s\ndata: %s\n\n", timeOverEvent, data)
+}
+
+// The quiz page restarts its countdown with the adjusted deadline
+func FormatDeadlineEvent(participation shared.Participation) string {
+	data := fmt.Sprintf(
+		`<div hx-on::load="setDeadline('%s', %t)"></div>`,
+		participation.ExpiresAt.Format(time.RFC3339),
+		participation.Paused(),
+	)
+	return fmt.Sprintf("event: %s\ndata: %s\n\n", deadlineEvent, data)
+}
+
+type deadlineInputFn func(r *http.Request) (DeadlineInput, error)
+
+func CreateDeadlineHandler(
+	storage DeadlineStorage,
+	authService shared.AuthRep,
+	deadlines DeadlineService,
+	inputFn deadlineInputFn,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		partiData, err := storage.ParticipationStatus(r.Context(), user.ID, input.QuizID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "sse is not suppported", http.StatusInternalServerError)
+			return
+		}
+		SSEHeaders(w)
+		listener := deadlines.Subscribe(partiData.ID)
+		defer deadlines.Unsubscribe(partiData.ID, listener)
+		flusher.Flush()
+	wait:
+		for partiData.EndReason == "" {
+			var fallback <-chan time.Time
+			if !partiData.Paused() {
+				fallback = time.After(time.Until(partiData.ExpiresAt) + deadlineGrace)
+			}
+			select {
+			case <-r.Context().Done():
+				return
+			case <-fallback:
+				break wait
+			case msg := <-listener:
+				if msg != shared.DeadlineAdjusted {
+					break wait
+				}
+				partiData, err = storage.ParticipationStatus(r.Context(), user.ID, input.QuizID)
+				if err != nil {
+					log.Println(err)
+					return
+				}
+				if _, err := fmt.Fprint(w, FormatDeadlineEvent(partiData)); err != nil {
+					log.Println(err)
+					return
+				}
+				flusher.Flush()
+			}
+		}
+		if _, err := fmt.Fprint(w, FormatTimeOverEvent()); err != nil {
+			log.Println(err)
+			return
+		}
+		flusher.Flush()
+	}
+}
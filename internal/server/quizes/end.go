@@ -2,6 +2,7 @@ package quizes
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/kw3a/spotted-server/internal/server/shared"
@@ -25,7 +26,7 @@ func GetEndInput(r *http.Request) (EndInput, error) {
 }
 
 type endInputFn func(r *http.Request) (EndInput, error)
-func CreateEndHandler(endStorage EndStorage, authService shared.AuthRep, inputFn endInputFn) http.HandlerFunc {
+func CreateEndHandler(endStorage EndStorage, authService shared.AuthRep, events shared.WebhookEmitter, notifier shared.Notifier, inputFn endInputFn) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		user, err := authService.GetUser(r)
 		if err != nil {
@@ -38,11 +39,28 @@ func CreateEndHandler(endStorage EndStorage, authService shared.AuthRep, inputFn
 			return
 		}
 		offer, err := endStorage.EndQuiz(r.Context(), user.ID, input.QuizID)
+		// a repeated request or one after the deadline doesn't announce
+		// the end again
+		if errors.Is(err, shared.ErrAlreadyFinished) {
+			w.Header().Set("HX-Redirect", "/applications#offer-"+offer.ID)
+			w.WriteHeader(http.StatusOK)
+			return
+		}
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		w.Header().Set("HX-Redirect", "/preamble/"+offer.ID)
+		shared.EmitWebhookEvent(r.Context(), events, shared.WebhookEvent{
+			Type:   shared.EventParticipationFinished,
+			UserID: user.ID,
+			QuizID: input.QuizID,
+		})
+		shared.Notify(r.Context(), notifier, shared.NotificationEvent{
+			Category: shared.NotifyResults,
+			UserID:   user.ID,
+			QuizID:   input.QuizID,
+		})
+		w.Header().Set("HX-Redirect", "/applications#offer-"+offer.ID)
 		w.WriteHeader(http.StatusOK)
 	}
 }
@@ -119,7 +119,7 @@ func CreateKeyStrokeWindowHandler(storage KeyStrokeWindowStorage, authService sh
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		if partiData.ExpiresAt.Before(time.Now()) {
+		if partiData.Finished(time.Now()) {
 			http.Error(w, "your participation is over", http.StatusUnauthorized)
 			return
 		}
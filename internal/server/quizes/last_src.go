@@ -8,15 +8,20 @@ import (
 )
 
 type SourceStorage interface {
-	LastSrc(ctx context.Context, userID, problemID string, languageID int32) (string, error)
+	LastSrc(ctx context.Context, userID, quizID, problemID string, languageID int32) (string, error)
 }
 
 type SourceInput struct {
+	QuizID     string
 	ProblemID  string
 	LanguageID int32
 }
 
 func GetSourceInput(r *http.Request) (SourceInput, error) {
+	quizID := r.FormValue("quizID")
+	if err := shared.ValidateUUID(quizID); err != nil {
+		return SourceInput{}, err
+	}
 	problemID := r.FormValue("problemID")
 	if err := shared.ValidateUUID(problemID); err != nil {
 		return SourceInput{}, err
@@ -27,6 +32,7 @@ func GetSourceInput(r *http.Request) (SourceInput, error) {
 		return SourceInput{}, err
 	}
 	return SourceInput{
+		QuizID:     quizID,
 		ProblemID:  problemID,
 		LanguageID: languageIDInt32,
 	}, nil
@@ -46,7 +52,7 @@ func CreateSourceHandler(storage SourceStorage, authServ shared.AuthRep, inputFn
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		src, err := storage.LastSrc(r.Context(), user.ID, input.ProblemID, input.LanguageID)
+		src, err := storage.LastSrc(r.Context(), user.ID, input.QuizID, input.ProblemID, input.LanguageID)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
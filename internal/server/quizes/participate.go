@@ -3,16 +3,20 @@ package quizes
 import (
 	"context"
 	"net/http"
+	"time"
 
 	"github.com/kw3a/spotted-server/internal/server/shared"
 )
 
 type ParticipationStorage interface {
+	SelectQuiz(ctx context.Context, quizID string) (shared.Quiz, error)
+	ClaimInvitation(ctx context.Context, quizID, userID, email, code string) error
 	Participate(ctx context.Context, userID string, quizID string) error
 }
 
 type ParticipateInput struct {
 	QuizID string
+	Code   string
 }
 
 func GetParticipateInput(r *http.Request) (ParticipateInput, error) {
@@ -22,10 +26,18 @@ func GetParticipateInput(r *http.Request) (ParticipateInput, error) {
 	}
 	return ParticipateInput{
 		QuizID: quizID,
+		Code:   r.FormValue("code"),
 	}, nil
 }
 type participateInputFn func(r *http.Request) (ParticipateInput, error)
-func CreateParticipateHandler(storage ParticipationStorage, authService shared.AuthRep, inputFn participateInputFn) http.HandlerFunc {
+func CreateParticipateHandler(
+	templ shared.TemplatesRepo,
+	storage ParticipationStorage,
+	authService shared.AuthRep,
+	events shared.WebhookEmitter,
+	notifier shared.Notifier,
+	inputFn participateInputFn,
+) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		user, err := authService.GetUser(r)
 		if err != nil {
@@ -37,11 +49,47 @@ func CreateParticipateHandler(storage ParticipationStorage, authService shared.A
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		quiz, err := storage.SelectQuiz(r.Context(), input.QuizID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = quiz.Available(time.Now())
+		if err == nil && quiz.InviteOnly {
+			if input.Code == "" {
+				err = shared.ErrInvitationRequired
+			} else {
+				err = storage.ClaimInvitation(r.Context(), quiz.ID, user.ID, user.Email, input.Code)
+			}
+		}
+		if shared.IsAccessError(err) {
+			// the preamble stays, the reason is shown next to the button
+			w.Header().Set("HX-Retarget", "#access-alert")
+			w.Header().Set("HX-Reswap", "innerHTML")
+			if err := templ.Render(w, "accessAlert", shared.Alert{Ok: false, Msg: err.Error()}); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 		err = storage.Participate(r.Context(), user.ID, input.QuizID)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
+		shared.EmitWebhookEvent(r.Context(), events, shared.WebhookEvent{
+			Type:   shared.EventParticipationStarted,
+			UserID: user.ID,
+			QuizID: input.QuizID,
+		})
+		shared.Notify(r.Context(), notifier, shared.NotificationEvent{
+			Category: shared.NotifyApplicant,
+			UserID:   user.ID,
+			QuizID:   input.QuizID,
+		})
 		w.Header().Set("HX-Redirect", "/quizes/"+input.QuizID)
 		w.WriteHeader(http.StatusOK)
 	}
@@ -0,0 +1,109 @@
+package quizes
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const maxProctoringDetail = 255
+
+type ProctoringEventStorage interface {
+	InsertProctoringEvent(ctx context.Context, participationID string, event shared.ProctoringEvent) error
+	CountProctoringEvents(ctx context.Context, participationID, kind string) (int32, error)
+	SelectProctoringRule(ctx context.Context, quizID, kind string) (shared.ProctoringRule, error)
+	ParticipationStatus(ctx context.Context, userID string, quizID string) (shared.Participation, error)
+}
+
+type ProctoringEventInput struct {
+	QuizID string
+	Event  shared.ProctoringEvent
+}
+
+type ProctoringEventOutput struct {
+	Warning bool   `json:"warning"`
+	Msg     string `json:"msg"`
+}
+
+func GetProctoringEventInput(r *http.Request) (ProctoringEventInput, error) {
+	quizID := r.FormValue("quizID")
+	if err := shared.ValidateUUID(quizID); err != nil {
+		return ProctoringEventInput{}, err
+	}
+	kind := r.FormValue("kind")
+	if err := shared.ValidateProctoringKind(kind); err != nil {
+		return ProctoringEventInput{}, err
+	}
+	detail := r.FormValue("detail")
+	if len(detail) > maxProctoringDetail {
+		return ProctoringEventInput{}, fmt.Errorf("detail is too long")
+	}
+	return ProctoringEventInput{
+		QuizID: quizID,
+		Event: shared.ProctoringEvent{
+			Kind:   kind,
+			Detail: detail,
+		},
+	}, nil
+}
+
+// The warning is only raised once the amount of events reaches the
+// threshold configured by the recruiter, a zero threshold disables it
+func ProctoringWarning(rule shared.ProctoringRule, count int32) ProctoringEventOutput {
+	if rule.Threshold <= 0 || count < rule.Threshold {
+		return ProctoringEventOutput{}
+	}
+	return ProctoringEventOutput{
+		Warning: true,
+		Msg: fmt.Sprintf(
+			"Advertencia: se registraron %d eventos de tipo \"%s\". Esta actividad será visible para el reclutador.",
+			count, rule.Label,
+		),
+	}
+}
+
+type proctoringEventInputFn func(*http.Request) (ProctoringEventInput, error)
+
+func CreateProctoringEventHandler(storage ProctoringEventStorage, authService shared.AuthRep, inputFn proctoringEventInputFn) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, err := authService.GetUser(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		input, err := inputFn(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		partiData, err := storage.ParticipationStatus(r.Context(), user.ID, input.QuizID)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if partiData.Finished(time.Now()) {
+			http.Error(w, "your participation is over", http.StatusUnauthorized)
+			return
+		}
+		input.Event.ID = uuid.NewString()
+		if err := storage.InsertProctoringEvent(r.Context(), partiData.ID, input.Event); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		count, err := storage.CountProctoringEvents(r.Context(), partiData.ID, input.Event.Kind)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		rule, err := storage.SelectProctoringRule(r.Context(), input.QuizID, input.Event.Kind)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		shared.EncodeAndLog(w, http.StatusOK, ProctoringWarning(rule, count))
+	}
+}
@@ -17,6 +17,7 @@ type QuizPageData struct {
 	VideoBrokerURL  string
 	Problems        []ProblemSelector
 	ExpiresAt       time.Time
+	Paused          bool
 	ParticipationID string
 	Score           shared.Score
 	Problem         shared.Problem
@@ -50,12 +51,12 @@ func GetQuizPageInput(r *http.Request) (QuizPageInput, error) {
 
 type QuizPageStorage interface {
 	ParticipationStatus(ctx context.Context, userID string, quizID string) (shared.Participation, error)
-	SelectProblemIDs(ctx context.Context, quizID string) ([]string, error)
-	SelectScore(ctx context.Context, userID string, problemID string) (shared.Score, error)
+	SelectProblemIDs(ctx context.Context, participationID string, quizID string) ([]string, error)
+	SelectScore(ctx context.Context, userID string, quizID string, problemID string) (shared.Score, error)
 	SelectProblem(ctx context.Context, problemID string) (shared.Problem, error)
 	SelectExamples(ctx context.Context, problemID string) ([]shared.Example, error)
 	SelectLanguages(ctx context.Context, quizID string) ([]shared.Language, error)
-	LastSrc(ctx context.Context, userID string, problemID string, languageID int32) (string, error)
+	LastSrc(ctx context.Context, userID string, quizID string, problemID string, languageID int32) (string, error)
 }
 
 func EnumerateProblems(problemIDs []string) []ProblemSelector {
@@ -107,17 +108,17 @@ func CreateQuizPageHandler(
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		if partiData.ExpiresAt.Before(time.Now()) {
+		if partiData.Finished(time.Now()) {
 			http.Error(w, "your participation is over", http.StatusUnauthorized)
 			return
 		}
-		problemIDs, err := storage.SelectProblemIDs(r.Context(), input.OfferID)
+		problemIDs, err := storage.SelectProblemIDs(r.Context(), partiData.ID, input.OfferID)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
 		selectedProblem := selectProblFn(problemIDs)
-		score, err := storage.SelectScore(r.Context(), user.ID, selectedProblem)
+		score, err := storage.SelectScore(r.Context(), user.ID, input.OfferID, selectedProblem)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -138,7 +139,7 @@ func CreateQuizPageHandler(
 			return
 		}
 		selectedLanguage := selectLangFn(languages)
-		lastSrc, err := storage.LastSrc(r.Context(), user.ID, selectedProblem, selectedLanguage.ID)
+		lastSrc, err := storage.LastSrc(r.Context(), user.ID, input.OfferID, selectedProblem, selectedLanguage.ID)
 		if err != nil {
 			http.Error(w, "src not found", http.StatusInternalServerError)
 			return
@@ -149,6 +150,7 @@ func CreateQuizPageHandler(
 			VideoBrokerURL:  videoBrokerURL,
 			Problems:        enumerateProblemsFn(problemIDs),
 			ExpiresAt:       partiData.ExpiresAt,
+			Paused:          partiData.Paused(),
 			ParticipationID: partiData.ID,
 			Score:           score,
 			Problem:         problem,
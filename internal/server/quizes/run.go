@@ -9,6 +9,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/kw3a/spotted-server/internal/server/codejudge"
 	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/kw3a/spotted-server/internal/server/signature"
+	"github.com/kw3a/spotted-server/internal/server/sqljudge"
 )
 
 type RunInput struct {
@@ -55,6 +57,7 @@ type RunStorage interface {
 
 	ParticipationStatus(ctx context.Context, userID string, quizID string) (shared.Participation, error)
 	GetTestCases(ctx context.Context, problemID string) ([]codejudge.TestCase, error)
+	SelectProblem(ctx context.Context, problemID string) (shared.Problem, error)
 }
 type JudgeService interface {
 	Send(dbTestCases []codejudge.TestCase, submission codejudge.Submission) ([]string, error)
@@ -93,29 +96,53 @@ func CreateRunHandler(
 			http.Error(w, "error in getting status:"+err.Error(), http.StatusBadRequest)
 			return
 		}
-		if participation.ExpiresAt.Before(time.Now()) {
+		if participation.Finished(time.Now()) {
 			http.Error(w, "your participation is over", http.StatusUnauthorized)
 			return
 		}
+		if participation.Paused() {
+			http.Error(w, "your participation is paused", http.StatusUnauthorized)
+			return
+		}
 		//Select DB Test Cases
 		testCases, err := storage.GetTestCases(r.Context(), input.ProblemID)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		//DB INSERTS
-		submissionID := uuid.NewString()
-		err = storage.CreateSubmission(r.Context(), submissionID, participation.ID, input.ProblemID, input.Src, input.LanguageID)
+		problem, err := storage.SelectProblem(r.Context(), input.ProblemID)
 		if err != nil {
-			http.Error(w, "error in create submission: "+err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		//Judge request
-		submission := codejudge.Submission{
+		if problem.Question != nil {
+			http.Error(w, errQuestion.Error(), http.StatusBadRequest)
+			return
+		}
+		// the submission keeps what the candidate wrote, only the judge
+		// receives the harness
+		src, err := signature.Prepare(problem.Signature, input.LanguageID, input.Src)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		submissionID := uuid.NewString()
+		testCases, submission, err := sqljudge.Prepare(problem.Database, testCases, codejudge.Submission{
 			ID:         submissionID,
-			Src:        input.Src,
+			Src:        src,
 			LanguageID: input.LanguageID,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
+		//DB INSERTS
+		err = storage.CreateSubmission(r.Context(), submissionID, participation.ID, input.ProblemID, input.Src, submission.LanguageID)
+		if err != nil {
+			http.Error(w, "error in create submission: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		//Judge request
 		tokens, err := judge.Send(testCases, submission)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -8,18 +8,24 @@ import (
 )
 
 type ScoreStorage interface {
-	SelectScore(ctx context.Context, userID string, problemID string) (shared.Score, error)
+	SelectScore(ctx context.Context, userID string, quizID string, problemID string) (shared.Score, error)
 }
 type ScoreInput struct {
+	QuizID    string
 	ProblemID string
 }
 
 func GetScoreInput(r *http.Request) (ScoreInput, error) {
+	quizID := r.FormValue("quizID")
+	if err := shared.ValidateUUID(quizID); err != nil {
+		return ScoreInput{}, err
+	}
 	problemID := r.FormValue("problemID")
 	if err := shared.ValidateUUID(problemID); err != nil {
 		return ScoreInput{}, err
 	}
 	return ScoreInput{
+		QuizID:    quizID,
 		ProblemID: problemID,
 	}, nil
 }
@@ -37,7 +43,7 @@ func CreateScoreHandler(templ shared.TemplatesRepo, storage ScoreStorage, authSe
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		score, err := storage.SelectScore(r.Context(), user.ID, input.ProblemID)
+		score, err := storage.SelectScore(r.Context(), user.ID, input.QuizID, input.ProblemID)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
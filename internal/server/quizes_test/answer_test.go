@@ -0,0 +1,187 @@
+package quizestest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/server/quizes"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type answerStorage struct {
+	mock.Mock
+}
+
+func (s *answerStorage) ParticipationStatus(ctx context.Context, userID string, quizID string) (shared.Participation, error) {
+	args := s.Called(ctx, userID, quizID)
+	return args.Get(0).(shared.Participation), args.Error(1)
+}
+func (s *answerStorage) SelectProblem(ctx context.Context, problemID string) (shared.Problem, error) {
+	args := s.Called(ctx, problemID)
+	return args.Get(0).(shared.Problem), args.Error(1)
+}
+func (s *answerStorage) SelectResponse(ctx context.Context, userID, quizID, problemID string) (shared.QuestionResponse, error) {
+	args := s.Called(ctx, userID, quizID, problemID)
+	return args.Get(0).(shared.QuestionResponse), args.Error(1)
+}
+func (s *answerStorage) SaveResponse(ctx context.Context, participationID, problemID, answer string, score int32, graded bool) error {
+	args := s.Called(ctx, participationID, problemID, answer, score, graded)
+	return args.Error(0)
+}
+
+func answerInputFn(values ...string) func(r *http.Request) (quizes.AnswerInput, error) {
+	return func(r *http.Request) (quizes.AnswerInput, error) {
+		return quizes.AnswerInput{QuizID: "quiz-id", ProblemID: "problem-id", Values: values}, nil
+	}
+}
+
+func choiceQuestion() *shared.Question {
+	return &shared.Question{
+		Kind:    shared.QuestionSingle,
+		Points:  4,
+		Options: []shared.QuestionOption{{Label: "a"}, {Label: "b", Correct: true}},
+	}
+}
+
+func TestGetAnswerInput(t *testing.T) {
+	quizID, problemID := uuid.NewString(), uuid.NewString()
+	req := formRequest("POST", "/", map[string][]string{
+		"quizID":    {quizID},
+		"problemID": {problemID},
+		"answer":    {"0", "1"},
+	})
+	input, err := quizes.GetAnswerInput(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if input.QuizID != quizID || input.ProblemID != problemID || len(input.Values) != 2 {
+		t.Errorf("unexpected input %v", input)
+	}
+}
+
+func TestGetAnswerInputBadProblemID(t *testing.T) {
+	req := formRequest("POST", "/", map[string][]string{
+		"quizID":    {uuid.NewString()},
+		"problemID": {"invalid"},
+	})
+	if _, err := quizes.GetAnswerInput(req); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestQuestionFormHandlerCodingProblem(t *testing.T) {
+	storage := new(answerStorage)
+	storage.On("SelectProblem", mock.Anything, "problem-id").Return(shared.Problem{}, nil)
+	handler := quizes.CreateQuestionFormHandler(&templates{}, storage, authRepo{}, answerInputFn())
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestQuestionFormHandler(t *testing.T) {
+	storage := new(answerStorage)
+	storage.On("SelectProblem", mock.Anything, "problem-id").Return(shared.Problem{Question: choiceQuestion()}, nil)
+	storage.On("SelectResponse", mock.Anything, mock.Anything, "quiz-id", "problem-id").Return(shared.QuestionResponse{Answer: "1"}, nil)
+	handler := quizes.CreateQuestionFormHandler(&templates{}, storage, authRepo{}, answerInputFn())
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestAnswerHandlerBadAuth(t *testing.T) {
+	handler := quizes.CreateAnswerHandler(&templates{}, new(answerStorage), invalidAuthRepo{}, answerInputFn("1"))
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestAnswerHandlerParticipationExpired(t *testing.T) {
+	storage := new(answerStorage)
+	expired := shared.Participation{ExpiresAt: time.Now().Add(-time.Hour)}
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(expired, nil)
+	handler := quizes.CreateAnswerHandler(&templates{}, storage, authRepo{}, answerInputFn("1"))
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+	storage.AssertNotCalled(t, "SaveResponse", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAnswerHandlerInvalidAnswer(t *testing.T) {
+	storage := new(answerStorage)
+	inTime := shared.Participation{ID: "part-id", ExpiresAt: time.Now().Add(time.Hour)}
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(inTime, nil)
+	storage.On("SelectProblem", mock.Anything, "problem-id").Return(shared.Problem{Question: choiceQuestion()}, nil)
+	handler := quizes.CreateAnswerHandler(&templates{}, storage, authRepo{}, answerInputFn("0", "1"))
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestAnswerHandlerBadStorage(t *testing.T) {
+	storage := new(answerStorage)
+	inTime := shared.Participation{ID: "part-id", ExpiresAt: time.Now().Add(time.Hour)}
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(inTime, nil)
+	storage.On("SelectProblem", mock.Anything, "problem-id").Return(shared.Problem{Question: choiceQuestion()}, nil)
+	storage.On("SaveResponse", mock.Anything, "part-id", "problem-id", "1", int32(4), true).Return(errors.New("error"))
+	handler := quizes.CreateAnswerHandler(&templates{}, storage, authRepo{}, answerInputFn("1"))
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestAnswerHandlerGraded(t *testing.T) {
+	storage := new(answerStorage)
+	inTime := shared.Participation{ID: "part-id", ExpiresAt: time.Now().Add(time.Hour)}
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(inTime, nil)
+	storage.On("SelectProblem", mock.Anything, "problem-id").Return(shared.Problem{Question: choiceQuestion()}, nil)
+	storage.On("SaveResponse", mock.Anything, "part-id", "problem-id", "1", int32(4), true).Return(nil)
+	handler := quizes.CreateAnswerHandler(&templates{}, storage, authRepo{}, answerInputFn("1"))
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+}
+
+func TestAnswerHandlerFreeText(t *testing.T) {
+	storage := new(answerStorage)
+	inTime := shared.Participation{ID: "part-id", ExpiresAt: time.Now().Add(time.Hour)}
+	question := &shared.Question{Kind: shared.QuestionText, Points: 10}
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(inTime, nil)
+	storage.On("SelectProblem", mock.Anything, "problem-id").Return(shared.Problem{Question: question}, nil)
+	storage.On("SaveResponse", mock.Anything, "part-id", "problem-id", "divide el rango", int32(0), false).Return(nil)
+	handler := quizes.CreateAnswerHandler(&templates{}, storage, authRepo{}, answerInputFn(" divide el rango "))
+	req, _ := http.NewRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	storage.AssertExpectations(t)
+}
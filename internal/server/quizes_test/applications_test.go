@@ -0,0 +1,206 @@
+package quizestest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/server/quizes"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type applicationsStorage struct {
+	mock.Mock
+}
+
+func (s *applicationsStorage) SelectCandidateApplications(ctx context.Context, userID string, page int32) ([]shared.CandidateApplication, error) {
+	args := s.Called(ctx, userID, page)
+	return args.Get(0).([]shared.CandidateApplication), args.Error(1)
+}
+
+func (s *applicationsStorage) WithdrawApplication(ctx context.Context, userID, participationID string) (shared.CandidateApplication, error) {
+	args := s.Called(ctx, userID, participationID)
+	return args.Get(0).(shared.CandidateApplication), args.Error(1)
+}
+
+func applicationsInputFn(r *http.Request) (quizes.ApplicationsInput, error) {
+	return quizes.ApplicationsInput{Page: 1}, nil
+}
+
+func withdrawInputFn(r *http.Request) (quizes.WithdrawInput, error) {
+	return quizes.WithdrawInput{ParticipationID: "p1"}, nil
+}
+
+func TestGetApplicationsInput(t *testing.T) {
+	req := httptest.NewRequest("GET", "/applications?page=3", nil)
+	input, err := quizes.GetApplicationsInput(req)
+	if err != nil {
+		t.Error(err)
+	}
+	if input.Page != 3 {
+		t.Errorf("expected page 3, got %d", input.Page)
+	}
+	req = httptest.NewRequest("GET", "/applications?page=-2", nil)
+	input, _ = quizes.GetApplicationsInput(req)
+	if input.Page != 1 {
+		t.Errorf("expected page 1, got %d", input.Page)
+	}
+}
+
+func TestApplicationsHandlerUnauthorized(t *testing.T) {
+	storage := new(applicationsStorage)
+	handler := quizes.CreateApplicationsHandler(&templates{}, storage, &invalidAuthRepo{}, applicationsInputFn)
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/applications", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status code %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+	storage.AssertNotCalled(t, "SelectCandidateApplications", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestApplicationsHandlerStorageError(t *testing.T) {
+	storage := new(applicationsStorage)
+	storage.On("SelectCandidateApplications", mock.Anything, mock.Anything, int32(1)).
+		Return([]shared.CandidateApplication{}, errors.New("error"))
+	handler := quizes.CreateApplicationsHandler(&templates{}, storage, &authRepo{}, applicationsInputFn)
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/applications", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status code %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+func TestApplicationsHandlerPage(t *testing.T) {
+	storage := new(applicationsStorage)
+	applications := []shared.CandidateApplication{{QuizID: "q1"}}
+	storage.On("SelectCandidateApplications", mock.Anything, mock.Anything, int32(1)).Return(applications, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "applicationsPage", mock.MatchedBy(func(data quizes.ApplicationsData) bool {
+		return len(data.Applications) == 1 && data.NextPage == 2
+	})).Return(nil)
+	handler := quizes.CreateApplicationsHandler(templ, storage, &authRepo{}, applicationsInputFn)
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/applications", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestApplicationsHandlerNextPage(t *testing.T) {
+	storage := new(applicationsStorage)
+	storage.On("SelectCandidateApplications", mock.Anything, mock.Anything, int32(2)).
+		Return([]shared.CandidateApplication{}, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "applicationList", mock.MatchedBy(func(data quizes.ApplicationsData) bool {
+		return data.NextPage == 0
+	})).Return(nil)
+	inputFn := func(r *http.Request) (quizes.ApplicationsInput, error) {
+		return quizes.ApplicationsInput{Page: 2}, nil
+	}
+	handler := quizes.CreateApplicationsHandler(templ, storage, &authRepo{}, inputFn)
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("GET", "/applications?page=2", nil))
+	templ.AssertExpectations(t)
+}
+
+func TestGetWithdrawInput(t *testing.T) {
+	req := WithUrlParam(httptest.NewRequest("POST", "/", nil), "participationID", "invalid")
+	if _, err := quizes.GetWithdrawInput(req); err == nil {
+		t.Error("expected error")
+	}
+	participationID := uuid.NewString()
+	req = WithUrlParam(httptest.NewRequest("POST", "/", nil), "participationID", participationID)
+	input, err := quizes.GetWithdrawInput(req)
+	if err != nil {
+		t.Error(err)
+	}
+	if input.ParticipationID != participationID {
+		t.Error("invalid participation ID")
+	}
+}
+
+func TestWithdrawHandlerUnauthorized(t *testing.T) {
+	storage := new(applicationsStorage)
+	handler := quizes.CreateWithdrawHandler(&templates{}, storage, &invalidAuthRepo{}, quizes.NewDeadlineBroker(), withdrawInputFn)
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("POST", "/", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status code %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+	storage.AssertNotCalled(t, "WithdrawApplication", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWithdrawHandlerBadInput(t *testing.T) {
+	storage := new(applicationsStorage)
+	inputFn := func(r *http.Request) (quizes.WithdrawInput, error) {
+		return quizes.WithdrawInput{}, errors.New("error")
+	}
+	handler := quizes.CreateWithdrawHandler(&templates{}, storage, &authRepo{}, quizes.NewDeadlineBroker(), inputFn)
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("POST", "/", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestWithdrawHandlerAlreadyWithdrawn(t *testing.T) {
+	storage := new(applicationsStorage)
+	storage.On("WithdrawApplication", mock.Anything, mock.Anything, "p1").
+		Return(shared.CandidateApplication{}, shared.ErrAlreadyWithdrawn)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "applicationAlert", mock.Anything).Return(nil)
+	handler := quizes.CreateWithdrawHandler(templ, storage, &authRepo{}, quizes.NewDeadlineBroker(), withdrawInputFn)
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("POST", "/", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+	if w.Header().Get("HX-Retarget") != "#application-alert-p1" {
+		t.Errorf("unexpected retarget %s", w.Header().Get("HX-Retarget"))
+	}
+	templ.AssertExpectations(t)
+}
+
+func TestWithdrawHandlerStorageError(t *testing.T) {
+	storage := new(applicationsStorage)
+	storage.On("WithdrawApplication", mock.Anything, mock.Anything, "p1").
+		Return(shared.CandidateApplication{}, errors.New("error"))
+	handler := quizes.CreateWithdrawHandler(&templates{}, storage, &authRepo{}, quizes.NewDeadlineBroker(), withdrawInputFn)
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("POST", "/", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestWithdrawHandler(t *testing.T) {
+	storage := new(applicationsStorage)
+	application := shared.CandidateApplication{Participation: shared.Participation{ID: "p1"}}
+	storage.On("WithdrawApplication", mock.Anything, mock.Anything, "p1").Return(application, nil)
+	templ := new(templatesMock)
+	templ.On("Render", mock.Anything, "applicationStatus", mock.MatchedBy(func(data shared.CandidateApplication) bool {
+		return data.Alert.Ok
+	})).Return(nil)
+	broker := quizes.NewDeadlineBroker()
+	listener := broker.Subscribe("p1")
+	handler := quizes.CreateWithdrawHandler(templ, storage, &authRepo{}, broker, withdrawInputFn)
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest("POST", "/", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+	select {
+	case reason := <-listener:
+		if reason != shared.EndReasonWithdrawn {
+			t.Errorf("unexpected reason %s", reason)
+		}
+	default:
+		t.Error("the open quiz pages were not notified")
+	}
+	templ.AssertExpectations(t)
+}
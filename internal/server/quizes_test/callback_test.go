@@ -115,6 +115,7 @@ func TestCallbackHandlerBadURLInput(t *testing.T) {
 	handler := quizes.CreateCallbackHandler(
 		&callbackStorageMock{},
 		&streamService{},
+		new(webhookEmitter),
 		nil,
 		invalidInputFn)
 	if handler == nil {
@@ -132,6 +133,7 @@ func TestCallbackHandlerBadJsonInput(t *testing.T) {
 	handler := quizes.CreateCallbackHandler(
 		&callbackStorageMock{},
 		&streamService{},
+		new(webhookEmitter),
 		invalidJsonDecoder,
 		callbackInputFn)
 	if handler == nil {
@@ -151,6 +153,7 @@ func TestCallbackHandlerBadStorage(t *testing.T) {
 	handler := quizes.CreateCallbackHandler(
 		storage,
 		&streamService{},
+		new(webhookEmitter),
 		jsonDecoder,
 		callbackInputFn)
 	if handler == nil {
@@ -171,9 +174,12 @@ func TestCallbackHandlerBadStream(t *testing.T) {
 	storage.On("UpdateTestCaseResult", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	stream := new(streamService)
 	stream.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(fmt.Errorf("stream error"))
+	events := new(webhookEmitter)
+	events.On("EmitWebhookEvent", mock.Anything, mock.Anything).Return(nil)
 	handler := quizes.CreateCallbackHandler(
 		storage,
 		stream,
+		events,
 		jsonDecoder,
 		callbackInputFn)
 	if handler == nil {
@@ -194,9 +200,15 @@ func TestCallbackHandler(t *testing.T) {
 	storage.On("UpdateTestCaseResult", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	stream := new(streamService)
 	stream.On("Update", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	events := new(webhookEmitter)
+	events.On("EmitWebhookEvent", mock.Anything, shared.WebhookEvent{
+		Type:         shared.EventSubmissionJudged,
+		SubmissionID: "",
+	}).Return(nil)
 	handler := quizes.CreateCallbackHandler(
 		storage,
 		stream,
+		events,
 		jsonDecoder,
 		callbackInputFn)
 	if handler == nil {
@@ -210,4 +222,5 @@ func TestCallbackHandler(t *testing.T) {
 	handler(w, req)
 	require.Equal(t, http.StatusOK, w.Code)
 	require.NotContains(t, logBuf.String(), "error updating stream:")
+	events.AssertExpectations(t)
 }
@@ -0,0 +1,244 @@
+package quizestest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/server/quizes"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+)
+
+type finalizerStorage struct {
+	mock.Mock
+}
+
+func (s *finalizerStorage) FinalizeExpired(ctx context.Context, now time.Time) ([]shared.Participation, error) {
+	args := s.Called(ctx, now)
+	return args.Get(0).([]shared.Participation), args.Error(1)
+}
+
+type deadlineStorage struct {
+	mock.Mock
+}
+
+func (s *deadlineStorage) ParticipationStatus(ctx context.Context, userID string, quizID string) (shared.Participation, error) {
+	args := s.Called(ctx, userID, quizID)
+	return args.Get(0).(shared.Participation), args.Error(1)
+}
+
+func deadlineInputFn(r *http.Request) (quizes.DeadlineInput, error) {
+	return quizes.DeadlineInput{QuizID: "quiz-id"}, nil
+}
+
+func TestDeadlineBroker(t *testing.T) {
+	broker := quizes.NewDeadlineBroker()
+	first := broker.Subscribe("p1")
+	second := broker.Subscribe("p1")
+	other := broker.Subscribe("p2")
+	broker.Unsubscribe("p1", second)
+	broker.Publish("p1", shared.EndReasonTimeout)
+	select {
+	case reason := <-first:
+		if reason != shared.EndReasonTimeout {
+			t.Errorf("expected %s, got %s", shared.EndReasonTimeout, reason)
+		}
+	default:
+		t.Error("expected event for p1")
+	}
+	select {
+	case <-second:
+		t.Error("unsubscribed listener received an event")
+	case <-other:
+		t.Error("p2 received an event for p1")
+	default:
+	}
+}
+
+func TestFinalize(t *testing.T) {
+	storage := new(finalizerStorage)
+	storage.On("FinalizeExpired", mock.Anything, mock.Anything).Return([]shared.Participation{
+		{ID: "p1", EndReason: shared.EndReasonAbandoned},
+	}, nil)
+	events := new(webhookEmitter)
+	events.On("EmitWebhookEvent", mock.Anything, shared.WebhookEvent{
+		Type:            shared.EventParticipationFinished,
+		ParticipationID: "p1",
+	}).Return(nil)
+	notifications := new(notifier)
+	notifications.On("Notify", mock.Anything, shared.NotificationEvent{
+		Category:        shared.NotifyResults,
+		ParticipationID: "p1",
+	}).Return(nil)
+	broker := quizes.NewDeadlineBroker()
+	listener := broker.Subscribe("p1")
+	quizes.Finalize(context.Background(), storage, broker, events, notifications, time.Now())
+	select {
+	case reason := <-listener:
+		if reason != shared.EndReasonAbandoned {
+			t.Errorf("expected %s, got %s", shared.EndReasonAbandoned, reason)
+		}
+	default:
+		t.Error("expected event")
+	}
+	events.AssertExpectations(t)
+	notifications.AssertExpectations(t)
+}
+
+func TestFinalizeStorageError(t *testing.T) {
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	storage := new(finalizerStorage)
+	storage.On("FinalizeExpired", mock.Anything, mock.Anything).Return([]shared.Participation{}, errors.New("error"))
+	broker := quizes.NewDeadlineBroker()
+	events := new(webhookEmitter)
+	quizes.Finalize(context.Background(), storage, broker, events, new(notifier), time.Now())
+	storage.AssertExpectations(t)
+	events.AssertNotCalled(t, "EmitWebhookEvent", mock.Anything, mock.Anything)
+}
+
+func TestGetDeadlineInput(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/", nil)
+	req = WithUrlParam(req, "quizID", "invalid")
+	if _, err := quizes.GetDeadlineInput(req); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestDeadlineHandlerUnauthorized(t *testing.T) {
+	handler := quizes.CreateDeadlineHandler(&deadlineStorage{}, invalidAuthRepo{}, quizes.NewDeadlineBroker(), deadlineInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestDeadlineHandlerBadInput(t *testing.T) {
+	badInputFn := func(r *http.Request) (quizes.DeadlineInput, error) {
+		return quizes.DeadlineInput{}, errors.New("error")
+	}
+	handler := quizes.CreateDeadlineHandler(&deadlineStorage{}, authRepo{}, quizes.NewDeadlineBroker(), badInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestDeadlineHandlerParticipationError(t *testing.T) {
+	storage := new(deadlineStorage)
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(shared.Participation{}, errors.New("error"))
+	handler := quizes.CreateDeadlineHandler(storage, authRepo{}, quizes.NewDeadlineBroker(), deadlineInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+}
+
+func TestDeadlineHandlerFinished(t *testing.T) {
+	storage := new(deadlineStorage)
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(shared.Participation{
+		ID:        "p1",
+		EndReason: shared.EndReasonManual,
+	}, nil)
+	handler := quizes.CreateDeadlineHandler(storage, authRepo{}, quizes.NewDeadlineBroker(), deadlineInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if !strings.Contains(w.Body.String(), "event: timeover") {
+		t.Errorf("expected timeover event, got %s", w.Body.String())
+	}
+}
+
+func TestDeadlineHandlerPublished(t *testing.T) {
+	storage := new(deadlineStorage)
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(shared.Participation{
+		ID:        "p1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil)
+	broker := quizes.NewDeadlineBroker()
+	handler := quizes.CreateDeadlineHandler(storage, authRepo{}, broker, deadlineInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	done := make(chan bool)
+	go func() {
+		handler(w, req)
+		done <- true
+	}()
+	deadline := time.After(time.Second)
+	for {
+		broker.Publish("p1", shared.EndReasonTimeout)
+		select {
+		case <-done:
+			if !strings.Contains(w.Body.String(), "event: timeover") {
+				t.Errorf("expected timeover event, got %s", w.Body.String())
+			}
+			return
+		case <-deadline:
+			t.Fatal("handler did not receive the event")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestFormatDeadlineEvent(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	event := quizes.FormatDeadlineEvent(shared.Participation{ExpiresAt: expiresAt, PausedAt: time.Now()})
+	if !strings.HasPrefix(event, "event: deadline\n") {
+		t.Errorf("unexpected event %s", event)
+	}
+	if !strings.Contains(event, "setDeadline('2030-01-02T03:04:05Z', true)") {
+		t.Errorf("unexpected event %s", event)
+	}
+}
+
+func TestDeadlineHandlerAdjusted(t *testing.T) {
+	var calls atomic.Int32
+	storage := new(deadlineStorage)
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(shared.Participation{
+		ID:        "p1",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil).Run(func(args mock.Arguments) { calls.Add(1) })
+	broker := quizes.NewDeadlineBroker()
+	handler := quizes.CreateDeadlineHandler(storage, authRepo{}, broker, deadlineInputFn)
+	ctx, cancel := context.WithCancel(context.Background())
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/", nil)
+	w := httptest.NewRecorder()
+	done := make(chan bool)
+	go func() {
+		handler(w, req)
+		done <- true
+	}()
+	// wait until the handler subscribed and read the second status
+	deadline := time.After(time.Second)
+	for calls.Load() < 2 {
+		broker.Publish("p1", shared.DeadlineAdjusted)
+		select {
+		case <-deadline:
+			t.Fatal("handler did not receive the adjustment")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	cancel()
+	<-done
+	if !strings.Contains(w.Body.String(), "event: deadline") {
+		t.Errorf("expected deadline event, got %s", w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "event: timeover") {
+		t.Errorf("unexpected timeover event")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/kw3a/spotted-server/internal/server/quizes"
 	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
 )
 
 type endStorage struct{}
@@ -24,6 +25,12 @@ func (i invalidEndStorage) EndQuiz(ctx context.Context, userID string, quizID st
 	return shared.Offer{}, errors.New("error")
 }
 
+type finishedEndStorage struct{}
+
+func (f finishedEndStorage) EndQuiz(ctx context.Context, userID string, quizID string) (shared.Offer, error) {
+	return shared.Offer{ID: "1"}, shared.ErrAlreadyFinished
+}
+
 func endInputFn(r *http.Request) (quizes.EndInput, error) {
 	return quizes.EndInput{QuizID: "1"}, nil
 }
@@ -69,7 +76,7 @@ func TestEndHandlerBadInput(t *testing.T) {
 	invalidInputFn := func(r *http.Request) (quizes.EndInput, error) {
 		return quizes.EndInput{}, errors.New("error")
 	}
-	handler := quizes.CreateEndHandler(&endStorage{}, &authRepo{}, invalidInputFn)
+	handler := quizes.CreateEndHandler(&endStorage{}, &authRepo{}, new(webhookEmitter), new(notifier), invalidInputFn)
 	if handler == nil {
 		t.Error("handler is nil")
 	}
@@ -82,7 +89,7 @@ func TestEndHandlerBadInput(t *testing.T) {
 }
 
 func TestEndHandlerInvalidAuth(t *testing.T) {
-	handler := quizes.CreateEndHandler(&endStorage{}, &invalidAuthRepo{}, endInputFn)
+	handler := quizes.CreateEndHandler(&endStorage{}, &invalidAuthRepo{}, new(webhookEmitter), new(notifier), endInputFn)
 	if handler == nil {
 		t.Error("handler is nil")
 	}
@@ -98,7 +105,7 @@ func TestEndHandlerInvalidAuth(t *testing.T) {
 }
 
 func TestEndHandlerInvalidStorage(t *testing.T) {
-	handler := quizes.CreateEndHandler(&invalidEndStorage{}, &authRepo{}, endInputFn)
+	handler := quizes.CreateEndHandler(&invalidEndStorage{}, &authRepo{}, new(webhookEmitter), new(notifier), endInputFn)
 	if handler == nil {
 		t.Error("handler is nil")
 	}
@@ -111,7 +118,17 @@ func TestEndHandlerInvalidStorage(t *testing.T) {
 }
 
 func TestEndHandler(t *testing.T) {
-	handler := quizes.CreateEndHandler(&endStorage{}, &authRepo{}, endInputFn)
+	events := new(webhookEmitter)
+	events.On("EmitWebhookEvent", mock.Anything, shared.WebhookEvent{
+		Type:   shared.EventParticipationFinished,
+		QuizID: "1",
+	}).Return(nil)
+	notifications := new(notifier)
+	notifications.On("Notify", mock.Anything, shared.NotificationEvent{
+		Category: shared.NotifyResults,
+		QuizID:   "1",
+	}).Return(nil)
+	handler := quizes.CreateEndHandler(&endStorage{}, &authRepo{}, events, notifications, endInputFn)
 	if handler == nil {
 		t.Error("handler is nil")
 	}
@@ -121,7 +138,26 @@ func TestEndHandler(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Error("invalid status code")
 	}
-	if w.Header().Get("HX-Redirect") != "/preamble/1" {
-		t.Errorf("invalid redirect. want: '/preamble/1', got:%s",w.Header().Get("HX-Redirect"))
+	if w.Header().Get("HX-Redirect") != "/applications#offer-1" {
+		t.Errorf("invalid redirect. want: '/applications#offer-1', got:%s",w.Header().Get("HX-Redirect"))
+	}
+	events.AssertExpectations(t)
+	notifications.AssertExpectations(t)
+}
+
+func TestEndHandlerAlreadyFinished(t *testing.T) {
+	events := new(webhookEmitter)
+	notifications := new(notifier)
+	handler := quizes.CreateEndHandler(&finishedEndStorage{}, &authRepo{}, events, notifications, endInputFn)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Error("invalid status code")
+	}
+	if w.Header().Get("HX-Redirect") != "/applications#offer-1" {
+		t.Errorf("invalid redirect. want: '/applications#offer-1', got:%s", w.Header().Get("HX-Redirect"))
 	}
+	events.AssertNotCalled(t, "EmitWebhookEvent", mock.Anything, mock.Anything)
+	notifications.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
 }
@@ -6,19 +6,38 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/kw3a/spotted-server/internal/server/quizes"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
 )
 
 type participateStorage struct{}
 
+func (p *participateStorage) SelectQuiz(ctx context.Context, quizID string) (shared.Quiz, error) {
+	return shared.Quiz{ID: quizID}, nil
+}
+
+func (p *participateStorage) ClaimInvitation(ctx context.Context, quizID, userID, email, code string) error {
+	return nil
+}
+
 func (p *participateStorage) Participate(ctx context.Context, userID string, quizID string) error {
 	return nil
 }
 
 type invalidParticipateStorage struct{}
 
+func (i *invalidParticipateStorage) SelectQuiz(ctx context.Context, quizID string) (shared.Quiz, error) {
+	return shared.Quiz{ID: quizID}, nil
+}
+
+func (i *invalidParticipateStorage) ClaimInvitation(ctx context.Context, quizID, userID, email, code string) error {
+	return nil
+}
+
 func (i *invalidParticipateStorage) Participate(ctx context.Context, userID string, quizID string) error {
 	return errors.New("error")
 }
@@ -65,7 +84,7 @@ func TestParticipateHandlerBadInput(t *testing.T) {
 	invalidInputFn := func(r *http.Request) (quizes.ParticipateInput, error) {
 		return quizes.ParticipateInput{}, errors.New("error")
 	}
-	handler := quizes.CreateParticipateHandler(&participateStorage{}, &authRepo{}, invalidInputFn)
+	handler := quizes.CreateParticipateHandler(&templates{}, &participateStorage{}, &authRepo{}, new(webhookEmitter), new(notifier), invalidInputFn)
 	if handler == nil {
 		t.Error("expected handler")
 	}
@@ -78,7 +97,7 @@ func TestParticipateHandlerBadInput(t *testing.T) {
 }
 
 func TestParticipateHandlerBadAuth(t *testing.T) {
-	handler := quizes.CreateParticipateHandler(&participateStorage{}, &invalidAuthRepo{}, participateInputFn)
+	handler := quizes.CreateParticipateHandler(&templates{}, &participateStorage{}, &invalidAuthRepo{}, new(webhookEmitter), new(notifier), participateInputFn)
 	if handler == nil {
 		t.Error("expected handler")
 	}
@@ -91,7 +110,7 @@ func TestParticipateHandlerBadAuth(t *testing.T) {
 }
 
 func TestParticipateHandlerBadStorage(t *testing.T) {
-	handler := quizes.CreateParticipateHandler(&invalidParticipateStorage{}, &authRepo{}, participateInputFn)
+	handler := quizes.CreateParticipateHandler(&templates{}, &invalidParticipateStorage{}, &authRepo{}, new(webhookEmitter), new(notifier), participateInputFn)
 	if handler == nil {
 		t.Error("expected handler")
 	}
@@ -104,7 +123,17 @@ func TestParticipateHandlerBadStorage(t *testing.T) {
 }
 
 func TestParticipateHandler(t *testing.T) {
-	handler := quizes.CreateParticipateHandler(&participateStorage{}, &authRepo{}, participateInputFn)
+	events := new(webhookEmitter)
+	events.On("EmitWebhookEvent", mock.Anything, shared.WebhookEvent{
+		Type:   shared.EventParticipationStarted,
+		QuizID: "1",
+	}).Return(nil)
+	notifications := new(notifier)
+	notifications.On("Notify", mock.Anything, shared.NotificationEvent{
+		Category: shared.NotifyApplicant,
+		QuizID:   "1",
+	}).Return(nil)
+	handler := quizes.CreateParticipateHandler(&templates{}, &participateStorage{}, &authRepo{}, events, notifications, participateInputFn)
 	if handler == nil {
 		t.Error("expected handler")
 	}
@@ -117,4 +146,110 @@ func TestParticipateHandler(t *testing.T) {
 	if w.Header().Get("HX-Redirect") != "/quizes/1" {
 		t.Error("invalid redirect")
 	}
+	events.AssertExpectations(t)
+	notifications.AssertExpectations(t)
+}
+
+type accessParticipateStorage struct {
+	mock.Mock
+}
+
+func (s *accessParticipateStorage) SelectQuiz(ctx context.Context, quizID string) (shared.Quiz, error) {
+	args := s.Called(ctx, quizID)
+	return args.Get(0).(shared.Quiz), args.Error(1)
+}
+
+func (s *accessParticipateStorage) ClaimInvitation(ctx context.Context, quizID, userID, email, code string) error {
+	args := s.Called(ctx, quizID, userID, email, code)
+	return args.Error(0)
+}
+
+func (s *accessParticipateStorage) Participate(ctx context.Context, userID string, quizID string) error {
+	args := s.Called(ctx, userID, quizID)
+	return args.Error(0)
+}
+
+func TestParticipateHandlerBadStorageSelectQuiz(t *testing.T) {
+	storage := new(accessParticipateStorage)
+	storage.On("SelectQuiz", mock.Anything, "1").Return(shared.Quiz{}, errors.New("error"))
+	handler := quizes.CreateParticipateHandler(&templates{}, storage, &authRepo{}, new(webhookEmitter), new(notifier), participateInputFn)
+	req := formRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Error("expected bad request")
+	}
+}
+
+func TestParticipateHandlerRefused(t *testing.T) {
+	cases := map[string]struct {
+		quiz     shared.Quiz
+		code     string
+		claimErr error
+	}{
+		"not open":   {quiz: shared.Quiz{ID: "1", OpensAt: time.Now().Add(time.Hour)}},
+		"closed":     {quiz: shared.Quiz{ID: "1", ClosesAt: time.Now().Add(-time.Hour)}},
+		"no code":    {quiz: shared.Quiz{ID: "1", InviteOnly: true}},
+		"wrong user": {quiz: shared.Quiz{ID: "1", InviteOnly: true}, code: "ABCD2345", claimErr: shared.ErrInvitationEmail},
+	}
+	for name, c := range cases {
+		storage := new(accessParticipateStorage)
+		storage.On("SelectQuiz", mock.Anything, "1").Return(c.quiz, nil)
+		storage.On("ClaimInvitation", mock.Anything, "1", mock.Anything, mock.Anything, c.code).Return(c.claimErr)
+		inputFn := func(r *http.Request) (quizes.ParticipateInput, error) {
+			return quizes.ParticipateInput{QuizID: "1", Code: c.code}, nil
+		}
+		handler := quizes.CreateParticipateHandler(&templates{}, storage, &authRepo{}, new(webhookEmitter), new(notifier), inputFn)
+		req := formRequest("POST", "/", nil)
+		w := httptest.NewRecorder()
+		handler(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: expected ok, got %d", name, w.Code)
+		}
+		if w.Header().Get("HX-Retarget") != "#access-alert" {
+			t.Errorf("%s: expected the alert to be retargeted", name)
+		}
+		storage.AssertNotCalled(t, "Participate", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
+
+func TestParticipateHandlerBadStorageClaim(t *testing.T) {
+	storage := new(accessParticipateStorage)
+	storage.On("SelectQuiz", mock.Anything, "1").Return(shared.Quiz{ID: "1", InviteOnly: true}, nil)
+	storage.On("ClaimInvitation", mock.Anything, "1", mock.Anything, mock.Anything, "ABCD2345").Return(errors.New("error"))
+	inputFn := func(r *http.Request) (quizes.ParticipateInput, error) {
+		return quizes.ParticipateInput{QuizID: "1", Code: "ABCD2345"}, nil
+	}
+	handler := quizes.CreateParticipateHandler(&templates{}, storage, &authRepo{}, new(webhookEmitter), new(notifier), inputFn)
+	req := formRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected internal server error, got %d", w.Code)
+	}
+}
+
+func TestParticipateHandlerInvited(t *testing.T) {
+	storage := new(accessParticipateStorage)
+	storage.On("SelectQuiz", mock.Anything, "1").Return(shared.Quiz{ID: "1", InviteOnly: true}, nil)
+	storage.On("ClaimInvitation", mock.Anything, "1", mock.Anything, mock.Anything, "ABCD2345").Return(nil)
+	storage.On("Participate", mock.Anything, mock.Anything, "1").Return(nil)
+	inputFn := func(r *http.Request) (quizes.ParticipateInput, error) {
+		return quizes.ParticipateInput{QuizID: "1", Code: "ABCD2345"}, nil
+	}
+	events := new(webhookEmitter)
+	events.On("EmitWebhookEvent", mock.Anything, mock.Anything).Return(nil)
+	notifications := new(notifier)
+	notifications.On("Notify", mock.Anything, mock.Anything).Return(nil)
+	handler := quizes.CreateParticipateHandler(&templates{}, storage, &authRepo{}, events, notifications, inputFn)
+	req := formRequest("POST", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Error("expected ok")
+	}
+	if w.Header().Get("HX-Redirect") != "/quizes/1" {
+		t.Error("invalid redirect")
+	}
+	storage.AssertExpectations(t)
 }
@@ -0,0 +1,165 @@
+package quizestest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/server/quizes"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type proctoringEventStorageMock struct {
+	mock.Mock
+}
+
+func (m *proctoringEventStorageMock) InsertProctoringEvent(ctx context.Context, participationID string, event shared.ProctoringEvent) error {
+	args := m.Called(ctx, participationID, event)
+	return args.Error(0)
+}
+
+func (m *proctoringEventStorageMock) CountProctoringEvents(ctx context.Context, participationID, kind string) (int32, error) {
+	args := m.Called(ctx, participationID, kind)
+	return args.Get(0).(int32), args.Error(1)
+}
+
+func (m *proctoringEventStorageMock) SelectProctoringRule(ctx context.Context, quizID, kind string) (shared.ProctoringRule, error) {
+	args := m.Called(ctx, quizID, kind)
+	return args.Get(0).(shared.ProctoringRule), args.Error(1)
+}
+
+func (m *proctoringEventStorageMock) ParticipationStatus(ctx context.Context, userID string, quizID string) (shared.Participation, error) {
+	args := m.Called(ctx, userID, quizID)
+	return args.Get(0).(shared.Participation), args.Error(1)
+}
+
+func proctoringInputFn(r *http.Request) (quizes.ProctoringEventInput, error) {
+	return quizes.ProctoringEventInput{
+		QuizID: "quiz-id",
+		Event:  shared.ProctoringEvent{Kind: shared.ProctoringTabSwitch},
+	}, nil
+}
+
+func activeParticipation() shared.Participation {
+	return shared.Participation{ID: "part-id", ExpiresAt: time.Now().Add(time.Hour)}
+}
+
+func TestGetProctoringEventInput(t *testing.T) {
+	req := formRequest("POST", "/proctoring", map[string][]string{
+		"quizID": {"f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+		"kind":   {shared.ProctoringPaste},
+		"detail": {"350"},
+	})
+	input, err := quizes.GetProctoringEventInput(req)
+	require.NoError(t, err)
+	require.Equal(t, shared.ProctoringPaste, input.Event.Kind)
+	require.Equal(t, "350", input.Event.Detail)
+}
+
+func TestGetProctoringEventInputBadKind(t *testing.T) {
+	req := formRequest("POST", "/proctoring", map[string][]string{
+		"quizID": {"f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+		"kind":   {"scroll"},
+	})
+	_, err := quizes.GetProctoringEventInput(req)
+	require.Error(t, err)
+}
+
+func TestProctoringWarning(t *testing.T) {
+	rule := shared.ProctoringRule{Kind: shared.ProctoringTabSwitch, Threshold: 3}
+	require.False(t, quizes.ProctoringWarning(rule, 2).Warning)
+	require.True(t, quizes.ProctoringWarning(rule, 3).Warning)
+	require.False(t, quizes.ProctoringWarning(shared.ProctoringRule{}, 10).Warning)
+}
+
+func TestProctoringEventHandlerUnauthorized(t *testing.T) {
+	handler := quizes.CreateProctoringEventHandler(&proctoringEventStorageMock{}, &invalidAuthRepo{}, proctoringInputFn)
+	req, _ := http.NewRequest("POST", "/proctoring", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestProctoringEventHandlerBadInput(t *testing.T) {
+	badInputFn := func(r *http.Request) (quizes.ProctoringEventInput, error) {
+		return quizes.ProctoringEventInput{}, errors.New("bad input")
+	}
+	handler := quizes.CreateProctoringEventHandler(&proctoringEventStorageMock{}, &authRepo{}, badInputFn)
+	req, _ := http.NewRequest("POST", "/proctoring", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestProctoringEventHandlerParticipationError(t *testing.T) {
+	storage := new(proctoringEventStorageMock)
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(shared.Participation{}, errors.New("error"))
+	handler := quizes.CreateProctoringEventHandler(storage, &authRepo{}, proctoringInputFn)
+	req, _ := http.NewRequest("POST", "/proctoring", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestProctoringEventHandlerExpired(t *testing.T) {
+	storage := new(proctoringEventStorageMock)
+	expired := shared.Participation{ID: "part-id", ExpiresAt: time.Now().Add(-time.Hour)}
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(expired, nil)
+	handler := quizes.CreateProctoringEventHandler(storage, &authRepo{}, proctoringInputFn)
+	req, _ := http.NewRequest("POST", "/proctoring", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Contains(t, w.Body.String(), "your participation is over")
+}
+
+func TestProctoringEventHandlerInsertError(t *testing.T) {
+	storage := new(proctoringEventStorageMock)
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(activeParticipation(), nil)
+	storage.On("InsertProctoringEvent", mock.Anything, "part-id", mock.Anything).Return(errors.New("error"))
+	handler := quizes.CreateProctoringEventHandler(storage, &authRepo{}, proctoringInputFn)
+	req, _ := http.NewRequest("POST", "/proctoring", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestProctoringEventHandlerRuleError(t *testing.T) {
+	storage := new(proctoringEventStorageMock)
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(activeParticipation(), nil)
+	storage.On("InsertProctoringEvent", mock.Anything, "part-id", mock.Anything).Return(nil)
+	storage.On("CountProctoringEvents", mock.Anything, "part-id", shared.ProctoringTabSwitch).Return(int32(1), nil)
+	storage.On("SelectProctoringRule", mock.Anything, "quiz-id", shared.ProctoringTabSwitch).Return(shared.ProctoringRule{}, errors.New("error"))
+	handler := quizes.CreateProctoringEventHandler(storage, &authRepo{}, proctoringInputFn)
+	req, _ := http.NewRequest("POST", "/proctoring", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestProctoringEventHandlerWarning(t *testing.T) {
+	storage := new(proctoringEventStorageMock)
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, "quiz-id").Return(activeParticipation(), nil)
+	storage.On("InsertProctoringEvent", mock.Anything, "part-id", mock.MatchedBy(func(e shared.ProctoringEvent) bool {
+		return e.ID != "" && e.Kind == shared.ProctoringTabSwitch
+	})).Return(nil)
+	storage.On("CountProctoringEvents", mock.Anything, "part-id", shared.ProctoringTabSwitch).Return(int32(3), nil)
+	storage.On("SelectProctoringRule", mock.Anything, "quiz-id", shared.ProctoringTabSwitch).Return(shared.ProctoringRule{
+		Kind:      shared.ProctoringTabSwitch,
+		Threshold: 3,
+	}, nil)
+	handler := quizes.CreateProctoringEventHandler(storage, &authRepo{}, proctoringInputFn)
+	req, _ := http.NewRequest("POST", "/proctoring", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var output quizes.ProctoringEventOutput
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&output))
+	require.True(t, output.Warning)
+}
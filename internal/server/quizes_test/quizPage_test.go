@@ -31,8 +31,8 @@ type quizPageStorage struct {
 	mock.Mock
 }
 
-func (q *quizPageStorage) LastSrc(ctx context.Context, userID string, problemID string, languageID int32) (string, error) {
-	args := q.Called(ctx, userID, problemID, languageID)
+func (q *quizPageStorage) LastSrc(ctx context.Context, userID string, quizID string, problemID string, languageID int32) (string, error) {
+	args := q.Called(ctx, userID, quizID, problemID, languageID)
 	return args.String(0), args.Error(1)
 }
 func (q *quizPageStorage) ParticipationStatus(ctx context.Context, userID string, quizID string) (shared.Participation, error) {
@@ -51,12 +51,12 @@ func (q *quizPageStorage) SelectProblem(ctx context.Context, problemID string) (
 	args := q.Called(ctx, problemID)
 	return args.Get(0).(shared.Problem), args.Error(1)
 }
-func (q *quizPageStorage) SelectProblemIDs(ctx context.Context, quizID string) ([]string, error) {
-	args := q.Called(ctx, quizID)
+func (q *quizPageStorage) SelectProblemIDs(ctx context.Context, participationID string, quizID string) ([]string, error) {
+	args := q.Called(ctx, participationID, quizID)
 	return args.Get(0).([]string), args.Error(1)
 }
-func (q *quizPageStorage) SelectScore(ctx context.Context, userID string, problemID string) (shared.Score, error) {
-	args := q.Called(ctx, userID, problemID)
+func (q *quizPageStorage) SelectScore(ctx context.Context, userID string, quizID string, problemID string) (shared.Score, error) {
+	args := q.Called(ctx, userID, quizID, problemID)
 	return args.Get(0).(shared.Score), args.Error(1)
 }
 
@@ -194,7 +194,7 @@ func TestQuizPageHandlerBadStorageSelectProblemIDs(t *testing.T) {
 	storage := new(quizPageStorage)
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
-	storage.On("SelectProblemIDs", mock.Anything, mock.Anything).Return([]string{}, errors.New("error"))
+	storage.On("SelectProblemIDs", mock.Anything, mock.Anything, mock.Anything).Return([]string{}, errors.New("error"))
 	handler := quizes.CreateQuizPageHandler(
 		&templates{},
 		storage,
@@ -219,8 +219,8 @@ func TestQuizPageHandlerBadStorageSelectScore(t *testing.T) {
 	storage := new(quizPageStorage)
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
-	storage.On("SelectProblemIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
-	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, errors.New("error"))
+	storage.On("SelectProblemIDs", mock.Anything, mock.Anything, mock.Anything).Return([]string{}, nil)
+	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, errors.New("error"))
 	handler := quizes.CreateQuizPageHandler(
 		&templates{},
 		storage,
@@ -245,8 +245,8 @@ func TestQuizPageHandlerBadStorageSelectProblem(t *testing.T) {
 	storage := new(quizPageStorage)
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
-	storage.On("SelectProblemIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
-	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, nil)
+	storage.On("SelectProblemIDs", mock.Anything, mock.Anything, mock.Anything).Return([]string{}, nil)
+	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, nil)
 	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{}, errors.New("error"))
 	handler := quizes.CreateQuizPageHandler(
 		&templates{},
@@ -272,8 +272,8 @@ func TestQuizPageHandlerBadStorageSelectExamples(t *testing.T) {
 	storage := new(quizPageStorage)
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
-	storage.On("SelectProblemIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
-	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, nil)
+	storage.On("SelectProblemIDs", mock.Anything, mock.Anything, mock.Anything).Return([]string{}, nil)
+	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, nil)
 	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{}, nil)
 	storage.On("SelectExamples", mock.Anything, mock.Anything).Return([]shared.Example{}, errors.New("error"))
 	handler := quizes.CreateQuizPageHandler(
@@ -300,8 +300,8 @@ func TestQuizPageHandlerBadStorageSelectLanguages(t *testing.T) {
 	storage := new(quizPageStorage)
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
-	storage.On("SelectProblemIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
-	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, nil)
+	storage.On("SelectProblemIDs", mock.Anything, mock.Anything, mock.Anything).Return([]string{}, nil)
+	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, nil)
 	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{}, nil)
 	storage.On("SelectExamples", mock.Anything, mock.Anything).Return([]shared.Example{}, nil)
 	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, errors.New("error"))
@@ -329,12 +329,12 @@ func TestQuizPageHandlerBadStorageLastSource(t *testing.T) {
 	storage := new(quizPageStorage)
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
-	storage.On("SelectProblemIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
-	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, nil)
+	storage.On("SelectProblemIDs", mock.Anything, mock.Anything, mock.Anything).Return([]string{}, nil)
+	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, nil)
 	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{}, nil)
 	storage.On("SelectExamples", mock.Anything, mock.Anything).Return([]shared.Example{}, nil)
 	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
-	storage.On("LastSrc", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", errors.New("error"))
+	storage.On("LastSrc", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", errors.New("error"))
 	handler := quizes.CreateQuizPageHandler(
 		&templates{},
 		storage,
@@ -359,12 +359,12 @@ func TestQuizPageHandlerBadTemplate(t *testing.T) {
 	storage := new(quizPageStorage)
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
-	storage.On("SelectProblemIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
-	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, nil)
+	storage.On("SelectProblemIDs", mock.Anything, mock.Anything, mock.Anything).Return([]string{}, nil)
+	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, nil)
 	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{}, nil)
 	storage.On("SelectExamples", mock.Anything, mock.Anything).Return([]shared.Example{}, nil)
 	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
-	storage.On("LastSrc", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	storage.On("LastSrc", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	handler := quizes.CreateQuizPageHandler(
 		&invalidTemplates{},
 		storage,
@@ -389,12 +389,12 @@ func TestQuizPageHandler(t *testing.T) {
 	storage := new(quizPageStorage)
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
-	storage.On("SelectProblemIDs", mock.Anything, mock.Anything).Return([]string{}, nil)
-	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, nil)
+	storage.On("SelectProblemIDs", mock.Anything, mock.Anything, mock.Anything).Return([]string{}, nil)
+	storage.On("SelectScore", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(shared.Score{}, nil)
 	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{}, nil)
 	storage.On("SelectExamples", mock.Anything, mock.Anything).Return([]shared.Example{}, nil)
 	storage.On("SelectLanguages", mock.Anything, mock.Anything).Return([]shared.Language{}, nil)
-	storage.On("LastSrc", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
+	storage.On("LastSrc", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return("", nil)
 	handler := quizes.CreateQuizPageHandler(
 		&templates{},
 		storage,
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/kw3a/spotted-server/internal/server/codejudge"
 	"github.com/kw3a/spotted-server/internal/server/quizes"
 	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/kw3a/spotted-server/internal/server/sqljudge"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -44,6 +46,10 @@ func (r *runStorage) GetTestCases(ctx context.Context, problemID string) ([]code
 	args := r.Called(ctx, problemID)
 	return args.Get(0).([]codejudge.TestCase), args.Error(1)
 }
+func (r *runStorage) SelectProblem(ctx context.Context, problemID string) (shared.Problem, error) {
+	args := r.Called(ctx, problemID)
+	return args.Get(0).(shared.Problem), args.Error(1)
+}
 func (r *runStorage) ParticipationStatus(ctx context.Context, userID string, quizID string) (shared.Participation, error) {
 	args := r.Called(ctx, userID, quizID)
 	return args.Get(0).(shared.Participation), args.Error(1)
@@ -197,6 +203,31 @@ func TestRunHandlerBadStorageParticipationExpired(t *testing.T) {
 	}
 }
 
+func TestRunHandlerParticipationPaused(t *testing.T) {
+	storage := new(runStorage)
+	paused := shared.Participation{
+		ExpiresAt: time.Now().Add(-time.Hour),
+		PausedAt:  time.Now().Add(-2 * time.Hour),
+	}
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(paused, nil)
+	handler := quizes.CreateRunHandler(
+		&templates{},
+		storage,
+		&authRepo{},
+		&streamService{},
+		&judgeService{},
+		60*time.Second,
+		runInputFn,
+	)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Error("expected status unauthorized")
+	}
+	storage.AssertNotCalled(t, "GetTestCases", mock.Anything, mock.Anything)
+}
+
 func TestRunHandlerBadStorageGetTestCases(t *testing.T) {
 	storage := new(runStorage)
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
@@ -222,11 +253,36 @@ func TestRunHandlerBadStorageGetTestCases(t *testing.T) {
 	}
 }
 
+func TestRunHandlerQuestion(t *testing.T) {
+	storage := new(runStorage)
+	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
+	storage.On("GetTestCases", mock.Anything, mock.Anything).Return([]codejudge.TestCase{}, nil)
+	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{Question: &shared.Question{Kind: shared.QuestionText}}, nil)
+	handler := quizes.CreateRunHandler(
+		&templates{},
+		storage,
+		&authRepo{},
+		&streamService{},
+		&judgeService{},
+		60*time.Second,
+		runInputFn,
+	)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Error("expected status bad request")
+	}
+	storage.AssertNotCalled(t, "CreateSubmission", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestRunHandlerBadStorageCreateSubmission(t *testing.T) {
 	storage := new(runStorage)
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
 	storage.On("GetTestCases", mock.Anything, mock.Anything).Return([]codejudge.TestCase{}, nil)
+	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{}, nil)
 	storage.On("CreateSubmission", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error"))
 	handler := quizes.CreateRunHandler(
 		&templates{},
@@ -275,6 +331,7 @@ func TestRunHandlerBadJudge(t *testing.T) {
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
 	storage.On("GetTestCases", mock.Anything, mock.Anything).Return([]codejudge.TestCase{}, nil)
+	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{}, nil)
 	storage.On("CreateSubmission", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	handler := quizes.CreateRunHandler(
 		&templates{},
@@ -301,6 +358,7 @@ func TestRunHandlerBadStream(t *testing.T) {
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
 	storage.On("GetTestCases", mock.Anything, mock.Anything).Return([]codejudge.TestCase{}, nil)
+	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{}, nil)
 	storage.On("CreateSubmission", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	stream := new(streamService)
 	stream.On("Register", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("error"))
@@ -329,6 +387,7 @@ func TestRunHandlerBadTemplate(t *testing.T) {
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
 	storage.On("GetTestCases", mock.Anything, mock.Anything).Return([]codejudge.TestCase{}, nil)
+	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{}, nil)
 	storage.On("CreateSubmission", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	stream := new(streamService)
 	stream.On("Register", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -357,6 +416,7 @@ func TestRunHandler(t *testing.T) {
 	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
 	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
 	storage.On("GetTestCases", mock.Anything, mock.Anything).Return([]codejudge.TestCase{}, nil)
+	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{}, nil)
 	storage.On("CreateSubmission", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	stream := new(streamService)
 	stream.On("Register", mock.Anything, mock.Anything, mock.Anything).Return(nil)
@@ -379,3 +439,138 @@ func TestRunHandler(t *testing.T) {
 		t.Error("expected status ok")
 	}
 }
+
+type judgeMock struct {
+	mock.Mock
+}
+
+func (j *judgeMock) Send(dbTestCases []codejudge.TestCase, submission codejudge.Submission) ([]string, error) {
+	args := j.Called(dbTestCases, submission)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func TestRunHandlerSignatureUnsupportedLanguage(t *testing.T) {
+	storage := new(runStorage)
+	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
+	storage.On("GetTestCases", mock.Anything, mock.Anything).Return([]codejudge.TestCase{}, nil)
+	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{Signature: "sum(a int, b int) int"}, nil)
+	handler := quizes.CreateRunHandler(
+		&templates{},
+		storage,
+		&authRepo{},
+		&streamService{},
+		&judgeService{},
+		60*time.Second,
+		runInputFn,
+	)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	storage.AssertNotCalled(t, "CreateSubmission", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRunHandlerSignatureWrapsSource(t *testing.T) {
+	src := "def sum(a, b):\n    return a + b\n"
+	inputFn := func(r *http.Request) (quizes.RunInput, error) {
+		return quizes.RunInput{Src: src, LanguageID: 71}, nil
+	}
+	storage := new(runStorage)
+	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
+	storage.On("GetTestCases", mock.Anything, mock.Anything).Return([]codejudge.TestCase{}, nil)
+	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{Signature: "sum(a int, b int) int"}, nil)
+	storage.On("CreateSubmission", mock.Anything, mock.Anything, mock.Anything, mock.Anything, src, int32(71)).Return(nil)
+	judge := new(judgeMock)
+	judge.On("Send", mock.Anything, mock.MatchedBy(func(s codejudge.Submission) bool {
+		return strings.HasPrefix(s.Src, src) && strings.Contains(s.Src, "_spotted_main()")
+	})).Return([]string{"token"}, nil)
+	stream := new(streamService)
+	stream.On("Register", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	handler := quizes.CreateRunHandler(
+		&templates{},
+		storage,
+		&authRepo{},
+		stream,
+		judge,
+		60*time.Second,
+		inputFn,
+	)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	judge.AssertExpectations(t)
+	storage.AssertExpectations(t)
+}
+
+func TestRunHandlerDatabaseScripts(t *testing.T) {
+	src := "SELECT name FROM emp;"
+	inputFn := func(r *http.Request) (quizes.RunInput, error) {
+		return quizes.RunInput{Src: src, LanguageID: 71}, nil
+	}
+	storage := new(runStorage)
+	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
+	storage.On("GetTestCases", mock.Anything, mock.Anything).Return([]codejudge.TestCase{{ID: "tc", Input: "INSERT INTO emp VALUES ('bo');", ExpectedOutput: "bo"}}, nil)
+	db := &shared.Database{Schema: "CREATE TABLE emp(name TEXT);"}
+	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{Database: db}, nil)
+	storage.On("CreateSubmission", mock.Anything, mock.Anything, mock.Anything, mock.Anything, src, sqljudge.LanguageID).Return(nil)
+	judge := new(judgeMock)
+	judge.On("Send", mock.MatchedBy(func(tcs []codejudge.TestCase) bool {
+		return len(tcs) == 1 && strings.Contains(tcs[0].Src, "INSERT INTO emp VALUES ('bo');") && strings.Contains(tcs[0].Src, "SELECT name FROM emp")
+	}), mock.MatchedBy(func(s codejudge.Submission) bool {
+		return s.LanguageID == sqljudge.LanguageID
+	})).Return([]string{"token"}, nil)
+	stream := new(streamService)
+	stream.On("Register", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	handler := quizes.CreateRunHandler(
+		&templates{},
+		storage,
+		&authRepo{},
+		stream,
+		judge,
+		60*time.Second,
+		inputFn,
+	)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	judge.AssertExpectations(t)
+	storage.AssertExpectations(t)
+}
+
+func TestRunHandlerDatabaseMultipleStatements(t *testing.T) {
+	inputFn := func(r *http.Request) (quizes.RunInput, error) {
+		return quizes.RunInput{Src: "DELETE FROM emp; SELECT 1", LanguageID: 71}, nil
+	}
+	storage := new(runStorage)
+	inTime := shared.Participation{ExpiresAt: time.Now().Add(time.Hour)}
+	storage.On("ParticipationStatus", mock.Anything, mock.Anything, mock.Anything).Return(inTime, nil)
+	storage.On("GetTestCases", mock.Anything, mock.Anything).Return([]codejudge.TestCase{}, nil)
+	storage.On("SelectProblem", mock.Anything, mock.Anything).Return(shared.Problem{Database: &shared.Database{Schema: "CREATE TABLE emp(name TEXT);"}}, nil)
+	handler := quizes.CreateRunHandler(
+		&templates{},
+		storage,
+		&authRepo{},
+		new(streamService),
+		new(judgeMock),
+		60*time.Second,
+		inputFn,
+	)
+	req, _ := http.NewRequest("GET", "/", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	storage.AssertNotCalled(t, "CreateSubmission", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
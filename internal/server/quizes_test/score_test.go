@@ -18,12 +18,12 @@ func scoreInputFn(r *http.Request) (quizes.ScoreInput, error) {
 
 type scoreStorage struct{}
 
-func (s scoreStorage) SelectScore(ctx context.Context, userID string, problemID string) (shared.Score, error) {
+func (s scoreStorage) SelectScore(ctx context.Context, userID string, quizID string, problemID string) (shared.Score, error) {
 	return shared.Score{}, nil
 }
 
 type invalidScoreStorage struct{}
-func (i *invalidScoreStorage) SelectScore(ctx context.Context, userID string, problemID string) (shared.Score, error) {
+func (i *invalidScoreStorage) SelectScore(ctx context.Context, userID string, quizID string, problemID string) (shared.Score, error) {
 	return shared.Score{}, errors.New("error")
 }
 
@@ -39,6 +39,19 @@ func TestGetScoreInputEmpty(t *testing.T) {
 }
 func TestGetScoreInputBadQuizID(t *testing.T) {
 	formValues := map[string][]string{
+		"quizID":    {"invalid"},
+		"problemID": {uuid.NewString()},
+	}
+	req := formRequest("GET", "/", formValues)
+	_, err := quizes.GetScoreInput(req)
+	if err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestGetScoreInputBadProblemID(t *testing.T) {
+	formValues := map[string][]string{
+		"quizID":    {uuid.NewString()},
 		"problemID": {"invalid"},
 	}
 	req := formRequest("GET", "/", formValues)
@@ -49,8 +62,9 @@ func TestGetScoreInputBadQuizID(t *testing.T) {
 }
 
 func TestGetScoreInput(t *testing.T) {
-	problemID := uuid.NewString()
+	quizID, problemID := uuid.NewString(), uuid.NewString()
 	formValues := map[string][]string{
+		"quizID":    {quizID},
 		"problemID": {problemID},
 	}
 	req := formRequest("GET", "/", formValues)
@@ -58,8 +72,8 @@ func TestGetScoreInput(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	if input.ProblemID != problemID {
-		t.Error("invalid problem ID")
+	if input.QuizID != quizID || input.ProblemID != problemID {
+		t.Error("invalid quiz or problem ID")
 	}
 }
 
@@ -17,18 +17,19 @@ func sourceInputFn(r *http.Request) (quizes.SourceInput, error) {
 }
 
 type sourceStorage struct{}
-func (s sourceStorage) LastSrc(ctx context.Context, userID string, problemID string, languageID int32) (string, error) {
+func (s sourceStorage) LastSrc(ctx context.Context, userID string, quizID string, problemID string, languageID int32) (string, error) {
 	return "", nil
 }
 
 type invalidSourceStorage struct{}
-func (i invalidSourceStorage) LastSrc(ctx context.Context, userID string, problemID string, languageID int32) (string, error) {
+func (i invalidSourceStorage) LastSrc(ctx context.Context, userID string, quizID string, problemID string, languageID int32) (string, error) {
 	return "", errors.New("error")
 }
 
 func TestGetSourceInputBadLanguageID(t *testing.T) {
 	languageID := "60"
 	formValues := map[string][]string{
+		"quizID":     {uuid.NewString()},
 		"problemID":  {"invalid"},
 		"languageID": {languageID},
 	}
@@ -42,6 +43,7 @@ func TestGetSourceInputBadLanguageID(t *testing.T) {
 func TestGetSourceInputBadProblemID(t *testing.T) {
 	problemID := uuid.NewString()
 	formValues := map[string][]string{
+		"quizID":     {uuid.NewString()},
 		"problemID":  {problemID},
 		"languageID": {"invalid"},
 	}
@@ -52,10 +54,24 @@ func TestGetSourceInputBadProblemID(t *testing.T) {
 	}
 }
 
+func TestGetSourceInputBadQuizID(t *testing.T) {
+	formValues := map[string][]string{
+		"quizID":     {"invalid"},
+		"problemID":  {uuid.NewString()},
+		"languageID": {"60"},
+	}
+	req := formRequest("GET", "/", formValues)
+	_, err := quizes.GetSourceInput(req)
+	if err == nil {
+		t.Error("expected error")
+	}
+}
+
 func TestGetSourceInput(t *testing.T) {
-	problemID := uuid.NewString()
+	quizID, problemID := uuid.NewString(), uuid.NewString()
 	languageID := "60"
 	formValues := map[string][]string{
+		"quizID":     {quizID},
 		"problemID":  {problemID},
 		"languageID": {languageID},
 	}
@@ -64,8 +80,8 @@ func TestGetSourceInput(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	if input.ProblemID != problemID {
-		t.Error("invalid problem ID")
+	if input.QuizID != quizID || input.ProblemID != problemID {
+		t.Error("invalid quiz or problem ID")
 	}
 	intLanguageID := int(input.LanguageID)
 	strLanguageID := strconv.Itoa(intLanguageID)
@@ -5,6 +5,8 @@ import (
 	"html/template"
 	"io"
 	"path/filepath"
+
+	"github.com/kw3a/spotted-server/internal/server/markdown"
 )
 
 type TemplatesRepo interface {
@@ -24,8 +26,9 @@ func viewsPath() (string, error) {
 }
 
 var templateFuncs = template.FuncMap{
-	"add": func(a, b int) int { return a + b },
-	"inc": func(i int) int { return i + 1 },
+	"add":      func(a, b int) int { return a + b },
+	"inc":      func(i int) int { return i + 1 },
+	"markdown": markdown.Render,
 	"json": func(v interface{}) template.JS {
 		a, _ := json.Marshal(v)
 		// #nosec G203
@@ -0,0 +1,69 @@
+package shared
+
+import (
+	"errors"
+	"time"
+)
+
+type Invitation struct {
+	ID     string
+	Email  string
+	Code   string
+	UsedAt time.Time
+}
+
+func (i Invitation) Used() bool {
+	return !i.UsedAt.IsZero()
+}
+
+var (
+	ErrQuizNotOpen        = errors.New("la prueba aún no está disponible")
+	ErrQuizClosed         = errors.New("la prueba ya no acepta participantes")
+	ErrQuizWindow         = errors.New("la apertura debe ser anterior al cierre")
+	ErrInvitationRequired = errors.New("esta prueba requiere un código de invitación")
+	ErrInvitationInvalid  = errors.New("el código de invitación no es válido")
+	ErrInvitationEmail    = errors.New("la invitación fue enviada a otro correo")
+	ErrInvitationUsed     = errors.New("la invitación ya fue utilizada")
+	ErrInvitationNoEmails = errors.New("indica al menos un correo")
+	ErrInvitationBadEmail = errors.New("uno de los correos no es válido")
+	ErrInvitationTooMany  = errors.New("puedes invitar hasta 200 correos a la vez")
+)
+
+// Access violations are shown to the user, any other error is internal
+func IsAccessError(err error) bool {
+	return IsAny(err,
+		ErrQuizNotOpen,
+		ErrQuizClosed,
+		ErrQuizWindow,
+		ErrOfferNotPublished,
+		ErrInvitationRequired,
+		ErrInvitationInvalid,
+		ErrInvitationEmail,
+		ErrInvitationUsed,
+		ErrInvitationNoEmails,
+		ErrInvitationBadEmail,
+		ErrInvitationTooMany,
+	)
+}
+
+func (q Quiz) Scheduled() bool {
+	return !q.OpensAt.IsZero() || !q.ClosesAt.IsZero()
+}
+
+// Available reports whether new participations can start at now, a zero
+// bound leaves that side of the window open
+func (q Quiz) Available(now time.Time) error {
+	switch q.OfferStatus {
+	case OfferDraft:
+		return ErrOfferNotPublished
+	case OfferClosed, OfferArchived:
+		return ErrQuizClosed
+	}
+	if !q.OpensAt.IsZero() && now.Before(q.OpensAt) {
+		return ErrQuizNotOpen
+	}
+	if !q.ClosesAt.IsZero() && !now.Before(q.ClosesAt) {
+		return ErrQuizClosed
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package shared
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuizAvailable(t *testing.T) {
+	now := time.Now()
+	cases := []struct {
+		quiz     Quiz
+		expected error
+	}{
+		{Quiz{}, nil},
+		{Quiz{OpensAt: now.Add(-time.Hour), ClosesAt: now.Add(time.Hour)}, nil},
+		{Quiz{OpensAt: now.Add(time.Minute)}, ErrQuizNotOpen},
+		{Quiz{ClosesAt: now.Add(-time.Minute)}, ErrQuizClosed},
+		{Quiz{ClosesAt: now}, ErrQuizClosed},
+		{Quiz{OpensAt: now}, nil},
+		{Quiz{OfferStatus: OfferPublished}, nil},
+		{Quiz{OfferStatus: OfferDraft}, ErrOfferNotPublished},
+		{Quiz{OfferStatus: OfferClosed}, ErrQuizClosed},
+		{Quiz{OfferStatus: OfferArchived, OpensAt: now.Add(time.Minute)}, ErrQuizClosed},
+	}
+	for _, c := range cases {
+		if err := c.quiz.Available(now); err != c.expected {
+			t.Errorf("opens %v closes %v: expected %v, got %v", c.quiz.OpensAt, c.quiz.ClosesAt, c.expected, err)
+		}
+	}
+}
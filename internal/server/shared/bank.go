@@ -0,0 +1,30 @@
+package shared
+
+import (
+	"errors"
+	"time"
+)
+
+// BankProblem is a library entry summarized by its latest version
+type BankProblem struct {
+	ID         string
+	ProblemID  string
+	Version    int32
+	Title      string
+	TimeLimit  int32
+	Difficulty int32
+	Tag        string
+	UpdatedAt  time.Time
+	Quizzes    int32
+}
+
+// ProblemVersion is an immutable snapshot of a library problem, quizzes
+// and submissions reference versions so later edits never change results
+type ProblemVersion struct {
+	ID        string
+	Version   int32
+	CreatedAt time.Time
+	Quizzes   int32
+}
+
+var ErrBankProblemNotFound = errors.New("uno de los problemas del banco no existe o no pertenece a la empresa")
@@ -0,0 +1,112 @@
+package shared
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Why the identity of an applicant was revealed
+const (
+	RevealReasonStage    = "stage"
+	RevealReasonDisabled = "disabled"
+)
+
+// BlindReview hides who the applicants of an offer are, recruiters see a
+// pseudonym until they move the applicant to the reveal stage
+type BlindReview struct {
+	// Key signs the pseudonyms, it is the server secret set by the storage
+	Key         []byte
+	OfferID     string
+	Enabled     bool
+	RevealStage string
+	Stages      []Stage
+	Alert       Alert
+}
+
+// IdentityReveal is the log entry of an applicant whose identity was
+// shown, it is kept even if blind review is enabled again
+type IdentityReveal struct {
+	Stage         string
+	Reason        string
+	RecruiterName string
+	CreatedAt     time.Time
+}
+
+// Pseudonym is stable for the same applicant of an offer and tells nothing
+// about them, without the key it can't be computed from the IDs
+func Pseudonym(key []byte, offerID, userID string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(offerID + ":" + userID))
+	return "Postulante " + strings.ToUpper(hex.EncodeToString(mac.Sum(nil)[:6]))
+}
+
+// Reveals is true when moving an applicant to stage shows their identity
+func (b BlindReview) Reveals(stage string) bool {
+	return b.Enabled && b.RevealStage != "" && stage == b.RevealStage
+}
+
+// pseudonyms hands out the pseudonyms of one offer, they are cut from the
+// hash so two applicants can share one and the later gets a number
+type pseudonyms struct {
+	review BlindReview
+	owners map[string]string
+}
+
+func (b BlindReview) pseudonyms() pseudonyms {
+	return pseudonyms{review: b, owners: make(map[string]string)}
+}
+
+// anonymous keeps only the pseudonym of the user, without an ID the
+// profile can't be linked either
+func (p pseudonyms) anonymous(user User) User {
+	name := Pseudonym(p.review.Key, p.review.OfferID, user.ID)
+	unique := name
+	for n := 2; p.owners[unique] != "" && p.owners[unique] != user.ID; n++ {
+		unique = fmt.Sprintf("%s-%d", name, n)
+	}
+	p.owners[unique] = user.ID
+	return User{Name: unique}
+}
+
+// Hide replaces the identity of the applicants not revealed yet, what they
+// submitted stays untouched
+func (b BlindReview) Hide(applications []Application) {
+	if !b.Enabled {
+		return
+	}
+	names := b.pseudonyms()
+	for i := range applications {
+		if applications[i].Pipeline.Revealed() {
+			continue
+		}
+		applications[i].Applicant = names.anonymous(applications[i].Applicant)
+		applications[i].Anonymous = true
+	}
+}
+
+// HidePairs hides both sides of the similarity report. It needs the
+// applications before Hide, submissions of prior offers are always hidden.
+func (b BlindReview) HidePairs(pairs []SimilarityPair, applications []Application) {
+	if !b.Enabled {
+		return
+	}
+	revealed := make(map[string]bool)
+	for _, application := range applications {
+		if application.Pipeline.Revealed() {
+			revealed[application.Applicant.ID] = true
+		}
+	}
+	names := b.pseudonyms()
+	for i := range pairs {
+		if !revealed[pairs[i].First.Applicant.ID] {
+			pairs[i].First.Applicant = names.anonymous(pairs[i].First.Applicant)
+		}
+		if !revealed[pairs[i].Second.Applicant.ID] {
+			pairs[i].Second.Applicant = names.anonymous(pairs[i].Second.Applicant)
+		}
+	}
+}
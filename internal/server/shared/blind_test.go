@@ -0,0 +1,107 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPseudonym(t *testing.T) {
+	key := []byte("secret")
+	first := Pseudonym(key, "offer-1", "user-1")
+	if first != Pseudonym(key, "offer-1", "user-1") {
+		t.Error("expected a stable pseudonym")
+	}
+	if first == Pseudonym(key, "offer-2", "user-1") {
+		t.Error("expected a different pseudonym in another offer")
+	}
+	if first == Pseudonym([]byte("other"), "offer-1", "user-1") {
+		t.Error("expected the pseudonym to depend on the key")
+	}
+	if !strings.HasPrefix(first, "Postulante ") || strings.Contains(first, "user-1") {
+		t.Errorf("unexpected pseudonym %q", first)
+	}
+}
+
+func TestPseudonymsUnique(t *testing.T) {
+	review := BlindReview{Key: []byte("secret"), OfferID: "offer"}
+	names := review.pseudonyms()
+	name := Pseudonym(review.Key, "offer", "user-1")
+	// another applicant of the offer already has the pseudonym
+	names.owners[name] = "user-2"
+	first := names.anonymous(User{ID: "user-1"})
+	if first.Name != name+"-2" {
+		t.Errorf("expected %q, got %q", name+"-2", first.Name)
+	}
+	if again := names.anonymous(User{ID: "user-1"}); again != first {
+		t.Errorf("expected the same pseudonym, got %q", again.Name)
+	}
+}
+
+func TestBlindReviewReveals(t *testing.T) {
+	review := BlindReview{Enabled: true, RevealStage: StageInterview}
+	if !review.Reveals(StageInterview) {
+		t.Error("expected the reveal stage to reveal")
+	}
+	// stages after the reveal one don't reveal, rejected is the last one
+	if review.Reveals(StageRejected) {
+		t.Error("expected other stages to keep the applicant hidden")
+	}
+	review.Enabled = false
+	if review.Reveals(StageInterview) {
+		t.Error("expected nothing to be revealed when disabled")
+	}
+}
+
+func blindApplications() []Application {
+	return []Application{
+		{
+			Applicant:     User{ID: "hidden", Name: "Ana", Email: "ana@example.com", ImageURL: "/ana.png"},
+			Participation: Participation{ID: "p1"},
+		},
+		{
+			Applicant:     User{ID: "shown", Name: "Beto"},
+			Participation: Participation{ID: "p2"},
+			Pipeline:      Pipeline{Reveal: IdentityReveal{Stage: StageInterview, CreatedAt: time.Now()}},
+		},
+	}
+}
+
+func TestBlindReviewHide(t *testing.T) {
+	applications := blindApplications()
+	review := BlindReview{Key: []byte("secret"), OfferID: "offer", Enabled: true, RevealStage: StageInterview}
+	review.Hide(applications)
+	hidden := applications[0]
+	if !hidden.Anonymous || hidden.Applicant != (User{Name: Pseudonym(review.Key, "offer", "hidden")}) {
+		t.Errorf("expected only the pseudonym, got %+v", hidden.Applicant)
+	}
+	if applications[1].Anonymous || applications[1].Applicant.Name != "Beto" {
+		t.Error("expected the revealed applicant to be shown")
+	}
+
+	applications = blindApplications()
+	BlindReview{}.Hide(applications)
+	if applications[0].Anonymous || applications[0].Applicant.Name != "Ana" {
+		t.Error("expected applicants to be shown without blind review")
+	}
+}
+
+func TestBlindReviewHidePairs(t *testing.T) {
+	applications := blindApplications()
+	pairs := []SimilarityPair{{
+		First:  SimilarityEntry{Applicant: applications[0].Applicant},
+		Second: SimilarityEntry{Applicant: applications[1].Applicant},
+	}, {
+		First:  SimilarityEntry{Applicant: applications[1].Applicant},
+		Second: SimilarityEntry{Applicant: User{ID: "prior", Name: "Carla"}, OfferTitle: "Otra"},
+		Prior:  true,
+	}}
+	review := BlindReview{Key: []byte("secret"), OfferID: "offer", Enabled: true, RevealStage: StageInterview}
+	review.HidePairs(pairs, applications)
+	if pairs[0].First.Applicant.Name != Pseudonym(review.Key, "offer", "hidden") || pairs[0].Second.Applicant.Name != "Beto" {
+		t.Errorf("unexpected first pair %+v", pairs[0])
+	}
+	if pairs[1].Second.Applicant.Name != Pseudonym(review.Key, "offer", "prior") {
+		t.Errorf("expected prior submissions to be hidden, got %+v", pairs[1].Second.Applicant)
+	}
+}
@@ -0,0 +1,42 @@
+package shared
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrAlreadyWithdrawn = errors.New("ya retiraste esta postulación")
+
+// CandidateApplication is a participation as its candidate sees it, the
+// stage is only known when the company shares it with candidates
+type CandidateApplication struct {
+	Offer         Offer
+	QuizID        string
+	Participation Participation
+	StageLabel    string
+	WithdrawnAt   time.Time
+	// Summary has the best submission of each problem and the responses,
+	// without the answer key nor the recruiter feedback
+	Summary []Summary
+	Alert   Alert
+}
+
+func (a CandidateApplication) Withdrawn() bool {
+	return !a.WithdrawnAt.IsZero()
+}
+
+func (a CandidateApplication) Running() bool {
+	return !a.Withdrawn() && !a.Participation.Finished(time.Now())
+}
+
+func (a CandidateApplication) StatusLabel() string {
+	switch {
+	case a.Withdrawn():
+		return "Retirada"
+	case a.Running():
+		return "En curso"
+	case a.StageLabel != "":
+		return a.StageLabel
+	}
+	return "Finalizada"
+}
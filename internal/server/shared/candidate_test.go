@@ -0,0 +1,39 @@
+package shared
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCandidateApplicationStatus(t *testing.T) {
+	now := time.Now()
+	running := Participation{ExpiresAt: now.Add(time.Hour)}
+	finished := Participation{ExpiresAt: now.Add(-time.Hour), EndReason: EndReasonManual}
+	tests := []struct {
+		name        string
+		application CandidateApplication
+		want        string
+	}{
+		{"running", CandidateApplication{Participation: running, StageLabel: "Nuevo"}, "En curso"},
+		{"finished", CandidateApplication{Participation: finished}, "Finalizada"},
+		{"shared stage", CandidateApplication{Participation: finished, StageLabel: "Entrevista"}, "Entrevista"},
+		{"withdrawn", CandidateApplication{Participation: running, StageLabel: "Entrevista", WithdrawnAt: now}, "Retirada"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.application.StatusLabel(); got != tt.want {
+				t.Errorf("StatusLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCandidateApplicationRunning(t *testing.T) {
+	application := CandidateApplication{
+		Participation: Participation{ExpiresAt: time.Now().Add(time.Hour)},
+		WithdrawnAt:   time.Now(),
+	}
+	if application.Running() {
+		t.Error("a withdrawn application is not running")
+	}
+}
@@ -2,6 +2,7 @@ package shared
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -43,3 +44,14 @@ func EncodeAndLog[T any](w http.ResponseWriter, status int, v T) {
 		log.Println(err)
 	}
 }
+
+// IsAny reports whether err matches any of the targets, the Is*Error
+// helpers list the errors each form shows to the user with it
+func IsAny(err error, targets ...error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsAny(t *testing.T) {
+	wrapped := fmt.Errorf("edit: %w", ErrNote)
+	if !IsAny(wrapped, ErrRating, ErrNote) {
+		t.Error("expected a wrapped target to match")
+	}
+	if IsAny(errors.New("db down"), ErrRating, ErrNote) || IsAny(nil, ErrRating) || IsAny(ErrNote) {
+		t.Error("unexpected match")
+	}
+}
@@ -0,0 +1,30 @@
+package shared
+
+import (
+	"errors"
+	"sort"
+)
+
+const (
+	ExportCSV  = "csv"
+	ExportXLSX = "xlsx"
+)
+
+var ErrExportFormat = errors.New("formato de exportación no soportado")
+
+// RankApplications orders the applicants from the best normalized score,
+// ties go to the best rated and then to the first one to apply
+func RankApplications(applications []Application) []Application {
+	ranked := append([]Application{}, applications...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if a.NormalizedScore() != b.NormalizedScore() {
+			return a.NormalizedScore() > b.NormalizedScore()
+		}
+		if a.Pipeline.Rating != b.Pipeline.Rating {
+			return a.Pipeline.Rating > b.Pipeline.Rating
+		}
+		return a.Participation.CreatedAt.Before(b.Participation.CreatedAt)
+	})
+	return ranked
+}
@@ -0,0 +1,39 @@
+package shared
+
+import (
+	"testing"
+	"time"
+)
+
+func scoredApplication(name string, accepted int, rating int32, createdAt time.Time) Application {
+	return Application{
+		Applicant:     User{Name: name},
+		Participation: Participation{CreatedAt: createdAt},
+		Problems:      []PoolProblem{{ID: "p1", Difficulty: 1}},
+		Summary: []Summary{{
+			Submission: Submission{ProblemID: "p1"},
+			Score:      Score{AcceptedTestCases: accepted, TotalTestCases: 4},
+		}},
+		Pipeline: Pipeline{Rating: rating},
+	}
+}
+
+func TestRankApplications(t *testing.T) {
+	now := time.Now()
+	applications := []Application{
+		scoredApplication("late", 2, 0, now),
+		scoredApplication("best", 4, 0, now),
+		scoredApplication("rated", 2, 3, now),
+		scoredApplication("early", 2, 0, now.Add(-time.Hour)),
+	}
+	ranked := RankApplications(applications)
+	want := []string{"best", "rated", "early", "late"}
+	for i, name := range want {
+		if ranked[i].Applicant.Name != name {
+			t.Errorf("position %d = %q, want %q", i+1, ranked[i].Applicant.Name, name)
+		}
+	}
+	if applications[0].Applicant.Name != "late" {
+		t.Error("RankApplications modified its input")
+	}
+}
@@ -54,3 +54,12 @@ func TestValidateLanguageID(t *testing.T) {
 		t.Errorf("expected %d, got %d", 60, languageIDInt32)
 	}
 }
+
+func TestValidateProctoringKind(t *testing.T) {
+	if err := ValidateProctoringKind(ProctoringTabSwitch); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+	if err := ValidateProctoringKind("scroll"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
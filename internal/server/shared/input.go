@@ -60,6 +60,13 @@ func ValidateLanguageID(languageID string) (int32, error) {
 	return languageIDInt32, nil
 }
 
+func ValidateProctoringKind(kind string) error {
+	if _, ok := ProctoringLabels[kind]; !ok {
+		return fmt.Errorf("unknown proctoring event: %s", kind)
+	}
+	return nil
+}
+
 func DateSpanishFormat(t sql.NullTime) string {
 	if !t.Valid {
 		return "presente"
@@ -0,0 +1,144 @@
+package shared
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Roles of a company member. Every member sees the offers, the applicants
+// and their recordings, recruiters also publish offers, review applicants
+// and use the library, admins also manage the team, the pipeline and the
+// webhooks, and the owner is the only one who transfers the company.
+const (
+	RoleOwner     = "owner"
+	RoleAdmin     = "admin"
+	RoleRecruiter = "recruiter"
+	RoleViewer    = "viewer"
+)
+
+// MemberRoles can be given by an invitation or a role change, the owner
+// only changes with a transfer
+var MemberRoles = []string{RoleAdmin, RoleRecruiter, RoleViewer}
+
+var MemberRoleLabels = map[string]string{
+	RoleOwner:     "Dueño",
+	RoleAdmin:     "Administrador",
+	RoleRecruiter: "Reclutador",
+	RoleViewer:    "Observador",
+}
+
+const maxInvitee = 255
+
+var (
+	ErrMemberRole    = errors.New("rol desconocido")
+	ErrInvitee       = errors.New("escribe el nick o el correo de la persona")
+	ErrAlreadyMember = errors.New("ya es miembro de la empresa")
+	ErrNotMember     = errors.New("no es miembro de la empresa")
+	ErrNotManager    = errors.New("solo el dueño y los administradores gestionan el equipo")
+	ErrNotEditor     = errors.New("tu rol en la empresa es de solo lectura")
+	ErrOwnerMember   = errors.New("el dueño no cambia de rol ni sale del equipo, primero transfiere la empresa")
+	ErrNotOwner      = errors.New("solo el dueño puede transferir la empresa")
+)
+
+// Team mistakes are shown next to the team, any other error is internal
+func IsMemberError(err error) bool {
+	return IsAny(err,
+		ErrMemberRole,
+		ErrInvitee,
+		ErrAlreadyMember,
+		ErrNotMember,
+		ErrNotManager,
+		ErrOwnerMember,
+		ErrNotOwner,
+	)
+}
+
+// CanEdit is true for the members that publish offers and review
+// applicants
+func CanEdit(role string) bool {
+	return role == RoleOwner || role == RoleAdmin || role == RoleRecruiter
+}
+
+// CanManage is true for the members that manage the team and the company
+// settings
+func CanManage(role string) bool {
+	return role == RoleOwner || role == RoleAdmin
+}
+
+func ValidateMemberRole(role string) error {
+	if !slices.Contains(MemberRoles, role) {
+		return ErrMemberRole
+	}
+	return nil
+}
+
+// ValidateInvitee accepts a nick or an email, the invitation is answered
+// by the user that has it
+func ValidateInvitee(invitee string) (string, error) {
+	invitee = strings.TrimSpace(invitee)
+	if invitee == "" || len(invitee) > maxInvitee || strings.ContainsAny(invitee, " \t\r\n") {
+		return "", ErrInvitee
+	}
+	return invitee, nil
+}
+
+type Member struct {
+	UserID    string
+	Nick      string
+	Name      string
+	Email     string
+	ImageURL  string
+	Role      string
+	CreatedAt time.Time
+}
+
+func (m Member) RoleLabel() string {
+	return MemberRoleLabels[m.Role]
+}
+
+// CompanyInvitation is shown to the team with the invitee and to the
+// invitee with the company
+type CompanyInvitation struct {
+	ID              string
+	CompanyID       string
+	CompanyName     string
+	CompanyImageURL string
+	Invitee         string
+	Role            string
+	InvitedBy       string
+	CreatedAt       time.Time
+	Declined        bool
+}
+
+func (i CompanyInvitation) RoleLabel() string {
+	return MemberRoleLabels[i.Role]
+}
+
+// Membership is a company of the user with the role they have in it
+type Membership struct {
+	CompanyID       string
+	CompanyName     string
+	CompanyImageURL string
+	Role            string
+}
+
+func (m Membership) RoleLabel() string {
+	return MemberRoleLabels[m.Role]
+}
+
+// CheckMemberChange tells if a member with actorRole can change the role
+// of a member with targetRole or remove them
+func CheckMemberChange(actorRole, targetRole string) error {
+	if !CanManage(actorRole) {
+		return ErrNotManager
+	}
+	if targetRole == "" {
+		return ErrNotMember
+	}
+	if targetRole == RoleOwner {
+		return ErrOwnerMember
+	}
+	return nil
+}
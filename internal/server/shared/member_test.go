@@ -0,0 +1,82 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMemberPermissions(t *testing.T) {
+	cases := []struct {
+		role   string
+		edit   bool
+		manage bool
+	}{
+		{RoleOwner, true, true},
+		{RoleAdmin, true, true},
+		{RoleRecruiter, true, false},
+		{RoleViewer, false, false},
+		{"", false, false},
+	}
+	for _, c := range cases {
+		if CanEdit(c.role) != c.edit {
+			t.Errorf("%q: expected CanEdit %v", c.role, c.edit)
+		}
+		if CanManage(c.role) != c.manage {
+			t.Errorf("%q: expected CanManage %v", c.role, c.manage)
+		}
+	}
+}
+
+func TestValidateMemberRole(t *testing.T) {
+	for _, role := range MemberRoles {
+		if err := ValidateMemberRole(role); err != nil {
+			t.Errorf("%q: expected nil, got %v", role, err)
+		}
+	}
+	for _, role := range []string{RoleOwner, "", "guest"} {
+		if err := ValidateMemberRole(role); err != ErrMemberRole {
+			t.Errorf("%q: expected %v, got %v", role, ErrMemberRole, err)
+		}
+	}
+}
+
+func TestValidateInvitee(t *testing.T) {
+	invitee, err := ValidateInvitee("  ana@mail.com ")
+	if err != nil || invitee != "ana@mail.com" {
+		t.Errorf("expected ana@mail.com, got %q %v", invitee, err)
+	}
+	for _, raw := range []string{"", "   ", "ana maria", strings.Repeat("a", 256)} {
+		if _, err := ValidateInvitee(raw); err != ErrInvitee {
+			t.Errorf("%q: expected %v, got %v", raw, ErrInvitee, err)
+		}
+	}
+}
+
+func TestCheckMemberChange(t *testing.T) {
+	cases := []struct {
+		actor    string
+		target   string
+		expected error
+	}{
+		{RoleOwner, RoleAdmin, nil},
+		{RoleAdmin, RoleViewer, nil},
+		{RoleAdmin, RoleOwner, ErrOwnerMember},
+		{RoleRecruiter, RoleViewer, ErrNotManager},
+		{"", RoleViewer, ErrNotManager},
+		{RoleOwner, "", ErrNotMember},
+	}
+	for _, c := range cases {
+		if err := CheckMemberChange(c.actor, c.target); err != c.expected {
+			t.Errorf("%q on %q: expected %v, got %v", c.actor, c.target, c.expected, err)
+		}
+	}
+}
+
+func TestOfferReadOnly(t *testing.T) {
+	if !(Offer{MemberRole: RoleViewer}).ReadOnly() {
+		t.Error("expected a viewer to be read only")
+	}
+	if (Offer{MemberRole: RoleRecruiter}).ReadOnly() {
+		t.Error("expected a recruiter to edit")
+	}
+}
@@ -2,6 +2,8 @@ package shared
 
 import (
 	"time"
+
+	"github.com/kw3a/spotted-server/internal/server/textsearch"
 )
 
 type User struct {
@@ -57,7 +59,6 @@ type OfferQueryParams struct {
 	Page      int32
 	UserID    string
 	CompanyID string
-	Query     string
 }
 type Language struct {
 	ID          int32
@@ -71,29 +72,85 @@ type Offer struct {
 	About           string
 	Requirements    string
 	Benefits        string
-	Status          int32
+	Status          string
 	CompanyName     string
 	CompanyID       string
 	CompanyImageURL string
 	MinWage         int32
 	MaxWage         int32
 	RelativeTime    string
+	Location        string
+	Modality        string
+	Seniority       string
+	Contract        string
+	Currency        string
+	WagePeriod      string
+	// Tags is the tech stack, it is only loaded by listings and pages that
+	// show it
+	Tags []Language
+	// Snippet is the part of the description that matched a search
+	Snippet []textsearch.Fragment
+	// MemberRole is the role of the user in the company of the offer, it is
+	// only loaded for its members
+	MemberRole string
 }
 
 type Quiz struct {
-	ID        string
-	Duration  int32
-	Languages []int32
+	ID         string
+	Duration   int32
+	Languages  []int32
+	OpensAt    time.Time
+	ClosesAt   time.Time
+	InviteOnly bool
+	// Pools draw the problems of every participation, Shuffle changes the
+	// order of the problems per participation
+	Pools   []Pool
+	Shuffle bool
+	// OfferStatus is only loaded where participations start, an empty status
+	// skips the lifecycle check
+	OfferStatus string
 }
 
 type Problem struct {
-	ID          string
-	Title       string
-	Description string
-	MemoryLimit int32
-	TimeLimit   int32
-	TestCases   []TestCase
-	Examples    []Example
+	ID            string
+	Title         string
+	Description   string
+	MemoryLimit   int32
+	TimeLimit     int32
+	TestCases     []TestCase
+	Examples      []Example
+	BankProblemID string
+	Version       int32
+	Checker       []CheckerFile
+	// Signature is the function declaration of the problem, it is empty
+	// when the solution reads stdin and writes stdout
+	Signature string
+	// Database is set for SQL problems, the candidate writes a query and
+	// every test case is a dataset with its expected result set
+	Database *Database
+	// Question is set for problems answered without code
+	Question *Question
+	// Difficulty and Tag place the problem in the pools of a quiz
+	Difficulty int32
+	Tag        string
+	// Generator adds test cases when the problem is published, it is not
+	// stored
+	Generator *Generator
+}
+
+// Database is the schema and the seed data shared by the test cases of a
+// SQL problem, Ordered results are compared row by row in order
+type Database struct {
+	Schema  string
+	Seed    string
+	Ordered bool
+}
+
+// CheckerFile is a source file of a custom output validator imported with a
+// problem package, it is kept so the package can be exported again
+type CheckerFile struct {
+	Path string
+	Src  string
 }
 
 type Example struct {
@@ -122,14 +179,42 @@ type Participation struct {
 	ID           string
 	CreatedAt    time.Time
 	ExpiresAt    time.Time
+	FinishedAt   time.Time
+	EndReason    string
+	PausedAt     time.Time
+	WithdrawnAt  time.Time
 	RelativeTime string
 }
 
+const (
+	EndReasonManual    = "manual"
+	EndReasonTimeout   = "timeout"
+	EndReasonAbandoned = "abandoned"
+	EndReasonWithdrawn = "withdrawn"
+)
+
+var EndReasonLabels = map[string]string{
+	EndReasonManual:    "Finalizada por el aplicante",
+	EndReasonTimeout:   "Tiempo agotado",
+	EndReasonAbandoned: "Abandonada",
+	EndReasonWithdrawn: "Retirada por el aplicante",
+}
+
+func (p Participation) EndReasonLabel() string {
+	return EndReasonLabels[p.EndReason]
+}
+
 type TestCase struct {
 	ID        string
 	Input     string
 	Output    string
 	ProblemID string
+	// InputHash and OutputHash name the blobs that hold data larger than
+	// InlineTestDataSize, Input and Output then only keep a preview
+	InputHash  string
+	OutputHash string
+	InputSize  int32
+	OutputSize int32
 }
 
 type TestCaseResult struct {
@@ -151,6 +236,20 @@ type Application struct {
 	Applicant     User
 	Participation Participation
 	Summary       []Summary
+	Proctoring    []ProctoringCount
+	Adjustments   []Adjustment
+	// Problems are the ones drawn for the participation
+	Problems []PoolProblem
+	Pipeline Pipeline
+	// Anonymous is set when the applicant is shown by pseudonym
+	Anonymous bool
+}
+
+func (a Application) Controls() ParticipationControls {
+	return ParticipationControls{
+		Participation: a.Participation,
+		Adjustments:   a.Adjustments,
+	}
 }
 
 type Summary struct {
@@ -158,6 +257,32 @@ type Summary struct {
 	Score      Score
 	Submission Submission
 	Results    []TestCaseResult
+	// Question and Response replace the submission for questions
+	Question *Question
+	Response QuestionResponse
+}
+
+type SimilarityEntry struct {
+	Applicant    User
+	ProblemTitle string
+	OfferTitle   string
+	Submission   Submission
+}
+
+type SimilarityPair struct {
+	ProblemTitle   string
+	Language       string
+	Score          int
+	Prior          bool
+	First          SimilarityEntry
+	Second         SimilarityEntry
+	FirstSegments  []Segment
+	SecondSegments []Segment
+}
+
+type Segment struct {
+	Text  string
+	Match bool
 }
 
 type StrokeWindow struct {
@@ -174,3 +299,42 @@ type StrokeWindow struct {
 	UuMean       int32
 	UuStdDev     int32
 }
+
+const (
+	ProctoringTabSwitch      = "tab_switch"
+	ProctoringBlur           = "blur"
+	ProctoringFullscreenExit = "fullscreen_exit"
+	ProctoringPaste          = "paste"
+)
+
+var ProctoringKinds = []string{
+	ProctoringTabSwitch,
+	ProctoringBlur,
+	ProctoringFullscreenExit,
+	ProctoringPaste,
+}
+
+var ProctoringLabels = map[string]string{
+	ProctoringTabSwitch:      "Cambios de pestaña",
+	ProctoringBlur:           "Pérdidas de foco",
+	ProctoringFullscreenExit: "Salidas de pantalla completa",
+	ProctoringPaste:          "Pegados extensos",
+}
+
+type ProctoringEvent struct {
+	ID     string
+	Kind   string
+	Detail string
+}
+
+type ProctoringRule struct {
+	Kind      string
+	Label     string
+	Threshold int32
+}
+
+type ProctoringCount struct {
+	Kind  string
+	Label string
+	Count int32
+}
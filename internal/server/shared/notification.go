@@ -0,0 +1,137 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// Categories a user gets notified about, in the order they are shown
+const (
+	NotifyResults   = "results"
+	NotifyApplicant = "applicant"
+	NotifyStatus    = "status"
+	NotifyArchived  = "archived"
+	NotifyTeam      = "team"
+)
+
+var NotificationCategories = []string{
+	NotifyResults,
+	NotifyApplicant,
+	NotifyStatus,
+	NotifyArchived,
+	NotifyTeam,
+}
+
+var NotificationCategoryLabels = map[string]string{
+	NotifyResults:   "Mis resultados están listos",
+	NotifyApplicant: "Un postulante nuevo en mis ofertas",
+	NotifyStatus:    "Mi postulación cambia de etapa",
+	NotifyArchived:  "Una oferta a la que postulé se archiva",
+	NotifyTeam:      "Me invitan al equipo de una empresa",
+}
+
+// How a notification reaches the email of the user, the digest sends the
+// pending ones together once a day
+const (
+	EmailOff     = "off"
+	EmailInstant = "instant"
+	EmailDigest  = "digest"
+)
+
+var EmailModes = []string{EmailOff, EmailInstant, EmailDigest}
+
+var EmailModeLabels = map[string]string{
+	EmailOff:     "Sin correo",
+	EmailInstant: "Correo al momento",
+	EmailDigest:  "Resumen diario",
+}
+
+var ErrNotificationPreference = errors.New("preferencia de notificación desconocida")
+
+type NotificationPreference struct {
+	Category string
+	InApp    bool
+	Email    string
+}
+
+func DefaultNotificationPreference(category string) NotificationPreference {
+	return NotificationPreference{Category: category, InApp: true, Email: EmailOff}
+}
+
+func (p NotificationPreference) Label() string {
+	return NotificationCategoryLabels[p.Category]
+}
+
+// Muted preferences don't write the notification at all
+func (p NotificationPreference) Muted() bool {
+	return !p.InApp && p.Email == EmailOff
+}
+
+// NotificationPreferences has every category, the ones the user never
+// changed with the default
+func NotificationPreferences(saved []NotificationPreference) []NotificationPreference {
+	preferences := []NotificationPreference{}
+	for _, category := range NotificationCategories {
+		preference := DefaultNotificationPreference(category)
+		for _, s := range saved {
+			if s.Category == category {
+				preference = s
+			}
+		}
+		preferences = append(preferences, preference)
+	}
+	return preferences
+}
+
+// Notification is written for one recipient, Email and Preference are
+// only filled when it is created so the center knows how to deliver it
+type Notification struct {
+	ID         string
+	UserID     string
+	Category   string
+	Title      string
+	Link       string
+	CreatedAt  time.Time
+	Read       bool
+	Email      string
+	Preference NotificationPreference
+}
+
+// NotificationEvent is raised by the handlers, the storage finds the
+// recipients from the ids of the subject. A participation can be given by
+// its id or by the applicant and the quiz.
+type NotificationEvent struct {
+	Category        string
+	OfferID         string
+	ParticipationID string
+	UserID          string
+	QuizID          string
+	Stage           string
+	InvitationID    string
+}
+
+// Notify only logs a failure, the request that caused the event goes on
+// without it
+func Notify(ctx context.Context, notifier Notifier, event NotificationEvent) {
+	if err := notifier.Notify(ctx, event); err != nil {
+		log.Println("notifications:", err)
+	}
+}
+
+type Email struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Digest is the daily email of a user, Until is the newest notification
+// it includes
+type Digest struct {
+	UserID        string
+	Name          string
+	Email         string
+	Notifications []Notification
+	Until         time.Time
+}
@@ -0,0 +1,39 @@
+package shared
+
+import "testing"
+
+func TestNotificationPreferences(t *testing.T) {
+	saved := []NotificationPreference{{Category: NotifyStatus, InApp: false, Email: EmailDigest}}
+	preferences := NotificationPreferences(saved)
+	if len(preferences) != len(NotificationCategories) {
+		t.Fatalf("expected %d preferences, got %d", len(NotificationCategories), len(preferences))
+	}
+	for i, preference := range preferences {
+		if preference.Category != NotificationCategories[i] {
+			t.Errorf("expected category %s, got %s", NotificationCategories[i], preference.Category)
+		}
+		if preference.Category == NotifyStatus {
+			if preference != saved[0] {
+				t.Errorf("expected the saved preference, got %v", preference)
+			}
+			continue
+		}
+		if preference != DefaultNotificationPreference(preference.Category) {
+			t.Errorf("expected the default preference, got %v", preference)
+		}
+	}
+}
+
+func TestNotificationPreferenceMuted(t *testing.T) {
+	cases := map[NotificationPreference]bool{
+		{InApp: true, Email: EmailOff}:      false,
+		{InApp: false, Email: EmailInstant}: false,
+		{InApp: false, Email: EmailDigest}:  false,
+		{InApp: false, Email: EmailOff}:     true,
+	}
+	for preference, expected := range cases {
+		if preference.Muted() != expected {
+			t.Errorf("%v: expected %v", preference, expected)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package shared
+
+import (
+	"errors"
+)
+
+// Offers start as drafts or published, only published offers are listed and
+// accept new participations. Closed offers can be published again, archived
+// offers are final
+const (
+	OfferDraft     = "draft"
+	OfferPublished = "published"
+	OfferClosed    = "closed"
+	OfferArchived  = "archived"
+)
+
+var OfferStatusLabels = map[string]string{
+	OfferDraft:     "Borrador",
+	OfferPublished: "Publicada",
+	OfferClosed:    "Cerrada",
+	OfferArchived:  "Archivada",
+}
+
+// OfferTransitions lists the states reachable from each state, in the order
+// they are offered to the owner
+var OfferTransitions = map[string][]string{
+	OfferDraft:     {OfferPublished, OfferArchived},
+	OfferPublished: {OfferClosed, OfferArchived},
+	OfferClosed:    {OfferPublished, OfferArchived},
+	OfferArchived:  {},
+}
+
+var (
+	ErrOfferTransition   = errors.New("la oferta no puede pasar a ese estado")
+	ErrOfferArchived     = errors.New("una oferta archivada no se puede editar")
+	ErrOfferNotPublished = errors.New("la oferta aún no está publicada")
+	ErrQuizLocked        = errors.New("la prueba ya tiene participantes, solo se puede editar el texto de la oferta")
+)
+
+func CanTransition(from, to string) bool {
+	for _, status := range OfferTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+func (o Offer) StatusLabel() string {
+	return OfferStatusLabels[o.Status]
+}
+
+func (o Offer) Transitions() []string {
+	return OfferTransitions[o.Status]
+}
+
+func (o Offer) Editable() bool {
+	return o.Status != OfferArchived
+}
+
+// ReadOnly is true for the members that only see the offer
+func (o Offer) ReadOnly() bool {
+	return !CanEdit(o.MemberRole)
+}
@@ -0,0 +1,42 @@
+package shared
+
+import "testing"
+
+func TestCanTransition(t *testing.T) {
+	cases := []struct {
+		from     string
+		to       string
+		expected bool
+	}{
+		{OfferDraft, OfferPublished, true},
+		{OfferDraft, OfferClosed, false},
+		{OfferDraft, OfferArchived, true},
+		{OfferPublished, OfferClosed, true},
+		{OfferPublished, OfferDraft, false},
+		{OfferPublished, OfferPublished, false},
+		{OfferClosed, OfferPublished, true},
+		{OfferClosed, OfferArchived, true},
+		{OfferArchived, OfferPublished, false},
+		{OfferArchived, OfferDraft, false},
+		{"", OfferPublished, false},
+		{OfferPublished, "deleted", false},
+	}
+	for _, c := range cases {
+		if got := CanTransition(c.from, c.to); got != c.expected {
+			t.Errorf("%s -> %s: expected %v, got %v", c.from, c.to, c.expected, got)
+		}
+	}
+}
+
+func TestOfferTransitionsHaveLabels(t *testing.T) {
+	for from, targets := range OfferTransitions {
+		if _, ok := OfferStatusLabels[from]; !ok {
+			t.Errorf("missing label for %s", from)
+		}
+		for _, to := range targets {
+			if _, ok := OfferTransitions[to]; !ok {
+				t.Errorf("%s -> %s: unknown target state", from, to)
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package shared
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	AdjustmentExtend = "extend"
+	AdjustmentPause  = "pause"
+	AdjustmentResume = "resume"
+	AdjustmentReopen = "reopen"
+)
+
+var AdjustmentLabels = map[string]string{
+	AdjustmentExtend: "Tiempo extra",
+	AdjustmentPause:  "Pausa",
+	AdjustmentResume: "Reanudación",
+	AdjustmentReopen: "Reapertura",
+}
+
+// Published on the deadline broker when a recruiter changes the timing of
+// a participation, end reasons are published when it finishes
+const DeadlineAdjusted = "adjusted"
+
+type Adjustment struct {
+	ID            string
+	Action        string
+	Minutes       int32
+	Reason        string
+	RecruiterName string
+	CreatedAt     time.Time
+}
+
+func (a Adjustment) Label() string {
+	return AdjustmentLabels[a.Action]
+}
+
+type ParticipationControls struct {
+	Participation Participation
+	Adjustments   []Adjustment
+	Alert         Alert
+}
+
+var (
+	ErrParticipationFinished  = errors.New("la participación ya terminó, puedes reabrirla")
+	ErrParticipationRunning   = errors.New("la participación sigue en curso")
+	ErrParticipationPaused    = errors.New("la participación ya está en pausa")
+	ErrParticipationNotPause  = errors.New("la participación no está en pausa")
+	ErrParticipationWithdrawn = errors.New("el aplicante retiró su postulación")
+	ErrAlreadyFinished        = errors.New("la participación ya había terminado")
+	ErrAdjustmentMinutes      = errors.New("indica la cantidad de minutos")
+	ErrAdjustmentAction       = errors.New("acción desconocida")
+)
+
+// Rule violations are shown to the recruiter, any other error is internal
+func IsAdjustmentError(err error) bool {
+	return IsAny(err,
+		ErrParticipationFinished,
+		ErrParticipationRunning,
+		ErrParticipationPaused,
+		ErrParticipationNotPause,
+		ErrParticipationWithdrawn,
+		ErrAdjustmentMinutes,
+		ErrAdjustmentAction,
+	)
+}
+
+func (p Participation) Paused() bool {
+	return !p.PausedAt.IsZero()
+}
+
+func (p Participation) Withdrawn() bool {
+	return !p.WithdrawnAt.IsZero()
+}
+
+func (p Participation) Finished(now time.Time) bool {
+	return p.EndReason != "" || (!p.Paused() && p.ExpiresAt.Before(now))
+}
+
+// Adjust returns the participation with the recruiter adjustment applied.
+// Resuming moves the deadline forward by the time spent paused. The
+// participation of an applicant who withdrew can't be changed anymore.
+func (p Participation) Adjust(a Adjustment, now time.Time) (Participation, error) {
+	if p.Withdrawn() {
+		return p, ErrParticipationWithdrawn
+	}
+	minutes := time.Duration(a.Minutes) * time.Minute
+	switch a.Action {
+	case AdjustmentExtend:
+		if p.Finished(now) {
+			return p, ErrParticipationFinished
+		}
+		if a.Minutes <= 0 {
+			return p, ErrAdjustmentMinutes
+		}
+		p.ExpiresAt = p.ExpiresAt.Add(minutes)
+	case AdjustmentPause:
+		if p.Finished(now) {
+			return p, ErrParticipationFinished
+		}
+		if p.Paused() {
+			return p, ErrParticipationPaused
+		}
+		p.PausedAt = now
+	case AdjustmentResume:
+		if p.EndReason != "" {
+			return p, ErrParticipationFinished
+		}
+		if !p.Paused() {
+			return p, ErrParticipationNotPause
+		}
+		p.ExpiresAt = p.ExpiresAt.Add(now.Sub(p.PausedAt))
+		p.PausedAt = time.Time{}
+	case AdjustmentReopen:
+		if !p.Finished(now) {
+			return p, ErrParticipationRunning
+		}
+		if a.Minutes <= 0 {
+			return p, ErrAdjustmentMinutes
+		}
+		p.ExpiresAt = now.Add(minutes)
+		p.FinishedAt = time.Time{}
+		p.EndReason = ""
+		p.PausedAt = time.Time{}
+	default:
+		return p, ErrAdjustmentAction
+	}
+	return p, nil
+}
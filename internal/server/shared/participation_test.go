@@ -0,0 +1,130 @@
+package shared
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAdjustExtend(t *testing.T) {
+	now := time.Now()
+	p := Participation{ExpiresAt: now.Add(10 * time.Minute)}
+	adjusted, err := p.Adjust(Adjustment{Action: AdjustmentExtend, Minutes: 15}, now)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !adjusted.ExpiresAt.Equal(now.Add(25 * time.Minute)) {
+		t.Errorf("unexpected deadline %v", adjusted.ExpiresAt)
+	}
+}
+
+func TestAdjustExtendFinished(t *testing.T) {
+	now := time.Now()
+	p := Participation{ExpiresAt: now.Add(-time.Minute), EndReason: EndReasonTimeout}
+	_, err := p.Adjust(Adjustment{Action: AdjustmentExtend, Minutes: 15}, now)
+	if !errors.Is(err, ErrParticipationFinished) {
+		t.Errorf("expected %v, got %v", ErrParticipationFinished, err)
+	}
+}
+
+func TestAdjustExtendWithoutMinutes(t *testing.T) {
+	now := time.Now()
+	p := Participation{ExpiresAt: now.Add(time.Minute)}
+	_, err := p.Adjust(Adjustment{Action: AdjustmentExtend}, now)
+	if !errors.Is(err, ErrAdjustmentMinutes) {
+		t.Errorf("expected %v, got %v", ErrAdjustmentMinutes, err)
+	}
+}
+
+func TestAdjustPauseResume(t *testing.T) {
+	start := time.Now()
+	p := Participation{ExpiresAt: start.Add(10 * time.Minute)}
+	paused, err := p.Adjust(Adjustment{Action: AdjustmentPause}, start)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if !paused.Paused() {
+		t.Fatal("expected paused participation")
+	}
+	if _, err := paused.Adjust(Adjustment{Action: AdjustmentPause}, start); !errors.Is(err, ErrParticipationPaused) {
+		t.Errorf("expected %v, got %v", ErrParticipationPaused, err)
+	}
+	// a paused participation is not over even after its deadline
+	if paused.Finished(start.Add(time.Hour)) {
+		t.Error("paused participation should not be finished")
+	}
+	resumed, err := paused.Adjust(Adjustment{Action: AdjustmentResume}, start.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if resumed.Paused() {
+		t.Error("expected resumed participation")
+	}
+	if !resumed.ExpiresAt.Equal(start.Add(40 * time.Minute)) {
+		t.Errorf("unexpected deadline %v", resumed.ExpiresAt)
+	}
+}
+
+func TestAdjustResumeNotPaused(t *testing.T) {
+	now := time.Now()
+	p := Participation{ExpiresAt: now.Add(time.Minute)}
+	_, err := p.Adjust(Adjustment{Action: AdjustmentResume}, now)
+	if !errors.Is(err, ErrParticipationNotPause) {
+		t.Errorf("expected %v, got %v", ErrParticipationNotPause, err)
+	}
+}
+
+func TestAdjustReopen(t *testing.T) {
+	now := time.Now()
+	p := Participation{
+		ExpiresAt:  now.Add(-time.Hour),
+		FinishedAt: now.Add(-time.Hour),
+		EndReason:  EndReasonTimeout,
+	}
+	reopened, err := p.Adjust(Adjustment{Action: AdjustmentReopen, Minutes: 20}, now)
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	if reopened.EndReason != "" || !reopened.FinishedAt.IsZero() {
+		t.Error("expected participation to be open")
+	}
+	if !reopened.ExpiresAt.Equal(now.Add(20 * time.Minute)) {
+		t.Errorf("unexpected deadline %v", reopened.ExpiresAt)
+	}
+}
+
+func TestAdjustReopenRunning(t *testing.T) {
+	now := time.Now()
+	p := Participation{ExpiresAt: now.Add(time.Hour)}
+	_, err := p.Adjust(Adjustment{Action: AdjustmentReopen, Minutes: 20}, now)
+	if !errors.Is(err, ErrParticipationRunning) {
+		t.Errorf("expected %v, got %v", ErrParticipationRunning, err)
+	}
+}
+
+func TestAdjustWithdrawn(t *testing.T) {
+	now := time.Now()
+	p := Participation{
+		ExpiresAt:   now.Add(-time.Hour),
+		FinishedAt:  now.Add(-time.Hour),
+		EndReason:   EndReasonWithdrawn,
+		PausedAt:    now.Add(-2 * time.Hour),
+		WithdrawnAt: now.Add(-time.Hour),
+	}
+	for _, action := range []string{AdjustmentReopen, AdjustmentExtend, AdjustmentResume} {
+		_, err := p.Adjust(Adjustment{Action: action, Minutes: 20}, now)
+		if !errors.Is(err, ErrParticipationWithdrawn) {
+			t.Errorf("%s: expected %v, got %v", action, ErrParticipationWithdrawn, err)
+		}
+	}
+}
+
+func TestAdjustUnknownAction(t *testing.T) {
+	_, err := Participation{}.Adjust(Adjustment{Action: "skip"}, time.Now())
+	if !IsAdjustmentError(err) {
+		t.Errorf("expected adjustment error, got %v", err)
+	}
+	if IsAdjustmentError(errors.New("db down")) {
+		t.Error("unexpected adjustment error")
+	}
+}
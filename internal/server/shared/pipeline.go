@@ -0,0 +1,279 @@
+package shared
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/kw3a/spotted-server/internal/server/textsearch"
+)
+
+// Stages every company starts with, applicants without a stage are in the
+// first one of their company
+const (
+	StageNew       = "new"
+	StageReviewed  = "reviewed"
+	StageInterview = "interview"
+	StageOffer     = "offer"
+	StageHired     = "hired"
+	StageRejected  = "rejected"
+)
+
+var DefaultStages = []Stage{
+	{Name: StageNew, Label: "Nuevo"},
+	{Name: StageReviewed, Label: "Revisado"},
+	{Name: StageInterview, Label: "Entrevista"},
+	{Name: StageOffer, Label: "Oferta"},
+	{Name: StageHired, Label: "Contratado"},
+	{Name: StageRejected, Label: "Rechazado"},
+}
+
+const (
+	MinStages         = 2
+	MaxStages         = 12
+	maxStageLabel     = 64
+	MaxStars          = 5
+	MaxApplicantTags  = 10
+	maxApplicantTag   = 32
+	MaxNoteLength     = 2000
+	MaxBulkApplicants = 200
+)
+
+// Actions a recruiter can take on an applicant, only moving and tagging
+// make sense in bulk
+const (
+	ReviewStage  = "stage"
+	ReviewRating = "rating"
+	ReviewNote   = "note"
+	ReviewTag    = "tag"
+	ReviewUntag  = "untag"
+)
+
+var BulkReviewActions = map[string]string{
+	ReviewStage: "Mover a",
+	ReviewTag:   "Etiquetar",
+}
+
+var (
+	ErrStage          = errors.New("etapa desconocida")
+	ErrStageCount     = errors.New("el proceso debe tener entre 2 y 12 etapas")
+	ErrStageLabel     = errors.New("cada etapa necesita un nombre de hasta 64 caracteres")
+	ErrStageDuplicate = errors.New("hay etapas repetidas")
+	ErrRating         = errors.New("la calificación debe estar entre 0 y 5 estrellas")
+	ErrApplicantTag   = errors.New("la etiqueta debe tener entre 1 y 32 caracteres")
+	ErrApplicantTags  = errors.New("un aplicante puede tener hasta 10 etiquetas")
+	ErrNote           = errors.New("la nota debe tener entre 1 y 2000 caracteres")
+	ErrReviewAction   = errors.New("acción desconocida")
+	ErrNoApplicants   = errors.New("selecciona al menos un aplicante")
+	ErrManyApplicants = errors.New("se pueden seleccionar hasta 200 aplicantes")
+)
+
+// Rule violations are shown to the recruiter, any other error is internal
+func IsReviewError(err error) bool {
+	return IsAny(err,
+		ErrStage,
+		ErrRating,
+		ErrApplicantTag,
+		ErrApplicantTags,
+		ErrNote,
+		ErrReviewAction,
+		ErrNoApplicants,
+		ErrManyApplicants,
+	)
+}
+
+// Mistakes in the pipeline form are shown next to it
+func IsStagesError(err error) bool {
+	return IsAny(err, ErrStageCount, ErrStageLabel, ErrStageDuplicate)
+}
+
+type Stage struct {
+	Name  string
+	Label string
+}
+
+// CompanyPipeline is the hiring process configured by a company
+type CompanyPipeline struct {
+	Stages []Stage
+	// ShareStage lets candidates see the stage they are in
+	ShareStage bool
+}
+
+// StageName is the key stored for a stage label, it survives renaming the
+// accents or the case of the label
+func StageName(label string) string {
+	words := strings.FieldsFunc(textsearch.Fold(label), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
+
+// ParseStages reads the pipeline of a company, one stage per line in order
+func ParseStages(text string) ([]Stage, error) {
+	stages := []Stage{}
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(text, "\n") {
+		label := strings.TrimSpace(line)
+		if label == "" {
+			continue
+		}
+		name := StageName(label)
+		if name == "" || len([]rune(label)) > maxStageLabel {
+			return nil, ErrStageLabel
+		}
+		if seen[name] {
+			return nil, ErrStageDuplicate
+		}
+		seen[name] = true
+		stages = append(stages, Stage{Name: name, Label: label})
+	}
+	if len(stages) < MinStages || len(stages) > MaxStages {
+		return nil, ErrStageCount
+	}
+	return stages, nil
+}
+
+// StagesText is the inverse of ParseStages, used to fill the form
+func StagesText(stages []Stage) string {
+	labels := make([]string, 0, len(stages))
+	for _, stage := range stages {
+		labels = append(labels, stage.Label)
+	}
+	return strings.Join(labels, "\n")
+}
+
+func HasStage(stages []Stage, name string) bool {
+	for _, stage := range stages {
+		if stage.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// StageLabel falls back to the stored name when the company removed the
+// stage after moving applicants to it
+func StageLabel(stages []Stage, name string) string {
+	for _, stage := range stages {
+		if stage.Name == name {
+			return stage.Label
+		}
+	}
+	return name
+}
+
+// ValidateApplicantTag trims and lowercases a tag so the same tag isn't
+// stored twice with different spelling
+func ValidateApplicantTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" || len([]rune(tag)) > maxApplicantTag {
+		return "", ErrApplicantTag
+	}
+	return tag, nil
+}
+
+type Review struct {
+	Action string
+	Stage  string
+	Stars  int32
+	Body   string
+	Tag    string
+}
+
+type StageChange struct {
+	From          string
+	To            string
+	RecruiterName string
+	CreatedAt     time.Time
+}
+
+type Note struct {
+	ID            string
+	Body          string
+	RecruiterName string
+	CreatedAt     time.Time
+}
+
+// Pipeline is where an applicant is in the hiring process of an offer,
+// Stages are the ones of the company that owns it
+type Pipeline struct {
+	ParticipationID string
+	Stage           string
+	Rating          int32
+	Tags            []string
+	History         []StageChange
+	Notes           []Note
+	Stages          []Stage
+	Alert           Alert
+	// WithdrawnAt is set when the candidate withdrew the application
+	WithdrawnAt time.Time
+	// Reveal is set once the identity of the applicant was shown in a
+	// blind review
+	Reveal IdentityReveal
+}
+
+func (p Pipeline) Withdrawn() bool {
+	return !p.WithdrawnAt.IsZero()
+}
+
+func (p Pipeline) Revealed() bool {
+	return !p.Reveal.CreatedAt.IsZero()
+}
+
+// Current is the stage of the applicant, new applicants are in the first
+// stage of the company
+func (p Pipeline) Current() string {
+	if p.Stage == "" && len(p.Stages) > 0 {
+		return p.Stages[0].Name
+	}
+	return p.Stage
+}
+
+func (p Pipeline) StageLabel() string {
+	return StageLabel(p.Stages, p.Current())
+}
+
+func (p Pipeline) Label(name string) string {
+	return StageLabel(p.Stages, name)
+}
+
+// Stars are the positions of the rating widget, true when filled
+func (p Pipeline) Stars() []bool {
+	stars := make([]bool, MaxStars)
+	for i := range stars {
+		stars[i] = int32(i) < p.Rating
+	}
+	return stars
+}
+
+type StageCount struct {
+	Stage
+	Count    int
+	Selected bool
+}
+
+// CountStages groups the applicants by stage in the order of the company
+// pipeline, stages removed from the pipeline are counted at the end
+func CountStages(stages []Stage, applications []Application, selected string) []StageCount {
+	counts := make([]StageCount, 0, len(stages))
+	index := make(map[string]int)
+	for _, stage := range stages {
+		index[stage.Name] = len(counts)
+		counts = append(counts, StageCount{Stage: stage, Selected: stage.Name == selected})
+	}
+	for _, application := range applications {
+		current := application.Pipeline.Current()
+		i, ok := index[current]
+		if !ok {
+			i = len(counts)
+			index[current] = i
+			counts = append(counts, StageCount{
+				Stage:    Stage{Name: current, Label: current},
+				Selected: current == selected,
+			})
+		}
+		counts[i].Count++
+	}
+	return counts
+}
@@ -0,0 +1,85 @@
+package shared
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseStages(t *testing.T) {
+	stages, err := ParseStages("  Nuevo \nEntrevista Técnica\n\nContratado")
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	expected := []Stage{
+		{Name: "nuevo", Label: "Nuevo"},
+		{Name: "entrevista-tecnica", Label: "Entrevista Técnica"},
+		{Name: "contratado", Label: "Contratado"},
+	}
+	if !reflect.DeepEqual(stages, expected) {
+		t.Errorf("expected %v, got %v", expected, stages)
+	}
+	if StagesText(stages) != "Nuevo\nEntrevista Técnica\nContratado" {
+		t.Errorf("unexpected text %q", StagesText(stages))
+	}
+}
+
+func TestParseStagesErrors(t *testing.T) {
+	cases := map[string]error{
+		"Nuevo":                  ErrStageCount,
+		"Nuevo\nNUEVO":           ErrStageDuplicate,
+		"Nuevo\n¿?":              ErrStageLabel,
+		"Entrevista\nentrevista": ErrStageDuplicate,
+	}
+	for text, expected := range cases {
+		if _, err := ParseStages(text); !errors.Is(err, expected) {
+			t.Errorf("%q: expected %v, got %v", text, expected, err)
+		}
+	}
+}
+
+func TestPipelineCurrent(t *testing.T) {
+	p := Pipeline{Stages: DefaultStages}
+	if p.Current() != StageNew || p.StageLabel() != "Nuevo" {
+		t.Errorf("expected the first stage, got %q", p.Current())
+	}
+	p.Stage = "removed"
+	if p.StageLabel() != "removed" {
+		t.Errorf("expected the stored name, got %q", p.StageLabel())
+	}
+}
+
+func TestPipelineStars(t *testing.T) {
+	stars := Pipeline{Rating: 2}.Stars()
+	if !reflect.DeepEqual(stars, []bool{true, true, false, false, false}) {
+		t.Errorf("unexpected stars %v", stars)
+	}
+}
+
+func TestCountStages(t *testing.T) {
+	stages := []Stage{{Name: "a", Label: "A"}, {Name: "b", Label: "B"}}
+	applications := []Application{
+		{},
+		{Pipeline: Pipeline{Stage: "a"}},
+		{Pipeline: Pipeline{Stage: "old"}},
+	}
+	for i := range applications {
+		applications[i].Pipeline.Stages = stages
+	}
+	counts := CountStages(stages, applications, "b")
+	if len(counts) != 3 {
+		t.Fatalf("expected 3 stages, got %v", counts)
+	}
+	if counts[0].Count != 2 || counts[1].Count != 0 || !counts[1].Selected || counts[2].Name != "old" {
+		t.Errorf("unexpected counts %v", counts)
+	}
+}
+
+func TestValidateApplicantTag(t *testing.T) {
+	if tag, err := ValidateApplicantTag("  Remoto "); err != nil || tag != "remoto" {
+		t.Errorf("unexpected tag %q, %v", tag, err)
+	}
+	if _, err := ValidateApplicantTag(" "); !errors.Is(err, ErrApplicantTag) {
+		t.Errorf("expected %v, got %v", ErrApplicantTag, err)
+	}
+}
@@ -0,0 +1,186 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+)
+
+// Problem difficulties, pools with no difficulty take any of them
+const (
+	DifficultyAny    int32 = 0
+	DifficultyEasy   int32 = 1
+	DifficultyMedium int32 = 2
+	DifficultyHard   int32 = 3
+)
+
+var DifficultyLabels = map[int32]string{
+	DifficultyEasy:   "Fácil",
+	DifficultyMedium: "Media",
+	DifficultyHard:   "Difícil",
+}
+
+// MaxDrawnProblems bounds the problems a candidate solves, pools may hold
+// more problems than that
+const MaxDrawnProblems = 5
+
+var (
+	ErrPoolTooSmall    = errors.New("un grupo no tiene suficientes problemas para sortear")
+	ErrTooManyProblems = fmt.Errorf("cada aplicante puede recibir hasta %d problemas", MaxDrawnProblems)
+)
+
+// Pool draws problems of the quiz that share the tag and the difficulty,
+// an empty tag or DifficultyAny match every problem
+type Pool struct {
+	Tag        string
+	Difficulty int32
+	Draw       int32
+}
+
+// PoolProblem is a problem of the quiz as seen by the draw
+type PoolProblem struct {
+	ID         string
+	Tag        string
+	Difficulty int32
+}
+
+func (p Pool) Matches(problem PoolProblem) bool {
+	return (p.Tag == "" || p.Tag == problem.Tag) &&
+		(p.Difficulty == DifficultyAny || p.Difficulty == problem.Difficulty)
+}
+
+func (p Pool) Label() string {
+	tag := "cualquier tema"
+	if p.Tag != "" {
+		tag = p.Tag
+	}
+	difficulty := "cualquier dificultad"
+	if label, ok := DifficultyLabels[p.Difficulty]; ok {
+		difficulty = label
+	}
+	return fmt.Sprintf("%d de %s, %s", p.Draw, tag, difficulty)
+}
+
+// CheckPools tells if every pool can draw its problems and the candidates
+// get at most MaxDrawnProblems. Pools draw in order and a problem is drawn
+// at most once
+func CheckPools(problems []PoolProblem, pools []Pool) error {
+	drawn := 0
+	for _, problem := range problems {
+		pooled := false
+		for _, pool := range pools {
+			pooled = pooled || pool.Matches(problem)
+		}
+		if !pooled {
+			drawn++
+		}
+	}
+	for _, pool := range pools {
+		drawn += int(pool.Draw)
+	}
+	if drawn > MaxDrawnProblems {
+		return ErrTooManyProblems
+	}
+	used := make(map[string]bool)
+	for _, pool := range pools {
+		available := 0
+		for _, problem := range problems {
+			if !used[problem.ID] && pool.Matches(problem) && available < int(pool.Draw) {
+				used[problem.ID] = true
+				available++
+			}
+		}
+		if available < int(pool.Draw) {
+			return fmt.Errorf("%w: %s", ErrPoolTooSmall, pool.Label())
+		}
+	}
+	return nil
+}
+
+// Draw picks the problems of a participation. Problems that match no pool
+// are always included, every pool adds its draw and the order of the quiz is
+// kept unless shuffle is set. The seed makes the draw repeatable
+func Draw(seed string, problems []PoolProblem, pools []Pool, shuffle bool) []string {
+	h := fnv.New64a()
+	h.Write([]byte(seed))
+	rng := rand.New(rand.NewSource(int64(h.Sum64())))
+	selected := make(map[string]bool)
+	for _, problem := range problems {
+		pooled := false
+		for _, pool := range pools {
+			pooled = pooled || pool.Matches(problem)
+		}
+		if !pooled {
+			selected[problem.ID] = true
+		}
+	}
+	drawn := make(map[string]bool)
+	for _, pool := range pools {
+		candidates := []string{}
+		for _, problem := range problems {
+			if !drawn[problem.ID] && pool.Matches(problem) {
+				candidates = append(candidates, problem.ID)
+			}
+		}
+		rng.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+		for i := 0; i < len(candidates) && i < int(pool.Draw); i++ {
+			drawn[candidates[i]] = true
+			selected[candidates[i]] = true
+		}
+	}
+	res := []string{}
+	for _, problem := range problems {
+		if selected[problem.ID] {
+			res = append(res, problem.ID)
+		}
+	}
+	if shuffle {
+		rng.Shuffle(len(res), func(i, j int) {
+			res[i], res[j] = res[j], res[i]
+		})
+	}
+	return res
+}
+
+func (p Problem) DifficultyLabel() string {
+	return DifficultyLabels[DifficultyWeight(p.Difficulty)]
+}
+
+func (p BankProblem) DifficultyLabel() string {
+	return DifficultyLabels[DifficultyWeight(p.Difficulty)]
+}
+
+// DifficultyWeight is how much a problem counts in the normalized score,
+// problems without difficulty count as medium ones
+func DifficultyWeight(difficulty int32) int32 {
+	if difficulty < DifficultyEasy || difficulty > DifficultyHard {
+		return DifficultyMedium
+	}
+	return difficulty
+}
+
+// NormalizedScore is the percentage of the assigned problems solved, every
+// problem weighted by its difficulty so candidates who drew harder problems
+// can be compared with the rest
+func (a Application) NormalizedScore() int {
+	scores := make(map[string]Score)
+	for _, summary := range a.Summary {
+		scores[summary.Submission.ProblemID] = summary.Score
+	}
+	total, solved := 0.0, 0.0
+	for _, problem := range a.Problems {
+		weight := float64(DifficultyWeight(problem.Difficulty))
+		total += weight
+		score := scores[problem.ID]
+		if score.TotalTestCases > 0 {
+			solved += weight * float64(score.AcceptedTestCases) / float64(score.TotalTestCases)
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return int(100*solved/total + 0.5)
+}
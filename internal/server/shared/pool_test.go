@@ -0,0 +1,93 @@
+package shared
+
+import (
+	"errors"
+	"testing"
+)
+
+func poolProblems() []PoolProblem {
+	return []PoolProblem{
+		{ID: "intro", Tag: "intro", Difficulty: DifficultyEasy},
+		{ID: "g1", Tag: "grafos", Difficulty: DifficultyMedium},
+		{ID: "g2", Tag: "grafos", Difficulty: DifficultyMedium},
+		{ID: "g3", Tag: "grafos", Difficulty: DifficultyHard},
+		{ID: "d1", Tag: "dp", Difficulty: DifficultyHard},
+		{ID: "d2", Tag: "dp", Difficulty: DifficultyHard},
+	}
+}
+
+func TestDraw(t *testing.T) {
+	problems := poolProblems()
+	pools := []Pool{{Tag: "grafos", Draw: 2}, {Difficulty: DifficultyHard, Draw: 1}}
+	first := Draw("participation", problems, pools, false)
+	if len(first) != 4 || first[0] != "intro" {
+		t.Fatalf("expected the unpooled problem and three drawn ones, got %v", first)
+	}
+	again := Draw("participation", problems, pools, false)
+	for i := range first {
+		if first[i] != again[i] {
+			t.Fatalf("expected the same draw, got %v and %v", first, again)
+		}
+	}
+	graphs, hard := 0, 0
+	for _, id := range first {
+		switch id {
+		case "g1", "g2", "g3":
+			graphs++
+		case "d1", "d2":
+			hard++
+		}
+	}
+	if graphs+hard != 3 || graphs < 2 {
+		t.Errorf("unexpected draw %v", first)
+	}
+}
+
+func TestDrawVariesBySeed(t *testing.T) {
+	problems := poolProblems()
+	pools := []Pool{{Tag: "grafos", Draw: 1}}
+	seen := make(map[string]bool)
+	for _, seed := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		drawn := Draw(seed, problems, pools, true)
+		if len(drawn) != 4 {
+			t.Fatalf("expected 4 problems, got %v", drawn)
+		}
+		for _, id := range drawn {
+			seen[id] = true
+		}
+	}
+	if !seen["g1"] || !seen["g2"] || !seen["g3"] {
+		t.Errorf("expected every graph problem to be drawn at least once, got %v", seen)
+	}
+}
+
+func TestCheckPools(t *testing.T) {
+	problems := poolProblems()
+	if err := CheckPools(problems, []Pool{{Tag: "dp", Draw: 2}, {Tag: "grafos", Draw: 1}}); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	err := CheckPools(problems, []Pool{{Tag: "grafos", Draw: 1}, {Tag: "dp", Draw: 1}, {Difficulty: DifficultyEasy, Draw: 2}})
+	if !errors.Is(err, ErrPoolTooSmall) {
+		t.Errorf("expected the easy pool to be too small, got %v", err)
+	}
+	err = CheckPools(problems, []Pool{{Tag: "grafos", Draw: 3}, {Tag: "dp", Draw: 2}})
+	if !errors.Is(err, ErrTooManyProblems) {
+		t.Errorf("expected too many problems with the unpooled one, got %v", err)
+	}
+}
+
+func TestNormalizedScore(t *testing.T) {
+	a := Application{
+		Problems: []PoolProblem{{ID: "easy", Difficulty: DifficultyEasy}, {ID: "hard", Difficulty: DifficultyHard}},
+		Summary: []Summary{
+			{Submission: Submission{ProblemID: "hard"}, Score: Score{AcceptedTestCases: 1, TotalTestCases: 2}},
+			{Submission: Submission{ProblemID: "easy"}, Score: Score{AcceptedTestCases: 2, TotalTestCases: 2}},
+		},
+	}
+	if score := a.NormalizedScore(); score != 63 {
+		t.Errorf("expected 63, got %d", score)
+	}
+	if score := (Application{}).NormalizedScore(); score != 0 {
+		t.Errorf("expected 0 without problems, got %d", score)
+	}
+}
@@ -0,0 +1,218 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Question kinds, coding problems have no question
+const (
+	QuestionSingle   = "single"
+	QuestionMultiple = "multiple"
+	QuestionNumeric  = "numeric"
+	QuestionShort    = "short"
+	QuestionText     = "text"
+)
+
+const (
+	MaxShortAnswer = 255
+	MaxTextAnswer  = 5000
+)
+
+var (
+	ErrNotGradable     = errors.New("solo las respuestas de texto libre se califican manualmente")
+	ErrScoreOutOfRange = errors.New("puntaje fuera de rango")
+)
+
+var QuestionLabels = map[string]string{
+	QuestionSingle:   "Opción única",
+	QuestionMultiple: "Opción múltiple",
+	QuestionNumeric:  "Respuesta numérica",
+	QuestionShort:    "Respuesta corta",
+	QuestionText:     "Texto libre",
+}
+
+// Question is a problem answered without code. Choice questions are scored
+// with their options, numeric and short ones with the accepted answers and
+// free text is graded by the recruiter following the rubric
+type Question struct {
+	Kind      string
+	Points    int32
+	Rubric    string
+	Tolerance float64
+	Options   []QuestionOption
+	Answers   []string
+}
+
+type QuestionOption struct {
+	Label   string
+	Correct bool
+}
+
+// QuestionResponse is the answer of a participation, Graded is false while
+// a free text answer waits for the recruiter
+type QuestionResponse struct {
+	ID       string
+	Answer   string
+	Graded   bool
+	Score    int32
+	Feedback string
+}
+
+func (q Question) Label() string {
+	return QuestionLabels[q.Kind]
+}
+
+func (q Question) Choice() bool {
+	return q.Kind == QuestionSingle || q.Kind == QuestionMultiple
+}
+
+// OptionsText writes the options the way the recruiter types them, one per
+// line and the correct ones starting with '*'
+func (q Question) OptionsText() string {
+	lines := make([]string, len(q.Options))
+	for i, option := range q.Options {
+		if option.Correct {
+			lines[i] = "*" + option.Label
+		} else {
+			lines[i] = option.Label
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ChoiceAnswer encodes the selected options as their sorted positions
+// separated by commas
+func (q Question) ChoiceAnswer(values []string) (string, error) {
+	positions := []int{}
+	seen := map[int]bool{}
+	for _, value := range values {
+		position, err := strconv.Atoi(value)
+		if err != nil || position < 0 || position >= len(q.Options) {
+			return "", fmt.Errorf("opción inválida")
+		}
+		if !seen[position] {
+			seen[position] = true
+			positions = append(positions, position)
+		}
+	}
+	if len(positions) == 0 {
+		return "", fmt.Errorf("selecciona una opción")
+	}
+	if q.Kind == QuestionSingle && len(positions) > 1 {
+		return "", fmt.Errorf("solo se puede seleccionar una opción")
+	}
+	sort.Ints(positions)
+	parts := make([]string, len(positions))
+	for i, position := range positions {
+		parts[i] = strconv.Itoa(position)
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// Encode validates the values sent by the candidate and returns the answer
+// as it is stored
+func (q Question) Encode(values []string) (string, error) {
+	if q.Choice() {
+		return q.ChoiceAnswer(values)
+	}
+	answer := ""
+	if len(values) > 0 {
+		answer = strings.TrimSpace(values[0])
+	}
+	if answer == "" {
+		return "", fmt.Errorf("la respuesta está vacía")
+	}
+	switch q.Kind {
+	case QuestionNumeric:
+		if _, err := ParseNumber(answer); err != nil {
+			return "", err
+		}
+	case QuestionShort:
+		if len(answer) > MaxShortAnswer {
+			return "", fmt.Errorf("la respuesta no puede tener más de %d caracteres", MaxShortAnswer)
+		}
+	case QuestionText:
+		if len(answer) > MaxTextAnswer {
+			return "", fmt.Errorf("la respuesta no puede tener más de %d caracteres", MaxTextAnswer)
+		}
+	}
+	return answer, nil
+}
+
+// Selected tells if the option at the position is part of the answer
+func (q Question) Selected(answer string, position int) bool {
+	for _, part := range strings.Split(answer, ",") {
+		if part == strconv.Itoa(position) {
+			return true
+		}
+	}
+	return false
+}
+
+// AnswerLabels shows a choice answer with the labels of its options
+func (q Question) AnswerLabels(answer string) []string {
+	res := []string{}
+	for i, option := range q.Options {
+		if q.Selected(answer, i) {
+			res = append(res, option.Label)
+		}
+	}
+	return res
+}
+
+// Grade scores the answer, ok is false for free text which is graded by
+// the recruiter. Every other kind gets all the points or none
+func (q Question) Grade(answer string) (score int32, ok bool) {
+	correct := false
+	switch q.Kind {
+	case QuestionText:
+		return 0, false
+	case QuestionSingle, QuestionMultiple:
+		correct = len(q.Options) > 0
+		for i, option := range q.Options {
+			if option.Correct != q.Selected(answer, i) {
+				correct = false
+			}
+		}
+	case QuestionNumeric:
+		value, err := ParseNumber(answer)
+		if err != nil {
+			break
+		}
+		for _, accepted := range q.Answers {
+			expected, err := ParseNumber(accepted)
+			if err == nil && math.Abs(value-expected) <= q.Tolerance {
+				correct = true
+			}
+		}
+	case QuestionShort:
+		for _, accepted := range q.Answers {
+			if NormalizeShortAnswer(answer) == NormalizeShortAnswer(accepted) {
+				correct = true
+			}
+		}
+	}
+	if correct {
+		return q.Points, true
+	}
+	return 0, true
+}
+
+// ParseNumber accepts a decimal comma as well as a decimal point
+func ParseNumber(s string) (float64, error) {
+	value, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", "."), 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("'%s' no es un número", strings.TrimSpace(s))
+	}
+	return value, nil
+}
+
+// NormalizeShortAnswer ignores case and repeated spaces
+func NormalizeShortAnswer(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
@@ -0,0 +1,72 @@
+package shared
+
+import "testing"
+
+func TestQuestionChoiceAnswer(t *testing.T) {
+	q := Question{Kind: QuestionMultiple, Options: []QuestionOption{{Label: "a"}, {Label: "b"}, {Label: "c"}}}
+	answer, err := q.ChoiceAnswer([]string{"2", "0", "2"})
+	if err != nil || answer != "0,2" {
+		t.Errorf("expected 0,2, got %q, %v", answer, err)
+	}
+	for _, values := range [][]string{{}, {"3"}, {"-1"}, {"a"}} {
+		if _, err := q.ChoiceAnswer(values); err == nil {
+			t.Errorf("expected error for %v", values)
+		}
+	}
+	q.Kind = QuestionSingle
+	if _, err := q.ChoiceAnswer([]string{"0", "1"}); err == nil {
+		t.Error("expected error for two options in a single choice question")
+	}
+	if labels := q.AnswerLabels("0,2"); len(labels) != 2 || labels[1] != "c" {
+		t.Errorf("unexpected labels %v", labels)
+	}
+}
+
+func TestQuestionEncode(t *testing.T) {
+	numeric := Question{Kind: QuestionNumeric}
+	if answer, err := numeric.Encode([]string{" 3,5 "}); err != nil || answer != "3,5" {
+		t.Errorf("expected 3,5, got %q, %v", answer, err)
+	}
+	if _, err := numeric.Encode([]string{"tres"}); err == nil {
+		t.Error("expected error for a word in a numeric question")
+	}
+	if _, err := (Question{Kind: QuestionText}).Encode([]string{"  "}); err == nil {
+		t.Error("expected error for an empty answer")
+	}
+	long := make([]byte, MaxShortAnswer+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+	if _, err := (Question{Kind: QuestionShort}).Encode([]string{string(long)}); err == nil {
+		t.Error("expected error for a long short answer")
+	}
+}
+
+func TestQuestionGrade(t *testing.T) {
+	choice := Question{Kind: QuestionMultiple, Points: 5, Options: []QuestionOption{{Correct: true}, {}, {Correct: true}}}
+	numeric := Question{Kind: QuestionNumeric, Points: 3, Answers: []string{"3.14"}, Tolerance: 0.01}
+	short := Question{Kind: QuestionShort, Points: 2, Answers: []string{"Búsqueda  binaria"}}
+	cases := []struct {
+		name     string
+		question Question
+		answer   string
+		score    int32
+	}{
+		{"all correct options", choice, "0,2", 5},
+		{"missing option", choice, "0", 0},
+		{"extra option", choice, "0,1,2", 0},
+		{"within tolerance", numeric, "3,141", 3},
+		{"out of tolerance", numeric, "3.2", 0},
+		{"same words", short, " búsqueda binaria", 2},
+		{"other words", short, "lineal", 0},
+	}
+	for _, c := range cases {
+		score, ok := c.question.Grade(c.answer)
+		if !ok || score != c.score {
+			t.Errorf("%s: expected %d, got %d (%v)", c.name, c.score, score, ok)
+		}
+	}
+	if _, ok := (Question{Kind: QuestionText, Points: 10}).Grade("texto"); ok {
+		t.Error("free text should wait for the recruiter")
+	}
+}
@@ -0,0 +1,147 @@
+package shared
+
+import (
+	"errors"
+)
+
+// Reference solutions are written by the problem author, accepted ones must
+// solve every test case and wrong ones must fail at least one, otherwise the
+// test data is inconsistent
+const (
+	ReferenceAccepted = "accepted"
+	ReferenceWrong    = "wrong"
+)
+
+var ReferenceExpectedLabels = map[string]string{
+	ReferenceAccepted: "Correcta",
+	ReferenceWrong:    "Incorrecta",
+}
+
+const (
+	ValidationPending = "pending"
+	ValidationPassed  = "passed"
+	ValidationFailed  = "failed"
+)
+
+var ValidationLabels = map[string]string{
+	ValidationPending: "Pendiente",
+	ValidationPassed:  "Válida",
+	ValidationFailed:  "Fallida",
+}
+
+// ReferenceTimeLimit is the limit in ms used to run reference solutions, it
+// is generous so the recorded runtimes can suggest the real limit
+const ReferenceTimeLimit = 10000
+
+var ErrReferenceValidation = errors.New("las soluciones de referencia de un problema no fueron validadas")
+
+type ReferenceSolution struct {
+	ID         string
+	ProblemID  string
+	LanguageID int32
+	Language   string
+	Src        string
+	Expected   string
+	TestCases  int
+	Results    []ReferenceResult
+}
+
+type ReferenceResult struct {
+	TestCaseID string
+	Status     string
+	Time       int32
+	Memory     int32
+}
+
+// Verdict tells whether the solution behaves as its author expected under
+// the given time limit in ms
+func (s ReferenceSolution) Verdict(timeLimit int32) string {
+	failed := false
+	for _, result := range s.Results {
+		if result.Status != "Accepted" || result.Time > timeLimit {
+			failed = true
+			break
+		}
+	}
+	if s.Expected == ReferenceWrong {
+		if failed {
+			return ValidationPassed
+		}
+		if len(s.Results) < s.TestCases {
+			return ValidationPending
+		}
+		return ValidationFailed
+	}
+	if failed {
+		return ValidationFailed
+	}
+	if len(s.Results) < s.TestCases {
+		return ValidationPending
+	}
+	return ValidationPassed
+}
+
+func (s ReferenceSolution) VerdictLabel(timeLimit int32) string {
+	return ValidationLabels[s.Verdict(timeLimit)]
+}
+
+func (s ReferenceSolution) ExpectedLabel() string {
+	return ReferenceExpectedLabels[s.Expected]
+}
+
+// MaxTime is the slowest accepted test case in ms
+func (s ReferenceSolution) MaxTime() int32 {
+	var res int32
+	for _, result := range s.Results {
+		if result.Status == "Accepted" && result.Time > res {
+			res = result.Time
+		}
+	}
+	return res
+}
+
+// ReferenceStatus summarizes the solutions of a problem version, a problem
+// without solutions has nothing to validate and returns an empty status
+func ReferenceStatus(solutions []ReferenceSolution, timeLimit int32) string {
+	if len(solutions) == 0 {
+		return ""
+	}
+	status := ValidationPassed
+	for _, solution := range solutions {
+		switch solution.Verdict(timeLimit) {
+		case ValidationFailed:
+			return ValidationFailed
+		case ValidationPending:
+			status = ValidationPending
+		}
+	}
+	return status
+}
+
+// SuggestTimeLimit proposes three times the slowest runtime of the accepted
+// reference solutions, rounded up to 100 ms. It returns 0 while there are no
+// runtimes to base it on
+func SuggestTimeLimit(solutions []ReferenceSolution) int32 {
+	var slowest int32
+	measured := false
+	for _, solution := range solutions {
+		if solution.Expected != ReferenceAccepted || len(solution.Results) == 0 {
+			continue
+		}
+		measured = true
+		if t := solution.MaxTime(); t > slowest {
+			slowest = t
+		}
+	}
+	if !measured {
+		return 0
+	}
+	suggested := (slowest*3 + 99) / 100 * 100
+	if suggested < 500 {
+		return 500
+	}
+	if suggested > ReferenceTimeLimit {
+		return ReferenceTimeLimit
+	}
+	return suggested
+}
@@ -0,0 +1,70 @@
+package shared
+
+import "testing"
+
+func TestReferenceVerdict(t *testing.T) {
+	accepted := ReferenceResult{Status: "Accepted", Time: 100}
+	wrong := ReferenceResult{Status: "Wrong Answer", Time: 100}
+	cases := []struct {
+		name     string
+		solution ReferenceSolution
+		want     string
+	}{
+		{"accepted pending", ReferenceSolution{Expected: ReferenceAccepted, TestCases: 2, Results: []ReferenceResult{accepted}}, ValidationPending},
+		{"accepted passed", ReferenceSolution{Expected: ReferenceAccepted, TestCases: 2, Results: []ReferenceResult{accepted, accepted}}, ValidationPassed},
+		{"accepted failed early", ReferenceSolution{Expected: ReferenceAccepted, TestCases: 2, Results: []ReferenceResult{wrong}}, ValidationFailed},
+		{"accepted too slow", ReferenceSolution{Expected: ReferenceAccepted, TestCases: 1, Results: []ReferenceResult{{Status: "Accepted", Time: 1500}}}, ValidationFailed},
+		{"wrong pending", ReferenceSolution{Expected: ReferenceWrong, TestCases: 2, Results: []ReferenceResult{accepted}}, ValidationPending},
+		{"wrong passed early", ReferenceSolution{Expected: ReferenceWrong, TestCases: 2, Results: []ReferenceResult{wrong}}, ValidationPassed},
+		{"wrong solves everything", ReferenceSolution{Expected: ReferenceWrong, TestCases: 2, Results: []ReferenceResult{accepted, accepted}}, ValidationFailed},
+	}
+	for _, c := range cases {
+		if got := c.solution.Verdict(1000); got != c.want {
+			t.Errorf("%s: expected %s, got %s", c.name, c.want, got)
+		}
+	}
+}
+
+func TestReferenceStatus(t *testing.T) {
+	passed := ReferenceSolution{Expected: ReferenceAccepted, TestCases: 1, Results: []ReferenceResult{{Status: "Accepted"}}}
+	pending := ReferenceSolution{Expected: ReferenceAccepted, TestCases: 1}
+	failed := ReferenceSolution{Expected: ReferenceWrong, TestCases: 1, Results: []ReferenceResult{{Status: "Accepted"}}}
+	if got := ReferenceStatus(nil, 1000); got != "" {
+		t.Errorf("expected empty status, got %s", got)
+	}
+	if got := ReferenceStatus([]ReferenceSolution{passed}, 1000); got != ValidationPassed {
+		t.Errorf("expected %s, got %s", ValidationPassed, got)
+	}
+	if got := ReferenceStatus([]ReferenceSolution{passed, pending}, 1000); got != ValidationPending {
+		t.Errorf("expected %s, got %s", ValidationPending, got)
+	}
+	if got := ReferenceStatus([]ReferenceSolution{pending, failed}, 1000); got != ValidationFailed {
+		t.Errorf("expected %s, got %s", ValidationFailed, got)
+	}
+}
+
+func TestSuggestTimeLimit(t *testing.T) {
+	solution := func(expected string, times ...int32) ReferenceSolution {
+		s := ReferenceSolution{Expected: expected, TestCases: len(times)}
+		for _, t := range times {
+			s.Results = append(s.Results, ReferenceResult{Status: "Accepted", Time: t})
+		}
+		return s
+	}
+	cases := []struct {
+		solutions []ReferenceSolution
+		want      int32
+	}{
+		{nil, 0},
+		{[]ReferenceSolution{solution(ReferenceAccepted)}, 0},
+		{[]ReferenceSolution{solution(ReferenceAccepted, 10, 20)}, 500},
+		{[]ReferenceSolution{solution(ReferenceAccepted, 120, 410), solution(ReferenceAccepted, 300)}, 1300},
+		{[]ReferenceSolution{solution(ReferenceAccepted, 100), solution(ReferenceWrong, 2000)}, 500},
+		{[]ReferenceSolution{solution(ReferenceAccepted, 5000)}, ReferenceTimeLimit},
+	}
+	for i, c := range cases {
+		if got := SuggestTimeLimit(c.solutions); got != c.want {
+			t.Errorf("case %d: expected %d, got %d", i, c.want, got)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package shared
+
+import (
+	"errors"
+	"time"
+)
+
+// A rejudge can target every submission of a quiz problem, the submissions
+// of one participation or a single submission
+const (
+	RejudgeProblem       = "problem"
+	RejudgeParticipation = "participation"
+	RejudgeSubmission    = "submission"
+)
+
+var ErrNothingToRejudge = errors.New("no hay envíos para volver a evaluar")
+
+type RejudgeRequest struct {
+	Scope    string
+	TargetID string
+	// Latest moves a quiz problem to the newest version of the library
+	// problem before judging, it only applies to the problem scope
+	Latest bool
+}
+
+// RejudgeEntry is a submission sent again to the judge
+type RejudgeEntry struct {
+	SubmissionID string
+	ProblemID    string
+	Src          string
+	LanguageID   int32
+	Signature    string
+	Database     *Database
+}
+
+type Rejudge struct {
+	ID        string
+	OfferID   string
+	Scope     string
+	CreatedAt time.Time
+	Rows      []RejudgeRow
+}
+
+type RejudgeRow struct {
+	SubmissionID string
+	Applicant    string
+	ProblemTitle string
+	Before       int32
+	After        int32
+	Judged       int32
+	Total        int32
+	Error        string
+}
+
+func (r RejudgeRow) Done() bool {
+	return r.Error != "" || r.Judged >= r.Total
+}
+
+func (r Rejudge) Judged() int32 {
+	var res int32
+	for _, row := range r.Rows {
+		if row.Error != "" {
+			res += row.Total
+		} else {
+			res += row.Judged
+		}
+	}
+	return res
+}
+
+func (r Rejudge) Total() int32 {
+	var res int32
+	for _, row := range r.Rows {
+		res += row.Total
+	}
+	return res
+}
+
+func (r Rejudge) Done() bool {
+	for _, row := range r.Rows {
+		if !row.Done() {
+			return false
+		}
+	}
+	return true
+}
+
+// Changed counts the submissions whose score is different after the rejudge
+func (r Rejudge) Changed() int {
+	res := 0
+	for _, row := range r.Rows {
+		if row.Done() && row.Before != row.After {
+			res++
+		}
+	}
+	return res
+}
@@ -0,0 +1,30 @@
+package shared
+
+import "testing"
+
+func TestRejudgeProgress(t *testing.T) {
+	rejudge := Rejudge{Rows: []RejudgeRow{
+		{Before: 1, After: 3, Judged: 3, Total: 3},
+		{Before: 2, After: 2, Judged: 3, Total: 3},
+		{Before: 0, After: 0, Judged: 1, Total: 3},
+	}}
+	if rejudge.Done() {
+		t.Error("expected rejudge in progress")
+	}
+	if got := rejudge.Judged(); got != 7 {
+		t.Errorf("expected 7 judged test cases, got %d", got)
+	}
+	if got := rejudge.Total(); got != 9 {
+		t.Errorf("expected 9 test cases, got %d", got)
+	}
+	if got := rejudge.Changed(); got != 1 {
+		t.Errorf("expected 1 changed submission, got %d", got)
+	}
+	rejudge.Rows[2].Error = "judge down"
+	if !rejudge.Done() {
+		t.Error("a failed submission must not keep the rejudge running")
+	}
+	if got := rejudge.Judged(); got != rejudge.Total() {
+		t.Errorf("expected every test case accounted for, got %d/%d", got, rejudge.Total())
+	}
+}
@@ -0,0 +1,187 @@
+package shared
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Structured attributes of an offer, an empty seniority or contract means
+// the recruiter didn't say
+const (
+	ModalityRemote = "remote"
+	ModalityHybrid = "hybrid"
+	ModalityOnsite = "onsite"
+)
+
+var ModalityLabels = map[string]string{
+	ModalityRemote: "Remoto",
+	ModalityHybrid: "Híbrido",
+	ModalityOnsite: "Presencial",
+}
+
+var SeniorityLabels = map[string]string{
+	"intern": "Practicante",
+	"junior": "Junior",
+	"semi":   "Semi senior",
+	"senior": "Senior",
+	"lead":   "Líder técnico",
+}
+
+var ContractLabels = map[string]string{
+	"fulltime":   "Tiempo completo",
+	"parttime":   "Medio tiempo",
+	"contractor": "Por proyecto",
+	"internship": "Pasantía",
+}
+
+var Currencies = []string{"USD", "EUR", "MXN", "COP", "BOB"}
+
+const (
+	WageMonthly = "month"
+	WageYearly  = "year"
+)
+
+var WagePeriodLabels = map[string]string{
+	WageMonthly: "mensual",
+	WageYearly:  "anual",
+}
+
+const (
+	SortDate      = "date"
+	SortWage      = "wage"
+	SortRelevance = "relevance"
+)
+
+var ErrInvalidCursor = errors.New("cursor inválido")
+
+// MaxOfferTags bounds the tech stack of an offer
+const MaxOfferTags = 8
+
+func ValidCurrency(currency string) bool {
+	for _, c := range Currencies {
+		if c == currency {
+			return true
+		}
+	}
+	return false
+}
+
+// OfferCursor is the position of the last offer of a page, the next page
+// starts right after it in the sort order
+type OfferCursor struct {
+	Key float64
+	ID  string
+}
+
+func (c OfferCursor) String() string {
+	return strconv.FormatFloat(c.Key, 'g', -1, 64) + "_" + c.ID
+}
+
+func ParseOfferCursor(value string) (OfferCursor, error) {
+	key, id, found := strings.Cut(value, "_")
+	if !found || id == "" {
+		return OfferCursor{}, ErrInvalidCursor
+	}
+	parsed, err := strconv.ParseFloat(key, 64)
+	if err != nil {
+		return OfferCursor{}, ErrInvalidCursor
+	}
+	return OfferCursor{Key: parsed, ID: id}, nil
+}
+
+// OfferFilters narrow the published offers, zero values don't filter. Wages
+// are compared as annual amounts
+type OfferFilters struct {
+	Query     string
+	Modality  string
+	Seniority string
+	Contract  string
+	CompanyID string
+	Currency  string
+	Tags      []int32
+	MinWage   int32
+	MaxWage   int32
+	Sort      string
+	// After is nil for the first page
+	After *OfferCursor
+}
+
+// Values encodes the filters as the query string of the search page, the
+// cursor is left out
+func (f OfferFilters) Values() url.Values {
+	v := url.Values{}
+	set := func(key, value string) {
+		if value != "" {
+			v.Set(key, value)
+		}
+	}
+	set("q", f.Query)
+	set("modality", f.Modality)
+	set("seniority", f.Seniority)
+	set("contract", f.Contract)
+	set("company", f.CompanyID)
+	set("currency", f.Currency)
+	for _, tag := range f.Tags {
+		v.Add("tag", strconv.Itoa(int(tag)))
+	}
+	if f.MinWage > 0 {
+		v.Set("minWage", strconv.Itoa(int(f.MinWage)))
+	}
+	if f.MaxWage > 0 {
+		v.Set("maxWage", strconv.Itoa(int(f.MaxWage)))
+	}
+	set("sort", f.Sort)
+	return v
+}
+
+func (f OfferFilters) HasTag(id int32) bool {
+	for _, tag := range f.Tags {
+		if tag == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Facet is a value of a filter with the offers that match it
+type Facet struct {
+	Value    string
+	Label    string
+	Count    int64
+	Selected bool
+}
+
+// OfferFacets are counted with the other filters applied, so a selected
+// value still shows its alternatives
+type OfferFacets struct {
+	Modalities []Facet
+	Tags       []Facet
+	Companies  []Facet
+}
+
+func (o Offer) ModalityLabel() string {
+	return ModalityLabels[o.Modality]
+}
+
+func (o Offer) SeniorityLabel() string {
+	return SeniorityLabels[o.Seniority]
+}
+
+func (o Offer) ContractLabel() string {
+	return ContractLabels[o.Contract]
+}
+
+func (o Offer) WagePeriodLabel() string {
+	return WagePeriodLabels[o.WagePeriod]
+}
+
+func (o Offer) HasTag(id int32) bool {
+	for _, tag := range o.Tags {
+		if tag.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,45 @@
+package shared
+
+import "testing"
+
+func TestOfferFiltersValues(t *testing.T) {
+	cases := []struct {
+		filters  OfferFilters
+		expected string
+	}{
+		{OfferFilters{After: &OfferCursor{Key: 2, ID: "o"}}, ""},
+		{OfferFilters{Sort: SortWage}, "sort=wage"},
+		{OfferFilters{Query: "go dev", Modality: ModalityHybrid}, "modality=hybrid&q=go+dev"},
+		{OfferFilters{Tags: []int32{62, 71}, MinWage: 100}, "minWage=100&tag=62&tag=71"},
+		{OfferFilters{CompanyID: "c", Currency: "EUR", MaxWage: 200}, "company=c&currency=EUR&maxWage=200"},
+	}
+	for _, c := range cases {
+		if got := c.filters.Values().Encode(); got != c.expected {
+			t.Errorf("%+v: expected %q, got %q", c.filters, c.expected, got)
+		}
+	}
+}
+
+func TestOfferFiltersHasTag(t *testing.T) {
+	filters := OfferFilters{Tags: []int32{62, 71}}
+	if !filters.HasTag(71) || filters.HasTag(54) {
+		t.Errorf("unexpected tags %v", filters.Tags)
+	}
+}
+
+func TestOfferCursor(t *testing.T) {
+	for _, cursor := range []OfferCursor{{Key: 1700000000, ID: "a-b"}, {Key: 0.1 + 0.2, ID: "c"}, {Key: 0, ID: "d"}} {
+		parsed, err := ParseOfferCursor(cursor.String())
+		if err != nil {
+			t.Errorf("%v: unexpected error %v", cursor, err)
+		}
+		if parsed != cursor {
+			t.Errorf("expected %v, got %v", cursor, parsed)
+		}
+	}
+	for _, value := range []string{"", "1.5", "1.5_", "x_id"} {
+		if _, err := ParseOfferCursor(value); err == nil {
+			t.Errorf("%q: expected error", value)
+		}
+	}
+}
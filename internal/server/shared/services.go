@@ -20,3 +20,17 @@ type AuthRep interface {
 type TemplatesRepo interface {
 	Render(w io.Writer, name string, data interface{}) error
 }
+
+// WebhookEmitter writes the event to the outbox of the company webhooks
+type WebhookEmitter interface {
+	EmitWebhookEvent(ctx context.Context, event WebhookEvent) error
+}
+
+// Notifier writes the notifications of the event and delivers them
+type Notifier interface {
+	Notify(ctx context.Context, event NotificationEvent) error
+}
+
+type Mailer interface {
+	Send(ctx context.Context, email Email) error
+}
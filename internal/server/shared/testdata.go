@@ -0,0 +1,100 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Test data longer than InlineTestDataSize bytes is kept compressed in the
+// blob store, the test case row only keeps a preview of TestPreviewSize bytes
+const (
+	InlineTestDataSize = 4096
+	TestPreviewSize    = 1000
+	MaxTestDataSize    = 16 << 20
+)
+
+var ErrGenerator = errors.New("el generador de casos de prueba falló")
+
+// Generator builds test cases when the problem is published. Src is run once
+// per seed with the seed as its input and prints the input of a test case,
+// Solution reads that input and prints the expected output
+type Generator struct {
+	LanguageID int32
+	Src        string
+	Solution   string
+	Seeds      []string
+}
+
+type TestGenerator interface {
+	Generate(ctx context.Context, generator Generator) ([]TestCase, error)
+}
+
+// ExpandGenerators appends the generated test cases to every problem with a
+// generator, the generator is dropped once it ran
+func ExpandGenerators(ctx context.Context, gen TestGenerator, problems []Problem) error {
+	for i, problem := range problems {
+		if problem.Generator == nil {
+			continue
+		}
+		if gen == nil {
+			return fmt.Errorf("%w: no hay un juez para ejecutarlo", ErrGenerator)
+		}
+		generated, err := gen.Generate(ctx, *problem.Generator)
+		if err != nil {
+			return err
+		}
+		problems[i].TestCases = append(problems[i].TestCases, generated...)
+		problems[i].Generator = nil
+	}
+	return nil
+}
+
+type TestDataReader interface {
+	ReadTestData(ctx context.Context, hash string) (string, error)
+}
+
+// FillTestData replaces the previews of the test cases with the data of
+// their blobs, packages carry the whole data
+func FillTestData(ctx context.Context, reader TestDataReader, problem *Problem) error {
+	for i, tc := range problem.TestCases {
+		if tc.InputHash != "" {
+			input, err := reader.ReadTestData(ctx, tc.InputHash)
+			if err != nil {
+				return err
+			}
+			problem.TestCases[i].Input, problem.TestCases[i].InputHash = input, ""
+		}
+		if tc.OutputHash != "" {
+			output, err := reader.ReadTestData(ctx, tc.OutputHash)
+			if err != nil {
+				return err
+			}
+			problem.TestCases[i].Output, problem.TestCases[i].OutputHash = output, ""
+		}
+	}
+	return nil
+}
+
+// Truncated tells whether the recruiter only sees a preview of the data
+func (tc TestCase) Truncated() bool {
+	return tc.InputHash != "" || tc.OutputHash != ""
+}
+
+func (tc TestCase) InputSizeLabel() string {
+	return SizeLabel(tc.InputSize)
+}
+
+func (tc TestCase) OutputSizeLabel() string {
+	return SizeLabel(tc.OutputSize)
+}
+
+func SizeLabel(size int32) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", size)
+}
@@ -0,0 +1,48 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type generatorMock struct {
+	err error
+}
+
+func (g generatorMock) Generate(ctx context.Context, generator Generator) ([]TestCase, error) {
+	res := []TestCase{}
+	for _, seed := range generator.Seeds {
+		res = append(res, TestCase{Input: seed, Output: seed})
+	}
+	return res, g.err
+}
+
+func TestExpandGenerators(t *testing.T) {
+	problems := []Problem{
+		{TestCases: []TestCase{{Input: "a"}}},
+		{TestCases: []TestCase{{Input: "b"}}, Generator: &Generator{Seeds: []string{"1", "2"}}},
+	}
+	if err := ExpandGenerators(context.Background(), generatorMock{}, problems); err != nil {
+		t.Fatal(err)
+	}
+	if len(problems[0].TestCases) != 1 || len(problems[1].TestCases) != 3 || problems[1].Generator != nil {
+		t.Errorf("expected the generated test cases, got %+v", problems[1])
+	}
+	failing := []Problem{{Generator: &Generator{}}}
+	if err := ExpandGenerators(context.Background(), generatorMock{err: ErrGenerator}, failing); !errors.Is(err, ErrGenerator) {
+		t.Errorf("expected generator error, got %v", err)
+	}
+	if err := ExpandGenerators(context.Background(), nil, []Problem{{Generator: &Generator{}}}); !errors.Is(err, ErrGenerator) {
+		t.Errorf("expected generator error without a judge, got %v", err)
+	}
+}
+
+func TestSizeLabel(t *testing.T) {
+	cases := map[int32]string{512: "512 B", 2048: "2.0 KB", 3 << 20: "3.0 MB"}
+	for size, expected := range cases {
+		if label := SizeLabel(size); label != expected {
+			t.Errorf("expected %s, got %s", expected, label)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package shared
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	EventParticipationStarted  = "participation.started"
+	EventSubmissionJudged      = "submission.judged"
+	EventParticipationFinished = "participation.finished"
+	EventOfferArchived         = "offer.archived"
+)
+
+// WebhookEvents are the events a company can subscribe to, in the order
+// they are shown
+var WebhookEvents = []string{
+	EventParticipationStarted,
+	EventSubmissionJudged,
+	EventParticipationFinished,
+	EventOfferArchived,
+}
+
+var WebhookEventLabels = map[string]string{
+	EventParticipationStarted:  "Un postulante inicia la evaluación",
+	EventSubmissionJudged:      "Un envío termina de calificarse",
+	EventParticipationFinished: "Un postulante termina la evaluación",
+	EventOfferArchived:         "Una oferta se archiva",
+}
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+var DeliveryStatusLabels = map[string]string{
+	DeliveryPending:   "Pendiente",
+	DeliveryDelivered: "Entregado",
+	DeliveryFailed:    "Fallido",
+}
+
+var (
+	ErrWebhookURL    = errors.New("la URL debe ser https y de hasta 2048 caracteres")
+	ErrWebhookEvents = errors.New("selecciona al menos un evento conocido")
+)
+
+func IsWebhookError(err error) bool {
+	return IsAny(err, ErrWebhookURL, ErrWebhookEvents)
+}
+
+// ValidateWebhookURL only accepts https, the payloads carry applicant data
+func ValidateWebhookURL(raw string) error {
+	if len(raw) > 2048 {
+		return ErrWebhookURL
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Scheme != "https" || parsed.Host == "" {
+		return ErrWebhookURL
+	}
+	return nil
+}
+
+// ParseWebhookEvents keeps the known events in the order of WebhookEvents
+func ParseWebhookEvents(values []string) ([]string, error) {
+	for _, value := range values {
+		if !slices.Contains(WebhookEvents, value) {
+			return nil, ErrWebhookEvents
+		}
+	}
+	events := []string{}
+	for _, event := range WebhookEvents {
+		if slices.Contains(values, event) {
+			events = append(events, event)
+		}
+	}
+	if len(events) == 0 {
+		return nil, ErrWebhookEvents
+	}
+	return events, nil
+}
+
+type Webhook struct {
+	ID        string
+	CompanyID string
+	URL       string
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+}
+
+func (w Webhook) Subscribed(event string) bool {
+	return slices.Contains(w.Events, event)
+}
+
+func (w Webhook) EventsText() string {
+	return strings.Join(w.Events, ", ")
+}
+
+type WebhookDelivery struct {
+	ID            string
+	WebhookID     string
+	URL           string
+	Secret        string
+	Event         string
+	Payload       string
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	ResponseCode  int
+	LastError     string
+	CreatedAt     time.Time
+	DeliveredAt   time.Time
+}
+
+func (d WebhookDelivery) StatusLabel() string {
+	return DeliveryStatusLabels[d.Status]
+}
+
+// WebhookEvent is emitted by the handlers, the storage finds the company
+// and the payload from the ids of the subject. A participation can be
+// given by its id or by the applicant and the quiz.
+type WebhookEvent struct {
+	Type            string
+	OfferID         string
+	ParticipationID string
+	UserID          string
+	QuizID          string
+	SubmissionID    string
+}
+
+// EmitWebhookEvent only logs a failure, the request that caused the event
+// goes on without it
+func EmitWebhookEvent(ctx context.Context, emitter WebhookEmitter, event WebhookEvent) {
+	if err := emitter.EmitWebhookEvent(ctx, event); err != nil {
+		log.Println("webhooks:", err)
+	}
+}
+
+// WebhookPayload is the signed body sent to the subscribed URL, ID is the
+// same in every delivery of the event so receivers can drop repeats
+type WebhookPayload struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      WebhookData `json:"data"`
+}
+
+type WebhookData struct {
+	Offer         WebhookOffer          `json:"offer"`
+	Participation *WebhookParticipation `json:"participation,omitempty"`
+	Applicant     *WebhookApplicant     `json:"applicant,omitempty"`
+	Submission    *WebhookSubmission    `json:"submission,omitempty"`
+}
+
+type WebhookOffer struct {
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Status string `json:"status"`
+}
+
+type WebhookParticipation struct {
+	ID         string     `json:"id"`
+	StartedAt  time.Time  `json:"started_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	EndReason  string     `json:"end_reason,omitempty"`
+}
+
+// WebhookApplicant only has the pseudonym while the offer is blind
+// reviewed and the applicant was not revealed
+type WebhookApplicant struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name"`
+	Email     string `json:"email,omitempty"`
+	Anonymous bool   `json:"anonymous"`
+}
+
+type WebhookSubmission struct {
+	ID                string `json:"id"`
+	ProblemID         string `json:"problem_id"`
+	ProblemTitle      string `json:"problem_title"`
+	AcceptedTestCases int    `json:"accepted_test_cases"`
+	TotalTestCases    int    `json:"total_test_cases"`
+}
@@ -0,0 +1,55 @@
+package shared
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	if err := ValidateWebhookURL("https://ats.example.com/spotted?key=1"); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+	invalid := []string{
+		"",
+		"http://ats.example.com/spotted",
+		"https://",
+		"ats.example.com/spotted",
+		"https://ats.example.com/" + strings.Repeat("a", 2048),
+	}
+	for _, raw := range invalid {
+		if err := ValidateWebhookURL(raw); err != ErrWebhookURL {
+			t.Errorf("%q: expected %v, got %v", raw, ErrWebhookURL, err)
+		}
+	}
+}
+
+func TestParseWebhookEvents(t *testing.T) {
+	events, err := ParseWebhookEvents([]string{EventOfferArchived, EventParticipationStarted, EventOfferArchived})
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	expected := []string{EventParticipationStarted, EventOfferArchived}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("expected %v, got %v", expected, events)
+	}
+	if _, err := ParseWebhookEvents(nil); err != ErrWebhookEvents {
+		t.Errorf("expected %v, got %v", ErrWebhookEvents, err)
+	}
+	if _, err := ParseWebhookEvents([]string{EventSubmissionJudged, "offer.deleted"}); err != ErrWebhookEvents {
+		t.Errorf("expected %v, got %v", ErrWebhookEvents, err)
+	}
+}
+
+func TestWebhookSubscribed(t *testing.T) {
+	webhook := Webhook{Events: []string{EventSubmissionJudged, EventParticipationFinished}}
+	if !webhook.Subscribed(EventSubmissionJudged) {
+		t.Error("expected a subscription to submission.judged")
+	}
+	if webhook.Subscribed(EventOfferArchived) {
+		t.Error("expected no subscription to offer.archived")
+	}
+	if webhook.EventsText() != "submission.judged, participation.finished" {
+		t.Errorf("unexpected text %q", webhook.EventsText())
+	}
+}
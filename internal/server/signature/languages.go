@@ -0,0 +1,818 @@
+package signature
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const stubComment = "Implementa la función, el evaluador lee la entrada y escribe el resultado."
+
+type language struct {
+	types map[Type]string
+	stub  func(s Signature, types map[Type]string) string
+	wrap  func(s Signature, types map[Type]string, src string) string
+}
+
+// the ids are the Judge0 languages offered in quizzes, the same ones that
+// have an example code
+var languages = map[int32]language{
+	60: {types: goTypes, stub: goStub, wrap: goWrap},
+	54: {types: cppTypes, stub: cppStub, wrap: cppWrap},
+	62: {types: javaTypes, stub: javaStub, wrap: javaWrap},
+	71: {types: pythonTypes, stub: pythonStub, wrap: pythonWrap},
+	63: {types: jsTypes, stub: jsStub, wrap: jsWrap},
+	73: {types: rustTypes, stub: rustStub, wrap: rustWrap},
+}
+
+// Stub is the starter code shown to the candidate
+func (s Signature) Stub(languageID int32) (string, error) {
+	l, ok := languages[languageID]
+	if !ok {
+		return "", ErrUnsupportedLanguage
+	}
+	return l.stub(s, l.types), nil
+}
+
+// Wrap returns a complete program, the candidate's source plus the code that
+// parses the test case input, calls the function and prints the result
+func (s Signature) Wrap(languageID int32, src string) (string, error) {
+	l, ok := languages[languageID]
+	if !ok {
+		return "", ErrUnsupportedLanguage
+	}
+	return l.wrap(s, l.types, src), nil
+}
+
+func typedParams(s Signature, types map[Type]string, format string) string {
+	params := make([]string, len(s.Params))
+	for i, p := range s.Params {
+		params[i] = fmt.Sprintf(format, p.Name, types[p.Type])
+	}
+	return strings.Join(params, ", ")
+}
+
+func args(s Signature, format string) string {
+	res := make([]string, len(s.Params))
+	for i := range s.Params {
+		res[i] = fmt.Sprintf(format, i)
+	}
+	return strings.Join(res, ", ")
+}
+
+var goTypes = map[Type]string{
+	Int: "int", Bool: "bool", String: "string",
+	IntArray: "[]int", BoolArray: "[]bool", StringArray: "[]string",
+}
+
+func goStub(s Signature, types map[Type]string) string {
+	return fmt.Sprintf("// %s\npackage main\n\nfunc %s(%s) %s {\n\n}\n",
+		stubComment, s.Name, typedParams(s, types, "%s %s"), types[s.Return])
+}
+
+var goPackage = regexp.MustCompile(`(?m)^package\s+\w+[^\n]*$`)
+
+const goImports = `
+import (
+	spottedjson "encoding/json"
+	spottedos "os"
+	spottedstrconv "strconv"
+)
+`
+
+const goHelpers = `
+func spottedRead(decoder *spottedjson.Decoder, v interface{}) {
+	if err := decoder.Decode(v); err != nil {
+		panic(err)
+	}
+}
+
+func spottedWrite(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case int:
+		return spottedstrconv.AppendInt(b, int64(v), 10)
+	case bool:
+		return spottedstrconv.AppendBool(b, v)
+	case string:
+		return spottedWriteString(b, v)
+	case []int:
+		b = append(b, '[')
+		for i, item := range v {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = spottedstrconv.AppendInt(b, int64(item), 10)
+		}
+		return append(b, ']')
+	case []bool:
+		b = append(b, '[')
+		for i, item := range v {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = spottedstrconv.AppendBool(b, item)
+		}
+		return append(b, ']')
+	case []string:
+		b = append(b, '[')
+		for i, item := range v {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			b = spottedWriteString(b, item)
+		}
+		return append(b, ']')
+	}
+	return b
+}
+
+func spottedWriteString(b []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	b = append(b, '"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b = append(b, '\\', '"')
+		case '\\':
+			b = append(b, '\\', '\\')
+		case '\b':
+			b = append(b, '\\', 'b')
+		case '\f':
+			b = append(b, '\\', 'f')
+		case '\n':
+			b = append(b, '\\', 'n')
+		case '\r':
+			b = append(b, '\\', 'r')
+		case '\t':
+			b = append(b, '\\', 't')
+		default:
+			if r < 0x20 {
+				b = append(b, '\\', 'u', '0', '0', hex[r>>4], hex[r&0xf])
+			} else {
+				b = append(b, string(r)...)
+			}
+		}
+	}
+	return append(b, '"')
+}
+`
+
+func goWrap(s Signature, types map[Type]string, src string) string {
+	var b strings.Builder
+	if loc := goPackage.FindStringIndex(src); loc != nil {
+		b.WriteString(src[:loc[1]])
+		b.WriteString("\n" + goImports)
+		b.WriteString(src[loc[1]:])
+	} else {
+		b.WriteString("package main\n" + goImports)
+		b.WriteString(src)
+	}
+	b.WriteString("\n\nfunc main() {\n")
+	b.WriteString("\tspottedDecoder := spottedjson.NewDecoder(spottedos.Stdin)\n")
+	for i, p := range s.Params {
+		fmt.Fprintf(&b, "\tvar spottedArg%d %s\n", i, types[p.Type])
+		fmt.Fprintf(&b, "\tspottedRead(spottedDecoder, &spottedArg%d)\n", i)
+	}
+	b.WriteString("\t_ = spottedDecoder\n")
+	fmt.Fprintf(&b, "\tspottedos.Stdout.Write(spottedWrite(nil, %s(%s)))\n}\n", s.Name, args(s, "spottedArg%d"))
+	b.WriteString(goHelpers)
+	return b.String()
+}
+
+var cppTypes = map[Type]string{
+	Int: "long long", Bool: "bool", String: "string",
+	IntArray: "vector<long long>", BoolArray: "vector<bool>", StringArray: "vector<string>",
+}
+
+// the harness doesn't rely on the candidate's using directives
+var cppQualified = map[Type]string{
+	Int: "long long", Bool: "bool", String: "std::string",
+	IntArray: "std::vector<long long>", BoolArray: "std::vector<bool>", StringArray: "std::vector<std::string>",
+}
+
+func cppStub(s Signature, types map[Type]string) string {
+	return fmt.Sprintf("// %s\n#include <string>\n#include <vector>\n\nusing namespace std;\n\n%s %s(%s) {\n\n}\n",
+		stubComment, types[s.Return], s.Name, typedParams(s, types, "%[2]s %[1]s"))
+}
+
+const cppIncludes = `#include <cctype>
+#include <iostream>
+#include <iterator>
+#include <stdexcept>
+#include <string>
+#include <vector>
+`
+
+const cppHelpers = `
+namespace spotted {
+struct Parser {
+    std::string s;
+    std::size_t i;
+    void skip() {
+        while (i < s.size() && std::isspace(static_cast<unsigned char>(s[i]))) i++;
+    }
+    bool peek(char c) {
+        skip();
+        return i < s.size() && s[i] == c;
+    }
+    void expect(char c) {
+        if (!peek(c)) throw std::runtime_error(std::string("expected ") + c);
+        i++;
+    }
+};
+
+inline void read(Parser& p, long long& v) {
+    p.skip();
+    std::size_t start = p.i;
+    if (p.i < p.s.size() && p.s[p.i] == '-') p.i++;
+    while (p.i < p.s.size() && std::isdigit(static_cast<unsigned char>(p.s[p.i]))) p.i++;
+    v = std::stoll(p.s.substr(start, p.i - start));
+}
+
+inline void read(Parser& p, bool& v) {
+    p.skip();
+    if (p.s.compare(p.i, 4, "true") == 0) {
+        v = true;
+        p.i += 4;
+    } else if (p.s.compare(p.i, 5, "false") == 0) {
+        v = false;
+        p.i += 5;
+    } else {
+        throw std::runtime_error("expected bool");
+    }
+}
+
+inline void read(Parser& p, std::string& v) {
+    p.expect('"');
+    v.clear();
+    while (p.i < p.s.size() && p.s[p.i] != '"') {
+        char c = p.s[p.i++];
+        if (c != '\\') {
+            v += c;
+            continue;
+        }
+        char e = p.s[p.i++];
+        switch (e) {
+        case 'b': v += '\b'; break;
+        case 'f': v += '\f'; break;
+        case 'n': v += '\n'; break;
+        case 'r': v += '\r'; break;
+        case 't': v += '\t'; break;
+        case 'u': {
+            unsigned long cp = std::stoul(p.s.substr(p.i, 4), nullptr, 16);
+            p.i += 4;
+            if (cp < 0x80) {
+                v += static_cast<char>(cp);
+            } else if (cp < 0x800) {
+                v += static_cast<char>(0xC0 | (cp >> 6));
+                v += static_cast<char>(0x80 | (cp & 0x3F));
+            } else {
+                v += static_cast<char>(0xE0 | (cp >> 12));
+                v += static_cast<char>(0x80 | ((cp >> 6) & 0x3F));
+                v += static_cast<char>(0x80 | (cp & 0x3F));
+            }
+            break;
+        }
+        default: v += e;
+        }
+    }
+    p.expect('"');
+}
+
+template <typename T>
+void read(Parser& p, std::vector<T>& v) {
+    p.expect('[');
+    v.clear();
+    if (p.peek(']')) {
+        p.i++;
+        return;
+    }
+    while (true) {
+        T item;
+        read(p, item);
+        v.push_back(item);
+        if (p.peek(',')) {
+            p.i++;
+            continue;
+        }
+        p.expect(']');
+        return;
+    }
+}
+
+inline void write(std::string& out, long long v) { out += std::to_string(v); }
+
+inline void write(std::string& out, bool v) { out += v ? "true" : "false"; }
+
+inline void write(std::string& out, const std::string& v) {
+    static const char hex[] = "0123456789abcdef";
+    out += '"';
+    for (unsigned char c : v) {
+        switch (c) {
+        case '"': out += "\\\""; break;
+        case '\\': out += "\\\\"; break;
+        case '\b': out += "\\b"; break;
+        case '\f': out += "\\f"; break;
+        case '\n': out += "\\n"; break;
+        case '\r': out += "\\r"; break;
+        case '\t': out += "\\t"; break;
+        default:
+            if (c < 0x20) {
+                out += "\\u00";
+                out += hex[c >> 4];
+                out += hex[c & 0xF];
+            } else {
+                out += static_cast<char>(c);
+            }
+        }
+    }
+    out += '"';
+}
+
+template <typename T>
+void write(std::string& out, const std::vector<T>& v) {
+    out += '[';
+    for (std::size_t i = 0; i < v.size(); i++) {
+        if (i > 0) out += ',';
+        write(out, static_cast<T>(v[i]));
+    }
+    out += ']';
+}
+}  // namespace spotted
+`
+
+func cppWrap(s Signature, types map[Type]string, src string) string {
+	var b strings.Builder
+	b.WriteString(cppIncludes)
+	b.WriteString(src)
+	b.WriteString("\n" + cppHelpers)
+	b.WriteString("\nint main() {\n")
+	b.WriteString("    spotted::Parser spottedParser{std::string(std::istreambuf_iterator<char>(std::cin), std::istreambuf_iterator<char>()), 0};\n")
+	for i, p := range s.Params {
+		fmt.Fprintf(&b, "    %s spottedArg%d;\n", cppQualified[p.Type], i)
+		fmt.Fprintf(&b, "    spotted::read(spottedParser, spottedArg%d);\n", i)
+	}
+	b.WriteString("    std::string spottedOut;\n")
+	fmt.Fprintf(&b, "    spotted::write(spottedOut, %s(%s));\n", s.Name, args(s, "spottedArg%d"))
+	b.WriteString("    std::cout << spottedOut;\n    return 0;\n}\n")
+	return b.String()
+}
+
+var javaTypes = map[Type]string{
+	Int: "long", Bool: "boolean", String: "String",
+	IntArray: "long[]", BoolArray: "boolean[]", StringArray: "String[]",
+}
+
+var javaReaders = map[Type]string{
+	Int: "readInt", Bool: "readBool", String: "readString",
+	IntArray: "readIntArray", BoolArray: "readBoolArray", StringArray: "readStringArray",
+}
+
+func javaStub(s Signature, types map[Type]string) string {
+	return fmt.Sprintf("// %s\nclass Solution {\n    public %s %s(%s) {\n\n    }\n}\n",
+		stubComment, types[s.Return], s.Name, typedParams(s, types, "%[2]s %[1]s"))
+}
+
+const javaHelpers = `
+    private static String in;
+    private static int pos;
+
+    private static void skip() {
+        while (pos < in.length() && Character.isWhitespace(in.charAt(pos))) pos++;
+    }
+
+    private static boolean peek(char c) {
+        skip();
+        return pos < in.length() && in.charAt(pos) == c;
+    }
+
+    private static void expect(char c) {
+        if (!peek(c)) throw new IllegalArgumentException("expected " + c);
+        pos++;
+    }
+
+    private static boolean next() {
+        if (peek(',')) {
+            pos++;
+            return true;
+        }
+        expect(']');
+        return false;
+    }
+
+    private static boolean empty() {
+        expect('[');
+        if (peek(']')) {
+            pos++;
+            return true;
+        }
+        return false;
+    }
+
+    private static long readInt() {
+        skip();
+        int start = pos;
+        if (peek('-')) pos++;
+        while (pos < in.length() && Character.isDigit(in.charAt(pos))) pos++;
+        return Long.parseLong(in.substring(start, pos));
+    }
+
+    private static boolean readBool() {
+        skip();
+        if (in.startsWith("true", pos)) {
+            pos += 4;
+            return true;
+        }
+        if (in.startsWith("false", pos)) {
+            pos += 5;
+            return false;
+        }
+        throw new IllegalArgumentException("expected bool");
+    }
+
+    private static String readString() {
+        expect('"');
+        StringBuilder sb = new StringBuilder();
+        while (in.charAt(pos) != '"') {
+            char c = in.charAt(pos++);
+            if (c != '\\') {
+                sb.append(c);
+                continue;
+            }
+            char e = in.charAt(pos++);
+            switch (e) {
+                case 'b': sb.append('\b'); break;
+                case 'f': sb.append('\f'); break;
+                case 'n': sb.append('\n'); break;
+                case 'r': sb.append('\r'); break;
+                case 't': sb.append('\t'); break;
+                case 'u':
+                    sb.append((char) Integer.parseInt(in.substring(pos, pos + 4), 16));
+                    pos += 4;
+                    break;
+                default: sb.append(e);
+            }
+        }
+        pos++;
+        return sb.toString();
+    }
+
+    private static long[] readIntArray() {
+        java.util.List<Long> items = new java.util.ArrayList<>();
+        if (!empty()) {
+            do {
+                items.add(readInt());
+            } while (next());
+        }
+        long[] res = new long[items.size()];
+        for (int i = 0; i < res.length; i++) res[i] = items.get(i);
+        return res;
+    }
+
+    private static boolean[] readBoolArray() {
+        java.util.List<Boolean> items = new java.util.ArrayList<>();
+        if (!empty()) {
+            do {
+                items.add(readBool());
+            } while (next());
+        }
+        boolean[] res = new boolean[items.size()];
+        for (int i = 0; i < res.length; i++) res[i] = items.get(i);
+        return res;
+    }
+
+    private static String[] readStringArray() {
+        java.util.List<String> items = new java.util.ArrayList<>();
+        if (!empty()) {
+            do {
+                items.add(readString());
+            } while (next());
+        }
+        return items.toArray(new String[0]);
+    }
+
+    private static void write(StringBuilder out, long v) {
+        out.append(v);
+    }
+
+    private static void write(StringBuilder out, boolean v) {
+        out.append(v ? "true" : "false");
+    }
+
+    private static void write(StringBuilder out, String v) {
+        out.append('"');
+        for (int i = 0; i < v.length(); i++) {
+            char c = v.charAt(i);
+            switch (c) {
+                case '"': out.append("\\\""); break;
+                case '\\': out.append("\\\\"); break;
+                case '\b': out.append("\\b"); break;
+                case '\f': out.append("\\f"); break;
+                case '\n': out.append("\\n"); break;
+                case '\r': out.append("\\r"); break;
+                case '\t': out.append("\\t"); break;
+                default:
+                    if (c < 0x20) out.append(String.format("\\u%04x", (int) c));
+                    else out.append(c);
+            }
+        }
+        out.append('"');
+    }
+
+    private static void write(StringBuilder out, long[] v) {
+        out.append('[');
+        for (int i = 0; i < v.length; i++) {
+            if (i > 0) out.append(',');
+            write(out, v[i]);
+        }
+        out.append(']');
+    }
+
+    private static void write(StringBuilder out, boolean[] v) {
+        out.append('[');
+        for (int i = 0; i < v.length; i++) {
+            if (i > 0) out.append(',');
+            write(out, v[i]);
+        }
+        out.append(']');
+    }
+
+    private static void write(StringBuilder out, String[] v) {
+        out.append('[');
+        for (int i = 0; i < v.length; i++) {
+            if (i > 0) out.append(',');
+            write(out, v[i]);
+        }
+        out.append(']');
+    }
+}
+`
+
+func javaWrap(s Signature, types map[Type]string, src string) string {
+	var b strings.Builder
+	b.WriteString(src)
+	b.WriteString("\n\npublic class Main {\n")
+	b.WriteString("    public static void main(String[] args) throws Exception {\n")
+	b.WriteString("        in = new String(System.in.readAllBytes(), java.nio.charset.StandardCharsets.UTF_8);\n")
+	for i, p := range s.Params {
+		fmt.Fprintf(&b, "        %s arg%d = %s();\n", types[p.Type], i, javaReaders[p.Type])
+	}
+	b.WriteString("        StringBuilder out = new StringBuilder();\n")
+	fmt.Fprintf(&b, "        write(out, new Solution().%s(%s));\n", s.Name, args(s, "arg%d"))
+	b.WriteString("        byte[] bytes = out.toString().getBytes(java.nio.charset.StandardCharsets.UTF_8);\n")
+	b.WriteString("        System.out.write(bytes, 0, bytes.length);\n")
+	b.WriteString("        System.out.flush();\n    }\n")
+	b.WriteString(javaHelpers)
+	return b.String()
+}
+
+var pythonTypes = map[Type]string{
+	Int: "int", Bool: "bool", String: "str",
+	IntArray: "List[int]", BoolArray: "List[bool]", StringArray: "List[str]",
+}
+
+func pythonStub(s Signature, types map[Type]string) string {
+	return fmt.Sprintf("# %s\nfrom typing import List\n\n\ndef %s(%s) -> %s:\n    pass\n",
+		stubComment, s.Name, typedParams(s, types, "%s: %s"), types[s.Return])
+}
+
+const pythonHarness = `
+
+def _spotted_main():
+    import json
+    import sys
+
+    lines = [line for line in sys.stdin.buffer.read().decode("utf-8").split("\n") if line.strip()]
+    result = %s(*[json.loads(line) for line in lines])
+    sys.stdout.buffer.write(json.dumps(result, ensure_ascii=False, separators=(",", ":")).encode("utf-8"))
+
+
+_spotted_main()
+`
+
+func pythonWrap(s Signature, types map[Type]string, src string) string {
+	return src + "\n" + fmt.Sprintf(pythonHarness, s.Name)
+}
+
+var jsTypes = map[Type]string{
+	Int: "number", Bool: "boolean", String: "string",
+	IntArray: "number[]", BoolArray: "boolean[]", StringArray: "string[]",
+}
+
+func jsStub(s Signature, types map[Type]string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "// %s\n/**\n", stubComment)
+	for _, p := range s.Params {
+		fmt.Fprintf(&b, " * @param {%s} %s\n", types[p.Type], p.Name)
+	}
+	fmt.Fprintf(&b, " * @return {%s}\n */\n", types[s.Return])
+	names := make([]string, len(s.Params))
+	for i, p := range s.Params {
+		names[i] = p.Name
+	}
+	fmt.Fprintf(&b, "function %s(%s) {\n\n}\n", s.Name, strings.Join(names, ", "))
+	return b.String()
+}
+
+const jsHarness = `
+(function () {
+  const lines = require("fs").readFileSync(0, "utf8").split("\n").filter((line) => line.trim() !== "");
+  const result = %s(...lines.map((line) => JSON.parse(line)));
+  process.stdout.write(JSON.stringify(result));
+})();
+`
+
+func jsWrap(s Signature, types map[Type]string, src string) string {
+	return src + "\n" + fmt.Sprintf(jsHarness, s.Name)
+}
+
+var rustTypes = map[Type]string{
+	Int: "i64", Bool: "bool", String: "String",
+	IntArray: "Vec<i64>", BoolArray: "Vec<bool>", StringArray: "Vec<String>",
+}
+
+func rustStub(s Signature, types map[Type]string) string {
+	return fmt.Sprintf("// %s\nfn %s(%s) -> %s {\n\n}\n",
+		stubComment, s.Name, typedParams(s, types, "%s: %s"), types[s.Return])
+}
+
+const rustHelpers = `
+mod spotted {
+    pub struct Parser {
+        chars: Vec<char>,
+        pos: usize,
+    }
+
+    impl Parser {
+        pub fn new(input: String) -> Parser {
+            Parser { chars: input.chars().collect(), pos: 0 }
+        }
+
+        fn skip(&mut self) {
+            while self.pos < self.chars.len() && self.chars[self.pos].is_whitespace() {
+                self.pos += 1;
+            }
+        }
+
+        fn peek(&mut self, c: char) -> bool {
+            self.skip();
+            self.pos < self.chars.len() && self.chars[self.pos] == c
+        }
+
+        fn expect(&mut self, c: char) {
+            if !self.peek(c) {
+                panic!("expected {}", c);
+            }
+            self.pos += 1;
+        }
+
+        fn next_char(&mut self) -> char {
+            let c = self.chars[self.pos];
+            self.pos += 1;
+            c
+        }
+    }
+
+    pub trait Json: Sized {
+        fn read(p: &mut Parser) -> Self;
+        fn write(&self, out: &mut String);
+    }
+
+    impl Json for i64 {
+        fn read(p: &mut Parser) -> i64 {
+            p.skip();
+            let start = p.pos;
+            if p.peek('-') {
+                p.pos += 1;
+            }
+            while p.pos < p.chars.len() && p.chars[p.pos].is_ascii_digit() {
+                p.pos += 1;
+            }
+            let digits: String = p.chars[start..p.pos].iter().collect();
+            digits.parse().unwrap()
+        }
+
+        fn write(&self, out: &mut String) {
+            out.push_str(&self.to_string());
+        }
+    }
+
+    impl Json for bool {
+        fn read(p: &mut Parser) -> bool {
+            p.skip();
+            let rest: String = p.chars[p.pos..].iter().take(5).collect();
+            if rest.starts_with("true") {
+                p.pos += 4;
+                true
+            } else if rest.starts_with("false") {
+                p.pos += 5;
+                false
+            } else {
+                panic!("expected bool")
+            }
+        }
+
+        fn write(&self, out: &mut String) {
+            out.push_str(if *self { "true" } else { "false" });
+        }
+    }
+
+    impl Json for String {
+        fn read(p: &mut Parser) -> String {
+            p.expect('"');
+            let mut s = String::new();
+            loop {
+                let c = p.next_char();
+                match c {
+                    '"' => break,
+                    '\\' => match p.next_char() {
+                        'b' => s.push('\u{8}'),
+                        'f' => s.push('\u{c}'),
+                        'n' => s.push('\n'),
+                        'r' => s.push('\r'),
+                        't' => s.push('\t'),
+                        'u' => {
+                            let hex: String = p.chars[p.pos..p.pos + 4].iter().collect();
+                            p.pos += 4;
+                            let code = u32::from_str_radix(&hex, 16).unwrap();
+                            s.push(std::char::from_u32(code).unwrap_or('\u{fffd}'));
+                        }
+                        other => s.push(other),
+                    },
+                    _ => s.push(c),
+                }
+            }
+            s
+        }
+
+        fn write(&self, out: &mut String) {
+            out.push('"');
+            for c in self.chars() {
+                match c {
+                    '"' => out.push_str("\\\""),
+                    '\\' => out.push_str("\\\\"),
+                    '\u{8}' => out.push_str("\\b"),
+                    '\u{c}' => out.push_str("\\f"),
+                    '\n' => out.push_str("\\n"),
+                    '\r' => out.push_str("\\r"),
+                    '\t' => out.push_str("\\t"),
+                    c if (c as u32) < 0x20 => out.push_str(&format!("\\u{:04x}", c as u32)),
+                    c => out.push(c),
+                }
+            }
+            out.push('"');
+        }
+    }
+
+    impl<T: Json> Json for Vec<T> {
+        fn read(p: &mut Parser) -> Vec<T> {
+            p.expect('[');
+            let mut v = Vec::new();
+            if p.peek(']') {
+                p.pos += 1;
+                return v;
+            }
+            loop {
+                v.push(T::read(p));
+                if p.peek(',') {
+                    p.pos += 1;
+                    continue;
+                }
+                p.expect(']');
+                return v;
+            }
+        }
+
+        fn write(&self, out: &mut String) {
+            out.push('[');
+            for (i, item) in self.iter().enumerate() {
+                if i > 0 {
+                    out.push(',');
+                }
+                item.write(out);
+            }
+            out.push(']');
+        }
+    }
+}
+`
+
+func rustWrap(s Signature, types map[Type]string, src string) string {
+	var b strings.Builder
+	// the declared name is kept in every language, even when it isn't
+	// snake case
+	b.WriteString("#![allow(non_snake_case)]\n")
+	b.WriteString(src)
+	b.WriteString("\n" + rustHelpers)
+	b.WriteString("\nfn main() {\n    use std::io::Read;\n")
+	b.WriteString("    let mut spotted_input = String::new();\n")
+	b.WriteString("    std::io::stdin().read_to_string(&mut spotted_input).unwrap();\n")
+	b.WriteString("    let mut spotted_parser = spotted::Parser::new(spotted_input);\n")
+	for i, p := range s.Params {
+		fmt.Fprintf(&b, "    let spotted_arg%d: %s = spotted::Json::read(&mut spotted_parser);\n", i, types[p.Type])
+	}
+	b.WriteString("    let mut spotted_out = String::new();\n")
+	fmt.Fprintf(&b, "    spotted::Json::write(&%s(%s), &mut spotted_out);\n", s.Name, args(s, "spotted_arg%d"))
+	b.WriteString("    print!(\"{}\", spotted_out);\n}\n")
+	return b.String()
+}
@@ -0,0 +1,290 @@
+package signature
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Type is a value accepted as parameter or returned by a function-signature
+// problem, test cases encode every value as JSON
+type Type string
+
+const (
+	Int         Type = "int"
+	Bool        Type = "bool"
+	String      Type = "string"
+	IntArray    Type = "int[]"
+	BoolArray   Type = "bool[]"
+	StringArray Type = "string[]"
+)
+
+const (
+	MaxLength = 255
+	maxParams = 8
+)
+
+var (
+	types = map[Type]bool{
+		Int: true, Bool: true, String: true,
+		IntArray: true, BoolArray: true, StringArray: true,
+	}
+	identifier = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+	declRegex  = regexp.MustCompile(`^\s*([^\s(]+)\s*\((.*)\)\s*(\S+)\s*$`)
+	// names that can't be used as identifiers in one of the languages the
+	// harness is generated for
+	reserved = map[string]bool{
+		"and": true, "as": true, "async": true, "await": true, "bool": true,
+		"boolean": true, "break": true, "case": true, "catch": true, "char": true,
+		"class": true, "const": true, "continue": true, "def": true, "default": true,
+		"del": true, "delete": true, "do": true, "double": true, "elif": true,
+		"else": true, "enum": true, "except": true, "export": true, "extends": true,
+		"false": true, "final": true, "finally": true, "float": true, "fn": true,
+		"for": true, "from": true, "func": true, "function": true, "global": true,
+		"go": true, "goto": true, "if": true, "impl": true, "import": true,
+		"in": true, "int": true, "interface": true, "is": true, "lambda": true,
+		"let": true, "long": true, "loop": true, "main": true, "map": true,
+		"match": true, "mod": true, "move": true, "mut": true, "new": true,
+		"None": true, "nonlocal": true, "not": true, "null": true, "or": true,
+		"package": true, "pass": true, "print": true, "private": true, "protected": true,
+		"pub": true, "public": true, "raise": true, "range": true, "ref": true,
+		"return": true, "select": true, "self": true, "Self": true, "short": true,
+		"static": true, "string": true, "String": true, "struct": true, "super": true,
+		"switch": true, "this": true, "throw": true, "throws": true, "trait": true,
+		"True": true, "False": true, "true": true, "try": true, "type": true,
+		"typeof": true, "unsafe": true, "use": true, "using": true, "var": true,
+		"vector": true, "void": true, "where": true, "while": true, "with": true,
+		"yield": true, "std": true, "Solution": true,
+	}
+)
+
+var ErrUnsupportedLanguage = errors.New("el lenguaje no soporta problemas con firma de función")
+
+type Param struct {
+	Name string
+	Type Type
+}
+
+// Signature is the function the candidate implements instead of reading
+// stdin, the harness of each language reads one JSON value per parameter
+// line and prints the returned value as JSON
+type Signature struct {
+	Name   string
+	Params []Param
+	Return Type
+}
+
+// Parse reads a declaration like "twoSum(nums int[], target int) int[]"
+func Parse(decl string) (Signature, error) {
+	if len(decl) > MaxLength {
+		return Signature{}, fmt.Errorf("la firma no puede tener más de %d caracteres", MaxLength)
+	}
+	match := declRegex.FindStringSubmatch(decl)
+	if match == nil {
+		return Signature{}, fmt.Errorf("la firma debe tener la forma nombre(parametro tipo, ...) tipo")
+	}
+	if err := validName(match[1]); err != nil {
+		return Signature{}, err
+	}
+	res := Signature{Name: match[1], Params: []Param{}}
+	seen := map[string]bool{match[1]: true}
+	if strings.TrimSpace(match[2]) != "" {
+		for _, field := range strings.Split(match[2], ",") {
+			parts := strings.Fields(field)
+			if len(parts) != 2 {
+				return Signature{}, fmt.Errorf("parámetro inválido: '%s'", strings.TrimSpace(field))
+			}
+			if err := validName(parts[0]); err != nil {
+				return Signature{}, err
+			}
+			if seen[parts[0]] {
+				return Signature{}, fmt.Errorf("el nombre '%s' está repetido", parts[0])
+			}
+			seen[parts[0]] = true
+			t := Type(parts[1])
+			if !types[t] {
+				return Signature{}, unknownType(parts[1])
+			}
+			res.Params = append(res.Params, Param{Name: parts[0], Type: t})
+		}
+	}
+	if len(res.Params) > maxParams {
+		return Signature{}, fmt.Errorf("la función puede tener como máximo %d parámetros", maxParams)
+	}
+	res.Return = Type(match[3])
+	if !types[res.Return] {
+		return Signature{}, unknownType(match[3])
+	}
+	return res, nil
+}
+
+func validName(name string) error {
+	if !identifier.MatchString(name) || len(name) > 64 {
+		return fmt.Errorf("'%s' no es un nombre válido", name)
+	}
+	if reserved[name] {
+		return fmt.Errorf("'%s' es una palabra reservada", name)
+	}
+	return nil
+}
+
+func unknownType(t string) error {
+	return fmt.Errorf("tipo desconocido '%s', los tipos válidos son int, bool, string, int[], bool[] y string[]", t)
+}
+
+func (s Signature) String() string {
+	params := make([]string, len(s.Params))
+	for i, p := range s.Params {
+		params[i] = p.Name + " " + string(p.Type)
+	}
+	return fmt.Sprintf("%s(%s) %s", s.Name, strings.Join(params, ", "), s.Return)
+}
+
+// CheckInput verifies that a test case input has one JSON value per
+// parameter line and normalizes every line
+func (s Signature) CheckInput(input string) (string, error) {
+	lines := valueLines(input)
+	if len(lines) != len(s.Params) {
+		return "", fmt.Errorf("la entrada debe tener %d líneas, una por parámetro", len(s.Params))
+	}
+	for i, line := range lines {
+		normalized, err := Normalize(s.Params[i].Type, line)
+		if err != nil {
+			return "", fmt.Errorf("parámetro '%s': %w", s.Params[i].Name, err)
+		}
+		lines[i] = normalized
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// CheckOutput normalizes the expected output so it matches exactly what the
+// harness prints
+func (s Signature) CheckOutput(output string) (string, error) {
+	normalized, err := Normalize(s.Return, strings.TrimSpace(output))
+	if err != nil {
+		return "", fmt.Errorf("salida: %w", err)
+	}
+	return normalized, nil
+}
+
+func valueLines(input string) []string {
+	res := []string{}
+	for _, line := range strings.Split(input, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			res = append(res, line)
+		}
+	}
+	return res
+}
+
+// Normalize decodes a JSON value of the type and encodes it again in the
+// compact form printed by every harness
+func Normalize(t Type, raw string) (string, error) {
+	if strings.TrimSpace(raw) == "null" {
+		return "", fmt.Errorf("se esperaba un valor de tipo %s", t)
+	}
+	var value interface{}
+	switch t {
+	case Int:
+		value = new(int64)
+	case Bool:
+		value = new(bool)
+	case String:
+		value = new(string)
+	case IntArray:
+		value = new([]int64)
+	case BoolArray:
+		value = new([]bool)
+	case StringArray:
+		value = new([]string)
+	default:
+		return "", unknownType(string(t))
+	}
+	if err := json.Unmarshal([]byte(raw), value); err != nil {
+		return "", fmt.Errorf("se esperaba un valor de tipo %s", t)
+	}
+	var b strings.Builder
+	switch v := value.(type) {
+	case *int64:
+		b.WriteString(strconv.FormatInt(*v, 10))
+	case *bool:
+		b.WriteString(strconv.FormatBool(*v))
+	case *string:
+		writeString(&b, *v)
+	case *[]int64:
+		b.WriteByte('[')
+		for i, item := range *v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.FormatInt(item, 10))
+		}
+		b.WriteByte(']')
+	case *[]bool:
+		b.WriteByte('[')
+		for i, item := range *v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(strconv.FormatBool(item))
+		}
+		b.WriteByte(']')
+	case *[]string:
+		b.WriteByte('[')
+		for i, item := range *v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeString(&b, item)
+		}
+		b.WriteByte(']')
+	}
+	return b.String(), nil
+}
+
+// writeString escapes like the harnesses do, quotes, backslashes and
+// control characters only, the rest of the text is kept as UTF-8
+func writeString(b *strings.Builder, s string) {
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(b, `\u%04x`, r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	b.WriteByte('"')
+}
+
+// Prepare wraps the candidate's source with the harness of the language,
+// sources of stdin/stdout problems are returned unchanged
+func Prepare(decl string, languageID int32, src string) (string, error) {
+	if decl == "" {
+		return src, nil
+	}
+	sig, err := Parse(decl)
+	if err != nil {
+		return "", err
+	}
+	return sig.Wrap(languageID, src)
+}
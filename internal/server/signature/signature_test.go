@@ -0,0 +1,172 @@
+package signature
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	sig, err := Parse("twoSum(nums int[], target int) int[]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sig.Name != "twoSum" || len(sig.Params) != 2 || sig.Return != IntArray {
+		t.Errorf("unexpected signature %+v", sig)
+	}
+	if sig.Params[1] != (Param{Name: "target", Type: Int}) {
+		t.Errorf("unexpected param %+v", sig.Params[1])
+	}
+	if got := sig.String(); got != "twoSum(nums int[], target int) int[]" {
+		t.Errorf("unexpected string %q", got)
+	}
+	sig, err = Parse("answer() int")
+	if err != nil || len(sig.Params) != 0 {
+		t.Errorf("expected a function without parameters, got %+v, %v", sig, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	decls := []string{
+		"",
+		"twoSum nums int[]",
+		"twoSum(nums int[], target int)",
+		"twoSum(nums float, target int) int",
+		"twoSum(nums int[], nums int) int",
+		"twoSum(twoSum int) int",
+		"main(a int) int",
+		"f(class int) int",
+		"f(a-b int) int",
+		"f(a int,) int",
+		"f(a b c) int",
+		"f(a int) " + strings.Repeat("x", MaxLength),
+	}
+	for _, decl := range decls {
+		if _, err := Parse(decl); err == nil {
+			t.Errorf("expected error for %q", decl)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	cases := []struct {
+		t    Type
+		raw  string
+		want string
+	}{
+		{Int, " -42 ", "-42"},
+		{Bool, "true", "true"},
+		{String, `"añ\"\n\u0001"`, `"añ\"\n\u0001"`},
+		{IntArray, "[1, 2,\t3]", "[1,2,3]"},
+		{IntArray, "[]", "[]"},
+		{BoolArray, "[false, true]", "[false,true]"},
+		{StringArray, `["x", "<y>"]`, `["x","<y>"]`},
+	}
+	for _, c := range cases {
+		got, err := Normalize(c.t, c.raw)
+		if err != nil {
+			t.Errorf("%s %s: unexpected error %v", c.t, c.raw, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s %s: expected %s, got %s", c.t, c.raw, c.want, got)
+		}
+	}
+	invalid := []struct {
+		t   Type
+		raw string
+	}{
+		{Int, "1.5"},
+		{Int, "null"},
+		{Int, "99999999999999999999"},
+		{Bool, "1"},
+		{String, "abc"},
+		{IntArray, "[1, \"2\"]"},
+		{StringArray, "null"},
+	}
+	for _, c := range invalid {
+		if _, err := Normalize(c.t, c.raw); err == nil {
+			t.Errorf("%s %s: expected error", c.t, c.raw)
+		}
+	}
+}
+
+func TestCheckInput(t *testing.T) {
+	sig, _ := Parse("f(words string[], n int) bool")
+	input, err := sig.CheckInput("[\"a\", \"b\"]\r\n\n 3 \n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if input != "[\"a\",\"b\"]\n3" {
+		t.Errorf("unexpected input %q", input)
+	}
+	if _, err := sig.CheckInput("[\"a\"]"); err == nil {
+		t.Error("expected error for a missing line")
+	}
+	if _, err := sig.CheckInput("3\n[\"a\"]"); err == nil {
+		t.Error("expected error for swapped parameters")
+	}
+	if out, err := sig.CheckOutput(" false\n"); err != nil || out != "false" {
+		t.Errorf("unexpected output %q, %v", out, err)
+	}
+}
+
+func TestStubAndWrap(t *testing.T) {
+	sig, _ := Parse("twoSum(nums int[], target int) int[]")
+	stubs := map[int32]string{
+		60: "func twoSum(nums []int, target int) []int {",
+		54: "vector<long long> twoSum(vector<long long> nums, long long target) {",
+		62: "public long[] twoSum(long[] nums, long target) {",
+		71: "def twoSum(nums: List[int], target: int) -> List[int]:",
+		63: "function twoSum(nums, target) {",
+		73: "fn twoSum(nums: Vec<i64>, target: i64) -> Vec<i64> {",
+	}
+	for id, want := range stubs {
+		stub, err := sig.Stub(id)
+		if err != nil {
+			t.Fatalf("language %d: %v", id, err)
+		}
+		if !strings.Contains(stub, want) {
+			t.Errorf("language %d: expected %q in stub\n%s", id, want, stub)
+		}
+		src, err := sig.Wrap(id, stub)
+		if err != nil {
+			t.Fatalf("language %d: %v", id, err)
+		}
+		if !strings.Contains(src, want) || !strings.Contains(src, "twoSum(") || len(src) <= len(stub) {
+			t.Errorf("language %d: expected the candidate's code inside the harness", id)
+		}
+	}
+	if _, err := sig.Stub(0); err != ErrUnsupportedLanguage {
+		t.Errorf("expected unsupported language, got %v", err)
+	}
+}
+
+func TestWrapGoImports(t *testing.T) {
+	sig, _ := Parse("f(a int) int")
+	src := "// comment\npackage main\n\nimport \"sort\"\n\nfunc f(a int) int { return a }\n"
+	wrapped, _ := sig.Wrap(60, src)
+	pkg := strings.Index(wrapped, "package main")
+	harness := strings.Index(wrapped, "spottedjson \"encoding/json\"")
+	candidate := strings.Index(wrapped, "import \"sort\"")
+	if !(pkg < harness && harness < candidate) {
+		t.Errorf("expected the harness imports right after the package clause\n%s", wrapped)
+	}
+	wrapped, _ = sig.Wrap(60, "func f(a int) int { return a }")
+	if !strings.HasPrefix(wrapped, "package main\n") {
+		t.Errorf("expected a package clause\n%s", wrapped)
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	src, err := Prepare("", 0, "print(input())")
+	if err != nil || src != "print(input())" {
+		t.Errorf("expected stdin problems unchanged, got %q, %v", src, err)
+	}
+	src, err = Prepare("f(a int) int", 71, "def f(a):\n    return a\n")
+	if err != nil || !strings.Contains(src, "_spotted_main()") {
+		t.Errorf("expected the harness, got %q, %v", src, err)
+	}
+	if _, err := Prepare("f(a int) int", 0, ""); err == nil {
+		t.Error("expected error for an unsupported language")
+	}
+}
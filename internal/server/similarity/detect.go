@@ -0,0 +1,122 @@
+package similarity
+
+import (
+	"sort"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const (
+	kGram  = 5
+	window = 4
+	// pairs below this Dice coefficient are not reported
+	SuspiciousScore = 0.6
+	// very short programs share most of their fingerprints by accident
+	minFingerprints = 5
+)
+
+type fingerprinted struct {
+	entry        shared.SimilarityEntry
+	fingerprints []Fingerprint
+}
+
+func fingerprintAll(entries []shared.SimilarityEntry) []fingerprinted {
+	res := make([]fingerprinted, 0, len(entries))
+	for _, entry := range entries {
+		tokens := Tokenize(entry.Submission.Src, entry.Submission.LanguageID)
+		fingerprints := Fingerprints(tokens, kGram, window)
+		if len(fingerprints) < minFingerprints {
+			continue
+		}
+		res = append(res, fingerprinted{entry: entry, fingerprints: fingerprints})
+	}
+	return res
+}
+
+// Entries flattens the best submission of every applicant per problem,
+// answers to questions have no submission and are left out
+func Entries(applications []shared.Application) []shared.SimilarityEntry {
+	res := []shared.SimilarityEntry{}
+	for _, application := range applications {
+		for _, summary := range application.Summary {
+			if summary.Question != nil {
+				continue
+			}
+			res = append(res, shared.SimilarityEntry{
+				Applicant:    application.Applicant,
+				ProblemTitle: summary.Title,
+				Submission:   summary.Submission,
+			})
+		}
+	}
+	return res
+}
+
+func comparable(a, b shared.SimilarityEntry) bool {
+	return a.Applicant.ID != b.Applicant.ID &&
+		a.Submission.ProblemID == b.Submission.ProblemID &&
+		a.Submission.LanguageID == b.Submission.LanguageID
+}
+
+// Detect compares every pair of submissions for the same problem and
+// language within the quiz, and every current submission against the prior
+// ones. Suspicious pairs are returned sorted by score.
+func Detect(current, prior []shared.SimilarityEntry) []shared.SimilarityPair {
+	currentFps := fingerprintAll(current)
+	priorFps := fingerprintAll(prior)
+	res := []shared.SimilarityPair{}
+	for i := range currentFps {
+		for j := i + 1; j < len(currentFps); j++ {
+			if pair, ok := comparePair(currentFps[i], currentFps[j]); ok {
+				res = append(res, pair)
+			}
+		}
+		for _, p := range priorFps {
+			if pair, ok := comparePair(currentFps[i], p); ok {
+				pair.Prior = true
+				res = append(res, pair)
+			}
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Score > res[j].Score
+	})
+	return res
+}
+
+func comparePair(a, b fingerprinted) (shared.SimilarityPair, bool) {
+	if !comparable(a.entry, b.entry) {
+		return shared.SimilarityPair{}, false
+	}
+	score, regionsA, regionsB := Compare(a.fingerprints, b.fingerprints)
+	if score < SuspiciousScore {
+		return shared.SimilarityPair{}, false
+	}
+	return shared.SimilarityPair{
+		ProblemTitle:   a.entry.ProblemTitle,
+		Language:       a.entry.Submission.Language,
+		Score:          int(score*100 + 0.5),
+		First:          a.entry,
+		Second:         b.entry,
+		FirstSegments:  Segments(a.entry.Submission.Src, regionsA),
+		SecondSegments: Segments(b.entry.Submission.Src, regionsB),
+	}, true
+}
+
+// Segments splits the source in alternating plain and matched parts so the
+// template can highlight them without building HTML by hand
+func Segments(src string, regions []Region) []shared.Segment {
+	res := []shared.Segment{}
+	pos := 0
+	for _, r := range regions {
+		if r.Start > pos {
+			res = append(res, shared.Segment{Text: src[pos:r.Start]})
+		}
+		res = append(res, shared.Segment{Text: src[r.Start:r.End], Match: true})
+		pos = r.End
+	}
+	if pos < len(src) {
+		res = append(res, shared.Segment{Text: src[pos:]})
+	}
+	return res
+}
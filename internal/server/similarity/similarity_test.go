@@ -0,0 +1,194 @@
+package similarity
+
+import (
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const goSrc = `package main
+
+import "fmt"
+
+// sums the numbers from the input
+func main() {
+	var n int
+	fmt.Scan(&n)
+	total := 0
+	for i := 0; i < n; i++ {
+		var x int
+		fmt.Scan(&x)
+		total += x
+	}
+	fmt.Println(total)
+}
+`
+
+// same program with renamed variables, other comments and spacing
+const renamedGoSrc = `package main
+import "fmt"
+func main() {
+	/* read amount */
+	var count int
+	fmt.Scan(&count)
+	acc := 0
+	for j := 0; j < count; j++ {
+		var value int
+		fmt.Scan(&value)
+		acc += value
+	}
+	fmt.Println(acc)
+}
+`
+
+const otherGoSrc = `package main
+
+import "fmt"
+
+func main() {
+	var s string
+	fmt.Scan(&s)
+	runes := []rune(s)
+	for l, r := 0, len(runes)-1; l < r; l, r = l+1, r-1 {
+		runes[l], runes[r] = runes[r], runes[l]
+	}
+	if string(runes) == s {
+		fmt.Println("YES")
+	} else {
+		fmt.Println("NO")
+	}
+}
+`
+
+func TestTokenizeIgnoresNamesAndComments(t *testing.T) {
+	a := Tokenize("x := 10 // comment", 60)
+	b := Tokenize("total := 99", 60)
+	if len(a) != len(b) {
+		t.Fatalf("expected %d tokens, got %d", len(b), len(a))
+	}
+	for i := range a {
+		if a[i].Value != b[i].Value {
+			t.Errorf("token %d: expected %s, got %s", i, b[i].Value, a[i].Value)
+		}
+	}
+}
+
+func TestTokenizeHashComments(t *testing.T) {
+	tokens := Tokenize("x = 'a#b' # comment\nprint(x)", 71)
+	expected := []string{IdentToken, "=", StringToken, IdentToken, "(", IdentToken, ")"}
+	if len(tokens) != len(expected) {
+		t.Fatalf("expected %d tokens, got %d", len(expected), len(tokens))
+	}
+	for i, v := range expected {
+		if tokens[i].Value != v {
+			t.Errorf("token %d: expected %s, got %s", i, v, tokens[i].Value)
+		}
+	}
+}
+
+func TestTokenizeOffsets(t *testing.T) {
+	src := `s = "hi"`
+	tokens := Tokenize(src, 71)
+	last := tokens[len(tokens)-1]
+	if src[last.Start:last.End] != `"hi"` {
+		t.Errorf("unexpected offsets %d-%d", last.Start, last.End)
+	}
+}
+
+func TestFingerprintsShortInput(t *testing.T) {
+	if len(Fingerprints(Tokenize("a b", 60), 5, 4)) != 0 {
+		t.Error("expected no fingerprints")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	a := Fingerprints(Tokenize(goSrc, 60), kGram, window)
+	b := Fingerprints(Tokenize(renamedGoSrc, 60), kGram, window)
+	c := Fingerprints(Tokenize(otherGoSrc, 60), kGram, window)
+	score, regionsA, regionsB := Compare(a, b)
+	if score < 0.99 {
+		t.Errorf("expected renamed copy to match, got %f", score)
+	}
+	if len(regionsA) == 0 || len(regionsB) == 0 {
+		t.Error("expected matched regions")
+	}
+	other, _, _ := Compare(a, c)
+	if other >= SuspiciousScore {
+		t.Errorf("expected different programs to score below %f, got %f", SuspiciousScore, other)
+	}
+}
+
+func TestMergeRegions(t *testing.T) {
+	merged := MergeRegions([]Region{{10, 20}, {0, 5}, {15, 30}, {30, 35}})
+	expected := []Region{{0, 5}, {10, 35}}
+	if len(merged) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, merged)
+	}
+	for i := range expected {
+		if merged[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, merged)
+		}
+	}
+}
+
+func TestSegments(t *testing.T) {
+	segments := Segments("abcdef", []Region{{1, 3}})
+	expected := []shared.Segment{{Text: "a"}, {Text: "bc", Match: true}, {Text: "def"}}
+	if len(segments) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, segments)
+	}
+	for i := range expected {
+		if segments[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, segments)
+		}
+	}
+}
+
+func entry(userID, problemID string, languageID int32, src string) shared.SimilarityEntry {
+	return shared.SimilarityEntry{
+		Applicant: shared.User{ID: userID},
+		Submission: shared.Submission{
+			Src:        src,
+			ProblemID:  problemID,
+			LanguageID: languageID,
+		},
+	}
+}
+
+func TestDetect(t *testing.T) {
+	current := []shared.SimilarityEntry{
+		entry("u1", "p1", 60, goSrc),
+		entry("u2", "p1", 60, renamedGoSrc),
+		entry("u3", "p1", 60, otherGoSrc),
+		// other language is never compared
+		entry("u4", "p1", 71, goSrc),
+		// other problem is never compared
+		entry("u5", "p2", 60, goSrc),
+	}
+	pairs := Detect(current, nil)
+	if len(pairs) != 1 {
+		t.Fatalf("expected 1 pair, got %d", len(pairs))
+	}
+	if pairs[0].First.Applicant.ID != "u1" || pairs[0].Second.Applicant.ID != "u2" {
+		t.Errorf("unexpected pair %s-%s", pairs[0].First.Applicant.ID, pairs[0].Second.Applicant.ID)
+	}
+	if pairs[0].Prior {
+		t.Error("expected pair from the same quiz")
+	}
+}
+
+func TestDetectPrior(t *testing.T) {
+	current := []shared.SimilarityEntry{entry("u1", "p1", 60, goSrc)}
+	prior := []shared.SimilarityEntry{
+		entry("u9", "p1", 60, renamedGoSrc),
+		// the applicant's own previous solution is not reported
+		entry("u1", "p1", 60, goSrc),
+	}
+	pairs := Detect(current, prior)
+	if len(pairs) != 1 {
+		t.Fatalf("expected 1 pair, got %d", len(pairs))
+	}
+	if !pairs[0].Prior {
+		t.Error("expected prior pair")
+	}
+}
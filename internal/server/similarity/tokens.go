@@ -0,0 +1,158 @@
+package similarity
+
+type Token struct {
+	Value string
+	Start int
+	End   int
+}
+
+// Placeholders of the tokens whose value is not kept, keywords and
+// operators are their own value
+const (
+	IdentToken  = "ID"
+	NumberToken = "NUM"
+	StringToken = "STR"
+)
+
+// Judge0 languages whose line comments start with '#'
+var hashCommentLanguages = map[int32]bool{
+	46: true, // bash
+	70: true, // python 2
+	71: true, // python 3
+	72: true, // ruby
+	80: true, // r
+	85: true, // perl
+}
+
+var keywords = map[string]bool{
+	"and": true, "as": true, "auto": true, "break": true, "case": true,
+	"catch": true, "chan": true, "char": true, "class": true, "const": true,
+	"continue": true, "def": true, "default": true, "defer": true, "del": true,
+	"do": true, "double": true, "elif": true, "else": true, "enum": true,
+	"except": true, "extends": true, "false": true, "final": true, "finally": true,
+	"float": true, "fn": true, "for": true, "func": true, "function": true,
+	"go": true, "if": true, "impl": true, "implements": true, "import": true,
+	"in": true, "int": true, "interface": true, "lambda": true, "let": true,
+	"long": true, "loop": true, "map": true, "match": true, "mut": true,
+	"new": true, "nil": true, "none": true, "not": true, "null": true,
+	"or": true, "package": true, "pass": true, "private": true, "protected": true,
+	"pub": true, "public": true, "raise": true, "range": true, "return": true,
+	"select": true, "short": true, "static": true, "string": true, "struct": true,
+	"switch": true, "this": true, "throw": true, "throws": true, "true": true,
+	"try": true, "type": true, "unsigned": true, "use": true, "var": true,
+	"void": true, "while": true, "with": true, "yield": true,
+}
+
+// IsKeyword reports whether the token value is one of the keywords kept by
+// Tokenize
+func IsKeyword(value string) bool {
+	return keywords[value]
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c >= 0x80 || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+// Tokenize drops whitespace and comments and replaces identifiers, numbers
+// and string literals with placeholders, so renaming variables or changing
+// constants does not hide copied code. Keywords and operators are kept.
+func Tokenize(src string, languageID int32) []Token {
+	hashComments := hashCommentLanguages[languageID]
+	tokens := []Token{}
+	i := 0
+	for i < len(src) {
+		c := src[i]
+		start := i
+		switch {
+		case isSpace(c):
+			i++
+			continue
+		case hashComments && c == '#':
+			i = skipLine(src, i)
+			continue
+		case !hashComments && c == '/' && i+1 < len(src) && src[i+1] == '/':
+			i = skipLine(src, i)
+			continue
+		case !hashComments && c == '/' && i+1 < len(src) && src[i+1] == '*':
+			i = skipBlockComment(src, i)
+			continue
+		case c == '"' || c == '\'' || c == '`':
+			i = skipString(src, i)
+			tokens = append(tokens, Token{Value: StringToken, Start: start, End: i})
+		case isDigit(c):
+			for i < len(src) && (isIdentStart(src[i]) || isDigit(src[i]) || src[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, Token{Value: NumberToken, Start: start, End: i})
+		case isIdentStart(c):
+			for i < len(src) && (isIdentStart(src[i]) || isDigit(src[i])) {
+				i++
+			}
+			value := src[start:i]
+			if !keywords[value] {
+				value = IdentToken
+			}
+			tokens = append(tokens, Token{Value: value, Start: start, End: i})
+		default:
+			i++
+			tokens = append(tokens, Token{Value: src[start:i], Start: start, End: i})
+		}
+	}
+	return tokens
+}
+
+func skipLine(src string, i int) int {
+	for i < len(src) && src[i] != '\n' {
+		i++
+	}
+	return i
+}
+
+func skipBlockComment(src string, i int) int {
+	i += 2
+	for i+1 < len(src) {
+		if src[i] == '*' && src[i+1] == '/' {
+			return i + 2
+		}
+		i++
+	}
+	return len(src)
+}
+
+// Unterminated literals end at the end of the line, except backticks and
+// python triple quotes which may span several lines
+func skipString(src string, i int) int {
+	quote := src[i]
+	if i+2 < len(src) && src[i+1] == quote && src[i+2] == quote {
+		end := i + 3
+		for end+2 < len(src) {
+			if src[end] == quote && src[end+1] == quote && src[end+2] == quote {
+				return end + 3
+			}
+			end++
+		}
+		return len(src)
+	}
+	i++
+	for i < len(src) {
+		switch {
+		case src[i] == '\\':
+			i += 2
+			continue
+		case src[i] == quote:
+			return i + 1
+		case src[i] == '\n' && quote != '`':
+			return i
+		}
+		i++
+	}
+	return len(src)
+}
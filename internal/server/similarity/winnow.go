@@ -0,0 +1,115 @@
+package similarity
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+type Fingerprint struct {
+	Hash  uint64
+	Start int
+	End   int
+}
+
+type Region struct {
+	Start int
+	End   int
+}
+
+// Fingerprints hashes every k consecutive tokens and keeps the minimum hash
+// of each window of w hashes, as described in "Winnowing: local algorithms
+// for document fingerprinting" (Schleimer, Wilkerson, Aiken).
+func Fingerprints(tokens []Token, k, w int) []Fingerprint {
+	if len(tokens) < k {
+		return []Fingerprint{}
+	}
+	grams := make([]Fingerprint, 0, len(tokens)-k+1)
+	for i := 0; i+k <= len(tokens); i++ {
+		h := fnv.New64a()
+		for _, token := range tokens[i : i+k] {
+			h.Write([]byte(token.Value))
+			h.Write([]byte{0})
+		}
+		grams = append(grams, Fingerprint{
+			Hash:  h.Sum64(),
+			Start: tokens[i].Start,
+			End:   tokens[i+k-1].End,
+		})
+	}
+	if len(grams) < w {
+		w = len(grams)
+	}
+	res := []Fingerprint{}
+	last := -1
+	for i := 0; i+w <= len(grams); i++ {
+		minIdx := i
+		for j := i; j < i+w; j++ {
+			// rightmost minimum, so consecutive windows share the selection
+			if grams[j].Hash <= grams[minIdx].Hash {
+				minIdx = j
+			}
+		}
+		if minIdx != last {
+			res = append(res, grams[minIdx])
+			last = minIdx
+		}
+	}
+	return res
+}
+
+// Compare returns the Dice coefficient of both fingerprint sets and the
+// source regions of each side covered by shared fingerprints
+func Compare(a, b []Fingerprint) (float64, []Region, []Region) {
+	hashesA := hashSet(a)
+	hashesB := hashSet(b)
+	if len(hashesA) == 0 || len(hashesB) == 0 {
+		return 0, nil, nil
+	}
+	shared := make(map[uint64]bool)
+	for h := range hashesA {
+		if hashesB[h] {
+			shared[h] = true
+		}
+	}
+	score := 2 * float64(len(shared)) / float64(len(hashesA)+len(hashesB))
+	return score, matchedRegions(a, shared), matchedRegions(b, shared)
+}
+
+func hashSet(fingerprints []Fingerprint) map[uint64]bool {
+	res := make(map[uint64]bool)
+	for _, f := range fingerprints {
+		res[f.Hash] = true
+	}
+	return res
+}
+
+func matchedRegions(fingerprints []Fingerprint, shared map[uint64]bool) []Region {
+	regions := []Region{}
+	for _, f := range fingerprints {
+		if shared[f.Hash] {
+			regions = append(regions, Region{Start: f.Start, End: f.End})
+		}
+	}
+	return MergeRegions(regions)
+}
+
+func MergeRegions(regions []Region) []Region {
+	if len(regions) == 0 {
+		return regions
+	}
+	sort.Slice(regions, func(i, j int) bool {
+		return regions[i].Start < regions[j].Start
+	})
+	res := []Region{regions[0]}
+	for _, r := range regions[1:] {
+		last := &res[len(res)-1]
+		if r.Start <= last.End {
+			if r.End > last.End {
+				last.End = r.End
+			}
+			continue
+		}
+		res = append(res, r)
+	}
+	return res
+}
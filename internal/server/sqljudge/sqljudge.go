@@ -0,0 +1,182 @@
+package sqljudge
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kw3a/spotted-server/internal/server/codejudge"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// LanguageID is the SQLite language of Judge0, the sqlite3 shell runs the
+// source as a script against an empty database
+const LanguageID int32 = 82
+
+const (
+	// MaxScriptLength bounds the schema and the seed data of a problem
+	MaxScriptLength = 20000
+	// Null is how the shell prints NULL values, expected results use it too
+	Null = "NULL"
+	// Separator divides the columns of a row, values can't contain it
+	Separator = "|"
+)
+
+var (
+	ErrEmptyQuery         = errors.New("la consulta está vacía")
+	ErrMultipleStatements = errors.New("la consulta debe ser una sola sentencia")
+	ErrDotCommand         = errors.New("la consulta no puede tener comandos del intérprete de sqlite")
+)
+
+// Query removes the trailing semicolon and checks that the candidate wrote
+// a single statement, comments and quoted text are skipped
+func Query(src string) (string, error) {
+	end := -1
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		comment := strings.HasPrefix(src[i:], "--") || strings.HasPrefix(src[i:], "/*")
+		if end >= 0 && !comment && !strings.ContainsRune(" \t\n\r;", rune(c)) {
+			return "", ErrMultipleStatements
+		}
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			j := strings.IndexByte(src[i+1:], c)
+			if j < 0 {
+				i = len(src)
+			} else {
+				i += j + 1
+			}
+		case c == '[':
+			j := strings.IndexByte(src[i+1:], ']')
+			if j < 0 {
+				i = len(src)
+			} else {
+				i += j + 1
+			}
+		case c == '-' && strings.HasPrefix(src[i:], "--"):
+			j := strings.IndexByte(src[i:], '\n')
+			if j < 0 {
+				i = len(src)
+			} else {
+				i += j
+			}
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			j := strings.Index(src[i+2:], "*/")
+			if j < 0 {
+				i = len(src)
+			} else {
+				i += j + 3
+			}
+		case c == ';' && end < 0:
+			end = i
+		}
+	}
+	if end >= 0 {
+		src = src[:end]
+	}
+	src = strings.TrimSpace(src)
+	if src == "" {
+		return "", ErrEmptyQuery
+	}
+	for _, line := range strings.Split(src, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), ".") {
+			return "", ErrDotCommand
+		}
+	}
+	return src, nil
+}
+
+// Script is the program judged for one test case: the schema, the seed data
+// and the dataset of the test case run silently, then the query prints its
+// rows. Unordered results are sorted by the text of every column so they
+// match the expected result normalized by Result
+func Script(db shared.Database, dataset, query string, columns int) string {
+	var b strings.Builder
+	b.WriteString(".bail on\n.headers off\n.mode list\n.nullvalue " + Null + "\n")
+	b.WriteString(".output /dev/null\n")
+	for _, part := range []string{db.Schema, db.Seed, dataset} {
+		if strings.TrimSpace(part) != "" {
+			b.WriteString(part + "\n;\n")
+		}
+	}
+	b.WriteString(".output stdout\n")
+	if db.Ordered || columns < 1 {
+		b.WriteString(query + "\n;\n")
+		return b.String()
+	}
+	names := make([]string, columns)
+	order := make([]string, columns)
+	for i := range names {
+		names[i] = fmt.Sprintf("c%d", i+1)
+		order[i] = fmt.Sprintf("CAST(%s AS TEXT)", names[i])
+	}
+	fmt.Fprintf(&b, "WITH spotted_result(%s) AS (\n%s\n)\nSELECT * FROM spotted_result ORDER BY %s;\n",
+		strings.Join(names, ", "), query, strings.Join(order, ", "))
+	return b.String()
+}
+
+// Result normalizes an expected result set, one row per line with the
+// columns divided by Separator. Rows of unordered problems are sorted the
+// same way Script sorts the rows of the query
+func Result(output string, ordered bool) string {
+	rows := []string{}
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimRight(line, " \t\r"); line != "" {
+			rows = append(rows, line)
+		}
+	}
+	if !ordered {
+		sort.SliceStable(rows, func(i, j int) bool {
+			return lessRow(strings.Split(rows[i], Separator), strings.Split(rows[j], Separator))
+		})
+	}
+	return strings.Join(rows, "\n")
+}
+
+// lessRow follows ORDER BY on text columns, NULL goes first and the rest is
+// compared byte by byte
+func lessRow(a, b []string) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == b[i] {
+			continue
+		}
+		if a[i] == Null {
+			return true
+		}
+		if b[i] == Null {
+			return false
+		}
+		return a[i] < b[i]
+	}
+	return len(a) < len(b)
+}
+
+func columns(output string) int {
+	output = strings.TrimSpace(output)
+	if output == "" {
+		return 0
+	}
+	first, _, _ := strings.Cut(output, "\n")
+	return strings.Count(first, Separator) + 1
+}
+
+// Prepare builds one script per test case for SQL problems and judges them
+// with SQLite, submissions of other problems are returned unchanged
+func Prepare(db *shared.Database, testCases []codejudge.TestCase, submission codejudge.Submission) ([]codejudge.TestCase, codejudge.Submission, error) {
+	if db == nil {
+		return testCases, submission, nil
+	}
+	query, err := Query(submission.Src)
+	if err != nil {
+		return nil, codejudge.Submission{}, err
+	}
+	res := make([]codejudge.TestCase, len(testCases))
+	for i, tc := range testCases {
+		tc.Src = Script(*db, tc.Input, query, columns(tc.ExpectedOutput))
+		tc.Input = ""
+		res[i] = tc
+	}
+	submission.LanguageID = LanguageID
+	return res, submission, nil
+}
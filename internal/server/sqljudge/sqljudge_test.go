@@ -0,0 +1,99 @@
+package sqljudge
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/codejudge"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+func TestQuery(t *testing.T) {
+	cases := map[string]string{
+		"SELECT 1":                              "SELECT 1",
+		"  SELECT 1;  \n":                       "SELECT 1",
+		"SELECT ';' AS x; -- end\n":             "SELECT ';' AS x",
+		"SELECT 1 /* ; */ ;;":                   "SELECT 1 /* ; */",
+		"SELECT \"a;b\" FROM t -- a; b":         "SELECT \"a;b\" FROM t -- a; b",
+		"WITH x AS (SELECT 1)\nSELECT * FROM x": "WITH x AS (SELECT 1)\nSELECT * FROM x",
+	}
+	for src, expected := range cases {
+		query, err := Query(src)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", src, err)
+			continue
+		}
+		if query != expected {
+			t.Errorf("%q: expected %q, got %q", src, expected, query)
+		}
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	cases := map[string]error{
+		"":                        ErrEmptyQuery,
+		" ; -- nothing":           ErrEmptyQuery,
+		"DELETE FROM t; SELECT 1": ErrMultipleStatements,
+		"SELECT 1; 'x'":           ErrMultipleStatements,
+		"SELECT 1\n.shell ls":     ErrDotCommand,
+	}
+	for src, expected := range cases {
+		if _, err := Query(src); !errors.Is(err, expected) {
+			t.Errorf("%q: expected %v, got %v", src, expected, err)
+		}
+	}
+}
+
+func TestResult(t *testing.T) {
+	output := "bo|2\n\nAl|NULL  \nana|1\nAl|1\n"
+	if got := Result(output, true); got != "bo|2\nAl|NULL\nana|1\nAl|1" {
+		t.Errorf("unexpected ordered result %q", got)
+	}
+	if got := Result(output, false); got != "Al|NULL\nAl|1\nana|1\nbo|2" {
+		t.Errorf("unexpected unordered result %q", got)
+	}
+}
+
+func TestScript(t *testing.T) {
+	db := shared.Database{Schema: "CREATE TABLE t(a INT, b TEXT);", Seed: "INSERT INTO t VALUES (1, 'x');"}
+	script := Script(db, "INSERT INTO t VALUES (2, NULL);", "SELECT a, b FROM t", 2)
+	for _, part := range []string{
+		".output /dev/null\nCREATE TABLE t(a INT, b TEXT);\n;\nINSERT INTO t VALUES (1, 'x');\n;\nINSERT INTO t VALUES (2, NULL);\n;\n.output stdout\n",
+		"WITH spotted_result(c1, c2) AS (\nSELECT a, b FROM t\n)\nSELECT * FROM spotted_result ORDER BY CAST(c1 AS TEXT), CAST(c2 AS TEXT);\n",
+	} {
+		if !strings.Contains(script, part) {
+			t.Errorf("expected script to contain %q, got:\n%s", part, script)
+		}
+	}
+	db.Ordered = true
+	script = Script(db, "", "SELECT a FROM t ORDER BY a", 1)
+	if !strings.HasSuffix(script, ".output stdout\nSELECT a FROM t ORDER BY a\n;\n") || strings.Contains(script, "spotted_result") {
+		t.Errorf("expected the query unchanged, got:\n%s", script)
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	testCases := []codejudge.TestCase{{ID: "1", Input: "INSERT INTO t VALUES (1);", ExpectedOutput: "1|x"}}
+	submission := codejudge.Submission{ID: "s", Src: "SELECT a, b FROM t;", LanguageID: 71}
+	tcs, sub, err := Prepare(nil, testCases, submission)
+	if err != nil || sub != submission || tcs[0].Src != "" {
+		t.Errorf("expected submission unchanged, got %+v %+v %v", sub, tcs, err)
+	}
+	tcs, sub, err = Prepare(&shared.Database{Schema: "CREATE TABLE t(a INT);"}, testCases, submission)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.LanguageID != LanguageID || sub.Src != submission.Src {
+		t.Errorf("unexpected submission %+v", sub)
+	}
+	if !strings.Contains(tcs[0].Src, "spotted_result(c1, c2)") || tcs[0].Input != "" {
+		t.Errorf("unexpected test case %+v", tcs[0])
+	}
+	if testCases[0].Src != "" {
+		t.Error("the test cases of the caller must not change")
+	}
+	if _, _, err := Prepare(&shared.Database{}, testCases, codejudge.Submission{Src: "SELECT 1; SELECT 2"}); err == nil {
+		t.Error("expected error for multiple statements")
+	}
+}
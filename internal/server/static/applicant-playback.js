@@ -89,7 +89,8 @@
         }
     }
 
-    // Initialize all recording sections
+    // Initialize all recording sections, applicants under blind review
+    // have none so their participation never reaches the broker
     function initializeRecordingSections() {
         const sections = document.querySelectorAll('[data-recording-section]');
 
@@ -0,0 +1,27 @@
+function renderMath(root) {
+  if (typeof renderMathInElement !== "function") return;
+  const elements = root.matches && root.matches(".markdown") ? [root] : root.querySelectorAll(".markdown");
+  elements.forEach((element) =>
+    renderMathInElement(element, {
+      delimiters: [
+        { left: "\\[", right: "\\]", display: true },
+        { left: "\\(", right: "\\)", display: false },
+      ],
+      throwOnError: false,
+    }),
+  );
+}
+
+function markdownSource(element) {
+  return element.closest(".md-field").querySelector("textarea").value;
+}
+
+function insertMarkdown(element, snippet) {
+  const textarea = element.closest(".md-field").querySelector("textarea");
+  const start = textarea.selectionStart ?? textarea.value.length;
+  textarea.value = textarea.value.slice(0, start) + snippet + textarea.value.slice(textarea.selectionEnd ?? start);
+  textarea.dispatchEvent(new Event("input"));
+}
+
+document.addEventListener("DOMContentLoaded", () => renderMath(document.body));
+document.addEventListener("htmx:afterSwap", (evt) => renderMath(evt.detail.target));
@@ -7,6 +7,19 @@ function updateInputNames() {
       `problems[${pIndex}][description]`;
     problem.querySelector("input[name^='problems'][name*='time_limit']").name =
       `problems[${pIndex}][time_limit]`;
+    problem.querySelector("input[name^='problems'][name*='signature']").name =
+      `problems[${pIndex}][signature]`;
+    problem.querySelector("textarea[name^='problems'][name*='sql_schema']").name =
+      `problems[${pIndex}][sql_schema]`;
+    problem.querySelector("textarea[name^='problems'][name*='sql_seed']").name =
+      `problems[${pIndex}][sql_seed]`;
+    problem.querySelector("select[name^='problems'][name*='sql_order']").name =
+      `problems[${pIndex}][sql_order]`;
+    for (const field of ["kind", "difficulty", "tag", "points", "options", "answers", "tolerance", "rubric",
+      "generator_language", "generator", "generator_solution", "generator_seeds"]) {
+      problem.querySelector(`[name^='problems'][name$='[${field}]']`).name =
+        `problems[${pIndex}][${field}]`;
+    }
 
     const testCases = problem.querySelectorAll(".tc");
     testCases.forEach((testCase, tcIndex) => {
@@ -14,6 +27,10 @@ function updateInputNames() {
         `problems[${pIndex}][test_cases][${tcIndex}][input]`;
       testCase.querySelector("textarea[name^='problems'][name*='test_cases'][name*='output']").name =
         `problems[${pIndex}][test_cases][${tcIndex}][output]`;
+      for (const field of ["input_hash", "output_hash"]) {
+        const hash = testCase.querySelector(`input[name$='[${field}]']`);
+        if (hash) hash.name = `problems[${pIndex}][test_cases][${tcIndex}][${field}]`;
+      }
     });
 
     const examples = problem.querySelectorAll(".ex");
@@ -26,47 +43,66 @@ function updateInputNames() {
   });
 }
 
+function toggleProblemKind(select) {
+  const problem = select.closest(".problem");
+  const kind = select.value;
+  const question = kind !== "code";
+  problem.querySelectorAll(".code-fields").forEach((el) => el.classList.toggle("hidden", question));
+  problem.querySelector(".question-fields").classList.toggle("hidden", !question);
+  problem.querySelector(".question-choice").classList.toggle("hidden", kind !== "single" && kind !== "multiple");
+  problem.querySelector(".question-answers").classList.toggle("hidden", kind !== "numeric" && kind !== "short");
+  problem.querySelector(".question-rubric").classList.toggle("hidden", kind !== "text");
+}
+
+function countBankProblems() {
+  return document.querySelectorAll("input[name='bank']:checked").length;
+}
+
 function handleAddProblem(event) {
   event.preventDefault();
-  const MAX_PROBLEMS = 5;
+  const MAX_PROBLEMS = 15;
   const problemsContainer = document.getElementById("problems-container");
-  const currentProblems = problemsContainer.children.length;
+  const currentProblems = problemsContainer.children.length + countBankProblems();
   if (currentProblems < MAX_PROBLEMS) {
-    const newProblem = problemsContainer.firstElementChild.cloneNode(true);
-    newProblem.querySelectorAll("input, textarea").forEach((field) => (field.value = ""));
+    const template = document.getElementById("problem-template");
+    const newProblem = template.content.firstElementChild.cloneNode(true);
     problemsContainer.appendChild(newProblem);
     updateInputNames();
   } else {
-    showToast("No puedes agregar más de 5 problemas.");
+    showToast("No puedes agregar más de 15 problemas.");
   }
 }
 
 function handleDeleteProblem(event) {
   event.preventDefault();
-  const MIN_PROBLEMS = 1;
   const problem = event.target.closest(".problem");
   const problemsContainer = document.getElementById("problems-container");
+  const MIN_PROBLEMS = parseInt(problemsContainer.dataset.min || "0", 10);
   const currentProblems = problemsContainer.children.length;
   if (currentProblems > MIN_PROBLEMS) {
     problem.remove();
     updateInputNames();
   } else {
-    showToast("Debe haber al menos 1 problema.");
+    showToast(`Debe haber al menos ${MIN_PROBLEMS} problema.`);
   }
 }
 
 function handleAddTestCase(event) {
   event.preventDefault();
-  const MAX_ENTRIES = 10;
+  const MAX_ENTRIES = 30;
   const container = event.target.closest(".test-case-group");
   const entries = container.querySelectorAll(".test-case");
   if (entries.length < MAX_ENTRIES) {
     const newTestCase = entries[0].cloneNode(true);
-    newTestCase.querySelectorAll("textarea").forEach((field) => (field.value = ""));
+    newTestCase.querySelectorAll(".tc-stored").forEach((el) => el.remove());
+    newTestCase.querySelectorAll("textarea").forEach((field) => {
+      field.value = "";
+      field.readOnly = false;
+    });
     container.appendChild(newTestCase);
     updateInputNames();
   } else {
-    showToast("No puedes agregar más de 10 casos de prueba.");
+    showToast("No puedes agregar más de 30 casos de prueba.");
   }
 }
 
@@ -113,26 +149,62 @@ function handleDeleteExample(event) {
   }
 }
 
+function validateField(field, errorElement, min, max) {
+  const value = field.value.trim();
+  if (value.length === 0) {
+    errorElement.textContent = min ? `Este campo es obligatorio. (min. ${min} caracteres)` : "Este campo es obligatorio.";
+    return false;
+  } else if (min && value.length < min) {
+    errorElement.textContent = `Este campo debe tener al menos ${min} caracteres.`;
+    return false;
+  } else if (max && value.length > max) {
+    errorElement.textContent = `Este campo no puede tener más de ${max} caracteres.`;
+    return false;
+  } else {
+    errorElement.textContent = "";
+    return true;
+  }
+}
+
+function validateProblems() {
+  let allValid = true;
+  const problems = Array.from(document.querySelectorAll("#problems-container .problem"));
+
+  problems.forEach((problem) => {
+    const title = problem.querySelector("input[name^='problems'][name*='title']");
+    const titleError = title.nextElementSibling;
+    const isTitleValid = validateField(title, titleError, 1, 64);
+    if (!isTitleValid) allValid = false;
+
+    const description = problem.querySelector("textarea[name^='problems'][name*='description']");
+    const descriptionError = description.nextElementSibling;
+    const isDescriptionValid = validateField(description, descriptionError, 10, 5000);
+    if (!isDescriptionValid) allValid = false;
+
+    const validateGroup = (groupSelector, max) => {
+      const items = Array.from(problem.querySelectorAll(groupSelector));
+      return items.forEach((item) => {
+        const input = item.querySelector("textarea[name*='input']");
+        const output = item.querySelector("textarea[name*='output']");
+        const inputError = input.nextElementSibling;
+        const outputError = output.nextElementSibling;
+        const isInputValid = validateField(input, inputError, 1, max);
+        const isOutputValid = validateField(output, outputError, 1, max);
+        if (!isInputValid || !isOutputValid) allValid = false;
+        return isInputValid && isOutputValid;
+      });
+    };
+
+    validateGroup(".test-case-group .test-case", 65535);
+    validateGroup(".example-group .example", 1000);
+  });
+
+  return allValid;
+}
+
 const validateForm = () => {
   let valid = true;
 
-  const validateField = (field, errorElement, min, max) => {
-    const value = field.value.trim();
-    if (value.length === 0) {
-      errorElement.textContent = min ? `Este campo es obligatorio. (min. ${min} caracteres)` : "Este campo es obligatorio.";
-      return false;
-    } else if (min && value.length < min) {
-      errorElement.textContent = `Este campo debe tener al menos ${min} caracteres.`;
-      return false;
-    } else if (max && value.length > max) {
-      errorElement.textContent = `Este campo no puede tener más de ${max} caracteres.`;
-      return false;
-    } else {
-      errorElement.textContent = "";
-      return true;
-    }
-  };
-
   const offerTitle = document.getElementById("f-title");
   const titleError = document.getElementById("f-title-error");
   if (!validateField(offerTitle, titleError, 10, 60)) valid = false;
@@ -161,44 +233,19 @@ const validateForm = () => {
   const benefitsError = document.getElementById("f-benefits-error");
   if (!validateField(benefits, benefitsError, 200, 5000)) valid = false;
 
-  const validateProblems = () => {
-    let allValid = true;
-    const problems = Array.from(document.querySelectorAll("#problems-container .problem"));
-
-    problems.forEach((problem) => {
-      const title = problem.querySelector("input[name^='problems'][name*='title']");
-      const titleError = title.nextElementSibling;
-      const isTitleValid = validateField(title, titleError, 1, 64);
-      if (!isTitleValid) allValid = false;
-
-      const description = problem.querySelector("textarea[name^='problems'][name*='description']");
-      const descriptionError = description.nextElementSibling;
-      const isDescriptionValid = validateField(description, descriptionError, 10, 5000);
-      if (!isDescriptionValid) allValid = false;
-
-      const validateGroup = (groupSelector) => {
-        const items = Array.from(problem.querySelectorAll(groupSelector));
-        return items.forEach((item) => {
-          const input = item.querySelector("textarea[name*='input']");
-          const output = item.querySelector("textarea[name*='output']");
-          const inputError = input.nextElementSibling;
-          const outputError = output.nextElementSibling;
-          const isInputValid = validateField(input, inputError, 1, 1000);
-          const isOutputValid = validateField(output, outputError, 1, 1000);
-          if (!isInputValid || !isOutputValid) allValid = false;
-          return isInputValid && isOutputValid;
-        });
-      };
-
-      validateGroup(".test-case-group .test-case");
-      validateGroup(".example-group .example");
-    });
-
-    return allValid;
-  };
-
   if (!validateProblems()) valid = false;
 
+  const totalProblems = document.querySelectorAll("#problems-container .problem").length + countBankProblems();
+  const pooled = Array.from(document.querySelectorAll("#f-pools input[name$='[draw]']")).some((draw) => draw.value.trim() !== "");
+  const maxProblems = pooled ? 15 : 5;
+  const problemsError = document.getElementById("add-problem-error");
+  if (totalProblems < 1 || totalProblems > maxProblems) {
+    problemsError.textContent = `La prueba debe tener entre 1 y ${maxProblems} problemas.`;
+    valid = false;
+  } else {
+    problemsError.textContent = "";
+  }
+
   const languages = Array.from(document.querySelectorAll("input[name='quiz[languages]']:checked"));
   const languagesError = document.getElementById("f-languages-error");
   if (languages.length === 0) {
@@ -213,6 +260,7 @@ const validateForm = () => {
 
 function submitForm(evt) {
   evt.preventDefault();
+  updateInputNames();
   const valid = validateForm();
   if (valid) {
     htmx.trigger('#offer-form-container', "evtsubmitoffer");
@@ -221,6 +269,16 @@ function submitForm(evt) {
   }
 }
 
+function submitProblem(evt) {
+  evt.preventDefault();
+  updateInputNames();
+  if (validateProblems()) {
+    htmx.trigger('#problem-form-container', "evtsubmitproblem");
+  } else {
+    showToast("Por favor, llena correctamente todos los campos.");
+  }
+}
+
 function toggleDetails(event) {
   const detailsElement = event.target.closest('details');
   if (detailsElement.hasAttribute('open')) {
@@ -0,0 +1,59 @@
+(function () {
+    const LARGE_PASTE_CHARS = 200;
+
+    // Get Quiz ID globally set in HTML
+    const getQuizID = () => window.quizID;
+
+    function report(kind, detail) {
+        const quizID = getQuizID();
+        if (!quizID) {
+            console.warn("Proctoring: No quizID found.");
+            return;
+        }
+        const formData = new FormData();
+        formData.append("quizID", quizID);
+        formData.append("kind", kind);
+        formData.append("detail", detail || "");
+
+        fetch("/proctoring", {
+            method: "POST",
+            body: formData
+        })
+            .then(res => res.ok ? res.json() : null)
+            .then(data => {
+                if (data && data.warning) {
+                    alert(data.msg);
+                }
+            })
+            .catch(err => console.error("Error sending proctoring event:", err));
+    }
+
+    document.addEventListener("visibilitychange", () => {
+        if (document.visibilityState === "hidden") {
+            report("tab_switch", "");
+        }
+    });
+
+    // A tab switch also blurs the window, only report blurs while the page is visible
+    window.addEventListener("blur", () => {
+        if (document.visibilityState === "visible") {
+            report("blur", "");
+        }
+    });
+
+    document.addEventListener("fullscreenchange", () => {
+        if (!document.fullscreenElement) {
+            report("fullscreen_exit", "");
+        }
+    });
+
+    // Monaco handles its own paste events, capture phase sees them first
+    document.addEventListener("paste", (e) => {
+        const text = (e.clipboardData || window.clipboardData).getData("text") || "";
+        if (text.length >= LARGE_PASTE_CHARS) {
+            report("paste", String(text.length));
+        }
+    }, true);
+
+    console.log("Proctoring events started.");
+})();
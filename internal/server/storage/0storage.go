@@ -5,11 +5,18 @@ import (
 	"log"
 
 	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
 )
 
 type MysqlStorage struct {
 	Queries *database.Queries
 	db      *sql.DB
+	// Blobs keeps the large test data and Generator runs the test
+	// generators of the problems, both are set by the app
+	Blobs     BlobStore
+	Generator shared.TestGenerator
+	// PseudonymKey signs the pseudonyms of blind reviewed applicants
+	PseudonymKey []byte
 }
 
 func NewMysqlStorage(dbURL string) (*MysqlStorage, error) {
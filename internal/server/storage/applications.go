@@ -0,0 +1,174 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// SelectCandidateApplications lists the participations of the candidate with
+// their own scores, the stage is only resolved for companies that share it
+func (mysql *MysqlStorage) SelectCandidateApplications(ctx context.Context, userID string, page int32) ([]shared.CandidateApplication, error) {
+	rows, err := mysql.Queries.SelectCandidateApplications(ctx, database.SelectCandidateApplicationsParams{
+		UserID: userID,
+		Limit:  offerPageSize,
+		Offset: (page - 1) * offerPageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	participationIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		participationIDs = append(participationIDs, row.ParticipationID)
+	}
+	summaries, err := mysql.candidateSummaries(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+	stagesByCompany := make(map[string][]shared.Stage)
+	res := make([]shared.CandidateApplication, 0, len(rows))
+	for _, row := range rows {
+		application := shared.CandidateApplication{
+			Offer: shared.Offer{
+				ID:              row.OfferID,
+				Title:           row.OfferTitle,
+				Status:          row.OfferStatus,
+				CompanyID:       row.CompanyID,
+				CompanyName:     row.CompanyName,
+				CompanyImageURL: row.CompanyImageUrl,
+			},
+			QuizID: row.QuizID,
+			Participation: shared.Participation{
+				ID:           row.ParticipationID,
+				CreatedAt:    row.ParticipationCreatedAt.Time,
+				ExpiresAt:    row.ParticipationExpiresAt,
+				FinishedAt:   row.ParticipationFinishedAt.Time,
+				EndReason:    row.ParticipationEndReason,
+				PausedAt:     row.ParticipationPausedAt.Time,
+				RelativeTime: RelativeTime(row.ParticipationCreatedAt.Time),
+			},
+			WithdrawnAt: row.WithdrawnAt.Time,
+			Summary:     summaries[row.ParticipationID],
+		}
+		if row.ShareStage {
+			stages, ok := stagesByCompany[row.CompanyID]
+			if !ok {
+				dbStages, err := mysql.Queries.SelectCompanyStages(ctx, row.CompanyID)
+				if err != nil {
+					return nil, err
+				}
+				stages = companyStages(dbStages)
+				stagesByCompany[row.CompanyID] = stages
+			}
+			pipeline := shared.Pipeline{Stage: row.Stage, Stages: stages}
+			application.StageLabel = pipeline.StageLabel()
+		}
+		res = append(res, application)
+	}
+	return res, nil
+}
+
+// candidateSummaries keeps what the candidate already knew: the best
+// submission of each problem and the score of the responses, the answer key
+// and the recruiter feedback stay hidden
+func (mysql *MysqlStorage) candidateSummaries(ctx context.Context, participationIDs []string) (map[string][]shared.Summary, error) {
+	res := make(map[string][]shared.Summary)
+	if len(participationIDs) == 0 {
+		return res, nil
+	}
+	summaries, err := mysql.batchSummary(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, summary := range summaries {
+		pid := summary.Submission.ParticipationID
+		res[pid] = append(res[pid], summary)
+	}
+	responses, err := mysql.batchResponses(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+	for pid, questions := range responses {
+		for _, summary := range questions {
+			res[pid] = append(res[pid], shared.Summary{
+				Title: summary.Title,
+				Score: summary.Score,
+				Question: &shared.Question{
+					Kind:   summary.Question.Kind,
+					Points: summary.Question.Points,
+				},
+				Response: shared.QuestionResponse{
+					Answer: summary.Response.Answer,
+					Graded: summary.Response.Graded,
+					Score:  summary.Response.Score,
+				},
+			})
+		}
+	}
+	return res, nil
+}
+
+// WithdrawApplication takes the candidate out of the process, a running
+// participation is finished on the way so nothing else is submitted
+func (mysql *MysqlStorage) WithdrawApplication(ctx context.Context, userID, participationID string) (shared.CandidateApplication, error) {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return shared.CandidateApplication{}, err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	dbParticipation, err := qtx.SelectParticipationByCandidate(ctx, database.SelectParticipationByCandidateParams{
+		ID:     participationID,
+		UserID: userID,
+	})
+	if err != nil {
+		return shared.CandidateApplication{}, err
+	}
+	reviews, err := qtx.BatchParticipationReviews(ctx, []string{participationID})
+	if err != nil {
+		return shared.CandidateApplication{}, err
+	}
+	if len(reviews) > 0 && reviews[0].WithdrawnAt.Valid {
+		return shared.CandidateApplication{}, shared.ErrAlreadyWithdrawn
+	}
+	now := time.Now()
+	participation := shared.Participation{
+		ID:         dbParticipation.ID,
+		CreatedAt:  dbParticipation.CreatedAt.Time,
+		ExpiresAt:  dbParticipation.ExpiresAt,
+		FinishedAt: dbParticipation.FinishedAt.Time,
+		EndReason:  dbParticipation.EndReason,
+		PausedAt:   dbParticipation.PausedAt.Time,
+	}
+	if !participation.Finished(now) {
+		err = qtx.FinishParticipation(ctx, database.FinishParticipationParams{
+			FinishedAt: sql.NullTime{Time: now, Valid: true},
+			EndReason:  shared.EndReasonWithdrawn,
+			ID:         participationID,
+		})
+		if err != nil {
+			return shared.CandidateApplication{}, err
+		}
+		participation.FinishedAt = now
+		participation.EndReason = shared.EndReasonWithdrawn
+	}
+	err = qtx.UpsertParticipationWithdrawal(ctx, database.UpsertParticipationWithdrawalParams{
+		ParticipationID: participationID,
+		WithdrawnAt:     sql.NullTime{Time: now, Valid: true},
+	})
+	if err != nil {
+		return shared.CandidateApplication{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return shared.CandidateApplication{}, err
+	}
+	participation.RelativeTime = RelativeTime(participation.CreatedAt)
+	return shared.CandidateApplication{
+		QuizID:        dbParticipation.QuizID,
+		Participation: participation,
+		WithdrawnAt:   now,
+	}, nil
+}
@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+func (mysql *MysqlStorage) SelectBankProblems(ctx context.Context, companyID string) ([]shared.BankProblem, error) {
+	rows, err := mysql.Queries.SelectBankProblems(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]shared.BankProblem, len(rows))
+	for i, row := range rows {
+		res[i] = shared.BankProblem{
+			ID:         row.ID,
+			ProblemID:  row.ProblemID,
+			Version:    row.Version,
+			Title:      row.Title,
+			TimeLimit:  row.TimeLimit,
+			Difficulty: row.Difficulty,
+			Tag:        row.Tag,
+			UpdatedAt:  row.CreatedAt,
+			Quizzes:    shared.IntToInt32(int(row.Quizzes)),
+		}
+	}
+	return res, nil
+}
+
+// SelectBankProblem returns the latest version of a library problem owned
+// by one of the user's companies, with its examples and test cases
+func (mysql *MysqlStorage) SelectBankProblem(ctx context.Context, bankProblemID string, userID string) (shared.Problem, error) {
+	row, err := mysql.Queries.SelectBankProblemByUser(ctx, database.SelectBankProblemByUserParams{
+		ID:     bankProblemID,
+		UserID: userID,
+	})
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	testCases, err := mysql.SelectTestCases(ctx, row.ProblemID)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	examples, err := mysql.SelectExamples(ctx, row.ProblemID)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	checker, err := selectCheckers(ctx, mysql.Queries, row.ProblemID)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	db, err := selectDatabase(ctx, mysql.Queries, row.ProblemID)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	question, err := selectQuestion(ctx, mysql.Queries, row.ProblemID)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	return shared.Problem{
+		ID:            row.ProblemID,
+		Title:         row.Title,
+		Description:   row.Description,
+		MemoryLimit:   row.MemoryLimit,
+		TimeLimit:     row.TimeLimit,
+		TestCases:     testCases,
+		Examples:      examples,
+		BankProblemID: row.ID,
+		Version:       row.Version,
+		Checker:       checker,
+		Signature:     row.Signature,
+		Database:      db,
+		Question:      question,
+		Difficulty:    row.Difficulty,
+		Tag:           row.Tag,
+	}, nil
+}
+
+// SelectCompanyBankProblem returns the latest version of a library problem
+// of the company with the whole test data, it is used by the package command
+// line tool
+func (mysql *MysqlStorage) SelectCompanyBankProblem(ctx context.Context, companyID string, bankProblemID string) (shared.Problem, error) {
+	latest, err := mysql.Queries.SelectLatestVersions(ctx, database.SelectLatestVersionsParams{
+		CompanyID:      companyID,
+		BankProblemIds: []string{bankProblemID},
+	})
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	if len(latest) == 0 {
+		return shared.Problem{}, shared.ErrBankProblemNotFound
+	}
+	problem, err := mysql.SelectProblem(ctx, latest[0].ID)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	if problem.TestCases, err = mysql.SelectTestCases(ctx, problem.ID); err != nil {
+		return shared.Problem{}, err
+	}
+	if err := shared.FillTestData(ctx, mysql, &problem); err != nil {
+		return shared.Problem{}, err
+	}
+	if problem.Examples, err = mysql.SelectExamples(ctx, problem.ID); err != nil {
+		return shared.Problem{}, err
+	}
+	if problem.Checker, err = selectCheckers(ctx, mysql.Queries, problem.ID); err != nil {
+		return shared.Problem{}, err
+	}
+	problem.BankProblemID = bankProblemID
+	return problem, nil
+}
+
+func selectCheckers(ctx context.Context, q *database.Queries, problemID string) ([]shared.CheckerFile, error) {
+	rows, err := q.SelectCheckers(ctx, problemID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]shared.CheckerFile, len(rows))
+	for i, row := range rows {
+		res[i] = shared.CheckerFile{Path: row.Path, Src: row.Src}
+	}
+	return res, nil
+}
+
+// selectDatabase returns nil for problems that are not SQL problems
+func selectDatabase(ctx context.Context, q *database.Queries, problemID string) (*shared.Database, error) {
+	row, err := q.SelectProblemDatabase(ctx, problemID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &shared.Database{
+		Schema:  row.SchemaSql,
+		Seed:    row.SeedSql,
+		Ordered: row.Ordered,
+	}, nil
+}
+
+func (mysql *MysqlStorage) SelectProblemVersions(ctx context.Context, bankProblemID string) ([]shared.ProblemVersion, error) {
+	rows, err := mysql.Queries.SelectProblemVersions(ctx, bankProblemID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]shared.ProblemVersion, len(rows))
+	for i, row := range rows {
+		res[i] = shared.ProblemVersion{
+			ID:        row.ID,
+			Version:   row.Version,
+			CreatedAt: row.CreatedAt,
+			Quizzes:   shared.IntToInt32(int(row.Quizzes)),
+		}
+	}
+	return res, nil
+}
+
+func (mysql *MysqlStorage) InsertBankProblem(ctx context.Context, companyID string, problem shared.Problem) (string, error) {
+	ids, err := mysql.InsertBankProblems(ctx, companyID, []shared.Problem{problem})
+	if err != nil {
+		return "", err
+	}
+	return ids[0], nil
+}
+
+// InsertBankProblems adds every problem to the library in one transaction,
+// a package import either succeeds completely or leaves nothing behind
+func (mysql *MysqlStorage) InsertBankProblems(ctx context.Context, companyID string, problems []shared.Problem) ([]string, error) {
+	if err := mysql.prepareTestData(ctx, problems); err != nil {
+		return nil, err
+	}
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	ids := make([]string, 0, len(problems))
+	for _, problem := range problems {
+		bankProblemID := uuid.New().String()
+		err = qtx.InsertBankProblem(ctx, database.InsertBankProblemParams{
+			ID:        bankProblemID,
+			CompanyID: companyID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := insertProblemVersion(ctx, qtx, bankProblemID, 1, problem); err != nil {
+			return nil, err
+		}
+		ids = append(ids, bankProblemID)
+	}
+	return ids, tx.Commit()
+}
+
+// UpdateBankProblem stores the edit as a new version, quizzes keep pointing
+// to the version they were created with
+func (mysql *MysqlStorage) UpdateBankProblem(
+	ctx context.Context,
+	bankProblemID string,
+	userID string,
+	problem shared.Problem,
+) (int32, error) {
+	prepared := []shared.Problem{problem}
+	if err := mysql.prepareTestData(ctx, prepared); err != nil {
+		return 0, err
+	}
+	problem = prepared[0]
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	latest, err := qtx.SelectBankProblemByUser(ctx, database.SelectBankProblemByUserParams{
+		ID:     bankProblemID,
+		UserID: userID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	// The editor does not handle memory limits nor checkers, the ones
+	// imported with a package carry over to the new version
+	problem.MemoryLimit = latest.MemoryLimit
+	if len(problem.Checker) == 0 {
+		if problem.Checker, err = selectCheckers(ctx, qtx, latest.ProblemID); err != nil {
+			return 0, err
+		}
+	}
+	version := latest.Version + 1
+	problemID, err := insertProblemVersion(ctx, qtx, bankProblemID, version, problem)
+	if err != nil {
+		return 0, err
+	}
+	// Reference solutions follow the problem but have to be judged again
+	// against the new test cases
+	err = qtx.CopyReferenceSolutions(ctx, database.CopyReferenceSolutionsParams{
+		NewProblemID: problemID,
+		ProblemID:    latest.ProblemID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return version, tx.Commit()
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+func (mysql *MysqlStorage) SelectBlindReview(ctx context.Context, offerID string) (shared.BlindReview, error) {
+	stages, err := mysql.SelectOfferStages(ctx, offerID)
+	if err != nil {
+		return shared.BlindReview{}, err
+	}
+	review := shared.BlindReview{Key: mysql.PseudonymKey, OfferID: offerID, Stages: stages}
+	dbReview, err := mysql.Queries.SelectOfferBlindReview(ctx, offerID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return review, nil
+	}
+	if err != nil {
+		return shared.BlindReview{}, err
+	}
+	review.Enabled = true
+	review.RevealStage = dbReview.RevealStage
+	return review, nil
+}
+
+// UpdateBlindReview reveals the applicants already in the reveal stage, and
+// every applicant when blind review is disabled, so no identity is shown
+// without a log entry
+func (mysql *MysqlStorage) UpdateBlindReview(ctx context.Context, recruiterID string, review shared.BlindReview) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	dbStages, err := qtx.SelectOfferStages(ctx, review.OfferID)
+	if err != nil {
+		return err
+	}
+	stages := companyStages(dbStages)
+	if review.Enabled && !shared.HasStage(stages, review.RevealStage) {
+		return shared.ErrStage
+	}
+	_, err = qtx.SelectOfferBlindReview(ctx, review.OfferID)
+	wasEnabled := err == nil
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if review.Enabled || wasEnabled {
+		if err := revealApplicants(ctx, qtx, recruiterID, review, stages); err != nil {
+			return err
+		}
+	}
+	if review.Enabled {
+		err = qtx.UpsertOfferBlindReview(ctx, database.UpsertOfferBlindReviewParams{
+			OfferID:     review.OfferID,
+			RevealStage: review.RevealStage,
+		})
+	} else {
+		err = qtx.DeleteOfferBlindReview(ctx, review.OfferID)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// revealApplicants logs who is shown after the settings change: the
+// applicants in the reveal stage, or all of them when it was disabled
+func revealApplicants(
+	ctx context.Context,
+	qtx *database.Queries,
+	recruiterID string,
+	review shared.BlindReview,
+	stages []shared.Stage,
+) error {
+	participations, err := qtx.SelectOfferParticipationStages(ctx, review.OfferID)
+	if err != nil {
+		return err
+	}
+	reason := shared.RevealReasonStage
+	if !review.Enabled {
+		reason = shared.RevealReasonDisabled
+	}
+	for _, p := range participations {
+		current := shared.Pipeline{Stage: p.Stage.String, Stages: stages}.Current()
+		if review.Enabled && !review.Reveals(current) {
+			continue
+		}
+		err = qtx.InsertIdentityReveal(ctx, database.InsertIdentityRevealParams{
+			ParticipationID: p.ID,
+			Stage:           current,
+			Reason:          reason,
+			UserID:          recruiterID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// revealIdentity logs the reveal of an applicant moved to the reveal stage
+// of a blind reviewed offer, applicants are revealed only once
+func revealIdentity(ctx context.Context, qtx *database.Queries, recruiterID, participationID, stage string) error {
+	dbReview, err := qtx.SelectParticipationBlindReview(ctx, participationID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	review := shared.BlindReview{Enabled: true, RevealStage: dbReview.RevealStage}
+	if !review.Reveals(stage) {
+		return nil
+	}
+	return qtx.InsertIdentityReveal(ctx, database.InsertIdentityRevealParams{
+		ParticipationID: participationID,
+		Stage:           stage,
+		Reason:          shared.RevealReasonStage,
+		UserID:          recruiterID,
+	})
+}
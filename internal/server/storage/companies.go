@@ -5,6 +5,7 @@ import (
 
 	"github.com/kw3a/spotted-server/internal/database"
 	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/kw3a/spotted-server/internal/server/textsearch"
 )
 
 const (
@@ -29,12 +30,14 @@ func (mysql *MysqlStorage) GetCompanyByID(ctx context.Context, companyID string)
 func (mysql *MysqlStorage) GetCompanies(ctx context.Context, params shared.CompanyQueryParams) ([]shared.Company, error) {
 	companies := []shared.Company{}
 	var dbCompanies []database.Company
-	if params.Query != "" {
+	// a query made only of stopwords lists every company
+	query := textsearch.BooleanQuery(textsearch.Terms(params.Query))
+	if query != "" {
 		if params.UserID == "" {
 			comp, err := mysql.Queries.GetCompaniesByQuery(ctx, database.GetCompaniesByQueryParams{
 				Limit:  companyPageSize,
 				Offset: (params.Page - 1) * companyPageSize,
-				CONCAT: params.Query,
+				Query:  query,
 			})
 			if err != nil {
 				return nil, err
@@ -45,7 +48,7 @@ func (mysql *MysqlStorage) GetCompanies(ctx context.Context, params shared.Compa
 				UserID: params.UserID,
 				Limit:  companyPageSize,
 				Offset: (params.Page - 1) * companyPageSize,
-				CONCAT: params.Query,
+				Query:  query,
 			})
 			if err != nil {
 				return nil, err
@@ -112,7 +115,13 @@ func (mysql *MysqlStorage) RegisterCompany(
 	website string,
 	imageURL string,
 ) error {
-	return mysql.Queries.InsertCompany(ctx, database.InsertCompanyParams{
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	err = qtx.InsertCompany(ctx, database.InsertCompanyParams{
 		ID:          id,
 		UserID:      userID,
 		Name:        name,
@@ -120,4 +129,23 @@ func (mysql *MysqlStorage) RegisterCompany(
 		Website:     website,
 		ImageUrl:    imageURL,
 	})
+	if err != nil {
+		return err
+	}
+	err = qtx.InsertCompanyMember(ctx, database.InsertCompanyMemberParams{
+		CompanyID: id,
+		UserID:    userID,
+		Role:      shared.RoleOwner,
+	})
+	if err != nil {
+		return err
+	}
+	err = qtx.UpsertCompanyDocument(ctx, database.UpsertCompanyDocumentParams{
+		CompanyID: id,
+		Body:      textsearch.Document(name, description),
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
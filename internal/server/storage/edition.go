@@ -0,0 +1,201 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// EditOffer updates the text and wages of the offer, quiz is nil when the
+// quiz is left as it is. Quizzes with participations can't change, results
+// would not be comparable
+func (mysql *MysqlStorage) EditOffer(
+	ctx context.Context,
+	offerID, ownerID string,
+	offer shared.Offer,
+	quiz *shared.Quiz,
+	bankProblemIDs []string,
+) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	current, err := qtx.SelectOfferStatusByUser(ctx, database.SelectOfferStatusByUserParams{
+		ID:     offerID,
+		UserID: ownerID,
+	})
+	if err != nil {
+		return err
+	}
+	if current.Status == shared.OfferArchived {
+		return shared.ErrOfferArchived
+	}
+	err = qtx.UpdateOffer(ctx, database.UpdateOfferParams{
+		Title:        offer.Title,
+		About:        offer.About,
+		Requirements: offer.Requirements,
+		Benefits:     offer.Benefits,
+		MinWage:      offer.MinWage,
+		MaxWage:      offer.MaxWage,
+		Location:     offer.Location,
+		Modality:     offer.Modality,
+		Seniority:    offer.Seniority,
+		Contract:     offer.Contract,
+		Currency:     offer.Currency,
+		WagePeriod:   offer.WagePeriod,
+		ID:           offerID,
+	})
+	if err != nil {
+		return fmt.Errorf("error updating offer: %w", err)
+	}
+	if err := qtx.DeleteOfferTags(ctx, offerID); err != nil {
+		return err
+	}
+	if err := insertOfferTags(ctx, qtx, offerID, offer.Tags); err != nil {
+		return err
+	}
+	if err := upsertOfferDocument(ctx, qtx, offerID, offer); err != nil {
+		return err
+	}
+	if quiz != nil {
+		if err := editQuiz(ctx, qtx, offerID, current.CompanyID, *quiz, bankProblemIDs); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// editQuiz replaces the languages, duration and problems of the quiz. Problems
+// already in the quiz keep their pinned version and order, new library
+// problems are pinned to their latest version
+func editQuiz(
+	ctx context.Context,
+	qtx *database.Queries,
+	offerID, companyID string,
+	quiz shared.Quiz,
+	bankProblemIDs []string,
+) error {
+	// a participation locks the quiz through its foreign key, holding the
+	// quiz keeps anyone from starting until the problems are replaced
+	dbQuiz, err := qtx.LockQuizByOffer(ctx, offerID)
+	if err != nil {
+		return err
+	}
+	participations, err := qtx.CountParticipations(ctx, dbQuiz.ID)
+	if err != nil {
+		return err
+	}
+	if participations > 0 {
+		return shared.ErrQuizLocked
+	}
+	err = qtx.UpdateQuizDuration(ctx, database.UpdateQuizDurationParams{
+		Duration: quiz.Duration,
+		ID:       dbQuiz.ID,
+	})
+	if err != nil {
+		return fmt.Errorf("error updating quiz: %w", err)
+	}
+	if err := qtx.DeleteLanguageQuizzes(ctx, dbQuiz.ID); err != nil {
+		return err
+	}
+	for _, lang := range quiz.Languages {
+		err = qtx.InsertLanguageQuiz(ctx, database.InsertLanguageQuizParams{
+			ID:         uuid.New().String(),
+			QuizID:     dbQuiz.ID,
+			LanguageID: lang,
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting language quiz: %w", err)
+		}
+	}
+	selected := make(map[string]bool)
+	for _, id := range bankProblemIDs {
+		selected[id] = true
+	}
+	current, err := qtx.SelectQuizBankProblems(ctx, dbQuiz.ID)
+	if err != nil {
+		return err
+	}
+	pinned := make(map[string]bool)
+	problemIDs := []string{}
+	for _, problem := range current {
+		if selected[problem.BankProblemID] {
+			pinned[problem.BankProblemID] = true
+			problemIDs = append(problemIDs, problem.ID)
+		}
+	}
+	added := []string{}
+	for _, id := range bankProblemIDs {
+		if !pinned[id] {
+			added = append(added, id)
+		}
+	}
+	if len(added) > 0 {
+		latest, err := qtx.SelectLatestVersions(ctx, database.SelectLatestVersionsParams{
+			CompanyID:      companyID,
+			BankProblemIds: added,
+		})
+		if err != nil {
+			return err
+		}
+		versions := make(map[string]string)
+		for _, version := range latest {
+			versions[version.BankProblemID] = version.ID
+		}
+		for _, bankProblemID := range added {
+			problemID, ok := versions[bankProblemID]
+			if !ok {
+				return shared.ErrBankProblemNotFound
+			}
+			if err := checkReferenceSolutions(ctx, qtx, problemID); err != nil {
+				return err
+			}
+			problemIDs = append(problemIDs, problemID)
+		}
+	}
+	if err := qtx.DeleteQuizProblems(ctx, dbQuiz.ID); err != nil {
+		return err
+	}
+	for position, problemID := range problemIDs {
+		err = qtx.InsertQuizProblem(ctx, database.InsertQuizProblemParams{
+			QuizID:    dbQuiz.ID,
+			ProblemID: problemID,
+			Position:  shared.IntToInt32(position),
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting quiz problem: %w", err)
+		}
+	}
+	pools, err := selectPools(ctx, qtx, dbQuiz.ID)
+	if err != nil {
+		return err
+	}
+	candidates, err := selectPoolProblems(ctx, qtx, dbQuiz.ID)
+	if err != nil {
+		return err
+	}
+	return shared.CheckPools(candidates, pools)
+}
+
+// SelectQuizBankProblems maps the library problems of the quiz to the version
+// the quiz is pinned to
+func (mysql *MysqlStorage) SelectQuizBankProblems(ctx context.Context, quizID string) (map[string]int32, error) {
+	rows, err := mysql.Queries.SelectQuizBankProblems(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]int32)
+	for _, row := range rows {
+		res[row.BankProblemID] = row.Version
+	}
+	return res, nil
+}
+
+func (mysql *MysqlStorage) CountParticipations(ctx context.Context, quizID string) (int64, error) {
+	return mysql.Queries.CountParticipations(ctx, quizID)
+}
@@ -99,5 +99,8 @@ fn main() {
     let output: String = input.chars().rev().collect();
     println!("{}", output);
 }
+`,
+	82: `-- Escribe una sola consulta, sus filas se comparan con el resultado esperado.
+SELECT 1;
 `,
 }
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	driver "github.com/go-sql-driver/mysql"
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// Without 0/O and 1/I so codes can be dictated or typed by hand
+const invitationAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// invitationAttempts bounds the codes drawn for one email, codes are unique
+// across every quiz
+const invitationAttempts = 5
+
+var errInvitationCode = errors.New("no se pudo generar un código de invitación")
+
+func invitationCode() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = invitationAlphabet[int(b)%len(invitationAlphabet)]
+	}
+	return string(buf), nil
+}
+
+func (mysql *MysqlStorage) UpdateQuizAccess(ctx context.Context, quiz shared.Quiz) error {
+	return mysql.Queries.UpdateQuizAccess(ctx, database.UpdateQuizAccessParams{
+		OpensAt:    nullTime(quiz.OpensAt),
+		ClosesAt:   nullTime(quiz.ClosesAt),
+		InviteOnly: quiz.InviteOnly,
+		ID:         quiz.ID,
+	})
+}
+
+func (mysql *MysqlStorage) SelectInvitations(ctx context.Context, quizID string) ([]shared.Invitation, error) {
+	dbInvitations, err := mysql.Queries.SelectInvitations(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	invitations := make([]shared.Invitation, len(dbInvitations))
+	for i, invitation := range dbInvitations {
+		invitations[i] = shared.Invitation{
+			ID:     invitation.ID,
+			Email:  invitation.Email,
+			Code:   invitation.Code,
+			UsedAt: invitation.UsedAt.Time,
+		}
+	}
+	return invitations, nil
+}
+
+// InsertInvitations creates one invitation per email, emails that were
+// already invited keep their original code
+func (mysql *MysqlStorage) InsertInvitations(ctx context.Context, quizID string, emails []string) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	for _, email := range emails {
+		if err := insertInvitation(ctx, qtx, quizID, email); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// insertInvitation skips an email already invited to the quiz and draws
+// another code when the code is taken by any invitation
+func insertInvitation(ctx context.Context, qtx *database.Queries, quizID, email string) error {
+	for range invitationAttempts {
+		_, err := qtx.SelectInvitationByEmail(ctx, database.SelectInvitationByEmailParams{
+			QuizID: quizID,
+			Email:  email,
+		})
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		code, err := invitationCode()
+		if err != nil {
+			return err
+		}
+		err = qtx.InsertInvitation(ctx, database.InsertInvitationParams{
+			ID:     uuid.New().String(),
+			Email:  email,
+			Code:   code,
+			QuizID: quizID,
+		})
+		// the email may have been invited meanwhile, the next iteration
+		// tells both duplicates apart
+		if !isDuplicateEntry(err) {
+			return err
+		}
+	}
+	return errInvitationCode
+}
+
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *driver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+func (mysql *MysqlStorage) DeleteInvitation(ctx context.Context, quizID string, invitationID string) error {
+	return mysql.Queries.DeleteInvitation(ctx, database.DeleteInvitationParams{
+		ID:     invitationID,
+		QuizID: quizID,
+	})
+}
+
+// ClaimInvitation binds the invitation to the user on first use. The same
+// user may claim it again, for example if starting the participation failed
+func (mysql *MysqlStorage) ClaimInvitation(ctx context.Context, quizID, userID, email, code string) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	invitation, err := qtx.SelectInvitationByCode(ctx, database.SelectInvitationByCodeParams{
+		QuizID: quizID,
+		Code:   strings.ToUpper(code),
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return shared.ErrInvitationInvalid
+	}
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(invitation.Email, email) {
+		return shared.ErrInvitationEmail
+	}
+	if invitation.UserID.Valid && invitation.UserID.String != userID {
+		return shared.ErrInvitationUsed
+	}
+	err = qtx.ClaimInvitation(ctx, database.ClaimInvitationParams{
+		UsedAt: sql.NullTime{Time: time.Now(), Valid: true},
+		UserID: sql.NullString{String: userID, Valid: true},
+		ID:     invitation.ID,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
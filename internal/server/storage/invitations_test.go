@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	driver "github.com/go-sql-driver/mysql"
+)
+
+func TestIsDuplicateEntry(t *testing.T) {
+	duplicate := &driver.MySQLError{Number: 1062, Message: "Duplicate entry 'ABCD2345' for key 'code'"}
+	if !isDuplicateEntry(duplicate) || !isDuplicateEntry(fmt.Errorf("insert: %w", duplicate)) {
+		t.Error("expected a duplicate entry")
+	}
+	for _, err := range []error{nil, errors.New("1062"), &driver.MySQLError{Number: 1452}} {
+		if isDuplicateEntry(err) {
+			t.Errorf("%v: expected another error", err)
+		}
+	}
+}
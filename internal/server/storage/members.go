@@ -0,0 +1,335 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// SelectCompanyRole returns the role of the user in the company, empty when
+// they are not a member
+func (mysql *MysqlStorage) SelectCompanyRole(ctx context.Context, companyID, userID string) (string, error) {
+	return memberRole(ctx, mysql.Queries, companyID, userID)
+}
+
+func (mysql *MysqlStorage) SelectCompanyMembers(ctx context.Context, companyID string) ([]shared.Member, error) {
+	rows, err := mysql.Queries.SelectCompanyMembers(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	members := []shared.Member{}
+	for _, row := range rows {
+		members = append(members, shared.Member{
+			UserID:    row.UserID,
+			Nick:      row.Nick,
+			Name:      row.Name,
+			Email:     row.Email,
+			ImageURL:  row.ImageUrl,
+			Role:      row.Role,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return members, nil
+}
+
+func (mysql *MysqlStorage) SelectCompanyInvitations(ctx context.Context, companyID string) ([]shared.CompanyInvitation, error) {
+	rows, err := mysql.Queries.SelectCompanyInvitations(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	invitations := []shared.CompanyInvitation{}
+	for _, row := range rows {
+		invitations = append(invitations, shared.CompanyInvitation{
+			ID:        row.ID,
+			CompanyID: companyID,
+			Invitee:   row.Invitee,
+			Role:      row.Role,
+			InvitedBy: row.InvitedBy,
+			CreatedAt: row.CreatedAt,
+			Declined:  row.DeclinedAt.Valid,
+		})
+	}
+	return invitations, nil
+}
+
+// memberRole returns the role of a member, empty when they are not one
+func memberRole(ctx context.Context, qtx *database.Queries, companyID, userID string) (string, error) {
+	role, err := qtx.SelectCompanyRole(ctx, database.SelectCompanyRoleParams{
+		CompanyID: companyID,
+		UserID:    userID,
+	})
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return role, err
+}
+
+func checkManager(ctx context.Context, qtx *database.Queries, companyID, userID string) error {
+	role, err := memberRole(ctx, qtx, companyID, userID)
+	if err != nil {
+		return err
+	}
+	if !shared.CanManage(role) {
+		return shared.ErrNotManager
+	}
+	return nil
+}
+
+// InviteMember invites someone who is not a member yet, inviting them
+// again replaces the role and the answer of the previous invitation
+func (mysql *MysqlStorage) InviteMember(ctx context.Context, userID string, invitation shared.CompanyInvitation) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	if err := checkManager(ctx, qtx, invitation.CompanyID, userID); err != nil {
+		return err
+	}
+	_, err = qtx.SelectInviteeMember(ctx, database.SelectInviteeMemberParams{
+		CompanyID: invitation.CompanyID,
+		Invitee:   invitation.Invitee,
+	})
+	if err == nil {
+		return shared.ErrAlreadyMember
+	}
+	if err != sql.ErrNoRows {
+		return err
+	}
+	err = qtx.UpsertCompanyInvitation(ctx, database.UpsertCompanyInvitationParams{
+		ID:        invitation.ID,
+		CompanyID: invitation.CompanyID,
+		Invitee:   invitation.Invitee,
+		Role:      invitation.Role,
+		InvitedBy: userID,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (mysql *MysqlStorage) DeleteMemberInvitation(ctx context.Context, userID, companyID, invitationID string) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	if err := checkManager(ctx, qtx, companyID, userID); err != nil {
+		return err
+	}
+	err = qtx.DeleteCompanyInvitation(ctx, database.DeleteCompanyInvitationParams{
+		ID:        invitationID,
+		CompanyID: companyID,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (mysql *MysqlStorage) UpdateMemberRole(ctx context.Context, userID, companyID, memberID, role string) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	actor, err := memberRole(ctx, qtx, companyID, userID)
+	if err != nil {
+		return err
+	}
+	current, err := memberRole(ctx, qtx, companyID, memberID)
+	if err != nil {
+		return err
+	}
+	if err := shared.CheckMemberChange(actor, current); err != nil {
+		return err
+	}
+	err = qtx.UpdateCompanyMemberRole(ctx, database.UpdateCompanyMemberRoleParams{
+		Role:      role,
+		CompanyID: companyID,
+		UserID:    memberID,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteMember removes a member from the team, every member but the owner
+// can leave on their own
+func (mysql *MysqlStorage) DeleteMember(ctx context.Context, userID, companyID, memberID string) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	actor, err := memberRole(ctx, qtx, companyID, userID)
+	if err != nil {
+		return err
+	}
+	current, err := memberRole(ctx, qtx, companyID, memberID)
+	if err != nil {
+		return err
+	}
+	if userID == memberID {
+		if current == "" {
+			return shared.ErrNotMember
+		}
+		if current == shared.RoleOwner {
+			return shared.ErrOwnerMember
+		}
+	} else if err := shared.CheckMemberChange(actor, current); err != nil {
+		return err
+	}
+	err = qtx.DeleteCompanyMember(ctx, database.DeleteCompanyMemberParams{
+		CompanyID: companyID,
+		UserID:    memberID,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// TransferCompany makes a member the owner, the previous owner stays as an
+// admin
+func (mysql *MysqlStorage) TransferCompany(ctx context.Context, userID, companyID, memberID string) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	actor, err := memberRole(ctx, qtx, companyID, userID)
+	if err != nil {
+		return err
+	}
+	if actor != shared.RoleOwner {
+		return shared.ErrNotOwner
+	}
+	current, err := memberRole(ctx, qtx, companyID, memberID)
+	if err != nil {
+		return err
+	}
+	if current == "" {
+		return shared.ErrNotMember
+	}
+	if current == shared.RoleOwner {
+		return tx.Commit()
+	}
+	err = qtx.UpdateCompanyMemberRole(ctx, database.UpdateCompanyMemberRoleParams{
+		Role:      shared.RoleOwner,
+		CompanyID: companyID,
+		UserID:    memberID,
+	})
+	if err != nil {
+		return err
+	}
+	err = qtx.UpdateCompanyMemberRole(ctx, database.UpdateCompanyMemberRoleParams{
+		Role:      shared.RoleAdmin,
+		CompanyID: companyID,
+		UserID:    userID,
+	})
+	if err != nil {
+		return err
+	}
+	err = qtx.UpdateCompanyOwner(ctx, database.UpdateCompanyOwnerParams{
+		UserID: memberID,
+		ID:     companyID,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SelectUserInvitations are the invitations sent to the nick or the email
+// of the user that they didn't answer
+func (mysql *MysqlStorage) SelectUserInvitations(ctx context.Context, userID string) ([]shared.CompanyInvitation, error) {
+	rows, err := mysql.Queries.SelectUserInvitations(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	invitations := []shared.CompanyInvitation{}
+	for _, row := range rows {
+		invitations = append(invitations, shared.CompanyInvitation{
+			ID:              row.ID,
+			CompanyID:       row.CompanyID,
+			CompanyName:     row.CompanyName,
+			CompanyImageURL: row.CompanyImageUrl,
+			Role:            row.Role,
+			InvitedBy:       row.InvitedBy,
+			CreatedAt:       row.CreatedAt,
+		})
+	}
+	return invitations, nil
+}
+
+func (mysql *MysqlStorage) SelectUserMemberships(ctx context.Context, userID string) ([]shared.Membership, error) {
+	rows, err := mysql.Queries.SelectUserMemberships(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	memberships := []shared.Membership{}
+	for _, row := range rows {
+		memberships = append(memberships, shared.Membership{
+			CompanyID:       row.CompanyID,
+			CompanyName:     row.CompanyName,
+			CompanyImageURL: row.CompanyImageUrl,
+			Role:            row.Role,
+		})
+	}
+	return memberships, nil
+}
+
+// AnswerInvitation adds the user to the team with the role of the
+// invitation or keeps it as declined
+func (mysql *MysqlStorage) AnswerInvitation(ctx context.Context, userID, invitationID string, accept bool) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	invitation, err := qtx.SelectUserInvitation(ctx, database.SelectUserInvitationParams{
+		ID:   invitationID,
+		ID_2: userID,
+	})
+	if err != nil {
+		return err
+	}
+	if !accept {
+		err = qtx.DeclineCompanyInvitation(ctx, database.DeclineCompanyInvitationParams{
+			DeclinedAt: nullTime(time.Now()),
+			ID:         invitation.ID,
+		})
+		if err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+	err = qtx.InsertCompanyMember(ctx, database.InsertCompanyMemberParams{
+		CompanyID: invitation.CompanyID,
+		UserID:    userID,
+		Role:      invitation.Role,
+	})
+	if err != nil {
+		return err
+	}
+	err = qtx.DeleteCompanyInvitation(ctx, database.DeleteCompanyInvitationParams{
+		ID:        invitation.ID,
+		CompanyID: invitation.CompanyID,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
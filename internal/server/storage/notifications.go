@@ -0,0 +1,325 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const notificationListSize = 20
+
+func toNotification(dbNotification database.Notification) shared.Notification {
+	return shared.Notification{
+		ID:        dbNotification.ID,
+		UserID:    dbNotification.UserID,
+		Category:  dbNotification.Category,
+		Title:     dbNotification.Title,
+		Link:      dbNotification.Link,
+		CreatedAt: dbNotification.CreatedAt,
+		Read:      dbNotification.ReadAt.Valid,
+	}
+}
+
+func (mysql *MysqlStorage) SelectNotifications(ctx context.Context, userID string) ([]shared.Notification, error) {
+	dbNotifications, err := mysql.Queries.SelectUserNotifications(ctx, database.SelectUserNotificationsParams{
+		UserID: userID,
+		Limit:  notificationListSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	notifications := []shared.Notification{}
+	for _, dbNotification := range dbNotifications {
+		notifications = append(notifications, toNotification(dbNotification))
+	}
+	return notifications, nil
+}
+
+func (mysql *MysqlStorage) CountUnreadNotifications(ctx context.Context, userID string) (int, error) {
+	count, err := mysql.Queries.CountUnreadNotifications(ctx, userID)
+	return int(count), err
+}
+
+// ReadNotification marks a notification of the user as read and returns it
+// so the user can be sent to its link
+func (mysql *MysqlStorage) ReadNotification(ctx context.Context, userID, notificationID string) (shared.Notification, error) {
+	dbNotification, err := mysql.Queries.SelectUserNotification(ctx, database.SelectUserNotificationParams{
+		ID:     notificationID,
+		UserID: userID,
+	})
+	if err != nil {
+		return shared.Notification{}, err
+	}
+	err = mysql.Queries.MarkNotificationRead(ctx, database.MarkNotificationReadParams{
+		ReadAt: nullTime(time.Now()),
+		ID:     notificationID,
+		UserID: userID,
+	})
+	if err != nil {
+		return shared.Notification{}, err
+	}
+	return toNotification(dbNotification), nil
+}
+
+func (mysql *MysqlStorage) ReadNotifications(ctx context.Context, userID string) error {
+	return mysql.Queries.MarkNotificationsRead(ctx, database.MarkNotificationsReadParams{
+		ReadAt: nullTime(time.Now()),
+		UserID: userID,
+	})
+}
+
+func (mysql *MysqlStorage) SelectNotificationPreferences(ctx context.Context, userID string) ([]shared.NotificationPreference, error) {
+	dbPreferences, err := mysql.Queries.SelectNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	saved := []shared.NotificationPreference{}
+	for _, dbPreference := range dbPreferences {
+		saved = append(saved, shared.NotificationPreference{
+			Category: dbPreference.Category,
+			InApp:    dbPreference.InApp,
+			Email:    dbPreference.Email,
+		})
+	}
+	return shared.NotificationPreferences(saved), nil
+}
+
+func (mysql *MysqlStorage) UpdateNotificationPreferences(ctx context.Context, userID string, preferences []shared.NotificationPreference) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	for _, preference := range preferences {
+		err = qtx.UpsertNotificationPreference(ctx, database.UpsertNotificationPreferenceParams{
+			UserID:   userID,
+			Category: preference.Category,
+			InApp:    preference.InApp,
+			Email:    preference.Email,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (mysql *MysqlStorage) notificationPreference(ctx context.Context, userID, category string) (shared.NotificationPreference, error) {
+	dbPreference, err := mysql.Queries.SelectNotificationPreference(ctx, database.SelectNotificationPreferenceParams{
+		UserID:   userID,
+		Category: category,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return shared.DefaultNotificationPreference(category), nil
+	}
+	if err != nil {
+		return shared.NotificationPreference{}, err
+	}
+	return shared.NotificationPreference{
+		Category: dbPreference.Category,
+		InApp:    dbPreference.InApp,
+		Email:    dbPreference.Email,
+	}, nil
+}
+
+// InsertNotifications writes a notification of the event for every
+// recipient under their preference, the ones only sent by email at once
+// are returned without a row
+func (mysql *MysqlStorage) InsertNotifications(ctx context.Context, event shared.NotificationEvent) ([]shared.Notification, error) {
+	drafts, err := mysql.notificationDrafts(ctx, event)
+	if err != nil {
+		return nil, err
+	}
+	notifications := []shared.Notification{}
+	for _, draft := range drafts {
+		preference, err := mysql.notificationPreference(ctx, draft.UserID, event.Category)
+		if err != nil {
+			return nil, err
+		}
+		if preference.Muted() {
+			continue
+		}
+		draft.ID = uuid.New().String()
+		draft.Category = event.Category
+		draft.CreatedAt = time.Now()
+		draft.Preference = preference
+		notifications = append(notifications, draft)
+	}
+	if len(notifications) == 0 {
+		return notifications, nil
+	}
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	for _, notification := range notifications {
+		digest := notification.Preference.Email == shared.EmailDigest
+		if !notification.Preference.InApp && !digest {
+			continue
+		}
+		err = qtx.InsertNotification(ctx, database.InsertNotificationParams{
+			ID:            notification.ID,
+			Category:      notification.Category,
+			Title:         notification.Title,
+			Link:          notification.Link,
+			InApp:         notification.Preference.InApp,
+			DigestPending: digest,
+			UserID:        notification.UserID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return notifications, tx.Commit()
+}
+
+// notificationDrafts returns the recipients of the event with the text
+// they get, no recipients means there is nobody to tell
+func (mysql *MysqlStorage) notificationDrafts(ctx context.Context, event shared.NotificationEvent) ([]shared.Notification, error) {
+	if event.Category == shared.NotifyArchived {
+		rows, err := mysql.Queries.SelectOfferApplicantsNotification(ctx, event.OfferID)
+		if err != nil {
+			return nil, err
+		}
+		drafts := []shared.Notification{}
+		seen := map[string]bool{}
+		for _, row := range rows {
+			if seen[row.UserID] {
+				continue
+			}
+			seen[row.UserID] = true
+			drafts = append(drafts, shared.Notification{
+				UserID: row.UserID,
+				Email:  row.Email,
+				Title:  fmt.Sprintf("La oferta «%s» fue archivada", row.OfferTitle),
+				Link:   "/applications",
+			})
+		}
+		return drafts, nil
+	}
+	if event.Category == shared.NotifyTeam {
+		rows, err := mysql.Queries.SelectInvitationNotification(ctx, event.InvitationID)
+		if err != nil {
+			return nil, err
+		}
+		drafts := []shared.Notification{}
+		for _, row := range rows {
+			drafts = append(drafts, shared.Notification{
+				UserID: row.UserID,
+				Email:  row.Email,
+				Title: fmt.Sprintf("Te invitaron al equipo de «%s» como %s",
+					row.CompanyName, shared.MemberRoleLabels[row.Role]),
+				Link: "/memberships",
+			})
+		}
+		return drafts, nil
+	}
+	participationID := event.ParticipationID
+	if participationID == "" {
+		participation, err := mysql.Queries.ParticipationStatus(ctx, database.ParticipationStatusParams{
+			UserID: event.UserID,
+			QuizID: event.QuizID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		participationID = participation.ID
+	}
+	row, err := mysql.Queries.SelectParticipationNotification(ctx, participationID)
+	if err != nil {
+		return nil, err
+	}
+	switch event.Category {
+	case shared.NotifyResults:
+		return []shared.Notification{{
+			UserID: row.UserID,
+			Email:  row.Email,
+			Title:  fmt.Sprintf("Tus resultados de «%s» están listos", row.OfferTitle),
+			Link:   "/applications",
+		}}, nil
+	case shared.NotifyApplicant:
+		// the members who review applicants are told under the same rules
+		// the applicant list follows
+		name := row.Name
+		if row.RevealStage.Valid && !row.RevealedStage.Valid {
+			name = shared.Pseudonym(mysql.PseudonymKey, row.OfferID, row.UserID)
+		}
+		members, err := mysql.Queries.SelectOfferTeamNotification(ctx, row.OfferID)
+		if err != nil {
+			return nil, err
+		}
+		drafts := []shared.Notification{}
+		for _, member := range members {
+			drafts = append(drafts, shared.Notification{
+				UserID: member.UserID,
+				Email:  member.Email,
+				Title:  fmt.Sprintf("%s postuló a «%s»", name, row.OfferTitle),
+				Link:   "/offers/admin/" + row.OfferID,
+			})
+		}
+		return drafts, nil
+	case shared.NotifyStatus:
+		if !row.ShareStage {
+			return nil, nil
+		}
+		stages, err := mysql.SelectOfferStages(ctx, row.OfferID)
+		if err != nil {
+			return nil, err
+		}
+		for _, stage := range stages {
+			if stage.Name == event.Stage {
+				return []shared.Notification{{
+					UserID: row.UserID,
+					Email:  row.Email,
+					Title:  fmt.Sprintf("Tu postulación a «%s» pasó a %s", row.OfferTitle, stage.Label),
+					Link:   "/applications",
+				}}, nil
+			}
+		}
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown notification category: %s", event.Category)
+}
+
+// SelectDigests groups the notifications waiting for the daily email by
+// user
+func (mysql *MysqlStorage) SelectDigests(ctx context.Context) ([]shared.Digest, error) {
+	rows, err := mysql.Queries.SelectPendingDigests(ctx)
+	if err != nil {
+		return nil, err
+	}
+	digests := []shared.Digest{}
+	for _, row := range rows {
+		if len(digests) == 0 || digests[len(digests)-1].UserID != row.UserID {
+			digests = append(digests, shared.Digest{UserID: row.UserID, Name: row.Name, Email: row.Email})
+		}
+		digest := &digests[len(digests)-1]
+		digest.Notifications = append(digest.Notifications, shared.Notification{
+			ID:        row.ID,
+			UserID:    row.UserID,
+			Title:     row.Title,
+			Link:      row.Link,
+			CreatedAt: row.CreatedAt,
+		})
+		digest.Until = row.CreatedAt
+	}
+	return digests, nil
+}
+
+// ClearDigest leaves out of the next digest the notifications it sent,
+// the ones written meanwhile wait for the next day
+func (mysql *MysqlStorage) ClearDigest(ctx context.Context, digest shared.Digest) error {
+	return mysql.Queries.ClearNotificationDigest(ctx, database.ClearNotificationDigestParams{
+		UserID:    digest.UserID,
+		CreatedAt: digest.Until,
+	})
+}
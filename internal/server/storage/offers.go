@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/kw3a/spotted-server/internal/database"
 	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/kw3a/spotted-server/internal/server/sqljudge"
 )
 
 const (
@@ -45,10 +46,14 @@ func (mysql *MysqlStorage) SelectFullProblems(ctx context.Context, quizID string
 	testCaseMap := make(map[string][]shared.TestCase)
 	for _, tc := range dbTestCases {
 		tcStruct := shared.TestCase{
-			ID:        tc.ID,
-			Input:     tc.Input,
-			Output:    tc.Output,
-			ProblemID: tc.ProblemID,
+			ID:         tc.ID,
+			Input:      tc.Input,
+			Output:     tc.Output,
+			ProblemID:  tc.ProblemID,
+			InputHash:  tc.InputHash,
+			OutputHash: tc.OutputHash,
+			InputSize:  tc.InputSize,
+			OutputSize: tc.OutputSize,
 		}
 		testCaseMap[tc.ProblemID] = append(testCaseMap[tc.ProblemID], tcStruct)
 	}
@@ -68,14 +73,29 @@ func (mysql *MysqlStorage) SelectFullProblems(ctx context.Context, quizID string
 	// Step 7: Build final problem slice
 	res := make([]shared.Problem, 0, len(dbProblems))
 	for _, p := range dbProblems {
+		db, err := selectDatabase(ctx, mysql.Queries, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		question, err := selectQuestion(ctx, mysql.Queries, p.ID)
+		if err != nil {
+			return nil, err
+		}
 		prob := shared.Problem{
-			ID:          p.ID,
-			Title:       p.Title,
-			Description: p.Description,
-			MemoryLimit: p.MemoryLimit,
-			TimeLimit:   p.TimeLimit,
-			TestCases:   testCaseMap[p.ID],
-			Examples:    exampleMap[p.ID],
+			ID:            p.ID,
+			Title:         p.Title,
+			Description:   p.Description,
+			MemoryLimit:   p.MemoryLimit,
+			TimeLimit:     p.TimeLimit,
+			TestCases:     testCaseMap[p.ID],
+			Examples:      exampleMap[p.ID],
+			BankProblemID: p.BankProblemID,
+			Version:       p.Version,
+			Signature:     p.Signature,
+			Database:      db,
+			Question:      question,
+			Difficulty:    p.Difficulty,
+			Tag:           p.Tag,
 		}
 		res = append(res, prob)
 	}
@@ -120,6 +140,31 @@ func (mysql *MysqlStorage) SelectApplications(ctx context.Context, quizID string
 		resultsBySubmission[result.SubmissionID] = append(resultsBySubmission[result.SubmissionID], result)
 	}
 
+	proctoringByParticipation, err := mysql.batchProctoringCounts(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustmentsByParticipation, err := mysql.batchAdjustments(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	responsesByParticipation, err := mysql.batchResponses(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	problemsByParticipation, err := mysql.batchDrawnProblems(ctx, quizID, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	pipelinesByParticipation, err := mysql.batchPipelines(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	finalApplications := []shared.Application{}
 	for _, application := range applications {
 		participationID := application.Participation.ID
@@ -127,7 +172,11 @@ func (mysql *MysqlStorage) SelectApplications(ctx context.Context, quizID string
 		for i := range summaries {
 			summaries[i].Results = resultsBySubmission[summaries[i].Submission.ID]
 		}
-		application.Summary = summaries
+		application.Summary = append(summaries, responsesByParticipation[participationID]...)
+		application.Proctoring = proctoringByParticipation[participationID]
+		application.Adjustments = adjustmentsByParticipation[participationID]
+		application.Problems = problemsByParticipation[participationID]
+		application.Pipeline = pipelinesByParticipation[participationID]
 		finalApplications = append(finalApplications, application)
 	}
 
@@ -153,6 +202,8 @@ func (mysql *MysqlStorage) selectApplicants(ctx context.Context, quizID string)
 				ID:           apl.ParticipationID,
 				CreatedAt:    apl.ParticipationCreatedAt.Time,
 				ExpiresAt:    apl.ParticipationExpiresAt,
+				EndReason:    apl.ParticipationEndReason,
+				PausedAt:     apl.ParticipationPausedAt.Time,
 				RelativeTime: RelativeTime(apl.ParticipationExpiresAt),
 			},
 		}
@@ -182,7 +233,7 @@ func (mysql *MysqlStorage) batchSummary(
 				Src:             s.Src,
 				LanguageID:      s.LanguageID,
 				Language:        s.DisplayName,
-				ProblemID:       s.ProblemID,
+				ProblemID:       judgedProblem(s.ProblemID, s.RejudgedProblemID),
 				ParticipationID: s.ParticipationID,
 			},
 		}
@@ -218,10 +269,37 @@ func (mysql *MysqlStorage) batchTCResults(
 }
 
 func (mysql *MysqlStorage) ArchiveOffer(ctx context.Context, offerID string, ownerID string) error {
-	return mysql.Queries.ArchiveOffer(ctx, database.ArchiveOfferParams{
+	return mysql.UpdateOfferStatus(ctx, offerID, ownerID, shared.OfferArchived)
+}
+
+// UpdateOfferStatus moves the offer of the owner to status, only the
+// transitions of the lifecycle are allowed
+func (mysql *MysqlStorage) UpdateOfferStatus(ctx context.Context, offerID, ownerID, status string) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	current, err := qtx.SelectOfferStatusByUser(ctx, database.SelectOfferStatusByUserParams{
 		ID:     offerID,
 		UserID: ownerID,
 	})
+	if err != nil {
+		return err
+	}
+	if !shared.CanTransition(current.Status, status) {
+		return fmt.Errorf("%w: %s a %s", shared.ErrOfferTransition,
+			shared.OfferStatusLabels[current.Status], shared.OfferStatusLabels[status])
+	}
+	err = qtx.UpdateOfferStatus(ctx, database.UpdateOfferStatusParams{
+		Status: status,
+		ID:     offerID,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (mysql *MysqlStorage) RegisterOffer(
@@ -231,7 +309,11 @@ func (mysql *MysqlStorage) RegisterOffer(
 	quizID string,
 	quiz shared.Quiz,
 	problems []shared.Problem,
+	bankProblemIDs []string,
 ) error {
+	if err := mysql.prepareTestData(ctx, problems); err != nil {
+		return err
+	}
 	tx, err := mysql.db.Begin()
 	if err != nil {
 		return err
@@ -247,19 +329,35 @@ func (mysql *MysqlStorage) RegisterOffer(
 		MinWage:      offer.MinWage,
 		MaxWage:      offer.MaxWage,
 		CompanyID:    offer.CompanyID,
+		Status:       offer.Status,
+		Location:     offer.Location,
+		Modality:     offer.Modality,
+		Seniority:    offer.Seniority,
+		Contract:     offer.Contract,
+		Currency:     offer.Currency,
+		WagePeriod:   offer.WagePeriod,
 	})
-
 	if err != nil {
 		return fmt.Errorf("error inserting offer: %w", err)
 	}
+	if err := insertOfferTags(ctx, qtx, offerID, offer.Tags); err != nil {
+		return err
+	}
+	if err := upsertOfferDocument(ctx, qtx, offerID, offer); err != nil {
+		return err
+	}
 	err = qtx.InsertQuiz(ctx, database.InsertQuizParams{
 		ID:       quizID,
 		Duration: quiz.Duration,
 		OfferID:  offerID,
+		Shuffle:  quiz.Shuffle,
 	})
 	if err != nil {
 		return fmt.Errorf("error inserting quiz: %w", err)
 	}
+	if err := insertPools(ctx, qtx, quizID, quiz.Pools); err != nil {
+		return err
+	}
 	for _, lang := range quiz.Languages {
 		err = qtx.InsertLanguageQuiz(ctx, database.InsertLanguageQuizParams{
 			ID:         uuid.New().String(),
@@ -270,45 +368,154 @@ func (mysql *MysqlStorage) RegisterOffer(
 			return fmt.Errorf("error inserting language quiz: %w", err)
 		}
 	}
+	// Problems written inline are added to the company library so they can
+	// be reused by later offers
+	problemIDs := []string{}
 	for _, problem := range problems {
-		problemID := uuid.New().String()
-		err = qtx.InsertProblem(ctx, database.InsertProblemParams{
-			ID:          problemID,
-			QuizID:      quizID,
-			Title:       problem.Title,
-			Description: problem.Description,
-			TimeLimit:   problem.TimeLimit,
-			MemoryLimit: 262144,
+		bankProblemID := uuid.New().String()
+		err = qtx.InsertBankProblem(ctx, database.InsertBankProblemParams{
+			ID:        bankProblemID,
+			CompanyID: offer.CompanyID,
 		})
 		if err != nil {
-			return fmt.Errorf("error inserting problem: %w", err)
+			return fmt.Errorf("error inserting bank problem: %w", err)
 		}
-		for _, tc := range problem.TestCases {
-			err = qtx.InsertTestCase(ctx, database.InsertTestCaseParams{
-				ID:        uuid.New().String(),
-				ProblemID: problemID,
-				Input:     tc.Input,
-				Output:    tc.Output,
-			})
-			if err != nil {
-				return fmt.Errorf("error inserting test case: %w", err)
-			}
+		problemID, err := insertProblemVersion(ctx, qtx, bankProblemID, 1, problem)
+		if err != nil {
+			return err
+		}
+		problemIDs = append(problemIDs, problemID)
+	}
+	if len(bankProblemIDs) > 0 {
+		latest, err := qtx.SelectLatestVersions(ctx, database.SelectLatestVersionsParams{
+			CompanyID:      offer.CompanyID,
+			BankProblemIds: bankProblemIDs,
+		})
+		if err != nil {
+			return err
+		}
+		versions := make(map[string]string)
+		for _, version := range latest {
+			versions[version.BankProblemID] = version.ID
 		}
-		for _, example := range problem.Examples {
-			err = qtx.InsertExample(ctx, database.InsertExampleParams{
-				ID:        uuid.New().String(),
-				ProblemID: problemID,
-				Input:     example.Input,
-				Output:    example.Output,
-			})
-			if err != nil {
-				return fmt.Errorf("error inserting example: %w", err)
+		for _, bankProblemID := range bankProblemIDs {
+			problemID, ok := versions[bankProblemID]
+			if !ok {
+				return shared.ErrBankProblemNotFound
 			}
+			// Library problems can't be published while their reference
+			// solutions are running or contradict the test cases
+			if err := checkReferenceSolutions(ctx, qtx, problemID); err != nil {
+				return err
+			}
+			problemIDs = append(problemIDs, problemID)
+		}
+	}
+	for position, problemID := range problemIDs {
+		err = qtx.InsertQuizProblem(ctx, database.InsertQuizProblemParams{
+			QuizID:    quizID,
+			ProblemID: problemID,
+			Position:  shared.IntToInt32(position),
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting quiz problem: %w", err)
 		}
 	}
+	// Library problems bring their own tags and difficulties, the pools
+	// are checked once every problem is in the quiz
+	candidates, err := selectPoolProblems(ctx, qtx, quizID)
+	if err != nil {
+		return err
+	}
+	if err := shared.CheckPools(candidates, quiz.Pools); err != nil {
+		return err
+	}
 	return tx.Commit()
 }
 
+func insertProblemVersion(
+	ctx context.Context,
+	qtx *database.Queries,
+	bankProblemID string,
+	version int32,
+	problem shared.Problem,
+) (string, error) {
+	problemID := uuid.New().String()
+	memoryLimit := problem.MemoryLimit
+	if memoryLimit == 0 {
+		memoryLimit = 262144
+	}
+	err := qtx.InsertProblem(ctx, database.InsertProblemParams{
+		ID:            problemID,
+		BankProblemID: bankProblemID,
+		Version:       version,
+		Title:         problem.Title,
+		Description:   problem.Description,
+		TimeLimit:     problem.TimeLimit,
+		MemoryLimit:   memoryLimit,
+		Signature:     problem.Signature,
+		Difficulty:    shared.DifficultyWeight(problem.Difficulty),
+		Tag:           problem.Tag,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error inserting problem: %w", err)
+	}
+	for _, tc := range problem.TestCases {
+		err = qtx.InsertTestCase(ctx, database.InsertTestCaseParams{
+			ID:         uuid.New().String(),
+			ProblemID:  problemID,
+			Input:      tc.Input,
+			Output:     tc.Output,
+			InputHash:  tc.InputHash,
+			OutputHash: tc.OutputHash,
+			InputSize:  tc.InputSize,
+			OutputSize: tc.OutputSize,
+		})
+		if err != nil {
+			return "", fmt.Errorf("error inserting test case: %w", err)
+		}
+	}
+	for _, example := range problem.Examples {
+		err = qtx.InsertExample(ctx, database.InsertExampleParams{
+			ID:        uuid.New().String(),
+			ProblemID: problemID,
+			Input:     example.Input,
+			Output:    example.Output,
+		})
+		if err != nil {
+			return "", fmt.Errorf("error inserting example: %w", err)
+		}
+	}
+	for _, checker := range problem.Checker {
+		err = qtx.InsertChecker(ctx, database.InsertCheckerParams{
+			ID:        uuid.New().String(),
+			ProblemID: problemID,
+			Path:      checker.Path,
+			Src:       checker.Src,
+		})
+		if err != nil {
+			return "", fmt.Errorf("error inserting checker: %w", err)
+		}
+	}
+	if problem.Database != nil {
+		err = qtx.InsertProblemDatabase(ctx, database.InsertProblemDatabaseParams{
+			ProblemID: problemID,
+			SchemaSql: problem.Database.Schema,
+			SeedSql:   problem.Database.Seed,
+			Ordered:   problem.Database.Ordered,
+		})
+		if err != nil {
+			return "", fmt.Errorf("error inserting problem database: %w", err)
+		}
+	}
+	if problem.Question != nil {
+		if err := insertQuestion(ctx, qtx, problemID, *problem.Question); err != nil {
+			return "", err
+		}
+	}
+	return problemID, nil
+}
+
 func (mysql *MysqlStorage) SelectOfferByUser(ctx context.Context, ID string, userID string) (shared.Offer, error) {
 	dbQuiz, err := mysql.Queries.GetOfferByUser(ctx, database.GetOfferByUserParams{
 		ID:   ID,
@@ -318,18 +525,33 @@ func (mysql *MysqlStorage) SelectOfferByUser(ctx context.Context, ID string, use
 		return shared.Offer{}, err
 	}
 	relativeTime := RelativeTime(dbQuiz.CreatedAt)
-	return shared.Offer{
+	offer := shared.Offer{
 		ID:              dbQuiz.ID,
 		Title:           dbQuiz.Title,
 		About:           dbQuiz.About,
+		Requirements:    dbQuiz.Requirements,
+		Benefits:        dbQuiz.Benefits,
 		Status:          dbQuiz.Status,
 		CompanyName:     dbQuiz.CompanyName,
 		CompanyID:       dbQuiz.CompanyID,
 		CompanyImageURL: dbQuiz.CompanyImageUrl,
 		MinWage:         dbQuiz.MinWage,
 		MaxWage:         dbQuiz.MaxWage,
+		Location:        dbQuiz.Location,
+		Modality:        dbQuiz.Modality,
+		Seniority:       dbQuiz.Seniority,
+		Contract:        dbQuiz.Contract,
+		Currency:        dbQuiz.Currency,
+		WagePeriod:      dbQuiz.WagePeriod,
 		RelativeTime:    relativeTime,
-	}, nil
+		MemberRole:      dbQuiz.MemberRole,
+	}
+	tags, err := mysql.selectOffersTags(ctx, []string{offer.ID})
+	if err != nil {
+		return shared.Offer{}, err
+	}
+	offer.Tags = tags[offer.ID]
+	return offer, nil
 }
 
 func (mysql *MysqlStorage) GetLanguages(ctx context.Context) ([]shared.Language, error) {
@@ -339,6 +561,10 @@ func (mysql *MysqlStorage) GetLanguages(ctx context.Context) ([]shared.Language,
 	}
 	res := []shared.Language{}
 	for _, lang := range languages {
+		// SQL problems pick the language themselves, quizzes can't offer it
+		if lang.ID == sqljudge.LanguageID {
+			continue
+		}
 		res = append(res, shared.Language{
 			ID:          lang.ID,
 			Name:        lang.Name,
@@ -354,7 +580,7 @@ func (mysql *MysqlStorage) SelectOffer(ctx context.Context, ID string) (shared.O
 		return shared.Offer{}, err
 	}
 	relativeTime := RelativeTime(dbQuiz.CreatedAt)
-	return shared.Offer{
+	offer := shared.Offer{
 		ID:              dbQuiz.ID,
 		Title:           dbQuiz.Title,
 		About:           dbQuiz.About,
@@ -366,73 +592,26 @@ func (mysql *MysqlStorage) SelectOffer(ctx context.Context, ID string) (shared.O
 		CompanyImageURL: dbQuiz.CompanyImageUrl,
 		MinWage:         dbQuiz.MinWage,
 		MaxWage:         dbQuiz.MaxWage,
+		Location:        dbQuiz.Location,
+		Modality:        dbQuiz.Modality,
+		Seniority:       dbQuiz.Seniority,
+		Contract:        dbQuiz.Contract,
+		Currency:        dbQuiz.Currency,
+		WagePeriod:      dbQuiz.WagePeriod,
 		RelativeTime:    relativeTime,
-	}, nil
-}
-
-func (mysql *MysqlStorage) GetOffersByCompany(ctx context.Context, companyID string, page int32) ([]shared.Offer, error) {
-	offers := []shared.Offer{}
-	dbOffers, err := mysql.Queries.GetOffersByCompany(ctx, database.GetOffersByCompanyParams{
-		ID:     companyID,
-		Limit:  offerPageSize,
-		Offset: (page - 1) * offerPageSize,
-	})
-	if err != nil {
-		return nil, err
-	}
-	for _, dbOffer := range dbOffers {
-		relativeTime := RelativeTime(dbOffer.CreatedAt)
-		offers = append(offers, shared.Offer{
-			ID:              dbOffer.ID,
-			Title:           dbOffer.Title,
-			About:           dbOffer.About,
-			Requirements:    dbOffer.Requirements,
-			Benefits:        dbOffer.Benefits,
-			Status:          dbOffer.Status,
-			CompanyName:     dbOffer.CompanyName,
-			CompanyID:       dbOffer.CompanyID,
-			CompanyImageURL: dbOffer.CompanyImageUrl,
-			MinWage:         dbOffer.MinWage,
-			MaxWage:         dbOffer.MaxWage,
-			RelativeTime:    relativeTime,
-		})
 	}
-	return offers, nil
-}
-
-func (mysql *MysqlStorage) GetOffers(ctx context.Context, page int32) ([]shared.Offer, error) {
-	offers := []shared.Offer{}
-	dbOffers, err := mysql.Queries.GetOffers(ctx, database.GetOffersParams{
-		Limit:  offerPageSize,
-		Offset: (page - 1) * offerPageSize,
-	})
+	tags, err := mysql.selectOffersTags(ctx, []string{offer.ID})
 	if err != nil {
-		return nil, err
-	}
-	for _, dbOffer := range dbOffers {
-		relativeTime := RelativeTime(dbOffer.CreatedAt)
-		offers = append(offers, shared.Offer{
-			ID:              dbOffer.ID,
-			Title:           dbOffer.Title,
-			About:           dbOffer.About,
-			Requirements:    dbOffer.Requirements,
-			Benefits:        dbOffer.Benefits,
-			Status:          dbOffer.Status,
-			CompanyName:     dbOffer.CompanyName,
-			CompanyID:       dbOffer.CompanyID,
-			CompanyImageURL: dbOffer.CompanyImageUrl,
-			MinWage:         dbOffer.MinWage,
-			MaxWage:         dbOffer.MaxWage,
-			RelativeTime:    relativeTime,
-		})
+		return shared.Offer{}, err
 	}
-	return offers, nil
+	offer.Tags = tags[offer.ID]
+	return offer, nil
 }
 
-func (mysql *MysqlStorage) GetOffersByQuery(ctx context.Context, query string, page int32) ([]shared.Offer, error) {
+func (mysql *MysqlStorage) GetOffersByCompany(ctx context.Context, companyID string, page int32) ([]shared.Offer, error) {
 	offers := []shared.Offer{}
-	dbOffers, err := mysql.Queries.GetOffersByQuery(ctx, database.GetOffersByQueryParams{
-		CONCAT: query,
+	dbOffers, err := mysql.Queries.GetOffersByCompany(ctx, database.GetOffersByCompanyParams{
+		ID:     companyID,
 		Limit:  offerPageSize,
 		Offset: (page - 1) * offerPageSize,
 	})
@@ -453,6 +632,12 @@ func (mysql *MysqlStorage) GetOffersByQuery(ctx context.Context, query string, p
 			CompanyImageURL: dbOffer.CompanyImageUrl,
 			MinWage:         dbOffer.MinWage,
 			MaxWage:         dbOffer.MaxWage,
+			Location:        dbOffer.Location,
+			Modality:        dbOffer.Modality,
+			Seniority:       dbOffer.Seniority,
+			Contract:        dbOffer.Contract,
+			Currency:        dbOffer.Currency,
+			WagePeriod:      dbOffer.WagePeriod,
 			RelativeTime:    relativeTime,
 		})
 	}
@@ -486,7 +671,14 @@ func (mysql *MysqlStorage) GetOffersByUser(ctx context.Context, userID string, p
 			CompanyImageURL: offer.CompanyImageUrl,
 			MinWage:         offer.MinWage,
 			MaxWage:         offer.MaxWage,
+			Location:        offer.Location,
+			Modality:        offer.Modality,
+			Seniority:       offer.Seniority,
+			Contract:        offer.Contract,
+			Currency:        offer.Currency,
+			WagePeriod:      offer.WagePeriod,
 			RelativeTime:    relativeTime,
+			MemberRole:      offer.MemberRole,
 		})
 	}
 	return res, nil
@@ -518,6 +710,12 @@ func (mysql *MysqlStorage) SelectParticipatedOffers(ctx context.Context, userID
 			CompanyImageURL: dbOffer.CompanyImageUrl,
 			MinWage:         dbOffer.MinWage,
 			MaxWage:         dbOffer.MaxWage,
+			Location:        dbOffer.Location,
+			Modality:        dbOffer.Modality,
+			Seniority:       dbOffer.Seniority,
+			Contract:        dbOffer.Contract,
+			Currency:        dbOffer.Currency,
+			WagePeriod:      dbOffer.WagePeriod,
 			RelativeTime:    RelativeTime(dbOffer.CreatedAt),
 		}
 		offers = append(offers, offer)
@@ -526,16 +724,10 @@ func (mysql *MysqlStorage) SelectParticipatedOffers(ctx context.Context, userID
 }
 
 func (mysql *MysqlStorage) SelectOffers(ctx context.Context, params shared.OfferQueryParams) ([]shared.Offer, error) {
-	if params.Query != "" {
-		return mysql.GetOffersByQuery(ctx, params.Query, params.Page)
-	}
 	if params.CompanyID != "" {
 		return mysql.GetOffersByCompany(ctx, params.CompanyID, params.Page)
 	}
-	if params.UserID != "" {
-		return mysql.GetOffersByUser(ctx, params.UserID, params.Page)
-	}
-	return mysql.GetOffers(ctx, params.Page)
+	return mysql.GetOffersByUser(ctx, params.UserID, params.Page)
 }
 
 func RelativeTime(t time.Time) string {
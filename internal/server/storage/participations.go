@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// AdjustParticipation applies a recruiter adjustment to a participation of
+// one of the recruiter's offers and records it in the audit trail
+func (mysql *MysqlStorage) AdjustParticipation(
+	ctx context.Context,
+	recruiterID string,
+	participationID string,
+	adjustment shared.Adjustment,
+) (shared.Participation, error) {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return shared.Participation{}, err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	dbParticipation, err := qtx.SelectParticipationByRecruiter(ctx, database.SelectParticipationByRecruiterParams{
+		ID:     participationID,
+		UserID: recruiterID,
+	})
+	if err != nil {
+		return shared.Participation{}, err
+	}
+	current := shared.Participation{
+		ID:         dbParticipation.ID,
+		CreatedAt:  dbParticipation.CreatedAt.Time,
+		ExpiresAt:  dbParticipation.ExpiresAt,
+		FinishedAt: dbParticipation.FinishedAt.Time,
+		EndReason:  dbParticipation.EndReason,
+		PausedAt:   dbParticipation.PausedAt.Time,
+	}
+	reviews, err := qtx.BatchParticipationReviews(ctx, []string{participationID})
+	if err != nil {
+		return shared.Participation{}, err
+	}
+	if len(reviews) > 0 {
+		current.WithdrawnAt = reviews[0].WithdrawnAt.Time
+	}
+	adjusted, err := current.Adjust(adjustment, time.Now())
+	if err != nil {
+		return shared.Participation{}, err
+	}
+	err = qtx.UpdateParticipationTiming(ctx, database.UpdateParticipationTimingParams{
+		ExpiresAt:  adjusted.ExpiresAt,
+		PausedAt:   nullTime(adjusted.PausedAt),
+		FinishedAt: nullTime(adjusted.FinishedAt),
+		EndReason:  adjusted.EndReason,
+		ID:         adjusted.ID,
+	})
+	if err != nil {
+		return shared.Participation{}, err
+	}
+	err = qtx.InsertParticipationAdjustment(ctx, database.InsertParticipationAdjustmentParams{
+		ID:              uuid.NewString(),
+		Action:          adjustment.Action,
+		Minutes:         adjustment.Minutes,
+		Reason:          adjustment.Reason,
+		ParticipationID: adjusted.ID,
+		UserID:          recruiterID,
+	})
+	if err != nil {
+		return shared.Participation{}, err
+	}
+	if err := tx.Commit(); err != nil {
+		return shared.Participation{}, err
+	}
+	adjusted.RelativeTime = RelativeTime(adjusted.ExpiresAt)
+	return adjusted, nil
+}
+
+func (mysql *MysqlStorage) SelectAdjustments(ctx context.Context, participationID string) ([]shared.Adjustment, error) {
+	adjustments, err := mysql.batchAdjustments(ctx, []string{participationID})
+	if err != nil {
+		return nil, err
+	}
+	return adjustments[participationID], nil
+}
+
+func (mysql *MysqlStorage) batchAdjustments(ctx context.Context, participationIDs []string) (map[string][]shared.Adjustment, error) {
+	dbAdjustments, err := mysql.Queries.BatchParticipationAdjustments(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string][]shared.Adjustment)
+	for _, a := range dbAdjustments {
+		res[a.ParticipationID] = append(res[a.ParticipationID], shared.Adjustment{
+			ID:            a.ID,
+			Action:        a.Action,
+			Minutes:       a.Minutes,
+			Reason:        a.Reason,
+			RecruiterName: a.RecruiterName,
+			CreatedAt:     a.CreatedAt,
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,282 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// companyStages maps the configured pipeline, companies that never
+// configured one use the default stages
+func companyStages(dbStages []database.CompanyStage) []shared.Stage {
+	if len(dbStages) == 0 {
+		return shared.DefaultStages
+	}
+	res := make([]shared.Stage, 0, len(dbStages))
+	for _, s := range dbStages {
+		res = append(res, shared.Stage{Name: s.Name, Label: s.Label})
+	}
+	return res
+}
+
+func (mysql *MysqlStorage) SelectCompanyPipeline(ctx context.Context, companyID string) (shared.CompanyPipeline, error) {
+	dbStages, err := mysql.Queries.SelectCompanyStages(ctx, companyID)
+	if err != nil {
+		return shared.CompanyPipeline{}, err
+	}
+	pipeline := shared.CompanyPipeline{Stages: companyStages(dbStages)}
+	settings, err := mysql.Queries.SelectCompanyPipeline(ctx, companyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return pipeline, nil
+	}
+	if err != nil {
+		return shared.CompanyPipeline{}, err
+	}
+	pipeline.ShareStage = settings.ShareStage
+	return pipeline, nil
+}
+
+func (mysql *MysqlStorage) SelectOfferStages(ctx context.Context, offerID string) ([]shared.Stage, error) {
+	dbStages, err := mysql.Queries.SelectOfferStages(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+	return companyStages(dbStages), nil
+}
+
+// UpdateCompanyPipeline replaces the pipeline of a company owned by the
+// user, applicants keep their stage even if it was removed
+func (mysql *MysqlStorage) UpdateCompanyPipeline(ctx context.Context, userID, companyID string, pipeline shared.CompanyPipeline) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	_, err = qtx.SelectCompany(ctx, database.SelectCompanyParams{ID: companyID, UserID: userID})
+	if err != nil {
+		return err
+	}
+	if err := qtx.DeleteCompanyStages(ctx, companyID); err != nil {
+		return err
+	}
+	for i, stage := range pipeline.Stages {
+		err = qtx.InsertCompanyStage(ctx, database.InsertCompanyStageParams{
+			CompanyID: companyID,
+			Name:      stage.Name,
+			Label:     stage.Label,
+			Position:  shared.IntToInt32(i),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	err = qtx.UpsertCompanyPipeline(ctx, database.UpsertCompanyPipelineParams{
+		CompanyID:  companyID,
+		ShareStage: pipeline.ShareStage,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReviewApplicants applies the same review to participations of the
+// recruiter's offers, nothing is saved if any of them fails
+func (mysql *MysqlStorage) ReviewApplicants(
+	ctx context.Context,
+	recruiterID string,
+	participationIDs []string,
+	review shared.Review,
+) error {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	for _, participationID := range participationIDs {
+		_, err := qtx.SelectParticipationByRecruiter(ctx, database.SelectParticipationByRecruiterParams{
+			ID:     participationID,
+			UserID: recruiterID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := reviewApplicant(ctx, qtx, recruiterID, participationID, review); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func reviewApplicant(
+	ctx context.Context,
+	qtx *database.Queries,
+	recruiterID string,
+	participationID string,
+	review shared.Review,
+) error {
+	switch review.Action {
+	case shared.ReviewStage:
+		dbStages, err := qtx.SelectParticipationStages(ctx, participationID)
+		if err != nil {
+			return err
+		}
+		pipeline := shared.Pipeline{Stages: companyStages(dbStages)}
+		if !shared.HasStage(pipeline.Stages, review.Stage) {
+			return shared.ErrStage
+		}
+		reviews, err := qtx.BatchParticipationReviews(ctx, []string{participationID})
+		if err != nil {
+			return err
+		}
+		if len(reviews) > 0 {
+			pipeline.Stage = reviews[0].Stage
+		}
+		from := pipeline.Current()
+		if from == review.Stage {
+			return nil
+		}
+		err = qtx.UpsertParticipationStage(ctx, database.UpsertParticipationStageParams{
+			ParticipationID: participationID,
+			Stage:           review.Stage,
+		})
+		if err != nil {
+			return err
+		}
+		err = qtx.InsertStageChange(ctx, database.InsertStageChangeParams{
+			ID:              uuid.NewString(),
+			FromStage:       from,
+			ToStage:         review.Stage,
+			ParticipationID: participationID,
+			UserID:          recruiterID,
+		})
+		if err != nil {
+			return err
+		}
+		return revealIdentity(ctx, qtx, recruiterID, participationID, review.Stage)
+	case shared.ReviewRating:
+		return qtx.UpsertParticipationRating(ctx, database.UpsertParticipationRatingParams{
+			ParticipationID: participationID,
+			Rating:          review.Stars,
+		})
+	case shared.ReviewNote:
+		return qtx.InsertParticipationNote(ctx, database.InsertParticipationNoteParams{
+			ID:              uuid.NewString(),
+			Body:            review.Body,
+			ParticipationID: participationID,
+			UserID:          recruiterID,
+		})
+	case shared.ReviewTag:
+		count, err := qtx.CountParticipationTags(ctx, participationID)
+		if err != nil {
+			return err
+		}
+		if count >= shared.MaxApplicantTags {
+			return shared.ErrApplicantTags
+		}
+		return qtx.InsertParticipationTag(ctx, database.InsertParticipationTagParams{
+			ParticipationID: participationID,
+			Tag:             review.Tag,
+		})
+	case shared.ReviewUntag:
+		return qtx.DeleteParticipationTag(ctx, database.DeleteParticipationTagParams{
+			ParticipationID: participationID,
+			Tag:             review.Tag,
+		})
+	}
+	return shared.ErrReviewAction
+}
+
+// SelectPipeline loads the review of a single applicant with the stages of
+// the company, used to render it again after a change
+func (mysql *MysqlStorage) SelectPipeline(ctx context.Context, participationID string) (shared.Pipeline, error) {
+	pipelines, err := mysql.batchPipelines(ctx, []string{participationID})
+	if err != nil {
+		return shared.Pipeline{}, err
+	}
+	dbStages, err := mysql.Queries.SelectParticipationStages(ctx, participationID)
+	if err != nil {
+		return shared.Pipeline{}, err
+	}
+	pipeline := pipelines[participationID]
+	pipeline.Stages = companyStages(dbStages)
+	return pipeline, nil
+}
+
+// batchPipelines loads everything but the stages, they are the same for
+// every applicant of an offer
+func (mysql *MysqlStorage) batchPipelines(ctx context.Context, participationIDs []string) (map[string]shared.Pipeline, error) {
+	res := make(map[string]shared.Pipeline)
+	for _, id := range participationIDs {
+		res[id] = shared.Pipeline{ParticipationID: id}
+	}
+	reviews, err := mysql.Queries.BatchParticipationReviews(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reviews {
+		pipeline := res[r.ParticipationID]
+		pipeline.Stage = r.Stage
+		pipeline.Rating = r.Rating
+		pipeline.WithdrawnAt = r.WithdrawnAt.Time
+		res[r.ParticipationID] = pipeline
+	}
+	history, err := mysql.Queries.BatchStageHistory(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range history {
+		pipeline := res[h.ParticipationID]
+		pipeline.History = append(pipeline.History, shared.StageChange{
+			From:          h.FromStage,
+			To:            h.ToStage,
+			RecruiterName: h.RecruiterName,
+			CreatedAt:     h.CreatedAt,
+		})
+		res[h.ParticipationID] = pipeline
+	}
+	notes, err := mysql.Queries.BatchParticipationNotes(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range notes {
+		pipeline := res[n.ParticipationID]
+		pipeline.Notes = append(pipeline.Notes, shared.Note{
+			ID:            n.ID,
+			Body:          n.Body,
+			RecruiterName: n.RecruiterName,
+			CreatedAt:     n.CreatedAt,
+		})
+		res[n.ParticipationID] = pipeline
+	}
+	tags, err := mysql.Queries.BatchParticipationTags(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		pipeline := res[t.ParticipationID]
+		pipeline.Tags = append(pipeline.Tags, t.Tag)
+		res[t.ParticipationID] = pipeline
+	}
+	reveals, err := mysql.Queries.BatchIdentityReveals(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range reveals {
+		pipeline := res[r.ParticipationID]
+		pipeline.Reveal = shared.IdentityReveal{
+			Stage:         r.Stage,
+			Reason:        r.Reason,
+			RecruiterName: r.RecruiterName,
+			CreatedAt:     r.CreatedAt,
+		}
+		res[r.ParticipationID] = pipeline
+	}
+	return res, nil
+}
@@ -0,0 +1,130 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+func insertPools(ctx context.Context, qtx *database.Queries, quizID string, pools []shared.Pool) error {
+	for position, pool := range pools {
+		err := qtx.InsertQuizPool(ctx, database.InsertQuizPoolParams{
+			ID:         uuid.New().String(),
+			QuizID:     quizID,
+			Position:   shared.IntToInt32(position),
+			Tag:        pool.Tag,
+			Difficulty: pool.Difficulty,
+			Draw:       pool.Draw,
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting quiz pool: %w", err)
+		}
+	}
+	return nil
+}
+
+func selectPoolProblems(ctx context.Context, q *database.Queries, quizID string) ([]shared.PoolProblem, error) {
+	rows, err := q.SelectPoolCandidates(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]shared.PoolProblem, len(rows))
+	for i, row := range rows {
+		res[i] = shared.PoolProblem{ID: row.ID, Tag: row.Tag, Difficulty: row.Difficulty}
+	}
+	return res, nil
+}
+
+func selectPools(ctx context.Context, q *database.Queries, quizID string) ([]shared.Pool, error) {
+	rows, err := q.SelectQuizPools(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]shared.Pool, len(rows))
+	for i, row := range rows {
+		res[i] = shared.Pool{Tag: row.Tag, Difficulty: row.Difficulty, Draw: row.Draw}
+	}
+	return res, nil
+}
+
+// drawProblems stores the problems of the participation, the participation
+// ID seeds the draw so drawing again gives the same problems
+func drawProblems(ctx context.Context, qtx *database.Queries, participationID string, quizID string) ([]string, error) {
+	quiz, err := qtx.GetQuiz(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	problems, err := selectPoolProblems(ctx, qtx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	pools, err := selectPools(ctx, qtx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	drawn := shared.Draw(participationID, problems, pools, quiz.Shuffle)
+	for position, problemID := range drawn {
+		err = qtx.InsertParticipationProblem(ctx, database.InsertParticipationProblemParams{
+			ParticipationID: participationID,
+			ProblemID:       problemID,
+			Position:        shared.IntToInt32(position),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error inserting participation problem: %w", err)
+		}
+	}
+	return drawn, nil
+}
+
+// SelectProblemIDs returns the problems drawn for the participation in the
+// order the candidate sees them. Participations started before the quiz had
+// pools are drawn the first time they are read
+func (mysql *MysqlStorage) SelectProblemIDs(ctx context.Context, participationID string, quizID string) ([]string, error) {
+	IDs, err := mysql.Queries.SelectParticipationProblemIDs(ctx, participationID)
+	if err != nil {
+		return nil, err
+	}
+	if len(IDs) == 0 {
+		if IDs, err = drawProblems(ctx, mysql.Queries, participationID, quizID); err != nil {
+			return nil, err
+		}
+	}
+	if len(IDs) == 0 {
+		return nil, errors.New("zero results")
+	}
+	return IDs, nil
+}
+
+// batchDrawnProblems groups the drawn problems by participation, the
+// participations without a draw get every problem of the quiz
+func (mysql *MysqlStorage) batchDrawnProblems(ctx context.Context, quizID string, participationIDs []string) (map[string][]shared.PoolProblem, error) {
+	rows, err := mysql.Queries.BatchParticipationProblems(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string][]shared.PoolProblem)
+	for _, row := range rows {
+		res[row.ParticipationID] = append(res[row.ParticipationID], shared.PoolProblem{
+			ID:         row.ID,
+			Tag:        row.Tag,
+			Difficulty: row.Difficulty,
+		})
+	}
+	var all []shared.PoolProblem
+	for _, participationID := range participationIDs {
+		if _, ok := res[participationID]; ok {
+			continue
+		}
+		if all == nil {
+			if all, err = selectPoolProblems(ctx, mysql.Queries, quizID); err != nil {
+				return nil, err
+			}
+		}
+		res[participationID] = all
+	}
+	return res, nil
+}
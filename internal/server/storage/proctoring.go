@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+func (s *MysqlStorage) InsertProctoringEvent(ctx context.Context, participationID string, event shared.ProctoringEvent) error {
+	return s.Queries.InsertProctoringEvent(ctx, database.InsertProctoringEventParams{
+		ID:              event.ID,
+		ParticipationID: participationID,
+		Kind:            event.Kind,
+		Detail:          event.Detail,
+	})
+}
+
+func (s *MysqlStorage) CountProctoringEvents(ctx context.Context, participationID, kind string) (int32, error) {
+	count, err := s.Queries.CountProctoringEvents(ctx, database.CountProctoringEventsParams{
+		ParticipationID: participationID,
+		Kind:            kind,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return shared.Int64ToInt32(count), nil
+}
+
+// A quiz without a rule for the given kind returns a zero threshold
+func (s *MysqlStorage) SelectProctoringRule(ctx context.Context, quizID, kind string) (shared.ProctoringRule, error) {
+	rule, err := s.Queries.SelectProctoringRule(ctx, database.SelectProctoringRuleParams{
+		QuizID: quizID,
+		Kind:   kind,
+	})
+	if err == sql.ErrNoRows {
+		return shared.ProctoringRule{Kind: kind, Label: shared.ProctoringLabels[kind]}, nil
+	}
+	if err != nil {
+		return shared.ProctoringRule{}, err
+	}
+	return shared.ProctoringRule{
+		Kind:      rule.Kind,
+		Label:     shared.ProctoringLabels[rule.Kind],
+		Threshold: rule.Threshold,
+	}, nil
+}
+
+func (s *MysqlStorage) SelectProctoringRules(ctx context.Context, quizID string) ([]shared.ProctoringRule, error) {
+	dbRules, err := s.Queries.SelectProctoringRules(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	res := []shared.ProctoringRule{}
+	for _, rule := range dbRules {
+		res = append(res, shared.ProctoringRule{
+			Kind:      rule.Kind,
+			Label:     shared.ProctoringLabels[rule.Kind],
+			Threshold: rule.Threshold,
+		})
+	}
+	return res, nil
+}
+
+func (s *MysqlStorage) UpsertProctoringRule(ctx context.Context, quizID string, rule shared.ProctoringRule) error {
+	return s.Queries.UpsertProctoringRule(ctx, database.UpsertProctoringRuleParams{
+		ID:        uuid.NewString(),
+		QuizID:    quizID,
+		Kind:      rule.Kind,
+		Threshold: rule.Threshold,
+	})
+}
+
+func (s *MysqlStorage) batchProctoringCounts(ctx context.Context, participationIDs []string) (map[string][]shared.ProctoringCount, error) {
+	dbCounts, err := s.Queries.BatchProctoringCounts(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string][]shared.ProctoringCount)
+	for _, c := range dbCounts {
+		res[c.ParticipationID] = append(res[c.ParticipationID], shared.ProctoringCount{
+			Kind:  c.Kind,
+			Label: shared.ProctoringLabels[c.Kind],
+			Count: shared.Int64ToInt32(c.Amount),
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,186 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// selectQuestion returns nil for coding problems
+func selectQuestion(ctx context.Context, q *database.Queries, problemID string) (*shared.Question, error) {
+	row, err := q.SelectQuestion(ctx, problemID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	options, err := q.SelectQuestionOptions(ctx, problemID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := q.SelectQuestionAnswers(ctx, problemID)
+	if err != nil {
+		return nil, err
+	}
+	question := &shared.Question{
+		Kind:      row.Kind,
+		Points:    row.Points,
+		Rubric:    row.Rubric,
+		Tolerance: row.Tolerance,
+		Options:   make([]shared.QuestionOption, len(options)),
+		Answers:   answers,
+	}
+	for i, option := range options {
+		question.Options[i] = shared.QuestionOption{Label: option.Label, Correct: option.Correct}
+	}
+	return question, nil
+}
+
+func insertQuestion(ctx context.Context, qtx *database.Queries, problemID string, question shared.Question) error {
+	err := qtx.InsertQuestion(ctx, database.InsertQuestionParams{
+		ProblemID: problemID,
+		Kind:      question.Kind,
+		Points:    question.Points,
+		Rubric:    question.Rubric,
+		Tolerance: question.Tolerance,
+	})
+	if err != nil {
+		return fmt.Errorf("error inserting question: %w", err)
+	}
+	for i, option := range question.Options {
+		err = qtx.InsertQuestionOption(ctx, database.InsertQuestionOptionParams{
+			ID:        uuid.New().String(),
+			ProblemID: problemID,
+			Position:  int32(i),
+			Label:     option.Label,
+			Correct:   option.Correct,
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting question option: %w", err)
+		}
+	}
+	for i, answer := range question.Answers {
+		err = qtx.InsertQuestionAnswer(ctx, database.InsertQuestionAnswerParams{
+			ID:        uuid.New().String(),
+			ProblemID: problemID,
+			Position:  int32(i),
+			Value:     answer,
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting question answer: %w", err)
+		}
+	}
+	return nil
+}
+
+// SaveResponse replaces the previous answer of the participation, a free
+// text answer is stored without score until the recruiter grades it
+func (mysql *MysqlStorage) SaveResponse(
+	ctx context.Context,
+	participationID, problemID, answer string,
+	score int32,
+	graded bool,
+) error {
+	return mysql.Queries.UpsertQuestionResponse(ctx, database.UpsertQuestionResponseParams{
+		ID:              uuid.New().String(),
+		ParticipationID: participationID,
+		ProblemID:       problemID,
+		Answer:          answer,
+		Score:           sql.NullInt32{Int32: score, Valid: graded},
+	})
+}
+
+// SelectResponse returns the answer of the latest participation of the user
+// that includes the question, it is empty when there is none
+func (mysql *MysqlStorage) SelectResponse(ctx context.Context, userID, quizID, problemID string) (shared.QuestionResponse, error) {
+	row, err := mysql.Queries.SelectQuestionResponse(ctx, database.SelectQuestionResponseParams{
+		ProblemID: problemID,
+		UserID:    userID,
+		QuizID:    quizID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return shared.QuestionResponse{}, nil
+	}
+	if err != nil {
+		return shared.QuestionResponse{}, err
+	}
+	return shared.QuestionResponse{
+		ID:       row.ID,
+		Answer:   row.Answer,
+		Graded:   row.Score.Valid,
+		Score:    row.Score.Int32,
+		Feedback: row.Feedback,
+	}, nil
+}
+
+// GradeResponse stores the score given by the recruiter to a free text
+// answer, the recruiter must own the offer of the quiz
+func (mysql *MysqlStorage) GradeResponse(ctx context.Context, userID, responseID string, score int32, feedback string) error {
+	problemID, err := mysql.Queries.SelectResponseByRecruiter(ctx, database.SelectResponseByRecruiterParams{
+		ID:     responseID,
+		UserID: userID,
+	})
+	if err != nil {
+		return err
+	}
+	question, err := selectQuestion(ctx, mysql.Queries, problemID)
+	if err != nil {
+		return err
+	}
+	if question == nil || question.Kind != shared.QuestionText {
+		return shared.ErrNotGradable
+	}
+	if score > question.Points {
+		return fmt.Errorf("%w: máximo %d", shared.ErrScoreOutOfRange, question.Points)
+	}
+	return mysql.Queries.GradeQuestionResponse(ctx, database.GradeQuestionResponseParams{
+		Score:    sql.NullInt32{Int32: score, Valid: true},
+		Feedback: feedback,
+		ID:       responseID,
+	})
+}
+
+// batchResponses builds the summaries of the questions answered in every
+// participation, grouped by participation
+func (mysql *MysqlStorage) batchResponses(ctx context.Context, participationIDs []string) (map[string][]shared.Summary, error) {
+	rows, err := mysql.Queries.BatchQuestionResponses(ctx, participationIDs)
+	if err != nil {
+		return nil, err
+	}
+	questions := make(map[string]*shared.Question)
+	res := make(map[string][]shared.Summary)
+	for _, row := range rows {
+		question, ok := questions[row.ProblemID]
+		if !ok {
+			if question, err = selectQuestion(ctx, mysql.Queries, row.ProblemID); err != nil {
+				return nil, err
+			}
+			questions[row.ProblemID] = question
+		}
+		if question == nil {
+			continue
+		}
+		res[row.ParticipationID] = append(res[row.ParticipationID], shared.Summary{
+			Title: row.Title,
+			Score: shared.Score{
+				AcceptedTestCases: int(row.Score.Int32),
+				TotalTestCases:    int(question.Points),
+			},
+			Question: question,
+			Response: shared.QuestionResponse{
+				ID:       row.ID,
+				Answer:   row.Answer,
+				Graded:   row.Score.Valid,
+				Score:    row.Score.Int32,
+				Feedback: row.Feedback,
+			},
+		})
+	}
+	return res, nil
+}
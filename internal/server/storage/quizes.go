@@ -10,8 +10,11 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/blobstore"
 	"github.com/kw3a/spotted-server/internal/server/codejudge"
 	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/kw3a/spotted-server/internal/server/signature"
+	"github.com/kw3a/spotted-server/internal/server/sqljudge"
 	"github.com/shopspring/decimal"
 )
 
@@ -66,10 +69,14 @@ func (mysql *MysqlStorage) SelectTestCases(ctx context.Context, problemID string
 	test_cases := []shared.TestCase{}
 	for _, test_case := range dbTC {
 		tc := shared.TestCase{
-			ID:        test_case.ID,
-			Input:     test_case.Input,
-			Output:    test_case.Output,
-			ProblemID: test_case.ProblemID,
+			ID:         test_case.ID,
+			Input:      test_case.Input,
+			Output:     test_case.Output,
+			ProblemID:  test_case.ProblemID,
+			InputHash:  test_case.InputHash,
+			OutputHash: test_case.OutputHash,
+			InputSize:  test_case.InputSize,
+			OutputSize: test_case.OutputSize,
 		}
 		test_cases = append(test_cases, tc)
 	}
@@ -98,10 +105,11 @@ func (mysql *MysqlStorage) GetResults(ctx context.Context, problemID, submission
 	return tcResults, nil
 }
 
-func (mysql *MysqlStorage) BestSubmission(ctx context.Context, applicantID string, problemID string) (shared.Submission, error) {
+func (mysql *MysqlStorage) BestSubmission(ctx context.Context, applicantID string, quizID string, problemID string) (shared.Submission, error) {
 	best, err := mysql.Queries.BestSubmission(ctx, database.BestSubmissionParams{
 		ProblemID: problemID,
 		UserID:    applicantID,
+		QuizID:    quizID,
 	})
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -115,7 +123,7 @@ func (mysql *MysqlStorage) BestSubmission(ctx context.Context, applicantID strin
 		AcceptedTestCases: int(best.AcceptedTestCases),
 		ParticipationID:   best.ParticipationID,
 		LanguageID:        best.LanguageID,
-		ProblemID:         best.ProblemID,
+		ProblemID:         judgedProblem(best.ProblemID, best.RejudgedProblemID),
 		Language:          best.Language,
 	}, nil
 }
@@ -154,10 +162,32 @@ func (mysql *MysqlStorage) SelectQuizByOffer(ctx context.Context, offerID string
 	if err != nil {
 		return shared.Quiz{}, err
 	}
+	return quizFromDB(dbQuiz), nil
+}
+
+func (mysql *MysqlStorage) SelectQuiz(ctx context.Context, quizID string) (shared.Quiz, error) {
+	dbQuiz, err := mysql.Queries.GetQuiz(ctx, quizID)
+	if err != nil {
+		return shared.Quiz{}, err
+	}
+	quiz := quizFromDB(dbQuiz)
+	// participations start from here, the offer decides if they can
+	quiz.OfferStatus, err = mysql.Queries.GetOfferStatusByQuiz(ctx, quizID)
+	if err != nil {
+		return shared.Quiz{}, err
+	}
+	return quiz, nil
+}
+
+func quizFromDB(dbQuiz database.Quiz) shared.Quiz {
 	return shared.Quiz{
-		ID:       dbQuiz.ID,
-		Duration: dbQuiz.Duration,
-	}, nil
+		ID:         dbQuiz.ID,
+		Duration:   dbQuiz.Duration,
+		OpensAt:    dbQuiz.OpensAt.Time,
+		ClosesAt:   dbQuiz.ClosesAt.Time,
+		InviteOnly: dbQuiz.InviteOnly,
+		Shuffle:    dbQuiz.Shuffle,
+	}
 }
 
 func (mysql *MysqlStorage) ParticipationStatus(ctx context.Context, userID string, quizID string) (shared.Participation, error) {
@@ -173,27 +203,34 @@ func (mysql *MysqlStorage) ParticipationStatus(ctx context.Context, userID strin
 		ID:           participation.ID,
 		CreatedAt:    participation.CreatedAt.Time,
 		ExpiresAt:    participation.ExpiresAt,
+		FinishedAt:   participation.FinishedAt.Time,
+		EndReason:    participation.EndReason,
+		PausedAt:     participation.PausedAt.Time,
 		RelativeTime: relativeTime,
 	}, nil
 }
 
+// Participate starts the participation and draws its problems
 func (mysql *MysqlStorage) Participate(ctx context.Context, userID string, quizID string) error {
-	return mysql.Queries.Participate(ctx, database.ParticipateParams{
-		ID:     uuid.New().String(),
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	participationID := uuid.New().String()
+	err = qtx.Participate(ctx, database.ParticipateParams{
+		ID:     participationID,
 		UserID: userID,
 		QuizID: quizID,
 	})
-}
-
-func (mysql *MysqlStorage) SelectProblemIDs(ctx context.Context, QuizID string) ([]string, error) {
-	IDs, err := mysql.Queries.SelectProblemIDs(ctx, QuizID)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	if len(IDs) == 0 {
-		return nil, errors.New("zero results")
+	if _, err := drawProblems(ctx, qtx, participationID, quizID); err != nil {
+		return err
 	}
-	return IDs, nil
+	return tx.Commit()
 }
 
 func ConvertLanguages(languages sql.NullString) ([]string, error) {
@@ -207,7 +244,22 @@ func ConvertLanguages(languages sql.NullString) ([]string, error) {
 	return result, nil
 }
 
-func (mysql *MysqlStorage) SelectScore(ctx context.Context, userID string, problemID string) (shared.Score, error) {
+func (mysql *MysqlStorage) SelectScore(ctx context.Context, userID string, quizID string, problemID string) (shared.Score, error) {
+	// questions are scored with points instead of test cases
+	question, err := selectQuestion(ctx, mysql.Queries, problemID)
+	if err != nil {
+		return shared.Score{}, err
+	}
+	if question != nil {
+		response, err := mysql.SelectResponse(ctx, userID, quizID, problemID)
+		if err != nil {
+			return shared.Score{}, err
+		}
+		return shared.Score{
+			AcceptedTestCases: int(response.Score),
+			TotalTestCases:    int(question.Points),
+		}, nil
+	}
 	totalTestCases, err := mysql.Queries.TotalTestCases(ctx, problemID)
 	if err != nil {
 		return shared.Score{}, err
@@ -215,6 +267,7 @@ func (mysql *MysqlStorage) SelectScore(ctx context.Context, userID string, probl
 	best, err := mysql.Queries.BestSubmission(ctx, database.BestSubmissionParams{
 		ProblemID: problemID,
 		UserID:    userID,
+		QuizID:    quizID,
 	})
 	acceptedTestCases := int(best.AcceptedTestCases)
 	if err == sql.ErrNoRows {
@@ -239,6 +292,9 @@ func (mysql *MysqlStorage) SelectProblems(ctx context.Context, quizID string) ([
 			Description: problem.Description,
 			MemoryLimit: problem.MemoryLimit,
 			TimeLimit:   problem.TimeLimit * 1000,
+			Signature:   problem.Signature,
+			Difficulty:  problem.Difficulty,
+			Tag:         problem.Tag,
 		}
 		res = append(res, p)
 	}
@@ -250,12 +306,25 @@ func (mysql *MysqlStorage) SelectProblem(ctx context.Context, problemID string)
 	if err != nil {
 		return shared.Problem{}, err
 	}
+	db, err := selectDatabase(ctx, mysql.Queries, problemID)
+	if err != nil {
+		return shared.Problem{}, err
+	}
+	question, err := selectQuestion(ctx, mysql.Queries, problemID)
+	if err != nil {
+		return shared.Problem{}, err
+	}
 	return shared.Problem{
 		ID:          dbProblem.ID,
 		Title:       dbProblem.Title,
 		Description: dbProblem.Description,
 		MemoryLimit: dbProblem.MemoryLimit,
 		TimeLimit:   dbProblem.TimeLimit,
+		Signature:   dbProblem.Signature,
+		Database:    db,
+		Question:    question,
+		Difficulty:  dbProblem.Difficulty,
+		Tag:         dbProblem.Tag,
 	}, nil
 }
 
@@ -274,14 +343,24 @@ func (mysql *MysqlStorage) SelectExamples(ctx context.Context, problemID string)
 	return res, nil
 }
 
-func (mysql *MysqlStorage) LastSrc(ctx context.Context, userID string, problemID string, languageID int32) (string, error) {
+func (mysql *MysqlStorage) LastSrc(ctx context.Context, userID string, quizID string, problemID string, languageID int32) (string, error) {
+	// SQL problems are always submitted in SQLite whatever the selected
+	// language is
+	db, err := selectDatabase(ctx, mysql.Queries, problemID)
+	if err != nil {
+		return "", err
+	}
+	if db != nil {
+		languageID = sqljudge.LanguageID
+	}
 	dbLastSubmission, err := mysql.Queries.LastSubmission(ctx, database.LastSubmissionParams{
 		ProblemID:  problemID,
 		LanguageID: languageID,
 		UserID:     userID,
+		QuizID:     quizID,
 	})
 	if err == sql.ErrNoRows {
-		return ExampleCode(languageID)
+		return mysql.starterCode(ctx, problemID, languageID)
 	}
 	if err != nil {
 		return "", err
@@ -289,6 +368,23 @@ func (mysql *MysqlStorage) LastSrc(ctx context.Context, userID string, problemID
 	return dbLastSubmission, nil
 }
 
+// starterCode is the stub of the function for signature problems and the
+// generic example otherwise
+func (mysql *MysqlStorage) starterCode(ctx context.Context, problemID string, languageID int32) (string, error) {
+	problem, err := mysql.Queries.SelectProblem(ctx, problemID)
+	if err != nil {
+		return "", err
+	}
+	if problem.Signature == "" {
+		return ExampleCode(languageID)
+	}
+	sig, err := signature.Parse(problem.Signature)
+	if err != nil {
+		return "", err
+	}
+	return sig.Stub(languageID)
+}
+
 func (s MysqlStorage) CreateSubmission(
 	ctx context.Context,
 	submissionID, participationID, problemID, src string,
@@ -325,6 +421,13 @@ func ToTC(dbTestCases []database.GetTestCasesRow) ([]codejudge.TestCase, error)
 			Input:          testCase.Input,
 			ExpectedOutput: testCase.Output,
 		}
+		// the rows of large test data only hold a preview
+		if testCase.InputHash != "" {
+			current.Input, current.InputBlob = "", testCase.InputHash
+		}
+		if testCase.OutputHash != "" {
+			current.ExpectedOutput, current.OutputBlob = "", testCase.OutputHash
+		}
 		res = append(res, current)
 	}
 	return res, nil
@@ -338,9 +441,10 @@ func (s MysqlStorage) UpdateTestCaseResult(
 ) error {
 	input.Time = input.Time.Mul(decimal.NewFromInt32(1000))
 	intTime := input.Time.IntPart()
+	// the output is only shown to the recruiter, large ones are cut
 	return s.Queries.UpdateTestCaseResult(ctx, database.UpdateTestCaseResultParams{
 		ID:           sql.NullString{String: input.Token, Valid: true},
-		Output:       input.Stdout,
+		Output:       blobstore.Truncate(input.Stdout, shared.InlineTestDataSize),
 		Status:       input.Status.Description,
 		Time:         shared.Int64ToInt32(intTime),
 		Memory:       input.Memory,
@@ -349,17 +453,72 @@ func (s MysqlStorage) UpdateTestCaseResult(
 	})
 }
 
+// EndQuiz finishes the participation of the user, it returns
+// shared.ErrAlreadyFinished along with the offer when it was already over
 func (s MysqlStorage) EndQuiz(ctx context.Context, userID, quizID string) (shared.Offer, error) {
-	err := s.Queries.EndParticipation(ctx, database.EndParticipationParams{
-		ExpiresAt: time.Now(),
-		UserID:    userID,
-		QuizID:    quizID,
+	now := time.Now()
+	affected, err := s.Queries.EndParticipation(ctx, database.EndParticipationParams{
+		ExpiresAt:  now,
+		FinishedAt: sql.NullTime{Time: now, Valid: true},
+		EndReason:  shared.EndReasonManual,
+		UserID:     userID,
+		QuizID:     quizID,
 	})
 	if err != nil {
 		return shared.Offer{}, err
 	}
 	offer, _ := s.Queries.GetOfferByQuiz(ctx, quizID)
+	if affected == 0 {
+		return shared.Offer{ID: offer.ID}, shared.ErrAlreadyFinished
+	}
 	return shared.Offer{
 		ID: offer.ID,
 	}, nil
 }
+
+// FinalizeExpired closes every participation whose deadline passed before
+// now. The finish time is the deadline itself, so scoring is frozen there
+// even if the finalizer runs late.
+func (s MysqlStorage) FinalizeExpired(ctx context.Context, now time.Time) ([]shared.Participation, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := s.Queries.WithTx(tx)
+	expired, err := qtx.SelectExpiredParticipations(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	res := []shared.Participation{}
+	for _, p := range expired {
+		reason := shared.EndReasonTimeout
+		if p.Submissions == 0 {
+			reason = shared.EndReasonAbandoned
+		}
+		// a recruiter may extend or pause it after the select, then it
+		// is left running and nothing is announced
+		affected, err := qtx.FinishExpiredParticipation(ctx, database.FinishExpiredParticipationParams{
+			FinishedAt: sql.NullTime{Time: p.ExpiresAt, Valid: true},
+			EndReason:  reason,
+			ID:         p.ID,
+			ExpiresAt:  now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if affected == 0 {
+			continue
+		}
+		res = append(res, shared.Participation{
+			ID:         p.ID,
+			ExpiresAt:  p.ExpiresAt,
+			FinishedAt: p.ExpiresAt,
+			EndReason:  reason,
+		})
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/kw3a/spotted-server/internal/server/sqljudge"
+	"github.com/shopspring/decimal"
+)
+
+// InsertReferenceSolution attaches the solution to the latest version of a
+// library problem owned by the user
+func (mysql *MysqlStorage) InsertReferenceSolution(
+	ctx context.Context,
+	bankProblemID string,
+	userID string,
+	solution shared.ReferenceSolution,
+) (shared.ReferenceSolution, error) {
+	latest, err := mysql.Queries.SelectBankProblemByUser(ctx, database.SelectBankProblemByUserParams{
+		ID:     bankProblemID,
+		UserID: userID,
+	})
+	if err != nil {
+		return shared.ReferenceSolution{}, err
+	}
+	solution.ID = uuid.New().String()
+	solution.ProblemID = latest.ProblemID
+	db, err := selectDatabase(ctx, mysql.Queries, latest.ProblemID)
+	if err != nil {
+		return shared.ReferenceSolution{}, err
+	}
+	if db != nil {
+		solution.LanguageID = sqljudge.LanguageID
+	}
+	err = mysql.Queries.InsertReferenceSolution(ctx, database.InsertReferenceSolutionParams{
+		ID:         solution.ID,
+		ProblemID:  solution.ProblemID,
+		LanguageID: solution.LanguageID,
+		Src:        solution.Src,
+		Expected:   solution.Expected,
+	})
+	if err != nil {
+		return shared.ReferenceSolution{}, err
+	}
+	return solution, nil
+}
+
+// ResetReferenceResults clears the results of the latest version so its
+// reference solutions can be judged again
+func (mysql *MysqlStorage) ResetReferenceResults(
+	ctx context.Context,
+	bankProblemID string,
+	userID string,
+) ([]shared.ReferenceSolution, error) {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	latest, err := qtx.SelectBankProblemByUser(ctx, database.SelectBankProblemByUserParams{
+		ID:     bankProblemID,
+		UserID: userID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := qtx.DeleteReferenceResults(ctx, latest.ProblemID); err != nil {
+		return nil, err
+	}
+	solutions, err := selectReferenceSolutions(ctx, qtx, latest.ProblemID)
+	if err != nil {
+		return nil, err
+	}
+	return solutions, tx.Commit()
+}
+
+func (mysql *MysqlStorage) DeleteReferenceSolution(ctx context.Context, solutionID, bankProblemID, userID string) error {
+	return mysql.Queries.DeleteReferenceSolution(ctx, database.DeleteReferenceSolutionParams{
+		ID:     solutionID,
+		ID_2:   bankProblemID,
+		UserID: userID,
+	})
+}
+
+func (mysql *MysqlStorage) SelectReferenceSolutions(ctx context.Context, problemID string) ([]shared.ReferenceSolution, error) {
+	return selectReferenceSolutions(ctx, mysql.Queries, problemID)
+}
+
+func selectReferenceSolutions(ctx context.Context, q *database.Queries, problemID string) ([]shared.ReferenceSolution, error) {
+	rows, err := q.SelectReferenceSolutions(ctx, problemID)
+	if err != nil {
+		return nil, err
+	}
+	results, err := q.SelectReferenceResults(ctx, problemID)
+	if err != nil {
+		return nil, err
+	}
+	bySolution := make(map[string][]shared.ReferenceResult)
+	for _, result := range results {
+		bySolution[result.ReferenceSolutionID] = append(bySolution[result.ReferenceSolutionID], shared.ReferenceResult{
+			TestCaseID: result.TestCaseID,
+			Status:     result.Status,
+			Time:       result.Time,
+			Memory:     result.Memory,
+		})
+	}
+	res := make([]shared.ReferenceSolution, len(rows))
+	for i, row := range rows {
+		res[i] = shared.ReferenceSolution{
+			ID:         row.ID,
+			ProblemID:  row.ProblemID,
+			LanguageID: row.LanguageID,
+			Language:   row.Language,
+			Src:        row.Src,
+			Expected:   row.Expected,
+			TestCases:  int(row.TestCases),
+			Results:    bySolution[row.ID],
+		}
+	}
+	return res, nil
+}
+
+// checkReferenceSolutions fails when the reference solutions of a problem
+// version are still running or did not behave as expected
+func checkReferenceSolutions(ctx context.Context, q *database.Queries, problemID string) error {
+	problem, err := q.SelectProblem(ctx, problemID)
+	if err != nil {
+		return err
+	}
+	solutions, err := selectReferenceSolutions(ctx, q, problemID)
+	if err != nil {
+		return err
+	}
+	status := shared.ReferenceStatus(solutions, problem.TimeLimit)
+	if status != "" && status != shared.ValidationPassed {
+		return fmt.Errorf("%w: %s (%s)", shared.ErrReferenceValidation, problem.Title, shared.ValidationLabels[status])
+	}
+	return nil
+}
+
+func (mysql *MysqlStorage) UpdateReferenceResult(
+	ctx context.Context,
+	input shared.CallbackJsonInput,
+	solutionID, testCaseID string,
+) error {
+	intTime := input.Time.Mul(decimal.NewFromInt32(1000)).IntPart()
+	return mysql.Queries.UpsertReferenceResult(ctx, database.UpsertReferenceResultParams{
+		ReferenceSolutionID: solutionID,
+		TestCaseID:          testCaseID,
+		Status:              input.Status.Description,
+		Time:                shared.Int64ToInt32(intTime),
+		Memory:              input.Memory,
+	})
+}
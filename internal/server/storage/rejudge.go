@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// CreateRejudge resets the results of the submissions in the scope and
+// records their scores, the caller sends them to the judge afterwards. The
+// recruiter must own the offer of the quiz
+func (mysql *MysqlStorage) CreateRejudge(
+	ctx context.Context,
+	userID string,
+	offerID string,
+	req shared.RejudgeRequest,
+) (string, []shared.RejudgeEntry, error) {
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return "", nil, err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	params := database.SelectRejudgeSubmissionsParams{}
+	switch req.Scope {
+	case shared.RejudgeProblem:
+		params.QuizID, err = qtx.SelectQuizProblemByRecruiter(ctx, database.SelectQuizProblemByRecruiterParams{
+			ID:        offerID,
+			ProblemID: req.TargetID,
+			UserID:    userID,
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		if req.Latest {
+			if req.TargetID, err = moveToLatestVersion(ctx, qtx, params.QuizID, req.TargetID); err != nil {
+				return "", nil, err
+			}
+		}
+		params.ProblemID = req.TargetID
+	case shared.RejudgeParticipation:
+		participation, err := qtx.SelectParticipationByRecruiter(ctx, database.SelectParticipationByRecruiterParams{
+			ID:     req.TargetID,
+			UserID: userID,
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		params.QuizID = participation.QuizID
+		params.ParticipationID = req.TargetID
+	case shared.RejudgeSubmission:
+		params.QuizID, err = qtx.SelectSubmissionByRecruiter(ctx, database.SelectSubmissionByRecruiterParams{
+			ID:     req.TargetID,
+			UserID: userID,
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		params.ID = req.TargetID
+	default:
+		return "", nil, fmt.Errorf("unknown rejudge scope: %s", req.Scope)
+	}
+	submissions, err := qtx.SelectRejudgeSubmissions(ctx, params)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(submissions) == 0 {
+		return "", nil, shared.ErrNothingToRejudge
+	}
+	rejudgeID := uuid.New().String()
+	err = qtx.InsertRejudge(ctx, database.InsertRejudgeParams{
+		ID:       rejudgeID,
+		Scope:    req.Scope,
+		TargetID: req.TargetID,
+		QuizID:   params.QuizID,
+		UserID:   userID,
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	entries := make([]shared.RejudgeEntry, len(submissions))
+	databases := make(map[string]*shared.Database)
+	for i, submission := range submissions {
+		db, ok := databases[submission.ProblemID]
+		if !ok {
+			if db, err = selectDatabase(ctx, qtx, submission.ProblemID); err != nil {
+				return "", nil, err
+			}
+			databases[submission.ProblemID] = db
+		}
+		err = qtx.InsertRejudgeSubmission(ctx, database.InsertRejudgeSubmissionParams{
+			RejudgeID:      rejudgeID,
+			SubmissionID:   submission.ID,
+			AcceptedBefore: int32(submission.AcceptedTestCases),
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		// Results are created again instead of updated, the test cases are
+		// different when the submission is judged on another version
+		if err := qtx.DeleteSubmissionResults(ctx, submission.ID); err != nil {
+			return "", nil, err
+		}
+		// the new verdict is announced again once judged
+		if err := qtx.DeleteSubmissionJudgedEvent(ctx, submission.ID); err != nil {
+			return "", nil, err
+		}
+		if err := qtx.ResetSubmission(ctx, submission.ID); err != nil {
+			return "", nil, err
+		}
+		if err := qtx.InsertSubmissionResults(ctx, submission.ID); err != nil {
+			return "", nil, err
+		}
+		entries[i] = shared.RejudgeEntry{
+			SubmissionID: submission.ID,
+			ProblemID:    submission.ProblemID,
+			Src:          submission.Src,
+			LanguageID:   submission.LanguageID,
+			Signature:    submission.Signature,
+			Database:     db,
+		}
+	}
+	return rejudgeID, entries, tx.Commit()
+}
+
+// moveToLatestVersion points the quiz to the newest version of the library
+// problem and judges its submissions against it, the submissions keep the
+// version the candidates solved and other quizzes keep the version they use
+func moveToLatestVersion(ctx context.Context, qtx *database.Queries, quizID, problemID string) (string, error) {
+	latestID, err := qtx.SelectLatestVersion(ctx, problemID)
+	if err != nil {
+		return "", err
+	}
+	if latestID == problemID {
+		return problemID, nil
+	}
+	if err := checkReferenceSolutions(ctx, qtx, latestID); err != nil {
+		return "", err
+	}
+	err = qtx.MoveQuizProblem(ctx, database.MoveQuizProblemParams{
+		NewProblemID: latestID,
+		QuizID:       quizID,
+		ProblemID:    problemID,
+	})
+	if err != nil {
+		return "", err
+	}
+	err = qtx.RejudgeOnVersion(ctx, database.RejudgeOnVersionParams{
+		NewProblemID: latestID,
+		QuizID:       quizID,
+		ProblemID:    problemID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return latestID, nil
+}
+
+// judgedProblem is the version a submission is scored on, the one it was
+// rejudged on or else the one the candidate solved
+func judgedProblem(problemID string, rejudgedProblemID sql.NullString) string {
+	if rejudgedProblemID.Valid {
+		return rejudgedProblemID.String
+	}
+	return problemID
+}
+
+func (mysql *MysqlStorage) SelectRejudge(ctx context.Context, rejudgeID, userID string) (shared.Rejudge, error) {
+	rejudge, err := mysql.Queries.SelectRejudgeByRecruiter(ctx, database.SelectRejudgeByRecruiterParams{
+		ID:     rejudgeID,
+		UserID: userID,
+	})
+	if err != nil {
+		return shared.Rejudge{}, err
+	}
+	rows, err := mysql.Queries.SelectRejudgeRows(ctx, rejudgeID)
+	if err != nil {
+		return shared.Rejudge{}, err
+	}
+	res := shared.Rejudge{
+		ID:        rejudge.ID,
+		OfferID:   rejudge.OfferID,
+		Scope:     rejudge.Scope,
+		CreatedAt: rejudge.CreatedAt,
+		Rows:      make([]shared.RejudgeRow, len(rows)),
+	}
+	for i, row := range rows {
+		applicant := row.Applicant
+		// the progress is shown under the same rules as the applicants
+		if row.RevealStage.Valid && !row.RevealedStage.Valid {
+			applicant = shared.Pseudonym(mysql.PseudonymKey, rejudge.OfferID, row.UserID)
+		}
+		res.Rows[i] = shared.RejudgeRow{
+			SubmissionID: row.SubmissionID,
+			Applicant:    applicant,
+			ProblemTitle: row.ProblemTitle,
+			Before:       row.AcceptedBefore,
+			After:        int32(row.AcceptedAfter),
+			Judged:       shared.Int64ToInt32(row.Judged),
+			Total:        shared.Int64ToInt32(row.Total),
+			Error:        row.Error,
+		}
+	}
+	return res, nil
+}
+
+func (mysql *MysqlStorage) UpdateRejudgeError(ctx context.Context, rejudgeID, submissionID, msg string) error {
+	return mysql.Queries.UpdateRejudgeError(ctx, database.UpdateRejudgeErrorParams{
+		Error:        msg,
+		RejudgeID:    rejudgeID,
+		SubmissionID: submissionID,
+	})
+}
@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestJudgedProblem(t *testing.T) {
+	if got := judgedProblem("v1", sql.NullString{}); got != "v1" {
+		t.Errorf("expected v1, got %s", got)
+	}
+	if got := judgedProblem("v1", sql.NullString{String: "v2", Valid: true}); got != "v2" {
+		t.Errorf("expected v2, got %s", got)
+	}
+}
@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+	"github.com/kw3a/spotted-server/internal/server/textsearch"
+)
+
+// SearchOffers lists a page of the published offers that match every
+// filter, next is nil on the last page. Text queries are matched against the
+// folded documents and ranked with the title weighing double
+func (mysql *MysqlStorage) SearchOffers(
+	ctx context.Context,
+	filters shared.OfferFilters,
+) ([]shared.Offer, *shared.OfferCursor, error) {
+	query := textsearch.BooleanQuery(textsearch.Terms(filters.Query))
+	sort := filters.Sort
+	if sort == shared.SortRelevance && query == "" {
+		sort = shared.SortDate
+	}
+	after := shared.OfferCursor{}
+	if filters.After != nil {
+		after = *filters.After
+	}
+	dbOffers, err := mysql.Queries.SearchOffers(ctx, database.SearchOffersParams{
+		Sort:      sort,
+		Query:     query,
+		Modality:  filters.Modality,
+		Seniority: filters.Seniority,
+		Contract:  filters.Contract,
+		CompanyID: filters.CompanyID,
+		Currency:  filters.Currency,
+		MinWage:   filters.MinWage,
+		MaxWage:   filters.MaxWage,
+		Tags:      joinTags(filters.Tags),
+		AfterID:   after.ID,
+		AfterKey:  after.Key,
+		// one more row tells whether there is a next page
+		Limit: offerPageSize + 1,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var next *shared.OfferCursor
+	if len(dbOffers) > offerPageSize {
+		dbOffers = dbOffers[:offerPageSize]
+		last := dbOffers[len(dbOffers)-1]
+		next = &shared.OfferCursor{Key: last.SortKey, ID: last.ID}
+	}
+	offers := []shared.Offer{}
+	offerIDs := []string{}
+	for _, dbOffer := range dbOffers {
+		offers = append(offers, shared.Offer{
+			ID:              dbOffer.ID,
+			Title:           dbOffer.Title,
+			About:           dbOffer.About,
+			Requirements:    dbOffer.Requirements,
+			Benefits:        dbOffer.Benefits,
+			Status:          dbOffer.Status,
+			CompanyName:     dbOffer.CompanyName,
+			CompanyID:       dbOffer.CompanyID,
+			CompanyImageURL: dbOffer.CompanyImageUrl,
+			MinWage:         dbOffer.MinWage,
+			MaxWage:         dbOffer.MaxWage,
+			Location:        dbOffer.Location,
+			Modality:        dbOffer.Modality,
+			Seniority:       dbOffer.Seniority,
+			Contract:        dbOffer.Contract,
+			Currency:        dbOffer.Currency,
+			WagePeriod:      dbOffer.WagePeriod,
+			RelativeTime:    RelativeTime(dbOffer.CreatedAt),
+		})
+		offerIDs = append(offerIDs, dbOffer.ID)
+	}
+	if len(offerIDs) == 0 {
+		return offers, nil, nil
+	}
+	tags, err := mysql.selectOffersTags(ctx, offerIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range offers {
+		offers[i].Tags = tags[offers[i].ID]
+	}
+	return offers, next, nil
+}
+
+// SearchFacets counts the offers of every value of the modality, tag and
+// company filters. Each facet ignores its own filter so the alternatives to
+// the selected value are still counted
+func (mysql *MysqlStorage) SearchFacets(ctx context.Context, filters shared.OfferFilters) (shared.OfferFacets, error) {
+	query := textsearch.BooleanQuery(textsearch.Terms(filters.Query))
+	tags := joinTags(filters.Tags)
+	facets := shared.OfferFacets{}
+	modalities, err := mysql.Queries.CountOfferModalities(ctx, database.CountOfferModalitiesParams{
+		Query:     query,
+		Seniority: filters.Seniority,
+		Contract:  filters.Contract,
+		CompanyID: filters.CompanyID,
+		Currency:  filters.Currency,
+		MinWage:   filters.MinWage,
+		MaxWage:   filters.MaxWage,
+		Tags:      tags,
+	})
+	if err != nil {
+		return shared.OfferFacets{}, err
+	}
+	for _, modality := range modalities {
+		facets.Modalities = append(facets.Modalities, shared.Facet{
+			Value:    modality.Modality,
+			Label:    shared.ModalityLabels[modality.Modality],
+			Count:    modality.Count,
+			Selected: modality.Modality == filters.Modality,
+		})
+	}
+	languages, err := mysql.Queries.CountOfferTags(ctx, database.CountOfferTagsParams{
+		Query:     query,
+		Modality:  filters.Modality,
+		Seniority: filters.Seniority,
+		Contract:  filters.Contract,
+		CompanyID: filters.CompanyID,
+		Currency:  filters.Currency,
+		MinWage:   filters.MinWage,
+		MaxWage:   filters.MaxWage,
+	})
+	if err != nil {
+		return shared.OfferFacets{}, err
+	}
+	for _, language := range languages {
+		facets.Tags = append(facets.Tags, shared.Facet{
+			Value:    strconv.Itoa(int(language.ID)),
+			Label:    language.DisplayName,
+			Count:    language.Count,
+			Selected: filters.HasTag(language.ID),
+		})
+	}
+	companies, err := mysql.Queries.CountOfferCompanies(ctx, database.CountOfferCompaniesParams{
+		Query:     query,
+		Modality:  filters.Modality,
+		Seniority: filters.Seniority,
+		Contract:  filters.Contract,
+		Currency:  filters.Currency,
+		MinWage:   filters.MinWage,
+		MaxWage:   filters.MaxWage,
+		Tags:      tags,
+	})
+	if err != nil {
+		return shared.OfferFacets{}, err
+	}
+	for _, company := range companies {
+		facets.Companies = append(facets.Companies, shared.Facet{
+			Value:    company.ID,
+			Label:    company.Name,
+			Count:    company.Count,
+			Selected: company.ID == filters.CompanyID,
+		})
+	}
+	return facets, nil
+}
+
+func (mysql *MysqlStorage) selectOffersTags(ctx context.Context, offerIDs []string) (map[string][]shared.Language, error) {
+	rows, err := mysql.Queries.SelectOffersTags(ctx, offerIDs)
+	if err != nil {
+		return nil, err
+	}
+	tags := make(map[string][]shared.Language)
+	for _, row := range rows {
+		tags[row.OfferID] = append(tags[row.OfferID], shared.Language{
+			ID:          row.ID,
+			Name:        row.Name,
+			DisplayName: row.DisplayName,
+		})
+	}
+	return tags, nil
+}
+
+// upsertOfferDocument refreshes the indexed text of the offer, the body
+// repeats the title so every term of a query can be required at once
+func upsertOfferDocument(ctx context.Context, qtx *database.Queries, offerID string, offer shared.Offer) error {
+	err := qtx.UpsertOfferDocument(ctx, database.UpsertOfferDocumentParams{
+		OfferID: offerID,
+		Title:   textsearch.Document(offer.Title),
+		Body:    textsearch.Document(offer.Title, offer.About, offer.Requirements),
+	})
+	if err != nil {
+		return fmt.Errorf("error indexing offer: %w", err)
+	}
+	return nil
+}
+
+func insertOfferTags(ctx context.Context, qtx *database.Queries, offerID string, tags []shared.Language) error {
+	for _, tag := range tags {
+		err := qtx.InsertOfferTag(ctx, database.InsertOfferTagParams{
+			OfferID:    offerID,
+			LanguageID: tag.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("error inserting offer tag: %w", err)
+		}
+	}
+	return nil
+}
+
+// joinTags builds the set that FIND_IN_SET matches against, empty means any
+func joinTags(tags []int32) string {
+	ids := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		ids = append(ids, strconv.Itoa(int(tag)))
+	}
+	return strings.Join(ids, ",")
+}
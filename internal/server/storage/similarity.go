@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// Best submissions from other quizzes of the same company that reuse a
+// problem of this quiz, a problem is considered reused when title and
+// description are identical
+func (mysql *MysqlStorage) SelectPriorSubmissions(ctx context.Context, quizID string) ([]shared.SimilarityEntry, error) {
+	dbSubmissions, err := mysql.Queries.SelectPriorSubmissions(ctx, quizID)
+	if err != nil {
+		return nil, err
+	}
+	res := []shared.SimilarityEntry{}
+	for _, s := range dbSubmissions {
+		res = append(res, shared.SimilarityEntry{
+			Applicant: shared.User{
+				ID:   s.UserID,
+				Name: s.Name,
+			},
+			ProblemTitle: s.Title,
+			OfferTitle:   s.OfferTitle,
+			Submission: shared.Submission{
+				ID:         s.ID,
+				Src:        s.Src,
+				LanguageID: s.LanguageID,
+				Language:   s.DisplayName,
+				ProblemID:  s.ProblemID,
+			},
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// testDB opens the migrated database in TEST_DATABASE_URL, the tests that
+// need one are skipped without it
+func testDB(t *testing.T) *MysqlStorage {
+	t.Helper()
+	dbURL := os.Getenv("TEST_DATABASE_URL")
+	if dbURL == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	storage, err := NewMysqlStorage(dbURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { storage.db.Close() })
+	return storage
+}
+
+func mustExec(t *testing.T, db *sql.DB, query string, args ...any) {
+	t.Helper()
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// seedQuiz creates a quiz of the company with the problem and an applicant
+// that submitted to it, it returns the quiz and the submission
+func seedQuiz(t *testing.T, db *sql.DB, companyID, problemID string, languageID int) (string, string) {
+	t.Helper()
+	offerID, quizID := uuid.NewString(), uuid.NewString()
+	userID, participationID, submissionID := uuid.NewString(), uuid.NewString(), uuid.NewString()
+	mustExec(t, db, `INSERT INTO offer (id, title, about, requirements, benefits, min_wage, max_wage, company_id)
+		VALUES (?, 'Offer', '', '', '', 0, 0, ?)`, offerID, companyID)
+	mustExec(t, db, "INSERT INTO quiz (id, duration, offer_id) VALUES (?, 60, ?)", quizID, offerID)
+	mustExec(t, db, "INSERT INTO quiz_problem (quiz_id, problem_id) VALUES (?, ?)", quizID, problemID)
+	mustExec(t, db, "INSERT INTO user (id, nick, password, name) VALUES (?, ?, '', 'Applicant')", userID, userID[:32])
+	t.Cleanup(func() { db.Exec("DELETE FROM user WHERE id = ?", userID) })
+	mustExec(t, db, "INSERT INTO participation (id, expires_at, user_id, quiz_id) VALUES (?, NOW(), ?, ?)", participationID, userID, quizID)
+	mustExec(t, db, `INSERT INTO submission (id, src, problem_id, participation_id, language_id)
+		VALUES (?, 'print(1)', ?, ?, ?)`, submissionID, problemID, participationID, languageID)
+	return quizID, submissionID
+}
+
+func seedCompany(t *testing.T, db *sql.DB) string {
+	t.Helper()
+	ownerID, companyID := uuid.NewString(), uuid.NewString()
+	mustExec(t, db, "INSERT INTO user (id, nick, password, name) VALUES (?, ?, '', 'Owner')", ownerID, ownerID[:32])
+	mustExec(t, db, `INSERT INTO company (id, name, description, website, image_url, user_id)
+		VALUES (?, 'Company', '', '', '', ?)`, companyID, ownerID)
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM company WHERE id = ?", companyID)
+		db.Exec("DELETE FROM user WHERE id = ?", ownerID)
+	})
+	return companyID
+}
+
+func TestSelectPriorSubmissionsCompany(t *testing.T) {
+	storage := testDB(t)
+	db := storage.db
+	languageID := 990001
+	mustExec(t, db, "INSERT IGNORE INTO language (id, name, display_name) VALUES (?, 'test', 'Test 990001')", languageID)
+	t.Cleanup(func() { db.Exec("DELETE FROM language WHERE id = ?", languageID) })
+
+	companyID := seedCompany(t, db)
+	otherCompanyID := seedCompany(t, db)
+	bankProblemID, problemID := uuid.NewString(), uuid.NewString()
+	mustExec(t, db, "INSERT INTO bank_problem (id, company_id) VALUES (?, ?)", bankProblemID, companyID)
+	mustExec(t, db, `INSERT INTO problem (id, description, title, memory_limit, time_limit, bank_problem_id)
+		VALUES (?, 'Shared description', 'Shared problem', 1024, 1, ?)`, problemID, bankProblemID)
+
+	quizID, _ := seedQuiz(t, db, companyID, problemID, languageID)
+	_, priorID := seedQuiz(t, db, companyID, problemID, languageID)
+	_, otherID := seedQuiz(t, db, otherCompanyID, problemID, languageID)
+
+	entries, err := storage.SelectPriorSubmissions(context.Background(), quizID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, entry := range entries {
+		if entry.Submission.ID == otherID {
+			t.Error("expected no submissions from another company")
+		}
+		found = found || entry.Submission.ID == priorID
+	}
+	if !found {
+		t.Error("expected the submission from the same company")
+	}
+}
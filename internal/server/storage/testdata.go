@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kw3a/spotted-server/internal/server/blobstore"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// BlobStore keeps the test data that doesn't fit in the test case rows
+type BlobStore interface {
+	Put(data []byte) (string, error)
+	Preview(hash string, n int) (string, int64, error)
+	Read(hash string) ([]byte, error)
+}
+
+var errNoBlobStore = errors.New("there is no blob store for large test data")
+
+// prepareTestData runs the generators and moves the test data larger than
+// InlineTestDataSize to the blob store, the rows keep a preview. Test cases
+// that already name a blob, like the ones sent back by the editor, keep it.
+// It runs before the transactions since generators may take a while
+func (mysql *MysqlStorage) prepareTestData(ctx context.Context, problems []shared.Problem) error {
+	if err := shared.ExpandGenerators(ctx, mysql.Generator, problems); err != nil {
+		return err
+	}
+	for i, problem := range problems {
+		for j := range problem.TestCases {
+			tc := &problems[i].TestCases[j]
+			if err := mysql.storeTestData(&tc.Input, &tc.InputHash, &tc.InputSize); err != nil {
+				return err
+			}
+			if err := mysql.storeTestData(&tc.Output, &tc.OutputHash, &tc.OutputSize); err != nil {
+				return err
+			}
+			// the scripts of SQL problems are built from the rows
+			if problem.Database != nil && tc.Truncated() {
+				return fmt.Errorf("los casos de prueba de un problema SQL no pueden superar %d bytes", shared.InlineTestDataSize)
+			}
+		}
+	}
+	return nil
+}
+
+func (mysql *MysqlStorage) storeTestData(data *string, hash *string, size *int32) error {
+	if *hash != "" {
+		if mysql.Blobs == nil {
+			return errNoBlobStore
+		}
+		preview, n, err := mysql.Blobs.Preview(*hash, shared.TestPreviewSize)
+		if err != nil {
+			return fmt.Errorf("test data %s: %w", *hash, err)
+		}
+		*data, *size = preview, shared.Int64ToInt32(n)
+		return nil
+	}
+	*size = shared.IntToInt32(len(*data))
+	if len(*data) <= shared.InlineTestDataSize {
+		return nil
+	}
+	if mysql.Blobs == nil {
+		return errNoBlobStore
+	}
+	stored, err := mysql.Blobs.Put([]byte(*data))
+	if err != nil {
+		return fmt.Errorf("error storing test data: %w", err)
+	}
+	*hash = stored
+	*data = blobstore.Truncate(*data, shared.TestPreviewSize)
+	return nil
+}
+
+// ReadTestData returns the whole data of a blob, the rows only have a preview
+func (mysql *MysqlStorage) ReadTestData(ctx context.Context, hash string) (string, error) {
+	if mysql.Blobs == nil {
+		return "", errNoBlobStore
+	}
+	data, err := mysql.Blobs.Read(hash)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/blobstore"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+type generatorMock struct{}
+
+func (generatorMock) Generate(ctx context.Context, generator shared.Generator) ([]shared.TestCase, error) {
+	return []shared.TestCase{{Input: strings.Repeat("1", 10000), Output: "ok"}}, nil
+}
+
+func TestPrepareTestData(t *testing.T) {
+	blobs, err := blobstore.NewFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	big := strings.Repeat("9 ", shared.InlineTestDataSize)
+	stored, err := blobs.Put([]byte(big))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mysql := &MysqlStorage{Blobs: blobs, Generator: generatorMock{}}
+	problems := []shared.Problem{{
+		TestCases: []shared.TestCase{
+			{Input: "1 2", Output: "3"},
+			{Input: big, Output: "x"},
+			{Input: "preview", Output: "y", InputHash: stored},
+		},
+		Generator: &shared.Generator{Seeds: []string{"1"}},
+	}}
+	if err := mysql.prepareTestData(context.Background(), problems); err != nil {
+		t.Fatal(err)
+	}
+	tcs := problems[0].TestCases
+	if len(tcs) != 4 || problems[0].Generator != nil {
+		t.Fatalf("expected the generated test case, got %d", len(tcs))
+	}
+	if tcs[0].Truncated() || tcs[0].InputSize != 3 || tcs[0].OutputSize != 1 {
+		t.Errorf("expected small data inline, got %+v", tcs[0])
+	}
+	if tcs[1].InputHash != stored || len(tcs[1].Input) != shared.TestPreviewSize || tcs[1].InputSize != int32(len(big)) {
+		t.Errorf("expected large data in the blob store, got %s of %d", tcs[1].InputHash, tcs[1].InputSize)
+	}
+	if tcs[2].Input != big[:shared.TestPreviewSize] || tcs[2].InputSize != int32(len(big)) {
+		t.Errorf("expected the preview of the stored blob, got %d bytes", tcs[2].InputSize)
+	}
+	if tcs[3].InputHash == "" || tcs[3].OutputHash != "" {
+		t.Errorf("expected the generated input in the blob store")
+	}
+	data, err := mysql.ReadTestData(context.Background(), tcs[3].InputHash)
+	if err != nil || len(data) != 10000 {
+		t.Errorf("expected the generated input back, got %d bytes, %v", len(data), err)
+	}
+}
+
+func TestPrepareTestDataErrors(t *testing.T) {
+	blobs, err := blobstore.NewFS(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]*MysqlStorage{
+		"no blob store":  {},
+		"missing blob":   {Blobs: blobs},
+		"no generator":   {Blobs: blobs},
+		"large SQL data": {Blobs: blobs},
+	}
+	problems := map[string]shared.Problem{
+		"no blob store":  {TestCases: []shared.TestCase{{Input: strings.Repeat("a", shared.InlineTestDataSize+1)}}},
+		"missing blob":   {TestCases: []shared.TestCase{{InputHash: blobstore.Hash([]byte("missing"))}}},
+		"no generator":   {Generator: &shared.Generator{}},
+		"large SQL data": {TestCases: []shared.TestCase{{Input: strings.Repeat("a", shared.InlineTestDataSize+1)}}, Database: &shared.Database{}},
+	}
+	for name, mysql := range cases {
+		if err := mysql.prepareTestData(context.Background(), []shared.Problem{problems[name]}); err == nil {
+			t.Errorf("%s: expected error, got nil", name)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kw3a/spotted-server/internal/database"
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const webhookDeliveryLogSize = 50
+
+func toWebhook(dbWebhook database.Webhook) shared.Webhook {
+	return shared.Webhook{
+		ID:        dbWebhook.ID,
+		CompanyID: dbWebhook.CompanyID,
+		URL:       dbWebhook.Url,
+		Secret:    dbWebhook.Secret,
+		Events:    strings.Split(dbWebhook.Events, ","),
+		CreatedAt: dbWebhook.CreatedAt,
+	}
+}
+
+func (mysql *MysqlStorage) SelectWebhooks(ctx context.Context, companyID string) ([]shared.Webhook, error) {
+	dbWebhooks, err := mysql.Queries.SelectCompanyWebhooks(ctx, companyID)
+	if err != nil {
+		return nil, err
+	}
+	webhooks := []shared.Webhook{}
+	for _, dbWebhook := range dbWebhooks {
+		webhooks = append(webhooks, toWebhook(dbWebhook))
+	}
+	return webhooks, nil
+}
+
+func (mysql *MysqlStorage) InsertWebhook(ctx context.Context, webhook shared.Webhook) error {
+	return mysql.Queries.InsertWebhook(ctx, database.InsertWebhookParams{
+		ID:        webhook.ID,
+		Url:       webhook.URL,
+		Secret:    webhook.Secret,
+		Events:    strings.Join(webhook.Events, ","),
+		CompanyID: webhook.CompanyID,
+	})
+}
+
+func (mysql *MysqlStorage) DeleteWebhook(ctx context.Context, companyID, webhookID string) error {
+	return mysql.Queries.DeleteWebhook(ctx, database.DeleteWebhookParams{
+		ID:        webhookID,
+		CompanyID: companyID,
+	})
+}
+
+// SelectWebhookDeliveries is the delivery log of the company, the latest
+// deliveries first
+func (mysql *MysqlStorage) SelectWebhookDeliveries(ctx context.Context, companyID string) ([]shared.WebhookDelivery, error) {
+	rows, err := mysql.Queries.SelectCompanyWebhookDeliveries(ctx, database.SelectCompanyWebhookDeliveriesParams{
+		CompanyID: companyID,
+		Limit:     webhookDeliveryLogSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []shared.WebhookDelivery{}
+	for _, row := range rows {
+		deliveries = append(deliveries, shared.WebhookDelivery{
+			ID:            row.ID,
+			WebhookID:     row.WebhookID,
+			URL:           row.Url,
+			Event:         row.Event,
+			Payload:       row.Payload,
+			Status:        row.Status,
+			Attempts:      int(row.Attempts),
+			NextAttemptAt: row.NextAttemptAt,
+			ResponseCode:  int(row.ResponseCode),
+			LastError:     row.LastError,
+			CreatedAt:     row.CreatedAt,
+			DeliveredAt:   row.DeliveredAt.Time,
+		})
+	}
+	return deliveries, nil
+}
+
+// RedeliverWebhook queues a delivery of the company again with every
+// attempt available
+func (mysql *MysqlStorage) RedeliverWebhook(ctx context.Context, companyID, deliveryID string) error {
+	_, err := mysql.Queries.SelectWebhookDeliveryByCompany(ctx, database.SelectWebhookDeliveryByCompanyParams{
+		ID:        deliveryID,
+		CompanyID: companyID,
+	})
+	if err != nil {
+		return err
+	}
+	return mysql.Queries.ResetWebhookDelivery(ctx, database.ResetWebhookDeliveryParams{
+		NextAttemptAt: time.Now(),
+		ID:            deliveryID,
+	})
+}
+
+func (mysql *MysqlStorage) SelectDueDeliveries(ctx context.Context, now time.Time, limit int) ([]shared.WebhookDelivery, error) {
+	rows, err := mysql.Queries.SelectDueWebhookDeliveries(ctx, database.SelectDueWebhookDeliveriesParams{
+		NextAttemptAt: now,
+		Limit:         shared.IntToInt32(limit),
+	})
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []shared.WebhookDelivery{}
+	for _, row := range rows {
+		deliveries = append(deliveries, shared.WebhookDelivery{
+			ID:            row.ID,
+			WebhookID:     row.WebhookID,
+			URL:           row.Url,
+			Secret:        row.Secret,
+			Event:         row.Event,
+			Payload:       row.Payload,
+			Status:        row.Status,
+			Attempts:      int(row.Attempts),
+			NextAttemptAt: row.NextAttemptAt,
+			ResponseCode:  int(row.ResponseCode),
+			LastError:     row.LastError,
+			CreatedAt:     row.CreatedAt,
+		})
+	}
+	return deliveries, nil
+}
+
+func (mysql *MysqlStorage) UpdateDeliveryAttempt(ctx context.Context, delivery shared.WebhookDelivery) error {
+	return mysql.Queries.UpdateWebhookDeliveryAttempt(ctx, database.UpdateWebhookDeliveryAttemptParams{
+		Status:        delivery.Status,
+		Attempts:      shared.IntToInt32(delivery.Attempts),
+		NextAttemptAt: delivery.NextAttemptAt,
+		ResponseCode:  shared.IntToInt32(delivery.ResponseCode),
+		LastError:     delivery.LastError,
+		DeliveredAt:   nullTime(delivery.DeliveredAt),
+		ID:            delivery.ID,
+	})
+}
+
+// EmitWebhookEvent writes a delivery of the event for every webhook of the
+// company subscribed to it, the dispatcher sends them afterwards
+func (mysql *MysqlStorage) EmitWebhookEvent(ctx context.Context, event shared.WebhookEvent) error {
+	companyID, data, err := mysql.webhookData(ctx, event)
+	if err != nil || companyID == "" {
+		return err
+	}
+	dbWebhooks, err := mysql.Queries.SelectCompanyWebhooks(ctx, companyID)
+	if err != nil {
+		return err
+	}
+	subscribed := []shared.Webhook{}
+	for _, dbWebhook := range dbWebhooks {
+		webhook := toWebhook(dbWebhook)
+		if webhook.Subscribed(event.Type) {
+			subscribed = append(subscribed, webhook)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+	now := time.Now()
+	payload, err := json.Marshal(shared.WebhookPayload{
+		ID:        uuid.New().String(),
+		Event:     event.Type,
+		CreatedAt: now,
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+	tx, err := mysql.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	qtx := mysql.Queries.WithTx(tx)
+	for _, webhook := range subscribed {
+		err = qtx.InsertWebhookDelivery(ctx, database.InsertWebhookDeliveryParams{
+			ID:            uuid.New().String(),
+			Event:         event.Type,
+			Payload:       string(payload),
+			NextAttemptAt: now,
+			WebhookID:     webhook.ID,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// webhookData returns the company of the event with the data of the
+// payload, no company means there is nothing to send yet
+func (mysql *MysqlStorage) webhookData(ctx context.Context, event shared.WebhookEvent) (string, shared.WebhookData, error) {
+	switch event.Type {
+	case shared.EventOfferArchived:
+		offer, err := mysql.Queries.SelectOfferWebhookEvent(ctx, event.OfferID)
+		if err != nil {
+			return "", shared.WebhookData{}, err
+		}
+		return offer.CompanyID, shared.WebhookData{
+			Offer: shared.WebhookOffer{ID: offer.ID, Title: offer.Title, Status: offer.Status},
+		}, nil
+	case shared.EventSubmissionJudged:
+		// every test case calls back, the event is sent with the last one.
+		// Concurrent last callbacks all see nothing pending, only the one
+		// that records the event sends it
+		submission, err := mysql.Queries.SelectSubmissionWebhookEvent(ctx, event.SubmissionID)
+		if err != nil || submission.PendingTestCases > 0 {
+			return "", shared.WebhookData{}, err
+		}
+		recorded, err := mysql.Queries.InsertSubmissionJudgedEvent(ctx, submission.ID)
+		if err != nil || recorded == 0 {
+			return "", shared.WebhookData{}, err
+		}
+		companyID, data, err := mysql.participationWebhookData(ctx, submission.ParticipationID)
+		if err != nil {
+			return "", shared.WebhookData{}, err
+		}
+		data.Submission = &shared.WebhookSubmission{
+			ID:                submission.ID,
+			ProblemID:         submission.ProblemID,
+			ProblemTitle:      submission.ProblemTitle,
+			AcceptedTestCases: int(submission.AcceptedTestCases),
+			TotalTestCases:    int(submission.TotalTestCases),
+		}
+		return companyID, data, nil
+	case shared.EventParticipationStarted, shared.EventParticipationFinished:
+		participationID := event.ParticipationID
+		if participationID == "" {
+			participation, err := mysql.Queries.ParticipationStatus(ctx, database.ParticipationStatusParams{
+				UserID: event.UserID,
+				QuizID: event.QuizID,
+			})
+			if err != nil {
+				return "", shared.WebhookData{}, err
+			}
+			participationID = participation.ID
+		}
+		return mysql.participationWebhookData(ctx, participationID)
+	}
+	return "", shared.WebhookData{}, fmt.Errorf("unknown webhook event: %s", event.Type)
+}
+
+func (mysql *MysqlStorage) participationWebhookData(ctx context.Context, participationID string) (string, shared.WebhookData, error) {
+	row, err := mysql.Queries.SelectParticipationWebhookEvent(ctx, participationID)
+	if err != nil {
+		return "", shared.WebhookData{}, err
+	}
+	participation := &shared.WebhookParticipation{
+		ID:        row.ID,
+		StartedAt: row.CreatedAt.Time,
+		ExpiresAt: row.ExpiresAt,
+		EndReason: row.EndReason,
+	}
+	if row.FinishedAt.Valid {
+		participation.FinishedAt = &row.FinishedAt.Time
+	}
+	applicant := &shared.WebhookApplicant{ID: row.UserID, Name: row.Name, Email: row.Email}
+	// the identity is sent under the same rules the recruiter sees it
+	if row.RevealStage.Valid && !row.RevealedStage.Valid {
+		applicant = &shared.WebhookApplicant{
+			Name:      shared.Pseudonym(mysql.PseudonymKey, row.OfferID, row.UserID),
+			Anonymous: true,
+		}
+	}
+	return row.CompanyID, shared.WebhookData{
+		Offer:         shared.WebhookOffer{ID: row.OfferID, Title: row.OfferTitle, Status: row.OfferStatus},
+		Participation: participation,
+		Applicant:     applicant,
+	}, nil
+}
@@ -0,0 +1,53 @@
+package testgen
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// Runner runs a program with the input and returns what it printed
+type Runner interface {
+	Run(ctx context.Context, src string, languageID int32, stdin string) (string, error)
+}
+
+// Generator runs the test generators of the recruiters on the judge
+type Generator struct {
+	runner Runner
+}
+
+func New(runner Runner) *Generator {
+	return &Generator{runner: runner}
+}
+
+// Generate runs the generator once per seed and the solution on every
+// generated input. The data of all the test cases is bounded by
+// MaxTestDataSize
+func (g *Generator) Generate(ctx context.Context, generator shared.Generator) ([]shared.TestCase, error) {
+	res := []shared.TestCase{}
+	total := 0
+	for _, seed := range generator.Seeds {
+		input, err := g.runner.Run(ctx, generator.Src, generator.LanguageID, seed)
+		if err != nil {
+			return nil, fmt.Errorf("%w con la semilla %q: %s", shared.ErrGenerator, seed, err)
+		}
+		if strings.TrimSpace(input) == "" {
+			return nil, fmt.Errorf("%w: la semilla %q no generó una entrada", shared.ErrGenerator, seed)
+		}
+		output, err := g.runner.Run(ctx, generator.Solution, generator.LanguageID, input)
+		if err != nil {
+			return nil, fmt.Errorf("%w: la solución falló con la semilla %q: %s", shared.ErrGenerator, seed, err)
+		}
+		if strings.TrimSpace(output) == "" {
+			return nil, fmt.Errorf("%w: la solución no imprimió nada con la semilla %q", shared.ErrGenerator, seed)
+		}
+		total += len(input) + len(output)
+		if total > shared.MaxTestDataSize {
+			return nil, fmt.Errorf("%w: los casos generados superan %s", shared.ErrGenerator, shared.SizeLabel(shared.MaxTestDataSize))
+		}
+		res = append(res, shared.TestCase{Input: input, Output: output})
+	}
+	return res, nil
+}
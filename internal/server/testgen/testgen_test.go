@@ -0,0 +1,66 @@
+package testgen
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+// runnerMock repeats the seed as many times as it says for the generator and
+// counts the numbers for the solution
+type runnerMock struct {
+	runs int
+}
+
+func (r *runnerMock) Run(ctx context.Context, src string, languageID int32, stdin string) (string, error) {
+	r.runs++
+	switch src {
+	case "gen":
+		n, err := strconv.Atoi(stdin)
+		if err != nil {
+			return "", errors.New("bad seed")
+		}
+		return strings.Repeat("7 ", n), nil
+	case "sol":
+		return strconv.Itoa(len(strings.Fields(stdin))), nil
+	}
+	return "", errors.New("compilation error")
+}
+
+func TestGenerate(t *testing.T) {
+	runner := &runnerMock{}
+	tcs, err := New(runner).Generate(context.Background(), shared.Generator{
+		LanguageID: 71,
+		Src:        "gen",
+		Solution:   "sol",
+		Seeds:      []string{"3", "100000"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tcs) != 2 || runner.runs != 4 {
+		t.Fatalf("expected two test cases, got %d after %d runs", len(tcs), runner.runs)
+	}
+	if tcs[0].Input != "7 7 7 " || tcs[0].Output != "3" || tcs[1].Output != "100000" {
+		t.Errorf("unexpected test cases %+v", tcs[0])
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	cases := map[string]shared.Generator{
+		"generator fails": {Src: "gen", Solution: "sol", Seeds: []string{"x"}},
+		"empty input":     {Src: "gen", Solution: "sol", Seeds: []string{"0"}},
+		"solution fails":  {Src: "gen", Solution: "bad", Seeds: []string{"1"}},
+		"too large":       {Src: "gen", Solution: "sol", Seeds: []string{"9000000"}},
+	}
+	for name, generator := range cases {
+		_, err := New(&runnerMock{}).Generate(context.Background(), generator)
+		if !errors.Is(err, shared.ErrGenerator) {
+			t.Errorf("%s: expected generator error, got %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package textsearch
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// MinTermLength matches innodb_ft_min_token_size, shorter words are
+	// never indexed
+	MinTermLength = 3
+	maxTerms      = 8
+)
+
+var folds = map[rune]rune{
+	'á': 'a', 'à': 'a', 'ä': 'a', 'â': 'a',
+	'é': 'e', 'è': 'e', 'ë': 'e', 'ê': 'e',
+	'í': 'i', 'ì': 'i', 'ï': 'i', 'î': 'i',
+	'ó': 'o', 'ò': 'o', 'ö': 'o', 'ô': 'o',
+	'ú': 'u', 'ù': 'u', 'ü': 'u', 'û': 'u',
+	'ñ': 'n', 'ç': 'c',
+}
+
+// stopwords are left out of the documents and the queries, InnoDB only
+// ships an English list
+var stopwords = map[string]bool{
+	"al": true, "ante": true, "aun": true, "como": true, "con": true,
+	"cual": true, "cuando": true, "de": true, "del": true, "desde": true,
+	"donde": true, "durante": true, "el": true, "ella": true, "ellos": true,
+	"en": true, "entre": true, "era": true, "es": true, "esa": true,
+	"ese": true, "eso": true, "esta": true, "este": true, "esto": true,
+	"fue": true, "ha": true, "hay": true, "la": true, "las": true,
+	"le": true, "les": true, "lo": true, "los": true, "mas": true,
+	"muy": true, "nos": true, "nuestra": true, "nuestro": true, "para": true,
+	"pero": true, "por": true, "que": true, "se": true, "ser": true,
+	"sin": true, "sobre": true, "son": true, "su": true, "sus": true,
+	"tambien": true, "te": true, "tiene": true, "todo": true, "tu": true,
+	"un": true, "una": true, "uno": true, "unos": true, "unas": true,
+	"usted": true, "ya": true, "yo": true, "y": true, "o": true,
+	"a": true, "e": true, "u": true, "ni": true, "mi": true,
+}
+
+// foldRune lowercases and strips the accents of a rune, every rune maps to
+// exactly one rune so positions are kept
+func foldRune(r rune) rune {
+	r = unicode.ToLower(r)
+	if folded, ok := folds[r]; ok {
+		return folded
+	}
+	return r
+}
+
+func Fold(text string) string {
+	return strings.Map(foldRune, text)
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(Fold(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Document is the text indexed for the given fields, folded and without
+// stopwords
+func Document(fields ...string) string {
+	res := []string{}
+	for _, field := range fields {
+		for _, word := range words(field) {
+			if !stopwords[word] {
+				res = append(res, word)
+			}
+		}
+	}
+	return strings.Join(res, " ")
+}
+
+// Terms are the searchable words of a query. Plurals are trimmed so the
+// prefix matches both forms, "programadores" finds "programador"
+func Terms(query string) []string {
+	res := []string{}
+	seen := make(map[string]bool)
+	for _, word := range words(query) {
+		if stopwords[word] || len([]rune(word)) < MinTermLength {
+			continue
+		}
+		term := stem(word)
+		if seen[term] {
+			continue
+		}
+		seen[term] = true
+		res = append(res, term)
+		if len(res) == maxTerms {
+			break
+		}
+	}
+	return res
+}
+
+func stem(word string) string {
+	length := len([]rune(word))
+	switch {
+	case length > 5 && strings.HasSuffix(word, "es"):
+		return strings.TrimSuffix(word, "es")
+	case length > 4 && strings.HasSuffix(word, "s"):
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+// BooleanQuery requires every term as a prefix, an empty query means the
+// search is not filtered by text
+func BooleanQuery(terms []string) string {
+	res := make([]string, 0, len(terms))
+	for _, term := range terms {
+		res = append(res, "+"+term+"*")
+	}
+	return strings.Join(res, " ")
+}
+
+// Fragment is a piece of a snippet, Match marks the pieces to highlight
+type Fragment struct {
+	Text  string
+	Match bool
+}
+
+// Snippet cuts around width runes of text around the first match of the
+// terms, or its beginning when nothing matches
+func Snippet(text string, terms []string, width int) []Fragment {
+	runes := []rune(text)
+	folded := []rune(Fold(text))
+	matches := make([]bool, len(runes))
+	first := -1
+	for i := range folded {
+		if i > 0 && isWordRune(folded[i-1]) {
+			continue
+		}
+		for _, term := range terms {
+			termRunes := []rune(term)
+			if !hasPrefixAt(folded, termRunes, i) {
+				continue
+			}
+			// the whole word is highlighted, the term is only its prefix
+			end := i + len(termRunes)
+			for end < len(folded) && isWordRune(folded[end]) {
+				end++
+			}
+			for j := i; j < end; j++ {
+				matches[j] = true
+			}
+			if first == -1 {
+				first = i
+			}
+			break
+		}
+	}
+	start := 0
+	if first > width/3 {
+		start = first - width/3
+	}
+	end := start + width
+	if end > len(runes) {
+		end = len(runes)
+	}
+	fragments := []Fragment{}
+	if start > 0 {
+		fragments = append(fragments, Fragment{Text: "…"})
+	}
+	for i := start; i < end; {
+		j := i
+		for j < end && matches[j] == matches[i] {
+			j++
+		}
+		fragments = append(fragments, Fragment{Text: string(runes[i:j]), Match: matches[i]})
+		i = j
+	}
+	if end < len(runes) {
+		fragments = append(fragments, Fragment{Text: "…"})
+	}
+	return fragments
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func hasPrefixAt(text, prefix []rune, at int) bool {
+	if at+len(prefix) > len(text) {
+		return false
+	}
+	for k, r := range prefix {
+		if text[at+k] != r {
+			return false
+		}
+	}
+	return true
+}
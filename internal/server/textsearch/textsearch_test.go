@@ -0,0 +1,85 @@
+package textsearch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	if got := Fold("Programación en Año Ünico"); got != "programacion en ano unico" {
+		t.Errorf("unexpected fold %q", got)
+	}
+}
+
+func TestDocument(t *testing.T) {
+	got := Document("Desarrollador de Software", "Buscamos a alguien con **experiencia** en Go.")
+	expected := "desarrollador software buscamos alguien experiencia go"
+	if got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
+
+func TestTerms(t *testing.T) {
+	cases := map[string][]string{
+		"programacion":                  {"programacion"},
+		"Programación de los Servicios": {"programacion", "servicio"},
+		"programadores programador":     {"programador"},
+		"la de en":                      {},
+		"go C# rust":                    {"rust"},
+	}
+	for query, expected := range cases {
+		if got := Terms(query); !reflect.DeepEqual(got, expected) {
+			t.Errorf("%q: expected %v, got %v", query, expected, got)
+		}
+	}
+}
+
+func TestBooleanQuery(t *testing.T) {
+	if got := BooleanQuery([]string{"backend", "java"}); got != "+backend* +java*" {
+		t.Errorf("unexpected query %q", got)
+	}
+	if got := BooleanQuery(nil); got != "" {
+		t.Errorf("expected an empty query, got %q", got)
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	fragments := Snippet("Buscamos expertos en programación funcional", []string{"programacion"}, 100)
+	expected := []Fragment{
+		{Text: "Buscamos expertos en "},
+		{Text: "programación", Match: true},
+		{Text: " funcional"},
+	}
+	if !reflect.DeepEqual(fragments, expected) {
+		t.Errorf("expected %v, got %v", expected, fragments)
+	}
+}
+
+func TestSnippetWindow(t *testing.T) {
+	text := "aaaa bbbb cccc dddd eeee ffff gggg hhhh"
+	fragments := Snippet(text, []string{"ffff"}, 12)
+	if fragments[0].Text != "…" || fragments[len(fragments)-1].Text != "…" {
+		t.Errorf("expected ellipsis around the window, got %v", fragments)
+	}
+	found := false
+	for _, fragment := range fragments {
+		if fragment.Match && fragment.Text == "ffff" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected the match inside the window, got %v", fragments)
+	}
+}
+
+func TestSnippetNoMatch(t *testing.T) {
+	fragments := Snippet("sin coincidencias", []string{"java"}, 100)
+	if len(fragments) != 1 || fragments[0].Match {
+		t.Errorf("expected the plain text, got %v", fragments)
+	}
+	// words that only contain the term are not highlighted
+	fragments = Snippet("javascript y ajava", []string{"java"}, 100)
+	if fragments[0].Text != "javascript" || !fragments[0].Match || fragments[1].Match {
+		t.Errorf("unexpected fragments %v", fragments)
+	}
+}
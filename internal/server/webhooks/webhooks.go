@@ -0,0 +1,211 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+const (
+	EventHeader     = "X-Spotted-Event"
+	DeliveryHeader  = "X-Spotted-Delivery"
+	TimestampHeader = "X-Spotted-Timestamp"
+	SignatureHeader = "X-Spotted-Signature"
+)
+
+const (
+	// MaxAttempts spreads the retries over about 20 hours with the backoff
+	// below
+	MaxAttempts = 12
+	firstRetry  = time.Minute
+	maxRetry    = 6 * time.Hour
+	batchSize   = 50
+	// the column of the last error is 255 characters long
+	maxErrorLength = 255
+)
+
+// NewSecret is the signing key of a new webhook, shown to the company so
+// its receiver can check the signature
+func NewSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// Sign is the HMAC-SHA256 of the timestamp and the body, the timestamp is
+// signed too so an old delivery can't be replayed
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Backoff is the wait after the given number of failed attempts, doubled
+// every time up to maxRetry
+func Backoff(attempts int) time.Duration {
+	delay := firstRetry
+	for i := 1; i < attempts && delay < maxRetry; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetry)
+}
+
+var errPrivateAddress = errors.New("la dirección del webhook no es pública")
+
+// NewClient doesn't follow redirects, a receiver answering with one is
+// misconfigured and the delivery fails. The URLs are chosen by the companies,
+// so the client only connects to public addresses and never through a proxy
+func NewClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   dialControl,
+	}).DialContext
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// dialControl runs after the name is resolved, so a public name pointing to
+// an internal address is rejected too
+func dialControl(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if !isPublicAddr(addr) {
+		return fmt.Errorf("%w: %s", errPrivateAddress, addr)
+	}
+	return nil
+}
+
+// isPublicAddr rejects loopback, private, link-local (the cloud metadata
+// services), multicast and unspecified addresses
+func isPublicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
+
+type DispatcherStorage interface {
+	SelectDueDeliveries(ctx context.Context, now time.Time, limit int) ([]shared.WebhookDelivery, error)
+	UpdateDeliveryAttempt(ctx context.Context, delivery shared.WebhookDelivery) error
+}
+
+// RunDispatcher sends the due deliveries of the outbox every interval until
+// the context is cancelled
+func RunDispatcher(ctx context.Context, storage DispatcherStorage, client *http.Client, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			Dispatch(ctx, storage, client, time.Now())
+		}
+	}
+}
+
+func Dispatch(ctx context.Context, storage DispatcherStorage, client *http.Client, now time.Time) {
+	deliveries, err := storage.SelectDueDeliveries(ctx, now, batchSize)
+	if err != nil {
+		log.Println("webhooks:", err)
+		return
+	}
+	for _, delivery := range deliveries {
+		delivery = Attempt(ctx, client, delivery, now)
+		if err := storage.UpdateDeliveryAttempt(ctx, delivery); err != nil {
+			log.Println("webhooks:", err)
+		}
+	}
+}
+
+// Attempt sends the delivery once and returns it with the outcome, a failed
+// one is scheduled again until it runs out of attempts
+func Attempt(ctx context.Context, client *http.Client, delivery shared.WebhookDelivery, now time.Time) shared.WebhookDelivery {
+	delivery.Attempts++
+	code, err := send(ctx, client, delivery, now)
+	delivery.ResponseCode = code
+	if err == nil {
+		delivery.Status = shared.DeliveryDelivered
+		delivery.DeliveredAt = now
+		delivery.LastError = ""
+		return delivery
+	}
+	delivery.LastError = truncate(err.Error())
+	if delivery.Attempts >= MaxAttempts {
+		delivery.Status = shared.DeliveryFailed
+		return delivery
+	}
+	delivery.Status = shared.DeliveryPending
+	delivery.NextAttemptAt = now.Add(Backoff(delivery.Attempts))
+	return delivery
+}
+
+func send(ctx context.Context, client *http.Client, delivery shared.WebhookDelivery, now time.Time) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.Secret, timestamp, body))
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// the connection is reused only if the body is read
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("respuesta %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+func truncate(msg string) string {
+	runes := []rune(msg)
+	if len(runes) <= maxErrorLength {
+		return msg
+	}
+	return string(runes[:maxErrorLength])
+}
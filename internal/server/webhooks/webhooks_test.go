@@ -0,0 +1,205 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kw3a/spotted-server/internal/server/shared"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"offer.archived"}`)
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := Sign("secret", 1700000000, body); got != expected {
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+	if Sign("secret", 1700000001, body) == expected {
+		t.Error("expected the timestamp to change the signature")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cases := map[int]time.Duration{
+		1:  time.Minute,
+		2:  2 * time.Minute,
+		5:  16 * time.Minute,
+		9:  256 * time.Minute,
+		10: 6 * time.Hour,
+		30: 6 * time.Hour,
+	}
+	for attempts, expected := range cases {
+		if got := Backoff(attempts); got != expected {
+			t.Errorf("%d attempts: expected %v, got %v", attempts, expected, got)
+		}
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	first, err := NewSecret()
+	if err != nil {
+		t.Fatalf("expected nil, got %v", err)
+	}
+	second, _ := NewSecret()
+	if len(first) != 64 || first == second {
+		t.Errorf("unexpected secrets %q, %q", first, second)
+	}
+}
+
+func delivery(url string) shared.WebhookDelivery {
+	return shared.WebhookDelivery{
+		ID:      "delivery-id",
+		URL:     url,
+		Secret:  "secret",
+		Event:   shared.EventOfferArchived,
+		Payload: `{"event":"offer.archived"}`,
+		Status:  shared.DeliveryPending,
+	}
+}
+
+func TestAttemptDelivered(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+		if r.Header.Get(SignatureHeader) != Sign("secret", timestamp, body) {
+			t.Error("invalid signature")
+		}
+		if r.Header.Get(EventHeader) != shared.EventOfferArchived || r.Header.Get(DeliveryHeader) != "delivery-id" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	result := Attempt(context.Background(), server.Client(), delivery(server.URL), now)
+	if result.Status != shared.DeliveryDelivered || result.Attempts != 1 || result.ResponseCode != http.StatusNoContent {
+		t.Errorf("unexpected delivery %+v", result)
+	}
+	if !result.DeliveredAt.Equal(now) {
+		t.Errorf("expected delivered at %v, got %v", now, result.DeliveredAt)
+	}
+}
+
+func TestAttemptRetry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	pending := delivery(server.URL)
+	pending.Attempts = 2
+	result := Attempt(context.Background(), server.Client(), pending, now)
+	if result.Status != shared.DeliveryPending || result.Attempts != 3 || result.ResponseCode != http.StatusInternalServerError {
+		t.Errorf("unexpected delivery %+v", result)
+	}
+	if !result.NextAttemptAt.Equal(now.Add(4 * time.Minute)) {
+		t.Errorf("unexpected next attempt %v", result.NextAttemptAt)
+	}
+	if result.LastError == "" {
+		t.Error("expected the error to be kept")
+	}
+}
+
+func TestAttemptFailed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://example.com", http.StatusFound)
+	}))
+	defer server.Close()
+	last := delivery(server.URL)
+	last.Attempts = MaxAttempts - 1
+	// the test server listens on loopback, only the redirect policy is tested
+	client := NewClient()
+	client.Transport = server.Client().Transport
+	result := Attempt(context.Background(), client, last, time.Now())
+	if result.Status != shared.DeliveryFailed || result.Attempts != MaxAttempts || result.ResponseCode != http.StatusFound {
+		t.Errorf("unexpected delivery %+v", result)
+	}
+}
+
+func TestAttemptPrivateAddress(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the request reached a loopback address")
+	}))
+	defer server.Close()
+	result := Attempt(context.Background(), NewClient(), delivery(server.URL), time.Now())
+	if result.Status != shared.DeliveryPending || result.ResponseCode != 0 {
+		t.Errorf("unexpected delivery %+v", result)
+	}
+	if !strings.Contains(result.LastError, errPrivateAddress.Error()) {
+		t.Errorf("expected %v, got %q", errPrivateAddress, result.LastError)
+	}
+}
+
+func TestPublicAddr(t *testing.T) {
+	cases := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.0.0.5", false},
+		{"172.16.3.4", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+	for _, c := range cases {
+		if isPublicAddr(netip.MustParseAddr(c.addr)) != c.public {
+			t.Errorf("%s: expected public %v", c.addr, c.public)
+		}
+	}
+}
+
+type dispatcherStorage struct {
+	deliveries []shared.WebhookDelivery
+	updated    []shared.WebhookDelivery
+	err        error
+}
+
+func (d *dispatcherStorage) SelectDueDeliveries(ctx context.Context, now time.Time, limit int) ([]shared.WebhookDelivery, error) {
+	return d.deliveries, d.err
+}
+
+func (d *dispatcherStorage) UpdateDeliveryAttempt(ctx context.Context, delivery shared.WebhookDelivery) error {
+	d.updated = append(d.updated, delivery)
+	return nil
+}
+
+func TestDispatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	storage := &dispatcherStorage{deliveries: []shared.WebhookDelivery{delivery(server.URL), delivery(server.URL)}}
+	Dispatch(context.Background(), storage, server.Client(), time.Now())
+	if len(storage.updated) != 2 {
+		t.Fatalf("expected 2 updates, got %d", len(storage.updated))
+	}
+	for _, updated := range storage.updated {
+		if updated.Status != shared.DeliveryDelivered {
+			t.Errorf("unexpected delivery %+v", updated)
+		}
+	}
+	storage = &dispatcherStorage{err: errors.New("error")}
+	Dispatch(context.Background(), storage, server.Client(), time.Now())
+	if len(storage.updated) != 0 {
+		t.Error("expected no updates")
+	}
+}
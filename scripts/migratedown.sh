@@ -4,7 +4,11 @@ if [ -f .env ]; then
     source .env
 fi
 
+# The reverse of migrateup.sh, every step stops at the last version of the
+# other directory
 cd sql/schema
-goose mysql $DATABASE_URL down-to 0
+goose mysql $DATABASE_URL down-to 21
 cd ../triggers
-goose mysql $DATABASE_URL down-to 0
\ No newline at end of file
+goose mysql $DATABASE_URL down-to 18
+cd ../schema
+goose mysql $DATABASE_URL down-to 0
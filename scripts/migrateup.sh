@@ -4,7 +4,12 @@ if [ -f .env ]; then
     source .env
 fi
 
+# Both directories share the goose version table, so they are applied in
+# version order: the schema up to the triggers, the triggers, and the rest
+# of the schema. Later triggers live in sql/schema with their version.
 cd sql/schema
-goose mysql $DATABASE_URL up
+goose mysql $DATABASE_URL up-to 18
 cd ../triggers
 goose mysql $DATABASE_URL up
+cd ../schema
+goose mysql $DATABASE_URL up
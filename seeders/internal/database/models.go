@@ -9,6 +9,13 @@ import (
 	"time"
 )
 
+type BankProblem struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CompanyID string
+}
+
 type Company struct {
 	ID          string
 	Name        string
@@ -96,31 +103,44 @@ type Offer struct {
 }
 
 type Participation struct {
-	ID        string
-	CreatedAt sql.NullTime
-	UpdatedAt sql.NullTime
-	ExpiresAt time.Time
-	UserID    string
-	QuizID    string
+	ID         string
+	CreatedAt  sql.NullTime
+	UpdatedAt  sql.NullTime
+	ExpiresAt  time.Time
+	UserID     string
+	QuizID     string
+	FinishedAt sql.NullTime
+	EndReason  string
+	PausedAt   sql.NullTime
 }
 
 type Problem struct {
-	ID          string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Description string
-	Title       string
-	MemoryLimit int32
-	TimeLimit   float64
-	QuizID      string
+	ID            string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Description   string
+	Title         string
+	MemoryLimit   int32
+	TimeLimit     float64
+	BankProblemID string
+	Version       int32
 }
 
 type Quiz struct {
-	ID        string
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	Duration  int32
-	OfferID   string
+	ID         string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Duration   int32
+	OfferID    string
+	OpensAt    sql.NullTime
+	ClosesAt   sql.NullTime
+	InviteOnly bool
+}
+
+type QuizProblem struct {
+	QuizID    string
+	ProblemID string
+	Position  int32
 }
 
 type Skill struct {
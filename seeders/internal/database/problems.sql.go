@@ -30,19 +30,36 @@ func (q *Queries) DeleteProblems(ctx context.Context, ids []string) error {
 	return err
 }
 
+const seedBankProblem = `-- name: SeedBankProblem :exec
+INSERT INTO bank_problem
+(id, company_id) VALUES
+(?, ?)
+`
+
+type SeedBankProblemParams struct {
+	ID        string
+	CompanyID string
+}
+
+func (q *Queries) SeedBankProblem(ctx context.Context, arg SeedBankProblemParams) error {
+	_, err := q.db.ExecContext(ctx, seedBankProblem, arg.ID, arg.CompanyID)
+	return err
+}
+
 const seedProblem = `-- name: SeedProblem :exec
 INSERT INTO problem 
-(id, description, title, memory_limit, time_limit, quiz_id) VALUES
-(?, ?, ?, ?, ?, ?)
+(id, description, title, memory_limit, time_limit, bank_problem_id, version) VALUES
+(?, ?, ?, ?, ?, ?, ?)
 `
 
 type SeedProblemParams struct {
-	ID          string
-	Description string
-	Title       string
-	MemoryLimit int32
-	TimeLimit   float64
-	QuizID      string
+	ID            string
+	Description   string
+	Title         string
+	MemoryLimit   int32
+	TimeLimit     float64
+	BankProblemID string
+	Version       int32
 }
 
 func (q *Queries) SeedProblem(ctx context.Context, arg SeedProblemParams) error {
@@ -52,7 +69,25 @@ func (q *Queries) SeedProblem(ctx context.Context, arg SeedProblemParams) error
 		arg.Title,
 		arg.MemoryLimit,
 		arg.TimeLimit,
-		arg.QuizID,
+		arg.BankProblemID,
+		arg.Version,
 	)
 	return err
 }
+
+const seedQuizProblem = `-- name: SeedQuizProblem :exec
+INSERT INTO quiz_problem
+(quiz_id, problem_id, position) VALUES
+(?, ?, ?)
+`
+
+type SeedQuizProblemParams struct {
+	QuizID    string
+	ProblemID string
+	Position  int32
+}
+
+func (q *Queries) SeedQuizProblem(ctx context.Context, arg SeedQuizProblemParams) error {
+	_, err := q.db.ExecContext(ctx, seedQuizProblem, arg.QuizID, arg.ProblemID, arg.Position)
+	return err
+}
@@ -64,7 +64,7 @@ func main() {
 		if err != nil {
 			log.Fatal(err)
 		}
-		problemIDs, err := seedCfg.seedProblems(quizesID)
+		problemIDs, err := seedCfg.seedProblems(companies[0], quizesID[0])
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -9,7 +9,9 @@ import (
 
 const problemsPath = "problems"
 
-func (cfg *SeedersConfig) seedProblems(quizIDs []string) ([]string, error) {
+// seedProblems adds the problems to the library of the company and uses
+// their first version in the quiz
+func (cfg *SeedersConfig) seedProblems(companyID string, quizID string) ([]string, error) {
 	description1, err := getText(problemsPath + "/description1.seed")
 	if err != nil {
 		return []string{}, err
@@ -25,7 +27,7 @@ func (cfg *SeedersConfig) seedProblems(quizIDs []string) ([]string, error) {
 			Title:       "Two Sum",
 			MemoryLimit: 262144,
 			TimeLimit:   1,
-			QuizID:      quizIDs[0],
+			Version:     1,
 		},
 		{
 			ID:          uuid.New().String(),
@@ -33,14 +35,30 @@ func (cfg *SeedersConfig) seedProblems(quizIDs []string) ([]string, error) {
 			Title:       "Add Two Numbers",
 			MemoryLimit: 262144,
 			TimeLimit:   1,
-			QuizID:      quizIDs[0],
+			Version:     1,
 		},
 	}
-	for _, problem := range problems {
+	for position, problem := range problems {
+		problem.BankProblemID = uuid.New().String()
+		err = cfg.DB.SeedBankProblem(cfg.Ctx, database.SeedBankProblemParams{
+			ID:        problem.BankProblemID,
+			CompanyID: companyID,
+		})
+		if err != nil {
+			return []string{}, err
+		}
 		err = cfg.DB.SeedProblem(cfg.Ctx, problem)
 		if err != nil {
 			return []string{}, err
 		}
+		err = cfg.DB.SeedQuizProblem(cfg.Ctx, database.SeedQuizProblemParams{
+			QuizID:    quizID,
+			ProblemID: problem.ID,
+			Position:  int32(position),
+		})
+		if err != nil {
+			return []string{}, err
+		}
 	}
 	IDs := []string{}
 	for _, problem := range problems {
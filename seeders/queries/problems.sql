@@ -1,8 +1,18 @@
+-- name: SeedBankProblem :exec
+INSERT INTO bank_problem
+(id, company_id) VALUES
+(?, ?);
+
 -- name: SeedProblem :exec
 INSERT INTO problem 
-(id, description, title, memory_limit, time_limit, quiz_id) VALUES
-(?, ?, ?, ?, ?, ?);
+(id, description, title, memory_limit, time_limit, bank_problem_id, version) VALUES
+(?, ?, ?, ?, ?, ?, ?);
+
+-- name: SeedQuizProblem :exec
+INSERT INTO quiz_problem
+(quiz_id, problem_id, position) VALUES
+(?, ?, ?);
 
 -- name: DeleteProblems :exec
 DELETE FROM problem
-WHERE id IN (sqlc.slice('ids'));
\ No newline at end of file
+WHERE id IN (sqlc.slice('ids'));
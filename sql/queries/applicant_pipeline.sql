@@ -0,0 +1,100 @@
+-- name: SelectCompanyStages :many
+SELECT company_stage.*
+FROM company_stage
+WHERE company_stage.company_id = ?
+ORDER BY company_stage.position;
+
+-- name: SelectOfferStages :many
+SELECT company_stage.*
+FROM company_stage
+JOIN offer ON offer.company_id = company_stage.company_id
+WHERE offer.id = ?
+ORDER BY company_stage.position;
+
+-- name: SelectParticipationStages :many
+SELECT company_stage.*
+FROM company_stage
+JOIN offer ON offer.company_id = company_stage.company_id
+JOIN quiz ON quiz.offer_id = offer.id
+JOIN participation ON participation.quiz_id = quiz.id
+WHERE participation.id = ?
+ORDER BY company_stage.position;
+
+-- name: DeleteCompanyStages :exec
+DELETE FROM company_stage
+WHERE company_id = ?;
+
+-- name: InsertCompanyStage :exec
+INSERT INTO company_stage (company_id, name, label, position)
+VALUES (?, ?, ?, ?);
+
+-- name: UpsertParticipationStage :exec
+INSERT INTO participation_review (participation_id, stage)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE stage = VALUES(stage);
+
+-- name: UpsertParticipationRating :exec
+INSERT INTO participation_review (participation_id, rating)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE rating = VALUES(rating);
+
+-- name: InsertStageChange :exec
+INSERT INTO participation_stage_history (id, from_stage, to_stage, participation_id, user_id)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: InsertParticipationNote :exec
+INSERT INTO participation_note (id, body, participation_id, user_id)
+VALUES (?, ?, ?, ?);
+
+-- name: InsertParticipationTag :exec
+INSERT IGNORE INTO participation_tag (participation_id, tag)
+VALUES (?, ?);
+
+-- name: DeleteParticipationTag :exec
+DELETE FROM participation_tag
+WHERE participation_id = ? AND tag = ?;
+
+-- name: CountParticipationTags :one
+SELECT COUNT(*)
+FROM participation_tag
+WHERE participation_id = ?;
+
+-- name: BatchParticipationReviews :many
+SELECT participation_review.*
+FROM participation_review
+WHERE participation_review.participation_id IN (sqlc.slice('participation_ids'));
+
+-- name: BatchStageHistory :many
+SELECT participation_stage_history.*, user.name AS recruiter_name
+FROM participation_stage_history
+JOIN user ON participation_stage_history.user_id = user.id
+WHERE participation_stage_history.participation_id IN (sqlc.slice('participation_ids'))
+ORDER BY participation_stage_history.created_at ASC;
+
+-- name: BatchParticipationNotes :many
+SELECT participation_note.*, user.name AS recruiter_name
+FROM participation_note
+JOIN user ON participation_note.user_id = user.id
+WHERE participation_note.participation_id IN (sqlc.slice('participation_ids'))
+ORDER BY participation_note.created_at DESC;
+
+-- name: BatchParticipationTags :many
+SELECT participation_tag.*
+FROM participation_tag
+WHERE participation_tag.participation_id IN (sqlc.slice('participation_ids'))
+ORDER BY participation_tag.tag;
+
+-- name: SelectCompanyPipeline :one
+SELECT company_pipeline.*
+FROM company_pipeline
+WHERE company_pipeline.company_id = ?;
+
+-- name: UpsertCompanyPipeline :exec
+INSERT INTO company_pipeline (company_id, share_stage)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE share_stage = VALUES(share_stage);
+
+-- name: UpsertParticipationWithdrawal :exec
+INSERT INTO participation_review (participation_id, withdrawn_at)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE withdrawn_at = VALUES(withdrawn_at);
@@ -0,0 +1,24 @@
+-- name: SelectCandidateApplications :many
+SELECT participation.id AS participation_id, participation.created_at AS participation_created_at,
+  participation.expires_at AS participation_expires_at, participation.finished_at AS participation_finished_at,
+  participation.end_reason AS participation_end_reason,
+  participation.paused_at AS participation_paused_at, quiz.id AS quiz_id,
+  offer.id AS offer_id, offer.title AS offer_title, offer.status AS offer_status,
+  company.id AS company_id, company.name AS company_name, company.image_url AS company_image_url,
+  COALESCE(participation_review.stage, "") AS stage, participation_review.withdrawn_at,
+  COALESCE(company_pipeline.share_stage, FALSE) AS share_stage
+FROM participation
+JOIN quiz ON participation.quiz_id = quiz.id
+JOIN offer ON quiz.offer_id = offer.id
+JOIN company ON offer.company_id = company.id
+LEFT JOIN participation_review ON participation_review.participation_id = participation.id
+LEFT JOIN company_pipeline ON company_pipeline.company_id = company.id
+WHERE participation.user_id = ?
+ORDER BY participation.created_at DESC
+LIMIT ? OFFSET ?;
+
+-- name: SelectParticipationByCandidate :one
+SELECT participation.*
+FROM participation
+WHERE participation.id = ? AND participation.user_id = ?
+FOR UPDATE;
@@ -0,0 +1,86 @@
+-- name: InsertBankProblem :exec
+INSERT INTO bank_problem (id, company_id)
+VALUES (?, ?);
+
+-- name: InsertQuizProblem :exec
+INSERT INTO quiz_problem (quiz_id, problem_id, position)
+VALUES (?, ?, ?);
+
+-- name: SelectBankProblems :many
+SELECT
+    bank_problem.id,
+    problem.id AS problem_id,
+    problem.version,
+    problem.title,
+    problem.time_limit,
+    problem.difficulty,
+    problem.tag,
+    problem.created_at,
+    (
+        SELECT COUNT(DISTINCT quiz_problem.quiz_id)
+        FROM quiz_problem
+        JOIN problem versions ON quiz_problem.problem_id = versions.id
+        WHERE versions.bank_problem_id = bank_problem.id
+    ) AS quizzes
+FROM bank_problem
+JOIN problem ON problem.bank_problem_id = bank_problem.id
+WHERE bank_problem.company_id = ?
+    AND problem.version = (
+        SELECT MAX(latest.version)
+        FROM problem latest
+        WHERE latest.bank_problem_id = bank_problem.id
+    )
+ORDER BY problem.title ASC;
+
+-- name: SelectBankProblemByUser :one
+SELECT
+    bank_problem.id,
+    bank_problem.company_id,
+    problem.id AS problem_id,
+    problem.version,
+    problem.title,
+    problem.description,
+    problem.memory_limit,
+    problem.time_limit,
+    problem.signature,
+    problem.difficulty,
+    problem.tag
+FROM bank_problem
+JOIN company ON bank_problem.company_id = company.id
+JOIN company_member ON company_member.company_id = company.id
+JOIN problem ON problem.bank_problem_id = bank_problem.id
+WHERE bank_problem.id = ? AND company_member.user_id = ? AND company_member.role IN ("owner", "admin", "recruiter")
+ORDER BY problem.version DESC
+LIMIT 1
+FOR UPDATE;
+
+-- name: SelectProblemVersions :many
+SELECT problem.id, problem.version, problem.created_at, COUNT(quiz_problem.quiz_id) AS quizzes
+FROM problem
+LEFT JOIN quiz_problem ON quiz_problem.problem_id = problem.id
+WHERE problem.bank_problem_id = ?
+GROUP BY problem.id, problem.version, problem.created_at
+ORDER BY problem.version DESC;
+
+-- name: SelectLatestVersions :many
+SELECT problem.id, problem.bank_problem_id
+FROM problem
+JOIN bank_problem ON problem.bank_problem_id = bank_problem.id
+WHERE bank_problem.company_id = ?
+    AND bank_problem.id IN (sqlc.slice('bank_problem_ids'))
+    AND problem.version = (
+        SELECT MAX(latest.version)
+        FROM problem latest
+        WHERE latest.bank_problem_id = bank_problem.id
+    );
+
+-- name: SelectQuizBankProblems :many
+SELECT problem.id, problem.bank_problem_id, problem.version
+FROM problem
+JOIN quiz_problem ON quiz_problem.problem_id = problem.id
+WHERE quiz_problem.quiz_id = ?
+ORDER BY quiz_problem.position;
+
+-- name: DeleteQuizProblems :exec
+DELETE FROM quiz_problem
+WHERE quiz_id = ?;
@@ -0,0 +1,37 @@
+-- name: SelectOfferBlindReview :one
+SELECT offer_blind_review.*
+FROM offer_blind_review
+WHERE offer_blind_review.offer_id = ?;
+
+-- name: SelectParticipationBlindReview :one
+SELECT offer_blind_review.*
+FROM offer_blind_review
+JOIN quiz ON quiz.offer_id = offer_blind_review.offer_id
+JOIN participation ON participation.quiz_id = quiz.id
+WHERE participation.id = ?;
+
+-- name: UpsertOfferBlindReview :exec
+INSERT INTO offer_blind_review (offer_id, reveal_stage)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE reveal_stage = VALUES(reveal_stage);
+
+-- name: DeleteOfferBlindReview :exec
+DELETE FROM offer_blind_review
+WHERE offer_id = ?;
+
+-- name: SelectOfferParticipationStages :many
+SELECT participation.id, participation_review.stage
+FROM participation
+JOIN quiz ON participation.quiz_id = quiz.id
+LEFT JOIN participation_review ON participation_review.participation_id = participation.id
+WHERE quiz.offer_id = ?;
+
+-- name: InsertIdentityReveal :exec
+INSERT IGNORE INTO identity_reveal (participation_id, stage, reason, user_id)
+VALUES (?, ?, ?, ?);
+
+-- name: BatchIdentityReveals :many
+SELECT identity_reveal.*, user.name AS recruiter_name
+FROM identity_reveal
+JOIN user ON identity_reveal.user_id = user.id
+WHERE identity_reveal.participation_id IN (sqlc.slice('participation_ids'));
@@ -0,0 +1,10 @@
+-- name: SelectCheckers :many
+SELECT problem_checker.path, problem_checker.src
+FROM problem_checker
+WHERE problem_checker.problem_id = ?
+ORDER BY problem_checker.path;
+
+-- name: InsertChecker :exec
+INSERT INTO problem_checker
+(id, problem_id, path, src)
+VALUES (?, ?, ?, ?);
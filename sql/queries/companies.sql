@@ -6,7 +6,8 @@ VALUES (?, ?, ?, ?, ?, ?);
 -- name: SelectCompany :one
 SELECT company.*
 FROM company
-WHERE company.id = ? AND company.user_id = ?;
+JOIN company_member ON company_member.company_id = company.id
+WHERE company.id = ? AND company_member.user_id = ? AND company_member.role IN ("owner", "admin");
 
 -- name: GetCompanies :many
 SELECT company.*
@@ -16,19 +17,25 @@ LIMIT ? OFFSET ?;
 -- name: GetCompaniesByUser :many
 SELECT company.*
 FROM company
-WHERE company.user_id = ?
+JOIN company_member ON company_member.company_id = company.id
+WHERE company_member.user_id = ?
 LIMIT ? OFFSET ?;
 
 -- name: GetCompaniesByQuery :many
 SELECT company.*
 FROM company
-WHERE company.name LIKE CONCAT('%', ?, '%')
+JOIN company_document ON company_document.company_id = company.id
+WHERE MATCH(company_document.body) AGAINST(sqlc.arg(query) IN BOOLEAN MODE)
+ORDER BY MATCH(company_document.body) AGAINST(sqlc.arg(query) IN BOOLEAN MODE) DESC, company.name
 LIMIT ? OFFSET ?;
 
 -- name: GetCompaniesByUserAndQuery :many
 SELECT company.*
 FROM company
-WHERE company.name LIKE CONCAT('%', ?, '%') AND company.user_id = ?
+JOIN company_member ON company_member.company_id = company.id
+JOIN company_document ON company_document.company_id = company.id
+WHERE MATCH(company_document.body) AGAINST(sqlc.arg(query) IN BOOLEAN MODE) AND company_member.user_id = sqlc.arg(user_id)
+ORDER BY MATCH(company_document.body) AGAINST(sqlc.arg(query) IN BOOLEAN MODE) DESC, company.name
 LIMIT ? OFFSET ?;
 
 -- name: GetCompanyByID :one
@@ -0,0 +1,88 @@
+-- name: InsertCompanyMember :exec
+INSERT INTO company_member (company_id, user_id, role)
+VALUES (?, ?, ?)
+ON DUPLICATE KEY UPDATE role = role;
+
+-- name: SelectCompanyRole :one
+SELECT company_member.role
+FROM company_member
+WHERE company_member.company_id = ? AND company_member.user_id = ?;
+
+-- name: SelectCompanyMembers :many
+SELECT company_member.user_id, company_member.role, company_member.created_at,
+  user.nick, user.name, user.email, user.image_url
+FROM company_member
+JOIN user ON company_member.user_id = user.id
+WHERE company_member.company_id = ?
+ORDER BY company_member.created_at, user.name;
+
+-- name: SelectInviteeMember :one
+SELECT company_member.user_id
+FROM company_member
+JOIN user ON company_member.user_id = user.id
+WHERE company_member.company_id = sqlc.arg(company_id)
+  AND (user.nick = sqlc.arg(invitee) OR user.email = sqlc.arg(invitee))
+LIMIT 1;
+
+-- name: UpdateCompanyMemberRole :exec
+UPDATE company_member
+SET role = ?
+WHERE company_id = ? AND user_id = ?;
+
+-- name: DeleteCompanyMember :exec
+DELETE FROM company_member
+WHERE company_id = ? AND user_id = ?;
+
+-- name: UpdateCompanyOwner :exec
+UPDATE company
+SET user_id = ?
+WHERE id = ?;
+
+-- name: SelectUserMemberships :many
+SELECT company.id AS company_id, company.name AS company_name, company.image_url AS company_image_url,
+  company_member.role
+FROM company_member
+JOIN company ON company_member.company_id = company.id
+WHERE company_member.user_id = ?
+ORDER BY company.name;
+
+-- name: UpsertCompanyInvitation :exec
+INSERT INTO company_invitation (id, company_id, invitee, role, invited_by)
+VALUES (?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE id = VALUES(id), role = VALUES(role), invited_by = VALUES(invited_by),
+  declined_at = NULL, created_at = CURRENT_TIMESTAMP;
+
+-- name: SelectCompanyInvitations :many
+SELECT company_invitation.id, company_invitation.created_at, company_invitation.invitee,
+  company_invitation.role, company_invitation.declined_at, user.name AS invited_by
+FROM company_invitation
+JOIN user ON company_invitation.invited_by = user.id
+WHERE company_invitation.company_id = ?
+ORDER BY company_invitation.created_at DESC;
+
+-- name: DeleteCompanyInvitation :exec
+DELETE FROM company_invitation
+WHERE id = ? AND company_id = ?;
+
+-- name: SelectUserInvitations :many
+SELECT company_invitation.id, company_invitation.created_at, company_invitation.role,
+  company.id AS company_id, company.name AS company_name, company.image_url AS company_image_url,
+  inviter.name AS invited_by
+FROM company_invitation
+JOIN company ON company_invitation.company_id = company.id
+JOIN user AS inviter ON company_invitation.invited_by = inviter.id
+JOIN user ON company_invitation.invitee = user.nick OR company_invitation.invitee = user.email
+WHERE user.id = ? AND company_invitation.declined_at IS NULL
+ORDER BY company_invitation.created_at DESC;
+
+-- name: SelectUserInvitation :one
+SELECT company_invitation.id, company_invitation.role, company_invitation.company_id
+FROM company_invitation
+JOIN user ON company_invitation.invitee = user.nick OR company_invitation.invitee = user.email
+WHERE company_invitation.id = ? AND user.id = ? AND company_invitation.declined_at IS NULL
+FOR UPDATE;
+
+-- name: DeclineCompanyInvitation :exec
+UPDATE company_invitation
+SET declined_at = ?
+WHERE id = ?;
@@ -0,0 +1,29 @@
+-- name: InsertInvitation :exec
+INSERT INTO quiz_invitation (id, email, code, quiz_id)
+VALUES (?, ?, ?, ?);
+
+-- name: SelectInvitationByEmail :one
+SELECT quiz_invitation.id
+FROM quiz_invitation
+WHERE quiz_invitation.quiz_id = ? AND quiz_invitation.email = ?;
+
+-- name: SelectInvitations :many
+SELECT quiz_invitation.*
+FROM quiz_invitation
+WHERE quiz_invitation.quiz_id = ?
+ORDER BY quiz_invitation.created_at ASC, quiz_invitation.email ASC;
+
+-- name: SelectInvitationByCode :one
+SELECT quiz_invitation.*
+FROM quiz_invitation
+WHERE quiz_invitation.quiz_id = ? AND quiz_invitation.code = ?
+FOR UPDATE;
+
+-- name: ClaimInvitation :exec
+UPDATE quiz_invitation
+SET used_at = ?, user_id = ?
+WHERE quiz_invitation.id = ?;
+
+-- name: DeleteInvitation :exec
+DELETE FROM quiz_invitation
+WHERE quiz_invitation.id = ? AND quiz_invitation.quiz_id = ?;
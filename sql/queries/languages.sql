@@ -13,3 +13,7 @@ ORDER BY name;
 INSERT INTO language_quiz
 (id, quiz_id, language_id)
 VALUES (?, ?, ?);
+
+-- name: DeleteLanguageQuizzes :exec
+DELETE FROM language_quiz
+WHERE quiz_id = ?;
@@ -0,0 +1,96 @@
+-- name: InsertNotification :exec
+INSERT INTO notification (id, category, title, link, in_app, digest_pending, user_id)
+VALUES (?, ?, ?, ?, ?, ?, ?);
+
+-- name: SelectUserNotifications :many
+SELECT notification.*
+FROM notification
+WHERE notification.user_id = ? AND notification.in_app = TRUE
+ORDER BY notification.created_at DESC
+LIMIT ?;
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*)
+FROM notification
+WHERE notification.user_id = ? AND notification.in_app = TRUE AND notification.read_at IS NULL;
+
+-- name: SelectUserNotification :one
+SELECT notification.*
+FROM notification
+WHERE notification.id = ? AND notification.user_id = ? AND notification.in_app = TRUE;
+
+-- name: MarkNotificationRead :exec
+UPDATE notification
+SET read_at = ?
+WHERE id = ? AND user_id = ? AND read_at IS NULL;
+
+-- name: MarkNotificationsRead :exec
+UPDATE notification
+SET read_at = ?
+WHERE user_id = ? AND read_at IS NULL;
+
+-- name: SelectNotificationPreferences :many
+SELECT notification_preference.*
+FROM notification_preference
+WHERE notification_preference.user_id = ?;
+
+-- name: SelectNotificationPreference :one
+SELECT notification_preference.*
+FROM notification_preference
+WHERE notification_preference.user_id = ? AND notification_preference.category = ?;
+
+-- name: UpsertNotificationPreference :exec
+INSERT INTO notification_preference (user_id, category, in_app, email)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE in_app = VALUES(in_app), email = VALUES(email);
+
+-- name: SelectPendingDigests :many
+SELECT notification.id, notification.created_at, notification.title, notification.link,
+  user.id AS user_id, user.name, user.email
+FROM notification
+JOIN user ON notification.user_id = user.id
+WHERE notification.digest_pending = TRUE
+ORDER BY user.id, notification.created_at;
+
+-- name: ClearNotificationDigest :exec
+UPDATE notification
+SET digest_pending = FALSE
+WHERE user_id = ? AND digest_pending = TRUE AND created_at <= ?;
+
+-- name: SelectParticipationNotification :one
+SELECT participation.id, participation.user_id, user.name, user.email,
+  offer.id AS offer_id, offer.title AS offer_title,
+  COALESCE(company_pipeline.share_stage, FALSE) AS share_stage,
+  offer_blind_review.reveal_stage, identity_reveal.stage AS revealed_stage
+FROM participation
+JOIN quiz ON participation.quiz_id = quiz.id
+JOIN offer ON quiz.offer_id = offer.id
+JOIN company ON offer.company_id = company.id
+JOIN user ON participation.user_id = user.id
+LEFT JOIN company_pipeline ON company_pipeline.company_id = company.id
+LEFT JOIN offer_blind_review ON offer_blind_review.offer_id = offer.id
+LEFT JOIN identity_reveal ON identity_reveal.participation_id = participation.id
+WHERE participation.id = ?;
+
+-- name: SelectOfferApplicantsNotification :many
+SELECT participation.id, user.id AS user_id, user.email, offer.title AS offer_title
+FROM participation
+JOIN quiz ON participation.quiz_id = quiz.id
+JOIN offer ON quiz.offer_id = offer.id
+JOIN user ON participation.user_id = user.id
+WHERE offer.id = ?;
+
+-- name: SelectOfferTeamNotification :many
+-- the members who can act on the applicants of the offer
+SELECT user.id AS user_id, user.email
+FROM offer
+JOIN company_member ON company_member.company_id = offer.company_id
+JOIN user ON company_member.user_id = user.id
+WHERE offer.id = ? AND company_member.role IN ("owner", "admin", "recruiter");
+
+-- name: SelectInvitationNotification :many
+SELECT user.id AS user_id, user.email, company.name AS company_name, company_invitation.role
+FROM company_invitation
+JOIN company ON company_invitation.company_id = company.id
+JOIN user ON company_invitation.invitee = user.nick OR company_invitation.invitee = user.email
+WHERE company_invitation.id = ?;
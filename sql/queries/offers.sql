@@ -6,34 +6,22 @@ WHERE offer.id = ?
 LIMIT 1;
 
 -- name: GetOfferByUser :one
-SELECT offer.*, company.name as company_name, company.image_url as company_image_url
+SELECT offer.*, company.name as company_name, company.image_url as company_image_url,
+  company_member.role AS member_role
 FROM offer
 JOIN company ON offer.company_id = company.id
-JOIN user ON company.user_id = user.id
+JOIN company_member ON company_member.company_id = company.id
+JOIN user ON company_member.user_id = user.id
 WHERE offer.id = ? AND user.id = ?
 LIMIT 1;
 
--- name: GetOffers :many
-SELECT offer.*, company.name as company_name, company.image_url as company_image_url
-FROM offer
-JOIN company ON offer.company_id= company.id
-WHERE offer.status = 1
-ORDER BY offer.created_at DESC
-LIMIT ? OFFSET ?;
-
--- name: GetOffersByQuery :many
-SELECT offer.*, company.name as company_name, company.image_url as company_image_url
-FROM offer
-JOIN company ON offer.company_id = company.id
-WHERE offer.title LIKE CONCAT('%', ?, '%') AND offer.status = 1
-ORDER BY offer.created_at DESC
-LIMIT ? OFFSET ?;
-
 -- name: GetOffersByUser :many
-SELECT offer.*, company.name as company_name, company.image_url as company_image_url
+SELECT offer.*, company.name as company_name, company.image_url as company_image_url,
+  company_member.role AS member_role
 FROM offer
 JOIN company ON offer.company_id = company.id
-JOIN user ON company.user_id = user.id
+JOIN company_member ON company_member.company_id = company.id
+JOIN user ON company_member.user_id = user.id
 WHERE user.id = ? 
 ORDER BY offer.created_at DESC
 LIMIT ? OFFSET ?;
@@ -42,7 +30,7 @@ LIMIT ? OFFSET ?;
 SELECT offer.*, company.name as company_name, company.image_url as company_image_url
 FROM offer
 JOIN company ON offer.company_id = company.id
-WHERE company.id = ? 
+WHERE company.id = ? AND offer.status != "draft"
 ORDER BY offer.created_at DESC
 LIMIT ? OFFSET ?;
 
@@ -65,11 +53,31 @@ LIMIT 1;
 
 -- name: InsertOffer :exec
 INSERT INTO offer
-(id, title, about, requirements, benefits, min_wage, max_wage, company_id)
-VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+(id, title, about, requirements, benefits, min_wage, max_wage, company_id, status,
+location, modality, seniority, contract, currency, wage_period)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 
--- name: ArchiveOffer :exec
-UPDATE offer
+-- name: SelectOfferStatusByUser :one
+SELECT offer.status, offer.company_id
+FROM offer
 JOIN company ON offer.company_id = company.id
-SET offer.status = -1
-WHERE offer.id = ? AND company.user_id = ?;
+JOIN company_member ON company_member.company_id = company.id
+WHERE offer.id = ? AND company_member.user_id = ? AND company_member.role IN ("owner", "admin", "recruiter")
+FOR UPDATE;
+
+-- name: GetOfferStatusByQuiz :one
+SELECT offer.status
+FROM offer
+JOIN quiz ON offer.id = quiz.offer_id
+WHERE quiz.id = ?;
+
+-- name: UpdateOfferStatus :exec
+UPDATE offer
+SET status = ?
+WHERE id = ?;
+
+-- name: UpdateOffer :exec
+UPDATE offer
+SET title = ?, about = ?, requirements = ?, benefits = ?, min_wage = ?, max_wage = ?,
+    location = ?, modality = ?, seniority = ?, contract = ?, currency = ?, wage_period = ?
+WHERE id = ?;
@@ -7,16 +7,55 @@ WHERE participation.user_id = ? AND participation.quiz_id = ?;
 INSERT INTO participation (id, user_id, quiz_id)
 VALUES (?, ?, ?);
 
--- name: EndParticipation :exec
+-- name: EndParticipation :execrows
 UPDATE participation
-SET expires_at = ?
-WHERE participation.user_id = ? AND participation.quiz_id = ?;
+SET expires_at = ?, finished_at = ?, end_reason = ?
+WHERE participation.user_id = ? AND participation.quiz_id = ? AND participation.finished_at IS NULL;
 
 -- name: SelectApplications :many
 SELECT user.*, participation.id as participation_id, participation.created_at as participation_created_at, 
-  participation.expires_at as participation_expires_at
+  participation.expires_at as participation_expires_at, participation.end_reason as participation_end_reason,
+  participation.paused_at as participation_paused_at
 FROM user
 JOIN participation ON user.id = participation.user_id
 WHERE participation.quiz_id = ?
 ORDER BY participation.created_at DESC;
 
+-- name: SelectExpiredParticipations :many
+SELECT participation.id, participation.expires_at, COUNT(submission.id) AS submissions
+FROM participation
+LEFT JOIN submission ON submission.participation_id = participation.id
+WHERE participation.finished_at IS NULL AND participation.paused_at IS NULL
+  AND participation.expires_at <= ?
+GROUP BY participation.id;
+
+-- name: FinishExpiredParticipation :execrows
+UPDATE participation
+SET finished_at = ?, end_reason = ?
+WHERE participation.id = ? AND participation.finished_at IS NULL
+  AND participation.expires_at <= ? AND participation.paused_at IS NULL;
+
+-- name: FinishParticipation :exec
+UPDATE participation
+SET finished_at = ?, end_reason = ?
+WHERE participation.id = ? AND participation.finished_at IS NULL;
+
+-- name: SelectParticipationByRecruiter :one
+SELECT participation.*
+FROM participation
+JOIN quiz ON participation.quiz_id = quiz.id
+JOIN offer ON quiz.offer_id = offer.id
+JOIN company ON offer.company_id = company.id
+JOIN company_member ON company_member.company_id = company.id
+WHERE participation.id = ? AND company_member.user_id = ? AND company_member.role IN ("owner", "admin", "recruiter")
+FOR UPDATE;
+
+-- name: UpdateParticipationTiming :exec
+UPDATE participation
+SET expires_at = ?, paused_at = ?, finished_at = ?, end_reason = ?
+WHERE participation.id = ?;
+
+-- name: CountParticipations :one
+SELECT COUNT(*) AS count
+FROM participation
+WHERE participation.quiz_id = ?;
@@ -0,0 +1,10 @@
+-- name: InsertParticipationAdjustment :exec
+INSERT INTO participation_adjustment (id, action, minutes, reason, participation_id, user_id)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: BatchParticipationAdjustments :many
+SELECT participation_adjustment.*, user.name AS recruiter_name
+FROM participation_adjustment
+JOIN user ON participation_adjustment.user_id = user.id
+WHERE participation_adjustment.participation_id IN (sqlc.slice('participation_ids'))
+ORDER BY participation_adjustment.created_at ASC;
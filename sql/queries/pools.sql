@@ -0,0 +1,33 @@
+-- name: InsertQuizPool :exec
+INSERT INTO quiz_pool (id, quiz_id, position, tag, difficulty, draw)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: SelectQuizPools :many
+SELECT tag, difficulty, draw
+FROM quiz_pool
+WHERE quiz_id = ?
+ORDER BY position;
+
+-- name: SelectPoolCandidates :many
+SELECT problem.id, problem.tag, problem.difficulty
+FROM problem
+INNER JOIN quiz_problem ON problem.id = quiz_problem.problem_id
+WHERE quiz_problem.quiz_id = ?
+ORDER BY quiz_problem.position;
+
+-- name: InsertParticipationProblem :exec
+INSERT IGNORE INTO participation_problem (participation_id, problem_id, position)
+VALUES (?, ?, ?);
+
+-- name: SelectParticipationProblemIDs :many
+SELECT problem_id
+FROM participation_problem
+WHERE participation_id = ?
+ORDER BY position;
+
+-- name: BatchParticipationProblems :many
+SELECT participation_problem.participation_id, problem.id, problem.tag, problem.difficulty
+FROM participation_problem
+JOIN problem ON participation_problem.problem_id = problem.id
+WHERE participation_problem.participation_id IN (/*SLICE:participation_ids*/?)
+ORDER BY participation_problem.participation_id, participation_problem.position;
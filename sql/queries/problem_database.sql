@@ -0,0 +1,9 @@
+-- name: SelectProblemDatabase :one
+SELECT problem_database.schema_sql, problem_database.seed_sql, problem_database.ordered
+FROM problem_database
+WHERE problem_database.problem_id = ?;
+
+-- name: InsertProblemDatabase :exec
+INSERT INTO problem_database
+(problem_id, schema_sql, seed_sql, ordered)
+VALUES (?, ?, ?, ?);
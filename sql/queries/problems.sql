@@ -3,21 +3,22 @@
 SELECT problem.*
 FROM problem
 WHERE problem.id = ?;
---
 
 -- name: SelectProblemIDs :many
 SELECT problem.id
 FROM problem
-INNER JOIN quiz ON problem.quiz_id = quiz.id
-WHERE quiz.id = ?;
+INNER JOIN quiz_problem ON problem.id = quiz_problem.problem_id
+WHERE quiz_problem.quiz_id = ?
+ORDER BY quiz_problem.position;
 
 -- name: SelectProblems :many
 SELECT problem.*
 FROM problem
-INNER JOIN quiz ON problem.quiz_id = quiz.id
-WHERE quiz.id = ?;
+INNER JOIN quiz_problem ON problem.id = quiz_problem.problem_id
+WHERE quiz_problem.quiz_id = ?
+ORDER BY quiz_problem.position;
 
 -- name: InsertProblem :exec
 INSERT INTO problem
-(id, quiz_id, title, description, memory_limit, time_limit)
-VALUES (?, ?, ?, ?, ?, ?);
+(id, bank_problem_id, version, title, description, memory_limit, time_limit, signature, difficulty, tag)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
@@ -0,0 +1,32 @@
+-- name: InsertProctoringEvent :exec
+INSERT INTO proctoring_event
+(id, participation_id, kind, detail)
+VALUES (?, ?, ?, ?);
+
+-- name: CountProctoringEvents :one
+SELECT COUNT(*) AS count
+FROM proctoring_event
+WHERE participation_id = ? AND kind = ?;
+
+-- name: BatchProctoringCounts :many
+SELECT participation_id, kind, COUNT(*) AS amount
+FROM proctoring_event
+WHERE participation_id IN (sqlc.slice('participation_ids'))
+GROUP BY participation_id, kind;
+
+-- name: SelectProctoringRule :one
+SELECT proctoring_rule.*
+FROM proctoring_rule
+WHERE quiz_id = ? AND kind = ?;
+
+-- name: SelectProctoringRules :many
+SELECT proctoring_rule.*
+FROM proctoring_rule
+WHERE quiz_id = ?
+ORDER BY kind;
+
+-- name: UpsertProctoringRule :exec
+INSERT INTO proctoring_rule
+(id, quiz_id, kind, threshold)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE threshold = VALUES(threshold);
@@ -0,0 +1,69 @@
+-- name: SelectQuestion :one
+SELECT question.kind, question.points, question.rubric, question.tolerance
+FROM question
+WHERE question.problem_id = ?;
+
+-- name: SelectQuestionOptions :many
+SELECT question_option.label, question_option.correct
+FROM question_option
+WHERE question_option.problem_id = ?
+ORDER BY question_option.position;
+
+-- name: SelectQuestionAnswers :many
+SELECT question_answer.value
+FROM question_answer
+WHERE question_answer.problem_id = ?
+ORDER BY question_answer.position;
+
+-- name: InsertQuestion :exec
+INSERT INTO question
+(problem_id, kind, points, rubric, tolerance)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: InsertQuestionOption :exec
+INSERT INTO question_option
+(id, problem_id, position, label, correct)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: InsertQuestionAnswer :exec
+INSERT INTO question_answer
+(id, problem_id, position, value)
+VALUES (?, ?, ?, ?);
+
+-- name: UpsertQuestionResponse :exec
+INSERT INTO question_response
+(id, participation_id, problem_id, answer, score)
+VALUES (?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE answer = VALUES(answer), score = VALUES(score), feedback = "";
+
+-- name: SelectQuestionResponse :one
+SELECT question_response.id, question_response.answer, question_response.score, question_response.feedback
+FROM question_response
+JOIN participation ON question_response.participation_id = participation.id
+WHERE question_response.problem_id = ? AND participation.user_id = ? AND participation.quiz_id = ?;
+
+-- name: BatchQuestionResponses :many
+SELECT question_response.id, question_response.participation_id, question_response.problem_id,
+  question_response.answer, question_response.score, question_response.feedback, problem.title
+FROM question_response
+JOIN problem ON question_response.problem_id = problem.id
+JOIN quiz_problem ON quiz_problem.problem_id = problem.id
+JOIN participation ON question_response.participation_id = participation.id
+  AND quiz_problem.quiz_id = participation.quiz_id
+WHERE question_response.participation_id IN (sqlc.slice('participation_ids'))
+ORDER BY quiz_problem.position;
+
+-- name: SelectResponseByRecruiter :one
+SELECT question_response.problem_id
+FROM question_response
+JOIN participation ON question_response.participation_id = participation.id
+JOIN quiz ON participation.quiz_id = quiz.id
+JOIN offer ON quiz.offer_id = offer.id
+JOIN company ON offer.company_id = company.id
+JOIN company_member ON company_member.company_id = company.id
+WHERE question_response.id = ? AND company_member.user_id = ? AND company_member.role IN ("owner", "admin", "recruiter");
+
+-- name: GradeQuestionResponse :exec
+UPDATE question_response
+SET score = ?, feedback = ?
+WHERE question_response.id = ?;
@@ -3,6 +3,27 @@ SELECT quiz.*
 FROM quiz
 WHERE quiz.offer_id = ?;
 
+-- name: LockQuizByOffer :one
+SELECT quiz.*
+FROM quiz
+WHERE quiz.offer_id = ?
+FOR UPDATE;
+
 -- name: InsertQuiz :exec
-INSERT INTO quiz (id, duration, offer_id)
-VALUES (?, ?, ?);
+INSERT INTO quiz (id, duration, offer_id, shuffle)
+VALUES (?, ?, ?, ?);
+
+-- name: GetQuiz :one
+SELECT quiz.*
+FROM quiz
+WHERE quiz.id = ?;
+
+-- name: UpdateQuizAccess :exec
+UPDATE quiz
+SET opens_at = ?, closes_at = ?, invite_only = ?
+WHERE quiz.id = ?;
+
+-- name: UpdateQuizDuration :exec
+UPDATE quiz
+SET duration = ?
+WHERE quiz.id = ?;
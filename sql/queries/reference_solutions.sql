@@ -0,0 +1,51 @@
+-- name: CopyReferenceSolutions :exec
+INSERT INTO reference_solution (id, problem_id, language_id, src, expected)
+SELECT UUID(), sqlc.arg('new_problem_id'), reference_solution.language_id, reference_solution.src, reference_solution.expected
+FROM reference_solution
+WHERE reference_solution.problem_id = sqlc.arg('problem_id');
+
+-- name: DeleteReferenceResults :exec
+DELETE reference_result
+FROM reference_result
+JOIN reference_solution ON reference_result.reference_solution_id = reference_solution.id
+WHERE reference_solution.problem_id = ?;
+
+-- name: DeleteReferenceSolution :exec
+DELETE reference_solution
+FROM reference_solution
+JOIN problem ON reference_solution.problem_id = problem.id
+JOIN bank_problem ON problem.bank_problem_id = bank_problem.id
+JOIN company ON bank_problem.company_id = company.id
+JOIN company_member ON company_member.company_id = company.id
+WHERE reference_solution.id = ? AND bank_problem.id = ? AND company_member.user_id = ? AND company_member.role IN ("owner", "admin", "recruiter");
+
+-- name: InsertReferenceSolution :exec
+INSERT INTO reference_solution
+(id, problem_id, language_id, src, expected)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: SelectReferenceResults :many
+SELECT reference_result.reference_solution_id, reference_result.test_case_id, reference_result.status, reference_result.time, reference_result.memory
+FROM reference_result
+JOIN reference_solution ON reference_result.reference_solution_id = reference_solution.id
+WHERE reference_solution.problem_id = ?;
+
+-- name: SelectReferenceSolutions :many
+SELECT
+    reference_solution.id,
+    reference_solution.problem_id,
+    reference_solution.language_id,
+    language.display_name AS language,
+    reference_solution.src,
+    reference_solution.expected,
+    (SELECT COUNT(*) FROM test_case WHERE test_case.problem_id = reference_solution.problem_id) AS test_cases
+FROM reference_solution
+JOIN language ON reference_solution.language_id = language.id
+WHERE reference_solution.problem_id = ?
+ORDER BY reference_solution.created_at, reference_solution.id;
+
+-- name: UpsertReferenceResult :exec
+INSERT INTO reference_result
+(reference_solution_id, test_case_id, status, time, memory)
+VALUES (?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE status = VALUES(status), time = VALUES(time), memory = VALUES(memory);
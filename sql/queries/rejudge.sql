@@ -0,0 +1,115 @@
+-- name: DeleteSubmissionJudgedEvent :exec
+DELETE FROM submission_judged_event
+WHERE submission_id = ?;
+
+-- name: DeleteSubmissionResults :exec
+DELETE FROM test_case_result
+WHERE submission_id = ?;
+
+-- name: InsertRejudge :exec
+INSERT INTO rejudge
+(id, scope, target_id, quiz_id, user_id)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: InsertRejudgeSubmission :exec
+INSERT INTO rejudge_submission (rejudge_id, submission_id, accepted_before)
+VALUES (?, ?, ?);
+
+-- name: InsertSubmissionResults :exec
+INSERT INTO test_case_result (submission_id, test_case_id, output)
+SELECT submission.id, test_case.id, ""
+FROM submission
+JOIN test_case ON test_case.problem_id = COALESCE(submission.rejudged_problem_id, submission.problem_id)
+WHERE submission.id = ?;
+
+-- name: MoveQuizProblem :exec
+UPDATE quiz_problem
+SET problem_id = sqlc.arg('new_problem_id')
+WHERE quiz_id = sqlc.arg('quiz_id') AND problem_id = sqlc.arg('problem_id');
+
+-- name: RejudgeOnVersion :exec
+UPDATE submission
+JOIN participation ON submission.participation_id = participation.id
+SET submission.rejudged_problem_id = sqlc.arg('new_problem_id')
+WHERE participation.quiz_id = sqlc.arg('quiz_id')
+  AND COALESCE(submission.rejudged_problem_id, submission.problem_id) = sqlc.arg('problem_id');
+
+-- name: ResetSubmission :exec
+UPDATE submission
+SET accepted_test_cases = 0
+WHERE id = ?;
+
+-- name: SelectLatestVersion :one
+SELECT latest.id
+FROM problem current
+JOIN problem latest ON latest.bank_problem_id = current.bank_problem_id
+WHERE current.id = ?
+ORDER BY latest.version DESC
+LIMIT 1;
+
+-- name: SelectQuizProblemByRecruiter :one
+SELECT quiz.id
+FROM quiz_problem
+JOIN quiz ON quiz_problem.quiz_id = quiz.id
+JOIN offer ON quiz.offer_id = offer.id
+JOIN company ON offer.company_id = company.id
+JOIN company_member ON company_member.company_id = company.id
+WHERE offer.id = ? AND quiz_problem.problem_id = ? AND company_member.user_id = ? AND company_member.role IN ("owner", "admin", "recruiter");
+
+-- name: SelectRejudgeByRecruiter :one
+SELECT rejudge.*, offer.id AS offer_id
+FROM rejudge
+JOIN quiz ON rejudge.quiz_id = quiz.id
+JOIN offer ON quiz.offer_id = offer.id
+JOIN company ON offer.company_id = company.id
+JOIN company_member ON company_member.company_id = company.id
+WHERE rejudge.id = ? AND company_member.user_id = ?;
+
+-- name: SelectRejudgeRows :many
+SELECT
+    rejudge_submission.submission_id,
+    rejudge_submission.accepted_before,
+    rejudge_submission.error,
+    submission.accepted_test_cases AS accepted_after,
+    problem.title AS problem_title,
+    participation.id AS participation_id,
+    user.id AS user_id,
+    user.name AS applicant,
+    offer_blind_review.reveal_stage,
+    identity_reveal.stage AS revealed_stage,
+    (SELECT COUNT(*) FROM test_case_result WHERE test_case_result.submission_id = submission.id) AS total,
+    (SELECT COUNT(*) FROM test_case_result WHERE test_case_result.submission_id = submission.id AND test_case_result.status <> "") AS judged
+FROM rejudge_submission
+JOIN submission ON rejudge_submission.submission_id = submission.id
+JOIN problem ON COALESCE(submission.rejudged_problem_id, submission.problem_id) = problem.id
+JOIN participation ON submission.participation_id = participation.id
+JOIN user ON participation.user_id = user.id
+JOIN quiz ON participation.quiz_id = quiz.id
+LEFT JOIN offer_blind_review ON offer_blind_review.offer_id = quiz.offer_id
+LEFT JOIN identity_reveal ON identity_reveal.participation_id = participation.id
+WHERE rejudge_submission.rejudge_id = ?
+ORDER BY participation.created_at, problem.title, submission.created_at;
+
+-- name: SelectRejudgeSubmissions :many
+SELECT submission.id, submission.src, submission.language_id, COALESCE(submission.rejudged_problem_id, submission.problem_id) AS problem_id, submission.accepted_test_cases, problem.signature
+FROM submission
+JOIN participation ON submission.participation_id = participation.id
+JOIN problem ON COALESCE(submission.rejudged_problem_id, submission.problem_id) = problem.id
+WHERE participation.quiz_id = ?
+  AND (COALESCE(submission.rejudged_problem_id, submission.problem_id) = ? OR submission.participation_id = ? OR submission.id = ?)
+FOR UPDATE;
+
+-- name: SelectSubmissionByRecruiter :one
+SELECT participation.quiz_id
+FROM submission
+JOIN participation ON submission.participation_id = participation.id
+JOIN quiz ON participation.quiz_id = quiz.id
+JOIN offer ON quiz.offer_id = offer.id
+JOIN company ON offer.company_id = company.id
+JOIN company_member ON company_member.company_id = company.id
+WHERE submission.id = ? AND company_member.user_id = ? AND company_member.role IN ("owner", "admin", "recruiter");
+
+-- name: UpdateRejudgeError :exec
+UPDATE rejudge_submission
+SET error = ?
+WHERE rejudge_id = ? AND submission_id = ?;
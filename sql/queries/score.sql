@@ -3,7 +3,11 @@ SELECT submission.*, language.display_name as language
 FROM submission
 JOIN participation ON submission.participation_id = participation.id
 JOIN language ON submission.language_id = language.id
-WHERE submission.problem_id = ? and participation.user_id = ?
+WHERE COALESCE(submission.rejudged_problem_id, submission.problem_id) = ? and participation.user_id = ?
+  AND submission.created_at <= participation.expires_at
+  -- problem versions can be shared by several quizzes, only the quiz being
+  -- solved counts
+  AND participation.quiz_id = ?
 ORDER BY submission.accepted_test_cases DESC, submission.created_at ASC
 LIMIT 1;
 
@@ -16,14 +20,16 @@ FROM (
         language.display_name, 
         COUNT(DISTINCT test_case.id) AS total_test_cases,
         ROW_NUMBER() OVER (
-            PARTITION BY s.participation_id, s.problem_id 
+            PARTITION BY s.participation_id, COALESCE(s.rejudged_problem_id, s.problem_id) 
             ORDER BY s.accepted_test_cases DESC, s.created_at ASC
         ) AS rk
     FROM submission s
     JOIN language ON s.language_id = language.id
-    JOIN problem ON s.problem_id = problem.id
+    JOIN problem ON COALESCE(s.rejudged_problem_id, s.problem_id) = problem.id
+    JOIN participation ON s.participation_id = participation.id
     LEFT JOIN test_case ON problem.id = test_case.problem_id
     WHERE s.participation_id IN (sqlc.slice('participation_ids'))
+      AND s.created_at <= participation.expires_at
     GROUP BY s.id  
 ) ranked
 WHERE ranked.rk = 1;
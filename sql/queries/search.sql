@@ -0,0 +1,116 @@
+-- name: SearchOffers :many
+SELECT ranked.*
+FROM (
+  SELECT offer.*, company.name as company_name, company.image_url as company_image_url,
+    CASE sqlc.arg(sort)
+      WHEN "wage" THEN offer.max_wage * IF(offer.wage_period = "month", 12, 1)
+      WHEN "relevance" THEN 2 * MATCH(offer_document.title) AGAINST(sqlc.arg(query) IN BOOLEAN MODE)
+        + MATCH(offer_document.body) AGAINST(sqlc.arg(query) IN BOOLEAN MODE)
+      ELSE UNIX_TIMESTAMP(offer.created_at)
+    END AS sort_key
+  FROM offer
+  JOIN company ON offer.company_id = company.id
+  LEFT JOIN offer_document ON offer_document.offer_id = offer.id
+  WHERE offer.status = "published"
+    AND (sqlc.arg(query) = "" OR MATCH(offer_document.body) AGAINST(sqlc.arg(query) IN BOOLEAN MODE))
+    AND (sqlc.arg(modality) = "" OR offer.modality = sqlc.arg(modality))
+    AND (sqlc.arg(seniority) = "" OR offer.seniority = sqlc.arg(seniority))
+    AND (sqlc.arg(contract) = "" OR offer.contract = sqlc.arg(contract))
+    AND (sqlc.arg(company_id) = "" OR offer.company_id = sqlc.arg(company_id))
+    AND (sqlc.arg(currency) = "" OR offer.currency = sqlc.arg(currency))
+    AND (sqlc.arg(min_wage) = 0 OR offer.max_wage * IF(offer.wage_period = "month", 12, 1) >= sqlc.arg(min_wage))
+    AND (sqlc.arg(max_wage) = 0 OR offer.min_wage * IF(offer.wage_period = "month", 12, 1) <= sqlc.arg(max_wage))
+    AND (sqlc.arg(tags) = "" OR EXISTS (
+      SELECT 1 FROM offer_tag
+      WHERE offer_tag.offer_id = offer.id AND FIND_IN_SET(offer_tag.language_id, sqlc.arg(tags))
+    ))
+) AS ranked
+WHERE sqlc.arg(after_id) = ""
+  OR ranked.sort_key < sqlc.arg(after_key)
+  OR (ranked.sort_key = sqlc.arg(after_key) AND ranked.id < sqlc.arg(after_id))
+ORDER BY ranked.sort_key DESC, ranked.id DESC
+LIMIT ?;
+
+-- name: CountOfferModalities :many
+SELECT offer.modality, COUNT(*) AS count
+FROM offer
+LEFT JOIN offer_document ON offer_document.offer_id = offer.id
+WHERE offer.status = "published"
+  AND (sqlc.arg(query) = "" OR MATCH(offer_document.body) AGAINST(sqlc.arg(query) IN BOOLEAN MODE))
+  AND (sqlc.arg(seniority) = "" OR offer.seniority = sqlc.arg(seniority))
+  AND (sqlc.arg(contract) = "" OR offer.contract = sqlc.arg(contract))
+  AND (sqlc.arg(company_id) = "" OR offer.company_id = sqlc.arg(company_id))
+  AND (sqlc.arg(currency) = "" OR offer.currency = sqlc.arg(currency))
+  AND (sqlc.arg(min_wage) = 0 OR offer.max_wage * IF(offer.wage_period = "month", 12, 1) >= sqlc.arg(min_wage))
+  AND (sqlc.arg(max_wage) = 0 OR offer.min_wage * IF(offer.wage_period = "month", 12, 1) <= sqlc.arg(max_wage))
+  AND (sqlc.arg(tags) = "" OR EXISTS (
+    SELECT 1 FROM offer_tag
+    WHERE offer_tag.offer_id = offer.id AND FIND_IN_SET(offer_tag.language_id, sqlc.arg(tags))
+  ))
+GROUP BY offer.modality
+ORDER BY count DESC;
+
+-- name: CountOfferTags :many
+SELECT language.id, language.display_name, COUNT(*) AS count
+FROM offer_tag
+JOIN offer ON offer_tag.offer_id = offer.id
+JOIN language ON offer_tag.language_id = language.id
+LEFT JOIN offer_document ON offer_document.offer_id = offer.id
+WHERE offer.status = "published"
+  AND (sqlc.arg(query) = "" OR MATCH(offer_document.body) AGAINST(sqlc.arg(query) IN BOOLEAN MODE))
+  AND (sqlc.arg(modality) = "" OR offer.modality = sqlc.arg(modality))
+  AND (sqlc.arg(seniority) = "" OR offer.seniority = sqlc.arg(seniority))
+  AND (sqlc.arg(contract) = "" OR offer.contract = sqlc.arg(contract))
+  AND (sqlc.arg(company_id) = "" OR offer.company_id = sqlc.arg(company_id))
+  AND (sqlc.arg(currency) = "" OR offer.currency = sqlc.arg(currency))
+  AND (sqlc.arg(min_wage) = 0 OR offer.max_wage * IF(offer.wage_period = "month", 12, 1) >= sqlc.arg(min_wage))
+  AND (sqlc.arg(max_wage) = 0 OR offer.min_wage * IF(offer.wage_period = "month", 12, 1) <= sqlc.arg(max_wage))
+GROUP BY language.id, language.display_name
+ORDER BY count DESC, language.display_name
+LIMIT 20;
+
+-- name: CountOfferCompanies :many
+SELECT company.id, company.name, COUNT(*) AS count
+FROM offer
+JOIN company ON offer.company_id = company.id
+LEFT JOIN offer_document ON offer_document.offer_id = offer.id
+WHERE offer.status = "published"
+  AND (sqlc.arg(query) = "" OR MATCH(offer_document.body) AGAINST(sqlc.arg(query) IN BOOLEAN MODE))
+  AND (sqlc.arg(modality) = "" OR offer.modality = sqlc.arg(modality))
+  AND (sqlc.arg(seniority) = "" OR offer.seniority = sqlc.arg(seniority))
+  AND (sqlc.arg(contract) = "" OR offer.contract = sqlc.arg(contract))
+  AND (sqlc.arg(currency) = "" OR offer.currency = sqlc.arg(currency))
+  AND (sqlc.arg(min_wage) = 0 OR offer.max_wage * IF(offer.wage_period = "month", 12, 1) >= sqlc.arg(min_wage))
+  AND (sqlc.arg(max_wage) = 0 OR offer.min_wage * IF(offer.wage_period = "month", 12, 1) <= sqlc.arg(max_wage))
+  AND (sqlc.arg(tags) = "" OR EXISTS (
+    SELECT 1 FROM offer_tag
+    WHERE offer_tag.offer_id = offer.id AND FIND_IN_SET(offer_tag.language_id, sqlc.arg(tags))
+  ))
+GROUP BY company.id, company.name
+ORDER BY count DESC, company.name
+LIMIT 20;
+
+-- name: InsertOfferTag :exec
+INSERT INTO offer_tag (offer_id, language_id)
+VALUES (?, ?);
+
+-- name: DeleteOfferTags :exec
+DELETE FROM offer_tag
+WHERE offer_id = ?;
+
+-- name: SelectOffersTags :many
+SELECT offer_tag.offer_id, language.id, language.name, language.display_name
+FROM offer_tag
+JOIN language ON offer_tag.language_id = language.id
+WHERE offer_tag.offer_id IN (sqlc.slice('offer_ids'))
+ORDER BY language.display_name;
+
+-- name: UpsertOfferDocument :exec
+INSERT INTO offer_document (offer_id, title, body)
+VALUES (?, ?, ?)
+ON DUPLICATE KEY UPDATE title = VALUES(title), body = VALUES(body);
+
+-- name: UpsertCompanyDocument :exec
+INSERT INTO company_document (company_id, body)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE body = VALUES(body);
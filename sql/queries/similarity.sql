@@ -0,0 +1,37 @@
+-- name: SelectPriorSubmissions :many
+SELECT *
+FROM (
+    SELECT
+        s.id,
+        s.src,
+        s.language_id,
+        current_problem.id AS problem_id,
+        current_problem.title,
+        language.display_name,
+        user.id AS user_id,
+        user.name,
+        offer.title AS offer_title,
+        ROW_NUMBER() OVER (
+            PARTITION BY s.participation_id, s.problem_id
+            ORDER BY s.accepted_test_cases DESC, s.created_at ASC
+        ) AS rk
+    FROM quiz_problem current_qp
+    JOIN problem current_problem ON current_qp.problem_id = current_problem.id
+    JOIN problem ON problem.bank_problem_id = current_problem.bank_problem_id
+        OR (problem.title = current_problem.title AND problem.description = current_problem.description)
+    JOIN submission s ON s.problem_id = problem.id
+    JOIN language ON s.language_id = language.id
+    JOIN participation ON s.participation_id = participation.id
+        AND participation.quiz_id <> current_qp.quiz_id
+    JOIN user ON participation.user_id = user.id
+    JOIN quiz ON participation.quiz_id = quiz.id
+    JOIN offer ON quiz.offer_id = offer.id
+    WHERE current_qp.quiz_id = ?
+        AND offer.company_id = (
+            SELECT current_offer.company_id
+            FROM quiz current_quiz
+            JOIN offer current_offer ON current_quiz.offer_id = current_offer.id
+            WHERE current_quiz.id = current_qp.quiz_id
+        )
+) ranked
+WHERE ranked.rk = 1;
@@ -7,7 +7,10 @@ VALUES (?, ?, ?, ?, ?);
 SELECT submission.src
 FROM submission
 JOIN participation ON submission.participation_id = participation.id
-WHERE submission.problem_id = ? and submission.language_id = ? and participation.user_id = ?
+WHERE COALESCE(submission.rejudged_problem_id, submission.problem_id) = ? and submission.language_id = ? and participation.user_id = ?
+  -- problem versions can be shared by several quizzes, only the quiz being
+  -- solved counts
+  AND participation.quiz_id = ?
 ORDER BY submission.created_at DESC
 LIMIT 1;
 
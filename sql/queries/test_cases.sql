@@ -1,5 +1,6 @@
 -- name: GetTestCases :many
-SELECT problem.time_limit, problem.memory_limit, test_case.id, test_case.input, test_case.output
+SELECT problem.time_limit, problem.memory_limit, test_case.id, test_case.input, test_case.output,
+  test_case.input_hash, test_case.output_hash
 FROM problem
 JOIN test_case 
 ON problem.id = test_case.problem_id
@@ -7,8 +8,8 @@ WHERE problem_id = ?;
 
 -- name: InsertTestCase :exec
 INSERT INTO test_case
-(id, problem_id, input, output)
-VALUES (?, ?, ?, ?);
+(id, problem_id, input, output, input_hash, output_hash, input_size, output_size)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
 
 -- name: SelectTestCases :many
 SELECT *
@@ -0,0 +1,81 @@
+-- name: InsertWebhook :exec
+INSERT INTO webhook (id, url, secret, events, company_id)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: DeleteWebhook :exec
+DELETE FROM webhook
+WHERE id = ? AND company_id = ?;
+
+-- name: SelectCompanyWebhooks :many
+SELECT webhook.*
+FROM webhook
+WHERE webhook.company_id = ?
+ORDER BY webhook.created_at;
+
+-- name: InsertWebhookDelivery :exec
+INSERT INTO webhook_delivery (id, event, payload, next_attempt_at, webhook_id)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: InsertSubmissionJudgedEvent :execrows
+INSERT IGNORE INTO submission_judged_event (submission_id)
+VALUES (?);
+
+-- name: SelectDueWebhookDeliveries :many
+SELECT webhook_delivery.*, webhook.url, webhook.secret
+FROM webhook_delivery
+JOIN webhook ON webhook_delivery.webhook_id = webhook.id
+WHERE webhook_delivery.status = "pending" AND webhook_delivery.next_attempt_at <= ?
+ORDER BY webhook_delivery.next_attempt_at
+LIMIT ?;
+
+-- name: UpdateWebhookDeliveryAttempt :exec
+UPDATE webhook_delivery
+SET status = ?, attempts = ?, next_attempt_at = ?, response_code = ?, last_error = ?, delivered_at = ?
+WHERE id = ?;
+
+-- name: SelectCompanyWebhookDeliveries :many
+SELECT webhook_delivery.*, webhook.url
+FROM webhook_delivery
+JOIN webhook ON webhook_delivery.webhook_id = webhook.id
+WHERE webhook.company_id = ?
+ORDER BY webhook_delivery.created_at DESC
+LIMIT ?;
+
+-- name: SelectWebhookDeliveryByCompany :one
+SELECT webhook_delivery.id
+FROM webhook_delivery
+JOIN webhook ON webhook_delivery.webhook_id = webhook.id
+WHERE webhook_delivery.id = ? AND webhook.company_id = ?;
+
+-- name: ResetWebhookDelivery :exec
+UPDATE webhook_delivery
+SET status = "pending", attempts = 0, next_attempt_at = ?
+WHERE id = ?;
+
+-- name: SelectOfferWebhookEvent :one
+SELECT offer.id, offer.title, offer.status, offer.company_id
+FROM offer
+WHERE offer.id = ?;
+
+-- name: SelectParticipationWebhookEvent :one
+SELECT participation.id, participation.created_at, participation.expires_at,
+  participation.finished_at, participation.end_reason,
+  offer.id AS offer_id, offer.title AS offer_title, offer.status AS offer_status, offer.company_id,
+  user.id AS user_id, user.name, user.email,
+  offer_blind_review.reveal_stage, identity_reveal.stage AS revealed_stage
+FROM participation
+JOIN quiz ON participation.quiz_id = quiz.id
+JOIN offer ON quiz.offer_id = offer.id
+JOIN user ON participation.user_id = user.id
+LEFT JOIN offer_blind_review ON offer_blind_review.offer_id = offer.id
+LEFT JOIN identity_reveal ON identity_reveal.participation_id = participation.id
+WHERE participation.id = ?;
+
+-- name: SelectSubmissionWebhookEvent :one
+SELECT submission.id, submission.created_at, submission.accepted_test_cases, submission.participation_id,
+  problem.id AS problem_id, problem.title AS problem_title,
+  (SELECT COUNT(*) FROM test_case_result WHERE test_case_result.submission_id = submission.id) AS total_test_cases,
+  (SELECT COUNT(*) FROM test_case_result WHERE test_case_result.submission_id = submission.id AND test_case_result.status = "") AS pending_test_cases
+FROM submission
+JOIN problem ON submission.problem_id = problem.id
+WHERE submission.id = ?;
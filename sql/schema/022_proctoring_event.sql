@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE proctoring_event (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  kind VARCHAR(32) NOT NULL,
+  detail VARCHAR(255) NOT NULL DEFAULT "",
+  participation_id CHAR(36) NOT NULL,
+  FOREIGN KEY (participation_id) REFERENCES participation(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE proctoring_event;
@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE proctoring_rule (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  kind VARCHAR(32) NOT NULL,
+  threshold INT NOT NULL,
+  quiz_id CHAR(36) NOT NULL,
+  FOREIGN KEY (quiz_id) REFERENCES quiz(id) ON DELETE CASCADE,
+  UNIQUE (quiz_id, kind)
+);
+
+-- +goose Down
+DROP TABLE proctoring_rule;
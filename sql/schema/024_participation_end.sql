@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE participation
+  ADD COLUMN finished_at TIMESTAMP NULL DEFAULT NULL,
+  ADD COLUMN end_reason VARCHAR(16) NOT NULL DEFAULT "";
+
+-- +goose Down
+ALTER TABLE participation
+  DROP COLUMN finished_at,
+  DROP COLUMN end_reason;
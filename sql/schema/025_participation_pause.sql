@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE participation
+  ADD COLUMN paused_at TIMESTAMP NULL DEFAULT NULL;
+
+-- +goose Down
+ALTER TABLE participation
+  DROP COLUMN paused_at;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE participation_adjustment (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  action VARCHAR(16) NOT NULL,
+  minutes INT NOT NULL DEFAULT 0,
+  reason VARCHAR(255) NOT NULL DEFAULT "",
+  participation_id CHAR(36) NOT NULL,
+  FOREIGN KEY (participation_id) REFERENCES participation(id) ON DELETE CASCADE,
+  user_id CHAR(36) NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE participation_adjustment;
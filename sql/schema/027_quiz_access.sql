@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE quiz
+  ADD COLUMN opens_at TIMESTAMP NULL DEFAULT NULL,
+  ADD COLUMN closes_at TIMESTAMP NULL DEFAULT NULL,
+  ADD COLUMN invite_only BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +goose Down
+ALTER TABLE quiz
+  DROP COLUMN opens_at,
+  DROP COLUMN closes_at,
+  DROP COLUMN invite_only;
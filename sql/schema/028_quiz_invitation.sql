@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE quiz_invitation (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  email VARCHAR(254) NOT NULL,
+  code CHAR(8) NOT NULL UNIQUE,
+  used_at TIMESTAMP NULL DEFAULT NULL,
+  user_id CHAR(36),
+  FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE SET NULL,
+  quiz_id CHAR(36) NOT NULL,
+  FOREIGN KEY (quiz_id) REFERENCES quiz(id) ON DELETE CASCADE,
+  UNIQUE (quiz_id, email)
+);
+
+-- +goose Down
+DROP TABLE quiz_invitation;
@@ -0,0 +1,63 @@
+-- +goose Up
+CREATE TABLE bank_problem (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  company_id CHAR(36) NOT NULL,
+  FOREIGN KEY (company_id) REFERENCES company(id) ON DELETE CASCADE
+);
+
+CREATE TABLE quiz_problem (
+  quiz_id CHAR(36) NOT NULL,
+  FOREIGN KEY (quiz_id) REFERENCES quiz(id) ON DELETE CASCADE,
+  problem_id CHAR(36) NOT NULL,
+  FOREIGN KEY (problem_id) REFERENCES problem(id) ON DELETE CASCADE,
+  position INT NOT NULL DEFAULT 0,
+  PRIMARY KEY (quiz_id, problem_id)
+);
+
+-- Every existing problem becomes the first version of its own bank entry
+INSERT INTO bank_problem (id, created_at, company_id)
+SELECT problem.id, problem.created_at, offer.company_id
+FROM problem
+JOIN quiz ON problem.quiz_id = quiz.id
+JOIN offer ON quiz.offer_id = offer.id;
+
+INSERT INTO quiz_problem (quiz_id, problem_id, position)
+SELECT problem.quiz_id, problem.id,
+  ROW_NUMBER() OVER (PARTITION BY problem.quiz_id ORDER BY problem.created_at, problem.id) - 1
+FROM problem;
+
+ALTER TABLE problem
+  ADD COLUMN bank_problem_id CHAR(36) NULL,
+  ADD COLUMN version INT NOT NULL DEFAULT 1;
+
+UPDATE problem SET bank_problem_id = problem.id;
+
+ALTER TABLE problem
+  MODIFY bank_problem_id CHAR(36) NOT NULL,
+  ADD FOREIGN KEY (bank_problem_id) REFERENCES bank_problem(id) ON DELETE CASCADE,
+  ADD UNIQUE (bank_problem_id, version),
+  DROP FOREIGN KEY problem_ibfk_1,
+  DROP COLUMN quiz_id;
+
+-- +goose Down
+ALTER TABLE problem ADD COLUMN quiz_id CHAR(36) NULL;
+
+UPDATE problem
+JOIN quiz_problem ON quiz_problem.problem_id = problem.id
+SET problem.quiz_id = quiz_problem.quiz_id;
+
+DELETE FROM problem WHERE problem.quiz_id IS NULL;
+
+ALTER TABLE problem DROP FOREIGN KEY problem_ibfk_2;
+
+ALTER TABLE problem
+  MODIFY quiz_id CHAR(36) NOT NULL,
+  ADD FOREIGN KEY (quiz_id) REFERENCES quiz(id) ON DELETE CASCADE,
+  DROP INDEX bank_problem_id,
+  DROP COLUMN bank_problem_id,
+  DROP COLUMN version;
+
+DROP TABLE quiz_problem;
+DROP TABLE bank_problem;
@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE problem_checker (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  path VARCHAR(255) NOT NULL,
+  src MEDIUMTEXT NOT NULL,
+  problem_id CHAR(36) NOT NULL,
+  FOREIGN KEY (problem_id) REFERENCES problem(id) ON DELETE CASCADE,
+  UNIQUE (problem_id, path)
+);
+
+-- +goose Down
+DROP TABLE problem_checker;
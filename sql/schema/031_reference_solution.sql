@@ -0,0 +1,27 @@
+-- +goose Up
+CREATE TABLE reference_solution (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  src MEDIUMTEXT NOT NULL,
+  expected VARCHAR(16) NOT NULL,
+  language_id INTEGER NOT NULL,
+  FOREIGN KEY (language_id) REFERENCES language(id),
+  problem_id CHAR(36) NOT NULL,
+  FOREIGN KEY (problem_id) REFERENCES problem(id) ON DELETE CASCADE
+);
+
+CREATE TABLE reference_result (
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  status VARCHAR(64) NOT NULL,
+  time INT NOT NULL DEFAULT 0,
+  memory INTEGER NOT NULL DEFAULT 0,
+  reference_solution_id CHAR(36) NOT NULL,
+  FOREIGN KEY (reference_solution_id) REFERENCES reference_solution(id) ON DELETE CASCADE,
+  test_case_id CHAR(36) NOT NULL,
+  FOREIGN KEY (test_case_id) REFERENCES test_case(id) ON DELETE CASCADE,
+  PRIMARY KEY (reference_solution_id, test_case_id)
+);
+
+-- +goose Down
+DROP TABLE reference_result;
+DROP TABLE reference_solution;
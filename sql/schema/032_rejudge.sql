@@ -0,0 +1,25 @@
+-- +goose Up
+CREATE TABLE rejudge (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  scope VARCHAR(16) NOT NULL,
+  target_id CHAR(36) NOT NULL,
+  quiz_id CHAR(36) NOT NULL,
+  FOREIGN KEY (quiz_id) REFERENCES quiz(id) ON DELETE CASCADE,
+  user_id CHAR(36) NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+CREATE TABLE rejudge_submission (
+  accepted_before INT NOT NULL,
+  error VARCHAR(255) NOT NULL DEFAULT "",
+  rejudge_id CHAR(36) NOT NULL,
+  FOREIGN KEY (rejudge_id) REFERENCES rejudge(id) ON DELETE CASCADE,
+  submission_id CHAR(36) NOT NULL,
+  FOREIGN KEY (submission_id) REFERENCES submission(id) ON DELETE CASCADE,
+  PRIMARY KEY (rejudge_id, submission_id)
+);
+
+-- +goose Down
+DROP TABLE rejudge_submission;
+DROP TABLE rejudge;
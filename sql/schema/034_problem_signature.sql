@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE problem ADD COLUMN signature VARCHAR(255) NOT NULL DEFAULT "";
+
+-- +goose Down
+ALTER TABLE problem DROP COLUMN signature;
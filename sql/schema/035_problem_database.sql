@@ -0,0 +1,13 @@
+-- +goose Up
+CREATE TABLE problem_database (
+  problem_id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  schema_sql MEDIUMTEXT NOT NULL,
+  seed_sql MEDIUMTEXT NOT NULL,
+  ordered BOOLEAN NOT NULL DEFAULT FALSE,
+  FOREIGN KEY (problem_id) REFERENCES problem(id) ON DELETE CASCADE
+);
+INSERT IGNORE INTO language (id, name, display_name) VALUES (82, "sql", "SQL (SQLite 3.27.2)");
+
+-- +goose Down
+DROP TABLE problem_database;
@@ -0,0 +1,49 @@
+-- +goose Up
+CREATE TABLE question (
+  problem_id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  kind VARCHAR(16) NOT NULL,
+  points INT NOT NULL,
+  rubric VARCHAR(2000) NOT NULL DEFAULT "",
+  tolerance DOUBLE NOT NULL DEFAULT 0,
+  FOREIGN KEY (problem_id) REFERENCES problem(id) ON DELETE CASCADE
+);
+
+CREATE TABLE question_option (
+  id CHAR(36) PRIMARY KEY,
+  problem_id CHAR(36) NOT NULL,
+  position INT NOT NULL,
+  label VARCHAR(500) NOT NULL,
+  correct BOOLEAN NOT NULL,
+  FOREIGN KEY (problem_id) REFERENCES problem(id) ON DELETE CASCADE,
+  UNIQUE (problem_id, position)
+);
+
+CREATE TABLE question_answer (
+  id CHAR(36) PRIMARY KEY,
+  problem_id CHAR(36) NOT NULL,
+  position INT NOT NULL,
+  value VARCHAR(255) NOT NULL,
+  FOREIGN KEY (problem_id) REFERENCES problem(id) ON DELETE CASCADE,
+  UNIQUE (problem_id, position)
+);
+
+CREATE TABLE question_response (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  participation_id CHAR(36) NOT NULL,
+  problem_id CHAR(36) NOT NULL,
+  answer TEXT NOT NULL,
+  score INT NULL,
+  feedback VARCHAR(1000) NOT NULL DEFAULT "",
+  FOREIGN KEY (participation_id) REFERENCES participation(id) ON DELETE CASCADE,
+  FOREIGN KEY (problem_id) REFERENCES problem(id) ON DELETE CASCADE,
+  UNIQUE (participation_id, problem_id)
+);
+
+-- +goose Down
+DROP TABLE question_response;
+DROP TABLE question_answer;
+DROP TABLE question_option;
+DROP TABLE question;
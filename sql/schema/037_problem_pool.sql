@@ -0,0 +1,38 @@
+-- +goose Up
+ALTER TABLE problem
+  ADD COLUMN difficulty INT NOT NULL DEFAULT 2,
+  ADD COLUMN tag VARCHAR(32) NOT NULL DEFAULT "";
+
+ALTER TABLE quiz
+  ADD COLUMN shuffle BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE quiz_pool (
+  id CHAR(36) PRIMARY KEY,
+  quiz_id CHAR(36) NOT NULL,
+  position INT NOT NULL,
+  tag VARCHAR(32) NOT NULL DEFAULT "",
+  difficulty INT NOT NULL DEFAULT 0,
+  draw INT NOT NULL,
+  FOREIGN KEY (quiz_id) REFERENCES quiz(id) ON DELETE CASCADE,
+  UNIQUE (quiz_id, position)
+);
+
+CREATE TABLE participation_problem (
+  participation_id CHAR(36) NOT NULL,
+  problem_id CHAR(36) NOT NULL,
+  position INT NOT NULL,
+  PRIMARY KEY (participation_id, problem_id),
+  FOREIGN KEY (participation_id) REFERENCES participation(id) ON DELETE CASCADE,
+  FOREIGN KEY (problem_id) REFERENCES problem(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE participation_problem;
+DROP TABLE quiz_pool;
+
+ALTER TABLE quiz
+  DROP COLUMN shuffle;
+
+ALTER TABLE problem
+  DROP COLUMN difficulty,
+  DROP COLUMN tag;
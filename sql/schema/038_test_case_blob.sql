@@ -0,0 +1,17 @@
+-- +goose Up
+-- test data too big for the table lives in the blob store, the row keeps a
+-- preview and the hash of the content
+ALTER TABLE test_case
+  ADD COLUMN input_hash CHAR(64) NOT NULL DEFAULT "",
+  ADD COLUMN output_hash CHAR(64) NOT NULL DEFAULT "",
+  ADD COLUMN input_size INT NOT NULL DEFAULT 0,
+  ADD COLUMN output_size INT NOT NULL DEFAULT 0;
+
+UPDATE test_case SET input_size = LENGTH(input), output_size = LENGTH(output);
+
+-- +goose Down
+ALTER TABLE test_case
+  DROP COLUMN output_size,
+  DROP COLUMN input_size,
+  DROP COLUMN output_hash,
+  DROP COLUMN input_hash;
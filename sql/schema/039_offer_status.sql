@@ -0,0 +1,11 @@
+-- +goose Up
+-- the status becomes a named state of the offer lifecycle, archived offers
+-- were -1 and every other offer was listed
+ALTER TABLE offer MODIFY status VARCHAR(16) NOT NULL DEFAULT "published";
+
+UPDATE offer SET status = IF(status = "-1", "archived", "published");
+
+-- +goose Down
+UPDATE offer SET status = IF(status = "archived", "-1", "1");
+
+ALTER TABLE offer MODIFY status INT NOT NULL DEFAULT 1;
@@ -0,0 +1,34 @@
+-- +goose Up
+-- structured attributes used by the search facets, existing wages were
+-- annual ranges without currency
+ALTER TABLE offer
+  ADD COLUMN location VARCHAR(100) NOT NULL DEFAULT "",
+  ADD COLUMN modality VARCHAR(16) NOT NULL DEFAULT "onsite",
+  ADD COLUMN seniority VARCHAR(16) NOT NULL DEFAULT "",
+  ADD COLUMN contract VARCHAR(16) NOT NULL DEFAULT "",
+  ADD COLUMN currency CHAR(3) NOT NULL DEFAULT "USD",
+  ADD COLUMN wage_period VARCHAR(16) NOT NULL DEFAULT "year";
+
+CREATE INDEX offer_search ON offer (status, modality, created_at);
+
+-- the tech stack of an offer, tags are the judge languages
+CREATE TABLE offer_tag (
+  offer_id CHAR(36) NOT NULL,
+  FOREIGN KEY (offer_id) REFERENCES offer(id) ON DELETE CASCADE,
+  language_id INTEGER NOT NULL,
+  FOREIGN KEY (language_id) REFERENCES language(id) ON DELETE CASCADE,
+  PRIMARY KEY (offer_id, language_id)
+);
+
+-- +goose Down
+DROP TABLE offer_tag;
+
+DROP INDEX offer_search ON offer;
+
+ALTER TABLE offer
+  DROP COLUMN wage_period,
+  DROP COLUMN currency,
+  DROP COLUMN contract,
+  DROP COLUMN seniority,
+  DROP COLUMN modality,
+  DROP COLUMN location;
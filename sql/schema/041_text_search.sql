@@ -0,0 +1,41 @@
+-- +goose Up
+-- folded text indexed for relevance search, the server keeps it in sync
+-- when offers and companies are saved
+CREATE TABLE offer_document (
+  offer_id CHAR(36) PRIMARY KEY,
+  FOREIGN KEY (offer_id) REFERENCES offer(id) ON DELETE CASCADE,
+  title VARCHAR(255) NOT NULL,
+  -- title, about and requirements, the title is repeated so every term can
+  -- be required against a single index
+  body TEXT NOT NULL,
+  FULLTEXT INDEX offer_document_title (title),
+  FULLTEXT INDEX offer_document_body (body)
+);
+
+CREATE TABLE company_document (
+  company_id CHAR(36) PRIMARY KEY,
+  FOREIGN KEY (company_id) REFERENCES company(id) ON DELETE CASCADE,
+  body TEXT NOT NULL,
+  FULLTEXT INDEX company_document_body (body)
+);
+
+-- existing rows only get their accents folded, stopwords are dropped the
+-- next time they are saved
+INSERT INTO offer_document (offer_id, title, body)
+SELECT id,
+  REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(LOWER(title),
+    "á", "a"), "é", "e"), "í", "i"), "ó", "o"), "ú", "u"), "ü", "u"), "ñ", "n"),
+  REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(LOWER(CONCAT_WS(" ", title, about, requirements)),
+    "á", "a"), "é", "e"), "í", "i"), "ó", "o"), "ú", "u"), "ü", "u"), "ñ", "n")
+FROM offer;
+
+INSERT INTO company_document (company_id, body)
+SELECT id,
+  REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(LOWER(CONCAT_WS(" ", name, description)),
+    "á", "a"), "é", "e"), "í", "i"), "ó", "o"), "ú", "u"), "ü", "u"), "ñ", "n")
+FROM company;
+
+-- +goose Down
+DROP TABLE company_document;
+
+DROP TABLE offer_document;
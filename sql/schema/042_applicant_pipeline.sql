@@ -0,0 +1,58 @@
+-- +goose Up
+-- companies without rows use the default pipeline
+CREATE TABLE company_stage (
+  company_id CHAR(36) NOT NULL,
+  FOREIGN KEY (company_id) REFERENCES company(id) ON DELETE CASCADE,
+  name VARCHAR(64) NOT NULL,
+  label VARCHAR(64) NOT NULL,
+  position INT NOT NULL,
+  PRIMARY KEY (company_id, name)
+);
+
+-- participations without a row are in the first stage and unrated
+CREATE TABLE participation_review (
+  participation_id CHAR(36) PRIMARY KEY,
+  FOREIGN KEY (participation_id) REFERENCES participation(id) ON DELETE CASCADE,
+  updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  stage VARCHAR(64) NOT NULL DEFAULT "",
+  rating INT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE participation_stage_history (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  from_stage VARCHAR(64) NOT NULL,
+  to_stage VARCHAR(64) NOT NULL,
+  participation_id CHAR(36) NOT NULL,
+  FOREIGN KEY (participation_id) REFERENCES participation(id) ON DELETE CASCADE,
+  user_id CHAR(36) NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+CREATE TABLE participation_note (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  body TEXT NOT NULL,
+  participation_id CHAR(36) NOT NULL,
+  FOREIGN KEY (participation_id) REFERENCES participation(id) ON DELETE CASCADE,
+  user_id CHAR(36) NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+CREATE TABLE participation_tag (
+  participation_id CHAR(36) NOT NULL,
+  FOREIGN KEY (participation_id) REFERENCES participation(id) ON DELETE CASCADE,
+  tag VARCHAR(32) NOT NULL,
+  PRIMARY KEY (participation_id, tag)
+);
+
+-- +goose Down
+DROP TABLE participation_tag;
+
+DROP TABLE participation_note;
+
+DROP TABLE participation_stage_history;
+
+DROP TABLE participation_review;
+
+DROP TABLE company_stage;
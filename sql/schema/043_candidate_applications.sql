@@ -0,0 +1,14 @@
+-- +goose Up
+-- whether candidates see the stage they are in, companies opt in
+CREATE TABLE company_pipeline (
+  company_id CHAR(36) PRIMARY KEY,
+  FOREIGN KEY (company_id) REFERENCES company(id) ON DELETE CASCADE,
+  share_stage BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+ALTER TABLE participation_review ADD COLUMN withdrawn_at TIMESTAMP NULL;
+
+-- +goose Down
+ALTER TABLE participation_review DROP COLUMN withdrawn_at;
+
+DROP TABLE company_pipeline;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- offers without a row show who the applicants are
+CREATE TABLE offer_blind_review (
+  offer_id CHAR(36) PRIMARY KEY,
+  FOREIGN KEY (offer_id) REFERENCES offer(id) ON DELETE CASCADE,
+  reveal_stage VARCHAR(64) NOT NULL
+);
+
+-- an applicant is revealed once, the row stays if blind review is enabled
+-- again
+CREATE TABLE identity_reveal (
+  participation_id CHAR(36) PRIMARY KEY,
+  FOREIGN KEY (participation_id) REFERENCES participation(id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  stage VARCHAR(64) NOT NULL,
+  reason VARCHAR(16) NOT NULL,
+  user_id CHAR(36) NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE identity_reveal;
+
+DROP TABLE offer_blind_review;
@@ -0,0 +1,35 @@
+-- +goose Up
+-- events holds the subscribed event names separated by commas
+CREATE TABLE webhook (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  url VARCHAR(2048) NOT NULL,
+  secret CHAR(64) NOT NULL,
+  events VARCHAR(255) NOT NULL,
+  company_id CHAR(36) NOT NULL,
+  FOREIGN KEY (company_id) REFERENCES company(id) ON DELETE CASCADE
+);
+
+-- the outbox, a row is written with the event and sent by the dispatcher
+-- until it is delivered or runs out of attempts
+CREATE TABLE webhook_delivery (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  event VARCHAR(64) NOT NULL,
+  payload TEXT NOT NULL,
+  status VARCHAR(16) NOT NULL DEFAULT "pending",
+  attempts INT NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  response_code INT NOT NULL DEFAULT 0,
+  last_error VARCHAR(255) NOT NULL DEFAULT "",
+  delivered_at TIMESTAMP NULL,
+  webhook_id CHAR(36) NOT NULL,
+  FOREIGN KEY (webhook_id) REFERENCES webhook(id) ON DELETE CASCADE
+);
+
+CREATE INDEX webhook_delivery_due ON webhook_delivery (status, next_attempt_at);
+
+-- +goose Down
+DROP TABLE webhook_delivery;
+
+DROP TABLE webhook;
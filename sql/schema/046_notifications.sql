@@ -0,0 +1,34 @@
+-- +goose Up
+-- a row is written for every recipient, in_app rows are listed in the
+-- notification center and digest_pending rows wait for the daily email
+CREATE TABLE notification (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  category VARCHAR(32) NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  link VARCHAR(255) NOT NULL,
+  in_app BOOLEAN NOT NULL,
+  digest_pending BOOLEAN NOT NULL,
+  read_at TIMESTAMP NULL,
+  user_id CHAR(36) NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE
+);
+
+CREATE INDEX notification_user ON notification (user_id, in_app, created_at);
+
+CREATE INDEX notification_digest ON notification (digest_pending, user_id);
+
+-- users without a row for a category get it in the app and no email
+CREATE TABLE notification_preference (
+  user_id CHAR(36) NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE,
+  category VARCHAR(32) NOT NULL,
+  in_app BOOLEAN NOT NULL,
+  email VARCHAR(16) NOT NULL,
+  PRIMARY KEY (user_id, category)
+);
+
+-- +goose Down
+DROP TABLE notification_preference;
+
+DROP TABLE notification;
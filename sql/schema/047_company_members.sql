@@ -0,0 +1,40 @@
+-- +goose Up
+-- company.user_id stays as the owner, every member has a row here with its
+-- role, the owner included
+CREATE TABLE company_member (
+  company_id CHAR(36) NOT NULL,
+  FOREIGN KEY (company_id) REFERENCES company(id) ON DELETE CASCADE,
+  user_id CHAR(36) NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES user(id) ON DELETE CASCADE,
+  role VARCHAR(16) NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (company_id, user_id)
+);
+
+CREATE INDEX company_member_user ON company_member (user_id);
+
+INSERT INTO company_member (company_id, user_id, role, created_at)
+SELECT company.id, company.user_id, "owner", company.created_at
+FROM company;
+
+-- invitee is the nick or the email the invitation was sent to, it is
+-- answered by the user that has either of them
+CREATE TABLE company_invitation (
+  id CHAR(36) PRIMARY KEY,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  invitee VARCHAR(255) NOT NULL,
+  role VARCHAR(16) NOT NULL,
+  declined_at TIMESTAMP NULL,
+  invited_by CHAR(36) NOT NULL,
+  FOREIGN KEY (invited_by) REFERENCES user(id) ON DELETE CASCADE,
+  company_id CHAR(36) NOT NULL,
+  FOREIGN KEY (company_id) REFERENCES company(id) ON DELETE CASCADE,
+  UNIQUE (company_id, invitee)
+);
+
+CREATE INDEX company_invitation_invitee ON company_invitation (invitee);
+
+-- +goose Down
+DROP TABLE company_invitation;
+
+DROP TABLE company_member;
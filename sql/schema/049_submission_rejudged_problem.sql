@@ -0,0 +1,11 @@
+-- +goose Up
+-- a rejudge on the latest version of a library problem keeps the version
+-- the candidate solved in problem_id and judges against this one
+ALTER TABLE submission
+  ADD COLUMN rejudged_problem_id CHAR(36) NULL,
+  ADD FOREIGN KEY (rejudged_problem_id) REFERENCES problem(id) ON DELETE SET NULL;
+
+-- +goose Down
+ALTER TABLE submission
+  DROP FOREIGN KEY submission_ibfk_4,
+  DROP COLUMN rejudged_problem_id;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- every test case calls back on its own, the row is written once by the
+-- callback that queues submission.judged so concurrent ones don't repeat it
+CREATE TABLE submission_judged_event (
+  submission_id CHAR(36) PRIMARY KEY,
+  FOREIGN KEY (submission_id) REFERENCES submission(id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE submission_judged_event;